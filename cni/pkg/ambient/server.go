@@ -71,9 +71,20 @@ func NewServer(ctx context.Context, args AmbientArgs) (*Server, error) {
 		return s.detectIptablesCommand(), nil
 	})
 
-	switch args.RedirectMode {
-	case IptablesMode:
-		s.redirectMode = IptablesMode
+	s.redirectMode = args.RedirectMode
+	if s.redirectMode == EbpfMode {
+		ebpfServer, err := newEbpfServer(args.LogLevel)
+		if err != nil {
+			// eBPF support varies between kernels; rather than leaving the node without capture, fall back to iptables.
+			log.Warnf("ebpf redirection is not supported on this node, falling back to iptables: %v", err)
+			s.redirectMode = IptablesMode
+		} else {
+			s.ebpfServer = ebpfServer
+			s.ebpfServer.Start(ctx.Done())
+		}
+	}
+
+	if s.redirectMode == IptablesMode {
 		// We need to find our Host IP -- is there a better way to do this?
 		h, err := GetHostIP(s.kubeClient.Kube())
 		if err != nil || h == "" {
@@ -81,12 +92,8 @@ func NewServer(ctx context.Context, args AmbientArgs) (*Server, error) {
 		}
 		HostIP = h
 		log.Infof("HostIP=%v", HostIP)
-	case EbpfMode:
-		s.redirectMode = EbpfMode
-		s.ebpfServer = ebpf.NewRedirectServer()
-		s.ebpfServer.SetLogLevel(args.LogLevel)
-		s.ebpfServer.Start(ctx.Done())
 	}
+	log.Infof("ambient redirection mode: %v", s.redirectMode)
 
 	s.setupHandlers()
 
@@ -95,6 +102,19 @@ func NewServer(ctx context.Context, args AmbientArgs) (*Server, error) {
 	return s, nil
 }
 
+// newEbpfServer verifies the node supports eBPF redirection and loads the redirection programs.
+func newEbpfServer(logLevel string) (*ebpf.RedirectServer, error) {
+	if err := ebpf.CheckCapabilities(); err != nil {
+		return nil, err
+	}
+	r, err := ebpf.NewRedirectServer()
+	if err != nil {
+		return nil, err
+	}
+	r.SetLogLevel(logLevel)
+	return r, nil
+}
+
 func (s *Server) isZTunnelRunning() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -192,6 +192,11 @@ func init() {
 		"A set of label selectors in label=value format that will be added to the pod list filters")
 	registerStringParameter(constants.RepairFieldSelectors, "",
 		"A set of field selectors in label=value format that will be added to the pod list filters")
+	registerStringParameter(constants.RepairAction, "",
+		"The action taken on broken pods: delete-pod, label-pod, taint-node or alert. If unset, the pods are deleted or labeled "+
+			"according to repair-delete-pods and repair-label-pods. Namespaces may override it with the cni.istio.io/repair-action annotation")
+	registerStringParameter(constants.RepairNodeTaintKey, "cni.istio.io/broken-pod",
+		"The key of the NoSchedule taint set on the nodes of the broken pods by the taint-node action")
 }
 
 func registerStringParameter(name, value, usage string) {
@@ -284,6 +289,8 @@ func constructConfig() (*config.Config, error) {
 		InitExitCode:       viper.GetInt(constants.RepairInitExitCode),
 		LabelSelectors:     viper.GetString(constants.RepairLabelSelectors),
 		FieldSelectors:     viper.GetString(constants.RepairFieldSelectors),
+		Action:             viper.GetString(constants.RepairAction),
+		NodeTaintKey:       viper.GetString(constants.RepairNodeTaintKey),
 	}
 
 	return &config.Config{InstallConfig: installCfg, RepairConfig: repairCfg}, nil
@@ -112,6 +112,14 @@ type RepairConfig struct {
 	// Whether to label broken pods
 	LabelPods bool
 
+	// The action taken on broken pods: delete-pod, label-pod, taint-node or alert. If empty, the
+	// pods are deleted or labeled according to DeletePods and LabelPods. The namespaces of the
+	// pods may override it with the cni.istio.io/repair-action annotation.
+	Action string
+
+	// Key of the NoSchedule taint set on the nodes of broken pods by the taint-node action
+	NodeTaintKey string
+
 	// Filters for race repair, including name of sidecar annotation, name of init container,
 	// init container termination message and exit code.
 	SidecarAnnotation  string
@@ -165,6 +173,8 @@ func (c RepairConfig) String() string {
 	b.WriteString("LabelValue: " + c.LabelValue + "\n")
 	b.WriteString("DeletePods: " + fmt.Sprint(c.DeletePods) + "\n")
 	b.WriteString("LabelPods: " + fmt.Sprint(c.LabelPods) + "\n")
+	b.WriteString("Action: " + c.Action + "\n")
+	b.WriteString("NodeTaintKey: " + c.NodeTaintKey + "\n")
 	b.WriteString("SidecarAnnotation: " + c.SidecarAnnotation + "\n")
 	b.WriteString("InitContainerName: " + c.InitContainerName + "\n")
 	b.WriteString("InitTerminationMsg: " + c.InitTerminationMsg + "\n")
@@ -51,6 +51,8 @@ const (
 	RepairInitExitCode       = "repair-init-container-exit-code"
 	RepairLabelSelectors     = "repair-label-selectors"
 	RepairFieldSelectors     = "repair-field-selectors"
+	RepairAction             = "repair-action"
+	RepairNodeTaintKey       = "repair-node-taint-key"
 )
 
 // Internal constants
@@ -0,0 +1,55 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/features"
+)
+
+// requiredMapTypes are the map types used by the ambient redirection programs.
+var requiredMapTypes = []ebpf.MapType{ebpf.Array, ebpf.Hash}
+
+// requiredHelpers are the helpers called by the ambient redirection programs.
+var requiredHelpers = []asm.BuiltinFunc{asm.FnRedirect, asm.FnSkcLookupTcp, asm.FnSkRelease}
+
+// CheckCapabilities verifies the node is able to run the ambient redirection programs, returning an error
+// describing the first missing capability. This allows callers to fall back to another redirection mode
+// rather than failing when the kernel lacks support.
+func CheckCapabilities() error {
+	if err := checkOrMountBPFFSDefault(); err != nil {
+		return fmt.Errorf("BPF filesystem is not available: %v", err)
+	}
+	if err := setLimit(); err != nil {
+		return fmt.Errorf("unable to remove memlock limit: %v", err)
+	}
+	if err := features.HaveProgramType(ebpf.SchedCLS); err != nil {
+		return fmt.Errorf("program type %v is not supported: %v", ebpf.SchedCLS, err)
+	}
+	for _, mt := range requiredMapTypes {
+		if err := features.HaveMapType(mt); err != nil {
+			return fmt.Errorf("map type %v is not supported: %v", mt, err)
+		}
+	}
+	for _, h := range requiredHelpers {
+		if err := features.HaveProgramHelper(ebpf.SchedCLS, h); err != nil {
+			return fmt.Errorf("helper %v is not supported: %v", h, err)
+		}
+	}
+	return nil
+}
@@ -219,13 +219,13 @@ type mapInfo struct {
 	Pad     uint8
 }
 
-func NewRedirectServer() *RedirectServer {
+func NewRedirectServer() (*RedirectServer, error) {
 	if err := checkOrMountBPFFSDefault(); err != nil {
-		log.Fatalf("BPF filesystem mounting on /sys/fs/bpf failed: %v", err)
+		return nil, fmt.Errorf("BPF filesystem mounting on /sys/fs/bpf failed: %v", err)
 	}
 
 	if err := setLimit(); err != nil {
-		log.Fatalf("Setting limit failed: %v", err)
+		return nil, fmt.Errorf("setting limit failed: %v", err)
 	}
 
 	r := &RedirectServer{
@@ -233,10 +233,10 @@ func NewRedirectServer() *RedirectServer {
 	}
 
 	if err := r.initBpfObjects(); err != nil {
-		log.Fatalf("Init bpf objects failed: %v", err)
+		return nil, fmt.Errorf("init bpf objects failed: %v", err)
 	}
 
-	return r
+	return r, nil
 }
 
 func checkOrMountBPFFSDefault() error {
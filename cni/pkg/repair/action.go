@@ -0,0 +1,197 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repair
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kretry "k8s.io/client-go/util/retry"
+)
+
+// The actions taken on broken pods.
+const (
+	ActionDeletePod = "delete-pod"
+	ActionLabelPod  = "label-pod"
+	ActionTaintNode = "taint-node"
+	ActionAlert     = "alert"
+)
+
+// ActionAnnotation overrides the action taken on the broken pods of a namespace, so the pods of the
+// namespaces whose pods may not be deleted are only labeled or reported.
+const ActionAnnotation = "cni.istio.io/repair-action"
+
+// The reasons of the events recorded on the broken pods.
+const (
+	eventPodDeleted  = "BrokenPodDeleted"
+	eventPodLabeled  = "BrokenPodLabeled"
+	eventNodeTainted = "BrokenPodNodeTainted"
+	eventPodDetected = "BrokenPodDetected"
+	eventSkipped     = "BrokenPodRepairSkipped"
+	eventFailed      = "BrokenPodRepairFailed"
+)
+
+// ValidateAction returns an error if the action is not one of the actions taken on broken pods.
+func ValidateAction(action string) error {
+	switch action {
+	case ActionDeletePod, ActionLabelPod, ActionTaintNode, ActionAlert:
+		return nil
+	}
+	return fmt.Errorf("invalid repair action %q, must be %s, %s, %s or %s",
+		action, ActionDeletePod, ActionLabelPod, ActionTaintNode, ActionAlert)
+}
+
+// action returns the action taken on the broken pod: the action of the annotation of its namespace if
+// any, else the configured action. It is empty if no action is configured.
+func (bpr brokenPodReconciler) action(pod corev1.Pod) string {
+	ns, err := bpr.client.CoreV1().Namespaces().Get(context.TODO(), pod.Namespace, metav1.GetOptions{})
+	if err != nil {
+		repairLog.Warnf("Failed to get namespace %s, using the default repair action: %v", pod.Namespace, err)
+	} else if action, f := ns.Annotations[ActionAnnotation]; f {
+		err := ValidateAction(action)
+		if err == nil && action == ActionTaintNode && bpr.defaultAction() != ActionTaintNode {
+			// Tainting the nodes affects the pods of every namespace, and requires permissions on the nodes which
+			// are only granted when the configured action is taint-node.
+			err = fmt.Errorf("the %s action may only be configured for all namespaces", ActionTaintNode)
+		}
+		if err == nil {
+			return action
+		}
+		repairLog.Warnf("Ignoring the %s annotation of namespace %s: %v", ActionAnnotation, pod.Namespace, err)
+	}
+	return bpr.defaultAction()
+}
+
+// defaultAction returns the configured action, derived from DeletePods and LabelPods if Action is unset.
+func (bpr brokenPodReconciler) defaultAction() string {
+	switch {
+	case bpr.cfg.Action != "":
+		return bpr.cfg.Action
+	case bpr.cfg.DeletePods:
+		return ActionDeletePod
+	case bpr.cfg.LabelPods:
+		return ActionLabelPod
+	}
+	return ""
+}
+
+// taintBrokenPodNode taints the node of the broken pod, so no more pods are scheduled on the node whose
+// CNI plugin is not ready. The pod itself is left untouched; the taint is removed once no broken pod is
+// left on the node.
+func (bpr brokenPodReconciler) taintBrokenPodNode(pod corev1.Pod) error {
+	m := podsRepaired.With(typeLabel.Value(taintType))
+	if !bpr.detectPod(pod) {
+		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonNotBroken)).Increment()
+		return nil
+	}
+	alreadyTainted := false
+	err := kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		node, err := bpr.client.CoreV1().Nodes().Get(context.TODO(), pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if hasTaint(node, bpr.cfg.NodeTaintKey) {
+			alreadyTainted = true
+			return nil
+		}
+		repairLog.Infof("Pod detected as broken, tainting node %s of pod %s/%s with %s", node.Name, pod.Namespace, pod.Name, bpr.cfg.NodeTaintKey)
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+			Key:    bpr.cfg.NodeTaintKey,
+			Value:  "true",
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+		_, err = bpr.client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		m.With(resultLabel.Value(resultFail)).Increment()
+		bpr.recordEvent(pod, corev1.EventTypeWarning, eventFailed, "Failed to taint node %s: %v", pod.Spec.NodeName, err)
+		return err
+	}
+	if bpr.nodeTainted != nil {
+		bpr.nodeTainted.Store(true)
+	}
+	if alreadyTainted {
+		repairLog.Infof("Node %s of pod %s/%s already has taint %s, skipping", pod.Spec.NodeName, pod.Namespace, pod.Name, bpr.cfg.NodeTaintKey)
+		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonAlreadyTainted)).Increment()
+		bpr.recordEvent(pod, corev1.EventTypeWarning, eventSkipped, "Node %s already has taint %s", pod.Spec.NodeName, bpr.cfg.NodeTaintKey)
+		return nil
+	}
+	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(pod, corev1.EventTypeWarning, eventNodeTainted, "Tainted node %s with %s:%s",
+		pod.Spec.NodeName, bpr.cfg.NodeTaintKey, corev1.TaintEffectNoSchedule)
+	return nil
+}
+
+// untaintNode removes the taint of the broken pods from the node, once no broken pod is left on it.
+func (bpr brokenPodReconciler) untaintNode(name string) error {
+	return kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		node, err := bpr.client.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !hasTaint(node, bpr.cfg.NodeTaintKey) {
+			return nil
+		}
+		repairLog.Infof("No broken pod left, removing taint %s from node %s", bpr.cfg.NodeTaintKey, name)
+		taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+		for _, taint := range node.Spec.Taints {
+			if taint.Key != bpr.cfg.NodeTaintKey {
+				taints = append(taints, taint)
+			}
+		}
+		node.Spec.Taints = taints
+		_, err = bpr.client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// hasTaint returns true if the node has a taint of the key.
+func hasTaint(node *corev1.Node, key string) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// alertBrokenPod only reports the broken pod, with a metric and an event, for the clusters where the
+// pods may not be modified.
+func (bpr brokenPodReconciler) alertBrokenPod(pod corev1.Pod) error {
+	m := podsRepaired.With(typeLabel.Value(alertType))
+	if !bpr.detectPod(pod) {
+		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonNotBroken)).Increment()
+		return nil
+	}
+	repairLog.Warnf("Pod detected as broken: %s/%s", pod.Namespace, pod.Name)
+	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(pod, corev1.EventTypeWarning, eventPodDetected,
+		"Pod detected as broken by the Istio CNI race repair, the init container %s failed", bpr.cfg.InitContainerName)
+	return nil
+}
+
+// recordEvent records an event on the pod, if the reconciler has a recorder.
+func (bpr brokenPodReconciler) recordEvent(pod corev1.Pod, eventType, reason, messageFmt string, args ...any) {
+	if bpr.recorder == nil {
+		return
+	}
+	bpr.recorder.Eventf(&pod, eventType, reason, messageFmt, args...)
+}
@@ -22,6 +22,8 @@ var (
 	typeLabel  = monitoring.MustCreateLabel("type")
 	deleteType = "delete"
 	labelType  = "label"
+	taintType  = "taint"
+	alertType  = "alert"
 
 	resultLabel   = monitoring.MustCreateLabel("result")
 	resultSuccess = "success"
@@ -33,8 +35,19 @@ var (
 		"Total number of pods repaired by repair controller",
 		monitoring.WithLabels(typeLabel, resultLabel),
 	)
+
+	reasonLabel          = monitoring.MustCreateLabel("reason")
+	reasonNotBroken      = "not_broken"
+	reasonAlreadyLabeled = "already_labeled"
+	reasonAlreadyTainted = "already_tainted"
+
+	podsSkipped = monitoring.NewSum(
+		"istio_cni_repair_pods_skipped_total",
+		"Total number of pods skipped by repair controller, by reason",
+		monitoring.WithLabels(reasonLabel),
+	)
 )
 
 func init() {
-	monitoring.MustRegister(podsRepaired)
+	monitoring.MustRegister(podsRepaired, podsSkipped)
 }
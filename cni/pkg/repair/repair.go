@@ -18,11 +18,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-multierror"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	"istio.io/istio/cni/pkg/config"
 	"istio.io/istio/pkg/kube"
@@ -35,24 +39,48 @@ var repairLog = log.RegisterScope("repair", "CNI race condition repair", 0)
 type brokenPodReconciler struct {
 	client client.Interface
 	cfg    *config.RepairConfig
+	// recorder records the events of the repaired and skipped pods, if set.
+	recorder record.EventRecorder
+	// nodeTainted is true if the node may have the taint of the broken pods, which is removed once no broken
+	// pod is left on the node.
+	nodeTainted *atomic.Bool
 }
 
 // Constructs a new brokenPodReconciler struct.
 func newBrokenPodReconciler(client client.Interface, cfg *config.RepairConfig) brokenPodReconciler {
 	return brokenPodReconciler{
-		client: client,
-		cfg:    cfg,
+		client:      client,
+		cfg:         cfg,
+		nodeTainted: &atomic.Bool{},
 	}
 }
 
 func (bpr brokenPodReconciler) ReconcilePod(pod corev1.Pod) (err error) {
 	repairLog.Debugf("Reconciling pod %s", pod.Name)
 
+	switch bpr.action(pod) {
+	case ActionDeletePod:
+		return bpr.deleteBrokenPod(pod)
+	case ActionLabelPod:
+		return bpr.labelBrokenPod(pod)
+	case ActionTaintNode:
+		return bpr.taintBrokenPodNode(pod)
+	case ActionAlert:
+		return bpr.alertBrokenPod(pod)
+	}
+	return nil
+}
+
+// Repair all pods detected as broken by ListPods with the action of their namespace
+func (bpr brokenPodReconciler) RepairBrokenPods() error {
+	podList, err := bpr.ListBrokenPods()
+	if err != nil {
+		return err
+	}
+
 	var multierr *multierror.Error
-	if bpr.cfg.DeletePods {
-		multierr = multierror.Append(err, bpr.deleteBrokenPod(pod))
-	} else if bpr.cfg.LabelPods {
-		multierr = multierror.Append(err, bpr.labelBrokenPod(pod))
+	for _, pod := range podList.Items {
+		multierr = multierror.Append(multierr, bpr.ReconcilePod(pod))
 	}
 	return multierr.ErrorOrNil()
 }
@@ -77,6 +105,7 @@ func (bpr brokenPodReconciler) labelBrokenPod(pod corev1.Pod) (err error) {
 	m := podsRepaired.With(typeLabel.Value(labelType))
 	if !bpr.detectPod(pod) {
 		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonNotBroken)).Increment()
 		return
 	}
 	repairLog.Infof("Pod detected as broken, adding label: %s/%s", pod.Namespace, pod.Name)
@@ -84,7 +113,9 @@ func (bpr brokenPodReconciler) labelBrokenPod(pod corev1.Pod) (err error) {
 	labels := pod.GetLabels()
 	if _, ok := labels[bpr.cfg.LabelKey]; ok {
 		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonAlreadyLabeled)).Increment()
 		repairLog.Infof("Pod %s/%s already has label with key %s, skipping", pod.Namespace, pod.Name, bpr.cfg.LabelKey)
+		bpr.recordEvent(pod, corev1.EventTypeWarning, eventSkipped, "Pod already has label %s", bpr.cfg.LabelKey)
 		return
 	}
 
@@ -99,9 +130,11 @@ func (bpr brokenPodReconciler) labelBrokenPod(pod corev1.Pod) (err error) {
 	if _, err = bpr.client.CoreV1().Pods(pod.Namespace).Update(context.TODO(), &pod, metav1.UpdateOptions{}); err != nil {
 		repairLog.Errorf("Failed to update pod: %s", err)
 		m.With(resultLabel.Value(resultFail)).Increment()
+		bpr.recordEvent(pod, corev1.EventTypeWarning, eventFailed, "Failed to label pod: %v", err)
 		return
 	}
 	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(pod, corev1.EventTypeWarning, eventPodLabeled, "Labeled pod with %s=%s", bpr.cfg.LabelKey, bpr.cfg.LabelValue)
 	return
 }
 
@@ -135,15 +168,18 @@ func (bpr brokenPodReconciler) deleteBrokenPod(pod corev1.Pod) error {
 	// Added for safety, to make sure no healthy pods get labeled.
 	if !bpr.detectPod(pod) {
 		m.With(resultLabel.Value(resultSkip)).Increment()
+		podsSkipped.With(reasonLabel.Value(reasonNotBroken)).Increment()
 		return nil
 	}
 	repairLog.Infof("Pod detected as broken, deleting: %s/%s", pod.Namespace, pod.Name)
 	err := bpr.client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
 	if err != nil {
 		m.With(resultLabel.Value(resultFail)).Increment()
+		bpr.recordEvent(pod, corev1.EventTypeWarning, eventFailed, "Failed to delete pod: %v", err)
 		return err
 	}
 	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(pod, corev1.EventTypeWarning, eventPodDeleted, "Deleted pod broken by the Istio CNI race condition")
 	return nil
 }
 
@@ -232,12 +268,25 @@ func StartRepair(ctx context.Context, cfg *config.RepairConfig) {
 		return
 	}
 
+	if cfg.Action != "" {
+		if err := ValidateAction(cfg.Action); err != nil {
+			repairLog.Fatalf("CNI repair has an invalid configuration: %s", err)
+		}
+	}
+
 	clientSet, err := clientSetup()
 	if err != nil {
 		repairLog.Fatalf("CNI repair could not construct clientSet: %s", err)
 	}
 
 	podFixer := newBrokenPodReconciler(clientSet, cfg)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientSet.CoreV1().Events("")})
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+	podFixer.recorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "istio-cni-repair", Host: cfg.NodeName})
 
 	if cfg.RunAsDaemon {
 		rc, err := NewRepairController(podFixer)
@@ -247,10 +296,13 @@ func StartRepair(ctx context.Context, cfg *config.RepairConfig) {
 		rc.Run(ctx.Done())
 	} else {
 		var multierr *multierror.Error
-		if podFixer.cfg.LabelPods {
+		if podFixer.cfg.Action != "" {
+			multierr = multierror.Append(err, podFixer.RepairBrokenPods())
+		}
+		if podFixer.cfg.Action == "" && podFixer.cfg.LabelPods {
 			multierr = multierror.Append(err, podFixer.LabelBrokenPods())
 		}
-		if podFixer.cfg.DeletePods {
+		if podFixer.cfg.Action == "" && podFixer.cfg.DeletePods {
 			multierr = multierror.Append(err, podFixer.DeleteBrokenPods())
 		}
 		if multierr.ErrorOrNil() != nil {
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"istio.io/istio/cni/pkg/config"
@@ -347,8 +349,9 @@ func TestNewBrokenPodReconciler(t *testing.T) {
 				config: cfg,
 			},
 			wantBpr: brokenPodReconciler{
-				client: client,
-				cfg:    &cfg,
+				client:      client,
+				cfg:         &cfg,
+				nodeTainted: &atomic.Bool{},
 			},
 		},
 	}
@@ -658,3 +661,121 @@ func TestAddToWorkerQueue(t *testing.T) {
 		})
 	}
 }
+
+func TestBrokenPodReconciler_reconcilePodActions(t *testing.T) {
+	brokenPod := *brokenPodWaiting.DeepCopy()
+	brokenPod.Namespace = "test-ns"
+	namespace := func(action string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns"}}
+		if action != "" {
+			ns.Annotations = map[string]string{ActionAnnotation: action}
+		}
+		return ns
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "TestNode"}}
+	repairConfig := config.RepairConfig{
+		InitContainerName:  constants.ValidationContainerName,
+		InitExitCode:       126,
+		InitTerminationMsg: "Died for some reason",
+		LabelKey:           "testkey",
+		LabelValue:         "testval",
+		NodeTaintKey:       "cni.istio.io/broken-pod",
+	}
+	tests := []struct {
+		name         string
+		action       string
+		deletePods   bool
+		nsAction     string
+		wantPod      bool
+		wantLabel    bool
+		wantTainted  bool
+		wantEvent    string
+		reconcileTwo bool
+	}{
+		{name: "legacy delete", deletePods: true, wantEvent: eventPodDeleted},
+		{name: "label", action: ActionLabelPod, wantPod: true, wantLabel: true, wantEvent: eventPodLabeled},
+		{name: "alert", action: ActionAlert, wantPod: true, wantEvent: eventPodDetected},
+		{name: "taint", action: ActionTaintNode, wantPod: true, wantTainted: true, wantEvent: eventNodeTainted},
+		{name: "already tainted", action: ActionTaintNode, wantPod: true, wantTainted: true, wantEvent: eventSkipped, reconcileTwo: true},
+		{name: "namespace override", action: ActionDeletePod, nsAction: ActionAlert, wantPod: true, wantEvent: eventPodDetected},
+		{name: "invalid namespace override", action: ActionLabelPod, nsAction: "drop", wantPod: true, wantLabel: true, wantEvent: eventPodLabeled},
+		{name: "namespace taint override", action: ActionLabelPod, nsAction: ActionTaintNode, wantPod: true, wantLabel: true, wantEvent: eventPodLabeled},
+		{name: "no action", wantPod: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := repairConfig
+			cfg.Action = tt.action
+			cfg.DeletePods = tt.deletePods
+			recorder := record.NewFakeRecorder(10)
+			bpr := brokenPodReconciler{
+				client:   fake.NewSimpleClientset(brokenPod.DeepCopy(), namespace(tt.nsAction), node.DeepCopy()),
+				cfg:      &cfg,
+				recorder: recorder,
+			}
+			if err := bpr.ReconcilePod(brokenPod); err != nil {
+				t.Fatalf("ReconcilePod() error = %v", err)
+			}
+			if tt.reconcileTwo {
+				<-recorder.Events
+				if err := bpr.ReconcilePod(brokenPod); err != nil {
+					t.Fatalf("ReconcilePod() error = %v", err)
+				}
+			}
+
+			pod, err := bpr.client.CoreV1().Pods("test-ns").Get(context.TODO(), brokenPod.Name, metav1.GetOptions{})
+			if (err == nil) != tt.wantPod {
+				t.Fatalf("pod exists = %v, want %v", err == nil, tt.wantPod)
+			}
+			if tt.wantPod {
+				if _, f := pod.Labels["testkey"]; f != tt.wantLabel {
+					t.Errorf("pod labeled = %v, want %v", f, tt.wantLabel)
+				}
+			}
+			n, _ := bpr.client.CoreV1().Nodes().Get(context.TODO(), "TestNode", metav1.GetOptions{})
+			if tainted := len(n.Spec.Taints) == 1 && n.Spec.Taints[0].Key == cfg.NodeTaintKey; tainted != tt.wantTainted {
+				t.Errorf("node tainted = %v, want %v (taints %v)", tainted, tt.wantTainted, n.Spec.Taints)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, tt.wantEvent) || tt.wantEvent == "" {
+					t.Errorf("got event %q, want %q", event, tt.wantEvent)
+				}
+			default:
+				if tt.wantEvent != "" {
+					t.Errorf("got no event, want %q", tt.wantEvent)
+				}
+			}
+		})
+	}
+}
+
+func TestBrokenPodReconciler_untaintNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "TestNode"},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: "other", Effect: corev1.TaintEffectNoExecute},
+			{Key: "cni.istio.io/broken-pod", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+		}},
+	}
+	bpr := newBrokenPodReconciler(fake.NewSimpleClientset(node), &config.RepairConfig{NodeTaintKey: "cni.istio.io/broken-pod"})
+	if err := bpr.untaintNode("TestNode"); err != nil {
+		t.Fatalf("untaintNode() error = %v", err)
+	}
+	n, _ := bpr.client.CoreV1().Nodes().Get(context.TODO(), "TestNode", metav1.GetOptions{})
+	if !reflect.DeepEqual(n.Spec.Taints, node.Spec.Taints[:1]) {
+		t.Errorf("got taints %v, want %v", n.Spec.Taints, node.Spec.Taints[:1])
+	}
+}
+
+func TestValidateAction(t *testing.T) {
+	for _, action := range []string{ActionDeletePod, ActionLabelPod, ActionTaintNode, ActionAlert} {
+		if err := ValidateAction(action); err != nil {
+			t.Errorf("ValidateAction(%q) = %v", action, err)
+		}
+	}
+	if err := ValidateAction("drop"); err == nil {
+		t.Errorf("ValidateAction(%q) got no error", "drop")
+	}
+}
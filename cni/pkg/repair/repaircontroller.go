@@ -30,9 +30,13 @@ import (
 	"istio.io/istio/pkg/kube"
 )
 
+// untaintInterval is the interval the node is checked at for broken pods, to remove its taint once none is left.
+const untaintInterval = 10 * time.Second
+
 type Controller struct {
 	clientset     client.Interface
 	workQueue     workqueue.RateLimitingInterface
+	pods          cache.Store
 	podController cache.Controller
 
 	reconciler brokenPodReconciler
@@ -72,7 +76,7 @@ func NewRepairController(reconciler brokenPodReconciler) (*Controller, error) {
 		},
 	)
 
-	_, c.podController = cache.NewInformer(podListWatch, &corev1.Pod{}, 0, cache.ResourceEventHandlerFuncs{
+	c.pods, c.podController = cache.NewInformer(podListWatch, &corev1.Pod{}, 0, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(newObj any) {
 			c.mayAddToWorkQueue(newObj)
 		},
@@ -102,6 +106,12 @@ func (rc *Controller) Run(stopCh <-chan struct{}) {
 		return
 	}
 
+	if rc.reconciler.defaultAction() == ActionTaintNode {
+		// The node may have been tainted before a restart.
+		rc.reconciler.nodeTainted.Store(true)
+		go wait.Until(rc.untaintHealthyNode, untaintInterval, stopCh)
+	}
+
 	// This will run the func every 1 second until stopCh is sent
 	go wait.Until(
 		func() {
@@ -115,6 +125,23 @@ func (rc *Controller) Run(stopCh <-chan struct{}) {
 	)
 }
 
+// untaintHealthyNode removes the taint of the broken pods from the node once no broken pod is left on it.
+func (rc *Controller) untaintHealthyNode() {
+	if !rc.reconciler.nodeTainted.Load() {
+		return
+	}
+	for _, obj := range rc.pods.List() {
+		if pod, ok := obj.(*corev1.Pod); ok && rc.reconciler.detectPod(*pod) {
+			return
+		}
+	}
+	if err := rc.reconciler.untaintNode(rc.reconciler.cfg.NodeName); err != nil {
+		repairLog.Errorf("Failed to remove taint %s from node %s: %v", rc.reconciler.cfg.NodeTaintKey, rc.reconciler.cfg.NodeName, err)
+		return
+	}
+	rc.reconciler.nodeTainted.Store(false)
+}
+
 // Process the next available item in the work queue.
 // Return false if exiting permanently, else return true
 // so the loop keeps processing.
@@ -19,8 +19,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	promModel "github.com/prometheus/common/model"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"istio.io/istio/istioctl/pkg/authz"
 	"istio.io/istio/istioctl/pkg/util/configdump"
@@ -29,7 +33,12 @@ import (
 	"istio.io/pkg/log"
 )
 
-var configDumpFile string
+var (
+	configDumpFile string
+
+	generateDuration time.Duration
+	generateEnforce  bool
+)
 
 var checkCmd = &cobra.Command{
 	Use:   "check [<type>/]<name>[.<namespace>]",
@@ -91,6 +100,81 @@ The command also supports reading from a standalone config dump file with flag -
 	},
 }
 
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate AuthorizationPolicies from the traffic observed in a namespace.",
+	Long: `Generate prints least-privilege ALLOW AuthorizationPolicies for the workloads of a namespace, matching
+the traffic reported to Prometheus by their proxies over the given duration. One policy is generated per
+destination app, allowing the principals of the sources observed sending requests or opening connections
+to the app.
+
+The policies are generated in dry-run mode unless --enforce is set, so their decisions can be audited
+before enforcing them. Plaintext traffic, which has no source principal, is reported but can not be allowed.`,
+	Example: `  # Generate AuthorizationPolicies from the traffic observed in namespace foo over the last day:
+  istioctl x authz generate -n foo
+
+  # Generate enforced AuthorizationPolicies from the traffic observed over the last week:
+  istioctl x authz generate -n foo --duration 168h --enforce`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kubeClient, err := kubeClient(kubeconfig, configContext)
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %w", err)
+		}
+		promAPI, fw, err := prometheusPortForward(kubeClient)
+		if err != nil {
+			return err
+		}
+		defer fw.Close()
+
+		ns := handlers.HandleNamespace(namespace, defaultNamespace)
+		traffic, err := observedTraffic(promAPI, ns, generateDuration)
+		if err != nil {
+			return err
+		}
+		policies, warnings := authz.GeneratePolicies(ns, traffic, generateEnforce)
+		for _, w := range warnings {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+		}
+		for i, policy := range policies {
+			b, err := yaml.Marshal(policy)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "---")
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(b))
+		}
+		return nil
+	},
+}
+
+// observedTraffic returns the traffic to the workloads of the namespace reported to Prometheus over the duration.
+func observedTraffic(promAPI promv1.API, namespace string, duration time.Duration) ([]authz.ObservedTraffic, error) {
+	var traffic []authz.ObservedTraffic
+	for _, metric := range []string{reqTot, tcpConnectionsOpened} {
+		query := fmt.Sprintf(`sum(increase(%s{reporter="destination",%s="%s"}[%s])) by (%s, %s) > 0`,
+			metric, destWorkloadNamespaceLabel, namespace, duration, destAppLabel, sourcePrincipalLabel)
+		val, _, err := promAPI.Query(context.Background(), query, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("query() failure for '%s': %v", query, err)
+		}
+		log.Debugf("executing query: %s  result:%s", query, val)
+		v, ok := val.(promModel.Vector)
+		if !ok {
+			return nil, fmt.Errorf("bad metric value type returned for query")
+		}
+		for _, sample := range v {
+			traffic = append(traffic, authz.ObservedTraffic{
+				SourcePrincipal: string(sample.Metric[sourcePrincipalLabel]),
+				DestinationApp:  string(sample.Metric[destAppLabel]),
+			})
+		}
+	}
+	return traffic, nil
+}
+
 func getConfigDumpFromFile(filename string) (*configdump.Wrapper, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -149,6 +233,7 @@ func AuthZ() *cobra.Command {
 	}
 
 	cmd.AddCommand(checkCmd)
+	cmd.AddCommand(generateCmd)
 	cmd.Long += "\n\n" + ExperimentalMsg
 	return cmd
 }
@@ -156,4 +241,8 @@ func AuthZ() *cobra.Command {
 func init() {
 	checkCmd.PersistentFlags().StringVarP(&configDumpFile, "file", "f", "",
 		"The json file with Envoy config dump to be checked")
+	generateCmd.PersistentFlags().DurationVarP(&generateDuration, "duration", "d", 24*time.Hour,
+		"The duration of the observed traffic")
+	generateCmd.PersistentFlags().BoolVar(&generateEnforce, "enforce", false,
+		"Generate enforced policies instead of dry-run policies")
 }
@@ -13,3 +13,99 @@
 // limitations under the License.
 
 package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	prometheus_model "github.com/prometheus/common/model"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/authz"
+)
+
+func TestAuthzGenerate(t *testing.T) {
+	sample := func(app, principal string) *prometheus_model.Sample {
+		return &prometheus_model.Sample{
+			Metric: prometheus_model.Metric{destAppLabel: prometheus_model.LabelValue(app), sourcePrincipalLabel: prometheus_model.LabelValue(principal)},
+			Value:  1,
+		}
+	}
+	mockProm := mockPromAPI{
+		cannedResponse: map[string]prometheus_model.Value{
+			`sum(increase(istio_requests_total{reporter="destination",destination_workload_namespace="foo"}[1h0m0s])) by (destination_app, source_principal) > 0`: prometheus_model.Vector{ // nolint: lll
+				sample("reviews", "spiffe://cluster.local/ns/foo/sa/productpage"),
+				sample("reviews", "spiffe://cluster.local/ns/bar/sa/sleep"),
+				sample("details", "unknown"),
+			},
+			`sum(increase(istio_tcp_connections_opened_total{reporter="destination",destination_workload_namespace="foo"}[1h0m0s])) by (destination_app, source_principal) > 0`: prometheus_model.Vector{ // nolint: lll
+				sample("mongodb", "spiffe://cluster.local/ns/foo/sa/reviews"),
+			},
+		},
+	}
+	traffic, err := observedTraffic(mockProm, "foo", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, warnings := authz.GeneratePolicies("foo", traffic, false)
+	if want := []string{"ignoring plaintext traffic to details, which can not be allowed by principal"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %v, want %v", warnings, want)
+	}
+	var got string
+	for _, p := range policies {
+		b, err := yaml.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got += "---\n" + string(b)
+	}
+	want := `---
+apiVersion: security.istio.io/v1beta1
+kind: AuthorizationPolicy
+metadata:
+  annotations:
+    istio.io/dry-run: "true"
+  creationTimestamp: null
+  name: mongodb-observed
+  namespace: foo
+spec:
+  rules:
+  - from:
+    - source:
+        principals:
+        - cluster.local/ns/foo/sa/reviews
+  selector:
+    matchLabels:
+      app: mongodb
+status: {}
+---
+apiVersion: security.istio.io/v1beta1
+kind: AuthorizationPolicy
+metadata:
+  annotations:
+    istio.io/dry-run: "true"
+  creationTimestamp: null
+  name: reviews-observed
+  namespace: foo
+spec:
+  rules:
+  - from:
+    - source:
+        principals:
+        - cluster.local/ns/bar/sa/sleep
+        - cluster.local/ns/foo/sa/productpage
+  selector:
+    matchLabels:
+      app: reviews
+status: {}
+`
+	if got != want {
+		t.Errorf("got policies:\n%s\nwant:\n%s", got, want)
+	}
+
+	policies, _ = authz.GeneratePolicies("foo", traffic, true)
+	if len(policies) != 2 || policies[0].Annotations != nil {
+		t.Errorf("expected enforced policies, got %v", policies)
+	}
+}
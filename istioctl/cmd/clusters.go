@@ -19,12 +19,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"istio.io/istio/istioctl/pkg/clioptions"
 	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/kube/multicluster"
 )
 
 // TODO move to multicluster package; requires exposing some private funcs/vars in this package
@@ -55,16 +58,56 @@ func writeMulticlusterStatus(out io.Writer, input map[string][]byte) error {
 		return err
 	}
 	w := new(tabwriter.Writer).Init(out, 0, 8, 5, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tSECRET\tSTATUS\tISTIOD")
+	_, _ = fmt.Fprintln(w, "NAME\tSECRET\tSTATUS\tENDPOINTS\tHEALTH\tLAST ERROR\tISTIOD")
 	for istiod, clusters := range statuses {
 		for _, c := range clusters {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.ID, c.SecretName, c.SyncStatus, istiod)
+			health := c.Health
+			if health == "" {
+				health = "-"
+			} else if c.HealthMessage != "" {
+				health += " (" + c.HealthMessage + ")"
+			}
+			lastError := "-"
+			if c.LastError != "" {
+				lastError = c.LastError
+				if c.LastErrorTime != nil {
+					lastError += " (" + c.LastErrorTime.Format(time.RFC3339) + ")"
+				}
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", c.ID, c.SecretName, c.SyncStatus, c.Endpoints, health, lastError, istiod)
 		}
 	}
 	_ = w.Flush()
 	return nil
 }
 
+// writeUnhealthyClusters warns about the remote clusters of each istiod which are not healthy.
+func writeUnhealthyClusters(out io.Writer, input map[string][]byte) error {
+	statuses, err := parseClusterStatuses(input)
+	if err != nil {
+		return err
+	}
+	istiods := make([]string, 0, len(statuses))
+	for istiod := range statuses {
+		istiods = append(istiods, istiod)
+	}
+	sort.Strings(istiods)
+	for _, istiod := range istiods {
+		for _, c := range statuses[istiod] {
+			switch c.Health {
+			case "", multicluster.HealthHealthy, multicluster.HealthUnknown:
+				continue
+			}
+			health := c.Health
+			if c.HealthMessage != "" {
+				health += " (" + c.HealthMessage + ")"
+			}
+			_, _ = fmt.Fprintf(out, "Warning: remote cluster %s of %s is %s\n", c.ID, istiod, health)
+		}
+	}
+	return nil
+}
+
 func parseClusterStatuses(input map[string][]byte) (map[string][]cluster.DebugInfo, error) {
 	statuses := make(map[string][]cluster.DebugInfo, len(input))
 	for istiodKey, bytes := range input {
@@ -0,0 +1,60 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestWriteMulticlusterStatus(t *testing.T) {
+	input := map[string][]byte{
+		"istiod-1": []byte(`[
+  {"id": "remote-1", "secretName": "istio-system/istio-remote-secret-1", "syncStatus": "synced", "health": "healthy",
+   "lastError": "connection refused", "lastErrorTime": "2023-05-01T10:00:00Z", "endpoints": 42},
+  {"id": "remote-2", "secretName": "istio-system/istio-remote-secret-2", "syncStatus": "timeout", "health": "stale",
+   "healthMessage": "watches failing since 2023-05-01T09:00:00Z", "endpoints": 0}
+]`),
+	}
+	out := &bytes.Buffer{}
+	assert.NoError(t, writeMulticlusterStatus(out, input))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+	}
+	assert.Equal(t, lines, []string{
+		"NAME SECRET STATUS ENDPOINTS HEALTH LAST ERROR ISTIOD",
+		"remote-1 istio-system/istio-remote-secret-1 synced 42 healthy connection refused (2023-05-01T10:00:00Z) istiod-1",
+		"remote-2 istio-system/istio-remote-secret-2 timeout 0 stale (watches failing since 2023-05-01T09:00:00Z) - istiod-1",
+	})
+}
+
+func TestWriteUnhealthyClusters(t *testing.T) {
+	input := map[string][]byte{
+		"istiod-1": []byte(`[
+  {"id": "remote-1", "syncStatus": "synced", "health": "healthy"},
+  {"id": "remote-2", "syncStatus": "synced", "health": "stale", "healthMessage": "watches failing since 2023-05-01T09:00:00Z"},
+  {"id": "remote-3", "syncStatus": "synced", "health": "unknown"}
+]`),
+		"istiod-2": []byte(`[{"id": "remote-4", "syncStatus": "synced", "health": "auth-failure"}]`),
+	}
+	out := &bytes.Buffer{}
+	assert.NoError(t, writeUnhealthyClusters(out, input))
+	assert.Equal(t, out.String(), "Warning: remote cluster remote-2 of istiod-1 is stale (watches failing since 2023-05-01T09:00:00Z)\n"+
+		"Warning: remote cluster remote-4 of istiod-2 is auth-failure\n")
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"istio.io/istio/istioctl/pkg/clioptions"
+	"istio.io/istio/pkg/kube"
 	"istio.io/pkg/log"
 )
 
@@ -41,7 +42,10 @@ var (
 const (
 	destWorkloadLabel          = "destination_workload"
 	destWorkloadNamespaceLabel = "destination_workload_namespace"
+	destAppLabel               = "destination_app"
+	sourcePrincipalLabel       = "source_principal"
 	reqTot                     = "istio_requests_total"
+	tcpConnectionsOpened       = "istio_tcp_connections_opened_total"
 	reqDur                     = "istio_request_duration_milliseconds"
 )
 
@@ -103,49 +107,59 @@ func run(c *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create k8s client: %v", err)
 	}
 
+	promAPI, fw, err := prometheusPortForward(client)
+	if err != nil {
+		return err
+	}
+	// Close the forwarder either when we exit or when an this processes is interrupted.
+	defer fw.Close()
+
+	printHeader(c.OutOrStdout())
+
+	workloads := args
+	for _, workload := range workloads {
+		sm, err := metrics(promAPI, workload, metricsDuration)
+		if err != nil {
+			return fmt.Errorf("could not build metrics for workload '%s': %v", workload, err)
+		}
+
+		printMetrics(c.OutOrStdout(), sm)
+	}
+	return nil
+}
+
+// prometheusPortForward returns the API of the Prometheus pod of the istio namespace, through a port forwarder
+// that the caller must close.
+func prometheusPortForward(client kube.CLIClient) (promv1.API, kube.PortForwarder, error) {
 	pl, err := client.PodsForSelector(context.TODO(), istioNamespace, "app=prometheus")
 	if err != nil {
-		return fmt.Errorf("not able to locate Prometheus pod: %v", err)
+		return nil, nil, fmt.Errorf("not able to locate Prometheus pod: %v", err)
 	}
 
 	if len(pl.Items) < 1 {
-		return errors.New("no Prometheus pods found")
+		return nil, nil, errors.New("no Prometheus pods found")
 	}
 
 	// only use the first pod in the list
 	promPod := pl.Items[0]
 	fw, err := client.NewPortForwarder(promPod.Name, istioNamespace, "", 0, 9090)
 	if err != nil {
-		return fmt.Errorf("could not build port forwarder for prometheus: %v", err)
+		return nil, nil, fmt.Errorf("could not build port forwarder for prometheus: %v", err)
 	}
 
 	if err = fw.Start(); err != nil {
-		return fmt.Errorf("failure running port forward process: %v", err)
+		return nil, nil, fmt.Errorf("failure running port forward process: %v", err)
 	}
-
-	// Close the forwarder either when we exit or when an this processes is interrupted.
-	defer fw.Close()
 	closePortForwarderOnInterrupt(fw)
 
 	log.Debugf("port-forward to prometheus pod ready")
 
 	promAPI, err := prometheusAPI(fmt.Sprintf("http://%s", fw.Address()))
 	if err != nil {
-		return fmt.Errorf("failure running port forward process: %v", err)
+		fw.Close()
+		return nil, nil, fmt.Errorf("failure running port forward process: %v", err)
 	}
-
-	printHeader(c.OutOrStdout())
-
-	workloads := args
-	for _, workload := range workloads {
-		sm, err := metrics(promAPI, workload, metricsDuration)
-		if err != nil {
-			return fmt.Errorf("could not build metrics for workload '%s': %v", workload, err)
-		}
-
-		printMetrics(c.OutOrStdout(), sm)
-	}
-	return nil
+	return promAPI, fw, nil
 }
 
 func prometheusAPI(address string) (promv1.API, error) {
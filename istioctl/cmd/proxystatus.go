@@ -36,6 +36,7 @@ import (
 
 func statusCommand() *cobra.Command {
 	var opts clioptions.ControlPlaneOptions
+	var remoteClusters bool
 
 	statusCmd := &cobra.Command{
 		Use:   "proxy-status [<type>/]<name>[.<namespace>]",
@@ -57,6 +58,9 @@ Retrieves last sent and last acknowledged xDS sync from Istiod to each Envoy in
   kubectl port-forward -n istio-system istio-egressgateway-59585c5b9c-ndc59 15000 &
   curl localhost:15000/config_dump > cd.json
   istioctl proxy-status istio-egressgateway-59585c5b9c-ndc59.istio-system --file cd.json
+
+  # Retrieve sync status for all Envoys, and the sync status of the remote clusters each Istiod reads endpoints from
+  istioctl proxy-status --remote-clusters
 `,
 		Aliases: []string{"ps"},
 		Args: func(cmd *cobra.Command, args []string) error {
@@ -105,13 +109,30 @@ Retrieves last sent and last acknowledged xDS sync from Istiod to each Envoy in
 				return err
 			}
 			sw := pilot.StatusWriter{Writer: c.OutOrStdout()}
-			return sw.PrintAll(statuses)
+			if err := sw.PrintAll(statuses); err != nil {
+				return err
+			}
+			clusters, err := kubeClient.AllDiscoveryDo(context.TODO(), istioNamespace, "debug/clusterz")
+			if !remoteClusters {
+				// The proxies may be sent stale endpoints of the unhealthy remote clusters, so they are reported anyway
+				if err == nil {
+					_ = writeUnhealthyClusters(c.ErrOrStderr(), clusters)
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(c.OutOrStdout())
+			return writeMulticlusterStatus(c.OutOrStdout(), clusters)
 		},
 	}
 
 	opts.AttachControlPlaneFlags(statusCmd)
 	statusCmd.PersistentFlags().StringVarP(&configDumpFile, "file", "f", "",
 		"Envoy config dump JSON file")
+	statusCmd.PersistentFlags().BoolVar(&remoteClusters, "remote-clusters", false,
+		"Also print the sync status, endpoint count and last error of the remote clusters each istiod reads endpoints from")
 
 	return statusCmd
 }
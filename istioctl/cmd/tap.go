@@ -0,0 +1,193 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/istioctl/pkg/clioptions"
+	"istio.io/istio/istioctl/pkg/util/handlers"
+	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pkg/kube"
+)
+
+// tapTimeout is added to the duration of the session, for the proxy to report the traces through istiod.
+const tapTimeout = 15 * time.Second
+
+func tapCmd() *cobra.Command {
+	var (
+		opts      clioptions.ControlPlaneOptions
+		duration  time.Duration
+		maxTraces int
+		method    string
+		path      string
+		headers   []string
+		full      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "tap <pod-name[.namespace]>",
+		Short: "Capture the HTTP traffic of a workload",
+		Long: `Runs a time-boxed Envoy tap session on the sidecar or gateway of the pod, and prints the captured
+request and response metadata as JSON. The session is started through istiod, which must run with
+PILOT_ENABLE_TAP_SESSIONS enabled. Only the inbound traffic of sidecars is captured.
+
+By default, only the metadata of the requests is captured: the bodies are dropped, and the values of
+the headers are redacted, except a few such as content-type or x-request-id. Use --full to capture
+the bodies and header values, which may contain credentials or personal data.
+
+The method, path and header values match as in AuthorizationPolicy: "*" matches any value, and a
+leading or trailing "*" matches a suffix or a prefix.`,
+		Example: `  # Capture the requests received by productpage for 30 seconds
+  istioctl experimental tap productpage-v1-7c6dd6b9f4-j9m4l.default
+
+  # Capture at most 10 POST requests to /api/ for one minute
+  istioctl experimental tap productpage-v1-7c6dd6b9f4-j9m4l --method POST --path '/api/*' --max-traces 10 --duration 1m
+
+  # Capture the requests of a given user
+  istioctl experimental tap productpage-v1-7c6dd6b9f4-j9m4l --header 'x-user:jason'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			kubeClient, err := kubeClientWithRevision(kubeconfig, configContext, opts.Revision)
+			if err != nil {
+				return err
+			}
+			podName, ns, err := handlers.InferPodInfoFromTypedResource(args[0],
+				handlers.HandleNamespace(namespace, defaultNamespace),
+				kubeClient.UtilFactory())
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			query.Set("proxyID", podName+"."+ns)
+			query.Set("duration", duration.String())
+			query.Set("maxTraces", strconv.Itoa(maxTraces))
+			if method != "" {
+				query.Set("method", method)
+			}
+			if path != "" {
+				query.Set("path", path)
+			}
+			for _, h := range headers {
+				query.Add("header", h)
+			}
+			if full {
+				query.Set("full", "true")
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), duration+tapTimeout)
+			defer cancel()
+			c.PrintErrf("Tapping %s.%s for %v...\n", podName, ns, duration)
+			dump, err := tapThroughIstiod(ctx, kubeClient, "debug/tapz?"+query.Encode())
+			if err != nil {
+				return err
+			}
+			return printTraces(c.OutOrStdout(), dump.Traces)
+		},
+	}
+	opts.AttachControlPlaneFlags(cmd)
+	cmd.PersistentFlags().DurationVarP(&duration, "duration", "d", 30*time.Second,
+		"How long the traffic is tapped for, at most 5m")
+	cmd.PersistentFlags().IntVar(&maxTraces, "max-traces", 100,
+		"Maximum number of requests captured, the session ends early once reached")
+	cmd.PersistentFlags().StringVar(&method, "method", "", "Capture only the requests with the HTTP method")
+	cmd.PersistentFlags().StringVar(&path, "path", "", "Capture only the requests with the path")
+	cmd.PersistentFlags().StringArrayVar(&headers, "header", nil,
+		"Capture only the requests with the header, formatted as name:value. Can be repeated")
+	cmd.PersistentFlags().BoolVar(&full, "full", false,
+		"Capture the bodies and the header values of the requests, which are otherwise redacted")
+	return cmd
+}
+
+// tapThroughIstiod runs the tap session on the istiod instance the proxy is connected to.
+func tapThroughIstiod(ctx context.Context, kubeClient kube.CLIClient, path string) (*xds.TapDump, error) {
+	istiods, err := kubeClient.GetIstioPods(ctx, istioNamespace, map[string]string{
+		"labelSelector": "app=istiod",
+		"fieldSelector": kube.RunningStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(istiods) == 0 {
+		return nil, fmt.Errorf("unable to find any Istiod instances")
+	}
+	for _, istiod := range istiods {
+		body, status, err := istiodDebugRequest(ctx, kubeClient, istiod.Name, istiod.Namespace, path)
+		if err != nil {
+			return nil, err
+		}
+		if status == http.StatusNotFound {
+			// The proxy is connected to another instance.
+			continue
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("istiod %s: %s", istiod.Name, bytes.TrimSpace(body))
+		}
+		dump := &xds.TapDump{}
+		if err := json.Unmarshal(body, dump); err != nil {
+			return nil, fmt.Errorf("failed to parse the traces: %v", err)
+		}
+		return dump, nil
+	}
+	return nil, fmt.Errorf("the proxy is not connected to any Istiod instance")
+}
+
+func istiodDebugRequest(ctx context.Context, kubeClient kube.CLIClient, podName, podNamespace, path string) ([]byte, int, error) {
+	fw, err := kubeClient.NewPortForwarder(podName, podNamespace, "", 0, 15014)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := fw.Start(); err != nil {
+		return nil, 0, fmt.Errorf("failure running port forward process: %v", err)
+	}
+	defer fw.Close()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/%s", fw.Address(), path), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+func printTraces(w io.Writer, traces []json.RawMessage) error {
+	if len(traces) == 0 {
+		_, _ = fmt.Fprintln(w, "No requests were captured.")
+		return nil
+	}
+	for _, trace := range traces {
+		var out bytes.Buffer
+		if err := json.Indent(&out, trace, "", "  "); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, out.String())
+	}
+	return nil
+}
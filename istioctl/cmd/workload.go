@@ -509,6 +509,13 @@ func createMeshConfig(kubeClient kube.CLIClient, wg *clientv1alpha3.WorkloadGrou
 		md["ISTIO_META_POD_PORTS"] = portsStr
 	}
 	md["ISTIO_META_WORKLOAD_NAME"] = wg.Name
+	// the probe of the WorkloadGroup can not express gRPC checks, they are passed to the agent in the proxy metadata
+	if grpcProbe := wg.Annotations[constants.GRPCProbeAnnotation]; grpcProbe != "" {
+		md["GRPC_READINESS_PROBE"] = grpcProbe
+		if meshConfig.DefaultConfig.ReadinessProbe == nil {
+			meshConfig.DefaultConfig.ReadinessProbe = &networkingv1alpha3.ReadinessProbe{}
+		}
+	}
 	lbls[label.ServiceCanonicalName.Name] = md["CANONICAL_SERVICE"]
 	lbls[label.ServiceCanonicalRevision.Name] = md["CANONICAL_REVISION"]
 	if labelsJSON, err := json.Marshal(lbls); err == nil {
@@ -0,0 +1,223 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/istioctl/pkg/clioptions"
+	"istio.io/istio/istioctl/pkg/multixds"
+	ztunnelDump "istio.io/istio/istioctl/pkg/writer/ztunnel/configdump"
+	"istio.io/istio/pilot/pkg/xds"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+)
+
+func ztunnelConfigCmd() *cobra.Command {
+	var opts clioptions.ControlPlaneOptions
+	var centralOpts clioptions.CentralControlPlaneOptions
+	var ztunnelNode, workloadAddress, output string
+
+	// run fetches the ztunnel state through Istiod, so it works without access to the ztunnel pods,
+	// and prints every matching ztunnel with the given function.
+	run := func(c *cobra.Command, printer func(cw *ztunnelDump.ConfigWriter) error) error {
+		kubeClient, err := kubeClientWithRevision(kubeconfig, configContext, opts.Revision)
+		if err != nil {
+			return err
+		}
+		resource := "ztunnelz"
+		if ztunnelNode != "" {
+			resource += "?node=" + url.QueryEscape(ztunnelNode)
+		}
+		xdsRequest := discovery.DiscoveryRequest{
+			ResourceNames: []string{resource},
+			Node: &core.Node{
+				Id: "debug~0.0.0.0~istioctl~cluster.local",
+			},
+			TypeUrl: v3.DebugType,
+		}
+		// Each ztunnel is connected to a single Istiod, so all instances must be queried.
+		xdsResponses, err := multixds.AllRequestAndProcessXds(&xdsRequest, centralOpts, istioNamespace,
+			"", "", kubeClient, multixds.DefaultOptions)
+		if err != nil {
+			return err
+		}
+		dumps := mergeZtunnelDumps(xdsResponses)
+		if len(dumps) == 0 {
+			if ztunnelNode != "" {
+				return fmt.Errorf("no ztunnel for node %q is connected to Istiod", ztunnelNode)
+			}
+			return fmt.Errorf("no ztunnel is connected to Istiod")
+		}
+		return printZtunnelDumps(c.OutOrStdout(), dumps, printer)
+	}
+
+	workloadFilter := func() ztunnelDump.WorkloadFilter {
+		return ztunnelDump.WorkloadFilter{
+			Address: workloadAddress,
+			Verbose: true,
+		}
+	}
+
+	workloadCmd := &cobra.Command{
+		Use:     "workload",
+		Short:   "Retrieves the workload configuration of ztunnels",
+		Aliases: []string{"workloads", "w"},
+		Args:    cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return run(c, func(cw *ztunnelDump.ConfigWriter) error {
+				switch output {
+				case summaryOutput:
+					return cw.PrintWorkloadSummary(workloadFilter())
+				case jsonOutput, yamlOutput:
+					return cw.PrintWorkloadDump(workloadFilter(), output)
+				default:
+					return fmt.Errorf("output format %q not supported", output)
+				}
+			})
+		},
+	}
+
+	secretCmd := &cobra.Command{
+		Use:     "secret",
+		Short:   "Retrieves the certificates of ztunnels",
+		Aliases: []string{"secrets", "s"},
+		Args:    cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return run(c, func(cw *ztunnelDump.ConfigWriter) error {
+				switch output {
+				case summaryOutput:
+					return cw.PrintSecretSummary()
+				case jsonOutput, yamlOutput:
+					return cw.PrintSecretDump(output)
+				default:
+					return fmt.Errorf("output format %q not supported", output)
+				}
+			})
+		},
+	}
+
+	allCmd := &cobra.Command{
+		Use:   "all",
+		Short: "Retrieves the workload configuration and certificates of ztunnels",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			if output != summaryOutput {
+				return fmt.Errorf("output format %q not supported, use the workload or secret command instead", output)
+			}
+			return run(c, func(cw *ztunnelDump.ConfigWriter) error {
+				return cw.PrintFullSummary(workloadFilter())
+			})
+		},
+	}
+
+	ztunnelConfigCmd := &cobra.Command{
+		Use:   "ztunnel-config",
+		Short: "Retrieves the configuration of ztunnels through Istiod",
+		Long: `Retrieves the configuration of the ztunnels connected to Istiod.
+The configuration is fetched by Istiod on behalf of istioctl, so access to the ztunnel pods, such as with port-forward, is not required.
+`,
+		Example: `  # Retrieve a summary of the workloads known to every ztunnel
+  istioctl x ztunnel-config workload
+
+  # Retrieve the certificates of the ztunnel running on node ambient-worker
+  istioctl x ztunnel-config secret --node ambient-worker
+
+  # Retrieve the full workload configuration of workload 10.244.1.4 from every ztunnel
+  istioctl x ztunnel-config workload --address 10.244.1.4 -o json
+`,
+	}
+	ztunnelConfigCmd.AddCommand(workloadCmd, secretCmd, allCmd)
+
+	opts.AttachControlPlaneFlags(ztunnelConfigCmd)
+	centralOpts.AttachControlPlaneFlags(ztunnelConfigCmd)
+	ztunnelConfigCmd.Long += "\n\n" + ExperimentalMsg
+	ztunnelConfigCmd.PersistentFlags().StringVar(&ztunnelNode, "node", "", "Only retrieve the configuration of the ztunnel on this node")
+	ztunnelConfigCmd.PersistentFlags().StringVar(&workloadAddress, "address", "", "Filter workloads by address field")
+	ztunnelConfigCmd.PersistentFlags().StringVarP(&output, "output", "o", summaryOutput, "Output format: one of json|yaml|short")
+	return ztunnelConfigCmd
+}
+
+// mergeZtunnelDumps combines the ztunnel dumps reported by each Istiod, keyed by node.
+func mergeZtunnelDumps(xdsResponses map[string]*discovery.DiscoveryResponse) map[string]xds.ZtunnelDump {
+	dumps := map[string]xds.ZtunnelDump{}
+	for _, response := range xdsResponses {
+		for _, resource := range response.Resources {
+			res := map[string]xds.ZtunnelDump{}
+			if err := json.Unmarshal(resource.Value, &res); err != nil {
+				// Istiod instances without a matching ztunnel respond with an error message rather than a dump.
+				continue
+			}
+			for node, dump := range res {
+				dumps[node] = dump
+			}
+		}
+	}
+	return dumps
+}
+
+func printZtunnelDumps(out io.Writer, dumps map[string]xds.ZtunnelDump, printer func(cw *ztunnelDump.ConfigWriter) error) error {
+	nodes := make([]string, 0, len(dumps))
+	for node := range dumps {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for i, node := range nodes {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		dump := dumps[node]
+		_, _ = fmt.Fprintf(out, "NODE: %s (%s)\n", node, dump.ProxyID)
+		if dump.Error != "" {
+			_, _ = fmt.Fprintf(out, "error: %s\n", dump.Error)
+			continue
+		}
+		cw, err := ztunnelConfigWriter(dump, out)
+		if err != nil {
+			return err
+		}
+		if err := printer(cw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ztunnelConfigWriter rebuilds the ztunnel config dump, which Istiod splits from the certificates, and primes a writer with it.
+func ztunnelConfigWriter(dump xds.ZtunnelDump, out io.Writer) (*ztunnelDump.ConfigWriter, error) {
+	configDump := make(map[string]json.RawMessage, len(dump.ConfigDump)+1)
+	for k, v := range dump.ConfigDump {
+		configDump[k] = v
+	}
+	if len(dump.Certificates) > 0 {
+		configDump["certificates"] = dump.Certificates
+	}
+	b, err := json.Marshal(configDump)
+	if err != nil {
+		return nil, err
+	}
+	cw := &ztunnelDump.ConfigWriter{Stdout: out}
+	if err := cw.Prime(b); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
@@ -0,0 +1,75 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	ztunnelDump "istio.io/istio/istioctl/pkg/writer/ztunnel/configdump"
+	"istio.io/istio/pilot/pkg/xds"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestZtunnelConfigDumps(t *testing.T) {
+	dump, err := json.Marshal(map[string]xds.ZtunnelDump{
+		"node-1": {
+			ProxyID: "ztunnel-abc.istio-system",
+			ConfigDump: map[string]json.RawMessage{
+				"workloads": json.RawMessage(`{"10.0.0.1":{"workloadIp":"10.0.0.1","name":"productpage","namespace":"default","node":"node-1"}}`),
+			},
+			Certificates: json.RawMessage(`[{"identity":"spiffe://cluster.local/ns/default/sa/default","state":"Available"}]`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := map[string]*discovery.DiscoveryResponse{
+		"istiod-1": {Resources: []*anypb.Any{{TypeUrl: v3.DebugType, Value: dump}}},
+		"istiod-2": {Resources: []*anypb.Any{{TypeUrl: v3.DebugType, Value: []byte("No ztunnel for the node is connected to this Pilot instance.")}}},
+	}
+	dumps := mergeZtunnelDumps(responses)
+	assert.Equal(t, len(dumps), 1)
+
+	out := &bytes.Buffer{}
+	err = printZtunnelDumps(out, dumps, func(cw *ztunnelDump.ConfigWriter) error {
+		return cw.PrintWorkloadSummary(ztunnelDump.WorkloadFilter{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"NODE: node-1 (ztunnel-abc.istio-system)", "productpage", "10.0.0.1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	err = printZtunnelDumps(out, dumps, func(cw *ztunnelDump.ConfigWriter) error {
+		return cw.PrintSecretDump(jsonOutput)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "spiffe://cluster.local/ns/default/sa/default") {
+		t.Fatalf("expected certificates in output:\n%s", out.String())
+	}
+}
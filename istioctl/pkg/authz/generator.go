@@ -0,0 +1,102 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/api/security/v1beta1"
+	typev1beta1 "istio.io/api/type/v1beta1"
+	clientsecurity "istio.io/client-go/pkg/apis/security/v1beta1"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/spiffe"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// unknown is the value of the telemetry labels that could not be determined.
+const unknown = "unknown"
+
+// ObservedTraffic is the traffic observed from a source to a destination workload.
+type ObservedTraffic struct {
+	// SourcePrincipal is the SPIFFE identity of the source, e.g. spiffe://cluster.local/ns/foo/sa/bar.
+	SourcePrincipal string
+	// DestinationApp is the app label of the destination workload.
+	DestinationApp string
+}
+
+// GeneratePolicies returns the least-privilege ALLOW AuthorizationPolicies in the namespace matching the
+// observed traffic: one policy per destination app, allowing the principals of the observed sources. Unless
+// enforce is set, the policies are generated in dry-run mode, so their decisions are only logged. The returned
+// warnings report the traffic which can not be expressed as an ALLOW rule.
+func GeneratePolicies(namespace string, traffic []ObservedTraffic, enforce bool) ([]*clientsecurity.AuthorizationPolicy, []string) {
+	principals := map[string]sets.String{}
+	warnings := sets.New[string]()
+	for _, t := range traffic {
+		if t.DestinationApp == "" || t.DestinationApp == unknown {
+			warnings.Insert("ignoring traffic to workloads without app label")
+			continue
+		}
+		if principals[t.DestinationApp] == nil {
+			principals[t.DestinationApp] = sets.New[string]()
+		}
+		if !strings.HasPrefix(t.SourcePrincipal, spiffe.URIPrefix) {
+			warnings.Insert(fmt.Sprintf("ignoring plaintext traffic to %s, which can not be allowed by principal", t.DestinationApp))
+			continue
+		}
+		principals[t.DestinationApp].Insert(strings.TrimPrefix(t.SourcePrincipal, spiffe.URIPrefix))
+	}
+
+	apps := make([]string, 0, len(principals))
+	for app := range principals {
+		apps = append(apps, app)
+	}
+	sort.Strings(apps)
+
+	policies := make([]*clientsecurity.AuthorizationPolicy, 0, len(apps))
+	for _, app := range apps {
+		if principals[app].IsEmpty() {
+			continue
+		}
+		policy := &clientsecurity.AuthorizationPolicy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gvk.AuthorizationPolicy.GroupVersion(),
+				Kind:       gvk.AuthorizationPolicy.Kind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      app + "-observed",
+				Namespace: namespace,
+			},
+			Spec: v1beta1.AuthorizationPolicy{
+				Selector: &typev1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": app}},
+				Action:   v1beta1.AuthorizationPolicy_ALLOW,
+				Rules: []*v1beta1.Rule{{
+					From: []*v1beta1.Rule_From{{
+						Source: &v1beta1.Source{Principals: sets.SortedList(principals[app])},
+					}},
+				}},
+			},
+		}
+		if !enforce {
+			policy.Annotations = map[string]string{annotation.IoIstioDryRun.Name: "true"}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, sets.SortedList(warnings)
+}
@@ -81,6 +81,7 @@ var (
 	pkcs8KeysEnv = env.Register("PKCS8_KEY", false,
 		"Whether to generate PKCS#8 private keys").Get()
 	eccSigAlgEnv        = env.Register("ECC_SIGNATURE_ALGORITHM", "", "The type of ECC signature algorithm to use when generating private keys").Get()
+	eccCurveEnv         = env.Register("ECC_CURVE", "P256", "The elliptic curve to use when generating EC private keys, either P256 or P384").Get()
 	fileMountedCertsEnv = env.Register("FILE_MOUNTED_CERTS", false, "").Get()
 	credFetcherTypeEnv  = env.Register("CREDENTIAL_FETCHER_TYPE", security.JWT,
 		"The type of the credential fetcher. Currently supported types include GoogleComputeEngine").Get()
@@ -120,6 +121,9 @@ var (
 	wasmHTTPRequestMaxRetries = env.Register("WASM_HTTP_REQUEST_MAX_RETRIES", wasm.DefaultHTTPRequestMaxRetries,
 		"maximum number of HTTP/HTTPS request retries for pulling a Wasm module via http/https").Get()
 
+	wasmHTTPRequireSHA256 = env.Register("WASM_HTTP_REQUIRE_SHA256", false,
+		"reject the Wasm modules pulled via http/https without a sha256 checksum, so their content is pinned").Get()
+
 	// Ability of istio-agent to retrieve bootstrap via XDS
 	enableBootstrapXdsEnv = env.Register("BOOTSTRAP_XDS_AGENT", false,
 		"If set to true, agent retrieves the bootstrap configuration prior to starting Envoy").Get()
@@ -140,6 +144,11 @@ var (
 	certSigner = env.Register("ISTIO_META_CERT_SIGNER", "",
 		"The cert signer info for workload cert")
 
+	// extraSANs are the additional SANs requested on the workload cert
+	extraSANs = env.Register("ISTIO_META_EXTRA_SANS", "",
+		"Comma separated DNS or URI SANs to request on the workload cert, in addition to its SPIFFE identity. "+
+			"They must be allowed by the security.istio.io/allowed-extra-sans annotation of the namespace")
+
 	istiodSAN = env.Register("ISTIOD_SAN", "",
 		"Override the ServerName used to validate Istiod certificate. "+
 			"Can be used as an alternative to setting /etc/hosts for VMs - discovery address will be an IP:port")
@@ -27,6 +27,7 @@ import (
 	"istio.io/istio/security/pkg/credentialfetcher"
 	"istio.io/istio/security/pkg/nodeagent/cafile"
 	"istio.io/istio/security/pkg/nodeagent/plugin/providers/google/stsclient"
+	pkiutil "istio.io/istio/security/pkg/pki/util"
 	"istio.io/istio/security/pkg/stsservice/tokenmanager"
 	"istio.io/pkg/log"
 )
@@ -47,15 +48,24 @@ func NewSecurityOptions(proxyConfig *meshconfig.ProxyConfig, stsPort int, tokenM
 		WorkloadRSAKeySize:             workloadRSAKeySizeEnv,
 		Pkcs8Keys:                      pkcs8KeysEnv,
 		ECCSigAlg:                      eccSigAlgEnv,
+		ECCCurve:                       eccCurveEnv,
 		SecretTTL:                      secretTTLEnv,
 		FileDebounceDuration:           fileDebounceDuration,
 		SecretRotationGracePeriodRatio: secretRotationGracePeriodRatioEnv,
 		STSPort:                        stsPort,
 		CertSigner:                     certSigner.Get(),
+		ExtraSANs:                      parseExtraSANs(extraSANs.Get()),
 		CARootPath:                     cafile.CACertFilePath,
 		CertChainFilePath:              security.DefaultCertChainFilePath,
 		KeyFilePath:                    security.DefaultKeyFilePath,
 		RootCertFilePath:               security.DefaultRootCertFilePath,
+		CRLFilePath:                    security.DefaultCRLFilePath,
+	}
+
+	if o.ECCSigAlg != "" {
+		if _, err := pkiutil.GetEllipticCurve(pkiutil.SupportedEllipticCurves(o.ECCCurve)); err != nil {
+			return o, fmt.Errorf("invalid ECC_CURVE: %v", err)
+		}
 	}
 
 	o, err := SetupSecurityOptions(proxyConfig, o, jwtPolicy.Get(),
@@ -141,3 +151,14 @@ func SetupSecurityOptions(proxyConfig *meshconfig.ProxyConfig, secOpt *security.
 	}
 	return o, nil
 }
+
+// parseExtraSANs returns the SANs of a comma separated list, ignoring empty entries.
+func parseExtraSANs(v string) []string {
+	var sans []string
+	for _, san := range strings.Split(v, ",") {
+		if san = strings.TrimSpace(san); san != "" {
+			sans = append(sans, san)
+		}
+	}
+	return sans
+}
@@ -37,7 +37,9 @@ import (
 	"istio.io/istio/pilot/pkg/model/status"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/workloadgroup"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/queue"
 	istiolog "istio.io/pkg/log"
@@ -359,7 +361,7 @@ func (c *Controller) unregisterWorkload(item any) error {
 			c.cleanupEntry(*wle)
 		}
 		return nil
-	}, features.WorkloadEntryCleanupGracePeriod)
+	}, c.cleanupPolicy(wle).GracePeriod)
 	return nil
 }
 
@@ -451,12 +453,13 @@ func (c *Controller) shouldCleanupEntry(wle config.Config) bool {
 	// 1. disconnect: the workload entry has been updated
 	// 2. connect: but the patch is based on the old workloadentry because of the propagation latency.
 	// So in this case the `DisconnectedAtAnnotation` is still there and the cleanup procedure will go on.
+	policy := c.cleanupPolicy(wle)
 	connTime := wle.Annotations[ConnectedAtAnnotation]
 	if connTime != "" {
 		// handle workload leak when both workload/pilot down at the same time before pilot has a chance to set disconnTime
 		connAt, err := time.Parse(timeFormat, connTime)
-		// if it has been 1.5*maxConnectionAge since workload connected, should delete it.
-		if err == nil && uint64(time.Since(connAt)) > uint64(c.maxConnectionAge)+uint64(c.maxConnectionAge/2) {
+		// if it has been maxConnectionAge+maxDisconnectDuration since workload connected, should delete it.
+		if err == nil && uint64(time.Since(connAt)) > uint64(c.maxConnectionAge)+uint64(policy.MaxDisconnectDuration) {
 			return true
 		}
 		return false
@@ -469,13 +472,35 @@ func (c *Controller) shouldCleanupEntry(wle config.Config) bool {
 
 	disconnAt, err := time.Parse(timeFormat, disconnTime)
 	// if we haven't passed the grace period, don't cleanup
-	if err == nil && time.Since(disconnAt) < features.WorkloadEntryCleanupGracePeriod {
+	if err == nil && time.Since(disconnAt) < policy.GracePeriod {
 		return false
 	}
 
 	return true
 }
 
+// cleanupPolicy returns the cleanup policy of the WorkloadGroup of the auto-registered WorkloadEntry, with the
+// unset durations defaulted.
+func (c *Controller) cleanupPolicy(wle config.Config) workloadgroup.CleanupPolicy {
+	var policy workloadgroup.CleanupPolicy
+	if group := c.store.Get(gvk.WorkloadGroup, wle.Annotations[AutoRegistrationGroupAnnotation], wle.Namespace); group != nil {
+		if v, f := group.Annotations[constants.WorkloadEntryCleanupAnnotation]; f {
+			var err error
+			if policy, err = workloadgroup.ParseCleanupPolicy(v); err != nil {
+				log.Warnf("ignoring invalid %s annotation of WorkloadGroup %s/%s: %v",
+					constants.WorkloadEntryCleanupAnnotation, group.Namespace, group.Name, err)
+			}
+		}
+	}
+	if policy.GracePeriod == 0 {
+		policy.GracePeriod = features.WorkloadEntryCleanupGracePeriod
+	}
+	if policy.MaxDisconnectDuration == 0 {
+		policy.MaxDisconnectDuration = c.maxConnectionAge / 2
+	}
+	return policy
+}
+
 func (c *Controller) cleanupEntry(wle config.Config) {
 	if err := c.cleanupLimit.Wait(context.TODO()); err != nil {
 		log.Errorf("error in WorkloadEntry cleanup rate limiter: %v", err)
@@ -33,6 +33,7 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/schema/collections"
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/keepalive"
@@ -210,6 +211,41 @@ func TestAutoregistrationLifecycle(t *testing.T) {
 	// TODO test garbage collection if pilot stops before disconnect meta is set (relies on heartbeat)
 }
 
+func TestCleanupPolicy(t *testing.T) {
+	c1, c2, store := setup(t)
+	c1.maxConnectionAge = time.Hour
+	wgB := wgA.DeepCopy()
+	wgB.Name = "wg-b"
+	wgB.Annotations = map[string]string{constants.WorkloadEntryCleanupAnnotation: `{"gracePeriod": "1h", "maxDisconnectDuration": "1m"}`}
+	createOrFail(t, store, wgB)
+	stop := test.NewStop(t)
+	go c1.Run(stop)
+	go c2.Run(stop)
+
+	entry := func(wg config.Config, annotations map[string]string) config.Config {
+		annotations[AutoRegistrationGroupAnnotation] = wg.Name
+		return config.Config{Meta: config.Meta{Name: "we", Namespace: wg.Namespace, Annotations: annotations}}
+	}
+	disconnected := map[string]string{DisconnectedAtAnnotation: time.Now().Add(-time.Minute).Format(timeFormat)}
+	// Connected to an istiod that stopped before recording the disconnection.
+	stale := map[string]string{ConnectedAtAnnotation: time.Now().Add(-time.Hour - 10*time.Minute).Format(timeFormat)}
+
+	// The grace period and max connection age of istiod apply by default.
+	assert.Equal(t, c1.shouldCleanupEntry(entry(wgA, disconnected)), true)
+	assert.Equal(t, c1.shouldCleanupEntry(entry(wgA, stale)), false)
+	// They are overridden by the cleanup policy of the WorkloadGroup.
+	assert.Equal(t, c1.shouldCleanupEntry(entry(wgB, disconnected)), false)
+	assert.Equal(t, c1.shouldCleanupEntry(entry(wgB, stale)), true)
+
+	// The entry of a disconnected workload is kept for the grace period of its WorkloadGroup.
+	p := fakeProxy("1.2.3.4", wgB, "nw1")
+	p.XdsNode = fakeNode("reg1", "zone1", "subzone1")
+	c1.RegisterWorkload(p, time.Now())
+	c1.QueueUnregisterWorkload(p, time.Now())
+	time.Sleep(2 * features.WorkloadEntryCleanupGracePeriod)
+	checkEntryOrFail(t, store, wgB, p, p.XdsNode, "")
+}
+
 func TestUpdateHealthCondition(t *testing.T) {
 	stop := test.NewStop(t)
 	ig, ig2, store := setup(t)
@@ -0,0 +1,176 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
+)
+
+// The types of the certificates whose expiry is monitored.
+const (
+	certTypeRoot         = "root"
+	certTypeIntermediate = "intermediate"
+	certTypeCACerts      = "cacerts"
+	certTypeWebhook      = "webhook"
+)
+
+var (
+	certTypeTag = monitoring.MustCreateLabel("type")
+
+	certExpiryDays = monitoring.NewGauge(
+		"istiod_cert_expiry_days_remaining",
+		"The number of days until the certificates used by istiod expire, by type: the root cert, the "+
+			"intermediate cert of the CA, the earliest expiring cert of the cacerts secret, and the istiod cert "+
+			"serving the webhooks. A negative value indicates the cert is expired.",
+		monitoring.WithLabels(certTypeTag),
+	)
+)
+
+func init() {
+	monitoring.MustRegister(certExpiryDays)
+}
+
+// certExpiryMonitor records the days remaining until the certificates used by istiod expire, and reports an
+// event when one of them expires within the warning threshold.
+type certExpiryMonitor struct {
+	certs     func() map[string][]byte
+	threshold time.Duration
+	recorder  record.EventRecorder
+	ref       *corev1.ObjectReference
+	now       func() time.Time
+
+	// warned holds the expiry of the cert of each type an event was reported for, so it is reported once per cert.
+	warned map[string]time.Time
+}
+
+func (m *certExpiryMonitor) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (m *certExpiryMonitor) check() {
+	for certType, pem := range m.certs() {
+		notAfter, ok := earliestExpiry(pem)
+		if !ok {
+			continue
+		}
+		remaining := notAfter.Sub(m.now())
+		certExpiryDays.With(certTypeTag.Value(certType)).Record(remaining.Hours() / 24)
+		if remaining > m.threshold || m.warned[certType].Equal(notAfter) {
+			continue
+		}
+		m.warned[certType] = notAfter
+		log.Warnf("istiod %s cert expires in %v, at %v", certType, remaining.Round(time.Minute), notAfter)
+		if m.recorder != nil && m.ref != nil {
+			m.recorder.Eventf(m.ref, corev1.EventTypeWarning, "CertificateExpiring",
+				"The %s cert expires in %v, at %v", certType, remaining.Round(time.Minute), notAfter.Format(time.RFC3339))
+		}
+	}
+}
+
+// earliestExpiry returns the earliest expiry of the PEM encoded certs.
+func earliestExpiry(pem []byte) (time.Time, bool) {
+	if len(pem) == 0 {
+		return time.Time{}, false
+	}
+	certs, _, err := util.ParsePemEncodedCertificateChain(pem)
+	if err != nil || len(certs) == 0 {
+		return time.Time{}, false
+	}
+	notAfter := certs[0].NotAfter
+	for _, c := range certs[1:] {
+		if c.NotAfter.Before(notAfter) {
+			notAfter = c.NotAfter
+		}
+	}
+	return notAfter, true
+}
+
+// monitoredCerts returns the PEM encoded certificates used by istiod, by type. The caCertFiles are the files
+// the cacerts secret is mounted at, when istiod uses plugged in certs.
+func (s *Server) monitoredCerts(caCertFiles []string) map[string][]byte {
+	certs := map[string][]byte{}
+	if s.CA != nil {
+		cert, _, _, root := s.CA.GetCAKeyCertBundle().GetAllPem()
+		certs[certTypeRoot] = root
+		if !bytes.Equal(cert, root) {
+			certs[certTypeIntermediate] = cert
+		}
+		var pem []byte
+		for _, f := range caCertFiles {
+			if b, err := os.ReadFile(f); err == nil {
+				pem = append(pem, b...)
+			}
+		}
+		certs[certTypeCACerts] = pem
+	} else if s.RA != nil {
+		certs[certTypeRoot] = s.RA.GetCAKeyCertBundle().GetRootCertPem()
+	}
+	certs[certTypeWebhook] = s.istiodCertBundleWatcher.GetKeyCertBundle().CertPem
+	return certs
+}
+
+// initCertExpiryMonitor monitors the expiry of the certificates used by istiod, reporting events on the istiod pod.
+func (s *Server) initCertExpiryMonitor(args *PilotArgs) {
+	var caCertFiles []string
+	if s.CA != nil {
+		if fileBundle, err := detectSigningCABundle(); err == nil {
+			caCertFiles = append([]string{fileBundle.SigningCertFile, fileBundle.RootCertFile}, fileBundle.CertChainFiles...)
+		}
+	}
+	m := &certExpiryMonitor{
+		certs:     func() map[string][]byte { return s.monitoredCerts(caCertFiles) },
+		threshold: features.CertExpiryWarningThreshold,
+		now:       time.Now,
+		warned:    map[string]time.Time{},
+	}
+	if s.kubeClient != nil && args.PodName != "" {
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: s.kubeClient.Kube().CoreV1().Events(args.Namespace)})
+		m.recorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "istiod"})
+		m.ref = &corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: args.Namespace, Name: args.PodName}
+		s.addStartFunc(func(stop <-chan struct{}) error {
+			go func() {
+				<-stop
+				broadcaster.Shutdown()
+			}()
+			return nil
+		})
+	}
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go m.run(features.CertExpiryCheckInterval, stop)
+		return nil
+	})
+}
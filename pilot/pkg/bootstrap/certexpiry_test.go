@@ -0,0 +1,82 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestCertExpiryMonitor(t *testing.T) {
+	now := time.Now()
+	cert := func(ttl time.Duration) []byte {
+		t.Helper()
+		pem, _, err := util.GenCertKeyFromOptions(util.CertOptions{
+			Host:         "istiod.istio-system.svc",
+			NotBefore:    now,
+			TTL:          ttl,
+			IsSelfSigned: true,
+			RSAKeySize:   2048,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem
+	}
+	root := cert(365 * 24 * time.Hour)
+	webhook := cert(10 * 24 * time.Hour)
+	certs := map[string][]byte{
+		certTypeRoot:    root,
+		certTypeCACerts: append(append([]byte{}, root...), webhook...),
+		certTypeWebhook: webhook,
+	}
+	recorder := record.NewFakeRecorder(10)
+	m := &certExpiryMonitor{
+		certs:     func() map[string][]byte { return certs },
+		threshold: 30 * 24 * time.Hour,
+		recorder:  recorder,
+		ref:       &corev1.ObjectReference{Kind: "Pod", Namespace: "istio-system", Name: "istiod"},
+		now:       func() time.Time { return now },
+		warned:    map[string]time.Time{},
+	}
+
+	m.check()
+	events := map[string]bool{}
+	for len(recorder.Events) > 0 {
+		events[strings.Fields(<-recorder.Events)[3]] = true
+	}
+	if len(events) != 2 || !events[certTypeWebhook] || !events[certTypeCACerts] {
+		t.Fatalf("expected events for the webhook and cacerts certs, got %v", events)
+	}
+
+	// The events are only reported once per cert.
+	m.check()
+	if len(recorder.Events) != 0 {
+		t.Fatalf("unexpected event %v", <-recorder.Events)
+	}
+
+	// A renewed cert which expires soon again is reported.
+	certs[certTypeWebhook] = cert(5 * 24 * time.Hour)
+	m.check()
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected an event for the renewed webhook cert, got %d", len(recorder.Events))
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"istio.io/istio/pilot/pkg/leaderelection"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/status/distribution"
+	"istio.io/istio/pilot/pkg/status/targetgateway"
 	"istio.io/istio/pkg/adsc"
 	"istio.io/istio/pkg/config/analysis/incluster"
 	"istio.io/istio/pkg/config/schema/collections"
@@ -148,9 +149,20 @@ func (s *Server) initK8SConfigStore(args *PilotArgs) error {
 			s.environment.CredentialsController, args.RegistryOptions.KubeOptions)
 		s.environment.GatewayAPIController = gwc
 		s.ConfigStores = append(s.ConfigStores, s.environment.GatewayAPIController)
+		var targetGatewayStatus *targetgateway.Controller
+		if features.EnableGatewayAPIStatus {
+			targetGatewayStatus = targetgateway.NewController(configController)
+		}
 		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
-			leaderelection.
-				NewLeaderElection(args.Namespace, args.PodName, leaderelection.GatewayStatusController, args.Revision, s.kubeClient).
+			le := leaderelection.
+				NewLeaderElection(args.Namespace, args.PodName, leaderelection.GatewayStatusController, args.Revision, s.kubeClient)
+			if targetGatewayStatus != nil {
+				le.AddRunFunction(func(leaderStop <-chan struct{}) {
+					log.Infof("Starting authorization policy target gateway status writer")
+					targetGatewayStatus.Run(leaderStop, s.statusManager)
+				})
+			}
+			le.
 				AddRunFunction(func(leaderStop <-chan struct{}) {
 					log.Infof("Starting gateway status writer")
 					gwc.SetStatusWrite(true, s.statusManager)
@@ -174,13 +186,21 @@ func (s *Server) initK8SConfigStore(args *PilotArgs) error {
 					AddRunFunction(func(leaderStop <-chan struct{}) {
 						// We can only run this if the Gateway CRD is created
 						if configController.WaitForCRD(gvk.KubernetesGateway, leaderStop) {
-							controller := gateway.NewDeploymentController(s.kubeClient, s.clusterID, s.webhookInfo.getWebhookConfig, s.webhookInfo.addHandler)
+							controller := gateway.NewDeploymentController(s.kubeClient, s.clusterID, args.Namespace, s.webhookInfo.getWebhookConfig, s.webhookInfo.addHandler)
+							s.XDSServer.RegisterController(controller)
+							var eastWest *gateway.EastWestGatewayController
+							if features.EnableEastWestGatewayProvisioning {
+								eastWest = gateway.NewEastWestGatewayController(s.kubeClient, s.clusterID, args.Namespace)
+							}
 							// Start informers again. This fixes the case where informers for namespace do not start,
 							// as we create them only after acquiring the leader lock
 							// Note: stop here should be the overall pilot stop, NOT the leader election stop. We are
 							// basically lazy loading the informer, if we stop it when we lose the lock we will never
 							// recreate it again.
 							s.kubeClient.RunAndWait(stop)
+							if eastWest != nil {
+								go eastWest.Run(leaderStop)
+							}
 							controller.Run(leaderStop)
 						}
 					}).
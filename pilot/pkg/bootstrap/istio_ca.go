@@ -17,7 +17,9 @@ package bootstrap
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"path"
@@ -118,6 +120,28 @@ var (
 			"Jitter selects a backoff time in seconds to start root cert rotator, "+
 			"and the back off time is below root cert check interval.")
 
+	selfSignedRootKeyRollover = env.Register("CITADEL_SELF_SIGNED_ROOT_KEY_ROLLOVER",
+		false,
+		"If true, the self-signed CA rolls over to a new root key when its root certificate is about to expire, "+
+			"instead of re-signing the root certificate with the existing key. The new root is distributed "+
+			"in the trust bundle before it is used for signing, and the old root is kept until it is retired.")
+
+	selfSignedRootKeyRolloverPhaseDuration = env.Register("CITADEL_SELF_SIGNED_ROOT_KEY_ROLLOVER_PHASE_DURATION",
+		cmd.DefaultRootKeyRolloverPhaseDuration,
+		"The minimum duration of each phase of a self-signed root key rollover. It should be longer than "+
+			"the time needed to distribute the trust bundle, and than the max TTL of issued workload certificates.")
+
+	selfSignedIntermediateCA = env.Register("CITADEL_SELF_SIGNED_INTERMEDIATE_CA",
+		false,
+		"If true, the self-signed root only signs an intermediate certificate, which signs the workload certificates. "+
+			"The intermediate is re-issued before it expires, and by the new root during a root key rollover. "+
+			"It requires CITADEL_SELF_SIGNED_ROOT_KEY_ROLLOVER.")
+
+	selfSignedIntermediateCertTTL = env.Register("CITADEL_SELF_SIGNED_INTERMEDIATE_CERT_TTL",
+		cmd.DefaultIntermediateCertTTL,
+		"The TTL of the intermediate certificates issued by the self-signed root. It should be longer than "+
+			"the max TTL of issued workload certificates.")
+
 	k8sInCluster = env.Register("KUBERNETES_SERVICE_HOST", "",
 		"Kubernetes service host, set automatically when running in-cluster")
 
@@ -133,8 +157,8 @@ var (
 
 	// TODO: Likely to be removed and added to mesh config
 	externalCaType = env.Register("EXTERNAL_CA", "",
-		"External CA Integration Type. Permitted Values are ISTIOD_RA_KUBERNETES_API or "+
-			"ISTIOD_RA_ISTIO_API").Get()
+		"External CA Integration Type, selecting the backend signing the workload certificates. The "+
+			"ISTIOD_RA_KUBERNETES_API backend is built in; other backends can be registered by builds of istiod.").Get()
 
 	// TODO: Likely to be removed and added to mesh config
 	k8sSigner = env.Register("K8S_SIGNER", "",
@@ -302,6 +326,8 @@ func (s *Server) loadCACerts(caOpts *caOptions, dir string) error {
 func handleEvent(s *Server) {
 	log.Info("Update Istiod cacerts")
 
+	s.loadPluggedinCRL()
+
 	var newCABundle []byte
 	var err error
 
@@ -345,6 +371,30 @@ func handleEvent(s *Server) {
 	log.Info("Istiod has detected the newly added intermediate CA and updated its key and certs accordingly")
 }
 
+// loadPluggedinCRL loads the certificate revocation list of the plugged-in CA, if present in cacerts, so
+// that it is distributed to workloads along with the root certificate.
+func (s *Server) loadPluggedinCRL() {
+	crlFile := path.Join(LocalCertDir.Get(), ca.CACRLFile)
+	crl, err := os.ReadFile(crlFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("failed reading %s: %v", crlFile, err)
+		return
+	}
+	if len(crl) > 0 {
+		block, _ := pem.Decode(crl)
+		if block == nil {
+			log.Errorf("failed decoding %s: no PEM data found", crlFile)
+			return
+		}
+		if _, err := x509.ParseRevocationList(block.Bytes); err != nil {
+			log.Errorf("failed parsing %s: %v", crlFile, err)
+			return
+		}
+		log.Infof("Loaded certificate revocation list from %s", crlFile)
+	}
+	s.istiodCertBundleWatcher.SetCRLAndNotify(crl)
+}
+
 // handleCACertsFileWatch handles the events on cacerts files
 func (s *Server) handleCACertsFileWatch() {
 	var timerC <-chan time.Time
@@ -444,6 +494,16 @@ func (s *Server) createIstioCA(opts *caOptions) (*ca.IstioCA, error) {
 				maxWorkloadCertTTL.Get(), opts.TrustDomain, true,
 				opts.Namespace, s.kubeClient.Kube().CoreV1(), fileBundle.RootCertFile,
 				enableJitterForRootCertRotator.Get(), caRSAKeySize.Get())
+			if err == nil {
+				caOpts.RotatorConfig.RootKeyRollover = selfSignedRootKeyRollover.Get()
+				caOpts.RotatorConfig.RolloverPhaseDuration = selfSignedRootKeyRolloverPhaseDuration.Get()
+				if selfSignedIntermediateCA.Get() && !selfSignedRootKeyRollover.Get() {
+					log.Warnf("ignoring CITADEL_SELF_SIGNED_INTERMEDIATE_CA, it requires CITADEL_SELF_SIGNED_ROOT_KEY_ROLLOVER")
+				} else {
+					caOpts.RotatorConfig.IntermediateCA = selfSignedIntermediateCA.Get()
+				}
+				caOpts.RotatorConfig.IntermediateCertTTL = selfSignedIntermediateCertTTL.Get()
+			}
 		} else {
 			log.Warnf(
 				"Use local self-signed CA certificate for testing. Will use in-memory root CA, no K8S access and no ca key file %s",
@@ -463,6 +523,7 @@ func (s *Server) createIstioCA(opts *caOptions) (*ca.IstioCA, error) {
 			return nil, fmt.Errorf("failed to create an istiod CA: %v", err)
 		}
 
+		s.loadPluggedinCRL()
 		if features.AutoReloadPluginCerts {
 			s.initCACertsWatcher()
 		}
@@ -509,9 +570,6 @@ func (s *Server) createIstioRA(opts *caOptions) (ra.RegistrationAuthority, error
 		}
 	}
 
-	if s.kubeClient == nil {
-		return nil, fmt.Errorf("kubeClient is nil")
-	}
 	raOpts := &ra.IstioRAOptions{
 		ExternalCAType:   opts.ExternalCAType,
 		DefaultCertTTL:   workloadCertTTL.Get(),
@@ -519,10 +577,12 @@ func (s *Server) createIstioRA(opts *caOptions) (ra.RegistrationAuthority, error
 		CaSigner:         opts.ExternalCASigner,
 		CaCertFile:       caCertFile,
 		VerifyAppendCA:   true,
-		K8sClient:        s.kubeClient.Kube(),
 		TrustDomain:      opts.TrustDomain,
 		CertSignerDomain: opts.CertSignerDomain,
 	}
+	if s.kubeClient != nil {
+		raOpts.K8sClient = s.kubeClient.Kube()
+	}
 	raServer, err := ra.NewIstioRA(raOpts)
 	if err != nil {
 		return nil, err
@@ -16,9 +16,12 @@ package bootstrap
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"time"
 
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/mesh/kubemesh"
 	"istio.io/pkg/filewatcher"
@@ -96,6 +99,42 @@ func (s *Server) initMeshConfiguration(args *PilotArgs, fileWatcher filewatcher.
 	}
 }
 
+// initTrustDomainMigration sets up the trust domain migration window of PILOT_TRUST_DOMAIN_MIGRATION. The config is
+// pushed to the proxies when the window ends, so they stop accepting the identities of the previous trust domain.
+// An invalid config fails the startup, rather than silently ending the migration.
+func (s *Server) initTrustDomainMigration() error {
+	m, err := model.ParseTrustDomainMigration(features.TrustDomainMigration)
+	if err != nil {
+		return fmt.Errorf("invalid PILOT_TRUST_DOMAIN_MIGRATION: %v", err)
+	}
+	if m == nil {
+		return nil
+	}
+	if !m.Active(time.Now()) {
+		log.Infof("trust domain migration from %s ended at %v", m.From, m.Until)
+		return nil
+	}
+	s.environment.TrustDomainMigration = m
+	log.Infof("migrating trust domain from %s to %s until %v", m.From, s.environment.Mesh().GetTrustDomain(), m.Until)
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go func() {
+			timer := time.NewTimer(time.Until(m.Until))
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				log.Infof("trust domain migration from %s ended", m.From)
+				s.XDSServer.ConfigUpdate(&model.PushRequest{
+					Full:   true,
+					Reason: []model.TriggerReason{model.GlobalUpdate},
+				})
+			case <-stop:
+			}
+		}()
+		return nil
+	})
+	return nil
+}
+
 // initMeshNetworks loads the mesh networks configuration from the file provided
 // in the args and add a watcher for changes in this file.
 func (s *Server) initMeshNetworks(args *PilotArgs, fileWatcher filewatcher.FileWatcher) {
@@ -26,6 +26,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -38,10 +39,12 @@ import (
 	"k8s.io/client-go/rest"
 
 	"istio.io/api/security/v1beta1"
+	"istio.io/istio/pilot/pkg/config/kube/drift"
 	kubecredentials "istio.io/istio/pilot/pkg/credentials/kube"
 	"istio.io/istio/pilot/pkg/features"
 	istiogrpc "istio.io/istio/pilot/pkg/grpc"
 	"istio.io/istio/pilot/pkg/keycertbundle"
+	"istio.io/istio/pilot/pkg/leaderelection"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/server"
 	"istio.io/istio/pilot/pkg/serviceregistry/aggregate"
@@ -149,6 +152,13 @@ type Server struct {
 	CA *ca.IstioCA
 	RA ra.RegistrationAuthority
 
+	// spireTrustBundle holds the roots last published by SPIRE, trusted for client certificates.
+	spireTrustBundle atomic.Pointer[[]byte]
+	// peerCertVerifier verifies the client certificates of the secure discovery service. It is rebuilt from
+	// peerCertTLSOptions when the SPIRE roots change.
+	peerCertVerifier   atomic.Pointer[spiffe.PeerCertVerifier]
+	peerCertTLSOptions *TLSOptions
+
 	// TrustAnchors for workload to workload mTLS
 	workloadTrustBundle     *tb.TrustBundle
 	certMu                  sync.RWMutex
@@ -261,6 +271,9 @@ func NewServer(args *PilotArgs, initFuncs ...func(*Server)) (*Server, error) {
 	if err := s.initKubeClient(args); err != nil {
 		return nil, fmt.Errorf("error initializing kube client: %v", err)
 	}
+	if err := s.initZtunnelAdminClient(); err != nil {
+		return nil, fmt.Errorf("error initializing ztunnel admin client: %v", err)
+	}
 
 	// used for both initKubeRegistry and initClusterRegistries
 	args.RegistryOptions.KubeOptions.EndpointMode = kubecontroller.DetectEndpointMode(s.kubeClient)
@@ -270,6 +283,12 @@ func NewServer(args *PilotArgs, initFuncs ...func(*Server)) (*Server, error) {
 
 	s.initMeshNetworks(args, s.fileWatcher)
 	s.initMeshHandlers()
+	if err := s.initTrustDomainMigration(); err != nil {
+		return nil, err
+	}
+	if err := validateProviderSettings(); err != nil {
+		return nil, err
+	}
 	s.environment.Init()
 	if err := s.environment.InitNetworksManager(s.XDSServer); err != nil {
 		return nil, err
@@ -372,6 +391,7 @@ func NewServer(args *PilotArgs, initFuncs ...func(*Server)) (*Server, error) {
 
 	// Start CA or RA server. This should be called after CA and Istiod certs have been created.
 	s.startCA(caOpts)
+	s.initCertExpiryMonitor(args)
 
 	// TODO: don't run this if galley is started, one ctlz is enough
 	if args.CtrlZOptions != nil {
@@ -524,6 +544,20 @@ func (s *Server) initSDSServer() {
 	}
 }
 
+// initZtunnelAdminClient creates the client reaching the admin interface of the ztunnels for /debug/ztunnelz. The
+// interface only listens on localhost, so it is reached through port forwards.
+func (s *Server) initZtunnelAdminClient() error {
+	if s.kubeClient == nil || !features.EnableAmbientControllers {
+		return nil
+	}
+	client, err := kubelib.NewCLIClient(kubelib.NewClientConfigForRestConfig(s.kubeClient.RESTConfig()), "")
+	if err != nil {
+		return err
+	}
+	s.XDSServer.ZtunnelAdminClient = client
+	return nil
+}
+
 // initKubeClient creates the k8s client if running in a k8s environment.
 // This is determined by the presence of a kube registry, which
 // uses in-context k8s, or a config source of type k8s.
@@ -559,6 +593,7 @@ func (s *Server) initKubeClient(args *PilotArgs) error {
 		kubeRestConfig, err := kubelib.DefaultRestConfig(args.RegistryOptions.KubeConfig, "", func(config *rest.Config) {
 			config.QPS = args.RegistryOptions.KubeOptions.KubernetesAPIQPS
 			config.Burst = args.RegistryOptions.KubeOptions.KubernetesAPIBurst
+			kubelib.SetThrottlingRetries(config, features.KubeClientMaxThrottledRetries)
 		})
 		if err != nil {
 			return fmt.Errorf("failed creating kube config: %v", err)
@@ -757,12 +792,14 @@ func (s *Server) initSecureDiscoveryService(args *PilotArgs) error {
 		return nil
 	}
 	log.Info("initializing secure discovery service")
+	s.peerCertVerifier.Store(peerCertVerifier)
+	s.peerCertTLSOptions = &args.ServerOptions.TLSOptions
 	cfg := &tls.Config{
 		GetCertificate: s.getIstiodCertificate,
 		ClientAuth:     tls.VerifyClientCertIfGiven,
 		ClientCAs:      peerCertVerifier.GetGeneralCertPool(),
 		VerifyPeerCertificate: func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			err := peerCertVerifier.VerifyPeerCert(rawCerts, verifiedChains)
+			err := s.peerCertVerifier.Load().VerifyPeerCert(rawCerts, verifiedChains)
 			if err != nil {
 				log.Infof("Could not verify certificate: %v", err)
 			}
@@ -770,6 +807,15 @@ func (s *Server) initSecureDiscoveryService(args *PilotArgs) error {
 		},
 		MinVersion:   tls.VersionTLS12,
 		CipherSuites: args.ServerOptions.TLSOptions.CipherSuits,
+		// The HTTP/2 ALPN is otherwise only added by the gRPC credentials to their own copy of the config.
+		NextProtos: []string{"h2"},
+	}
+	// The verifier changes with the SPIRE roots, so the client CAs are looked up for each handshake.
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := cfg.Clone()
+		c.GetConfigForClient = nil
+		c.ClientCAs = s.peerCertVerifier.Load().GetGeneralCertPool()
+		return c, nil
 	}
 
 	tlsCreds := credentials.NewTLS(cfg)
@@ -1038,7 +1084,8 @@ func getDNSNames(args *PilotArgs, host string) []string {
 // createPeerCertVerifier creates a SPIFFE certificate verifier with the current istiod configuration.
 func (s *Server) createPeerCertVerifier(tlsOptions TLSOptions) (*spiffe.PeerCertVerifier, error) {
 	customTLSCertsExists, _, _, caCertPath := hasCustomTLSCerts(tlsOptions)
-	if !customTLSCertsExists && s.CA == nil && features.SpiffeBundleEndpoints == "" && !s.isCADisabled() {
+	if !customTLSCertsExists && s.CA == nil && features.SpiffeBundleEndpoints == "" &&
+		features.SpireTrustBundleConfigMap == "" && !s.isCADisabled() {
 		// Running locally without configured certs - no TLS mode
 		return nil, nil
 	}
@@ -1064,6 +1111,10 @@ func (s *Server) createPeerCertVerifier(tlsOptions TLSOptions) (*spiffe.PeerCert
 		}
 	}
 
+	if spireBundle := s.spireTrustBundle.Load(); spireBundle != nil {
+		rootCertBytes = append(rootCertBytes, *spireBundle...)
+	}
+
 	if len(rootCertBytes) != 0 {
 		err := peerCertVerifier.AddMappingFromPEM(spiffe.GetTrustDomain(), rootCertBytes)
 		if err != nil {
@@ -1160,9 +1211,30 @@ func (s *Server) initMulticluster(args *PilotArgs) {
 	}
 	s.multiclusterController = multicluster.NewController(s.kubeClient, args.Namespace, s.clusterID, s.environment.Watcher)
 	s.XDSServer.ListRemoteClusters = s.multiclusterController.ListRemoteClusters
+	if features.EnableConfigDriftDetection {
+		drifts := drift.NewController(s.clusterID)
+		s.multiclusterController.AddHandler(drifts)
+		s.XDSServer.ConfigDrifts = drifts
+		s.addStartFunc(func(stop <-chan struct{}) error {
+			go drifts.Run(stop)
+			return nil
+		})
+	}
 	s.addStartFunc(func(stop <-chan struct{}) error {
 		return s.multiclusterController.Run(stop)
 	})
+	if features.EnableClusterRegistrations {
+		s.addStartFunc(func(stop <-chan struct{}) error {
+			go leaderelection.
+				NewLeaderElection(args.Namespace, args.PodName, leaderelection.ClusterRegistrationController, args.Revision, s.kubeClient).
+				AddRunFunction(func(leaderStop <-chan struct{}) {
+					log.Infof("Starting cluster registration status writer")
+					s.multiclusterController.RunRegistrationStatus(leaderStop)
+				}).
+				Run(stop)
+			return nil
+		})
+	}
 }
 
 // maybeCreateCA creates and initializes CA Key if needed.
@@ -1269,6 +1341,10 @@ func (s *Server) initWorkloadTrustBundle(args *PilotArgs) error {
 	var err error
 
 	if !features.MultiRootMesh {
+		if features.SpireTrustBundleConfigMap != "" {
+			log.Warnf("SPIRE_TRUST_BUNDLE_CONFIGMAP is set but ISTIO_MULTIROOT_MESH is disabled, " +
+				"SPIRE roots will not be distributed to proxies")
+		}
 		return nil
 	}
 
@@ -1301,6 +1377,11 @@ func (s *Server) initWorkloadTrustBundle(args *PilotArgs) error {
 		return err
 	}
 
+	// SPIRE: Add roots published by the SPIRE server
+	if err = s.initSpireTrustBundle(); err != nil {
+		return err
+	}
+
 	// IstioRA: Explicitly add roots corresponding to RA
 	if s.RA != nil {
 		// Implicitly add the Istio RA certificates to the Workload Trust Bundle
@@ -346,6 +346,7 @@ func TestNewServer(t *testing.T) {
 		expectedDomain   string
 		enableSecureGRPC bool
 		jwtRule          string
+		kubeConfigStore  bool
 	}{
 		{
 			name:           "default domain",
@@ -369,6 +370,12 @@ func TestNewServer(t *testing.T) {
 			expectedDomain:   constants.DefaultClusterLocalDomain,
 			enableSecureGRPC: true,
 		},
+		{
+			name:            "kube config store with gateway status",
+			domain:          "",
+			expectedDomain:  constants.DefaultClusterLocalDomain,
+			kubeConfigStore: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -401,6 +408,10 @@ func TestNewServer(t *testing.T) {
 					FileDir: configDir,
 				}
 
+				if c.kubeConfigStore {
+					p.RegistryOptions.FileDir = ""
+				}
+
 				p.ShutdownDuration = 1 * time.Millisecond
 
 				p.JwtRule = c.jwtRule
@@ -17,6 +17,8 @@ package bootstrap
 import (
 	"fmt"
 
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/leaderelection"
 	"istio.io/istio/pilot/pkg/serviceregistry/aggregate"
 	kubecontroller "istio.io/istio/pilot/pkg/serviceregistry/kube/controller"
 	"istio.io/istio/pilot/pkg/serviceregistry/provider"
@@ -32,11 +34,24 @@ func (s *Server) ServiceController() *aggregate.Controller {
 func (s *Server) initServiceControllers(args *PilotArgs) error {
 	serviceControllers := s.ServiceController()
 
-	s.serviceEntryController = serviceentry.NewController(
-		s.configController, s.XDSServer,
-		serviceentry.WithClusterID(s.clusterID),
-	)
+	options := []serviceentry.Option{serviceentry.WithClusterID(s.clusterID)}
+	if features.PersistAutoAllocatedIPs && s.kubeClient != nil {
+		options = append(options, serviceentry.WithIPAllocationTable(s.kubeClient, args.Namespace, s.configController.HasSynced))
+	}
+	s.serviceEntryController = serviceentry.NewController(s.configController, s.XDSServer, options...)
 	serviceControllers.AddRegistry(s.serviceEntryController)
+	if features.PersistAutoAllocatedIPs && s.kubeClient != nil {
+		s.addStartFunc(func(stop <-chan struct{}) error {
+			go leaderelection.
+				NewLeaderElection(args.Namespace, args.PodName, leaderelection.IPAllocationController, args.Revision, s.kubeClient).
+				AddRunFunction(func(leaderStop <-chan struct{}) {
+					log.Infof("Starting ServiceEntry ip allocations writer")
+					s.serviceEntryController.RunIPAllocationsWriter(leaderStop)
+				}).
+				Run(stop)
+			return nil
+		})
+	}
 
 	registered := make(map[provider.ID]bool)
 	for _, r := range args.RegistryOptions.Registries {
@@ -0,0 +1,42 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"fmt"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/security/authz/builder"
+	dnsServer "istio.io/istio/pkg/dns/server"
+)
+
+// validateProviderSettings checks the settings standing in for the fields the mesh config does not have yet, so an
+// invalid value fails the startup of istiod rather than being ignored by the components reading them.
+func validateProviderSettings() error {
+	if _, err := model.ParseJwksIssuers(features.JwksIssuers); err != nil {
+		return fmt.Errorf("invalid PILOT_JWKS_ISSUERS: %v", err)
+	}
+	if err := builder.ValidateExtAuthzProviderSettings(features.ExtAuthzProviderSettings); err != nil {
+		return fmt.Errorf("invalid PILOT_EXT_AUTHZ_PROVIDER_SETTINGS: %v", err)
+	}
+	if err := model.ValidateOtelAccessLogProviderSettings(features.OtelAccessLogProviderSettings); err != nil {
+		return fmt.Errorf("invalid PILOT_OTEL_ACCESS_LOG_PROVIDER_SETTINGS: %v", err)
+	}
+	if _, err := dnsServer.ParseForwardingRules(features.DNSForwardingRules); err != nil {
+		return fmt.Errorf("invalid PILOT_DNS_FORWARDING_RULES: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/kube/watcher/configmapwatcher"
+	"istio.io/pkg/log"
+)
+
+// spireBundleKey is the ConfigMap key the SPIRE k8sbundle notifier writes the trust bundle to.
+const spireBundleKey = "bundle.crt"
+
+// spireTrustBundleConfigMap returns the namespace and name of the ConfigMap holding the SPIRE trust bundle.
+func spireTrustBundleConfigMap() (string, string, error) {
+	ns, name, ok := strings.Cut(features.SpireTrustBundleConfigMap, "/")
+	if !ok || ns == "" || name == "" {
+		return "", "", fmt.Errorf("invalid SPIRE_TRUST_BUNDLE_CONFIGMAP %q, expected <namespace>/<name>",
+			features.SpireTrustBundleConfigMap)
+	}
+	return ns, name, nil
+}
+
+// initSpireTrustBundle keeps the workload trust bundle in sync with the roots published by SPIRE.
+func (s *Server) initSpireTrustBundle() error {
+	if features.SpireTrustBundleConfigMap == "" {
+		return nil
+	}
+	if s.kubeClient == nil {
+		log.Warnf("SPIRE_TRUST_BUNDLE_CONFIGMAP is set but there is no Kubernetes client, ignoring")
+		return nil
+	}
+	ns, name, err := spireTrustBundleConfigMap()
+	if err != nil {
+		return err
+	}
+	c := configmapwatcher.NewController(s.kubeClient, ns, name, func(cm *v1.ConfigMap) {
+		var bundle []byte
+		if cm != nil {
+			bundle = []byte(cm.Data[spireBundleKey])
+		}
+		// Keep the last known roots in case the bundle is malformed.
+		if err := s.workloadTrustBundle.UpdateSpireTrustBundle(bundle); err != nil {
+			return
+		}
+		s.spireTrustBundle.Store(&bundle)
+		s.refreshPeerCertVerifier()
+	})
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go c.Run(stop)
+		return nil
+	})
+	return nil
+}
+
+// refreshPeerCertVerifier rebuilds the verifier of the secure discovery service, so that it trusts the current SPIRE
+// roots. Until the SPIRE ConfigMap is first read, client certificates issued by SPIRE are rejected.
+func (s *Server) refreshPeerCertVerifier() {
+	if s.peerCertTLSOptions == nil {
+		return
+	}
+	v, err := s.createPeerCertVerifier(*s.peerCertTLSOptions)
+	if err != nil {
+		log.Errorf("failed to refresh the peer certificate verifier with the SPIRE trust bundle: %v", err)
+		return
+	}
+	if v != nil {
+		s.peerCertVerifier.Store(v)
+	}
+}
@@ -17,10 +17,12 @@ import (
 
 	k8sioapiadmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	k8sioapiappsv1 "k8s.io/api/apps/v1"
+	k8sioapiautoscalingv2 "k8s.io/api/autoscaling/v2"
 	k8sioapicertificatesv1 "k8s.io/api/certificates/v1"
 	k8sioapicorev1 "k8s.io/api/core/v1"
 	k8sioapidiscoveryv1 "k8s.io/api/discovery/v1"
 	k8sioapinetworkingv1 "k8s.io/api/networking/v1"
+	k8sioapipolicyv1 "k8s.io/api/policy/v1"
 	k8sioapiextensionsapiserverpkgapisapiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	sigsk8siogatewayapiapisv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	sigsk8siogatewayapiapisv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -854,6 +856,24 @@ var translationMap = map[config.GroupVersionKind]func(r runtime.Object) config.C
 			Spec: &obj.Spec,
 		}
 	},
+	gvk.DaemonSet: func(r runtime.Object) config.Config {
+		obj := r.(*k8sioapiappsv1.DaemonSet)
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind:  gvk.DaemonSet,
+				Name:              obj.Name,
+				Namespace:         obj.Namespace,
+				Labels:            obj.Labels,
+				Annotations:       obj.Annotations,
+				ResourceVersion:   obj.ResourceVersion,
+				CreationTimestamp: obj.CreationTimestamp.Time,
+				OwnerReferences:   obj.OwnerReferences,
+				UID:               string(obj.UID),
+				Generation:        obj.Generation,
+			},
+			Spec: &obj.Spec,
+		}
+	},
 	gvk.Deployment: func(r runtime.Object) config.Config {
 		obj := r.(*k8sioapiappsv1.Deployment)
 		return config.Config{
@@ -1022,6 +1042,24 @@ var translationMap = map[config.GroupVersionKind]func(r runtime.Object) config.C
 			Status: &obj.Status,
 		}
 	},
+	gvk.HorizontalPodAutoscaler: func(r runtime.Object) config.Config {
+		obj := r.(*k8sioapiautoscalingv2.HorizontalPodAutoscaler)
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind:  gvk.HorizontalPodAutoscaler,
+				Name:              obj.Name,
+				Namespace:         obj.Namespace,
+				Labels:            obj.Labels,
+				Annotations:       obj.Annotations,
+				ResourceVersion:   obj.ResourceVersion,
+				CreationTimestamp: obj.CreationTimestamp.Time,
+				OwnerReferences:   obj.OwnerReferences,
+				UID:               string(obj.UID),
+				Generation:        obj.Generation,
+			},
+			Spec: &obj.Spec,
+		}
+	},
 	gvk.Ingress: func(r runtime.Object) config.Config {
 		obj := r.(*k8sioapinetworkingv1.Ingress)
 		return config.Config{
@@ -1114,6 +1152,24 @@ var translationMap = map[config.GroupVersionKind]func(r runtime.Object) config.C
 			Spec: &obj.Spec,
 		}
 	},
+	gvk.NetworkPolicy: func(r runtime.Object) config.Config {
+		obj := r.(*k8sioapinetworkingv1.NetworkPolicy)
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind:  gvk.NetworkPolicy,
+				Name:              obj.Name,
+				Namespace:         obj.Namespace,
+				Labels:            obj.Labels,
+				Annotations:       obj.Annotations,
+				ResourceVersion:   obj.ResourceVersion,
+				CreationTimestamp: obj.CreationTimestamp.Time,
+				OwnerReferences:   obj.OwnerReferences,
+				UID:               string(obj.UID),
+				Generation:        obj.Generation,
+			},
+			Spec: &obj.Spec,
+		}
+	},
 	gvk.Node: func(r runtime.Object) config.Config {
 		obj := r.(*k8sioapicorev1.Node)
 		return config.Config{
@@ -1169,6 +1225,24 @@ var translationMap = map[config.GroupVersionKind]func(r runtime.Object) config.C
 			Spec: &obj.Spec,
 		}
 	},
+	gvk.PodDisruptionBudget: func(r runtime.Object) config.Config {
+		obj := r.(*k8sioapipolicyv1.PodDisruptionBudget)
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind:  gvk.PodDisruptionBudget,
+				Name:              obj.Name,
+				Namespace:         obj.Namespace,
+				Labels:            obj.Labels,
+				Annotations:       obj.Annotations,
+				ResourceVersion:   obj.ResourceVersion,
+				CreationTimestamp: obj.CreationTimestamp.Time,
+				OwnerReferences:   obj.OwnerReferences,
+				UID:               string(obj.UID),
+				Generation:        obj.Generation,
+			},
+			Spec: &obj.Spec,
+		}
+	},
 	gvk.ProxyConfig: func(r runtime.Object) config.Config {
 		obj := r.(*apiistioioapinetworkingv1beta1.ProxyConfig)
 		return config.Config{
@@ -1317,6 +1391,24 @@ var translationMap = map[config.GroupVersionKind]func(r runtime.Object) config.C
 			Status: &obj.Status,
 		}
 	},
+	gvk.StatefulSet: func(r runtime.Object) config.Config {
+		obj := r.(*k8sioapiappsv1.StatefulSet)
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind:  gvk.StatefulSet,
+				Name:              obj.Name,
+				Namespace:         obj.Namespace,
+				Labels:            obj.Labels,
+				Annotations:       obj.Annotations,
+				ResourceVersion:   obj.ResourceVersion,
+				CreationTimestamp: obj.CreationTimestamp.Time,
+				OwnerReferences:   obj.OwnerReferences,
+				UID:               string(obj.UID),
+				Generation:        obj.Generation,
+			},
+			Spec: &obj.Spec,
+		}
+	},
 	gvk.TCPRoute: func(r runtime.Object) config.Config {
 		obj := r.(*sigsk8siogatewayapiapisv1alpha2.TCPRoute)
 		return config.Config{
@@ -0,0 +1,265 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drift detects the divergent copies of the same config across the primary clusters of a multi-primary
+// mesh, which would otherwise cause the istiod of each cluster to route the traffic differently.
+package drift
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	clientnetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/multicluster"
+	istiolog "istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
+)
+
+var log = istiolog.RegisterScope("drift", "config drift detection across primary clusters", 0)
+
+var (
+	typeTag = monitoring.MustCreateLabel("type")
+
+	configDrifts = monitoring.NewGauge(
+		"pilot_config_drifts",
+		"Number of configs of the config cluster with a divergent copy in a remote primary cluster, by type.",
+		monitoring.WithLabels(typeTag),
+	)
+
+	// watchedKinds are the kinds of the configs compared across the clusters.
+	watchedKinds = []kind.Kind{kind.VirtualService, kind.DestinationRule, kind.Gateway}
+)
+
+func init() {
+	monitoring.MustRegister(configDrifts)
+}
+
+// clusterConfigs reads the compared configs of a cluster.
+type clusterConfigs struct {
+	virtualServices  kclient.Client[*clientnetworking.VirtualService]
+	destinationRules kclient.Client[*clientnetworking.DestinationRule]
+	gateways         kclient.Client[*clientnetworking.Gateway]
+}
+
+func newClusterConfigs(client kube.Client, enqueue func(kind.Kind, controllers.Object)) *clusterConfigs {
+	c := &clusterConfigs{
+		virtualServices:  kclient.New[*clientnetworking.VirtualService](client),
+		destinationRules: kclient.New[*clientnetworking.DestinationRule](client),
+		gateways:         kclient.New[*clientnetworking.Gateway](client),
+	}
+	c.virtualServices.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) { enqueue(kind.VirtualService, o) }))
+	c.destinationRules.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) { enqueue(kind.DestinationRule, o) }))
+	c.gateways.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) { enqueue(kind.Gateway, o) }))
+	return c
+}
+
+func (c *clusterConfigs) shutdown() {
+	controllers.ShutdownAll(c.virtualServices, c.destinationRules, c.gateways)
+}
+
+// spec returns the spec of the config, or nil if the cluster does not have it.
+func (c *clusterConfigs) spec(key model.ConfigKey) proto.Message {
+	switch key.Kind {
+	case kind.VirtualService:
+		if vs := c.virtualServices.Get(key.Name, key.Namespace); vs != nil {
+			return &vs.Spec
+		}
+	case kind.DestinationRule:
+		if dr := c.destinationRules.Get(key.Name, key.Namespace); dr != nil {
+			return &dr.Spec
+		}
+	case kind.Gateway:
+		if gw := c.gateways.Get(key.Name, key.Namespace); gw != nil {
+			return &gw.Spec
+		}
+	}
+	return nil
+}
+
+// Controller compares the VirtualServices, DestinationRules and Gateways of the remote clusters with those of the
+// config cluster, and reports the divergent copies of the same config. Only the configs present in both clusters
+// are compared, as the remote clusters of a primary-remote mesh do not have configs.
+type Controller struct {
+	configCluster cluster.ID
+	queue         controllers.Queue
+
+	mu       sync.RWMutex
+	clusters map[cluster.ID]*clusterConfigs
+	drifts   map[model.ConfigKey][]model.ConfigDrift
+}
+
+var _ multicluster.ClusterHandler = &Controller{}
+
+func NewController(configCluster cluster.ID) *Controller {
+	c := &Controller{
+		configCluster: configCluster,
+		clusters:      map[cluster.ID]*clusterConfigs{},
+		drifts:        map[model.ConfigKey][]model.ConfigDrift{},
+	}
+	c.queue = controllers.NewQueue("config drift",
+		controllers.WithGenericReconciler(c.Reconcile),
+		controllers.WithMaxAttempts(5))
+	return c
+}
+
+func (c *Controller) Run(stop <-chan struct{}) {
+	c.queue.Run(stop)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, configs := range c.clusters {
+		configs.shutdown()
+	}
+}
+
+func (c *Controller) HasSynced() bool {
+	return c.queue.HasSynced()
+}
+
+func (c *Controller) enqueue(k kind.Kind, o controllers.Object) {
+	c.queue.Add(model.ConfigKey{Kind: k, Name: o.GetName(), Namespace: o.GetNamespace()})
+}
+
+func (c *Controller) ClusterAdded(cluster *multicluster.Cluster, _ <-chan struct{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clusters[cluster.ID] = newClusterConfigs(cluster.Client, c.enqueue)
+	return nil
+}
+
+func (c *Controller) ClusterUpdated(cluster *multicluster.Cluster, stop <-chan struct{}) error {
+	if err := c.ClusterDeleted(cluster.ID); err != nil {
+		return err
+	}
+	return c.ClusterAdded(cluster, stop)
+}
+
+func (c *Controller) ClusterDeleted(id cluster.ID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if configs := c.clusters[id]; configs != nil {
+		configs.shutdown()
+	}
+	delete(c.clusters, id)
+	// the configs of the cluster no longer drift
+	for key, drifts := range c.drifts {
+		for _, d := range drifts {
+			if d.Cluster == id {
+				c.queue.Add(key)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Reconcile compares the copies of the config of the key across the clusters.
+func (c *Controller) Reconcile(raw any) error {
+	key := raw.(model.ConfigKey)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var drifts []model.ConfigDrift
+	if local := c.clusters[c.configCluster]; local != nil {
+		if spec := local.spec(key); spec != nil {
+			for _, id := range c.remoteClusters() {
+				remote := c.clusters[id].spec(key)
+				if remote == nil {
+					continue
+				}
+				fields, err := Diff(spec, remote)
+				if err != nil {
+					return fmt.Errorf("failed to compare %s in cluster %s: %v", key, id, err)
+				}
+				if len(fields) > 0 {
+					drifts = append(drifts, model.ConfigDrift{
+						Kind:      key.Kind.String(),
+						Namespace: key.Namespace,
+						Name:      key.Name,
+						Cluster:   id,
+						Fields:    fields,
+					})
+				}
+			}
+		}
+	}
+
+	for _, d := range drifts {
+		log.Warnf("%s %s/%s of cluster %s diverges from the config cluster %s: %v",
+			d.Kind, d.Namespace, d.Name, d.Cluster, c.configCluster, d.Fields)
+	}
+	if len(drifts) == 0 {
+		if _, f := c.drifts[key]; f {
+			log.Infof("%s %s/%s no longer diverges across clusters", key.Kind, key.Namespace, key.Name)
+		}
+		delete(c.drifts, key)
+	} else {
+		c.drifts[key] = drifts
+	}
+	c.recordMetrics()
+	return nil
+}
+
+func (c *Controller) remoteClusters() []cluster.ID {
+	ids := make([]cluster.ID, 0, len(c.clusters))
+	for id := range c.clusters {
+		if id != c.configCluster {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (c *Controller) recordMetrics() {
+	counts := map[kind.Kind]int{}
+	for key := range c.drifts {
+		counts[key.Kind]++
+	}
+	for _, k := range watchedKinds {
+		configDrifts.With(typeTag.Value(k.String())).Record(float64(counts[k]))
+	}
+}
+
+// ConfigDrifts returns the divergent copies of the configs, for debugging.
+func (c *Controller) ConfigDrifts() []model.ConfigDrift {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := []model.ConfigDrift{}
+	for _, drifts := range c.drifts {
+		out = append(out, drifts...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Cluster < b.Cluster
+	})
+	return out
+}
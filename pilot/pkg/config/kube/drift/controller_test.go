@@ -0,0 +1,109 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networking "istio.io/api/networking/v1alpha3"
+	clientnetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/kube/multicluster"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestController(t *testing.T) {
+	stop := test.NewStop(t)
+	c := NewController("config")
+	go c.Run(stop)
+
+	clients := map[cluster.ID]kube.Client{}
+	addCluster := func(id cluster.ID) clienttest.TestCached[*clientnetworking.DestinationRule] {
+		client := kube.NewFakeClient()
+		clients[id] = client
+		assert.NoError(t, c.ClusterAdded(&multicluster.Cluster{ID: id, Client: client}, stop))
+		drs := clienttest.Wrap(t, kclient.New[*clientnetworking.DestinationRule](client))
+		client.RunAndWait(stop)
+		return drs
+	}
+	dr := func(lb networking.LoadBalancerSettings_SimpleLB) *clientnetworking.DestinationRule {
+		return &clientnetworking.DestinationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "default"},
+			Spec: networking.DestinationRule{
+				Host: "reviews",
+				TrafficPolicy: &networking.TrafficPolicy{LoadBalancer: &networking.LoadBalancerSettings{
+					LbPolicy: &networking.LoadBalancerSettings_Simple{Simple: lb},
+				}},
+			},
+		}
+	}
+	expect := func(want ...model.ConfigDrift) {
+		t.Helper()
+		if want == nil {
+			want = []model.ConfigDrift{}
+		}
+		assert.EventuallyEqual(t, c.ConfigDrifts, want)
+	}
+	drifted := func(id cluster.ID) model.ConfigDrift {
+		return model.ConfigDrift{
+			Kind:      "DestinationRule",
+			Namespace: "default",
+			Name:      "reviews",
+			Cluster:   id,
+			Fields:    []string{"trafficPolicy.loadBalancer.simple"},
+		}
+	}
+
+	local := addCluster("config")
+	remote := addCluster("remote")
+
+	// A config present only in the config cluster does not drift
+	local.Create(dr(networking.LoadBalancerSettings_ROUND_ROBIN))
+	expect()
+
+	remote.Create(dr(networking.LoadBalancerSettings_ROUND_ROBIN))
+	expect()
+
+	remote.Update(dr(networking.LoadBalancerSettings_LEAST_REQUEST))
+	expect(drifted("remote"))
+
+	// The configs of an updated cluster are read again
+	assert.NoError(t, c.ClusterUpdated(&multicluster.Cluster{ID: "remote", Client: clients["remote"]}, stop))
+	clients["remote"].RunAndWait(stop)
+	expect(drifted("remote"))
+
+	// An update of the config cluster is compared again
+	local.Update(dr(networking.LoadBalancerSettings_LEAST_REQUEST))
+	expect()
+
+	other := addCluster("other")
+	other.Create(dr(networking.LoadBalancerSettings_RANDOM))
+	expect(drifted("other"))
+
+	// The configs of a deleted cluster no longer drift
+	assert.NoError(t, c.ClusterDeleted("other"))
+	expect()
+
+	remote.Delete("reviews", "default")
+	local.Update(dr(networking.LoadBalancerSettings_RANDOM))
+	expect()
+}
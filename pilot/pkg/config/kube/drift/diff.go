@@ -0,0 +1,85 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"istio.io/istio/pkg/util/protomarshal"
+)
+
+// Diff returns the paths of the fields which differ between the two specs, for example http[0].route[1].weight,
+// sorted. The fields set in only one of the specs are included.
+func Diff(a, b proto.Message) ([]string, error) {
+	am, err := protomarshal.ToJSONMap(a)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := protomarshal.ToJSONMap(b)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	diffValues("", am, bm, &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func diffValues(path string, a, b any, fields *[]string) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			*fields = append(*fields, path)
+			return
+		}
+		keys := map[string]struct{}{}
+		for k := range av {
+			keys[k] = struct{}{}
+		}
+		for k := range bv {
+			keys[k] = struct{}{}
+		}
+		for k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffValues(p, av[k], bv[k], fields)
+		}
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(av) || i >= len(bv) {
+				*fields = append(*fields, p)
+				continue
+			}
+			diffValues(p, av[i], bv[i], fields)
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*fields = append(*fields, path)
+		}
+	}
+}
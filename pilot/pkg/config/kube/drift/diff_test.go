@@ -0,0 +1,58 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestDiff(t *testing.T) {
+	route := func(weights ...int32) *networking.VirtualService {
+		vs := &networking.VirtualService{Hosts: []string{"reviews"}, Http: []*networking.HTTPRoute{{}}}
+		for i, w := range weights {
+			subset := []string{"v1", "v2", "v3"}[i]
+			vs.Http[0].Route = append(vs.Http[0].Route, &networking.HTTPRouteDestination{
+				Destination: &networking.Destination{Host: "reviews", Subset: subset},
+				Weight:      w,
+			})
+		}
+		return vs
+	}
+	cases := []struct {
+		name string
+		a, b *networking.VirtualService
+		want []string
+	}{
+		{name: "identical", a: route(90, 10), b: route(90, 10), want: nil},
+		{name: "weights", a: route(90, 10), b: route(50, 50), want: []string{"http[0].route[0].weight", "http[0].route[1].weight"}},
+		{name: "added route", a: route(90, 10), b: route(80, 10, 10), want: []string{"http[0].route[0].weight", "http[0].route[2]"}},
+		{
+			name: "field set in one",
+			a:    &networking.VirtualService{Hosts: []string{"reviews"}},
+			b:    &networking.VirtualService{Hosts: []string{"reviews"}, Gateways: []string{"mesh"}},
+			want: []string{"gateways"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.a, tt.b)
+			assert.NoError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
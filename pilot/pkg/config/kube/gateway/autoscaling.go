@@ -0,0 +1,127 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+
+	"istio.io/istio/pilot/pkg/features"
+)
+
+const (
+	// gatewayAutoscalingMinReplicas sets the minimum replicas of the HorizontalPodAutoscaler for a gateway.
+	gatewayAutoscalingMinReplicas = "gateway.istio.io/autoscaling-min-replicas"
+	// gatewayAutoscalingMaxReplicas sets the maximum replicas of the HorizontalPodAutoscaler for a gateway.
+	// Autoscaling is only enabled when this is set, either on the Gateway or its namespace.
+	gatewayAutoscalingMaxReplicas = "gateway.istio.io/autoscaling-max-replicas"
+	// gatewayAutoscalingTargetCPU sets the target average CPU utilization, as a percentage of the requested CPU.
+	gatewayAutoscalingTargetCPU = "gateway.istio.io/autoscaling-target-cpu-utilization"
+	// gatewayAutoscalingTargetMemory sets the target average memory utilization, as a percentage of the requested
+	// memory. The gateway is only scaled on its memory when this is set.
+	gatewayAutoscalingTargetMemory = "gateway.istio.io/autoscaling-target-memory-utilization"
+	// gatewayAutoscalingTargetConnections sets the target average of the active downstream connections of the pods.
+	// The gateway is only scaled on its connections when this is set. The metric is read from the custom metrics API,
+	// under the name of features.GatewayAutoscalingConnectionsMetric.
+	gatewayAutoscalingTargetConnections = "gateway.istio.io/autoscaling-target-active-connections"
+	// gatewayAutoscalingTargetRequests sets the target average of the requests per second of the pods. The gateway is
+	// only scaled on its requests when this is set. The metric is read from the custom metrics API, under the name of
+	// features.GatewayAutoscalingRequestsMetric.
+	gatewayAutoscalingTargetRequests = "gateway.istio.io/autoscaling-target-requests-per-second"
+
+	defaultAutoscalingMinReplicas = 1
+	defaultAutoscalingTargetCPU   = 80
+)
+
+// AutoscalingInput configures the HorizontalPodAutoscaler rendered for a gateway.
+type AutoscalingInput struct {
+	MinReplicas                    int32
+	MaxReplicas                    int32
+	TargetCPUUtilizationPercentage int32
+	// TargetMemoryUtilizationPercentage is the target of the memory utilization, 0 if the gateway is not scaled on its
+	// memory.
+	TargetMemoryUtilizationPercentage int32
+	// TargetActiveConnections is the target average of the active downstream connections of the pods, 0 if the
+	// gateway is not scaled on its connections. ActiveConnectionsMetric is the name of the metric of the pods.
+	TargetActiveConnections int32
+	ActiveConnectionsMetric string
+	// TargetRequestsPerSecond is the target average of the requests per second of the pods, 0 if the gateway is not
+	// scaled on its requests. RequestsPerSecondMetric is the name of the metric of the pods.
+	TargetRequestsPerSecond int32
+	RequestsPerSecondMetric string
+}
+
+// extractAutoscaling builds the autoscaling configuration for a gateway. Each setting is read from the Gateway annotations,
+// falling back to the annotations of the Gateway's namespace. If no maximum is configured, nil is returned and
+// autoscaling is disabled.
+func extractAutoscaling(gwAnnotations, nsAnnotations map[string]string) (*AutoscalingInput, error) {
+	lookup := func(key string, def int32) (int32, bool, error) {
+		v, f := gwAnnotations[key]
+		if !f {
+			v, f = nsAnnotations[key]
+		}
+		if !f {
+			return def, false, nil
+		}
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || i <= 0 {
+			return 0, true, fmt.Errorf("invalid %v annotation %q: must be a positive integer", key, v)
+		}
+		return int32(i), true, nil
+	}
+	maxReplicas, enabled, err := lookup(gatewayAutoscalingMaxReplicas, 0)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	minReplicas, _, err := lookup(gatewayAutoscalingMinReplicas, defaultAutoscalingMinReplicas)
+	if err != nil {
+		return nil, err
+	}
+	targetCPU, _, err := lookup(gatewayAutoscalingTargetCPU, defaultAutoscalingTargetCPU)
+	if err != nil {
+		return nil, err
+	}
+	targetMemory, _, err := lookup(gatewayAutoscalingTargetMemory, 0)
+	if err != nil {
+		return nil, err
+	}
+	targetConnections, _, err := lookup(gatewayAutoscalingTargetConnections, 0)
+	if err != nil {
+		return nil, err
+	}
+	targetRequests, _, err := lookup(gatewayAutoscalingTargetRequests, 0)
+	if err != nil {
+		return nil, err
+	}
+	if minReplicas > maxReplicas {
+		return nil, fmt.Errorf("%v (%d) must not be greater than %v (%d)",
+			gatewayAutoscalingMinReplicas, minReplicas, gatewayAutoscalingMaxReplicas, maxReplicas)
+	}
+	res := &AutoscalingInput{
+		MinReplicas:                       minReplicas,
+		MaxReplicas:                       maxReplicas,
+		TargetCPUUtilizationPercentage:    targetCPU,
+		TargetMemoryUtilizationPercentage: targetMemory,
+	}
+	if targetConnections > 0 {
+		res.TargetActiveConnections = targetConnections
+		res.ActiveConnectionsMetric = features.GatewayAutoscalingConnectionsMetric
+	}
+	if targetRequests > 0 {
+		res.TargetRequestsPerSecond = targetRequests
+		res.RequestsPerSecondMetric = features.GatewayAutoscalingRequestsMetric
+	}
+	return res, nil
+}
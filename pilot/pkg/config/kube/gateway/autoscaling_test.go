@@ -0,0 +1,90 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractAutoscaling(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		ns        map[string]string
+		want      *AutoscalingInput
+		wantError bool
+	}{
+		{
+			name: "disabled",
+			gw:   map[string]string{gatewayAutoscalingMinReplicas: "2"},
+			want: nil,
+		},
+		{
+			name: "defaults",
+			gw:   map[string]string{gatewayAutoscalingMaxReplicas: "3"},
+			want: &AutoscalingInput{MinReplicas: 1, MaxReplicas: 3, TargetCPUUtilizationPercentage: 80},
+		},
+		{
+			name: "namespace defaults",
+			gw:   map[string]string{gatewayAutoscalingMinReplicas: "2"},
+			ns:   map[string]string{gatewayAutoscalingMaxReplicas: "4", gatewayAutoscalingMinReplicas: "3", gatewayAutoscalingTargetCPU: "50"},
+			want: &AutoscalingInput{MinReplicas: 2, MaxReplicas: 4, TargetCPUUtilizationPercentage: 50},
+		},
+		{
+			name: "memory",
+			gw:   map[string]string{gatewayAutoscalingMaxReplicas: "3", gatewayAutoscalingTargetMemory: "70"},
+			want: &AutoscalingInput{MinReplicas: 1, MaxReplicas: 3, TargetCPUUtilizationPercentage: 80, TargetMemoryUtilizationPercentage: 70},
+		},
+		{
+			name: "connections and requests",
+			gw: map[string]string{
+				gatewayAutoscalingMaxReplicas:       "3",
+				gatewayAutoscalingTargetConnections: "1000",
+				gatewayAutoscalingTargetRequests:    "500",
+			},
+			want: &AutoscalingInput{
+				MinReplicas: 1, MaxReplicas: 3, TargetCPUUtilizationPercentage: 80,
+				TargetActiveConnections: 1000, ActiveConnectionsMetric: "envoy_server_total_connections",
+				TargetRequestsPerSecond: 500, RequestsPerSecondMetric: "istio_requests_per_second",
+			},
+		},
+		{
+			name:      "invalid connections",
+			gw:        map[string]string{gatewayAutoscalingMaxReplicas: "3", gatewayAutoscalingTargetConnections: "0"},
+			wantError: true,
+		},
+		{
+			name:      "invalid",
+			gw:        map[string]string{gatewayAutoscalingMaxReplicas: "many"},
+			wantError: true,
+		},
+		{
+			name:      "min greater than max",
+			gw:        map[string]string{gatewayAutoscalingMaxReplicas: "2", gatewayAutoscalingMinReplicas: "3"},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractAutoscaling(tt.gw, tt.ns)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	credentials "istio.io/istio/pilot/pkg/credentials/kube"
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/ptr"
+)
+
+// gatewayConditionResolvedRefs reports whether the TLS Secrets referenced by the listeners of the gateway are
+// permitted, exist and hold a valid key pair. It is only reported if features.ValidateGatewayCertificateRefs is
+// enabled. The resources of a gateway are applied whatever its references, as only the listeners with unresolved
+// references are not served.
+const gatewayConditionResolvedRefs = "gateway.istio.io/ResolvedRefs"
+
+// The reasons of the gatewayConditionResolvedRefs condition.
+const (
+	reasonResolvedRefs = string(gateway.ListenerReasonResolvedRefs)
+	// reasonInvalidCertificateRef is reported for the references which are not Secrets, and the Secrets not holding
+	// a valid key pair.
+	reasonInvalidCertificateRef = string(gateway.ListenerReasonInvalidCertificateRef)
+	// reasonCertificateRefNotFound is reported for the references to missing Secrets.
+	reasonCertificateRefNotFound = "CertificateRefNotFound"
+	// reasonCertificateRefNotPermitted is reported for the references to the Secrets of other namespaces not
+	// permitted by a ReferenceGrant.
+	reasonCertificateRefNotPermitted = string(gateway.ListenerReasonRefNotPermitted)
+)
+
+// certificateRefError is the error of a certificate reference of a listener.
+type certificateRefError struct {
+	reason  string
+	message string
+}
+
+// listenerCertificateRefs returns the Secrets referenced by the listeners of the gateway terminating TLS, by listener.
+// The references which are not Secrets are returned as errors.
+func listenerCertificateRefs(gw *gateway.Gateway) (refs map[string][]types.NamespacedName, errs []certificateRefError) {
+	refs = map[string][]types.NamespacedName{}
+	for _, l := range gw.Spec.Listeners {
+		if l.TLS == nil || ptr.OrDefault(l.TLS.Mode, gateway.TLSModeTerminate) != gateway.TLSModeTerminate {
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			if !nilOrEqual((*string)(ref.Group), gvk.Secret.Group) || !nilOrEqual((*string)(ref.Kind), gvk.Secret.Kind) {
+				errs = append(errs, certificateRefError{
+					reason:  reasonInvalidCertificateRef,
+					message: fmt.Sprintf("listener %s: invalid certificate reference %v, only secret is allowed", l.Name, objectReferenceString(ref)),
+				})
+				continue
+			}
+			refs[string(l.Name)] = append(refs[string(l.Name)], types.NamespacedName{
+				Namespace: ptr.OrDefault((*string)(ref.Namespace), gw.Namespace),
+				Name:      string(ref.Name),
+			})
+		}
+	}
+	return refs, errs
+}
+
+// certificateRefsCondition returns the ResolvedRefs condition of the gateway, false if one of the Secrets referenced
+// by its listeners is not permitted, missing or malformed. The permission to reference the Secrets of other
+// namespaces is checked first, so their existence is not disclosed.
+func (d *DeploymentController) certificateRefsCondition(gw gateway.Gateway) metav1.Condition {
+	refs, errs := listenerCertificateRefs(&gw)
+	for _, l := range gw.Spec.Listeners {
+		for _, ref := range refs[string(l.Name)] {
+			if !d.certificateRefPermitted(gw.Namespace, ref) {
+				errs = append(errs, certificateRefError{
+					reason: reasonCertificateRefNotPermitted,
+					message: fmt.Sprintf("listener %s: Secret %v not accessible to a Gateway in namespace %q (missing a ReferenceGrant?)",
+						l.Name, ref, gw.Namespace),
+				})
+				continue
+			}
+			secret := d.secrets.Get(ref.Name, ref.Namespace)
+			if secret == nil {
+				errs = append(errs, certificateRefError{
+					reason:  reasonCertificateRefNotFound,
+					message: fmt.Sprintf("listener %s: Secret %v not found", l.Name, ref),
+				})
+				continue
+			}
+			if err := validateKeyPair(secret); err != nil {
+				errs = append(errs, certificateRefError{
+					reason:  reasonInvalidCertificateRef,
+					message: fmt.Sprintf("listener %s: Secret %v is invalid: %v", l.Name, ref, err),
+				})
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return metav1.Condition{
+			Type:    gatewayConditionResolvedRefs,
+			Status:  kstatus.StatusTrue,
+			Reason:  reasonResolvedRefs,
+			Message: "All the certificate references of the listeners are resolved",
+		}
+	}
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.message)
+	}
+	return metav1.Condition{
+		Type:    gatewayConditionResolvedRefs,
+		Status:  kstatus.StatusFalse,
+		Reason:  errs[0].reason,
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// certificateRefPermitted returns whether a gateway of the namespace may reference the Secret: if it is in the same
+// namespace, or a ReferenceGrant of the namespace of the Secret permits it.
+func (d *DeploymentController) certificateRefPermitted(namespace string, ref types.NamespacedName) bool {
+	if ref.Namespace == namespace {
+		return true
+	}
+	for _, grant := range d.referenceGrants.List(ref.Namespace, klabels.Everything()) {
+		from := slices.ContainsFunc(grant.Spec.From, func(f k8s.ReferenceGrantFrom) bool {
+			return string(f.Group) == gvk.KubernetesGateway.Group && string(f.Kind) == gvk.KubernetesGateway.Kind &&
+				string(f.Namespace) == namespace
+		})
+		to := slices.ContainsFunc(grant.Spec.To, func(t k8s.ReferenceGrantTo) bool {
+			return t.Group == "" && string(t.Kind) == gvk.Secret.Kind && (t.Name == nil || string(*t.Name) == ref.Name)
+		})
+		if from && to {
+			return true
+		}
+	}
+	return false
+}
+
+// validateKeyPair returns an error if the Secret does not hold a valid TLS key pair.
+func validateKeyPair(secret *corev1.Secret) error {
+	key, cert, err := credentials.ExtractKeyAndCert(secret)
+	if err != nil {
+		return err
+	}
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return fmt.Errorf("the certificate is malformed: %v", err)
+	}
+	return nil
+}
+
+// gatewaysReferencingGrantNamespace returns the outputs of a ReferenceGrant: the gateways whose listeners reference
+// the Secrets of its namespace.
+func (d *DeploymentController) gatewaysReferencingGrantNamespace(o controllers.Object) []types.NamespacedName {
+	var res []types.NamespacedName
+	for _, g := range d.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+		refs, _ := listenerCertificateRefs(g)
+		for _, lr := range refs {
+			if slices.ContainsFunc(lr, func(ref types.NamespacedName) bool { return ref.Namespace == o.GetNamespace() }) {
+				res = append(res, config.NamespacedName(g))
+				break
+			}
+		}
+	}
+	return res
+}
+
+// gatewaysReferencingSecret returns the outputs of a Secret: the gateways whose listeners reference it.
+func (d *DeploymentController) gatewaysReferencingSecret(o controllers.Object) []types.NamespacedName {
+	secret := config.NamespacedName(o)
+	var res []types.NamespacedName
+	for _, g := range d.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+		refs, _ := listenerCertificateRefs(g)
+		for _, lr := range refs {
+			if slices.Contains(lr, secret) {
+				res = append(res, config.NamespacedName(g))
+				break
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,202 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func tlsListener(name string, refs ...v1beta1.SecretObjectReference) v1beta1.Listener {
+	return v1beta1.Listener{
+		Name:     v1beta1.SectionName(name),
+		Port:     443,
+		Protocol: v1beta1.HTTPSProtocolType,
+		TLS:      &v1beta1.GatewayTLSConfig{CertificateRefs: refs},
+	}
+}
+
+func TestCertificateRefsCondition(t *testing.T) {
+	otherNamespaceRef := func(name string) v1beta1.SecretObjectReference {
+		return v1beta1.SecretObjectReference{Name: v1beta1.ObjectName(name), Namespace: ptr.Of(v1beta1.Namespace("cert"))}
+	}
+	grant := func(name *k8s.ObjectName) *k8s.ReferenceGrant {
+		return &k8s.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "cert"},
+			Spec: k8s.ReferenceGrantSpec{
+				From: []k8s.ReferenceGrantFrom{{Group: k8s.GroupName, Kind: "Gateway", Namespace: "istio-system"}},
+				To:   []k8s.ReferenceGrantTo{{Kind: "Secret", Name: name}},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		listeners []v1beta1.Listener
+		// grants are the ReferenceGrants of the namespace of the Secrets
+		grants      []*k8s.ReferenceGrant
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "no tls",
+			listeners:  []v1beta1.Listener{{Name: "http", Port: 80, Protocol: v1beta1.HTTPProtocolType}},
+			wantStatus: kstatus.StatusTrue,
+			wantReason: reasonResolvedRefs,
+		},
+		{
+			name:       "valid",
+			listeners:  []v1beta1.Listener{tlsListener("https", v1beta1.SecretObjectReference{Name: "my-cert-http"})},
+			wantStatus: kstatus.StatusTrue,
+			wantReason: reasonResolvedRefs,
+		},
+		{
+			name: "passthrough",
+			listeners: []v1beta1.Listener{{
+				Name:     "tls",
+				Port:     443,
+				Protocol: v1beta1.TLSProtocolType,
+				TLS:      &v1beta1.GatewayTLSConfig{Mode: ptr.Of(v1beta1.TLSModePassthrough)},
+			}},
+			wantStatus: kstatus.StatusTrue,
+			wantReason: reasonResolvedRefs,
+		},
+		{
+			name:        "not found",
+			listeners:   []v1beta1.Listener{tlsListener("https", v1beta1.SecretObjectReference{Name: "missing"})},
+			wantStatus:  kstatus.StatusFalse,
+			wantReason:  reasonCertificateRefNotFound,
+			wantMessage: "listener https: Secret istio-system/missing not found",
+		},
+		{
+			name:        "malformed",
+			listeners:   []v1beta1.Listener{tlsListener("https", v1beta1.SecretObjectReference{Name: "malformed"})},
+			wantStatus:  kstatus.StatusFalse,
+			wantReason:  reasonInvalidCertificateRef,
+			wantMessage: "listener https: Secret istio-system/malformed is invalid: the certificate is malformed",
+		},
+		{
+			name: "not a secret",
+			listeners: []v1beta1.Listener{tlsListener("https", v1beta1.SecretObjectReference{
+				Kind: ptr.Of(v1beta1.Kind("ConfigMap")),
+				Name: "my-cert-http",
+			})},
+			wantStatus:  kstatus.StatusFalse,
+			wantReason:  reasonInvalidCertificateRef,
+			wantMessage: "listener https: invalid certificate reference /ConfigMap/my-cert-http., only secret is allowed",
+		},
+		{
+			name:       "other namespace",
+			listeners:  []v1beta1.Listener{tlsListener("https", otherNamespaceRef("cert"))},
+			grants:     []*k8s.ReferenceGrant{grant(nil)},
+			wantStatus: kstatus.StatusTrue,
+			wantReason: reasonResolvedRefs,
+		},
+		{
+			name:       "other namespace granted by name",
+			listeners:  []v1beta1.Listener{tlsListener("https", otherNamespaceRef("cert"))},
+			grants:     []*k8s.ReferenceGrant{grant(ptr.Of(k8s.ObjectName("cert")))},
+			wantStatus: kstatus.StatusTrue,
+			wantReason: reasonResolvedRefs,
+		},
+		{
+			name:        "other namespace not granted",
+			listeners:   []v1beta1.Listener{tlsListener("https", otherNamespaceRef("cert"))},
+			grants:      []*k8s.ReferenceGrant{grant(ptr.Of(k8s.ObjectName("other")))},
+			wantStatus:  kstatus.StatusFalse,
+			wantReason:  reasonCertificateRefNotPermitted,
+			wantMessage: `listener https: Secret cert/cert not accessible to a Gateway in namespace "istio-system"`,
+		},
+		{
+			// The permission is checked first, so the Secret is not disclosed
+			name:        "other namespace not granted missing",
+			listeners:   []v1beta1.Listener{tlsListener("https", otherNamespaceRef("missing"))},
+			wantStatus:  kstatus.StatusFalse,
+			wantReason:  reasonCertificateRefNotPermitted,
+			wantMessage: `listener https: Secret cert/missing not accessible to a Gateway in namespace "istio-system"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := kube.NewFakeClient(secrets...)
+			d := &DeploymentController{
+				secrets:         kclient.New[*corev1.Secret](client),
+				referenceGrants: kclient.New[*k8s.ReferenceGrant](client),
+			}
+			client.RunAndWait(test.NewStop(t))
+			for _, g := range tt.grants {
+				clienttest.Wrap(t, d.referenceGrants).Create(g)
+			}
+			assert.EventuallyEqual(t, func() int {
+				return len(d.referenceGrants.List("cert", klabels.Everything()))
+			}, len(tt.grants))
+			got := d.certificateRefsCondition(v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "istio-system"},
+				Spec:       v1beta1.GatewaySpec{Listeners: tt.listeners},
+			})
+			assert.Equal(t, got.Type, gatewayConditionResolvedRefs)
+			assert.Equal(t, got.Status, tt.wantStatus)
+			assert.Equal(t, got.Reason, tt.wantReason)
+			if !strings.HasPrefix(got.Message, tt.wantMessage) {
+				t.Fatalf("got message %q, want prefix %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGatewaysReferencingSecret(t *testing.T) {
+	client := kube.NewFakeClient()
+	d := &DeploymentController{gateways: kclient.New[*v1beta1.Gateway](client)}
+	gateways := clienttest.Wrap(t, d.gateways)
+	gateways.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"},
+		Spec: v1beta1.GatewaySpec{Listeners: []v1beta1.Listener{
+			tlsListener("https", v1beta1.SecretObjectReference{Name: "cert"}),
+		}},
+	})
+	gateways.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "other"},
+		Spec: v1beta1.GatewaySpec{Listeners: []v1beta1.Listener{
+			tlsListener("https", v1beta1.SecretObjectReference{Name: "cert", Namespace: ptr.Of(v1beta1.Namespace("default"))}),
+			tlsListener("https-2", v1beta1.SecretObjectReference{Name: "cert", Namespace: ptr.Of(v1beta1.Namespace("default"))}),
+		}},
+	})
+	gateways.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other"},
+		Spec: v1beta1.GatewaySpec{Listeners: []v1beta1.Listener{
+			tlsListener("https", v1beta1.SecretObjectReference{Name: "cert"}),
+		}},
+	})
+	client.RunAndWait(test.NewStop(t))
+	got := d.gatewaysReferencingSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: "default"}})
+	slices.SortFunc(got, func(a, b types.NamespacedName) bool { return a.String() < b.String() })
+	assert.Equal(t, got, []types.NamespacedName{{Namespace: "default", Name: "a"}, {Namespace: "other", Name: "b"}})
+}
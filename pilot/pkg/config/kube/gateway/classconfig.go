@@ -0,0 +1,362 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/kclient"
+)
+
+// GatewayClassConfigGVR is the resource of the GatewayClassConfigs, the typed parameters of a GatewayClass.
+var GatewayClassConfigGVR = schema.GroupVersionResource{
+	Group:    "gateway.istio.io",
+	Version:  "v1alpha1",
+	Resource: "gatewayclassconfigs",
+}
+
+// GatewayClassConfigKind is the kind of the GatewayClassConfigs.
+const GatewayClassConfigKind = "GatewayClassConfig"
+
+// GatewayClassConfig holds the parameters of the gateways of the GatewayClasses referencing it in their parametersRef.
+// It is the typed alternative to a parameters ConfigMap, see classParameters for the precedence of the settings.
+type GatewayClassConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayClassConfigSpec `json:"spec"`
+}
+
+// GatewayClassConfigSpec is the specification of a GatewayClassConfig.
+type GatewayClassConfigSpec struct {
+	// Mode is the deployment mode of the gateways, DeploymentModeDeployment if unset.
+	Mode string `json:"mode,omitempty"`
+	// PerPodServices exposes each pod of the StatefulSet with its own Service, in DeploymentModeStatefulSet.
+	PerPodServices bool `json:"perPodServices,omitempty"`
+	// Deployment overrides the defaults of the workload of the gateways.
+	Deployment *GatewayClassDeployment `json:"deployment,omitempty"`
+	// Service overrides the defaults of the Service of the gateways.
+	Service *GatewayClassService `json:"service,omitempty"`
+	// ServiceAccount overrides the defaults of the ServiceAccount of the gateways.
+	ServiceAccount *GatewayClassServiceAccount `json:"serviceAccount,omitempty"`
+	// Autoscaling enables a HorizontalPodAutoscaler for the gateways.
+	Autoscaling *GatewayClassAutoscaling `json:"autoscaling,omitempty"`
+	// NetworkPolicy restricts the ingress traffic of the pods of the gateways to their listener ports and status port.
+	NetworkPolicy bool `json:"networkPolicy,omitempty"`
+}
+
+// GatewayClassDeployment holds the settings of the workload of the gateways.
+type GatewayClassDeployment struct {
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Resources are the resources of the gateway proxy container. Only the cpu and memory are supported.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	SchedulingInput `json:",inline"`
+	// TopologySpreadConstraints replace the default spread of the replicas across zones, if set.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is the PriorityClass of the pods of the gateways.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// RuntimeClassName is the RuntimeClass of the pods of the gateways.
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+	// HostNetwork runs the pods of the gateways in the network namespace of their nodes, binding the listener ports
+	// on the nodes. The Service of the gateways defaults to ClusterIP.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+}
+
+// GatewayClassService holds the settings of the Service of the gateways.
+type GatewayClassService struct {
+	Type                  corev1.ServiceType                      `json:"type,omitempty"`
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	Annotations           map[string]string                       `json:"annotations,omitempty"`
+}
+
+// GatewayClassServiceAccount holds the settings of the ServiceAccount of the gateways.
+type GatewayClassServiceAccount struct {
+	// Annotations are added to the annotations of the ServiceAccount, like the cloud IAM identity of the pods.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayClassAutoscaling holds the settings of the HorizontalPodAutoscaler of the gateways.
+type GatewayClassAutoscaling struct {
+	MinReplicas                       *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas                       int32  `json:"maxReplicas"`
+	TargetCPUUtilizationPercentage    *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// TargetActiveConnections and TargetRequestsPerSecond are the targets of the averages of the active downstream
+	// connections and requests per second of the pods, read from the custom metrics API.
+	TargetActiveConnections *int32 `json:"targetActiveConnections,omitempty"`
+	TargetRequestsPerSecond *int32 `json:"targetRequestsPerSecond,omitempty"`
+}
+
+// parametersClassConfig returns the GatewayClassConfig holding the parameters of the class, referenced by the
+// parametersRef of the GatewayClass. ok is false if the class does not reference one.
+func parametersClassConfig(gc *gateway.GatewayClass) (ref types.NamespacedName, ok bool) {
+	p := gc.Spec.ParametersRef
+	if p == nil || string(p.Group) != GatewayClassConfigGVR.Group || string(p.Kind) != GatewayClassConfigKind || p.Namespace == nil {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: string(*p.Namespace), Name: p.Name}, true
+}
+
+// classConfigCRDName is the name of the CustomResourceDefinition of the GatewayClassConfigs.
+var classConfigCRDName = GatewayClassConfigGVR.Resource + "." + GatewayClassConfigGVR.Group
+
+// onCRDEvent starts watching the GatewayClassConfigs once their CRD is installed: the informer cannot be started
+// before, as it would never sync.
+func (d *DeploymentController) onCRDEvent(name string) {
+	if name != classConfigCRDName {
+		return
+	}
+	d.classConfigCRDOnce.Do(func() {
+		close(d.classConfigCRD)
+	})
+}
+
+// runClassConfigs watches the GatewayClassConfigs, once their CRD is installed.
+func (d *DeploymentController) runClassConfigs(stop <-chan struct{}) {
+	select {
+	case <-d.classConfigCRD:
+	case <-stop:
+		return
+	}
+	inf := d.client.DynamicInformer().ForResource(GatewayClassConfigGVR).Informer()
+	classConfigs := kclient.NewUntyped(d.client, inf, kclient.Filter{})
+	classConfigs.AddEventHandler(controllers.ObjectHandler(d.classConfigDep.handler(d.queue)))
+	go inf.Run(stop)
+	if !kube.WaitForCacheSync(stop, classConfigs.HasSynced) {
+		return
+	}
+	d.classConfigs.Store(&classConfigs)
+}
+
+// readClassConfig reads the parameters of the GatewayClassConfig referenced by a GatewayClass.
+func (d *DeploymentController) readClassConfig(ref types.NamespacedName) (classParameters, error) {
+	classConfigs := d.classConfigs.Load()
+	if classConfigs == nil {
+		return classParameters{}, fmt.Errorf("%s %v cannot be read: the %s CRD is not installed, or not synced yet",
+			GatewayClassConfigKind, ref, classConfigCRDName)
+	}
+	obj := (*classConfigs).Get(ref.Name, ref.Namespace)
+	if obj == nil {
+		return classParameters{}, fmt.Errorf("%s %v not found", GatewayClassConfigKind, ref)
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return classParameters{}, fmt.Errorf("unexpected type %T for %s %v", obj, GatewayClassConfigKind, ref)
+	}
+	cfg := &GatewayClassConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cfg); err != nil {
+		return classParameters{}, fmt.Errorf("invalid %s %v: %v", GatewayClassConfigKind, ref, err)
+	}
+	res, err := classConfigParameters(cfg.Spec)
+	if err != nil {
+		return classParameters{}, fmt.Errorf("invalid %s %v: %v", GatewayClassConfigKind, ref, err)
+	}
+	return res, nil
+}
+
+// classConfigParameters converts the specification of a GatewayClassConfig to the parameters of the class. The
+// settings also configurable with the annotations of the Gateways are set as their default annotations, so that they
+// are validated and merged with those of the Gateways the same way.
+func classConfigParameters(spec GatewayClassConfigSpec) (classParameters, error) {
+	res := classParameters{mode: DeploymentModeDeployment, perPodServices: spec.PerPodServices, networkPolicy: spec.NetworkPolicy}
+	switch spec.Mode {
+	case "":
+	case DeploymentModeDeployment, DeploymentModeDaemonSet, DeploymentModeStatefulSet:
+		res.mode = spec.Mode
+	default:
+		return classParameters{}, fmt.Errorf("invalid mode %q: must be %s, %s or %s",
+			spec.Mode, DeploymentModeDeployment, DeploymentModeDaemonSet, DeploymentModeStatefulSet)
+	}
+	if res.perPodServices && res.mode != DeploymentModeStatefulSet {
+		return classParameters{}, fmt.Errorf("perPodServices requires the %s mode", DeploymentModeStatefulSet)
+	}
+
+	annotations := map[string]string{}
+	if dp := spec.Deployment; dp != nil {
+		if dp.Replicas != nil {
+			if *dp.Replicas < 0 {
+				return classParameters{}, fmt.Errorf("replicas must not be negative, got %d", *dp.Replicas)
+			}
+			annotations[gatewayReplicas] = strconv.Itoa(int(*dp.Replicas))
+		}
+		if dp.Resources != nil {
+			for _, r := range []struct {
+				annotation string
+				name       corev1.ResourceName
+				from       corev1.ResourceList
+			}{
+				{annotation.SidecarProxyCPU.Name, corev1.ResourceCPU, dp.Resources.Requests},
+				{annotation.SidecarProxyMemory.Name, corev1.ResourceMemory, dp.Resources.Requests},
+				{annotation.SidecarProxyCPULimit.Name, corev1.ResourceCPU, dp.Resources.Limits},
+				{annotation.SidecarProxyMemoryLimit.Name, corev1.ResourceMemory, dp.Resources.Limits},
+			} {
+				if q, f := r.from[r.name]; f {
+					annotations[r.annotation] = q.String()
+				}
+			}
+			for _, l := range []corev1.ResourceList{dp.Resources.Requests, dp.Resources.Limits} {
+				for name := range l {
+					if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+						return classParameters{}, fmt.Errorf("unsupported resource %q: only %s and %s are supported",
+							name, corev1.ResourceCPU, corev1.ResourceMemory)
+					}
+				}
+			}
+		}
+		if err := validateNodeSelector(dp.NodeSelector); err != nil {
+			return classParameters{}, err
+		}
+		res.scheduling = dp.SchedulingInput
+		res.hostNetwork = dp.HostNetwork
+		if dp.TopologySpreadConstraints != nil {
+			if err := validateTopologySpreadConstraints(dp.TopologySpreadConstraints); err != nil {
+				return classParameters{}, fmt.Errorf("invalid topologySpreadConstraints: %v", err)
+			}
+			res.topologySpreadConstraints = dp.TopologySpreadConstraints
+		}
+		for _, c := range []struct {
+			field string
+			name  string
+			dest  *string
+		}{
+			{"priorityClassName", dp.PriorityClassName, &res.priorityClassName},
+			{"runtimeClassName", dp.RuntimeClassName, &res.runtimeClassName},
+		} {
+			if c.name == "" {
+				continue
+			}
+			if err := validateClassName(c.name); err != nil {
+				return classParameters{}, fmt.Errorf("invalid %s: %v", c.field, err)
+			}
+			*c.dest = c.name
+		}
+	}
+	if as := spec.Autoscaling; as != nil {
+		if spec.Deployment != nil && spec.Deployment.Replicas != nil {
+			return classParameters{}, fmt.Errorf("replicas cannot be set when autoscaling is enabled")
+		}
+		if as.MaxReplicas <= 0 {
+			return classParameters{}, fmt.Errorf("autoscaling maxReplicas must be a positive integer, got %d", as.MaxReplicas)
+		}
+		annotations[gatewayAutoscalingMaxReplicas] = strconv.Itoa(int(as.MaxReplicas))
+		for _, s := range []struct {
+			annotation string
+			field      string
+			value      *int32
+		}{
+			{gatewayAutoscalingMinReplicas, "minReplicas", as.MinReplicas},
+			{gatewayAutoscalingTargetCPU, "targetCPUUtilizationPercentage", as.TargetCPUUtilizationPercentage},
+			{gatewayAutoscalingTargetMemory, "targetMemoryUtilizationPercentage", as.TargetMemoryUtilizationPercentage},
+			{gatewayAutoscalingTargetConnections, "targetActiveConnections", as.TargetActiveConnections},
+			{gatewayAutoscalingTargetRequests, "targetRequestsPerSecond", as.TargetRequestsPerSecond},
+		} {
+			if s.value == nil {
+				continue
+			}
+			if *s.value <= 0 {
+				return classParameters{}, fmt.Errorf("autoscaling %s must be a positive integer, got %d", s.field, *s.value)
+			}
+			annotations[s.annotation] = strconv.Itoa(int(*s.value))
+		}
+		if as.MinReplicas != nil && *as.MinReplicas > as.MaxReplicas {
+			return classParameters{}, fmt.Errorf("autoscaling minReplicas (%d) must not be greater than maxReplicas (%d)",
+				*as.MinReplicas, as.MaxReplicas)
+		}
+	}
+	if svc := spec.Service; svc != nil {
+		if svc.Type != "" {
+			annotations[serviceTypeOverride] = string(svc.Type)
+		}
+		if svc.ExternalTrafficPolicy != "" {
+			annotations[externalTrafficPolicy] = string(svc.ExternalTrafficPolicy)
+		}
+		res.serviceAnnotations = svc.Annotations
+	}
+	if sa := spec.ServiceAccount; sa != nil {
+		if err := validateServiceAccountAnnotations(sa.Annotations); err != nil {
+			return classParameters{}, fmt.Errorf("invalid serviceAccount: %v", err)
+		}
+		res.serviceAccountAnnotations = sa.Annotations
+	}
+	if len(annotations) > 0 {
+		res.annotations = annotations
+	}
+	return res, nil
+}
+
+// replicasAnnotations are the annotations configuring the replicas of a gateway. A Gateway setting any of them
+// replaces all those of its class, as the fixed replicas and the autoscaling are exclusive.
+var replicasAnnotations = []string{
+	gatewayReplicas,
+	gatewayAutoscalingMinReplicas,
+	gatewayAutoscalingMaxReplicas,
+	gatewayAutoscalingTargetCPU,
+	gatewayAutoscalingTargetMemory,
+	gatewayAutoscalingTargetConnections,
+	gatewayAutoscalingTargetRequests,
+}
+
+// withClassAnnotations returns the annotations of a gateway, defaulting to the annotations of its class.
+func withClassAnnotations(gwAnnotations, class map[string]string) map[string]string {
+	if len(class) == 0 {
+		return gwAnnotations
+	}
+	overridesReplicas := false
+	for _, k := range replicasAnnotations {
+		if _, f := gwAnnotations[k]; f {
+			overridesReplicas = true
+		}
+	}
+	res := make(map[string]string, len(gwAnnotations)+len(class))
+	for k, v := range class {
+		res[k] = v
+	}
+	if overridesReplicas {
+		for _, k := range replicasAnnotations {
+			delete(res, k)
+		}
+	}
+	for k, v := range gwAnnotations {
+		res[k] = v
+	}
+	return res
+}
+
+// mergeMaps merges the maps, the later maps taking precedence. It is nil if all the maps are empty.
+func mergeMaps(maps ...map[string]string) map[string]string {
+	var res map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if res == nil {
+				res = map[string]string{}
+			}
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,325 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestClassConfigParameters(t *testing.T) {
+	cases := []struct {
+		name      string
+		spec      GatewayClassConfigSpec
+		want      classParameters
+		wantError bool
+	}{
+		{
+			name: "empty",
+			want: classParameters{mode: DeploymentModeDeployment},
+		},
+		{
+			name: "deployment",
+			spec: GatewayClassConfigSpec{
+				Deployment: &GatewayClassDeployment{
+					Replicas: ptr.Of(int32(3)),
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+					SchedulingInput: SchedulingInput{NodeSelector: map[string]string{"pool": "ingress"}},
+				},
+				Service: &GatewayClassService{
+					Type:                  corev1.ServiceTypeNodePort,
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					Annotations:           map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+				},
+			},
+			want: classParameters{
+				mode: DeploymentModeDeployment,
+				annotations: map[string]string{
+					gatewayReplicas:                     "3",
+					"sidecar.istio.io/proxyCPU":         "500m",
+					"sidecar.istio.io/proxyMemoryLimit": "1Gi",
+					serviceTypeOverride:                 "NodePort",
+					externalTrafficPolicy:               "Local",
+				},
+				scheduling:         SchedulingInput{NodeSelector: map[string]string{"pool": "ingress"}},
+				serviceAnnotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			},
+		},
+		{
+			name: "autoscaling",
+			spec: GatewayClassConfigSpec{
+				Autoscaling: &GatewayClassAutoscaling{MinReplicas: ptr.Of(int32(2)), MaxReplicas: 5},
+			},
+			want: classParameters{
+				mode: DeploymentModeDeployment,
+				annotations: map[string]string{
+					gatewayAutoscalingMinReplicas: "2",
+					gatewayAutoscalingMaxReplicas: "5",
+				},
+			},
+		},
+		{
+			name: "autoscaling on connections and requests",
+			spec: GatewayClassConfigSpec{
+				Autoscaling: &GatewayClassAutoscaling{
+					MaxReplicas:             5,
+					TargetActiveConnections: ptr.Of(int32(1000)),
+					TargetRequestsPerSecond: ptr.Of(int32(500)),
+				},
+			},
+			want: classParameters{
+				mode: DeploymentModeDeployment,
+				annotations: map[string]string{
+					gatewayAutoscalingMaxReplicas:       "5",
+					gatewayAutoscalingTargetConnections: "1000",
+					gatewayAutoscalingTargetRequests:    "500",
+				},
+			},
+		},
+		{
+			name: "invalid autoscaling target connections",
+			spec: GatewayClassConfigSpec{
+				Autoscaling: &GatewayClassAutoscaling{MaxReplicas: 5, TargetActiveConnections: ptr.Of(int32(0))},
+			},
+			wantError: true,
+		},
+		{
+			name: "statefulset",
+			spec: GatewayClassConfigSpec{Mode: DeploymentModeStatefulSet, PerPodServices: true},
+			want: classParameters{mode: DeploymentModeStatefulSet, perPodServices: true},
+		},
+		{
+			name: "pod classes",
+			spec: GatewayClassConfigSpec{Deployment: &GatewayClassDeployment{
+				PriorityClassName: "system-cluster-critical",
+				RuntimeClassName:  "gvisor",
+			}},
+			want: classParameters{mode: DeploymentModeDeployment, priorityClassName: "system-cluster-critical", runtimeClassName: "gvisor"},
+		},
+		{
+			name: "host network",
+			spec: GatewayClassConfigSpec{Mode: DeploymentModeDaemonSet, Deployment: &GatewayClassDeployment{HostNetwork: true}},
+			want: classParameters{mode: DeploymentModeDaemonSet, hostNetwork: true},
+		},
+		{
+			name:      "invalid priority class name",
+			spec:      GatewayClassConfigSpec{Deployment: &GatewayClassDeployment{PriorityClassName: "High_Priority"}},
+			wantError: true,
+		},
+		{
+			name: "service account annotations",
+			spec: GatewayClassConfigSpec{ServiceAccount: &GatewayClassServiceAccount{
+				Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/gw"},
+			}},
+			want: classParameters{
+				mode:                      DeploymentModeDeployment,
+				serviceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/gw"},
+			},
+		},
+		{
+			name: "invalid service account annotation",
+			spec: GatewayClassConfigSpec{ServiceAccount: &GatewayClassServiceAccount{
+				Annotations: map[string]string{"-invalid-": "a"},
+			}},
+			wantError: true,
+		},
+		{
+			name: "network policy",
+			spec: GatewayClassConfigSpec{NetworkPolicy: true},
+			want: classParameters{mode: DeploymentModeDeployment, networkPolicy: true},
+		},
+		{
+			name:      "invalid mode",
+			spec:      GatewayClassConfigSpec{Mode: "ReplicaSet"},
+			wantError: true,
+		},
+		{
+			name:      "per pod services without statefulset",
+			spec:      GatewayClassConfigSpec{PerPodServices: true},
+			wantError: true,
+		},
+		{
+			name: "replicas and autoscaling",
+			spec: GatewayClassConfigSpec{
+				Deployment:  &GatewayClassDeployment{Replicas: ptr.Of(int32(3))},
+				Autoscaling: &GatewayClassAutoscaling{MaxReplicas: 5},
+			},
+			wantError: true,
+		},
+		{
+			name:      "min replicas greater than max",
+			spec:      GatewayClassConfigSpec{Autoscaling: &GatewayClassAutoscaling{MinReplicas: ptr.Of(int32(6)), MaxReplicas: 5}},
+			wantError: true,
+		},
+		{
+			name:      "missing max replicas",
+			spec:      GatewayClassConfigSpec{Autoscaling: &GatewayClassAutoscaling{}},
+			wantError: true,
+		},
+		{
+			name: "unsupported resource",
+			spec: GatewayClassConfigSpec{Deployment: &GatewayClassDeployment{Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+			}}},
+			wantError: true,
+		},
+		{
+			name: "invalid topology spread constraints",
+			spec: GatewayClassConfigSpec{Deployment: &GatewayClassDeployment{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1}},
+			}},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := classConfigParameters(tt.spec)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			// The parameters are unexported, and cannot be compared with assert.Equal
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithClassAnnotations(t *testing.T) {
+	class := map[string]string{
+		gatewayAutoscalingMaxReplicas: "5",
+		serviceTypeOverride:           "NodePort",
+	}
+	// The gateway annotations take precedence
+	assert.Equal(t, withClassAnnotations(map[string]string{serviceTypeOverride: "ClusterIP"}, class), map[string]string{
+		gatewayAutoscalingMaxReplicas: "5",
+		serviceTypeOverride:           "ClusterIP",
+	})
+	// Fixed replicas replace the autoscaling of the class
+	assert.Equal(t, withClassAnnotations(map[string]string{gatewayReplicas: "2"}, class), map[string]string{
+		gatewayReplicas:     "2",
+		serviceTypeOverride: "NodePort",
+	})
+}
+
+func TestTemplateInputClassAnnotations(t *testing.T) {
+	test.SetForTest(t, &features.EnableDualStack, true)
+	client := kube.NewFakeClient()
+	d := &DeploymentController{client: client, namespaces: kclient.New[*corev1.Namespace](client)}
+	client.RunAndWait(test.NewStop(t))
+	gw := v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gateway",
+			Namespace:   "default",
+			Annotations: map[string]string{gatewayProxyTag: "1.19.0"},
+		},
+		Spec: v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+	}
+	params := classParameters{annotations: map[string]string{
+		gatewayNameOverride:    "class-gateway",
+		ipFamilyPolicyOverride: "RequireDualStack",
+		ipFamiliesOverride:     "IPv4,IPv6",
+		gatewayProxyHub:        "gcr.io/istio-testing",
+		gatewayProxyTag:        "1.18.0",
+	}}
+	// All the settings read the class annotations as defaults of the gateway ones
+	input, err := d.templateInput(gw, params)
+	assert.NoError(t, err)
+	assert.Equal(t, input.DeploymentName, "class-gateway")
+	assert.Equal(t, input.IPFamilyPolicy, corev1.IPFamilyPolicyRequireDualStack)
+	assert.Equal(t, input.IPFamilies, []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol})
+	assert.Equal(t, input.ProxyImageOverride, ProxyImageInput{Hub: "gcr.io/istio-testing", Tag: "1.19.0"})
+
+	params.annotations[gatewayProxyHub] = ""
+	_, err = d.templateInput(gw, params)
+	assert.Equal(t, errors.Is(err, errInvalidProxyImage), true)
+}
+
+func TestGatewayClassConfigChange(t *testing.T) {
+	c := kube.NewFakeClient()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	writes := make(chan string, 10)
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		if g == gvr.Service {
+			writes <- string(data)
+		}
+		return nil
+	}
+	stop := test.NewStop(t)
+	clienttest.Wrap(t, d.gatewayClasses).Create(&v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "configured"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Group:     v1beta1.Group(GatewayClassConfigGVR.Group),
+				Kind:      GatewayClassConfigKind,
+				Name:      "params",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	})
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	clienttest.Wrap(t, d.gateways).Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       v1beta1.GatewaySpec{GatewayClassName: "configured"},
+	})
+	// The CRD is not installed yet, nothing is rendered
+	assert.ChannelIsEmpty(t, writes)
+
+	configs := c.Dynamic().Resource(GatewayClassConfigGVR).Namespace("istio-system")
+	cfg := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": GatewayClassConfigGVR.GroupVersion().String(),
+		"kind":       GatewayClassConfigKind,
+		"metadata":   map[string]any{"name": "params", "namespace": "istio-system"},
+		"spec":       map[string]any{"service": map[string]any{"type": "NodePort"}},
+	}}
+	if _, err := configs.Create(context.Background(), cfg, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	d.onCRDEvent(classConfigCRDName)
+	assert.Equal(t, strings.Contains(assert.ChannelHasItem(t, writes), `"type":"NodePort"`), true)
+
+	// Changing the parameters re-renders the gateways of the class
+	cfg.Object["spec"] = map[string]any{"service": map[string]any{"type": "ClusterIP"}}
+	if _, err := configs.Update(context.Background(), cfg, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, strings.Contains(assert.ChannelHasItem(t, writes), `"type":"ClusterIP"`), true)
+}
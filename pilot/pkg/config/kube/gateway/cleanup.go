@@ -0,0 +1,158 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	istiolog "istio.io/pkg/log"
+)
+
+const (
+	// gatewayCleanupFinalizer is set on the managed Gateways, so the resources generated for them are deleted with
+	// them, reporting the progress of the deletion, rather than left to the garbage collection of their owner
+	// references.
+	gatewayCleanupFinalizer = "gateway.istio.io/resources-cleanup"
+	// gatewayConditionResourcesDeleted reports the progress of the deletion of the resources of a deleted Gateway.
+	gatewayConditionResourcesDeleted = "gateway.istio.io/ResourcesDeleted"
+)
+
+// deleter is a function that abstracts the deletion of the generated resources, like patcher.
+type deleter func(gvr schema.GroupVersionResource, name string, namespace string) error
+
+// generatedResource identifies a resource generated for a gateway.
+type generatedResource struct {
+	gvr       schema.GroupVersionResource
+	name      string
+	namespace string
+}
+
+// ensureCleanupFinalizer adds the cleanup finalizer to the gateway, if missing.
+func (d *DeploymentController) ensureCleanupFinalizer(gw gateway.Gateway) error {
+	if slices.Contains(gw.Finalizers, gatewayCleanupFinalizer) {
+		return nil
+	}
+	gw = *gw.DeepCopy()
+	gw.Finalizers = append(gw.Finalizers, gatewayCleanupFinalizer)
+	_, err := d.gateways.Update(&gw)
+	return err
+}
+
+// cleanup deletes the resources generated for a deleted gateway, then removes its cleanup finalizer. The resources
+// are found by rendering the templates of the gateway again, so those with overridden names are found as well. Only
+// those with the managed label and an owner reference to the gateway are deleted, so the resources the gateway
+// reuses, like the ServiceAccount of the gatewaySAOverride annotation, are left. If they can no longer be rendered,
+// e.g. because the class was deleted, they are left to the garbage collection of their owner references.
+func (d *DeploymentController) cleanup(log *istiolog.Scope, gw gateway.Gateway) error {
+	if !slices.Contains(gw.Finalizers, gatewayCleanupFinalizer) {
+		return nil
+	}
+	if others := d.sharingGateways(gw); len(others) > 0 {
+		// The resources are still used by the other gateways sharing the deployment
+		log.Infof("leaving the resources shared with %d gateways", len(others))
+	} else if rendered, err := d.generatedResources(gw); err != nil {
+		log.Warnf("cannot find the resources of the deleted gateway, leaving them to garbage collection: %v", err)
+	} else {
+		resources := make([]generatedResource, 0, len(rendered))
+		for _, r := range rendered {
+			if d.existingGeneratedFor(r, gw) {
+				resources = append(resources, r)
+			}
+		}
+		log.Infof("deleting %d resources of the deleted gateway", len(resources))
+		if err := d.setCleanupCondition(gw, "Deleting", fmt.Sprintf("Deleting %d resources", len(resources))); err != nil {
+			log.Warnf("failed to report the cleanup progress: %v", err)
+		}
+		for _, r := range resources {
+			if err := d.deleter(r.gvr, r.name, r.namespace); err != nil && !kerrors.IsNotFound(err) {
+				err = fmt.Errorf("delete %v %v/%v: %v", r.gvr.Resource, r.namespace, r.name, err)
+				d.event(gw, corev1.EventTypeWarning, eventResourcesDeleteFailed, "Failed to %v", err)
+				if serr := d.setCleanupCondition(gw, "DeletionFailed", err.Error()); serr != nil {
+					log.Warnf("failed to report the cleanup failure: %v", serr)
+				}
+				return err
+			}
+		}
+	}
+
+	gw = *gw.DeepCopy()
+	i := slices.Index(gw.Finalizers, gatewayCleanupFinalizer)
+	gw.Finalizers = slices.Delete(gw.Finalizers, i, i+1)
+	if _, err := d.gateways.Update(&gw); err != nil {
+		return fmt.Errorf("remove cleanup finalizer: %v", err)
+	}
+	log.Info("gateway resources deleted")
+	return nil
+}
+
+// generatedResources renders the templates of the gateway and returns the resources they generate.
+func (d *DeploymentController) generatedResources(gw gateway.Gateway) ([]generatedResource, error) {
+	gi, f := d.classInfo(string(gw.Spec.GatewayClassName))
+	if !f {
+		return nil, fmt.Errorf("unknown GatewayClass %v", gw.Spec.GatewayClassName)
+	}
+	params, err := d.classParametersOf(gi)
+	if err != nil {
+		return nil, err
+	}
+	input, err := d.templateInput(gw, params)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := d.render(gi, params, input)
+	if err != nil {
+		return nil, err
+	}
+	return renderedResources(rendered, gw.Namespace)
+}
+
+// renderedResources returns the resources of the rendered templates, applied in the namespace of the gateway.
+func renderedResources(rendered []string, namespace string) ([]generatedResource, error) {
+	res := make([]generatedResource, 0, len(rendered))
+	for _, t := range rendered {
+		data := map[string]any{}
+		if err := yaml.Unmarshal([]byte(t), &data); err != nil {
+			return nil, err
+		}
+		us := unstructured.Unstructured{Object: data}
+		gvr, err := generatedResourceGVR(us)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, generatedResource{gvr: gvr, name: us.GetName(), namespace: namespace})
+	}
+	return res, nil
+}
+
+// setCleanupCondition reports the progress of the cleanup of the deleted gateway in its status.
+func (d *DeploymentController) setCleanupCondition(gw gateway.Gateway, reason, message string) error {
+	return d.patchConditions(gw, []metav1.Condition{{
+		Type:               gatewayConditionResourcesDeleted,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: gw.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}})
+}
@@ -0,0 +1,114 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/util/sets"
+)
+
+func TestGatewayCleanup(t *testing.T) {
+	test.SetForTest(t, &features.EnableGatewayCleanupFinalizer, true)
+	owned := metav1.ObjectMeta{
+		Namespace: "default",
+		Labels:    map[string]string{constants.ManagedGatewayLabel: "true"},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: gvk.KubernetesGateway.GroupVersion(),
+			Kind:       gvk.KubernetesGateway.Kind,
+			Name:       "gw",
+		}},
+	}
+	named := func(meta metav1.ObjectMeta, name string) metav1.ObjectMeta {
+		meta.Name = name
+		return meta
+	}
+	c := kube.NewFakeClient(
+		&appsv1.Deployment{ObjectMeta: named(owned, "custom-name")},
+		&corev1.Service{ObjectMeta: named(owned, "custom-name")},
+		// The ServiceAccount of the gatewaySAOverride annotation is not owned by the gateway
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-sa",
+			Namespace: "default",
+			Labels:    map[string]string{constants.ManagedGatewayLabel: "true"},
+		}},
+	)
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	conditions := make(chan string, 10)
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		if g == gvr.KubernetesGateway && len(subresources) > 0 && strings.Contains(string(data), gatewayConditionResourcesDeleted) {
+			conditions <- string(data)
+		}
+		return nil
+	}
+	deleted := make(chan string, 10)
+	d.deleter = func(g schema.GroupVersionResource, name string, namespace string) error {
+		deleted <- fmt.Sprintf("%s/%s/%s", g.Resource, namespace, name)
+		return nil
+	}
+	stop := test.NewStop(t)
+	gws := clienttest.Wrap(t, d.gateways)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	gws.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gw",
+			Namespace:   "default",
+			Annotations: map[string]string{gatewayNameOverride: "custom-name", gatewaySAOverride: "custom-sa"},
+		},
+		Spec: v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+	})
+	hasFinalizer := func() bool {
+		gw := gws.Get("gw", "default")
+		return gw != nil && slices.Contains(gw.Finalizers, gatewayCleanupFinalizer)
+	}
+	assert.EventuallyEqual(t, hasFinalizer, true)
+	assert.ChannelIsEmpty(t, deleted)
+
+	// The fake client does not handle finalizers, so mark the gateway as deleted.
+	gw := gws.Get("gw", "default").DeepCopy()
+	gw.DeletionTimestamp = &metav1.Time{}
+	gws.Update(gw)
+	assert.EventuallyEqual(t, hasFinalizer, false)
+	got := sets.New[string]()
+	for i := 0; i < 2; i++ {
+		got.Insert(assert.ChannelHasItem(t, deleted))
+	}
+	assert.Equal(t, got, sets.New(
+		"deployments/default/custom-name",
+		"services/default/custom-name",
+	))
+	assert.ChannelIsEmpty(t, deleted)
+	// The progress is reported in the status
+	assert.Equal(t, strings.Contains(assert.ChannelHasItem(t, conditions), `"reason":"Deleting"`), true)
+}
@@ -50,6 +50,18 @@ const (
 	gatewayTLSTerminateModeKey   = "gateway.istio.io/tls-terminate-mode"
 	gatewayNameOverride          = "gateway.istio.io/name-override"
 	gatewaySAOverride            = "gateway.istio.io/service-account"
+	serviceTypeOverride          = "networking.istio.io/service-type"
+	ipFamilyPolicyOverride       = "networking.istio.io/ip-family-policy"
+	ipFamiliesOverride           = "networking.istio.io/ip-families"
+	externalTrafficPolicy        = "networking.istio.io/external-traffic-policy"
+	healthCheckNodePort          = "networking.istio.io/health-check-node-port"
+	sessionAffinity              = "networking.istio.io/session-affinity"
+	sessionAffinityTimeout       = "networking.istio.io/session-affinity-timeout"
+
+	// jwtClaimHeaderPrefix is the header name prefix used in HTTPRoute header matches to route on the claims of the
+	// validated JWT, e.g. "request.auth.claims.group". Gateway API does not allow "@" in header names, so this is
+	// translated to the "@request.auth.claims." header VirtualService uses for the same purpose.
+	jwtClaimHeaderPrefix = "request.auth.claims."
 )
 
 // KubernetesResources stores all inputs to our conversion
@@ -301,7 +313,7 @@ func buildHTTPVirtualServices(
 		vs := &istio.HTTPRoute{}
 		// Auto-name the route. If upstream defines an explicit name, will use it instead
 		// The position within the route is unique
-		vs.Name = fmt.Sprintf("%s.%s.%d", obj.Namespace, obj.Name, pos)
+		vs.Name = model.HTTPRouteRuleName(obj.Namespace, obj.Name, pos)
 
 		for _, match := range r.Matches {
 			uri, err := createURIMatch(match)
@@ -1168,13 +1180,17 @@ func createHeadersMatch(match k8s.HTTPRouteMatch) (map[string]*istio.StringMatch
 		if header.Type != nil {
 			tp = *header.Type
 		}
+		name := string(header.Name)
+		if strings.HasPrefix(strings.ToLower(name), jwtClaimHeaderPrefix) {
+			name = "@" + name
+		}
 		switch tp {
 		case k8sbeta.HeaderMatchExact:
-			res[string(header.Name)] = &istio.StringMatch{
+			res[name] = &istio.StringMatch{
 				MatchType: &istio.StringMatch_Exact{Exact: header.Value},
 			}
 		case k8sbeta.HeaderMatchRegularExpression:
-			res[string(header.Name)] = &istio.StringMatch{
+			res[name] = &istio.StringMatch{
 				MatchType: &istio.StringMatch_Regex{Regex: header.Value},
 			}
 		default:
@@ -1581,7 +1597,7 @@ func IsManaged(gw *k8s.GatewaySpec) bool {
 
 func extractGatewayServices(r KubernetesResources, kgw *k8s.GatewaySpec, obj config.Config) ([]string, []string) {
 	if IsManaged(kgw) {
-		return []string{fmt.Sprintf("%s.%s.svc.%v", getDefaultName(obj.Name, kgw), obj.Namespace, r.Domain)}, nil
+		return []string{fmt.Sprintf("%s.%s.svc.%v", managedResourceName(obj.Name, obj.Annotations, kgw), obj.Namespace, r.Domain)}, nil
 	}
 	gatewayServices := []string{}
 	skippedAddresses := []string{}
@@ -0,0 +1,200 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/inject"
+)
+
+const (
+	// TemplateConfigMapLabel selects the ConfigMaps the deployment controller watches for class parameters. A
+	// GatewayClass can only reference a ConfigMap with this label, to avoid watching every ConfigMap of the cluster.
+	TemplateConfigMapLabel = "gateway.istio.io/deployment-template"
+	// templateConfigMapKey is the key of the go template in the ConfigMap. If unset, the builtin template of the
+	// class is used.
+	templateConfigMapKey = "template"
+	// modeConfigMapKey is the key of the deployment mode in the ConfigMap, DeploymentModeDeployment if unset.
+	modeConfigMapKey = "mode"
+	// perPodServicesConfigMapKey is the key enabling a Service per pod in DeploymentModeStatefulSet, "false" if unset.
+	perPodServicesConfigMapKey = "perPodServices"
+	// schedulingConfigMapKey is the key of the scheduling constraints of the pods of the gateways of the class, in the
+	// format of the gatewayScheduling annotation.
+	schedulingConfigMapKey = "scheduling"
+	// topologySpreadConstraintsConfigMapKey is the key of the topology spread constraints of the pods of the gateways
+	// of the class. If unset, the replicas are spread across zones.
+	topologySpreadConstraintsConfigMapKey = "topologySpreadConstraints"
+	// networkPolicyConfigMapKey is the key enabling a NetworkPolicy restricting the ingress traffic of the pods of the
+	// gateways of the class to their listener ports and status port, "false" if unset.
+	networkPolicyConfigMapKey = "networkPolicy"
+	// priorityClassNameConfigMapKey is the key of the PriorityClass of the pods of the gateways of the class, so they
+	// are not the first to be evicted under node pressure.
+	priorityClassNameConfigMapKey = "priorityClassName"
+	// runtimeClassNameConfigMapKey is the key of the RuntimeClass of the pods of the gateways of the class, to run
+	// them in a sandbox like gVisor or Kata Containers.
+	runtimeClassNameConfigMapKey = "runtimeClassName"
+	// serviceAccountAnnotationsConfigMapKey is the key of the annotations of the ServiceAccounts of the gateways of the
+	// class, in JSON or YAML, like the cloud IAM identity of the pods.
+	serviceAccountAnnotationsConfigMapKey = "serviceAccountAnnotations"
+	// hostNetworkConfigMapKey is the key running the pods of the gateways of the class in the network namespace of
+	// their nodes, to expose them on the addresses of the nodes without a load balancer, "false" if unset.
+	hostNetworkConfigMapKey = "hostNetwork"
+)
+
+// The modes in which the gateways of a class are deployed.
+const (
+	// DeploymentModeDeployment deploys the gateways as a Deployment behind a Service, of type LoadBalancer by default.
+	DeploymentModeDeployment = "Deployment"
+	// DeploymentModeDaemonSet deploys the gateways as a DaemonSet binding the listener ports on the host of every node,
+	// for host network ingress. The Service is only used in the cluster.
+	DeploymentModeDaemonSet = "DaemonSet"
+	// DeploymentModeStatefulSet deploys the gateways as a StatefulSet, giving the pods a stable name and network
+	// identity, for the protocols whose clients are pinned to a gateway instance. Each pod can also be exposed by its
+	// own Service.
+	DeploymentModeStatefulSet = "StatefulSet"
+)
+
+// classParameters are the parameters of a GatewayClass, read from the ConfigMap or the GatewayClassConfig referenced by
+// its parametersRef. A class references one or the other, so they are never merged: the GatewayClassConfig holds the
+// settings of the ConfigMap keys but the template, which only a ConfigMap can hold, and those of the Gateway
+// annotations like the replicas. The settings of a gateway
+// are resolved in this order of precedence: the annotations of the Gateway, the parameters of its class, the
+// annotations of its namespace, and the defaults of the template.
+type classParameters struct {
+	// template replaces the builtin template of the class, if set.
+	template *template.Template
+	mode     string
+	// perPodServices exposes each pod of the StatefulSet with its own Service.
+	perPodServices bool
+	// scheduling holds the scheduling constraints of the pods, which the gateways can override.
+	scheduling SchedulingInput
+	// topologySpreadConstraints replaces the default topology spread constraints of the pods, if not nil.
+	topologySpreadConstraints []corev1.TopologySpreadConstraint
+	// priorityClassName and runtimeClassName are the PriorityClass and RuntimeClass of the pods, if not empty.
+	priorityClassName string
+	runtimeClassName  string
+	// networkPolicy renders a NetworkPolicy restricting the ingress traffic of the pods to the ports of the gateway.
+	networkPolicy bool
+	// hostNetwork runs the pods in the network namespace of their nodes, binding the listener ports on the nodes.
+	hostNetwork bool
+	// annotations are the defaults of the annotations of the gateways of the class.
+	annotations map[string]string
+	// serviceAnnotations are added to the annotations of the gateway Services.
+	serviceAnnotations map[string]string
+	// serviceAccountAnnotations are the defaults of the annotations of the gateway ServiceAccounts.
+	serviceAccountAnnotations map[string]string
+}
+
+// parametersConfigMap returns the ConfigMap holding the parameters of the class, referenced by the parametersRef of
+// the GatewayClass. ok is false if the class has no parameters.
+func parametersConfigMap(gc *gateway.GatewayClass) (ref types.NamespacedName, ok bool) {
+	p := gc.Spec.ParametersRef
+	if p == nil || string(p.Group) != gvk.ConfigMap.Group || string(p.Kind) != gvk.ConfigMap.Kind || p.Namespace == nil {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: string(*p.Namespace), Name: p.Name}, true
+}
+
+// readClassParameters reads the parameters of the ConfigMap referenced by a GatewayClass.
+func (d *DeploymentController) readClassParameters(ref types.NamespacedName) (classParameters, error) {
+	cm := d.configMaps.Get(ref.Name, ref.Namespace)
+	if cm == nil {
+		return classParameters{}, fmt.Errorf("parameters ConfigMap %v not found, or missing the %s label", ref, TemplateConfigMapLabel)
+	}
+	res := classParameters{mode: DeploymentModeDeployment}
+	if mode, f := cm.Data[modeConfigMapKey]; f {
+		if mode != DeploymentModeDeployment && mode != DeploymentModeDaemonSet && mode != DeploymentModeStatefulSet {
+			return classParameters{}, fmt.Errorf("invalid mode %q in ConfigMap %v: must be %s, %s or %s",
+				mode, ref, DeploymentModeDeployment, DeploymentModeDaemonSet, DeploymentModeStatefulSet)
+		}
+		res.mode = mode
+	}
+	if raw, f := cm.Data[perPodServicesConfigMapKey]; f {
+		perPodServices, err := strconv.ParseBool(raw)
+		if err != nil {
+			return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", perPodServicesConfigMapKey, ref, err)
+		}
+		if perPodServices && res.mode != DeploymentModeStatefulSet {
+			return classParameters{}, fmt.Errorf("%s in ConfigMap %v requires the %s mode", perPodServicesConfigMapKey, ref, DeploymentModeStatefulSet)
+		}
+		res.perPodServices = perPodServices
+	}
+	if raw, f := cm.Data[schedulingConfigMapKey]; f {
+		scheduling, err := parseScheduling(raw)
+		if err != nil {
+			return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", schedulingConfigMapKey, ref, err)
+		}
+		res.scheduling = scheduling
+	}
+	if raw, f := cm.Data[topologySpreadConstraintsConfigMapKey]; f {
+		constraints, err := parseTopologySpreadConstraints(raw)
+		if err != nil {
+			return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", topologySpreadConstraintsConfigMapKey, ref, err)
+		}
+		res.topologySpreadConstraints = constraints
+	}
+	for _, c := range []struct {
+		key  string
+		dest *string
+	}{
+		{priorityClassNameConfigMapKey, &res.priorityClassName},
+		{runtimeClassNameConfigMapKey, &res.runtimeClassName},
+	} {
+		if raw, f := cm.Data[c.key]; f {
+			if err := validateClassName(raw); err != nil {
+				return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", c.key, ref, err)
+			}
+			*c.dest = raw
+		}
+	}
+	for _, c := range []struct {
+		key  string
+		dest *bool
+	}{
+		{networkPolicyConfigMapKey, &res.networkPolicy},
+		{hostNetworkConfigMapKey, &res.hostNetwork},
+	} {
+		if raw, f := cm.Data[c.key]; f {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", c.key, ref, err)
+			}
+			*c.dest = v
+		}
+	}
+	if raw, f := cm.Data[serviceAccountAnnotationsConfigMapKey]; f {
+		annotations, err := parseServiceAccountAnnotations(raw)
+		if err != nil {
+			return classParameters{}, fmt.Errorf("invalid %s in ConfigMap %v: %v", serviceAccountAnnotationsConfigMapKey, ref, err)
+		}
+		res.serviceAccountAnnotations = annotations
+	}
+	if raw, f := cm.Data[templateConfigMapKey]; f {
+		templates, err := inject.ParseTemplates(inject.RawTemplates{ref.String(): raw})
+		if err != nil {
+			return classParameters{}, fmt.Errorf("invalid template in ConfigMap %v: %v", ref, err)
+		}
+		res.template = templates[ref.String()]
+	}
+	return res, nil
+}
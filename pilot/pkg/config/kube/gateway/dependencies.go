@@ -0,0 +1,94 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/kube/controllers"
+)
+
+// dependency is an input of a controller, declaring the outputs to recompute on a change of one of its objects.
+// The controllers declaring their inputs this way expose their dependency graph, and the recomputations each input
+// triggered, for debugging.
+type dependency struct {
+	input string
+	// trigger describes the outputs recomputed on a change of the input.
+	trigger string
+	// outputs returns the keys of the outputs to recompute on a change of the object, nil if the input is not an
+	// object.
+	outputs func(o controllers.Object) []types.NamespacedName
+
+	events         *atomic.Uint64
+	recomputations *atomic.Uint64
+}
+
+func newDependency(input, trigger string, outputs func(o controllers.Object) []types.NamespacedName) *dependency {
+	return &dependency{
+		input:          input,
+		trigger:        trigger,
+		outputs:        outputs,
+		events:         atomic.NewUint64(0),
+		recomputations: atomic.NewUint64(0),
+	}
+}
+
+// handler returns the handler of the changes of the input, queueing the outputs for recomputation.
+func (d *dependency) handler(q controllers.Queue) func(o controllers.Object) {
+	return func(o controllers.Object) {
+		d.events.Inc()
+		for _, key := range d.outputs(o) {
+			d.recomputations.Inc()
+			q.Add(key)
+		}
+	}
+}
+
+func (d *dependency) stats() model.ControllerInput {
+	return model.ControllerInput{
+		Input:          d.input,
+		Trigger:        d.trigger,
+		Events:         d.events.Load(),
+		Recomputations: d.recomputations.Load(),
+	}
+}
+
+// self returns the key of the object, for the inputs which are the outputs themselves.
+func self(o controllers.Object) []types.NamespacedName {
+	return []types.NamespacedName{config.NamespacedName(o)}
+}
+
+// owners returns the keys of the owners of the object of the group and kind, like controllers.EnqueueForParentHandler.
+func owners(kind config.GroupVersionKind) func(o controllers.Object) []types.NamespacedName {
+	return func(o controllers.Object) []types.NamespacedName {
+		var res []types.NamespacedName
+		for _, ref := range o.GetOwnerReferences() {
+			refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				log.Errorf("could not parse OwnerReference api version %q: %v", ref.APIVersion, err)
+				continue
+			}
+			if refGV.Group == kind.Group && ref.Kind == kind.Kind {
+				// Reference doesn't have namespace, but its always same-namespace, so use objects
+				res = append(res, types.NamespacedName{Namespace: o.GetNamespace(), Name: ref.Name})
+			}
+		}
+		return res
+	}
+}
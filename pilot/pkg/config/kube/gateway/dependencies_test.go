@@ -0,0 +1,91 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestDeploymentControllerDependencies(t *testing.T) {
+	c := kube.NewFakeClient()
+	var injectionChanged func()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {
+		injectionChanged = fn
+	})
+	d.patcher = func(schema.GroupVersionResource, string, string, []byte, ...string) error {
+		return nil
+	}
+	stop := test.NewStop(t)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	inputs := func() map[string]model.ControllerInput {
+		res := map[string]model.ControllerInput{}
+		for _, in := range d.Dependencies().Inputs {
+			res[in.Input] = in
+		}
+		return res
+	}
+	recomputations := func(input string) func() uint64 {
+		return func() uint64 {
+			return inputs()[input].Recomputations
+		}
+	}
+
+	clienttest.Wrap(t, d.gateways).Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+	})
+	assert.EventuallyEqual(t, recomputations("Gateway"), uint64(1))
+
+	// A namespace recomputes its gateways.
+	clienttest.Wrap(t, d.namespaces).Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	assert.EventuallyEqual(t, recomputations("Namespace"), uint64(1))
+
+	// A service recomputes its owner gateway only.
+	services := clienttest.Wrap(t, d.services)
+	services.Create(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}})
+	services.Create(&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      "gw",
+		Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       "Gateway",
+			Name:       "gw",
+		}},
+	}})
+	assert.EventuallyEqual(t, func() uint64 { return inputs()["Service"].Events }, uint64(2))
+	assert.Equal(t, inputs()["Service"].Recomputations, uint64(1))
+
+	injectionChanged()
+	assert.Equal(t, inputs()["InjectionTemplate"], model.ControllerInput{
+		Input:          "InjectionTemplate",
+		Trigger:        "all the gateways",
+		Events:         1,
+		Recomputations: 1,
+	})
+	assert.Equal(t, len(d.Dependencies().Inputs), 12)
+}
@@ -17,10 +17,14 @@ package gateway
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
+	"go.uber.org/atomic"
+	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,24 +32,33 @@ import (
 	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8s "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
 	"sigs.k8s.io/yaml"
 
 	meshapi "istio.io/api/mesh/v1alpha1"
+	credentials "istio.io/istio/pilot/pkg/credentials/kube"
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/model/kstatus"
 	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/kube/inject"
 	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/watcher/crdwatcher"
 	"istio.io/istio/pkg/test/util/tmpl"
 	"istio.io/istio/pkg/test/util/yml"
 	"istio.io/istio/pkg/util/sets"
 	istiolog "istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
 )
 
 // DeploymentController implements a controller that materializes a Gateway into an in cluster gateway proxy
@@ -72,17 +85,51 @@ import (
 //   - SSA using standard API types doesn't work well either: https://github.com/kubernetes-sigs/controller-runtime/issues/1669
 //   - This leaves YAML templates, converted to unstructured types and Applied with the dynamic client.
 type DeploymentController struct {
-	client         kube.Client
-	clusterID      cluster.ID
-	queue          controllers.Queue
-	patcher        patcher
-	gateways       kclient.Client[*gateway.Gateway]
-	gatewayClasses kclient.Client[*gateway.GatewayClass]
+	client    kube.Client
+	clusterID cluster.ID
+	// systemNamespace is the namespace of istiod, the only namespace the parameters ConfigMaps are read from.
+	systemNamespace string
+	queue           controllers.Queue
+	patcher         patcher
+	deleter         deleter
+	gateways        kclient.Client[*gateway.Gateway]
+	gatewayClasses  kclient.Client[*gateway.GatewayClass]
 
 	injectConfig    func() inject.WebhookConfig
 	deployments     kclient.Client[*appsv1.Deployment]
+	daemonSets      kclient.Client[*appsv1.DaemonSet]
+	statefulSets    kclient.Client[*appsv1.StatefulSet]
 	services        kclient.Client[*corev1.Service]
 	serviceAccounts kclient.Client[*corev1.ServiceAccount]
+	namespaces      kclient.Client[*corev1.Namespace]
+	// configMaps holds the custom templates referenced by the GatewayClasses.
+	configMaps kclient.Client[*corev1.ConfigMap]
+	// secrets holds the TLS Secrets referenced by the listeners, nil unless features.ValidateGatewayCertificateRefs is
+	// enabled.
+	secrets kclient.Client[*corev1.Secret]
+	// referenceGrants holds the ReferenceGrants permitting the listeners to reference the Secrets of other namespaces,
+	// nil unless features.ValidateGatewayCertificateRefs is enabled.
+	referenceGrants kclient.Client[*k8s.ReferenceGrant]
+	// pods holds the pods of the gateways, reporting whether they pulled the proxy image.
+	pods kclient.Client[*corev1.Pod]
+	// classConfigs holds the GatewayClassConfigs referenced by the GatewayClasses, nil until their CRD is installed.
+	classConfigs       atomic.Pointer[kclient.Untyped]
+	classConfigDep     *dependency
+	crdWatcher         *crdwatcher.Controller
+	classConfigCRD     chan struct{}
+	classConfigCRDOnce sync.Once
+
+	// prunable lists the kinds of the generated resources deleted once the templates no longer render them.
+	prunable []prunableResource
+
+	// dependencies are the inputs of the controller.
+	dependencies []*dependency
+	// managed counts the managed gateways by class, for the metrics.
+	managed *managedGateways
+
+	// recorder records events on the Gateways, sent to the API server by the broadcaster while the controller runs.
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
 }
 
 // Patcher is a function that abstracts patching logic. This is largely because client-go fakes do not handle patching
@@ -96,6 +143,11 @@ type classInfo struct {
 	description string
 	// The key in the templates to use for this class
 	templates string
+	// parameters, if set, is the ConfigMap holding the parameters of this class, like a template to use instead of
+	// templates.
+	parameters *types.NamespacedName
+	// classConfig, if set, is the GatewayClassConfig holding the parameters of this class.
+	classConfig *types.NamespacedName
 	// reportGatewayClassStatus, if enabled, will set the GatewayClass to be accepted when it is first created.
 	// nolint: unused
 	reportGatewayClassStatus bool
@@ -103,6 +155,75 @@ type classInfo struct {
 
 var classInfos = getClassInfos()
 
+var (
+	gatewayClassTag = monitoring.MustCreateLabel("class")
+	kindTag         = monitoring.MustCreateLabel("kind")
+
+	gatewayApplyErrors = monitoring.NewSum(
+		"pilot_gateway_deployment_apply_errors_total",
+		"Resources generated for the managed gateways which failed to be applied, by class of the gateway and kind of "+
+			"the resource.",
+		monitoring.WithLabels(gatewayClassTag, kindTag),
+	)
+
+	gatewayRenderFailures = monitoring.NewSum(
+		"pilot_gateway_deployment_render_failures_total",
+		"Templates of the managed gateways which failed to be rendered, by class of the gateway.",
+		monitoring.WithLabels(gatewayClassTag),
+	)
+
+	managedGatewayCount = monitoring.NewGauge(
+		"pilot_gateway_deployment_managed_gateways",
+		"Gateways managed by the gateway deployment controller, by class.",
+		monitoring.WithLabels(gatewayClassTag),
+	)
+)
+
+func init() {
+	monitoring.MustRegister(gatewayApplyErrors, gatewayRenderFailures, managedGatewayCount)
+}
+
+// managedGateways counts the managed gateways by class, reporting the counts on managedGatewayCount.
+type managedGateways struct {
+	mu      sync.Mutex
+	classes map[types.NamespacedName]string
+	counts  map[string]int
+}
+
+func newManagedGateways() *managedGateways {
+	return &managedGateways{classes: map[types.NamespacedName]string{}, counts: map[string]int{}}
+}
+
+// set records the gateway as managed with the class, or as not managed if the class is empty.
+func (m *managedGateways) set(gw types.NamespacedName, class string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, f := m.classes[gw]
+	if f && old == class {
+		return
+	}
+	if f {
+		delete(m.classes, gw)
+		m.counts[old]--
+		managedGatewayCount.With(gatewayClassTag.Value(old)).Record(float64(m.counts[old]))
+		if m.counts[old] == 0 {
+			delete(m.counts, old)
+		}
+	}
+	if class != "" {
+		m.classes[gw] = class
+		m.counts[class]++
+		managedGatewayCount.With(gatewayClassTag.Value(class)).Record(float64(m.counts[class]))
+	}
+}
+
+// count returns the number of managed gateways of the class.
+func (m *managedGateways) count(class string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[class]
+}
+
 func getClassInfos() map[string]classInfo {
 	m := map[string]classInfo{
 		DefaultClassName: {
@@ -132,14 +253,15 @@ var knownControllers = func() sets.String {
 
 // NewDeploymentController constructs a DeploymentController and registers required informers.
 // The controller will not start until Run() is called.
-func NewDeploymentController(client kube.Client, clusterID cluster.ID,
+func NewDeploymentController(client kube.Client, clusterID cluster.ID, systemNamespace string,
 	webhookConfig func() inject.WebhookConfig, injectionHandler func(fn func()),
 ) *DeploymentController {
 	gateways := kclient.New[*gateway.Gateway](client)
 	gatewayClasses := kclient.New[*gateway.GatewayClass](client)
 	dc := &DeploymentController{
-		client:    client,
-		clusterID: clusterID,
+		client:          client,
+		clusterID:       clusterID,
+		systemNamespace: systemNamespace,
 		patcher: func(gvr schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
 			c := client.Dynamic().Resource(gvr).Namespace(namespace)
 			t := true
@@ -149,53 +271,203 @@ func NewDeploymentController(client kube.Client, clusterID cluster.ID,
 			}, subresources...)
 			return err
 		},
+		deleter: func(gvr schema.GroupVersionResource, name string, namespace string) error {
+			return client.Dynamic().Resource(gvr).Namespace(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+		},
 		gateways:       gateways,
 		gatewayClasses: gatewayClasses,
 		injectConfig:   webhookConfig,
+		crdWatcher:     crdwatcher.NewController(client),
+		classConfigCRD: make(chan struct{}),
+		managed:        newManagedGateways(),
 	}
+	dc.broadcaster, dc.recorder = newEventRecorder()
 	dc.queue = controllers.NewQueue("gateway deployment",
 		controllers.WithReconciler(dc.Reconcile),
-		controllers.WithMaxAttempts(5))
+		controllers.WithMaxAttempts(features.GatewayDeploymentControllerMaxAttempts),
+		controllers.WithItemExponentialBackoff(features.GatewayDeploymentControllerRetryBaseDelay,
+			features.GatewayDeploymentControllerMaxRetryDelay),
+		controllers.WithOverallRateLimit(features.GatewayDeploymentControllerQPS, features.GatewayDeploymentControllerBurst),
+		controllers.WithMaxDelay(features.GatewayDeploymentControllerMaxRetryDelay),
+		controllers.WithKind(kind.KubernetesGateway),
+		controllers.WithCluster(clusterID),
+		controllers.WithClassOf(dc.gatewayClassOf))
+	metrics := dc.queue.Metrics()
 
-	// Set up a handler that will add the parent Gateway object onto the queue.
+	// The inputs of the controller, and the gateways recomputed on their changes.
 	// The queue will only handle Gateway objects; if child resources (Service, etc) are updated we re-add
 	// the Gateway to the queue and reconcile the state of the world.
-	handler := controllers.ObjectHandler(controllers.EnqueueForParentHandler(dc.queue, gvk.KubernetesGateway))
+	allGateways := func(controllers.Object) []types.NamespacedName {
+		return gatewayKeys(dc.gateways.List(metav1.NamespaceAll, klabels.Everything()))
+	}
+	gatewayDep := newDependency(kind.KubernetesGateway.String(),
+		"the gateway, the gateways sharing its deployment, and the other gateways in host network mode",
+		func(o controllers.Object) []types.NamespacedName {
+			gw := o.(*gateway.Gateway)
+			return append(append(self(o), dc.sharingGatewayKeys(gw)...), dc.hostNetworkGatewayKeys(gw)...)
+		})
+	gatewayClassDep := newDependency(kind.GatewayClass.String(), "the gateways of the class", func(o controllers.Object) []types.NamespacedName {
+		var res []types.NamespacedName
+		for _, g := range dc.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+			if string(g.Spec.GatewayClassName) == o.GetName() {
+				res = append(res, config.NamespacedName(g))
+			}
+		}
+		return res
+	})
+	serviceDep := newDependency(kind.Service.String(), "the owner gateway", owners(gvk.KubernetesGateway))
+	deploymentDep := newDependency(kind.Deployment.String(), "the owner gateway", owners(gvk.KubernetesGateway))
+	daemonSetDep := newDependency(kind.DaemonSet.String(), "the owner gateway", owners(gvk.KubernetesGateway))
+	statefulSetDep := newDependency(kind.StatefulSet.String(), "the owner gateway", owners(gvk.KubernetesGateway))
+	serviceAccountDep := newDependency(kind.ServiceAccount.String(), "the owner gateway", owners(gvk.KubernetesGateway))
+	podDep := newDependency(kind.Pod.String(), "the gateway of the pod, and the gateways sharing its deployment", dc.podGateways)
+	// Namespaces may hold defaults for the gateways within them, so requeue all gateways in the namespace on change.
+	namespaceDep := newDependency(kind.Namespace.String(), "the gateways of the namespace", func(o controllers.Object) []types.NamespacedName {
+		return gatewayKeys(dc.gateways.List(o.GetName(), klabels.Everything()))
+	})
+	// On injection template change, requeue all gateways
+	injectionDep := newDependency("InjectionTemplate", "all the gateways", allGateways)
+	// On custom template change, requeue the gateways of the classes using it
+	configMapDep := newDependency(kind.ConfigMap.String(), "the gateways of the classes using the template",
+		dc.gatewaysOfClassesWith(parametersConfigMap))
+	// Likewise for the GatewayClassConfigs
+	dc.classConfigDep = newDependency(GatewayClassConfigKind, "the gateways of the classes using the parameters",
+		dc.gatewaysOfClassesWith(parametersClassConfig))
+	dc.dependencies = []*dependency{
+		gatewayDep, gatewayClassDep, serviceDep, deploymentDep, daemonSetDep, statefulSetDep, serviceAccountDep, podDep,
+		namespaceDep, injectionDep, configMapDep, dc.classConfigDep,
+	}
+	// On TLS Secret change, requeue the gateways whose listeners reference it, to provision them once it is valid
+	// Likewise for the ReferenceGrants, which may permit the references to the Secrets of their namespace
+	var secretDep, referenceGrantDep *dependency
+	if features.ValidateGatewayCertificateRefs {
+		secretDep = newDependency(kind.Secret.String(), "the gateways referencing the secret", dc.gatewaysReferencingSecret)
+		referenceGrantDep = newDependency(kind.ReferenceGrant.String(), "the gateways referencing the secrets of its namespace",
+			dc.gatewaysReferencingGrantNamespace)
+		dc.dependencies = append(dc.dependencies, secretDep, referenceGrantDep)
+	}
 
 	// Use the full informer, since we are already fetching all Services for other purposes
 	// If we somehow stop watching Services in the future we can add a label selector like below.
 	dc.services = kclient.New[*corev1.Service](client)
-	dc.services.AddEventHandler(handler)
+	dc.services.AddEventHandler(metrics.Handler(kind.Service, controllers.ObjectHandler(serviceDep.handler(dc.queue))))
 
 	// For Deployments, this is the only controller watching. We can filter to just the deployments we care about
 	dc.deployments = kclient.NewFiltered[*appsv1.Deployment](client, kclient.Filter{LabelSelector: constants.ManagedGatewayLabel})
-	dc.deployments.AddEventHandler(handler)
+	dc.deployments.AddEventHandler(metrics.Handler(kind.Deployment, controllers.ObjectHandler(deploymentDep.handler(dc.queue))))
+
+	// Likewise for the DaemonSets of the gateways deployed in DaemonSet mode
+	dc.daemonSets = kclient.NewFiltered[*appsv1.DaemonSet](client, kclient.Filter{LabelSelector: constants.ManagedGatewayLabel})
+	dc.daemonSets.AddEventHandler(metrics.Handler(kind.DaemonSet, controllers.ObjectHandler(daemonSetDep.handler(dc.queue))))
+
+	// And the StatefulSets of the gateways deployed in StatefulSet mode
+	dc.statefulSets = kclient.NewFiltered[*appsv1.StatefulSet](client, kclient.Filter{LabelSelector: constants.ManagedGatewayLabel})
+	dc.statefulSets.AddEventHandler(metrics.Handler(kind.StatefulSet, controllers.ObjectHandler(statefulSetDep.handler(dc.queue))))
 
 	dc.serviceAccounts = kclient.New[*corev1.ServiceAccount](client)
-	dc.serviceAccounts.AddEventHandler(handler)
+	dc.serviceAccounts.AddEventHandler(metrics.Handler(kind.ServiceAccount, controllers.ObjectHandler(serviceAccountDep.handler(dc.queue))))
 
-	gateways.AddEventHandler(controllers.ObjectHandler(dc.queue.AddObject))
-	gatewayClasses.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) {
-		for _, g := range dc.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
-			if string(g.Spec.GatewayClassName) == o.GetName() {
-				dc.queue.AddObject(g)
-			}
-		}
-	}))
+	dc.namespaces = kclient.New[*corev1.Namespace](client)
+	dc.namespaces.AddEventHandler(metrics.Handler(kind.Namespace, controllers.ObjectHandler(namespaceDep.handler(dc.queue))))
 
-	// On injection template change, requeue all gateways
+	// Only the ConfigMaps labeled as templates may be referenced by a GatewayClass, so we do not watch all ConfigMaps
+	dc.configMaps = kclient.NewFiltered[*corev1.ConfigMap](client, kclient.Filter{LabelSelector: TemplateConfigMapLabel})
+	dc.configMaps.AddEventHandler(metrics.Handler(kind.ConfigMap, controllers.ObjectHandler(configMapDep.handler(dc.queue))))
+
+	// The Secrets are already watched for the credentials of the gateways, so we share the informer of their filter
+	if secretDep != nil {
+		dc.secrets = kclient.NewFiltered[*corev1.Secret](client, kclient.Filter{FieldSelector: credentials.SecretsFieldSelector()})
+		dc.secrets.AddEventHandler(metrics.Handler(kind.Secret, controllers.ObjectHandler(secretDep.handler(dc.queue))))
+		dc.referenceGrants = kclient.New[*k8s.ReferenceGrant](client)
+		dc.referenceGrants.AddEventHandler(metrics.Handler(kind.ReferenceGrant,
+			controllers.ObjectHandler(referenceGrantDep.handler(dc.queue))))
+	}
+
+	// The pods report whether the proxy image is pulled, so we only watch those of the gateways
+	dc.pods = kclient.NewFiltered[*corev1.Pod](client, kclient.Filter{LabelSelector: constants.GatewayNameLabel})
+	dc.pods.AddEventHandler(metrics.Handler(kind.Pod, controllers.ObjectHandler(podDep.handler(dc.queue))))
+
+	// The generated resources no longer rendered are found in the informers above, or in the metadata of their kind
+	dc.prunable = dc.newPrunableResources(client)
+
+	// The GatewayClassConfigs are watched once their CRD is installed, see runClassConfigs
+	dc.crdWatcher.AddCallBack(dc.onCRDEvent)
+
+	gateways.AddEventHandler(metrics.Handler(kind.KubernetesGateway, controllers.ObjectHandler(gatewayDep.handler(dc.queue))))
+	gatewayClasses.AddEventHandler(metrics.Handler(kind.GatewayClass, controllers.ObjectHandler(gatewayClassDep.handler(dc.queue))))
+
+	onInjectionChange := injectionDep.handler(dc.queue)
 	injectionHandler(func() {
-		for _, gw := range dc.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
-			dc.queue.AddObject(gw)
-		}
+		onInjectionChange(nil)
 	})
 
 	return dc
 }
 
+// Dependencies returns the dependency graph of the controller, with the recomputations of the gateways each input
+// triggered.
+func (d *DeploymentController) Dependencies() model.ControllerDependencies {
+	res := model.ControllerDependencies{
+		Controller: "gateway deployment",
+		Output:     kind.KubernetesGateway.String(),
+	}
+	for _, dep := range d.dependencies {
+		res.Inputs = append(res.Inputs, dep.stats())
+	}
+	for _, f := range d.queue.FailedItems() {
+		res.Failed = append(res.Failed, model.FailedReconcile{Output: f.Key, Error: f.Error, Attempts: f.Attempts, Time: f.Time})
+	}
+	return res
+}
+
+// gatewaysOfClassesWith returns the outputs of the parameters of the classes: the gateways of the classes whose
+// parametersRef references the changed object.
+func (d *DeploymentController) gatewaysOfClassesWith(
+	parametersRef func(gc *gateway.GatewayClass) (types.NamespacedName, bool),
+) func(o controllers.Object) []types.NamespacedName {
+	return func(o controllers.Object) []types.NamespacedName {
+		var res []types.NamespacedName
+		for _, gc := range d.gatewayClasses.List(metav1.NamespaceAll, klabels.Everything()) {
+			if ref, ok := parametersRef(gc); !ok || ref != config.NamespacedName(o) {
+				continue
+			}
+			for _, g := range d.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+				if string(g.Spec.GatewayClassName) == gc.Name {
+					res = append(res, config.NamespacedName(g))
+				}
+			}
+		}
+		return res
+	}
+}
+
+func gatewayKeys(gws []*gateway.Gateway) []types.NamespacedName {
+	res := make([]types.NamespacedName, 0, len(gws))
+	for _, gw := range gws {
+		res = append(res, config.NamespacedName(gw))
+	}
+	return res
+}
+
 func (d *DeploymentController) Run(stop <-chan struct{}) {
+	d.startRecording()
+	d.crdWatcher.Run(stop)
+	go d.runClassConfigs(stop)
 	d.queue.Run(stop)
-	controllers.ShutdownAll(d.deployments, d.services, d.serviceAccounts, d.gateways, d.gatewayClasses)
+	controllers.ShutdownAll(d.deployments, d.daemonSets, d.statefulSets, d.services, d.serviceAccounts, d.namespaces, d.configMaps, d.gateways, d.gatewayClasses)
+	if d.secrets != nil {
+		controllers.ShutdownAll(d.secrets)
+	}
+	d.broadcaster.Shutdown()
+}
+
+// gatewayClassOf returns the class of the Gateway, for the metrics of its reconciles.
+func (d *DeploymentController) gatewayClassOf(req types.NamespacedName) string {
+	gw := d.gateways.Get(req.Name, req.Namespace)
+	if gw == nil {
+		return ""
+	}
+	return string(gw.Spec.GatewayClassName)
 }
 
 // Reconcile takes in the name of a Gateway and ensures the cluster is in the desired state
@@ -207,33 +479,51 @@ func (d *DeploymentController) Reconcile(req types.NamespacedName) error {
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
+		d.managed.set(req, "")
 		return nil
 	}
+	if gw.DeletionTimestamp != nil {
+		d.managed.set(req, "")
+		return d.cleanup(log, *gw)
+	}
 
 	gc := d.gatewayClasses.Get(string(gw.Spec.GatewayClassName), "")
 	if gc != nil {
 		// We found the gateway class, but we do not implement it. Skip
 		if !knownControllers.Contains(string(gc.Spec.ControllerName)) {
+			d.managed.set(req, "")
 			return nil
 		}
 	} else {
 		// Didn't find gateway class, and it wasn't an implicitly known one
 		if _, f := classInfos[string(gw.Spec.GatewayClassName)]; !f {
+			d.managed.set(req, "")
 			return nil
 		}
 	}
 
 	// Matched class, reconcile it
+	class := string(gw.Spec.GatewayClassName)
+	if IsManaged(&gw.Spec) {
+		d.managed.set(req, class)
+	} else {
+		d.managed.set(req, "")
+	}
 	return d.configureIstioGateway(log, *gw)
 }
 
 func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gateway.Gateway) error {
 	// If user explicitly sets addresses, we are assuming they are pointing to an existing deployment.
 	// We will not manage it in this case
-	gi, f := classInfos[string(gw.Spec.GatewayClassName)]
+	gi, f := d.classInfo(string(gw.Spec.GatewayClassName))
 	if !f {
 		return nil
 	}
+	params, err := d.classParametersOf(gi)
+	if err != nil {
+		d.provisioningFailed(log, gw, eventInvalidConfiguration, err.Error())
+		return err
+	}
 	if !IsManaged(&gw.Spec) {
 		log.Debug("skip disabled gateway")
 		return nil
@@ -245,24 +535,24 @@ func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gat
 	}
 	log.Info("reconciling")
 
-	defaultName := getDefaultName(gw.Name, &gw.Spec)
-	deploymentName := defaultName
-	if nameOverride, exists := gw.Annotations[gatewayNameOverride]; exists {
-		deploymentName = nameOverride
+	if err := validateSharedDeployment(gw); err != nil {
+		d.provisioningFailed(log, gw, eventInvalidConfiguration, err.Error())
+		return err
 	}
-
-	gatewaySA := defaultName
-	if saOverride, exists := gw.Annotations[gatewaySAOverride]; exists {
-		gatewaySA = saOverride
+	// The gateways sharing a deployment render the same resources, merging their listeners
+	input, err := d.templateInput(d.sharedGateway(gw), params)
+	if err != nil {
+		reason := eventInvalidConfiguration
+		if errors.Is(err, errInvalidProxyImage) {
+			reason = eventInvalidProxyImage
+		}
+		d.provisioningFailed(log, gw, reason, err.Error())
+		return err
 	}
-
-	input := TemplateInput{
-		Gateway:        &gw,
-		DeploymentName: deploymentName,
-		ServiceAccount: gatewaySA,
-		Ports:          extractServicePorts(gw),
-		ClusterID:      d.clusterID.String(),
-		KubeVersion122: kube.IsAtLeastVersion(d.client, 22),
+	if features.EnableGatewayCleanupFinalizer {
+		if err := d.ensureCleanupFinalizer(gw); err != nil {
+			return fmt.Errorf("add cleanup finalizer: %v", err)
+		}
 	}
 
 	if overwriteControllerVersion {
@@ -273,20 +563,178 @@ func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gat
 	} else {
 		log.Debugf("controller version existing=%v, no action needed", existingControllerVersion)
 	}
-	rendered, err := d.render(gi.templates, input)
+	var conditions []metav1.Condition
+	if features.ValidateGatewayCertificateRefs {
+		// The listeners with unresolved certificate references are not served, but the others are, so the gateway is
+		// still provisioned. The fixes of the references requeue the gateway.
+		cond := d.certificateRefsCondition(gw)
+		if cond.Status == kstatus.StatusFalse {
+			d.event(gw, corev1.EventTypeWarning, eventInvalidCertificateRef, "%s", cond.Message)
+		}
+		conditions = append(conditions, cond)
+	}
+	if params.hostNetwork {
+		// The pods of a gateway conflicting with an older gateway could not be scheduled, so we wait for the older
+		// gateway to be deleted or moved to other nodes, which requeues the gateway.
+		cond := d.hostPortsCondition(gw, input)
+		if cond.Status == kstatus.StatusFalse {
+			d.provisioningFailed(log, gw, eventHostPortConflict,
+				fmt.Sprintf("Waiting for the host ports to be available: %s", cond.Message), cond)
+			return nil
+		}
+		conditions = append(conditions, cond)
+	}
+	rendered, err := d.render(gi, params, input)
 	if err != nil {
+		gatewayRenderFailures.With(gatewayClassTag.Value(string(gw.Spec.GatewayClassName))).Increment()
+		d.provisioningFailed(log, gw, eventTemplateRenderFailed, fmt.Sprintf("Failed to render the template: %v", err))
 		return fmt.Errorf("failed to render template: %v", err)
 	}
+	existed := d.workloadExists(gw.Namespace, input)
 	for _, t := range rendered {
-		if err := d.apply(gi.controller, t); err != nil {
+		if err := d.apply(gi.controller, gw.Namespace, t); err != nil {
+			gatewayApplyErrors.With(gatewayClassTag.Value(string(gw.Spec.GatewayClassName)),
+				kindTag.Value(renderedKind(t))).Increment()
+			d.provisioningFailed(log, gw, eventApplyFailed, fmt.Sprintf("Failed to apply the generated resources: %v", err))
 			return fmt.Errorf("apply failed: %v", err)
 		}
 	}
+	resources, err := renderedResources(rendered, gw.Namespace)
+	if err != nil {
+		return fmt.Errorf("parse the generated resources: %v", err)
+	}
+	if err := d.prune(log, gw, resources); err != nil {
+		d.provisioningFailed(log, gw, eventApplyFailed, fmt.Sprintf("Failed to delete the resources no longer generated: %v", err))
+		return fmt.Errorf("prune failed: %v", err)
+	}
+	if !existed {
+		d.event(gw, corev1.EventTypeNormal, eventDeploymentCreated, "Created %s %s/%s", input.workloadKind(), gw.Namespace, input.DeploymentName)
+	}
+	if err := d.provisioned(gw, input, len(rendered), conditions...); err != nil {
+		return fmt.Errorf("update gateway status: %v", err)
+	}
 
 	log.Info("gateway updated")
 	return nil
 }
 
+// templateInput builds the input of the template of the gateway from its annotations, and the annotations of its
+// namespace.
+func (d *DeploymentController) templateInput(gw gateway.Gateway, params classParameters) (TemplateInput, error) {
+	// The settings of the class are defaults for those of the gateway annotations
+	annotations := withClassAnnotations(gw.Annotations, params.annotations)
+
+	defaultName := managedResourceName(gw.Name, gw.Annotations, &gw.Spec)
+	deploymentName := defaultName
+	if nameOverride, exists := annotations[gatewayNameOverride]; exists {
+		deploymentName = nameOverride
+	}
+
+	gatewaySA := defaultName
+	saOverride, saOverridden := annotations[gatewaySAOverride]
+	if saOverridden {
+		gatewaySA = saOverride
+	}
+
+	var nsAnnotations map[string]string
+	if ns := d.namespaces.Get(gw.Namespace, ""); ns != nil {
+		nsAnnotations = ns.Annotations
+	}
+	autoscaling, err := extractAutoscaling(annotations, nsAnnotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid autoscaling configuration: %v", err)
+	}
+	replicas, err := extractReplicas(annotations, autoscaling)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid replicas configuration: %v", err)
+	}
+	if params.mode == DeploymentModeDaemonSet && (autoscaling != nil || replicas != nil) {
+		return TemplateInput{}, fmt.Errorf("autoscaling and replicas cannot be configured in %s mode", DeploymentModeDaemonSet)
+	}
+	// The clients of a StatefulSet are pinned to its pods, which are only added or removed with the replicas.
+	if params.mode == DeploymentModeStatefulSet && autoscaling != nil {
+		return TemplateInput{}, fmt.Errorf("autoscaling cannot be configured in %s mode, set the %s annotation instead",
+			DeploymentModeStatefulSet, gatewayReplicas)
+	}
+	var podServices []string
+	if params.perPodServices {
+		podServices = statefulSetPodNames(deploymentName, replicas)
+	}
+	serviceType, err := extractServiceType(annotations, params.mode, params.hostNetwork)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid service configuration: %v", err)
+	}
+	ipFamilyPolicy, ipFamilies, err := extractIPFamilies(annotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid service configuration: %v", err)
+	}
+	serviceTraffic, err := extractServiceTraffic(annotations, serviceType)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid service configuration: %v", err)
+	}
+	resources, err := extractResources(annotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid resources configuration: %v", err)
+	}
+	pdb, err := extractPodDisruptionBudget(annotations, nsAnnotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid pod disruption budget configuration: %v", err)
+	}
+	infrastructure, err := extractInfrastructure(annotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid infrastructure configuration: %v", err)
+	}
+	scheduling, err := extractScheduling(annotations, params.scheduling)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid scheduling configuration: %v", err)
+	}
+	image, err := extractProxyImage(annotations, infrastructure.Annotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("%w: %v", errInvalidProxyImage, err)
+	}
+	saAnnotations, err := extractServiceAccountAnnotations(annotations, params.serviceAccountAnnotations)
+	if err != nil {
+		return TemplateInput{}, fmt.Errorf("invalid service account configuration: %v", err)
+	}
+	ports := extractServicePorts(gw)
+
+	return TemplateInput{
+		Gateway:                  &gw,
+		DeploymentName:           deploymentName,
+		Owners:                   d.gatewayOwners(gw),
+		ServiceAccount:           gatewaySA,
+		ServiceAccountOverridden: saOverridden,
+		Ports:                    ports,
+		ServiceType:              serviceType,
+		ClusterID:                d.clusterID.String(),
+		KubeVersion122:           kube.IsAtLeastVersion(d.client, 22),
+		Autoscaling:              autoscaling,
+		Replicas:                 replicas,
+		DaemonSet:                params.mode == DeploymentModeDaemonSet,
+		StatefulSet:              params.mode == DeploymentModeStatefulSet,
+		HostNetwork:              params.hostNetwork,
+		HostPrivilegedPorts:      params.hostNetwork && hasPrivilegedPort(ports),
+		PodServices:              podServices,
+		Resources:                resources,
+
+		PodDisruptionBudget: pdb,
+		Infrastructure:      infrastructure,
+		ServiceAnnotations:  mergeMaps(params.serviceAnnotations, extractServiceAnnotations(gw)),
+		IPFamilyPolicy:      ipFamilyPolicy,
+		IPFamilies:          ipFamilies,
+		ServiceTraffic:      serviceTraffic,
+		Scheduling:          scheduling,
+
+		TopologySpreadConstraints: extractTopologySpreadConstraints(gw.Name, params.mode, params.topologySpreadConstraints),
+		NetworkPolicy:             params.networkPolicy,
+		PodMonitor:                features.EnableGatewayPodMonitor,
+		ProxyImageOverride:        image,
+		PriorityClassName:         params.priorityClassName,
+		RuntimeClassName:          params.runtimeClassName,
+		ServiceAccountAnnotations: saAnnotations,
+	}, nil
+}
+
 const (
 	// ControllerVersionAnnotation is an annotation added to the Gateway by the controller specifying
 	// the "controller version". The original intent of this was to work around
@@ -345,19 +793,71 @@ type derivedInput struct {
 	Values      map[string]any
 }
 
-func (d *DeploymentController) render(templateName string, mi TemplateInput) ([]string, error) {
+// classParametersOf returns the parameters of the class, the defaults if the class has none.
+func (d *DeploymentController) classParametersOf(gi classInfo) (classParameters, error) {
+	if gi.classConfig != nil {
+		return d.readClassConfig(*gi.classConfig)
+	}
+	if gi.parameters == nil {
+		return classParameters{mode: DeploymentModeDeployment}, nil
+	}
+	if gi.parameters.Namespace != d.systemNamespace {
+		// The templates are rendered with the permissions of istiod, so only its administrators may provide them
+		return classParameters{}, fmt.Errorf("invalid GatewayClass parameters: ConfigMap %v must be in the %s namespace",
+			*gi.parameters, d.systemNamespace)
+	}
+	params, err := d.readClassParameters(*gi.parameters)
+	if err != nil {
+		return classParameters{}, fmt.Errorf("invalid GatewayClass parameters: %v", err)
+	}
+	return params, nil
+}
+
+// classInfo returns the information of the class. A GatewayClass referencing a parameters ConfigMap or a
+// GatewayClassConfig in its parametersRef is handled with these parameters, whether or not it is one of the builtin
+// classes.
+func (d *DeploymentController) classInfo(name string) (classInfo, bool) {
+	gi, f := classInfos[name]
+	gc := d.gatewayClasses.Get(name, "")
+	if gc == nil {
+		return gi, f
+	}
+	if ref, ok := parametersConfigMap(gc); ok {
+		gi.parameters = &ref
+	} else if ref, ok := parametersClassConfig(gc); ok {
+		gi.classConfig = &ref
+	} else {
+		return gi, f
+	}
+	gi.controller = string(gc.Spec.ControllerName)
+	if !f {
+		// Not a builtin class, use the builtin template of its controller unless the parameters replace it.
+		for _, builtin := range classInfos {
+			if builtin.controller == gi.controller {
+				gi.templates = builtin.templates
+			}
+		}
+	}
+	return gi, true
+}
+
+func (d *DeploymentController) render(gi classInfo, params classParameters, mi TemplateInput) ([]string, error) {
 	cfg := d.injectConfig()
 
-	template := cfg.Templates[templateName]
+	template := cfg.Templates[gi.templates]
+	if params.template != nil {
+		template = params.template
+	}
 	if template == nil {
-		return nil, fmt.Errorf("no %q template defined", templateName)
+		return nil, fmt.Errorf("no %q template defined", gi.templates)
 	}
 	input := derivedInput{
 		TemplateInput: mi,
-		ProxyImage: inject.ProxyImage(
+		ProxyImage: proxyImage(
 			cfg.Values.Struct(),
 			cfg.MeshConfig.GetDefaultConfig().GetImage(),
 			mi.Annotations,
+			mi.ProxyImageOverride,
 		),
 		ProxyConfig: cfg.MeshConfig.GetDefaultConfig(),
 		MeshConfig:  cfg.MeshConfig,
@@ -379,21 +879,27 @@ func (d *DeploymentController) setGatewayControllerVersion(gws gateway.Gateway)
 	return d.patcher(gvr.KubernetesGateway, gws.GetName(), gws.GetNamespace(), []byte(patch))
 }
 
-// apply server-side applies a template to the cluster.
-func (d *DeploymentController) apply(controller string, yml string) error {
+// apply server-side applies a template to the cluster, in the namespace of the gateway. Only the kinds of
+// generatedKinds may be rendered.
+func (d *DeploymentController) apply(controller string, namespace string, yml string) error {
 	data := map[string]any{}
 	err := yaml.Unmarshal([]byte(yml), &data)
 	if err != nil {
 		return err
 	}
 	us := unstructured.Unstructured{Object: data}
+	if gk := us.GroupVersionKind().GroupKind(); !generatedKinds.Contains(gk) {
+		return fmt.Errorf("%v %v cannot be generated for a gateway", gk, us.GetName())
+	}
+	// The templates cannot create resources in the namespaces of other gateways, or in the istiod namespace
+	us.SetNamespace(namespace)
 	// set managed-by label
 	clabel := strings.ReplaceAll(controller, "/", "-")
 	err = unstructured.SetNestedField(us.Object, clabel, "metadata", "labels", constants.ManagedGatewayLabel)
 	if err != nil {
 		return err
 	}
-	gvr, err := controllers.UnstructuredToGVR(us)
+	gvr, err := generatedResourceGVR(us)
 	if err != nil {
 		return err
 	}
@@ -409,13 +915,189 @@ func (d *DeploymentController) apply(controller string, yml string) error {
 	return nil
 }
 
+// generatedKinds are the kinds of the resources the templates may render.
+var generatedKinds = sets.New(
+	gvk.ServiceAccount.Kubernetes().GroupKind(),
+	gvk.Deployment.Kubernetes().GroupKind(),
+	gvk.DaemonSet.Kubernetes().GroupKind(),
+	gvk.StatefulSet.Kubernetes().GroupKind(),
+	gvk.Service.Kubernetes().GroupKind(),
+	gvk.HorizontalPodAutoscaler.Kubernetes().GroupKind(),
+	gvk.PodDisruptionBudget.Kubernetes().GroupKind(),
+	gvk.NetworkPolicy.Kubernetes().GroupKind(),
+	schema.GroupKind{Group: PodMonitorGVR.Group, Kind: podMonitorKind},
+)
+
+// renderedKind returns the kind of a rendered resource, for the metrics.
+func renderedKind(yml string) string {
+	meta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal([]byte(yml), &meta); err != nil || meta.Kind == "" {
+		return "unknown"
+	}
+	return meta.Kind
+}
+
 type TemplateInput struct {
 	*gateway.Gateway
 	DeploymentName string
+	// Owners are the gateways owning the generated resources, all the gateways of a shared deployment.
+	Owners         []OwnerInput
 	ServiceAccount string
-	Ports          []corev1.ServicePort
+	// ServiceAccountOverridden is set when the ServiceAccount is named by the gatewaySAOverride annotation. It is
+	// then not owned by the gateway, so it is not deleted with it.
+	ServiceAccountOverridden bool
+	Ports                    []corev1.ServicePort
+	// ServiceType is the type of the gateway Service.
+	ServiceType    corev1.ServiceType
 	ClusterID      string
 	KubeVersion122 bool
+	// Autoscaling configures a HorizontalPodAutoscaler for the gateway. If nil, autoscaling is disabled.
+	Autoscaling *AutoscalingInput
+	// Replicas is the number of replicas of the gateway Deployment. If nil, it is not set.
+	Replicas *int32
+	// DaemonSet deploys the gateway as a DaemonSet binding the listener ports on the hosts, instead of a Deployment.
+	DaemonSet bool
+	// StatefulSet deploys the gateway as a StatefulSet, with stable pod names, instead of a Deployment.
+	StatefulSet bool
+	// HostNetwork runs the gateway pods in the network namespace of their nodes, binding the listener ports on the
+	// nodes.
+	HostNetwork bool
+	// HostPrivilegedPorts is set for the gateways in host network mode with listener ports below 1024, whose proxy
+	// runs as root to bind them, as the network sysctl allowing it otherwise is not allowed with the host network.
+	HostPrivilegedPorts bool
+	// PodServices are the names of the pods of the StatefulSet exposed by their own Service, of the same name.
+	PodServices []string
+	// Resources holds the resources of the gateway proxy container. If nil, the template defaults are used.
+	Resources *ResourcesInput
+	// PodDisruptionBudget configures a PodDisruptionBudget for the gateway. If nil, none is rendered.
+	PodDisruptionBudget *PodDisruptionBudgetInput
+	// Infrastructure holds the labels and annotations added to the generated resources.
+	Infrastructure InfrastructureInput
+	// ServiceAnnotations are added to the annotations of the gateway Service, like the hostnames of the external-dns
+	// integration.
+	ServiceAnnotations map[string]string
+	// ServiceAccountAnnotations are added to the annotations of the gateway ServiceAccount, like the cloud IAM
+	// identity of the pods.
+	ServiceAccountAnnotations map[string]string
+	// IPFamilyPolicy is the IP family policy of the gateway Services, the default of the cluster if empty.
+	IPFamilyPolicy corev1.IPFamilyPolicy
+	// IPFamilies are the IP families of the gateway Services, the default of the cluster if empty.
+	IPFamilies []corev1.IPFamily
+	// ServiceTraffic configures the routing of the traffic of the gateway Services, like the preservation of the
+	// source IP of the clients.
+	ServiceTraffic ServiceTrafficInput
+	// Scheduling holds the scheduling constraints of the gateway pods, like a node selector.
+	Scheduling SchedulingInput
+	// TopologySpreadConstraints spread the gateway pods across the topology domains, like the zones.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	// NetworkPolicy renders a NetworkPolicy only allowing the ingress traffic of the gateway pods on the Ports.
+	NetworkPolicy bool
+	// PodMonitor renders a Prometheus Operator PodMonitor scraping the metrics of the gateway pods.
+	PodMonitor bool
+	// ProxyImageOverride overrides the hub and tag of the proxy image of the gateway, rendered as the ProxyImage of
+	// the derivedInput.
+	ProxyImageOverride ProxyImageInput
+	// PriorityClassName is the PriorityClass of the gateway pods, the default of the cluster if empty.
+	PriorityClassName string
+	// RuntimeClassName is the RuntimeClass of the gateway pods, the default runtime if empty.
+	RuntimeClassName string
+}
+
+// workloadKind returns the kind of the workload of the gateway.
+func (t TemplateInput) workloadKind() string {
+	switch {
+	case t.DaemonSet:
+		return gvk.DaemonSet.Kind
+	case t.StatefulSet:
+		return gvk.StatefulSet.Kind
+	}
+	return gvk.Deployment.Kind
+}
+
+// workloadExists returns whether the workload of the gateway exists.
+func (d *DeploymentController) workloadExists(namespace string, input TemplateInput) bool {
+	switch {
+	case input.DaemonSet:
+		return d.daemonSets.Get(input.DeploymentName, namespace) != nil
+	case input.StatefulSet:
+		return d.statefulSets.Get(input.DeploymentName, namespace) != nil
+	}
+	return d.deployments.Get(input.DeploymentName, namespace) != nil
+}
+
+// statefulSetPodNames returns the names of the pods of the gateway StatefulSet, of 1 replica if unset.
+func statefulSetPodNames(name string, replicas *int32) []string {
+	n := int32(1)
+	if replicas != nil {
+		n = *replicas
+	}
+	res := make([]string, 0, n)
+	for i := int32(0); i < n; i++ {
+		res = append(res, fmt.Sprintf("%s-%d", name, i))
+	}
+	return res
+}
+
+// extractServiceType returns the type of the gateway Service: the type of the serviceTypeOverride annotation if set,
+// else ClusterIP in DaemonSet mode or host network mode, where the listeners are bound on the hosts, and LoadBalancer
+// otherwise.
+func extractServiceType(gwAnnotations map[string]string, mode string, hostNetwork bool) (corev1.ServiceType, error) {
+	v, f := gwAnnotations[serviceTypeOverride]
+	if !f {
+		if mode == DeploymentModeDaemonSet || hostNetwork {
+			return corev1.ServiceTypeClusterIP, nil
+		}
+		return corev1.ServiceTypeLoadBalancer, nil
+	}
+	switch t := corev1.ServiceType(v); t {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid %v annotation %q: must be %s, %s or %s", serviceTypeOverride, v,
+		corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+}
+
+// extractIPFamilies returns the IP family policy and the IP families of the gateway Services, set by the
+// ipFamilyPolicyOverride and ipFamiliesOverride annotations for dual-stack gateways, which require
+// features.EnableDualStack. The families are a comma separated list, the first being the family of the cluster IP of
+// the Services.
+func extractIPFamilies(gwAnnotations map[string]string) (corev1.IPFamilyPolicy, []corev1.IPFamily, error) {
+	var policy corev1.IPFamilyPolicy
+	if v, f := gwAnnotations[ipFamilyPolicyOverride]; f {
+		switch p := corev1.IPFamilyPolicy(v); p {
+		case corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+			policy = p
+		default:
+			return "", nil, fmt.Errorf("invalid %v annotation %q: must be %s, %s or %s", ipFamilyPolicyOverride, v,
+				corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack)
+		}
+	}
+	var families []corev1.IPFamily
+	if v, f := gwAnnotations[ipFamiliesOverride]; f {
+		for _, s := range strings.Split(v, ",") {
+			family := corev1.IPFamily(strings.TrimSpace(s))
+			if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+				return "", nil, fmt.Errorf("invalid %v annotation %q: %q must be %s or %s", ipFamiliesOverride, v,
+					family, corev1.IPv4Protocol, corev1.IPv6Protocol)
+			}
+			if slices.Contains(families, family) {
+				return "", nil, fmt.Errorf("invalid %v annotation %q: duplicate family %s", ipFamiliesOverride, v, family)
+			}
+			families = append(families, family)
+		}
+	}
+	if policy == corev1.IPFamilyPolicySingleStack && len(families) > 1 {
+		return "", nil, fmt.Errorf("%v %s allows a single IP family, got %d", ipFamilyPolicyOverride, policy, len(families))
+	}
+	if policy == corev1.IPFamilyPolicyRequireDualStack && len(families) == 1 {
+		return "", nil, fmt.Errorf("%v %s requires both IP families, got %s", ipFamilyPolicyOverride, policy, families[0])
+	}
+	// The proxies only listen on the addresses of both families in dual-stack mode
+	dualStack := policy == corev1.IPFamilyPolicyPreferDualStack || policy == corev1.IPFamilyPolicyRequireDualStack || len(families) > 1
+	if dualStack && !features.EnableDualStack {
+		return "", nil, fmt.Errorf("dual-stack gateways require istiod to run with ISTIO_DUAL_STACK enabled")
+	}
+	return policy, families, nil
 }
 
 func extractServicePorts(gw gateway.Gateway) []corev1.ServicePort {
@@ -427,6 +1109,7 @@ func extractServicePorts(gw gateway.Gateway) []corev1.ServicePort {
 		AppProtocol: &tcp,
 	})
 	portNums := map[int32]struct{}{}
+	names := sets.New("status-port")
 	for i, l := range gw.Spec.Listeners {
 		if _, f := portNums[int32(l.Port)]; f {
 			continue
@@ -437,6 +1120,11 @@ func extractServicePorts(gw gateway.Gateway) []corev1.ServicePort {
 			// Should not happen since name is required, but in case an invalid resource gets in...
 			name = fmt.Sprintf("%s-%d", strings.ToLower(string(l.Protocol)), i)
 		}
+		if names.Contains(name) {
+			// The listeners of the gateways sharing a deployment may have the same names, on different ports
+			name = fmt.Sprintf("%s-%d", name, l.Port)
+		}
+		names.Insert(name)
 		appProtocol := strings.ToLower(string(l.Protocol))
 		svcPorts = append(svcPorts, corev1.ServicePort{
 			Name:        name,
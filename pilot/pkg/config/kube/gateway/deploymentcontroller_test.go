@@ -18,18 +18,24 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 	"sigs.k8s.io/yaml"
 
+	"istio.io/api/annotation"
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model/kstatus"
 	"istio.io/istio/pilot/test/util"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/constants"
@@ -37,7 +43,9 @@ import (
 	"istio.io/istio/pkg/config/schema/gvr"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/kube/kclient"
 	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/ptr"
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/env"
 	"istio.io/istio/pkg/test/util/assert"
@@ -48,6 +56,7 @@ import (
 
 func TestConfigureIstioGateway(t *testing.T) {
 	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	test.SetForTest(t, &features.EnableDualStack, true)
 	// Recompute with ambient enabled
 	classInfos = getClassInfos()
 	tests := []struct {
@@ -103,8 +112,8 @@ func TestConfigureIstioGateway(t *testing.T) {
 					Name:      "default",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"networking.istio.io/service-type": string(corev1.ServiceTypeClusterIP),
-						gatewayNameOverride:                "default",
+						serviceTypeOverride: string(corev1.ServiceTypeClusterIP),
+						gatewayNameOverride: "default",
 					},
 				},
 				Spec: v1beta1.GatewaySpec{
@@ -136,6 +145,329 @@ func TestConfigureIstioGateway(t *testing.T) {
 				},
 			},
 		},
+		{
+			"autoscaling",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayAutoscalingMaxReplicas:  "5",
+						gatewayAutoscalingTargetCPU:    "60",
+						gatewayAutoscalingTargetMemory: "70",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"autoscaling-connections",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayAutoscalingMaxReplicas:       "5",
+						gatewayAutoscalingTargetConnections: "1000",
+						gatewayAutoscalingTargetRequests:    "500",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"custom-template",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "custom",
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"infrastructure",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayInfrastructure: `{"labels":{"team":"a"},"annotations":{"cloud.example.com/lb-type":"internal"}}`,
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"replicas-resources",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayReplicas:                         "3",
+						annotation.SidecarProxyCPU.Name:         "500m",
+						annotation.SidecarProxyMemoryLimit.Name: "1Gi",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"daemonset",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "daemonset",
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"statefulset",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default",
+					Namespace:   "default",
+					Annotations: map[string]string{gatewayReplicas: "2"},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "statefulset",
+					Listeners: []v1beta1.Listener{{
+						Name:     "tcp",
+						Port:     v1beta1.PortNumber(5000),
+						Protocol: v1beta1.TCPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"dual-stack",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						ipFamilyPolicyOverride: "PreferDualStack",
+						ipFamiliesOverride:     "IPv6,IPv4",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"source-ip",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						externalTrafficPolicy:  "Local",
+						healthCheckNodePort:    "30100",
+						sessionAffinity:        "ClientIP",
+						sessionAffinityTimeout: "600",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"scheduling",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayScheduling: `{"nodeSelector":{"pool":"ingress"},` +
+							`"tolerations":[{"key":"dedicated","operator":"Equal","value":"ingress","effect":"NoSchedule"}]}`,
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"network-policy",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "network-policy",
+					Listeners: []v1beta1.Listener{
+						{
+							Name:     "http",
+							Port:     v1beta1.PortNumber(80),
+							Protocol: v1beta1.HTTPProtocolType,
+						},
+						{
+							Name:     "https",
+							Port:     v1beta1.PortNumber(443),
+							Protocol: v1beta1.HTTPSProtocolType,
+						},
+					},
+				},
+			},
+		},
+		{
+			"proxy-image",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayProxyHub: "gcr.io/istio-testing",
+						gatewayProxyTag: "canary",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+				},
+			},
+		},
+		{
+			"pod-classes",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "pod-classes",
+				},
+			},
+		},
+		{
+			"service-account-annotations",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayServiceAccountAnnotations: `{"eks.amazonaws.com/role-arn":"arn:aws:iam::123456789012:role/gw"}`,
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "service-account",
+				},
+			},
+		},
+		{
+			"host-network",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "host-network",
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			// Without ports below 1024, the proxy does not run as root
+			"host-network-unprivileged",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: "host-network",
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(8080),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
+		{
+			"pod-disruption-budget",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default",
+					Namespace:   "default",
+					Annotations: map[string]string{gatewayPDBMinAvailable: "50%"},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: DefaultClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "http",
+						Port:     v1beta1.PortNumber(80),
+						Protocol: v1beta1.HTTPProtocolType,
+					}},
+				},
+			},
+		},
 		{
 			"waypoint",
 			v1beta1.Gateway{
@@ -153,17 +485,80 @@ func TestConfigureIstioGateway(t *testing.T) {
 				},
 			},
 		},
+		{
+			"waypoint-autoscaling",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "namespace",
+					Namespace: "default",
+					Annotations: map[string]string{
+						gatewayAutoscalingMinReplicas: "2",
+						gatewayAutoscalingMaxReplicas: "5",
+						"sidecar.istio.io/proxyCPU":   "500m",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: constants.WaypointGatewayClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "mesh",
+						Port:     v1beta1.PortNumber(15008),
+						Protocol: "ALL",
+					}},
+				},
+			},
+		},
+		{
+			"waypoint-autoscaling-namespace",
+			v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "namespace",
+					Namespace:   "autoscaled",
+					Annotations: map[string]string{gatewayAutoscalingTargetCPU: "60"},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: constants.WaypointGatewayClassName,
+					Listeners: []v1beta1.Listener{{
+						Name:     "mesh",
+						Port:     v1beta1.PortNumber(15008),
+						Protocol: "ALL",
+					}},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			buf := &bytes.Buffer{}
+			client := kube.NewFakeClient(
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default-istio", Namespace: "default"}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "custom-sa", Namespace: "default"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:        "autoscaled",
+					Annotations: map[string]string{gatewayAutoscalingMaxReplicas: "10"},
+				}},
+				customTemplateConfigMap,
+				daemonSetConfigMap,
+				statefulSetConfigMap,
+				networkPolicyConfigMap,
+				podClassesConfigMap,
+				serviceAccountConfigMap,
+				hostNetworkConfigMap,
+			)
 			d := &DeploymentController{
-				client: kube.NewFakeClient(
-					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default-istio", Namespace: "default"}},
-					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "custom-sa", Namespace: "default"}},
-				),
-				clusterID:    cluster.ID(features.ClusterName),
-				injectConfig: testInjectionConfig(t),
+				client:          client,
+				systemNamespace: "istio-system",
+				namespaces:      kclient.New[*corev1.Namespace](client),
+				gateways:        kclient.New[*v1beta1.Gateway](client),
+				gatewayClasses:  kclient.New[*v1beta1.GatewayClass](client),
+				configMaps:      kclient.NewFiltered[*corev1.ConfigMap](client, kclient.Filter{LabelSelector: TemplateConfigMapLabel}),
+				deployments:     kclient.New[*appsv1.Deployment](client),
+				daemonSets:      kclient.New[*appsv1.DaemonSet](client),
+				statefulSets:    kclient.New[*appsv1.StatefulSet](client),
+				services:        kclient.New[*corev1.Service](client),
+				pods:            kclient.New[*corev1.Pod](client),
+				recorder:        &record.FakeRecorder{},
+				clusterID:       cluster.ID(features.ClusterName),
+				injectConfig:    testInjectionConfig(t),
 				patcher: func(gvr schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
 					b, err := yaml.JSONToYAML(data)
 					if err != nil {
@@ -174,6 +569,14 @@ func TestConfigureIstioGateway(t *testing.T) {
 					return nil
 				},
 			}
+			clienttest.Wrap(t, d.gatewayClasses).Create(customGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(daemonSetGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(statefulSetGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(networkPolicyGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(podClassesGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(serviceAccountGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(hostNetworkGatewayClass)
+			client.RunAndWait(test.NewStop(t))
 			err := d.configureIstioGateway(istiolog.FindScope(istiolog.DefaultScopeName), tt.gw)
 			if err != nil {
 				t.Fatal(err)
@@ -185,11 +588,152 @@ func TestConfigureIstioGateway(t *testing.T) {
 	}
 }
 
+func TestExtractServiceType(t *testing.T) {
+	cases := []struct {
+		name        string
+		gw          map[string]string
+		mode        string
+		hostNetwork bool
+		want        corev1.ServiceType
+		wantError   bool
+	}{
+		{
+			name: "default",
+			mode: DeploymentModeDeployment,
+			want: corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name: "daemonset default",
+			mode: DeploymentModeDaemonSet,
+			want: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name:        "host network default",
+			mode:        DeploymentModeDeployment,
+			hostNetwork: true,
+			want:        corev1.ServiceTypeClusterIP,
+		},
+		{
+			name: "node port",
+			gw:   map[string]string{serviceTypeOverride: "NodePort"},
+			mode: DeploymentModeDeployment,
+			want: corev1.ServiceTypeNodePort,
+		},
+		{
+			name: "daemonset override",
+			gw:   map[string]string{serviceTypeOverride: "NodePort"},
+			mode: DeploymentModeDaemonSet,
+			want: corev1.ServiceTypeNodePort,
+		},
+		{
+			name:      "external name",
+			gw:        map[string]string{serviceTypeOverride: "ExternalName"},
+			mode:      DeploymentModeDeployment,
+			wantError: true,
+		},
+		{
+			name:      "wrong case",
+			gw:        map[string]string{serviceTypeOverride: "clusterip"},
+			mode:      DeploymentModeDeployment,
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractServiceType(tt.gw, tt.mode, tt.hostNetwork)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestExtractIPFamilies(t *testing.T) {
+	cases := []struct {
+		name       string
+		gw         map[string]string
+		wantPolicy corev1.IPFamilyPolicy
+		// singleStack runs istiod without features.EnableDualStack
+		singleStack  bool
+		wantFamilies []corev1.IPFamily
+		wantError    bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:         "dual stack",
+			gw:           map[string]string{ipFamilyPolicyOverride: "RequireDualStack", ipFamiliesOverride: "IPv6, IPv4"},
+			wantPolicy:   corev1.IPFamilyPolicyRequireDualStack,
+			wantFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+		},
+		{
+			name:       "policy only",
+			gw:         map[string]string{ipFamilyPolicyOverride: "PreferDualStack"},
+			wantPolicy: corev1.IPFamilyPolicyPreferDualStack,
+		},
+		{
+			name:         "families only",
+			gw:           map[string]string{ipFamiliesOverride: "IPv6"},
+			wantFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+		},
+		{
+			name:      "invalid policy",
+			gw:        map[string]string{ipFamilyPolicyOverride: "DualStack"},
+			wantError: true,
+		},
+		{
+			name:      "invalid family",
+			gw:        map[string]string{ipFamiliesOverride: "IPv4,ipv6"},
+			wantError: true,
+		},
+		{
+			name:      "duplicate family",
+			gw:        map[string]string{ipFamiliesOverride: "IPv4,IPv4"},
+			wantError: true,
+		},
+		{
+			name:      "single stack with two families",
+			gw:        map[string]string{ipFamilyPolicyOverride: "SingleStack", ipFamiliesOverride: "IPv4,IPv6"},
+			wantError: true,
+		},
+		{
+			name:      "dual stack with one family",
+			gw:        map[string]string{ipFamilyPolicyOverride: "RequireDualStack", ipFamiliesOverride: "IPv4"},
+			wantError: true,
+		},
+		{
+			name:        "single stack istiod",
+			gw:          map[string]string{ipFamilyPolicyOverride: "PreferDualStack"},
+			singleStack: true,
+			wantError:   true,
+		},
+		{
+			name:         "single family with single stack istiod",
+			gw:           map[string]string{ipFamiliesOverride: "IPv6"},
+			singleStack:  true,
+			wantFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			test.SetForTest(t, &features.EnableDualStack, !tt.singleStack)
+			policy, families, err := extractIPFamilies(tt.gw)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, policy, tt.wantPolicy)
+			assert.Equal(t, families, tt.wantFamilies)
+		})
+	}
+}
+
 func TestVersionManagement(t *testing.T) {
 	log.SetOutputLevel(istiolog.DebugLevel)
 	writes := make(chan string, 10)
 	c := kube.NewFakeClient()
-	d := NewDeploymentController(c, "", testInjectionConfig(t), func(fn func()) {})
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
 	reconciles := atomic.NewInt32(0)
 	wantReconcile := int32(0)
 	expectReconciled := func() {
@@ -202,7 +746,7 @@ func TestVersionManagement(t *testing.T) {
 		if g == gvr.Service {
 			reconciles.Inc()
 		}
-		if g == gvr.KubernetesGateway {
+		if g == gvr.KubernetesGateway && len(subresources) == 0 {
 			b, err := yaml.JSONToYAML(data)
 			if err != nil {
 				return err
@@ -276,6 +820,397 @@ func TestVersionManagement(t *testing.T) {
 	assert.Equal(t, reconciles.Load(), wantReconcile)
 }
 
+var (
+	customGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "custom-template",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	customTemplateConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-template",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{templateConfigMapKey: `apiVersion: v1
+kind: Service
+metadata:
+  name: {{.DeploymentName | quote}}
+  namespace: {{.Namespace | quote}}
+spec:
+  type: ClusterIP
+  ports:
+{{- range $key, $val := .Ports }}
+  - name: {{ $val.Name | quote }}
+    port: {{ $val.Port }}
+{{- end }}
+  selector:
+    istio.io/gateway-name: {{.Name}}
+`},
+	}
+)
+
+var (
+	daemonSetGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonset"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "daemonset",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	daemonSetConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "daemonset",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{modeConfigMapKey: DeploymentModeDaemonSet},
+	}
+	statefulSetGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "statefulset",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	statefulSetConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "statefulset",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{modeConfigMapKey: DeploymentModeStatefulSet, perPodServicesConfigMapKey: "true"},
+	}
+	networkPolicyGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "network-policy"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "network-policy",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	networkPolicyConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "network-policy",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{networkPolicyConfigMapKey: "true"},
+	}
+	podClassesGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-classes"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "pod-classes",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	podClassesConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-classes",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{priorityClassNameConfigMapKey: "system-cluster-critical", runtimeClassNameConfigMapKey: "gvisor"},
+	}
+	serviceAccountGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-account"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "service-account",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	serviceAccountConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service-account",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{serviceAccountAnnotationsConfigMapKey: `
+eks.amazonaws.com/role-arn: arn:aws:iam::123456789012:role/default
+eks.amazonaws.com/sts-regional-endpoints: "true"`},
+	}
+	hostNetworkGatewayClass = &v1beta1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-network"},
+		Spec: v1beta1.GatewayClassSpec{
+			ControllerName: constants.ManagedGatewayController,
+			ParametersRef: &v1beta1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "host-network",
+				Namespace: ptr.Of(v1beta1.Namespace("istio-system")),
+			},
+		},
+	}
+	hostNetworkConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "host-network",
+			Namespace: "istio-system",
+			Labels:    map[string]string{TemplateConfigMapLabel: "true"},
+		},
+		Data: map[string]string{modeConfigMapKey: DeploymentModeDaemonSet, hostNetworkConfigMapKey: "true"},
+	}
+)
+
+func TestReadClassParameters(t *testing.T) {
+	cases := []struct {
+		name      string
+		data      map[string]string
+		wantMode  string
+		wantError bool
+	}{
+		{
+			name:     "empty",
+			wantMode: DeploymentModeDeployment,
+		},
+		{
+			name:     "daemonset",
+			data:     map[string]string{modeConfigMapKey: DeploymentModeDaemonSet},
+			wantMode: DeploymentModeDaemonSet,
+		},
+		{
+			name:     "statefulset",
+			data:     map[string]string{modeConfigMapKey: DeploymentModeStatefulSet, perPodServicesConfigMapKey: "true"},
+			wantMode: DeploymentModeStatefulSet,
+		},
+		{
+			name:      "invalid mode",
+			data:      map[string]string{modeConfigMapKey: "ReplicaSet"},
+			wantError: true,
+		},
+		{
+			name:      "per pod services without statefulset",
+			data:      map[string]string{perPodServicesConfigMapKey: "true"},
+			wantError: true,
+		},
+		{
+			name:      "invalid per pod services",
+			data:      map[string]string{modeConfigMapKey: DeploymentModeStatefulSet, perPodServicesConfigMapKey: "yes"},
+			wantError: true,
+		},
+		{
+			name:      "invalid scheduling",
+			data:      map[string]string{schedulingConfigMapKey: "nodeSelector: [pool]"},
+			wantError: true,
+		},
+		{
+			name:      "invalid topology spread constraints",
+			data:      map[string]string{topologySpreadConstraintsConfigMapKey: "- topologyKey: kubernetes.io/hostname"},
+			wantError: true,
+		},
+		{
+			name:      "invalid priority class name",
+			data:      map[string]string{priorityClassNameConfigMapKey: "High_Priority"},
+			wantError: true,
+		},
+		{
+			name:      "invalid runtime class name",
+			data:      map[string]string{runtimeClassNameConfigMapKey: ""},
+			wantError: true,
+		},
+		{
+			name:      "invalid service account annotations",
+			data:      map[string]string{serviceAccountAnnotationsConfigMapKey: "eks.amazonaws.com/role-arn: [a]"},
+			wantError: true,
+		},
+		{
+			name:      "invalid network policy",
+			data:      map[string]string{networkPolicyConfigMapKey: "yes"},
+			wantError: true,
+		},
+		{
+			name:      "invalid template",
+			data:      map[string]string{templateConfigMapKey: "{{ .Name"},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := kube.NewFakeClient(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: "istio-system", Labels: map[string]string{TemplateConfigMapLabel: ""}},
+				Data:       tt.data,
+			})
+			d := &DeploymentController{configMaps: kclient.NewFiltered[*corev1.ConfigMap](c, kclient.Filter{LabelSelector: TemplateConfigMapLabel})}
+			c.RunAndWait(test.NewStop(t))
+			got, err := d.readClassParameters(types.NamespacedName{Namespace: "istio-system", Name: "params"})
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got.mode, tt.wantMode)
+		})
+	}
+}
+
+func TestCustomTemplateChange(t *testing.T) {
+	c := kube.NewFakeClient()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	writes := make(chan string, 10)
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		if g == gvr.Service {
+			writes <- name
+		}
+		return nil
+	}
+	stop := test.NewStop(t)
+	gws := clienttest.Wrap(t, d.gateways)
+	cms := clienttest.Wrap(t, d.configMaps)
+	clienttest.Wrap(t, d.gatewayClasses).Create(customGatewayClass)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	gws.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       v1beta1.GatewaySpec{GatewayClassName: "custom"},
+	})
+	// The template does not exist yet, nothing is rendered
+	assert.ChannelIsEmpty(t, writes)
+
+	cms.Create(customTemplateConfigMap)
+	assert.Equal(t, assert.ChannelHasItem(t, writes), "gw-custom")
+
+	// Changing the template re-renders the gateways of the class
+	cm := customTemplateConfigMap.DeepCopy()
+	cm.Data[templateConfigMapKey] = strings.ReplaceAll(cm.Data[templateConfigMapKey], "ClusterIP", "LoadBalancer")
+	cms.Update(cm)
+	assert.Equal(t, assert.ChannelHasItem(t, writes), "gw-custom")
+}
+
+func TestDeploymentControllerEvents(t *testing.T) {
+	c := kube.NewFakeClient()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	recorder := record.NewFakeRecorder(10)
+	d.recorder = recorder
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		return nil
+	}
+	stop := test.NewStop(t)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	gw := v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+	}
+	assert.NoError(t, d.configureIstioGateway(istiolog.FindScope(istiolog.DefaultScopeName), gw))
+	assert.Equal(t, assert.ChannelHasItem(t, recorder.Events), "Normal DeploymentCreated Created Deployment default/gw-istio")
+
+	gw.Annotations = map[string]string{gatewayReplicas: "-1"}
+	assert.Error(t, d.configureIstioGateway(istiolog.FindScope(istiolog.DefaultScopeName), gw))
+	assert.Equal(t, strings.HasPrefix(assert.ChannelHasItem(t, recorder.Events), "Warning InvalidConfiguration "), true)
+}
+
+func TestDeploymentControllerCertificateRefs(t *testing.T) {
+	test.SetForTest(t, &features.ValidateGatewayCertificateRefs, true)
+	c := kube.NewFakeClient()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	recorder := record.NewFakeRecorder(10)
+	d.recorder = recorder
+	applied := atomic.NewInt32(0)
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		if g == gvr.Deployment {
+			applied.Inc()
+		}
+		return nil
+	}
+	stop := test.NewStop(t)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	gw := v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "istio-system"},
+		Spec: v1beta1.GatewaySpec{
+			GatewayClassName: DefaultClassName,
+			Listeners:        []v1beta1.Listener{tlsListener("https", v1beta1.SecretObjectReference{Name: "my-cert-http"})},
+		},
+	}
+	// A missing Secret is reported, but does not block the provisioning of the other listeners
+	assert.NoError(t, d.configureIstioGateway(istiolog.FindScope(istiolog.DefaultScopeName), gw))
+	assert.Equal(t, strings.HasPrefix(assert.ChannelHasItem(t, recorder.Events), "Warning InvalidCertificateRef "), true)
+	assert.Equal(t, applied.Load(), 1)
+
+	// Once the Secret is created, the reference is resolved
+	clienttest.Wrap(t, d.secrets).Create(secrets[0].(*corev1.Secret))
+	retry.UntilSuccessOrFail(t, func() error {
+		if cond := d.certificateRefsCondition(gw); cond.Status != kstatus.StatusTrue {
+			return fmt.Errorf("certificate references not resolved: %v", cond.Message)
+		}
+		return nil
+	}, retry.Timeout(time.Second*5))
+}
+
+func TestManagedGateways(t *testing.T) {
+	m := newManagedGateways()
+	a := types.NamespacedName{Name: "a", Namespace: "default"}
+	b := types.NamespacedName{Name: "b", Namespace: "default"}
+	m.set(a, DefaultClassName)
+	m.set(b, DefaultClassName)
+	m.set(b, DefaultClassName)
+	assert.Equal(t, m.count(DefaultClassName), 2)
+
+	m.set(b, constants.WaypointGatewayClassName)
+	assert.Equal(t, m.count(DefaultClassName), 1)
+	assert.Equal(t, m.count(constants.WaypointGatewayClassName), 1)
+
+	m.set(a, "")
+	m.set(b, "")
+	assert.Equal(t, m.count(DefaultClassName), 0)
+	assert.Equal(t, m.count(constants.WaypointGatewayClassName), 0)
+}
+
+func TestRenderedKind(t *testing.T) {
+	assert.Equal(t, renderedKind("apiVersion: apps/v1\nkind: Deployment\n"), "Deployment")
+	assert.Equal(t, renderedKind("apiVersion: v1\n"), "unknown")
+	assert.Equal(t, renderedKind(":"), "unknown")
+}
+
+func TestApplyGeneratedResource(t *testing.T) {
+	var applied []string
+	d := &DeploymentController{
+		patcher: func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+			applied = append(applied, fmt.Sprintf("%s/%s/%s", g.Resource, namespace, name))
+			return nil
+		},
+	}
+	// The resources are applied in the namespace of the gateway, whatever the template sets
+	assert.NoError(t, d.apply("istio.io/gateway-controller", "default", "apiVersion: v1\nkind: Service\nmetadata:\n  name: gw\n"))
+	assert.NoError(t, d.apply("istio.io/gateway-controller", "default",
+		"apiVersion: policy/v1\nkind: PodDisruptionBudget\nmetadata:\n  name: gw\n  namespace: istio-system\n"))
+	assert.Equal(t, applied, []string{"services/default/gw", "poddisruptionbudgets/default/gw"})
+	// Only the kinds of the builtin templates can be rendered
+	assert.Error(t, d.apply("istio.io/gateway-controller", "default",
+		"apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRoleBinding\nmetadata:\n  name: gw\n"))
+	assert.Equal(t, len(applied), 2)
+}
+
+func TestClassParametersNamespace(t *testing.T) {
+	d := &DeploymentController{systemNamespace: "istio-system"}
+	_, err := d.classParametersOf(classInfo{parameters: &types.NamespacedName{Namespace: "default", Name: "params"}})
+	assert.Error(t, err)
+}
+
 func testInjectionConfig(t test.Failer) func() inject.WebhookConfig {
 	vc, err := inject.NewValuesConfig(`
 global:
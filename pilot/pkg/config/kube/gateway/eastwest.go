@@ -0,0 +1,156 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/label"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/ptr"
+)
+
+const (
+	// EastWestGatewayName is the name of the east-west gateway provisioned in the system namespace.
+	EastWestGatewayName = "istio-eastwestgateway"
+	// eastWestGatewayLabel marks the east-west gateway provisioned by the EastWestGatewayController, so a Gateway
+	// of the same name created by users is left alone.
+	eastWestGatewayLabel = "gateway.istio.io/east-west"
+	// eastWestGatewayPort is the port of the cross-network listener of the east-west gateway, the default port of
+	// the network gateways.
+	eastWestGatewayPort = 15443
+)
+
+// EastWestGatewayController provisions the east-west gateway of the network of the cluster, as declared by the
+// topology.istio.io/network label of the system namespace. This replaces generating and applying the east-west
+// gateway by hand when adding a cluster to a multi-network mesh.
+// The gateway is a Gateway of the default class, so it is deployed by the DeploymentController. Its Service
+// inherits the network label, so it is registered as the gateway of the network, and its addresses are kept up
+// to date, without listing it in the MeshNetworks.
+// Only the network label of the gateway is reconciled once it is created, so users can customize it; if it is
+// deleted it is created again, and if the network of the cluster is unset it is removed.
+type EastWestGatewayController struct {
+	systemNamespace string
+	queue           controllers.Queue
+	namespaces      kclient.Client[*corev1.Namespace]
+	gateways        kclient.Client[*gateway.Gateway]
+}
+
+// NewEastWestGatewayController constructs an EastWestGatewayController and registers required informers.
+// The controller will not start until Run() is called.
+func NewEastWestGatewayController(client kube.Client, clusterID cluster.ID, systemNamespace string) *EastWestGatewayController {
+	c := &EastWestGatewayController{systemNamespace: systemNamespace}
+	c.queue = controllers.NewQueue("east-west gateway",
+		controllers.WithReconciler(c.Reconcile),
+		controllers.WithMaxAttempts(5),
+		controllers.WithKind(kind.KubernetesGateway),
+		controllers.WithCluster(clusterID))
+	metrics := c.queue.Metrics()
+
+	// There is a single gateway to reconcile, so every event enqueues the same key.
+	enqueue := func(controllers.Object) {
+		c.queue.Add(types.NamespacedName{Name: EastWestGatewayName, Namespace: systemNamespace})
+	}
+	c.namespaces = kclient.New[*corev1.Namespace](client)
+	c.namespaces.AddEventHandler(metrics.Handler(kind.Namespace,
+		controllers.FilteredObjectHandler(enqueue, func(o controllers.Object) bool {
+			return o.GetName() == systemNamespace
+		})))
+	c.gateways = kclient.New[*gateway.Gateway](client)
+	c.gateways.AddEventHandler(metrics.Handler(kind.KubernetesGateway,
+		controllers.FilteredObjectHandler(enqueue, func(o controllers.Object) bool {
+			return o.GetName() == EastWestGatewayName && o.GetNamespace() == systemNamespace
+		})))
+	return c
+}
+
+func (c *EastWestGatewayController) Run(stop <-chan struct{}) {
+	// Ensure we initially reconcile the current state
+	c.queue.Add(types.NamespacedName{Name: EastWestGatewayName, Namespace: c.systemNamespace})
+	c.queue.Run(stop)
+	controllers.ShutdownAll(c.namespaces, c.gateways)
+}
+
+func (c *EastWestGatewayController) Reconcile(types.NamespacedName) error {
+	existing := c.gateways.Get(EastWestGatewayName, c.systemNamespace)
+	if existing != nil && existing.Labels[eastWestGatewayLabel] != "true" {
+		log.Debugf("Gateway %s/%s is not managed by the east-west gateway controller, no action", c.systemNamespace, EastWestGatewayName)
+		return nil
+	}
+
+	network := ""
+	if ns := c.namespaces.Get(c.systemNamespace, ""); ns != nil {
+		network = ns.Labels[label.TopologyNetwork.Name]
+	}
+	if network == "" {
+		if existing == nil {
+			return nil
+		}
+		log.Infof("Removing east-west gateway %s/%s, as the network of the cluster is not set", c.systemNamespace, EastWestGatewayName)
+		return controllers.IgnoreNotFound(c.gateways.Delete(EastWestGatewayName, c.systemNamespace))
+	}
+
+	if existing == nil {
+		log.Infof("Provisioning east-west gateway %s/%s for network %s", c.systemNamespace, EastWestGatewayName, network)
+		_, err := c.gateways.Create(eastWestGateway(c.systemNamespace, network))
+		if kerrors.IsAlreadyExists(err) {
+			// This is not really an error, just a race condition; the gateway is reconciled on its event.
+			return nil
+		}
+		return err
+	}
+	if existing.Labels[label.TopologyNetwork.Name] == network {
+		return nil
+	}
+	log.Infof("Updating the network of east-west gateway %s/%s to %s", c.systemNamespace, EastWestGatewayName, network)
+	gw := existing.DeepCopy()
+	gw.Labels[label.TopologyNetwork.Name] = network
+	_, err := c.gateways.Update(gw)
+	return err
+}
+
+// eastWestGateway returns the east-west gateway of the network, exposing the services of the network to the other
+// networks on the auto passthrough listener.
+func eastWestGateway(namespace, network string) *gateway.Gateway {
+	return &gateway.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EastWestGatewayName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				eastWestGatewayLabel:       "true",
+				label.TopologyNetwork.Name: network,
+			},
+		},
+		Spec: gateway.GatewaySpec{
+			GatewayClassName: DefaultClassName,
+			Listeners: []gateway.Listener{{
+				Name:     "cross-network",
+				Port:     eastWestGatewayPort,
+				Protocol: gateway.TLSProtocolType,
+				TLS: &gateway.GatewayTLSConfig{
+					Mode: ptr.Of(gateway.TLSModePassthrough),
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/label"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+func TestEastWestGatewayController(t *testing.T) {
+	client := kube.NewFakeClient()
+	c := NewEastWestGatewayController(client, "cluster-1", "istio-system")
+	stop := test.NewStop(t)
+	client.RunAndWait(stop)
+	go c.Run(stop)
+
+	namespaces := clienttest.Wrap(t, c.namespaces)
+	gateways := clienttest.Wrap(t, c.gateways)
+	setNetwork := func(network string) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system", Labels: map[string]string{}}}
+		if network != "" {
+			ns.Labels[label.TopologyNetwork.Name] = network
+		}
+		namespaces.CreateOrUpdate(ns)
+	}
+	expectNetwork := func(network string) {
+		t.Helper()
+		retry.UntilSuccessOrFail(t, func() error {
+			gw := gateways.Get(EastWestGatewayName, "istio-system")
+			if network == "" {
+				if gw != nil {
+					return fmt.Errorf("expected no gateway, got one for network %q", gw.Labels[label.TopologyNetwork.Name])
+				}
+				return nil
+			}
+			if gw == nil {
+				return fmt.Errorf("expected gateway for network %q, got none", network)
+			}
+			if got := gw.Labels[label.TopologyNetwork.Name]; got != network {
+				return fmt.Errorf("expected gateway for network %q, got %q", network, got)
+			}
+			return nil
+		})
+	}
+
+	// No gateway without a network
+	setNetwork("")
+	expectNetwork("")
+
+	setNetwork("network-1")
+	expectNetwork("network-1")
+	gw := gateways.Get(EastWestGatewayName, "istio-system")
+	if gw.Spec.GatewayClassName != DefaultClassName || len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].Port != 15443 {
+		t.Fatalf("unexpected east-west gateway spec: %+v", gw.Spec)
+	}
+
+	// Once we delete it, it should be added back
+	gateways.Delete(EastWestGatewayName, "istio-system")
+	expectNetwork("network-1")
+
+	setNetwork("network-2")
+	expectNetwork("network-2")
+
+	setNetwork("")
+	expectNetwork("")
+
+	// A gateway of the same name not provisioned by the controller is left alone
+	gateways.Create(&gateway.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EastWestGatewayName,
+			Namespace: "istio-system",
+			Labels:    map[string]string{label.TopologyNetwork.Name: "custom"},
+		},
+		Spec: gateway.GatewaySpec{GatewayClassName: DefaultClassName},
+	})
+	setNetwork("network-1")
+	expectNetwork("custom")
+}
@@ -0,0 +1,56 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/kube"
+)
+
+// The reasons of the events recorded on the Gateways by the deployment controller, so the provisioning failures are
+// shown by kubectl describe gateway.
+const (
+	eventDeploymentCreated     = "DeploymentCreated"
+	eventInvalidConfiguration  = "InvalidConfiguration"
+	eventInvalidProxyImage     = "InvalidProxyImage"
+	eventInvalidCertificateRef = "InvalidCertificateRef"
+	eventHostPortConflict      = "HostPortConflict"
+	eventTemplateRenderFailed  = "TemplateRenderFailed"
+	eventApplyFailed           = "ApplyFailed"
+	eventResourcesDeleteFailed = "ResourcesDeleteFailed"
+)
+
+// newEventRecorder returns the recorder of the events of the deployment controller, and its broadcaster to start
+// and shut down with the controller.
+func newEventRecorder() (record.EventBroadcaster, record.EventRecorder) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(kube.IstioScheme, corev1.EventSource{Component: constants.ManagedGatewayController})
+	return broadcaster, recorder
+}
+
+// startRecording starts sending the recorded events to the API server.
+func (d *DeploymentController) startRecording() {
+	d.broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: d.client.Kube().CoreV1().Events("")})
+}
+
+// event records an event on the gateway.
+func (d *DeploymentController) event(gw gateway.Gateway, eventType, reason, messageFmt string, args ...any) {
+	d.recorder.Eventf(&gw, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,50 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"strings"
+
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// externalDNSHostnameAnnotation is the annotation of the Services external-dns provisions the DNS records of, with
+// the comma separated hostnames of the records.
+const externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// extractServiceAnnotations returns the annotations added to the gateway Service. If the external-dns integration is
+// enabled, these are the hostnames of the listeners, unless the gateway sets the annotation itself, which is
+// propagated to the Service like its other annotations.
+func extractServiceAnnotations(gw gateway.Gateway) map[string]string {
+	if !features.EnableGatewayExternalDNS {
+		return nil
+	}
+	if _, f := gw.Annotations[externalDNSHostnameAnnotation]; f {
+		return nil
+	}
+	hostnames := sets.New[string]()
+	for _, l := range gw.Spec.Listeners {
+		if l.Hostname != nil && *l.Hostname != "" {
+			hostnames.Insert(string(*l.Hostname))
+		}
+	}
+	if hostnames.IsEmpty() {
+		return nil
+	}
+	return map[string]string{externalDNSHostnameAnnotation: strings.Join(sets.SortedList(hostnames), ",")}
+}
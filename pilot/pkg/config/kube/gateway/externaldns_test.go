@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractServiceAnnotations(t *testing.T) {
+	listeners := []v1beta1.Listener{
+		{Name: "b", Hostname: ptr.Of(v1beta1.Hostname("b.example.com"))},
+		{Name: "a", Hostname: ptr.Of(v1beta1.Hostname("*.example.com"))},
+		{Name: "b-tls", Hostname: ptr.Of(v1beta1.Hostname("b.example.com"))},
+		{Name: "any"},
+	}
+	cases := []struct {
+		name        string
+		disabled    bool
+		annotations map[string]string
+		listeners   []v1beta1.Listener
+		want        map[string]string
+	}{
+		{
+			name:      "disabled",
+			disabled:  true,
+			listeners: listeners,
+		},
+		{
+			name:      "hostnames",
+			listeners: listeners,
+			want:      map[string]string{externalDNSHostnameAnnotation: "*.example.com,b.example.com"},
+		},
+		{
+			name:      "no hostnames",
+			listeners: []v1beta1.Listener{{Name: "any"}},
+		},
+		{
+			name:        "set on the gateway",
+			annotations: map[string]string{externalDNSHostnameAnnotation: "gw.example.com"},
+			listeners:   listeners,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			test.SetForTest(t, &features.EnableGatewayExternalDNS, !tt.disabled)
+			gw := v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       v1beta1.GatewaySpec{Listeners: tt.listeners},
+			}
+			assert.Equal(t, extractServiceAnnotations(gw), tt.want)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/kube/kclient"
@@ -46,13 +47,15 @@ func NewClassController(kc kube.Client) *ClassController {
 	gc := &ClassController{}
 	gc.queue = controllers.NewQueue("gateway class",
 		controllers.WithReconciler(gc.Reconcile),
-		controllers.WithMaxAttempts(25))
+		controllers.WithMaxAttempts(25),
+		controllers.WithKind(kind.GatewayClass))
 
 	gc.classes = kclient.New[*gateway.GatewayClass](kc)
-	gc.classes.AddEventHandler(controllers.FilteredObjectHandler(gc.queue.AddObject, func(o controllers.Object) bool {
-		_, f := classInfos[o.GetName()]
-		return f
-	}))
+	gc.classes.AddEventHandler(gc.queue.Metrics().Handler(kind.GatewayClass,
+		controllers.FilteredObjectHandler(gc.queue.AddObject, func(o controllers.Object) bool {
+			_, f := classInfos[o.GetName()]
+			return f
+		})))
 	return gc
 }
 
@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// gatewayConditionHostPortsAvailable reports whether the ports bound on the nodes by the pods of a gateway in host
+// network mode are not bound by the pods of another gateway which may be scheduled to the same nodes. It is only
+// reported for the gateways of the classes in host network mode, in which case the resources of a gateway are not
+// applied while it conflicts with an older gateway.
+const gatewayConditionHostPortsAvailable = "gateway.istio.io/HostPortsAvailable"
+
+// The reasons of the gatewayConditionHostPortsAvailable condition.
+const (
+	reasonHostPortsAvailable = "HostPortsAvailable"
+	reasonHostPortConflict   = "HostPortConflict"
+)
+
+// hostNetworkProxyPorts are the ports bound on the nodes by the proxy of a gateway in host network mode, besides its
+// listener ports: the Envoy admin, istio-agent, status and Prometheus ports. Two gateways in host network mode thus
+// never fit on the same node.
+var hostNetworkProxyPorts = []int32{15000, 15020, 15021, 15090}
+
+// hostNetworkPorts returns the ports bound on the nodes by the pods of a gateway in host network mode.
+func hostNetworkPorts(input TemplateInput) sets.Set[int32] {
+	res := sets.New(hostNetworkProxyPorts...)
+	for _, p := range input.Ports {
+		res.Insert(p.Port)
+	}
+	return res
+}
+
+// hasPrivilegedPort returns whether one of the ports is below 1024, which only root may bind without the network
+// sysctl lowering the unprivileged ports.
+func hasPrivilegedPort(ports []corev1.ServicePort) bool {
+	return slices.ContainsFunc(ports, func(p corev1.ServicePort) bool {
+		return p.Port < 1024
+	})
+}
+
+// nodeSelectorsOverlap returns whether pods with the node selectors may be scheduled to the same nodes, which is the
+// case unless they select different values of the same label. The affinities of the pods are not considered, so the
+// pods may still never share a node.
+func nodeSelectorsOverlap(a, b map[string]string) bool {
+	for k, v := range a {
+		if other, f := b[k]; f && other != v {
+			return false
+		}
+	}
+	return true
+}
+
+// olderGateway returns whether the gateway a was created before the gateway b, which wins their port conflicts.
+func olderGateway(a, b *gateway.Gateway) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return config.NamespacedName(a).String() < config.NamespacedName(b).String()
+}
+
+// hostPortsCondition returns the HostPortsAvailable condition of a gateway in host network mode, false if one of the
+// ports bound by its pods is bound by the pods of an older gateway in host network mode which may be scheduled to the
+// same nodes. The older gateway keeps its ports, so the conflict is stable regardless of the order of the reconciles.
+// The gateways sharing a deployment are compared as the single workload they render, of the age of its owner.
+func (d *DeploymentController) hostPortsCondition(gw gateway.Gateway, input TemplateInput) metav1.Condition {
+	self := d.sharedGateway(gw)
+	ports := hostNetworkPorts(input)
+	workload := types.NamespacedName{Namespace: gw.Namespace, Name: managedResourceName(gw.Name, gw.Annotations, &gw.Spec)}
+	seen := sets.New(workload)
+	var conflicts []string
+	for _, g := range d.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+		if g.DeletionTimestamp != nil || !IsManaged(&g.Spec) {
+			continue
+		}
+		other := types.NamespacedName{Namespace: g.Namespace, Name: managedResourceName(g.Name, g.Annotations, &g.Spec)}
+		if seen.InsertContains(other) {
+			continue
+		}
+		gi, f := d.classInfo(string(g.Spec.GatewayClassName))
+		if !f {
+			continue
+		}
+		params, err := d.classParametersOf(gi)
+		if err != nil || !params.hostNetwork {
+			continue
+		}
+		shared := d.sharedGateway(*g)
+		if !olderGateway(&shared, &self) {
+			continue
+		}
+		// The gateways which cannot be rendered are not deployed, and bind no port
+		otherInput, err := d.templateInput(shared, params)
+		if err != nil || !nodeSelectorsOverlap(input.Scheduling.NodeSelector, otherInput.Scheduling.NodeSelector) {
+			continue
+		}
+		if common := ports.Intersection(hostNetworkPorts(otherInput)); !common.IsEmpty() {
+			conflicts = append(conflicts, fmt.Sprintf("ports %v are bound by the pods of gateway %v on the same nodes",
+				sets.SortedList(common), config.NamespacedName(&shared)))
+		}
+	}
+	if len(conflicts) == 0 {
+		return metav1.Condition{
+			Type:    gatewayConditionHostPortsAvailable,
+			Status:  kstatus.StatusTrue,
+			Reason:  reasonHostPortsAvailable,
+			Message: "The ports of the gateway are not bound by another gateway on the same nodes",
+		}
+	}
+	// The gateways are listed in no particular order, and the message is sorted not to update the status needlessly
+	slices.Sort(conflicts)
+	return metav1.Condition{
+		Type:    gatewayConditionHostPortsAvailable,
+		Status:  kstatus.StatusFalse,
+		Reason:  reasonHostPortConflict,
+		Message: strings.Join(conflicts, "; "),
+	}
+}
+
+// hostNetworkGatewayKeys returns the other gateways of the classes in host network mode if the gateway is in host
+// network mode, recomputed on its changes as it may start or stop conflicting with them.
+func (d *DeploymentController) hostNetworkGatewayKeys(gw *gateway.Gateway) []types.NamespacedName {
+	if !d.hostNetworkClass(string(gw.Spec.GatewayClassName)) {
+		return nil
+	}
+	var res []types.NamespacedName
+	for _, g := range d.gateways.List(metav1.NamespaceAll, klabels.Everything()) {
+		if config.NamespacedName(g) != config.NamespacedName(gw) && d.hostNetworkClass(string(g.Spec.GatewayClassName)) {
+			res = append(res, config.NamespacedName(g))
+		}
+	}
+	return res
+}
+
+// hostNetworkClass returns whether the gateways of the class are deployed in host network mode.
+func (d *DeploymentController) hostNetworkClass(name string) bool {
+	gi, f := d.classInfo(name)
+	if !f {
+		return false
+	}
+	params, err := d.classParametersOf(gi)
+	return err == nil && params.hostNetwork
+}
@@ -0,0 +1,127 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestNodeSelectorsOverlap(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b map[string]string
+		want bool
+	}{
+		{name: "none", want: true},
+		{name: "one", a: map[string]string{"pool": "ingress"}, want: true},
+		{name: "same", a: map[string]string{"pool": "ingress"}, b: map[string]string{"pool": "ingress"}, want: true},
+		{name: "other labels", a: map[string]string{"pool": "ingress"}, b: map[string]string{"zone": "a"}, want: true},
+		{name: "different", a: map[string]string{"pool": "ingress"}, b: map[string]string{"pool": "edge"}, want: false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, nodeSelectorsOverlap(tt.a, tt.b), tt.want)
+			assert.Equal(t, nodeSelectorsOverlap(tt.b, tt.a), tt.want)
+		})
+	}
+}
+
+func hostNetworkGateway(name string, created time.Time, port int, annotations map[string]string) *v1beta1.Gateway {
+	return &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1beta1.GatewaySpec{
+			GatewayClassName: "host-network",
+			Listeners:        []v1beta1.Listener{{Name: "http", Port: v1beta1.PortNumber(port), Protocol: v1beta1.HTTPProtocolType}},
+		},
+	}
+}
+
+func TestHostPortsCondition(t *testing.T) {
+	client := kube.NewFakeClient(hostNetworkConfigMap)
+	d := &DeploymentController{
+		client:          client,
+		systemNamespace: "istio-system",
+		namespaces:      kclient.New[*corev1.Namespace](client),
+		gateways:        kclient.New[*v1beta1.Gateway](client),
+		gatewayClasses:  kclient.New[*v1beta1.GatewayClass](client),
+		configMaps:      kclient.NewFiltered[*corev1.ConfigMap](client, kclient.Filter{LabelSelector: TemplateConfigMapLabel}),
+	}
+	clienttest.Wrap(t, d.gatewayClasses).Create(hostNetworkGatewayClass)
+	now := time.Now()
+	gateways := clienttest.Wrap(t, d.gateways)
+	gateways.Create(hostNetworkGateway("first", now, 80,
+		map[string]string{gatewayScheduling: `{"nodeSelector":{"pool":"ingress"}}`}))
+	gateways.Create(hostNetworkGateway("edge", now.Add(time.Minute), 80,
+		map[string]string{gatewayScheduling: `{"nodeSelector":{"pool":"edge"}}`}))
+	gateways.Create(hostNetworkGateway("second", now.Add(2*time.Minute), 80, nil))
+	gateways.Create(&v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-class", Namespace: "default", CreationTimestamp: metav1.NewTime(now)},
+		Spec: v1beta1.GatewaySpec{
+			GatewayClassName: DefaultClassName,
+			Listeners:        []v1beta1.Listener{{Name: "http", Port: 80, Protocol: v1beta1.HTTPProtocolType}},
+		},
+	})
+	client.RunAndWait(test.NewStop(t))
+
+	gi, _ := d.classInfo("host-network")
+	params, err := d.classParametersOf(gi)
+	assert.NoError(t, err)
+	condition := func(name string) metav1.Condition {
+		gw := d.gateways.Get(name, "default")
+		input, err := d.templateInput(*gw, params)
+		assert.NoError(t, err)
+		return d.hostPortsCondition(*gw, input)
+	}
+
+	// The oldest gateway keeps its ports
+	got := condition("first")
+	assert.Equal(t, got.Type, gatewayConditionHostPortsAvailable)
+	assert.Equal(t, got.Status, kstatus.StatusTrue)
+	// The gateways scheduled to other nodes do not conflict
+	assert.Equal(t, condition("edge").Status, kstatus.StatusTrue)
+	// The gateways without node selector may be scheduled to the nodes of all the others
+	got = condition("second")
+	assert.Equal(t, got.Status, kstatus.StatusFalse)
+	assert.Equal(t, got.Reason, reasonHostPortConflict)
+	assert.Equal(t, got.Message, "ports [80 15000 15020 15021 15090] are bound by the pods of gateway default/edge on the same nodes; "+
+		"ports [80 15000 15020 15021 15090] are bound by the pods of gateway default/first on the same nodes")
+
+	keys := d.hostNetworkGatewayKeys(d.gateways.Get("first", "default"))
+	slices.SortFunc(keys, func(a, b types.NamespacedName) bool { return a.String() < b.String() })
+	assert.Equal(t, keys, []types.NamespacedName{
+		{Namespace: "default", Name: "edge"},
+		{Namespace: "default", Name: "second"},
+	})
+	assert.Equal(t, d.hostNetworkGatewayKeys(d.gateways.Get("other-class", "default")), nil)
+}
@@ -0,0 +1,59 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// gatewayInfrastructure holds the labels and annotations stamped onto the resources generated for a gateway, in the
+// JSON format of the spec.infrastructure stanza of the Gateway API: {"labels":{...},"annotations":{...}}.
+// The Gateway API version we build against predates spec.infrastructure, so it is read from this annotation until
+// the field is available. Unlike the other Gateway annotations, it is not itself copied to the generated resources.
+const gatewayInfrastructure = "gateway.istio.io/infrastructure"
+
+// InfrastructureInput holds the labels and annotations added to the resources generated for a gateway, like the
+// Service, so they can be customized (e.g. with cloud load balancer annotations) independently of the Gateway's own.
+type InfrastructureInput struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// extractInfrastructure reads the infrastructure labels and annotations of a gateway from its annotations.
+func extractInfrastructure(gwAnnotations map[string]string) (InfrastructureInput, error) {
+	var res InfrastructureInput
+	raw, f := gwAnnotations[gatewayInfrastructure]
+	if !f {
+		return res, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		return InfrastructureInput{}, fmt.Errorf("invalid %v annotation: %v", gatewayInfrastructure, err)
+	}
+	for k, v := range res.Labels {
+		if errs := append(validation.IsQualifiedName(k), validation.IsValidLabelValue(v)...); len(errs) > 0 {
+			return InfrastructureInput{}, fmt.Errorf("invalid %v label %q: %v", gatewayInfrastructure, k, strings.Join(errs, "; "))
+		}
+	}
+	for k := range res.Annotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return InfrastructureInput{}, fmt.Errorf("invalid %v annotation %q: %v", gatewayInfrastructure, k, strings.Join(errs, "; "))
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractInfrastructure(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		want      InfrastructureInput
+		wantError bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "labels and annotations",
+			gw: map[string]string{
+				gatewayInfrastructure: `{"labels":{"team":"a"},"annotations":{"service.beta.kubernetes.io/aws-load-balancer-type":"nlb"}}`,
+			},
+			want: InfrastructureInput{
+				Labels:      map[string]string{"team": "a"},
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			},
+		},
+		{
+			name:      "invalid json",
+			gw:        map[string]string{gatewayInfrastructure: `{"labels":`},
+			wantError: true,
+		},
+		{
+			name:      "invalid label value",
+			gw:        map[string]string{gatewayInfrastructure: `{"labels":{"team":"not a value"}}`},
+			wantError: true,
+		},
+		{
+			name:      "invalid annotation key",
+			gw:        map[string]string{gatewayInfrastructure: `{"annotations":{"a/b/c":"x"}}`},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractInfrastructure(tt.gw)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
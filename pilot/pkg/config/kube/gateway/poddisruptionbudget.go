@@ -0,0 +1,79 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// gatewayPDBMinAvailable sets the minimum number, or percentage, of the gateway pods which must remain available
+	// during voluntary disruptions such as node drains.
+	gatewayPDBMinAvailable = "gateway.istio.io/pdb-min-available"
+	// gatewayPDBMaxUnavailable sets the maximum number, or percentage, of the gateway pods which may be unavailable
+	// during voluntary disruptions. It is exclusive with gatewayPDBMinAvailable.
+	gatewayPDBMaxUnavailable = "gateway.istio.io/pdb-max-unavailable"
+)
+
+// PodDisruptionBudgetInput configures the PodDisruptionBudget rendered for a gateway. Exactly one of the fields is set.
+type PodDisruptionBudgetInput struct {
+	MinAvailable   string
+	MaxUnavailable string
+}
+
+// extractPodDisruptionBudget builds the PodDisruptionBudget configuration for a gateway. The setting is read from the
+// Gateway annotations, falling back to the annotations of the Gateway's namespace. If neither annotation is set, nil is
+// returned and no PodDisruptionBudget is rendered.
+func extractPodDisruptionBudget(gwAnnotations, nsAnnotations map[string]string) (*PodDisruptionBudgetInput, error) {
+	annotations := gwAnnotations
+	if _, f := gwAnnotations[gatewayPDBMinAvailable]; !f {
+		if _, f := gwAnnotations[gatewayPDBMaxUnavailable]; !f {
+			annotations = nsAnnotations
+		}
+	}
+	minAvailable, minSet := annotations[gatewayPDBMinAvailable]
+	maxUnavailable, maxSet := annotations[gatewayPDBMaxUnavailable]
+	switch {
+	case minSet && maxSet:
+		return nil, fmt.Errorf("only one of %v and %v may be set", gatewayPDBMinAvailable, gatewayPDBMaxUnavailable)
+	case minSet:
+		if err := validateIntOrPercent(gatewayPDBMinAvailable, minAvailable); err != nil {
+			return nil, err
+		}
+		return &PodDisruptionBudgetInput{MinAvailable: minAvailable}, nil
+	case maxSet:
+		if err := validateIntOrPercent(gatewayPDBMaxUnavailable, maxUnavailable); err != nil {
+			return nil, err
+		}
+		return &PodDisruptionBudgetInput{MaxUnavailable: maxUnavailable}, nil
+	}
+	return nil, nil
+}
+
+// validateIntOrPercent checks the value is a non-negative integer or a percentage between 0% and 100%.
+func validateIntOrPercent(key, v string) error {
+	if p, ok := strings.CutSuffix(v, "%"); ok {
+		if i, err := strconv.Atoi(p); err != nil || i < 0 || i > 100 {
+			return fmt.Errorf("invalid %v annotation %q: must be a percentage between 0%% and 100%%", key, v)
+		}
+		return nil
+	}
+	if i, err := strconv.Atoi(v); err != nil || i < 0 {
+		return fmt.Errorf("invalid %v annotation %q: must be a non-negative integer or a percentage", key, v)
+	}
+	return nil
+}
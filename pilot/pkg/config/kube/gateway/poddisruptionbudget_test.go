@@ -0,0 +1,81 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractPodDisruptionBudget(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		ns        map[string]string
+		want      *PodDisruptionBudgetInput
+		wantError bool
+	}{
+		{
+			name: "disabled",
+			want: nil,
+		},
+		{
+			name: "min available",
+			gw:   map[string]string{gatewayPDBMinAvailable: "1"},
+			want: &PodDisruptionBudgetInput{MinAvailable: "1"},
+		},
+		{
+			name: "max unavailable percentage",
+			gw:   map[string]string{gatewayPDBMaxUnavailable: "25%"},
+			want: &PodDisruptionBudgetInput{MaxUnavailable: "25%"},
+		},
+		{
+			name: "namespace default",
+			ns:   map[string]string{gatewayPDBMinAvailable: "50%"},
+			want: &PodDisruptionBudgetInput{MinAvailable: "50%"},
+		},
+		{
+			name: "gateway overrides namespace",
+			gw:   map[string]string{gatewayPDBMaxUnavailable: "1"},
+			ns:   map[string]string{gatewayPDBMinAvailable: "50%"},
+			want: &PodDisruptionBudgetInput{MaxUnavailable: "1"},
+		},
+		{
+			name:      "both",
+			gw:        map[string]string{gatewayPDBMinAvailable: "1", gatewayPDBMaxUnavailable: "1"},
+			wantError: true,
+		},
+		{
+			name:      "invalid percentage",
+			gw:        map[string]string{gatewayPDBMinAvailable: "150%"},
+			wantError: true,
+		},
+		{
+			name:      "negative",
+			gw:        map[string]string{gatewayPDBMaxUnavailable: "-1"},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractPodDisruptionBudget(tt.gw, tt.ns)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
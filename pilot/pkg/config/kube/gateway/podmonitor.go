@@ -0,0 +1,42 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"istio.io/istio/pkg/kube/controllers"
+)
+
+// PodMonitorGVR is the resource of the Prometheus Operator PodMonitors, rendered for the gateways when
+// features.EnableGatewayPodMonitor is set. It is not part of the schema, as the types of the Prometheus Operator are
+// not a dependency of istiod.
+var PodMonitorGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "podmonitors",
+}
+
+// podMonitorKind is the kind of the PodMonitors.
+const podMonitorKind = "PodMonitor"
+
+// generatedResourceGVR returns the resource of a resource generated for a gateway.
+func generatedResourceGVR(us unstructured.Unstructured) (schema.GroupVersionResource, error) {
+	if us.GroupVersionKind() == PodMonitorGVR.GroupVersion().WithKind(podMonitorKind) {
+		return PodMonitorGVR, nil
+	}
+	return controllers.UnstructuredToGVR(us)
+}
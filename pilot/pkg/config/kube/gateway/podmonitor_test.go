@@ -0,0 +1,70 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestPodMonitor(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(map[bool]string{false: "disabled", true: "enabled"}[enabled], func(t *testing.T) {
+			test.SetForTest(t, &features.EnableGatewayPodMonitor, enabled)
+			c := kube.NewFakeClient()
+			d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+			monitors := make(chan unstructured.Unstructured, 10)
+			d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+				if g == PodMonitorGVR {
+					us := unstructured.Unstructured{}
+					if err := json.Unmarshal(data, &us.Object); err != nil {
+						return err
+					}
+					monitors <- us
+				}
+				return nil
+			}
+			stop := test.NewStop(t)
+			go d.Run(stop)
+			c.RunAndWait(stop)
+
+			clienttest.Wrap(t, d.gateways).Create(&v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+				Spec:       v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+			})
+			if !enabled {
+				assert.ChannelIsEmpty(t, monitors)
+				return
+			}
+			pm := assert.ChannelHasItem(t, monitors)
+			assert.Equal(t, pm.GetName(), "gw-istio")
+			selector, _, _ := unstructured.NestedStringMap(pm.Object, "spec", "selector", "matchLabels")
+			assert.Equal(t, selector, map[string]string{"istio.io/gateway-name": "gw"})
+			endpoints, _, _ := unstructured.NestedSlice(pm.Object, "spec", "podMetricsEndpoints")
+			assert.Equal(t, endpoints, []any{map[string]any{"port": "http-envoy-prom", "path": "/stats/prometheus"}})
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/api/annotation"
+	networking "istio.io/api/networking/v1beta1"
+	opconfig "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/util/sets"
+)
+
+const (
+	// gatewayProxyHub overrides the hub of the proxy image of a gateway, to run a canary proxy version on specific
+	// gateways.
+	gatewayProxyHub = "gateway.istio.io/proxy-hub"
+	// gatewayProxyTag overrides the tag of the proxy image of a gateway. The image type of the proxy, like distroless,
+	// is still appended to the tag.
+	gatewayProxyTag = "gateway.istio.io/proxy-tag"
+)
+
+// ProxyImageInput overrides the hub and tag of the proxy image of a gateway, those of the injection config if empty.
+type ProxyImageInput struct {
+	Hub string
+	Tag string
+}
+
+// errInvalidProxyImage is the error of the gateways with invalid proxy image overrides.
+var errInvalidProxyImage = errors.New("invalid proxy image")
+
+// extractProxyImage reads the overrides of the proxy image of a gateway from its annotations, and checks they form a
+// valid image reference. They conflict with the sidecar.istio.io/proxyImage annotation of the gateway pods, which
+// would otherwise silently replace the image.
+func extractProxyImage(annotations map[string]string, podAnnotations map[string]string) (ProxyImageInput, error) {
+	res := ProxyImageInput{Hub: annotations[gatewayProxyHub], Tag: annotations[gatewayProxyTag]}
+	_, hubFound := annotations[gatewayProxyHub]
+	_, tagFound := annotations[gatewayProxyTag]
+	if hubFound || tagFound {
+		for _, a := range []map[string]string{annotations, podAnnotations} {
+			if _, f := a[annotation.SidecarProxyImage.Name]; f {
+				return ProxyImageInput{}, fmt.Errorf("%v and %v annotations cannot be set with the %v annotation",
+					gatewayProxyHub, gatewayProxyTag, annotation.SidecarProxyImage.Name)
+			}
+		}
+	}
+	if hubFound {
+		if res.Hub == "" {
+			return ProxyImageInput{}, fmt.Errorf("%v annotation must not be empty", gatewayProxyHub)
+		}
+		if _, err := name.NewRepository(res.Hub + "/proxyv2"); err != nil {
+			return ProxyImageInput{}, fmt.Errorf("invalid %v annotation %q: %v", gatewayProxyHub, res.Hub, err)
+		}
+	}
+	if tagFound {
+		if res.Tag == "" {
+			return ProxyImageInput{}, fmt.Errorf("%v annotation must not be empty", gatewayProxyTag)
+		}
+		if _, err := name.NewTag("proxyv2:" + res.Tag); err != nil {
+			return ProxyImageInput{}, fmt.Errorf("invalid %v annotation %q: %v", gatewayProxyTag, res.Tag, err)
+		}
+	}
+	return res, nil
+}
+
+// proxyImage returns the proxy image of a gateway: the image of the injection config, with the hub and tag
+// overridden by the gateway.
+func proxyImage(values *opconfig.Values, image *networking.ProxyImage, annotations map[string]string, override ProxyImageInput) string {
+	if override.Hub != "" || override.Tag != "" {
+		if values == nil {
+			values = &opconfig.Values{}
+		} else {
+			values = proto.Clone(values).(*opconfig.Values)
+		}
+		if values.Global == nil {
+			values.Global = &opconfig.GlobalConfig{}
+		}
+		if override.Hub != "" {
+			values.Global.Hub = override.Hub
+		}
+		if override.Tag != "" {
+			values.Global.Tag = structpb.NewStringValue(override.Tag)
+		}
+	}
+	return inject.ProxyImage(values, image, annotations)
+}
+
+// gatewayConditionProxyImageResolved reports whether the proxy image of the gateway is pulled by its pods, false if
+// one of them cannot pull it, for instance as a canary tag or hub overridden by the gateway does not exist.
+const gatewayConditionProxyImageResolved = "gateway.istio.io/ProxyImageResolved"
+
+// The reasons of the gatewayConditionProxyImageResolved condition.
+const (
+	reasonProxyImageResolved   = "Resolved"
+	reasonProxyImagePending    = "Pending"
+	reasonProxyImagePullFailed = "PullFailed"
+)
+
+// imagePullFailures are the reasons of the waiting containers whose image cannot be pulled.
+var imagePullFailures = sets.New("ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull")
+
+// proxyImageCondition returns the ProxyImageResolved condition of the gateway, from the status of the proxy container
+// of its pods. It is unknown until a pod pulled the image or failed to.
+func (d *DeploymentController) proxyImageCondition(namespace string, input TemplateInput) metav1.Condition {
+	cond := metav1.Condition{Type: gatewayConditionProxyImageResolved}
+	var pulled int
+	var failures []string
+	pods := d.pods.List(namespace, klabels.SelectorFromSet(map[string]string{constants.GatewayNameLabel: input.Name}))
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != inject.ProxyContainerName {
+				continue
+			}
+			if w := cs.State.Waiting; w != nil && imagePullFailures.Contains(w.Reason) {
+				failures = append(failures, fmt.Sprintf("pod %s cannot pull %s: %s", pod.Name, cs.Image, w.Message))
+			} else if cs.ImageID != "" {
+				pulled++
+			}
+		}
+	}
+	switch {
+	case len(failures) > 0:
+		cond.Status = kstatus.StatusFalse
+		cond.Reason = reasonProxyImagePullFailed
+		slices.Sort(failures)
+		cond.Message = strings.Join(failures, "; ")
+	case pulled > 0:
+		cond.Status = kstatus.StatusTrue
+		cond.Reason = reasonProxyImageResolved
+		cond.Message = fmt.Sprintf("The proxy image is pulled by %d pods", pulled)
+	default:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = reasonProxyImagePending
+		cond.Message = "Waiting for the pods of the gateway to pull the proxy image"
+	}
+	return cond
+}
+
+// podGateways returns the outputs of a gateway pod: the gateway named by its label, and the gateways sharing its
+// deployment.
+func (d *DeploymentController) podGateways(o controllers.Object) []types.NamespacedName {
+	gw := d.gateways.Get(o.GetLabels()[constants.GatewayNameLabel], o.GetNamespace())
+	if gw == nil {
+		return nil
+	}
+	return append([]types.NamespacedName{config.NamespacedName(gw)}, d.sharingGatewayKeys(gw)...)
+}
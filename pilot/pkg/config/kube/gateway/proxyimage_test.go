@@ -0,0 +1,139 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/annotation"
+	opconfig "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractProxyImage(t *testing.T) {
+	cases := []struct {
+		name string
+		gw   map[string]string
+		// pod are the annotations of the gateway pods
+		pod       map[string]string
+		want      ProxyImageInput
+		wantError bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "hub and tag",
+			gw:   map[string]string{gatewayProxyHub: "gcr.io/istio-testing", gatewayProxyTag: "1.19-alpha.1"},
+			want: ProxyImageInput{Hub: "gcr.io/istio-testing", Tag: "1.19-alpha.1"},
+		},
+		{
+			name: "tag",
+			gw:   map[string]string{gatewayProxyTag: "canary"},
+			want: ProxyImageInput{Tag: "canary"},
+		},
+		{
+			name:      "empty hub",
+			gw:        map[string]string{gatewayProxyHub: ""},
+			wantError: true,
+		},
+		{
+			name:      "invalid hub",
+			gw:        map[string]string{gatewayProxyHub: "gcr.io/Istio Testing"},
+			wantError: true,
+		},
+		{
+			name:      "invalid tag",
+			gw:        map[string]string{gatewayProxyTag: "1.19:latest"},
+			wantError: true,
+		},
+		{
+			name:      "proxy image annotation",
+			gw:        map[string]string{gatewayProxyTag: "canary", annotation.SidecarProxyImage.Name: "proxyv2:1.18.0"},
+			wantError: true,
+		},
+		{
+			name:      "pod proxy image annotation",
+			gw:        map[string]string{gatewayProxyHub: "gcr.io/istio-testing"},
+			pod:       map[string]string{annotation.SidecarProxyImage.Name: "proxyv2:1.18.0"},
+			wantError: true,
+		},
+		{
+			name: "proxy image annotation without overrides",
+			gw:   map[string]string{annotation.SidecarProxyImage.Name: "proxyv2:1.18.0"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractProxyImage(tt.gw, tt.pod)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestProxyImage(t *testing.T) {
+	values := &opconfig.Values{Global: &opconfig.GlobalConfig{Hub: "docker.io/istio", Tag: structpb.NewStringValue("1.18.0")}}
+	assert.Equal(t, proxyImage(values, nil, nil, ProxyImageInput{}), "docker.io/istio/proxyv2:1.18.0")
+	assert.Equal(t, proxyImage(values, nil, nil, ProxyImageInput{Tag: "1.19.0"}), "docker.io/istio/proxyv2:1.19.0")
+	assert.Equal(t, proxyImage(values, nil, nil, ProxyImageInput{Hub: "gcr.io/istio-testing"}), "gcr.io/istio-testing/proxyv2:1.18.0")
+	// The image type is still appended to the tag
+	distroless := map[string]string{annotation.SidecarProxyImageType.Name: "distroless"}
+	assert.Equal(t, proxyImage(values, nil, distroless, ProxyImageInput{Tag: "1.19.0"}), "docker.io/istio/proxyv2:1.19.0-distroless")
+	// The injection config is left untouched
+	assert.Equal(t, values.Global.Hub, "docker.io/istio")
+}
+
+func TestProxyImageCondition(t *testing.T) {
+	client := kube.NewFakeClient()
+	d := &DeploymentController{pods: kclient.NewFiltered[*corev1.Pod](client, kclient.Filter{LabelSelector: constants.GatewayNameLabel})}
+	client.RunAndWait(test.NewStop(t))
+	pods := clienttest.Wrap(t, d.pods)
+	pod := func(name string, status corev1.ContainerStatus) *corev1.Pod {
+		status.Name = "istio-proxy"
+		status.Image = "gcr.io/istio-testing/proxyv2:canary"
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{constants.GatewayNameLabel: "gw"}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	input := TemplateInput{Gateway: &v1beta1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw"}}}
+	reason := func() string {
+		return d.proxyImageCondition("default", input).Reason
+	}
+
+	assert.Equal(t, reason(), reasonProxyImagePending)
+	pods.Create(pod("gw-1", corev1.ContainerStatus{ImageID: "gcr.io/istio-testing/proxyv2@sha256:1234"}))
+	assert.EventuallyEqual(t, reason, reasonProxyImageResolved)
+	// A single pod failing to pull the image is reported
+	pods.Create(pod("gw-2", corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+		Reason:  "ImagePullBackOff",
+		Message: "Back-off pulling image",
+	}}}))
+	assert.EventuallyEqual(t, reason, reasonProxyImagePullFailed)
+	assert.Equal(t, d.proxyImageCondition("default", input).Message, "pod gw-2 cannot pull gcr.io/istio-testing/proxyv2:canary: Back-off pulling image")
+}
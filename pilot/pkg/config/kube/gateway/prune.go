@@ -0,0 +1,140 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/kclient"
+	istiolog "istio.io/pkg/log"
+)
+
+// prunableResource lists the existing resources of a kind the templates generate, to delete those of a gateway the
+// templates no longer render, like the PodDisruptionBudget of a gateway whose annotation was removed.
+type prunableResource struct {
+	gvr  schema.GroupVersionResource
+	list func(namespace string) []controllers.Object
+}
+
+// newPrunableResources returns the kinds of the generated resources pruned once no longer rendered. The workloads,
+// Services and ServiceAccounts are read from the informers of the controller, the other kinds from their metadata.
+func (d *DeploymentController) newPrunableResources(client kube.Client) []prunableResource {
+	managed := kclient.Filter{LabelSelector: constants.ManagedGatewayLabel}
+	return []prunableResource{
+		{gvr: gvr.Deployment, list: listOf[*appsv1.Deployment](d.deployments)},
+		{gvr: gvr.DaemonSet, list: listOf[*appsv1.DaemonSet](d.daemonSets)},
+		{gvr: gvr.StatefulSet, list: listOf[*appsv1.StatefulSet](d.statefulSets)},
+		{gvr: gvr.Service, list: listOf[*corev1.Service](d.services)},
+		{gvr: gvr.ServiceAccount, list: listOf[*corev1.ServiceAccount](d.serviceAccounts)},
+		{gvr: gvr.HorizontalPodAutoscaler, list: listOf(kclient.NewMetadata(client, gvr.HorizontalPodAutoscaler, managed))},
+		{gvr: gvr.PodDisruptionBudget, list: listOf(kclient.NewMetadata(client, gvr.PodDisruptionBudget, managed))},
+		{gvr: gvr.NetworkPolicy, list: listOf(kclient.NewMetadata(client, gvr.NetworkPolicy, managed))},
+	}
+}
+
+// listOf returns a function listing the objects of the reader in a namespace.
+func listOf[T controllers.Object](r kclient.Reader[T]) func(namespace string) []controllers.Object {
+	return func(namespace string) []controllers.Object {
+		objs := r.List(namespace, klabels.Everything())
+		res := make([]controllers.Object, 0, len(objs))
+		for _, o := range objs {
+			res = append(res, o)
+		}
+		return res
+	}
+}
+
+// prune deletes the resources generated for the gateway which are no longer rendered: those of its namespace with
+// the managed label and owned by the gateway, missing from the rendered resources.
+func (d *DeploymentController) prune(log *istiolog.Scope, gw gateway.Gateway, rendered []generatedResource) error {
+	keep := make(map[generatedResource]struct{}, len(rendered))
+	for _, r := range rendered {
+		keep[r.key()] = struct{}{}
+	}
+	for _, p := range d.prunable {
+		for _, o := range p.list(gw.Namespace) {
+			if !generatedFor(o, gw) {
+				continue
+			}
+			r := generatedResource{gvr: p.gvr, name: o.GetName(), namespace: o.GetNamespace()}
+			if _, f := keep[r.key()]; f {
+				continue
+			}
+			log.Infof("deleting %v %v/%v which is no longer rendered", p.gvr.Resource, r.namespace, r.name)
+			if err := d.deleter(p.gvr, r.name, r.namespace); err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("delete %v %v/%v: %v", p.gvr.Resource, r.namespace, r.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// existingGeneratedFor returns whether the resource exists, generated for the gateway. The resources of the kinds
+// which are not listed, like the PodMonitors, are left to the garbage collection of their owner references.
+func (d *DeploymentController) existingGeneratedFor(r generatedResource, gw gateway.Gateway) bool {
+	for _, p := range d.prunable {
+		if (generatedResource{gvr: p.gvr}).key() != (generatedResource{gvr: r.gvr}).key() {
+			continue
+		}
+		for _, o := range p.list(r.namespace) {
+			if o.GetName() == r.name {
+				return generatedFor(o, gw)
+			}
+		}
+	}
+	return false
+}
+
+// generatedFor returns whether the object was generated for the gateway: it has the managed label and an owner
+// reference to the gateway. The ServiceAccounts named by the gatewaySAOverride annotation are not owned by the
+// gateway, as they may be used by other workloads.
+func generatedFor(o metav1.Object, gw gateway.Gateway) bool {
+	_, managed := o.GetLabels()[constants.ManagedGatewayLabel]
+	return managed && ownedByGateway(o, gw)
+}
+
+// key returns the resource without the version of its kind, the resources rendered in another version than the
+// one listed being the same.
+func (r generatedResource) key() generatedResource {
+	r.gvr.Version = ""
+	return r
+}
+
+// ownedByGateway returns whether the object has an owner reference to the gateway.
+func ownedByGateway(o metav1.Object, gw gateway.Gateway) bool {
+	for _, ref := range o.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != gvk.KubernetesGateway.Group || ref.Kind != gvk.KubernetesGateway.Kind {
+			continue
+		}
+		if ref.Name == gw.Name && (gw.UID == "" || ref.UID == gw.UID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/util/sets"
+)
+
+func TestGatewayPrune(t *testing.T) {
+	owner := func(name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: gvk.KubernetesGateway.GroupVersion(),
+			Kind:       gvk.KubernetesGateway.Kind,
+			Name:       name,
+		}}
+	}
+	managed := map[string]string{constants.ManagedGatewayLabel: "true"}
+	meta := func(name string, labels map[string]string, owners []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, OwnerReferences: owners}
+	}
+	partial := func(g schema.GroupVersionResource, kind string, m metav1.ObjectMeta) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: g.GroupVersion().String(), Kind: kind}, ObjectMeta: m}
+	}
+	cases := []struct {
+		name    string
+		class   string
+		objects []runtime.Object
+		// metadata are the objects only watched through their metadata
+		metadata map[schema.GroupVersionResource]*metav1.PartialObjectMetadata
+		want     sets.String
+	}{
+		{
+			name:  "annotations removed",
+			class: DefaultClassName,
+			metadata: map[schema.GroupVersionResource]*metav1.PartialObjectMetadata{
+				gvr.PodDisruptionBudget:     partial(gvr.PodDisruptionBudget, "PodDisruptionBudget", meta("gw-istio", managed, owner("gw"))),
+				gvr.HorizontalPodAutoscaler: partial(gvr.HorizontalPodAutoscaler, "HorizontalPodAutoscaler", meta("gw-istio", managed, owner("gw"))),
+			},
+			want: sets.New("poddisruptionbudgets/default/gw-istio", "horizontalpodautoscalers/default/gw-istio"),
+		},
+		{
+			name:  "mode change",
+			class: daemonSetGatewayClass.Name,
+			objects: []runtime.Object{
+				// The Deployment is replaced by a DaemonSet of the same name
+				&appsv1.Deployment{ObjectMeta: meta("gw-daemonset", managed, owner("gw"))},
+				&appsv1.DaemonSet{ObjectMeta: meta("gw-daemonset", managed, owner("gw"))},
+				// Those of another gateway, and those the gateways do not own, are left
+				&appsv1.Deployment{ObjectMeta: meta("other-daemonset", managed, owner("other"))},
+				&appsv1.Deployment{ObjectMeta: meta("custom", managed, nil)},
+			},
+			want: sets.New("deployments/default/gw-daemonset"),
+		},
+		{
+			name:  "scale down",
+			class: statefulSetGatewayClass.Name,
+			objects: []runtime.Object{
+				// The Service of the second pod is no longer rendered with a single replica
+				&corev1.Service{ObjectMeta: meta("gw-statefulset-0", managed, owner("gw"))},
+				&corev1.Service{ObjectMeta: meta("gw-statefulset-1", managed, owner("gw"))},
+				// Nor the Deployment of the previous mode
+				&appsv1.Deployment{ObjectMeta: meta("gw-statefulset", managed, owner("gw"))},
+			},
+			want: sets.New("services/default/gw-statefulset-1", "deployments/default/gw-statefulset"),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := kube.NewFakeClient(append([]runtime.Object{daemonSetConfigMap, statefulSetConfigMap}, tt.objects...)...)
+			d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+			clienttest.Wrap(t, d.gatewayClasses).Create(daemonSetGatewayClass)
+			clienttest.Wrap(t, d.gatewayClasses).Create(statefulSetGatewayClass)
+			d.patcher = func(schema.GroupVersionResource, string, string, []byte, ...string) error {
+				return nil
+			}
+			var mu sync.Mutex
+			deleted := sets.New[string]()
+			d.deleter = func(g schema.GroupVersionResource, name string, namespace string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				deleted.Insert(fmt.Sprintf("%s/%s/%s", g.Resource, namespace, name))
+				return nil
+			}
+			stop := test.NewStop(t)
+			gws := clienttest.Wrap(t, d.gateways)
+			go d.Run(stop)
+			c.RunAndWait(stop)
+
+			for g, obj := range tt.metadata {
+				_, err := c.Metadata().Resource(g).Namespace("default").(metadatafake.MetadataClient).CreateFake(obj, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			// Wait for the existing resources to be listed before they are pruned
+			assert.EventuallyEqual(t, func() int {
+				n := 0
+				for _, p := range d.prunable {
+					n += len(p.list("default"))
+				}
+				return n
+			}, len(tt.objects)+len(tt.metadata))
+
+			gws.Create(&v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+				Spec:       v1beta1.GatewaySpec{GatewayClassName: v1beta1.ObjectName(tt.class)},
+			})
+			assert.EventuallyEqual(t, func() sets.String {
+				mu.Lock()
+				defer mu.Unlock()
+				return deleted.Copy()
+			}, tt.want)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"istio.io/api/annotation"
+)
+
+// gatewayReplicas sets the number of replicas of the gateway Deployment. It cannot be set together with autoscaling,
+// which owns the replicas.
+const gatewayReplicas = "gateway.istio.io/replicas"
+
+// ResourcesInput holds the resource requests and limits of the gateway proxy container.
+type ResourcesInput struct {
+	Requests map[string]string
+	Limits   map[string]string
+}
+
+// extractReplicas reads the replicas of the gateway Deployment from the Gateway annotations. If unset, nil is returned
+// and the replicas are left to Kubernetes or to the HorizontalPodAutoscaler.
+func extractReplicas(gwAnnotations map[string]string, autoscaling *AutoscalingInput) (*int32, error) {
+	v, f := gwAnnotations[gatewayReplicas]
+	if !f {
+		return nil, nil
+	}
+	if autoscaling != nil {
+		return nil, fmt.Errorf("%v cannot be set when autoscaling is enabled", gatewayReplicas)
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || i < 0 {
+		return nil, fmt.Errorf("invalid %v annotation %q: must be a non-negative integer", gatewayReplicas, v)
+	}
+	replicas := int32(i)
+	return &replicas, nil
+}
+
+// extractResources reads the resources of the gateway proxy container from the Gateway annotations, the same
+// sidecar.istio.io/proxy* annotations as the sidecars and the waypoints. If none is set, nil is returned and the
+// resources are left to the template.
+func extractResources(gwAnnotations map[string]string) (*ResourcesInput, error) {
+	res := &ResourcesInput{}
+	for _, r := range []struct {
+		annotation string
+		name       corev1.ResourceName
+		into       *map[string]string
+	}{
+		{annotation.SidecarProxyCPU.Name, corev1.ResourceCPU, &res.Requests},
+		{annotation.SidecarProxyMemory.Name, corev1.ResourceMemory, &res.Requests},
+		{annotation.SidecarProxyCPULimit.Name, corev1.ResourceCPU, &res.Limits},
+		{annotation.SidecarProxyMemoryLimit.Name, corev1.ResourceMemory, &res.Limits},
+	} {
+		v, f := gwAnnotations[r.annotation]
+		if !f {
+			continue
+		}
+		if _, err := resource.ParseQuantity(v); err != nil {
+			return nil, fmt.Errorf("invalid %v annotation %q: %v", r.annotation, v, err)
+		}
+		if *r.into == nil {
+			*r.into = map[string]string{}
+		}
+		(*r.into)[string(r.name)] = v
+	}
+	if res.Requests == nil && res.Limits == nil {
+		return nil, nil
+	}
+	for name, limit := range res.Limits {
+		request, f := res.Requests[name]
+		if !f {
+			continue
+		}
+		if r := resource.MustParse(request); r.Cmp(resource.MustParse(limit)) > 0 {
+			return nil, fmt.Errorf("%v request %v is greater than the limit %v", name, request, limit)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,114 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractReplicas(t *testing.T) {
+	cases := []struct {
+		name        string
+		gw          map[string]string
+		autoscaling *AutoscalingInput
+		want        *int32
+		wantError   bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "set",
+			gw:   map[string]string{gatewayReplicas: "3"},
+			want: ptr.Of(int32(3)),
+		},
+		{
+			name: "zero",
+			gw:   map[string]string{gatewayReplicas: "0"},
+			want: ptr.Of(int32(0)),
+		},
+		{
+			name:      "negative",
+			gw:        map[string]string{gatewayReplicas: "-1"},
+			wantError: true,
+		},
+		{
+			name:        "with autoscaling",
+			gw:          map[string]string{gatewayReplicas: "3"},
+			autoscaling: &AutoscalingInput{MinReplicas: 1, MaxReplicas: 5},
+			wantError:   true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractReplicas(tt.gw, tt.autoscaling)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestExtractResources(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		want      *ResourcesInput
+		wantError bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "requests and limits",
+			gw: map[string]string{
+				annotation.SidecarProxyCPU.Name:         "500m",
+				annotation.SidecarProxyMemory.Name:      "256Mi",
+				annotation.SidecarProxyMemoryLimit.Name: "1Gi",
+			},
+			want: &ResourcesInput{
+				Requests: map[string]string{"cpu": "500m", "memory": "256Mi"},
+				Limits:   map[string]string{"memory": "1Gi"},
+			},
+		},
+		{
+			name:      "invalid quantity",
+			gw:        map[string]string{annotation.SidecarProxyCPU.Name: "lots"},
+			wantError: true,
+		},
+		{
+			name: "request above limit",
+			gw: map[string]string{
+				annotation.SidecarProxyCPU.Name:      "2",
+				annotation.SidecarProxyCPULimit.Name: "1",
+			},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractResources(tt.gw)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
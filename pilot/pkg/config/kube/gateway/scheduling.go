@@ -0,0 +1,90 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// gatewayScheduling holds the scheduling constraints of the pods of a gateway, in JSON:
+// {"nodeSelector":{...},"tolerations":[...],"affinity":{...}}. Each constraint set replaces the one of the class
+// parameters, so a gateway can be pinned to dedicated nodes without a custom template.
+const gatewayScheduling = "gateway.istio.io/scheduling"
+
+// SchedulingInput holds the scheduling constraints of the pods of a gateway.
+type SchedulingInput struct {
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	Affinity     *corev1.Affinity    `json:"affinity,omitempty"`
+}
+
+// parseScheduling parses scheduling constraints, in JSON or YAML. Unknown fields are rejected, so that a misspelled
+// constraint is reported rather than ignored.
+func parseScheduling(raw string) (SchedulingInput, error) {
+	var res SchedulingInput
+	if err := yaml.UnmarshalStrict([]byte(raw), &res); err != nil {
+		return SchedulingInput{}, err
+	}
+	if err := validateNodeSelector(res.NodeSelector); err != nil {
+		return SchedulingInput{}, err
+	}
+	return res, nil
+}
+
+// validateNodeSelector returns an error if a node selector is not a valid label.
+func validateNodeSelector(nodeSelector map[string]string) error {
+	for k, v := range nodeSelector {
+		if errs := append(validation.IsQualifiedName(k), validation.IsValidLabelValue(v)...); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector %q: %v", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// validateClassName returns an error if the name of a PriorityClass or RuntimeClass is invalid.
+func validateClassName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %v", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// extractScheduling returns the scheduling constraints of the pods of a gateway: those of its gatewayScheduling
+// annotation, each defaulting to the constraint of the class parameters.
+func extractScheduling(gwAnnotations map[string]string, class SchedulingInput) (SchedulingInput, error) {
+	raw, f := gwAnnotations[gatewayScheduling]
+	if !f {
+		return class, nil
+	}
+	res, err := parseScheduling(raw)
+	if err != nil {
+		return SchedulingInput{}, fmt.Errorf("invalid %v annotation: %v", gatewayScheduling, err)
+	}
+	if res.NodeSelector == nil {
+		res.NodeSelector = class.NodeSelector
+	}
+	if res.Tolerations == nil {
+		res.Tolerations = class.Tolerations
+	}
+	if res.Affinity == nil {
+		res.Affinity = class.Affinity
+	}
+	return res, nil
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractScheduling(t *testing.T) {
+	class := SchedulingInput{
+		NodeSelector: map[string]string{"pool": "default"},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	}
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		class     SchedulingInput
+		want      SchedulingInput
+		wantError bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:  "class defaults",
+			class: class,
+			want:  class,
+		},
+		{
+			name:  "node selector override",
+			gw:    map[string]string{gatewayScheduling: `{"nodeSelector":{"pool":"ingress"}}`},
+			class: class,
+			want: SchedulingInput{
+				NodeSelector: map[string]string{"pool": "ingress"},
+				Tolerations:  class.Tolerations,
+			},
+		},
+		{
+			name: "affinity",
+			gw: map[string]string{gatewayScheduling: `{"affinity":{"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":` +
+				`{"nodeSelectorTerms":[{"matchExpressions":[{"key":"pool","operator":"In","values":["ingress"]}]}]}}}}`},
+			want: SchedulingInput{
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "pool",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"ingress"},
+						}}}},
+					},
+				}},
+			},
+		},
+		{
+			name:      "invalid json",
+			gw:        map[string]string{gatewayScheduling: `{"nodeSelector":`},
+			wantError: true,
+		},
+		{
+			name:      "unknown field",
+			gw:        map[string]string{gatewayScheduling: `{"nodeSelectors":{"pool":"ingress"}}`},
+			wantError: true,
+		},
+		{
+			name:      "invalid node selector",
+			gw:        map[string]string{gatewayScheduling: `{"nodeSelector":{"pool":"in gress"}}`},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractScheduling(tt.gw, tt.class)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// gatewayServiceAccountAnnotations holds the annotations added to the ServiceAccount generated for a gateway, in
+// JSON, like the cloud IAM identity the pods assume: {"eks.amazonaws.com/role-arn":"arn:aws:iam::..."} or
+// {"iam.gke.io/gcp-service-account":"..."}. The cloud providers project the tokens of the identity into the pods of
+// the annotated ServiceAccounts. Each annotation replaces the one of the class parameters.
+const gatewayServiceAccountAnnotations = "gateway.istio.io/service-account-annotations"
+
+// parseServiceAccountAnnotations parses the annotations of a ServiceAccount, in JSON or YAML.
+func parseServiceAccountAnnotations(raw string) (map[string]string, error) {
+	var res map[string]string
+	if err := yaml.UnmarshalStrict([]byte(raw), &res); err != nil {
+		return nil, err
+	}
+	if err := validateServiceAccountAnnotations(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// validateServiceAccountAnnotations returns an error if the name of an annotation of a ServiceAccount is invalid.
+func validateServiceAccountAnnotations(annotations map[string]string) error {
+	for k := range annotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation %q: %v", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// extractServiceAccountAnnotations returns the annotations of the ServiceAccount of a gateway: those of its
+// gatewayServiceAccountAnnotations annotation, merged over those of the class parameters.
+func extractServiceAccountAnnotations(gwAnnotations map[string]string, class map[string]string) (map[string]string, error) {
+	raw, f := gwAnnotations[gatewayServiceAccountAnnotations]
+	if !f {
+		return class, nil
+	}
+	res, err := parseServiceAccountAnnotations(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", gatewayServiceAccountAnnotations, err)
+	}
+	return mergeMaps(class, res), nil
+}
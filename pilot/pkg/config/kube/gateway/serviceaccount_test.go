@@ -0,0 +1,82 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractServiceAccountAnnotations(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		class     map[string]string
+		want      map[string]string
+		wantError bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:  "class",
+			class: map[string]string{"iam.gke.io/gcp-service-account": "gw@project.iam.gserviceaccount.com"},
+			want:  map[string]string{"iam.gke.io/gcp-service-account": "gw@project.iam.gserviceaccount.com"},
+		},
+		{
+			name: "gateway",
+			gw: map[string]string{
+				gatewayServiceAccountAnnotations: `{"eks.amazonaws.com/role-arn":"arn:aws:iam::123456789012:role/gw"}`,
+			},
+			want: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/gw"},
+		},
+		{
+			name: "gateway overrides class",
+			gw: map[string]string{
+				gatewayServiceAccountAnnotations: `{"eks.amazonaws.com/role-arn":"arn:aws:iam::123456789012:role/gw"}`,
+			},
+			class: map[string]string{
+				"eks.amazonaws.com/role-arn":               "arn:aws:iam::123456789012:role/default",
+				"eks.amazonaws.com/sts-regional-endpoints": "true",
+			},
+			want: map[string]string{
+				"eks.amazonaws.com/role-arn":               "arn:aws:iam::123456789012:role/gw",
+				"eks.amazonaws.com/sts-regional-endpoints": "true",
+			},
+		},
+		{
+			name:      "invalid json",
+			gw:        map[string]string{gatewayServiceAccountAnnotations: `{"a":`},
+			wantError: true,
+		},
+		{
+			name:      "invalid annotation",
+			gw:        map[string]string{gatewayServiceAccountAnnotations: `{"-invalid-":"a"}`},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractServiceAccountAnnotations(tt.gw, tt.class)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
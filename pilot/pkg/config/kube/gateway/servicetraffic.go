@@ -0,0 +1,93 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxSessionAffinityTimeoutSeconds is the maximum session sticky time allowed by Kubernetes, one day.
+const maxSessionAffinityTimeoutSeconds = 86400
+
+// ServiceTrafficInput configures the routing of the traffic of the gateway Service. The zero value keeps the defaults
+// of Kubernetes.
+type ServiceTrafficInput struct {
+	// ExternalTrafficPolicy set to Local preserves the source IP of the clients, only routing the external traffic to
+	// the gateway pods of the node receiving it.
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy
+	// HealthCheckNodePort is the node port of the health checks of the load balancer with the Local policy,
+	// allocated by Kubernetes if 0.
+	HealthCheckNodePort int32
+	// SessionAffinity set to ClientIP routes the connections of a client to the same gateway pod.
+	SessionAffinity corev1.ServiceAffinity
+	// SessionAffinityTimeoutSeconds is the maximum session sticky time with the ClientIP affinity, the default of
+	// Kubernetes if 0.
+	SessionAffinityTimeoutSeconds int32
+}
+
+// extractServiceTraffic returns the traffic configuration of the gateway Service from the annotations of the gateway.
+// The external traffic policy only applies to the Services exposed outside the cluster, of type LoadBalancer or
+// NodePort.
+func extractServiceTraffic(gwAnnotations map[string]string, serviceType corev1.ServiceType) (ServiceTrafficInput, error) {
+	res := ServiceTrafficInput{}
+	if v, f := gwAnnotations[externalTrafficPolicy]; f {
+		switch p := corev1.ServiceExternalTrafficPolicy(v); p {
+		case corev1.ServiceExternalTrafficPolicyCluster, corev1.ServiceExternalTrafficPolicyLocal:
+			res.ExternalTrafficPolicy = p
+		default:
+			return ServiceTrafficInput{}, fmt.Errorf("invalid %v annotation %q: must be %s or %s", externalTrafficPolicy, v,
+				corev1.ServiceExternalTrafficPolicyCluster, corev1.ServiceExternalTrafficPolicyLocal)
+		}
+		if serviceType != corev1.ServiceTypeLoadBalancer && serviceType != corev1.ServiceTypeNodePort {
+			return ServiceTrafficInput{}, fmt.Errorf("%v requires a %s or %s Service, got %s", externalTrafficPolicy,
+				corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort, serviceType)
+		}
+	}
+	if v, f := gwAnnotations[healthCheckNodePort]; f {
+		port, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return ServiceTrafficInput{}, fmt.Errorf("invalid %v annotation %q: must be a port number", healthCheckNodePort, v)
+		}
+		if serviceType != corev1.ServiceTypeLoadBalancer || res.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+			return ServiceTrafficInput{}, fmt.Errorf("%v requires a %s Service with the %s %v", healthCheckNodePort,
+				corev1.ServiceTypeLoadBalancer, corev1.ServiceExternalTrafficPolicyLocal, externalTrafficPolicy)
+		}
+		res.HealthCheckNodePort = int32(port)
+	}
+	if v, f := gwAnnotations[sessionAffinity]; f {
+		switch a := corev1.ServiceAffinity(v); a {
+		case corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP:
+			res.SessionAffinity = a
+		default:
+			return ServiceTrafficInput{}, fmt.Errorf("invalid %v annotation %q: must be %s or %s", sessionAffinity, v,
+				corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP)
+		}
+	}
+	if v, f := gwAnnotations[sessionAffinityTimeout]; f {
+		seconds, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || seconds < 1 || seconds > maxSessionAffinityTimeoutSeconds {
+			return ServiceTrafficInput{}, fmt.Errorf("invalid %v annotation %q: must be a number of seconds between 1 and %d",
+				sessionAffinityTimeout, v, maxSessionAffinityTimeoutSeconds)
+		}
+		if res.SessionAffinity != corev1.ServiceAffinityClientIP {
+			return ServiceTrafficInput{}, fmt.Errorf("%v requires the %s %v", sessionAffinityTimeout, corev1.ServiceAffinityClientIP, sessionAffinity)
+		}
+		res.SessionAffinityTimeoutSeconds = int32(seconds)
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestExtractServiceTraffic(t *testing.T) {
+	cases := []struct {
+		name        string
+		gw          map[string]string
+		serviceType corev1.ServiceType
+		want        ServiceTrafficInput
+		wantError   bool
+	}{
+		{
+			name:        "unset",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name: "local with health check node port",
+			gw: map[string]string{
+				externalTrafficPolicy: "Local",
+				healthCheckNodePort:   "30100",
+			},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			want: ServiceTrafficInput{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   30100,
+			},
+		},
+		{
+			name: "client ip affinity",
+			gw: map[string]string{
+				sessionAffinity:        "ClientIP",
+				sessionAffinityTimeout: "600",
+			},
+			serviceType: corev1.ServiceTypeClusterIP,
+			want: ServiceTrafficInput{
+				SessionAffinity:               corev1.ServiceAffinityClientIP,
+				SessionAffinityTimeoutSeconds: 600,
+			},
+		},
+		{
+			name:        "invalid policy",
+			gw:          map[string]string{externalTrafficPolicy: "local"},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+		{
+			name:        "policy of a cluster ip service",
+			gw:          map[string]string{externalTrafficPolicy: "Local"},
+			serviceType: corev1.ServiceTypeClusterIP,
+			wantError:   true,
+		},
+		{
+			name:        "health check node port without local policy",
+			gw:          map[string]string{healthCheckNodePort: "30100"},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+		{
+			name: "health check node port of a node port service",
+			gw: map[string]string{
+				externalTrafficPolicy: "Local",
+				healthCheckNodePort:   "30100",
+			},
+			serviceType: corev1.ServiceTypeNodePort,
+			wantError:   true,
+		},
+		{
+			name: "invalid health check node port",
+			gw: map[string]string{
+				externalTrafficPolicy: "Local",
+				healthCheckNodePort:   "70000",
+			},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+		{
+			name:        "invalid affinity",
+			gw:          map[string]string{sessionAffinity: "Cookie"},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+		{
+			name:        "timeout without client ip affinity",
+			gw:          map[string]string{sessionAffinityTimeout: "600"},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+		{
+			name: "timeout over a day",
+			gw: map[string]string{
+				sessionAffinity:        "ClientIP",
+				sessionAffinityTimeout: "86401",
+			},
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			wantError:   true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractServiceTraffic(tt.gw, tt.serviceType)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
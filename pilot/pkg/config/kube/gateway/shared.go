@@ -0,0 +1,144 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8s "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config"
+)
+
+// gatewaySharedDeployment collapses the managed gateways of a namespace and class with the same value onto a single
+// Deployment and Service, named after the value and listening on the ports of the listeners of all the gateways, so
+// that they share a load balancer. The resources are configured by the annotations of the oldest gateway of the group,
+// and owned by all the gateways of the group, so they are only garbage collected with the last of them.
+const gatewaySharedDeployment = "gateway.istio.io/shared-deployment"
+
+// sharedDeploymentName returns the name of the shared deployment of a gateway, if it has the gatewaySharedDeployment
+// annotation.
+func sharedDeploymentName(annotations map[string]string, kgw *k8s.GatewaySpec) (string, bool) {
+	shared, f := annotations[gatewaySharedDeployment]
+	if !f {
+		return "", false
+	}
+	return getDefaultName(shared, kgw), true
+}
+
+// managedResourceName returns the name of the Service of a managed gateway: the name of its shared deployment, if
+// any, else its default name.
+func managedResourceName(name string, annotations map[string]string, kgw *k8s.GatewaySpec) string {
+	if shared, f := sharedDeploymentName(annotations, kgw); f {
+		return shared
+	}
+	return getDefaultName(name, kgw)
+}
+
+// validateSharedDeployment returns an error if the shared deployment of a gateway cannot be generated.
+func validateSharedDeployment(gw gateway.Gateway) error {
+	name, f := sharedDeploymentName(gw.Annotations, &gw.Spec)
+	if !f {
+		return nil
+	}
+	if _, f := gw.Annotations[gatewayNameOverride]; f {
+		return fmt.Errorf("%v cannot be set with %v", gatewayNameOverride, gatewaySharedDeployment)
+	}
+	if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+		return fmt.Errorf("invalid %v annotation: the name of the shared deployment %q is invalid: %v",
+			gatewaySharedDeployment, name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sharingGateways returns the managed gateways of the namespace and class sharing the deployment of the gateway,
+// including the gateway itself if it is not deleted, the oldest first. The gateways are grouped from the informer cache rather than an index updated
+// by an event handler, which could lag behind the reconciliation of the gateways.
+func (d *DeploymentController) sharingGateways(gw gateway.Gateway) []*gateway.Gateway {
+	name, f := sharedDeploymentName(gw.Annotations, &gw.Spec)
+	if !f {
+		return nil
+	}
+	var res []*gateway.Gateway
+	for _, member := range d.gateways.List(gw.Namespace, klabels.Everything()) {
+		if memberName, f := sharedDeploymentName(member.Annotations, &member.Spec); !f || memberName != name ||
+			member.Spec.GatewayClassName != gw.Spec.GatewayClassName {
+			continue
+		}
+		if member.DeletionTimestamp == nil && IsManaged(&member.Spec) {
+			res = append(res, member)
+		}
+	}
+	slices.SortFunc(res, func(a, b *gateway.Gateway) bool {
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+	return res
+}
+
+// sharedGateway returns the gateway generating the resources of the shared deployment of the gateway: a copy of the
+// oldest gateway of the group, with the listeners of all the gateways. The gateway is returned as is if it has no
+// shared deployment.
+func (d *DeploymentController) sharedGateway(gw gateway.Gateway) gateway.Gateway {
+	members := d.sharingGateways(gw)
+	if len(members) == 0 {
+		return gw
+	}
+	res := *members[0].DeepCopy()
+	for _, member := range members[1:] {
+		res.Spec.Listeners = append(res.Spec.Listeners, member.Spec.Listeners...)
+	}
+	return res
+}
+
+// OwnerInput is a Gateway owning the generated resources.
+type OwnerInput struct {
+	Name string
+	UID  types.UID
+}
+
+// gatewayOwners returns the owners of the resources generated for the gateway: the gateways sharing its deployment, or
+// the gateway itself.
+func (d *DeploymentController) gatewayOwners(gw gateway.Gateway) []OwnerInput {
+	members := d.sharingGateways(gw)
+	if len(members) == 0 {
+		return []OwnerInput{{Name: gw.Name, UID: gw.UID}}
+	}
+	res := make([]OwnerInput, 0, len(members))
+	for _, member := range members {
+		res = append(res, OwnerInput{Name: member.Name, UID: member.UID})
+	}
+	return res
+}
+
+// sharingGatewayKeys returns the other gateways sharing the deployment of the gateway, recomputed on its changes as
+// their ports are merged.
+func (d *DeploymentController) sharingGatewayKeys(gw *gateway.Gateway) []types.NamespacedName {
+	var res []types.NamespacedName
+	for _, member := range d.sharingGateways(*gw) {
+		if member.Name != gw.Name {
+			res = append(res, config.NamespacedName(member))
+		}
+	}
+	return res
+}
@@ -0,0 +1,202 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestValidateSharedDeployment(t *testing.T) {
+	cases := []struct {
+		name      string
+		gw        map[string]string
+		wantError bool
+	}{
+		{
+			name: "not shared",
+		},
+		{
+			name: "shared",
+			gw:   map[string]string{gatewaySharedDeployment: "public"},
+		},
+		{
+			name:      "invalid name",
+			gw:        map[string]string{gatewaySharedDeployment: "Public"},
+			wantError: true,
+		},
+		{
+			name:      "name override",
+			gw:        map[string]string{gatewaySharedDeployment: "public", gatewayNameOverride: "gw"},
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw", Annotations: tt.gw},
+				Spec:       v1beta1.GatewaySpec{GatewayClassName: DefaultClassName},
+			}
+			err := validateSharedDeployment(gw)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("wantError %v, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestManagedResourceName(t *testing.T) {
+	spec := &v1beta1.GatewaySpec{GatewayClassName: DefaultClassName}
+	assert.Equal(t, managedResourceName("gw", nil, spec), "gw-istio")
+	assert.Equal(t, managedResourceName("gw", map[string]string{gatewaySharedDeployment: "public"}, spec), "public-istio")
+}
+
+func TestSharedDeployment(t *testing.T) {
+	c := kube.NewFakeClient()
+	d := NewDeploymentController(c, "", "istio-system", testInjectionConfig(t), func(fn func()) {})
+	services := make(chan corev1.Service, 10)
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		if g == gvr.Service {
+			svc := corev1.Service{}
+			if err := json.Unmarshal(data, &svc); err != nil {
+				return err
+			}
+			services <- svc
+		}
+		return nil
+	}
+	stop := test.NewStop(t)
+	gws := clienttest.Wrap(t, d.gateways)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+
+	shared := func(name string, port v1beta1.PortNumber, created time.Time) *v1beta1.Gateway {
+		return &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID("uid-" + name),
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{gatewaySharedDeployment: "public"},
+			},
+			Spec: v1beta1.GatewaySpec{
+				GatewayClassName: DefaultClassName,
+				Listeners: []v1beta1.Listener{{
+					Name:     "http",
+					Port:     port,
+					Protocol: v1beta1.HTTPProtocolType,
+				}},
+			},
+		}
+	}
+	ports := func(svc corev1.Service) []int32 {
+		var res []int32
+		for _, p := range svc.Spec.Ports {
+			res = append(res, p.Port)
+		}
+		return res
+	}
+	portNames := func(svc corev1.Service) []string {
+		var res []string
+		for _, p := range svc.Spec.Ports {
+			res = append(res, p.Name)
+		}
+		return res
+	}
+	owners := func(svc corev1.Service) []string {
+		var res []string
+		for _, o := range svc.OwnerReferences {
+			res = append(res, o.Name)
+		}
+		return res
+	}
+	now := time.Now()
+	gws.Create(shared("b", 8080, now))
+	svc := assert.ChannelHasItem(t, services)
+	assert.Equal(t, svc.Name, "public-istio")
+	assert.Equal(t, ports(svc), []int32{15021, 8080})
+
+	// An older gateway configures the shared resources, which all the gateways own, and its listeners are merged first
+	gws.Create(shared("a", 80, now.Add(-time.Hour)))
+	for i := 0; i < 2; i++ {
+		svc = assert.ChannelHasItem(t, services)
+		assert.Equal(t, svc.Name, "public-istio")
+		assert.Equal(t, owners(svc), []string{"a", "b"})
+		assert.Equal(t, ports(svc), []int32{15021, 80, 8080})
+		// The ports of the listeners with the same name are told apart
+		assert.Equal(t, portNames(svc), []string{"status-port", "http", "http-8080"})
+	}
+
+	// Deleting a gateway removes its listeners
+	gws.Delete("b", "default")
+	svc = assert.ChannelHasItem(t, services)
+	assert.Equal(t, ports(svc), []int32{15021, 80})
+	assert.Equal(t, owners(svc), []string{"a"})
+}
+
+func TestSharingGateways(t *testing.T) {
+	c := kube.NewFakeClient()
+	d := &DeploymentController{gateways: kclient.New[*v1beta1.Gateway](c)}
+	c.RunAndWait(test.NewStop(t))
+	gws := clienttest.Wrap(t, d.gateways)
+	for _, gw := range []struct {
+		name, namespace, class string
+	}{
+		{"a", "default", DefaultClassName},
+		{"b", "default", DefaultClassName},
+		// The gateways of other namespaces and classes have their own deployment
+		{"c", "other", DefaultClassName},
+		{"d", "default", "custom"},
+	} {
+		gws.Create(&v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        gw.name,
+				Namespace:   gw.namespace,
+				Annotations: map[string]string{gatewaySharedDeployment: "public"},
+			},
+			Spec: v1beta1.GatewaySpec{GatewayClassName: v1beta1.ObjectName(gw.class)},
+		})
+	}
+	names := func(name, namespace, class string) func() []string {
+		return func() []string {
+			gw := v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: map[string]string{gatewaySharedDeployment: "public"}},
+				Spec:       v1beta1.GatewaySpec{GatewayClassName: v1beta1.ObjectName(class)},
+			}
+			var res []string
+			for _, member := range d.sharingGateways(gw) {
+				res = append(res, member.Name)
+			}
+			return res
+		}
+	}
+	assert.EventuallyEqual(t, names("b", "default", DefaultClassName), []string{"a", "b"})
+	assert.EventuallyEqual(t, names("c", "other", DefaultClassName), []string{"c"})
+	assert.EventuallyEqual(t, names("d", "default", "custom"), []string{"d"})
+}
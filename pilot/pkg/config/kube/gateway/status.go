@@ -0,0 +1,223 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/config/schema/gvr"
+	istiolog "istio.io/pkg/log"
+)
+
+// The conditions of the Gateway status reporting the state of the infrastructure deployed for the managed gateways.
+const (
+	// gatewayConditionResourcesProvisioned reports whether the resources generated for the gateway were applied.
+	gatewayConditionResourcesProvisioned = "gateway.istio.io/ResourcesProvisioned"
+	// gatewayConditionDeploymentReady reports whether the replicas of the gateway Deployment, DaemonSet or
+	// StatefulSet, are rolled out and available.
+	gatewayConditionDeploymentReady = "gateway.istio.io/DeploymentReady"
+	// gatewayConditionServiceAddressAssigned reports whether the gateway Service was assigned an address, the
+	// external address of the load balancer for a LoadBalancer Service.
+	gatewayConditionServiceAddressAssigned = "gateway.istio.io/ServiceAddressAssigned"
+)
+
+// infrastructureConditions are the conditions of the status of the managed gateways owned by the deployment
+// controller.
+var infrastructureConditions = []string{
+	gatewayConditionResourcesProvisioned,
+	gatewayConditionDeploymentReady,
+	gatewayConditionServiceAddressAssigned,
+	gatewayConditionProxyImageResolved,
+	gatewayConditionResolvedRefs,
+	gatewayConditionHostPortsAvailable,
+}
+
+// provisioningFailed reports a failure to provision the gateway, with an event and its ResourcesProvisioned
+// condition, along with the conditions of the cause of the failure.
+func (d *DeploymentController) provisioningFailed(log *istiolog.Scope, gw gateway.Gateway, reason, message string,
+	conditions ...metav1.Condition,
+) {
+	d.event(gw, corev1.EventTypeWarning, reason, "%s", message)
+	err := d.reportStatus(gw, append([]metav1.Condition{{
+		Type:    gatewayConditionResourcesProvisioned,
+		Status:  kstatus.StatusFalse,
+		Reason:  reason,
+		Message: message,
+	}}, conditions...)...)
+	if err != nil {
+		log.Warnf("failed to report the provisioning failure: %v", err)
+	}
+}
+
+// provisioned reports the gateway resources as applied, along with the readiness of its workload and Service, the
+// resolution of its proxy image, and the other conditions checked before applying them.
+func (d *DeploymentController) provisioned(gw gateway.Gateway, input TemplateInput, resources int, conditions ...metav1.Condition) error {
+	return d.reportStatus(gw, append([]metav1.Condition{
+		{
+			Type:    gatewayConditionResourcesProvisioned,
+			Status:  kstatus.StatusTrue,
+			Reason:  "Provisioned",
+			Message: fmt.Sprintf("Applied %d resources", resources),
+		},
+		d.workloadReadyCondition(gw.Namespace, input),
+		d.serviceAddressCondition(gw.Namespace, input),
+		d.proxyImageCondition(gw.Namespace, input),
+	}, conditions...)...)
+}
+
+// workloadReadyCondition returns the DeploymentReady condition of the gateway, true once the latest generation of
+// its Deployment, DaemonSet or StatefulSet, is rolled out and all the replicas are available.
+func (d *DeploymentController) workloadReadyCondition(namespace string, input TemplateInput) metav1.Condition {
+	var generation, observedGeneration int64
+	var desired, updated, available int32
+	workloadKind := input.workloadKind()
+	switch {
+	case input.DaemonSet:
+		ds := d.daemonSets.Get(input.DeploymentName, namespace)
+		if ds == nil {
+			return workloadNotFoundCondition(workloadKind, namespace, input.DeploymentName)
+		}
+		generation, observedGeneration = ds.Generation, ds.Status.ObservedGeneration
+		desired, updated, available = ds.Status.DesiredNumberScheduled, ds.Status.UpdatedNumberScheduled, ds.Status.NumberAvailable
+	case input.StatefulSet:
+		sts := d.statefulSets.Get(input.DeploymentName, namespace)
+		if sts == nil {
+			return workloadNotFoundCondition(workloadKind, namespace, input.DeploymentName)
+		}
+		generation, observedGeneration = sts.Generation, sts.Status.ObservedGeneration
+		desired = 1
+		if sts.Spec.Replicas != nil {
+			desired = *sts.Spec.Replicas
+		}
+		updated, available = sts.Status.UpdatedReplicas, sts.Status.AvailableReplicas
+	default:
+		dp := d.deployments.Get(input.DeploymentName, namespace)
+		if dp == nil {
+			return workloadNotFoundCondition(workloadKind, namespace, input.DeploymentName)
+		}
+		generation, observedGeneration = dp.Generation, dp.Status.ObservedGeneration
+		desired = 1
+		if dp.Spec.Replicas != nil {
+			desired = *dp.Spec.Replicas
+		}
+		updated, available = dp.Status.UpdatedReplicas, dp.Status.AvailableReplicas
+	}
+
+	cond := metav1.Condition{Type: gatewayConditionDeploymentReady, Status: kstatus.StatusFalse}
+	switch {
+	case observedGeneration < generation:
+		cond.Reason = "RolloutInProgress"
+		cond.Message = fmt.Sprintf("%s %s/%s is rolling out generation %d", workloadKind, namespace, input.DeploymentName, generation)
+	case updated < desired || available < desired:
+		cond.Reason = "ReplicasUnavailable"
+		cond.Message = fmt.Sprintf("%d/%d replicas of %s %s/%s are available", available, desired, workloadKind, namespace, input.DeploymentName)
+	default:
+		cond.Status = kstatus.StatusTrue
+		cond.Reason = "Ready"
+		cond.Message = fmt.Sprintf("%d/%d replicas of %s %s/%s are available", available, desired, workloadKind, namespace, input.DeploymentName)
+	}
+	return cond
+}
+
+func workloadNotFoundCondition(workloadKind, namespace, name string) metav1.Condition {
+	return metav1.Condition{
+		Type:    gatewayConditionDeploymentReady,
+		Status:  kstatus.StatusFalse,
+		Reason:  "NotFound",
+		Message: fmt.Sprintf("%s %s/%s not found", workloadKind, namespace, name),
+	}
+}
+
+// serviceAddressCondition returns the ServiceAddressAssigned condition of the gateway, true once its Service has an
+// address: the ingress addresses of the load balancer for a LoadBalancer Service, the cluster IP otherwise.
+func (d *DeploymentController) serviceAddressCondition(namespace string, input TemplateInput) metav1.Condition {
+	cond := metav1.Condition{Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusFalse}
+	svc := d.services.Get(input.DeploymentName, namespace)
+	if svc == nil {
+		cond.Reason = "NotFound"
+		cond.Message = fmt.Sprintf("Service %s/%s not found", namespace, input.DeploymentName)
+		return cond
+	}
+	var addresses []string
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if ing.IP != "" {
+				addresses = append(addresses, ing.IP)
+			} else if ing.Hostname != "" {
+				addresses = append(addresses, ing.Hostname)
+			}
+		}
+	} else if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		addresses = append(addresses, svc.Spec.ClusterIP)
+	}
+	if len(addresses) == 0 {
+		cond.Reason = "AddressPending"
+		cond.Message = fmt.Sprintf("Waiting for an address of %s Service %s/%s", svc.Spec.Type, namespace, svc.Name)
+		return cond
+	}
+	cond.Status = kstatus.StatusTrue
+	cond.Reason = "AddressAssigned"
+	cond.Message = fmt.Sprintf("%s Service %s/%s has address %s", svc.Spec.Type, namespace, svc.Name, strings.Join(addresses, ", "))
+	return cond
+}
+
+// reportStatus sets the conditions of the gateway status, if they changed. The conditions of the other controllers
+// are left untouched, as the status is patched with the infrastructure conditions only.
+func (d *DeploymentController) reportStatus(gw gateway.Gateway, conditions ...metav1.Condition) error {
+	if !features.EnableGatewayAPIStatus {
+		return nil
+	}
+	updated := gw.Status.Conditions
+	for _, c := range conditions {
+		c.ObservedGeneration = gw.Generation
+		c.LastTransitionTime = metav1.Now()
+		updated = kstatus.UpdateConditionIfChanged(updated, c)
+	}
+	if reflect.DeepEqual(updated, gw.Status.Conditions) {
+		return nil
+	}
+	var owned []metav1.Condition
+	for _, t := range infrastructureConditions {
+		if c := kstatus.GetCondition(updated, t); c != kstatus.EmptyCondition {
+			owned = append(owned, c)
+		}
+	}
+	return d.patchConditions(gw, owned)
+}
+
+// patchConditions server-side applies the conditions to the gateway status. Like the generated resources, the
+// conditions are owned by the controller field manager: those it no longer applies are removed.
+func (d *DeploymentController) patchConditions(gw gateway.Gateway, conditions []metav1.Condition) error {
+	patch, err := json.Marshal(map[string]any{
+		"apiVersion": gateway.GroupVersion.String(),
+		"kind":       "Gateway",
+		"status": map[string]any{
+			"conditions": conditions,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return d.patcher(gvr.KubernetesGateway, gw.Name, gw.Namespace, patch, "status")
+}
@@ -0,0 +1,211 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestInfrastructureConditions(t *testing.T) {
+	replicas := int32(2)
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		daemonSet   bool
+		statefulSet bool
+		wantReady   metav1.Condition
+		wantAddress metav1.Condition
+	}{
+		{
+			name: "not found",
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusFalse,
+				Reason: "NotFound", Message: "Deployment default/gw not found",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusFalse,
+				Reason: "NotFound", Message: "Service default/gw not found",
+			},
+		},
+		{
+			name: "pending",
+			objects: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 2},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 2},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+				},
+			},
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusFalse,
+				Reason: "RolloutInProgress", Message: "Deployment default/gw is rolling out generation 2",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusFalse,
+				Reason: "AddressPending", Message: "Waiting for an address of LoadBalancer Service default/gw",
+			},
+		},
+		{
+			name: "unavailable",
+			objects: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 2},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+				},
+			},
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusFalse,
+				Reason: "ReplicasUnavailable", Message: "1/2 replicas of Deployment default/gw are available",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusFalse,
+				Reason: "NotFound", Message: "Service default/gw not found",
+			},
+		},
+		{
+			name: "ready",
+			objects: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 2},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+					Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+						{IP: "1.2.3.4"}, {Hostname: "gw.example.com"},
+					}}},
+				},
+			},
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusTrue,
+				Reason: "Ready", Message: "2/2 replicas of Deployment default/gw are available",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusTrue,
+				Reason: "AddressAssigned", Message: "LoadBalancer Service default/gw has address 1.2.3.4, gw.example.com",
+			},
+		},
+		{
+			name: "daemonset",
+			objects: []runtime.Object{
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 1},
+					Status: appsv1.DaemonSetStatus{
+						ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3,
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1"},
+				},
+			},
+			daemonSet: true,
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusTrue,
+				Reason: "Ready", Message: "3/3 replicas of DaemonSet default/gw are available",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusTrue,
+				Reason: "AddressAssigned", Message: "ClusterIP Service default/gw has address 10.0.0.1",
+			},
+		},
+		{
+			name: "statefulset",
+			objects: []runtime.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 1},
+					Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+					Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 1},
+				},
+			},
+			statefulSet: true,
+			wantReady: metav1.Condition{
+				Type: gatewayConditionDeploymentReady, Status: kstatus.StatusFalse,
+				Reason: "ReplicasUnavailable", Message: "1/2 replicas of StatefulSet default/gw are available",
+			},
+			wantAddress: metav1.Condition{
+				Type: gatewayConditionServiceAddressAssigned, Status: kstatus.StatusFalse,
+				Reason: "NotFound", Message: "Service default/gw not found",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := kube.NewFakeClient(tt.objects...)
+			d := &DeploymentController{
+				deployments:  kclient.New[*appsv1.Deployment](client),
+				daemonSets:   kclient.New[*appsv1.DaemonSet](client),
+				statefulSets: kclient.New[*appsv1.StatefulSet](client),
+				services:     kclient.New[*corev1.Service](client),
+			}
+			client.RunAndWait(test.NewStop(t))
+			input := TemplateInput{DeploymentName: "gw", DaemonSet: tt.daemonSet, StatefulSet: tt.statefulSet}
+			assert.Equal(t, d.workloadReadyCondition("default", input), tt.wantReady)
+			assert.Equal(t, d.serviceAddressCondition("default", input), tt.wantAddress)
+		})
+	}
+}
+
+func TestReportStatus(t *testing.T) {
+	var patches int
+	d := &DeploymentController{
+		patcher: func(gvr schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+			patches++
+			return nil
+		},
+	}
+	provisioned := metav1.Condition{
+		Type:    gatewayConditionResourcesProvisioned,
+		Status:  kstatus.StatusTrue,
+		Reason:  "Provisioned",
+		Message: "Applied 3 resources",
+	}
+	gw := v1beta1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default", Generation: 1}}
+	assert.NoError(t, d.reportStatus(gw, provisioned))
+	assert.Equal(t, patches, 1)
+
+	// An unchanged condition is not patched again
+	existing := provisioned
+	existing.ObservedGeneration = 1
+	existing.LastTransitionTime = metav1.Now()
+	gw.Status.Conditions = []metav1.Condition{existing}
+	assert.NoError(t, d.reportStatus(gw, provisioned))
+	assert.Equal(t, patches, 1)
+
+	// A new generation is
+	gw.Generation = 2
+	assert.NoError(t, d.reportStatus(gw, provisioned))
+	assert.Equal(t, patches, 2)
+}
@@ -0,0 +1,79 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/pkg/config/constants"
+)
+
+// parseTopologySpreadConstraints parses the topology spread constraints of the class parameters, in JSON or YAML. An
+// empty list disables the default constraint.
+func parseTopologySpreadConstraints(raw string) ([]corev1.TopologySpreadConstraint, error) {
+	res := []corev1.TopologySpreadConstraint{}
+	if err := yaml.UnmarshalStrict([]byte(raw), &res); err != nil {
+		return nil, err
+	}
+	if err := validateTopologySpreadConstraints(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// validateTopologySpreadConstraints returns an error if a constraint misses one of its required fields.
+func validateTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) error {
+	for i, c := range constraints {
+		if c.MaxSkew < 1 {
+			return fmt.Errorf("constraint %d: maxSkew must be at least 1, got %d", i, c.MaxSkew)
+		}
+		if c.TopologyKey == "" {
+			return fmt.Errorf("constraint %d: topologyKey is required", i)
+		}
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule && c.WhenUnsatisfiable != corev1.ScheduleAnyway {
+			return fmt.Errorf("constraint %d: whenUnsatisfiable must be %s or %s, got %q",
+				i, corev1.DoNotSchedule, corev1.ScheduleAnyway, c.WhenUnsatisfiable)
+		}
+	}
+	return nil
+}
+
+// extractTopologySpreadConstraints returns the topology spread constraints of the pods of a gateway. Unless the class
+// parameters configure them, the replicas are spread across zones on a best effort basis. The constraints without a
+// label selector select the pods of the gateway. The pods of a DaemonSet are not spread, as they run on every node.
+func extractTopologySpreadConstraints(gw string, mode string, class []corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	if mode == DeploymentModeDaemonSet {
+		return nil
+	}
+	if class == nil {
+		class = []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+		}}
+	}
+	res := make([]corev1.TopologySpreadConstraint, 0, len(class))
+	for _, c := range class {
+		if c.LabelSelector == nil {
+			c.LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{constants.GatewayNameLabel: gw}}
+		}
+		res = append(res, c)
+	}
+	return res
+}
@@ -0,0 +1,122 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestTopologySpreadConstraints(t *testing.T) {
+	gatewayPods := &metav1.LabelSelector{MatchLabels: map[string]string{"istio.io/gateway-name": "gw"}}
+	cases := []struct {
+		name      string
+		raw       *string
+		mode      string
+		want      []corev1.TopologySpreadConstraint
+		wantError bool
+	}{
+		{
+			name: "default",
+			mode: DeploymentModeDeployment,
+			want: []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     gatewayPods,
+			}},
+		},
+		{
+			name: "daemonset",
+			mode: DeploymentModeDaemonSet,
+		},
+		{
+			name: "disabled",
+			raw:  ptr.Of("[]"),
+			mode: DeploymentModeDeployment,
+			want: []corev1.TopologySpreadConstraint{},
+		},
+		{
+			name: "class",
+			raw: ptr.Of(`
+- maxSkew: 2
+  topologyKey: kubernetes.io/hostname
+  whenUnsatisfiable: DoNotSchedule
+- maxSkew: 1
+  topologyKey: topology.kubernetes.io/zone
+  whenUnsatisfiable: ScheduleAnyway
+  labelSelector:
+    matchLabels:
+      app: ingress
+`),
+			mode: DeploymentModeStatefulSet,
+			want: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           2,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     gatewayPods,
+				},
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ingress"}},
+				},
+			},
+		},
+		{
+			name:      "missing max skew",
+			raw:       ptr.Of(`[{"topologyKey":"kubernetes.io/hostname","whenUnsatisfiable":"DoNotSchedule"}]`),
+			wantError: true,
+		},
+		{
+			name:      "missing topology key",
+			raw:       ptr.Of(`[{"maxSkew":1,"whenUnsatisfiable":"DoNotSchedule"}]`),
+			wantError: true,
+		},
+		{
+			name:      "invalid when unsatisfiable",
+			raw:       ptr.Of(`[{"maxSkew":1,"topologyKey":"kubernetes.io/hostname","whenUnsatisfiable":"Never"}]`),
+			wantError: true,
+		},
+		{
+			name:      "unknown field",
+			raw:       ptr.Of(`[{"maxSkews":1}]`),
+			wantError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var class []corev1.TopologySpreadConstraint
+			if tt.raw != nil {
+				var err error
+				class, err = parseTopologySpreadConstraints(*tt.raw)
+				if tt.wantError != (err != nil) {
+					t.Fatalf("wantError %v, got %v", tt.wantError, err)
+				}
+				if err != nil {
+					return
+				}
+			}
+			assert.Equal(t, extractTopologySpreadConstraints("gw", tt.mode, class), tt.want)
+		})
+	}
+}
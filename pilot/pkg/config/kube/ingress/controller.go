@@ -35,6 +35,7 @@ import (
 	"istio.io/istio/pkg/config/schema/collection"
 	"istio.io/istio/pkg/config/schema/collections"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/kube/kclient"
@@ -110,8 +111,10 @@ func NewController(client kube.Client, meshWatcher mesh.Holder,
 	}
 	c.queue = controllers.NewQueue("ingress",
 		controllers.WithReconciler(c.onEvent),
-		controllers.WithMaxAttempts(5))
-	c.ingress.AddEventHandler(controllers.ObjectHandler(c.queue.AddObject))
+		controllers.WithMaxAttempts(5),
+		controllers.WithKind(kind.Ingress),
+		controllers.WithCluster(options.ClusterID))
+	c.ingress.AddEventHandler(c.queue.Metrics().Handler(kind.Ingress, controllers.ObjectHandler(c.queue.AddObject)))
 	return c
 }
 
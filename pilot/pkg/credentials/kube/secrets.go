@@ -80,11 +80,8 @@ func NewCredentialsController(kc kube.Client) *CredentialsController {
 	// This makes the assumption we will never care about Helm secrets or SA token secrets - two common
 	// large secrets in clusters.
 	// This is a best effort optimization only; the code would behave correctly if we watched all secrets.
-	fieldSelector := fields.AndSelectors(
-		fields.OneTermNotEqualSelector("type", "helm.sh/release.v1"),
-		fields.OneTermNotEqualSelector("type", string(v1.SecretTypeServiceAccountToken))).String()
 	secrets := kclient.NewFiltered[*v1.Secret](kc, kclient.Filter{
-		FieldSelector: fieldSelector,
+		FieldSelector: SecretsFieldSelector(),
 	})
 
 	return &CredentialsController{
@@ -94,6 +91,14 @@ func NewCredentialsController(kc kube.Client) *CredentialsController {
 	}
 }
 
+// SecretsFieldSelector returns the field selector of the Secrets which may hold credentials, excluding the Helm
+// release and ServiceAccount token Secrets. The other controllers reading credentials use it to share the informer.
+func SecretsFieldSelector() string {
+	return fields.AndSelectors(
+		fields.OneTermNotEqualSelector("type", "helm.sh/release.v1"),
+		fields.OneTermNotEqualSelector("type", string(v1.SecretTypeServiceAccountToken))).String()
+}
+
 const cacheTTL = time.Minute
 
 // clearExpiredCache iterates through the cache and removes all expired entries. Should be called with mutex held.
@@ -223,6 +228,12 @@ func hasValue(d map[string][]byte, keys ...string) bool {
 	return true
 }
 
+// ExtractKeyAndCert extracts the server key and certificate of a Secret, like GetKeyCertAndStaple.
+func ExtractKeyAndCert(scrt *v1.Secret) (key, cert []byte, err error) {
+	key, cert, _, err = extractKeyCertAndStaple(scrt)
+	return key, cert, err
+}
+
 // extractKeyCertAndStaple extracts server key, certificate and OCSP staple
 func extractKeyCertAndStaple(scrt *v1.Secret) (key, cert, staple []byte, err error) {
 	if hasValue(scrt.Data, GenericScrtCert, GenericScrtKey) {
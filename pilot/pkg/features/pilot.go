@@ -412,12 +412,97 @@ var (
 	EnableGatewayAPIDeploymentController = env.Register("PILOT_ENABLE_GATEWAY_API_DEPLOYMENT_CONTROLLER", true,
 		"If this is set to true, gateway-api resources will automatically provision in cluster deployment, services, etc").Get()
 
+	KubeClientQPS = env.Register("PILOT_KUBE_CLIENT_QPS", 80.0,
+		"The default of the --kubernetesApiQPS flag, the maximum QPS of the requests of istiod to the Kubernetes API server").Get()
+
+	KubeClientBurst = env.Register("PILOT_KUBE_CLIENT_BURST", 160,
+		"The default of the --kubernetesApiBurst flag, the maximum burst of the requests of istiod to the Kubernetes API server").Get()
+
+	KubeClientMaxThrottledRetries = env.Register("PILOT_KUBE_CLIENT_MAX_THROTTLED_RETRIES", 10,
+		"The number of times the requests of istiod throttled by the API Priority and Fairness of the Kubernetes API server "+
+			"are retried, after the delay requested by the API server. 0 disables the retries").Get()
+
+	GatewayDeploymentControllerQPS = env.Register("PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_QPS", 10.0,
+		"The rate of the retries of the reconciles of all the gateways by the gateway deployment controller, "+
+			"to lower when the API server throttles istiod").Get()
+
+	GatewayDeploymentControllerBurst = env.Register("PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_BURST", 100,
+		"The burst of the retries of the reconciles of all the gateways by the gateway deployment controller").Get()
+
+	GatewayDeploymentControllerMaxRetryDelay = env.Register("PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_MAX_RETRY_DELAY", 5*time.Minute,
+		"The maximal delay of the retries of the reconcile of a gateway by the gateway deployment controller, "+
+			"bounding the time a gateway stays out of sync after failures").Get()
+
+	GatewayDeploymentControllerRetryBaseDelay = env.Register("PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_RETRY_BASE_DELAY",
+		5*time.Millisecond,
+		"The delay of the first retry of the reconcile of a gateway by the gateway deployment controller, doubled on each "+
+			"retry up to PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_MAX_RETRY_DELAY").Get()
+
+	GatewayDeploymentControllerMaxAttempts = env.Register("PILOT_GATEWAY_DEPLOYMENT_CONTROLLER_MAX_ATTEMPTS", 5,
+		"The number of attempts of the reconcile of a gateway by the gateway deployment controller before it is no longer "+
+			"retried until the gateway or its inputs change. The gateways no longer retried are listed on /debug/controllerz").Get()
+
+	EnableGatewayCleanupFinalizer = env.Register("PILOT_ENABLE_GATEWAY_CLEANUP_FINALIZER", false,
+		"If this is set to true, the gateway deployment controller sets a finalizer on the Gateways it manages, to delete "+
+			"the resources generated for a Gateway with it, including those not covered by owner references. The Gateways "+
+			"cannot be deleted while istiod is not running").Get()
+
+	EnableGatewayExternalDNS = env.Register("PILOT_ENABLE_GATEWAY_EXTERNAL_DNS", false,
+		"If this is set to true, the gateway deployment controller annotates the Service of the Gateways it manages with "+
+			"the hostnames of their listeners, for external-dns to provision their DNS records").Get()
+
+	EnableGatewayPodMonitor = env.Register("PILOT_ENABLE_GATEWAY_POD_MONITOR", false,
+		"If this is set to true, the gateway deployment controller applies a Prometheus Operator PodMonitor scraping the "+
+			"metrics of the pods of the Gateways it manages. The PodMonitor CRD must be installed").Get()
+
+	ValidateGatewayCertificateRefs = env.Register("PILOT_GATEWAY_VALIDATE_CERTIFICATE_REFS", false,
+		"If this is set to true, the gateway deployment controller validates the TLS Secrets referenced by the listeners of "+
+			"the Gateways it manages before applying their resources, and reports the references not permitted by a "+
+			"ReferenceGrant, missing or malformed in the gateway.istio.io/ResolvedRefs condition").Get()
+
+	GatewayAutoscalingConnectionsMetric = env.Register("PILOT_GATEWAY_AUTOSCALING_CONNECTIONS_METRIC",
+		"envoy_server_total_connections",
+		"The name of the metric of the active downstream connections of the gateway pods in the custom metrics API, used by "+
+			"the HorizontalPodAutoscalers of the Gateways setting the gateway.istio.io/autoscaling-target-active-connections "+
+			"annotation. The metrics adapter, like prometheus-adapter, must expose it. The default Envoy server stat is "+
+			"always exported by the proxies, unlike the listener and HTTP connection manager stats, which need an "+
+			"inclusion in the proxyStatsMatcher").Get()
+
+	GatewayAutoscalingRequestsMetric = env.Register("PILOT_GATEWAY_AUTOSCALING_REQUESTS_METRIC",
+		"istio_requests_per_second",
+		"The name of the metric of the requests per second of the gateway pods in the custom metrics API, used by the "+
+			"HorizontalPodAutoscalers of the Gateways setting the gateway.istio.io/autoscaling-target-requests-per-second "+
+			"annotation. The metrics adapter, like prometheus-adapter, must expose it").Get()
+
+	EnableEastWestGatewayProvisioning = env.Register("PILOT_ENABLE_EAST_WEST_GATEWAY_PROVISIONING", false,
+		"If this is set to true, istiod provisions the east-west gateway of the network of the cluster, set by the "+
+			"topology.istio.io/network label of the system namespace, as a Gateway deployed by the gateway deployment controller").Get()
+
+	EnableConfigDriftDetection = env.Register("PILOT_ENABLE_CONFIG_DRIFT_DETECTION", false,
+		"If this is set to true, istiod watches the VirtualServices, DestinationRules and Gateways of the remote primary "+
+			"clusters of a multi-primary mesh, and reports the copies diverging from those of its config cluster").Get()
+
 	ClusterName = env.Register("CLUSTER_ID", "Kubernetes",
 		"Defines the cluster and service registry that this Istiod instance is belongs to").Get()
 
 	ExternalIstiod = env.Register("EXTERNAL_ISTIOD", false,
 		"If this is set to true, one Istiod will control remote clusters including CA.").Get()
 
+	PersistAutoAllocatedIPs = env.Register("PILOT_PERSIST_AUTO_ALLOCATED_IPS", false,
+		"If this is set to true, the addresses auto-allocated to the ServiceEntries are persisted in the "+
+			"istio-ip-allocations ConfigMap of the istiod namespace, so a ServiceEntry keeps its address across the "+
+			"restarts and the upgrades of istiod, and when other ServiceEntries are added or removed").Get()
+
+	EnableRemoteServiceAutoAllocation = env.Register("PILOT_ENABLE_REMOTE_SERVICE_AUTO_ALLOCATION", false,
+		"If this is set to true, the Kubernetes services missing from some clusters of the mesh are allocated an address "+
+			"of the 240.241.0.0/16 range, resolved by the DNS proxy of the proxies of these clusters requesting "+
+			"auto allocation, so they can call the services living in other clusters without local stub services").Get()
+
+	RemoteClusterBootstrapAddress = env.Register("PILOT_REMOTE_CLUSTER_BOOTSTRAP_ADDRESS", "",
+		"If this is set along with EXTERNAL_ISTIOD, istiod provisions and maintains the sidecar injection webhook "+
+			"configuration and the mesh config of the remote clusters it controls, pointing at this host of the external "+
+			"istiod reachable from the remote clusters").Get()
+
 	EnableCAServer = env.Register("ENABLE_CA_SERVER", true,
 		"If this is set to false, will not create CA server in istiod.").Get()
 
@@ -506,6 +591,12 @@ var (
 			"Use || between <trustdomain, endpoint> tuples. Use | as delimiter between trust domain and endpoint in "+
 			"each tuple. For example: foo|https://url/for/foo||bar|https://url/for/bar").Get()
 
+	SpireTrustBundleConfigMap = env.Register("SPIRE_TRUST_BUNDLE_CONFIGMAP", "",
+		"The <namespace>/<name> of the ConfigMap where the SPIRE server publishes its trust bundle, under the "+
+			"bundle.crt key, as written by the SPIRE k8sbundle notifier. If set, Istiod adds the SPIRE roots to "+
+			"the workload trust bundle and uses them to verify client certificates. Requires ISTIO_MULTIROOT_MESH "+
+			"to distribute the roots to proxies.").Get()
+
 	EnableXDSCaching = env.Register("PILOT_ENABLE_XDS_CACHE", true,
 		"If true, Pilot will cache XDS responses.").Get()
 
@@ -546,6 +637,27 @@ var (
 			"Setting the timeout to 0 disables this behavior.",
 	).Get()
 
+	RemoteClusterHealthCheckInterval = env.Register(
+		"PILOT_REMOTE_CLUSTER_HEALTH_CHECK_INTERVAL",
+		30*time.Second,
+		"The interval at which pilot checks the health of the clusters added via remote-secrets. "+
+			"Setting the interval to 0 disables the health checks.",
+	).Get()
+
+	RemoteClusterStaleThreshold = env.Register(
+		"PILOT_REMOTE_CLUSTER_STALE_THRESHOLD",
+		2*time.Minute,
+		"If the watches of a remote cluster keep failing for longer than this threshold, the data watched from the cluster "+
+			"is reported as stale.",
+	).Get()
+
+	RemoteClusterCredentialExpiryWarning = env.Register(
+		"PILOT_REMOTE_CLUSTER_CREDENTIAL_EXPIRY_WARNING",
+		7*24*time.Hour,
+		"If the credentials of a remote cluster expire within this threshold, the cluster is reported as having "+
+			"expiring credentials.",
+	).Get()
+
 	EnableTelemetryLabel = env.Register("PILOT_ENABLE_TELEMETRY_LABEL", true,
 		"If true, pilot will add telemetry related metadata to cluster and endpoint resources, which will be consumed by telemetry filter.",
 	).Get()
@@ -591,6 +703,67 @@ var (
 		"The interval for istiod to fetch the jwks_uri for the jwks public key.",
 	).Get()
 
+	JwksIssuers = env.Register(
+		"PILOT_JWKS_ISSUERS",
+		"",
+		"A JSON list of per-issuer settings for istiod to fetch the JWKS of RequestAuthentication issuers, for "+
+			"example when the issuer is only reachable through an egress proxy or uses a private CA. Each entry has "+
+			"the issuer, and optionally the proxy URL to fetch through, the path of a PEM CA bundle to validate the "+
+			"server with, and the refreshInterval and refreshIntervalOnFailure durations. For example: "+
+			`[{"issuer":"https://idp.corp","proxy":"http://egress.istio-system:3128","caBundle":"/etc/jwks/ca.pem",`+
+			`"refreshInterval":"5m","refreshIntervalOnFailure":"10s"}]`+". Istiod does not start if an entry has no "+
+			"issuer, an unknown field, an unparsable proxy URL or duration, or a CA bundle path that does not exist.",
+	).Get()
+
+	TrustDomainMigration = env.Register(
+		"PILOT_TRUST_DOMAIN_MIGRATION",
+		"",
+		"A JSON object configuring the migration of the mesh from a previous trust domain to the trust domain of "+
+			"the mesh config. Until the end of the migration window, the proxies accept the identities of both trust "+
+			"domains, while the certificates are issued with the new trust domain. For example: "+
+			`{"from":"old.example.com","until":"2024-01-01T00:00:00Z"}`+". An invalid value fails the startup of istiod.",
+	).Get()
+
+	CertExpiryCheckInterval = env.Register(
+		"PILOT_CERT_EXPIRY_CHECK_INTERVAL",
+		time.Hour,
+		"The interval for istiod to check the expiry of the root, intermediate, cacerts and webhook certs.",
+	).Get()
+
+	CertExpiryWarningThreshold = env.Register(
+		"PILOT_CERT_EXPIRY_WARNING_THRESHOLD",
+		30*24*time.Hour,
+		"The remaining validity below which istiod reports a warning event for the root, intermediate, cacerts "+
+			"and webhook certs.",
+	).Get()
+
+	ExtAuthzProviderSettings = env.Register(
+		"PILOT_EXT_AUTHZ_PROVIDER_SETTINGS",
+		"",
+		"A JSON list of settings of the envoyExtAuthzHttp and envoyExtAuthzGrpc extension providers of the mesh "+
+			"config, in addition to the ones of the mesh config. Each entry has the provider name, and optionally "+
+			"the metadataContextNamespaces of the dynamic metadata to send to the provider, and for gRPC providers "+
+			"the initialMetadata to send to the provider. For example: "+
+			`[{"provider":"opa","metadataContextNamespaces":["envoy.filters.http.jwt_authn"],`+
+			`"initialMetadata":{"x-tenant":"shop"}}]`+". Istiod does not start if the value is not JSON or has an "+
+			"unknown field. The initialMetadata of an HTTP provider, or with an upper case key, is reported as an "+
+			"error of the provider, as an invalid mesh config provider is.",
+	).Get()
+
+	OtelAccessLogProviderSettings = env.Register(
+		"PILOT_OTEL_ACCESS_LOG_PROVIDER_SETTINGS",
+		"",
+		"A JSON list of settings of the envoyOtelAls extension providers of the mesh config, in addition to the ones "+
+			"of the mesh config. Each entry has the provider name, and optionally the resourceAttributes of the logs, "+
+			"a structured body of the logs replacing the text format of the provider, the headers sent to the "+
+			"collector, e.g. for authentication, and the attributeMapping of the logs, istio for the fields of the "+
+			"default JSON access log format or otel for the OpenTelemetry semantic conventions. For example: "+
+			`[{"provider":"otel","resourceAttributes":{"deployment.environment":"prod"},`+
+			`"body":{"method":"%REQ(:METHOD)%","code":"%RESPONSE_CODE%"},"headers":{"x-api-key":"secret"},`+
+			`"attributeMapping":"otel"}]`+". The headers are sent to every proxy in its configuration. Istiod does "+
+			"not start if the value has an unknown field or an attributeMapping other than istio or otel.",
+	).Get()
+
 	EnableInboundPassthrough = env.Register(
 		"PILOT_ENABLE_INBOUND_PASSTHROUGH",
 		true,
@@ -611,11 +784,44 @@ var (
 		"If enabled, HBONE support can be configured for proxies. "+
 			"Note: proxies must opt in on a per-proxy basis with ENABLE_HBONE to actually get HBONE config, in addition to this flag.").Get()
 
+	EnableHBONEEastWest = env.Register(
+		"PILOT_ENABLE_HBONE_EAST_WEST",
+		false,
+		"If enabled, proxies with HBONE enabled reach the workloads of the other networks supporting HBONE through an HBONE "+
+			"tunnel relayed by the east-west gateway of their network, instead of the SNI based mTLS passthrough. The tunnel "+
+			"is established with the workload, which sees the identity of the client. The east-west gateway must have HBONE "+
+			"enabled and expose port 15008. Requires PILOT_ENABLE_HBONE.").Get()
+
 	EnableAmbientControllers = env.Register(
 		"PILOT_ENABLE_AMBIENT_CONTROLLERS",
 		false,
 		"If enabled, controllers required for ambient will run. This is required to run ambient mesh.").Get()
 
+	ZtunnelAdminPort = env.Register(
+		"PILOT_ZTUNNEL_ADMIN_PORT",
+		15000,
+		"The port of the ztunnel admin interface that istiod queries to aggregate ztunnel state on /debug/ztunnelz. "+
+			"The interface listens on localhost, istiod reaches it through port forwards to the ztunnel pods.").Get()
+
+	EnableDryRunAuthzMetrics = env.Register(
+		"PILOT_ENABLE_DRY_RUN_AUTHZ_METRICS",
+		false,
+		"If enabled, the istio_requests_total metric of sidecars and gateways has the dry_run_allow_policy, "+
+			"dry_run_allow_result, dry_run_deny_policy and dry_run_deny_result dimensions, reporting the shadow "+
+			"decisions of AuthorizationPolicies in dry-run mode. Requires Telemetry metrics to be enabled.").Get()
+
+	ProxyStatsPort = env.Register(
+		"PILOT_PROXY_STATS_PORT",
+		15090,
+		"The port of the proxy Prometheus stats endpoint that istiod queries to aggregate dry-run "+
+			"AuthorizationPolicy results on /debug/dryrunz. The endpoint must be reachable on the pod IP for this to work.").Get()
+
+	EnableTapSessions = env.Register(
+		"PILOT_ENABLE_TAP_SESSIONS",
+		false,
+		"If enabled, the inbound HTTP listeners of sidecars and the listeners of gateways have the Envoy tap filter, "+
+			"so time-boxed tap sessions can be started on a proxy through /debug/tapz or istioctl x tap.").Get()
+
 	// EnableUnsafeAssertions enables runtime checks to test assertions in our code. This should never be enabled in
 	// production; when assertions fail Istio will panic.
 	EnableUnsafeAssertions = env.Register(
@@ -672,6 +878,23 @@ var (
 	MulticlusterHeadlessEnabled = env.Register("ENABLE_MULTICLUSTER_HEADLESS", true,
 		"If true, the DNS name table for a headless service will resolve to same-network endpoints in any cluster.").Get()
 
+	DNSSearchDomains = func() []string {
+		domains := env.Register("PILOT_DNS_SEARCH_DOMAINS", "",
+			"Comma separated list of search domains the DNS proxy of the agents expands the hosts of the name table "+
+				"with, in addition to the search domains of their resolv.conf.").Get()
+		if domains == "" {
+			return nil
+		}
+		return strings.Split(domains, ",")
+	}()
+
+	DNSForwardingRules = env.Register("PILOT_DNS_FORWARDING_RULES", "",
+		"Comma separated list of domain=server conditional forwarding rules of the DNS proxy of the agents, e.g. "+
+			"corp.example.com=10.0.0.10,corp.example.com=10.0.0.11:5353. The queries of the domain and its subdomains "+
+			"which are not answered from the name table are forwarded to the servers of the domain instead of the "+
+			"servers of the resolv.conf of the agent. Istiod does not start if a rule is not domain=server, or its "+
+			"server not an ip or ip:port.").Get()
+
 	ResolveHostnameGateways = env.Register("RESOLVE_HOSTNAME_GATEWAYS", true,
 		"If true, hostnames in the LoadBalancer addresses of a Service will be resolved at the control plane for use in cross-network gateways.").Get()
 
@@ -696,6 +919,12 @@ var (
 	VerifyCertAtClient = env.Register("VERIFY_CERTIFICATE_AT_CLIENT", false,
 		"If enabled, certificates received by the proxy will be verified against the OS CA certificate bundle.").Get()
 
+	SecureNamingAudit = env.Register("PILOT_SECURE_NAMING_AUDIT", false,
+		"If enabled, the identities of the endpoints of a service which do not match the subject alt names of the "+
+			"ISTIO_MUTUAL settings of its destination rule are logged and counted in the pilot_secure_naming_mismatches "+
+			"metric whenever the services or destination rules change. The subject alt names sent to the proxies are "+
+			"unchanged, so the connections to these endpoints still fail.").Get()
+
 	PrioritizedLeaderElection = env.Register("PRIORITIZED_LEADER_ELECTION", true,
 		"If enabled, the default revision will steal leader locks from non-default revisions").Get()
 
@@ -728,6 +957,10 @@ var (
 	LocalClusterSecretWatcher = env.Register("LOCAL_CLUSTER_SECRET_WATCHER", false,
 		"If enabled, the cluster secret watcher will watch the namespace of the external cluster instead of config cluster").Get()
 
+	EnableClusterRegistrations = env.Register("PILOT_ENABLE_CLUSTER_REGISTRATIONS", false,
+		"If enabled, remote clusters can be registered by ClusterRegistrations in the istiod namespace, declaring their API "+
+			"server, network, locality and credentials, as an alternative to the kubeconfig remote secrets").Get()
+
 	SidecarIgnorePort = env.Register("SIDECAR_IGNORE_PORT_IN_HOST_MATCH", true, "If enabled, port will not be used in vhost domain matches.").Get()
 
 	EnableEnhancedResourceScoping = env.Register("ENABLE_ENHANCED_RESOURCE_SCOPING", false,
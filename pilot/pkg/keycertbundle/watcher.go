@@ -15,6 +15,7 @@
 package keycertbundle
 
 import (
+	"bytes"
 	"os"
 	"sync"
 
@@ -26,6 +27,8 @@ type KeyCertBundle struct {
 	CertPem  []byte
 	KeyPem   []byte
 	CABundle []byte
+	// CRL is the certificate revocation list of the CA, if any.
+	CRL []byte
 }
 
 type Watcher struct {
@@ -88,6 +91,23 @@ func (w *Watcher) SetAndNotify(key, cert, caBundle []byte) {
 	}
 }
 
+// SetCRLAndNotify sets the certificate revocation list and notifies the watchers if it changed.
+// An empty crl clears the current one.
+func (w *Watcher) SetCRLAndNotify(crl []byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if bytes.Equal(w.bundle.CRL, crl) {
+		return
+	}
+	w.bundle.CRL = crl
+	for _, ch := range w.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // SetFromFilesAndNotify sets the key cert and root cert from files and notify the watchers.
 func (w *Watcher) SetFromFilesAndNotify(keyFile, certFile, rootCert string) error {
 	cert, err := os.ReadFile(certFile)
@@ -130,6 +150,13 @@ func (w *Watcher) GetCABundle() []byte {
 	return w.bundle.CABundle
 }
 
+// GetCRL returns the certificate revocation list.
+func (w *Watcher) GetCRL() []byte {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.bundle.CRL
+}
+
 // GetCABundle returns the CABundle.
 func (w *Watcher) GetKeyCertBundle() KeyCertBundle {
 	w.mutex.Lock()
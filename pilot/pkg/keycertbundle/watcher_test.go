@@ -78,6 +78,41 @@ func TestWatcher(t *testing.T) {
 	}
 }
 
+func TestWatcherCRL(t *testing.T) {
+	watcher := NewWatcher()
+	_, watch := watcher.AddWatcher()
+
+	crl := []byte("crl")
+	watcher.SetCRLAndNotify(crl)
+	select {
+	case <-watch:
+		if !bytes.Equal(watcher.GetCRL(), crl) {
+			t.Errorf("got wrong CRL %s", watcher.GetCRL())
+		}
+	default:
+		t.Errorf("watcher not notified of the CRL")
+	}
+
+	// Setting the same CRL again does not notify.
+	watcher.SetCRLAndNotify(crl)
+	select {
+	case <-watch:
+		t.Errorf("watcher notified of an unchanged CRL")
+	default:
+	}
+
+	// Clearing the CRL notifies.
+	watcher.SetCRLAndNotify(nil)
+	select {
+	case <-watch:
+		if len(watcher.GetCRL()) != 0 {
+			t.Errorf("CRL should be cleared, got %s", watcher.GetCRL())
+		}
+	default:
+		t.Errorf("watcher not notified of the cleared CRL")
+	}
+}
+
 func TestWatcherFromFile(t *testing.T) {
 	watcher := NewWatcher()
 
@@ -50,6 +50,10 @@ const (
 	GatewayDeploymentController = "istio-gateway-deployment-leader"
 	StatusController            = "istio-status-leader"
 	AnalyzeController           = "istio-analyze-leader"
+	// ClusterRegistrationController controls the status of the ClusterRegistrations.
+	ClusterRegistrationController = "istio-cluster-registration-leader"
+	// IPAllocationController persists the addresses auto-allocated to the ServiceEntries.
+	IPAllocationController = "istio-ip-allocation-leader"
 )
 
 // Leader election key prefix for remote istiod managed clusters
@@ -15,7 +15,10 @@
 package model
 
 import (
+	"golang.org/x/exp/slices"
+
 	authpb "istio.io/api/security/v1beta1"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/schema/gvk"
 )
@@ -66,10 +69,27 @@ type AuthorizationPoliciesResult struct {
 }
 
 // ListAuthorizationPolicies returns authorization policies applied to the workload in the given namespace.
+// Policies targeting a specific listener of a Kubernetes Gateway are not included.
 func (policy *AuthorizationPolicies) ListAuthorizationPolicies(namespace string, workload labels.Instance) AuthorizationPoliciesResult {
+	ret, _ := policy.listAuthorizationPolicies(namespace, workload, nil)
+	return ret
+}
+
+// ListAuthorizationPoliciesForListeners returns authorization policies applied to the workload in the given namespace,
+// for a filter chain serving the given listeners of a Kubernetes Gateway. It also returns whether any of the policies
+// targets one of the listeners, in which case they differ from the policies applied to the whole workload.
+func (policy *AuthorizationPolicies) ListAuthorizationPoliciesForListeners(namespace string, workload labels.Instance,
+	listeners []string,
+) (AuthorizationPoliciesResult, bool) {
+	return policy.listAuthorizationPolicies(namespace, workload, listeners)
+}
+
+func (policy *AuthorizationPolicies) listAuthorizationPolicies(namespace string, workload labels.Instance,
+	listeners []string,
+) (AuthorizationPoliciesResult, bool) {
 	ret := AuthorizationPoliciesResult{}
 	if policy == nil {
-		return ret
+		return ret, false
 	}
 
 	var namespaces []string
@@ -81,27 +101,49 @@ func (policy *AuthorizationPolicies) ListAuthorizationPolicies(namespace string,
 		namespaces = append(namespaces, namespace)
 	}
 
+	targetsListener := false
 	for _, ns := range namespaces {
 		for _, config := range policy.NamespaceToPolicies[ns] {
-			spec := config.Spec
-			selector := labels.Instance(spec.GetSelector().GetMatchLabels())
-			if selector.SubsetOf(workload) {
-				switch config.Spec.GetAction() {
-				case authpb.AuthorizationPolicy_ALLOW:
-					ret.Allow = append(ret.Allow, config)
-				case authpb.AuthorizationPolicy_DENY:
-					ret.Deny = append(ret.Deny, config)
-				case authpb.AuthorizationPolicy_AUDIT:
-					ret.Audit = append(ret.Audit, config)
-				case authpb.AuthorizationPolicy_CUSTOM:
-					ret.Custom = append(ret.Custom, config)
-				default:
-					log.Errorf("ignored authorization policy %s.%s with unsupported action: %s",
-						config.Namespace, config.Name, config.Spec.GetAction())
-				}
+			applies, forListener := config.appliesTo(namespace, workload, listeners)
+			if !applies {
+				continue
+			}
+			targetsListener = targetsListener || forListener
+			switch config.Spec.GetAction() {
+			case authpb.AuthorizationPolicy_ALLOW:
+				ret.Allow = append(ret.Allow, config)
+			case authpb.AuthorizationPolicy_DENY:
+				ret.Deny = append(ret.Deny, config)
+			case authpb.AuthorizationPolicy_AUDIT:
+				ret.Audit = append(ret.Audit, config)
+			case authpb.AuthorizationPolicy_CUSTOM:
+				ret.Custom = append(ret.Custom, config)
+			default:
+				log.Errorf("ignored authorization policy %s.%s with unsupported action: %s",
+					config.Namespace, config.Name, config.Spec.GetAction())
 			}
 		}
 	}
 
-	return ret
+	return ret, targetsListener
+}
+
+// appliesTo returns whether the policy applies to the workload in the given namespace, for a filter chain serving the
+// given listeners of a Kubernetes Gateway, and whether it does so because it targets one of the listeners.
+func (policy AuthorizationPolicy) appliesTo(namespace string, workload labels.Instance, listeners []string) (bool, bool) {
+	gateway := policy.Annotations[constants.TargetGatewayAnnotation]
+	if gateway == "" {
+		selector := labels.Instance(policy.Spec.GetSelector().GetMatchLabels())
+		return selector.SubsetOf(workload), false
+	}
+	// A policy targeting a Gateway only applies to the Gateway in its own namespace.
+	if policy.Namespace != namespace || workload[constants.GatewayNameLabel] != gateway {
+		return false, false
+	}
+	section := policy.Annotations[constants.TargetSectionNameAnnotation]
+	if section == "" {
+		return true, false
+	}
+	applies := slices.Contains(listeners, section)
+	return applies, applies
 }
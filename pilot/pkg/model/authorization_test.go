@@ -25,6 +25,7 @@ import (
 	authpb "istio.io/api/security/v1beta1"
 	selectorpb "istio.io/api/type/v1beta1"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/schema/collection"
 	"istio.io/istio/pkg/config/schema/gvk"
@@ -336,6 +337,91 @@ func TestAuthorizationPolicies_ListAuthorizationPolicies(t *testing.T) {
 	}
 }
 
+func TestAuthorizationPolicies_ListAuthorizationPoliciesForListeners(t *testing.T) {
+	policy := &authpb.AuthorizationPolicy{
+		Rules: []*authpb.Rule{{From: []*authpb.Rule_From{{Source: &authpb.Source{Principals: []string{"sleep"}}}}}},
+	}
+	targetConfig := func(name, ns, gateway, section string) config.Config {
+		cfg := newConfig(name, ns, policy)
+		cfg.Annotations = map[string]string{constants.TargetGatewayAnnotation: gateway}
+		if section != "" {
+			cfg.Annotations[constants.TargetSectionNameAnnotation] = section
+		}
+		return cfg
+	}
+	names := func(policies []AuthorizationPolicy) []string {
+		var ret []string
+		for _, p := range policies {
+			ret = append(ret, p.Name)
+		}
+		return ret
+	}
+	configs := []config.Config{
+		newConfig("workload", "bar", policy),
+		targetConfig("gateway", "bar", "gw", ""),
+		targetConfig("admin", "bar", "gw", "admin"),
+		targetConfig("other-gateway", "bar", "other", ""),
+		targetConfig("root", "istio-config", "gw", ""),
+	}
+	gatewayLabels := map[string]string{constants.GatewayNameLabel: "gw"}
+
+	cases := []struct {
+		name         string
+		ns           string
+		labels       map[string]string
+		listeners    []string
+		wantAllow    []string
+		wantTargeted bool
+	}{
+		{
+			name:      "gateway without listener",
+			ns:        "bar",
+			labels:    gatewayLabels,
+			wantAllow: []string{"gateway", "workload"},
+		},
+		{
+			name:      "other listener",
+			ns:        "bar",
+			labels:    gatewayLabels,
+			listeners: []string{"http"},
+			wantAllow: []string{"gateway", "workload"},
+		},
+		{
+			name:         "targeted listener",
+			ns:           "bar",
+			labels:       gatewayLabels,
+			listeners:    []string{"http", "admin"},
+			wantAllow:    []string{"admin", "gateway", "workload"},
+			wantTargeted: true,
+		},
+		{
+			name:      "gateway in another namespace",
+			ns:        "foo",
+			labels:    gatewayLabels,
+			listeners: []string{"admin"},
+		},
+		{
+			name:      "not a gateway",
+			ns:        "bar",
+			labels:    map[string]string{"app": "gw"},
+			listeners: []string{"admin"},
+			wantAllow: []string{"workload"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			authzPolicies := createFakeAuthorizationPolicies(configs)
+			result, targeted := authzPolicies.ListAuthorizationPoliciesForListeners(tc.ns, tc.labels, tc.listeners)
+			if got := names(result.Allow); !reflect.DeepEqual(tc.wantAllow, got) {
+				t.Errorf("wantAllow: %v\n but got: %v\n", tc.wantAllow, got)
+			}
+			if targeted != tc.wantTargeted {
+				t.Errorf("wantTargeted: %v but got: %v", tc.wantTargeted, targeted)
+			}
+		})
+	}
+}
+
 func createFakeAuthorizationPolicies(configs []config.Config) *AuthorizationPolicies {
 	store := &authzFakeStore{}
 	for _, cfg := range configs {
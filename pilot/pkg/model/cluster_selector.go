@@ -0,0 +1,93 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// ClusterSelector returns the clusters the config is scoped to by the cluster-selector annotation, or nil if it
+// applies to every cluster.
+func ClusterSelector(meta config.Meta) cluster.Selector {
+	value, f := meta.Annotations[constants.ClusterSelectorAnnotation]
+	if !f {
+		return nil
+	}
+	selector, err := cluster.ParseSelector(value)
+	if err != nil {
+		log.Debugf("ignoring invalid %s annotation of %s/%s: %v", constants.ClusterSelectorAnnotation, meta.Namespace, meta.Name, err)
+		return nil
+	}
+	return selector
+}
+
+// VirtualServicesForCluster returns the virtual services applying to the proxies of the cluster: those without a
+// cluster selector and those selecting the cluster. A virtual service selecting the cluster shadows the virtual
+// services without a cluster selector sharing one of its hosts, so a routing change can be rolled out to some
+// clusters while the others keep the previous virtual service.
+func (ps *PushContext) VirtualServicesForCluster(vss []config.Config, id cluster.ID) []config.Config {
+	selectors := ps.virtualServiceIndex.clusterSelectors
+	if len(selectors) == 0 {
+		return vss
+	}
+	scoped := false
+	for _, vs := range vss {
+		if _, f := selectors[virtualServiceKey(vs)]; f {
+			scoped = true
+			break
+		}
+	}
+	if !scoped {
+		return vss
+	}
+
+	shadowed := sets.String{}
+	for _, vs := range vss {
+		if selector, f := selectors[virtualServiceKey(vs)]; f && selector.Matches(id) {
+			shadowed.InsertAll(vs.Spec.(*networking.VirtualService).Hosts...)
+		}
+	}
+	out := make([]config.Config, 0, len(vss))
+	for _, vs := range vss {
+		if selector, f := selectors[virtualServiceKey(vs)]; f {
+			if selector.Matches(id) {
+				out = append(out, vs)
+			}
+			continue
+		}
+		if !containsAnyHost(shadowed, vs.Spec.(*networking.VirtualService).Hosts) {
+			out = append(out, vs)
+		}
+	}
+	return out
+}
+
+func virtualServiceKey(vs config.Config) ConfigKey {
+	return ConfigKey{Kind: kind.VirtualService, Name: vs.Name, Namespace: vs.Namespace}
+}
+
+func containsAnyHost(hosts sets.String, candidates []string) bool {
+	for _, h := range candidates {
+		if hosts.Contains(h) {
+			return true
+		}
+	}
+	return false
+}
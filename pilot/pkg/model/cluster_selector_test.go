@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/visibility"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestVirtualServicesForCluster(t *testing.T) {
+	vs := func(name, clusters string, hosts ...string) config.Config {
+		meta := config.Meta{Name: name, Namespace: "default"}
+		if clusters != "" {
+			meta.Annotations = map[string]string{constants.ClusterSelectorAnnotation: clusters}
+		}
+		return config.Config{Meta: meta, Spec: &networking.VirtualService{Hosts: hosts}}
+	}
+	vss := []config.Config{
+		vs("reviews", "", "reviews"),
+		vs("reviews-canary", "cluster-1,cluster-2", "reviews"),
+		vs("ratings", "", "ratings"),
+		vs("ratings-canary", "cluster-3", "ratings"),
+	}
+	ps := NewPushContext()
+	for _, vs := range vss {
+		if selector := ClusterSelector(vs.Meta); len(selector) > 0 {
+			ps.virtualServiceIndex.clusterSelectors[virtualServiceKey(vs)] = selector
+		}
+	}
+	names := func(vss []config.Config) []string {
+		var out []string
+		for _, vs := range vss {
+			out = append(out, vs.Name)
+		}
+		return out
+	}
+
+	cases := []struct {
+		cluster cluster.ID
+		want    []string
+	}{
+		{cluster: "cluster-1", want: []string{"reviews-canary", "ratings"}},
+		{cluster: "cluster-2", want: []string{"reviews-canary", "ratings"}},
+		{cluster: "cluster-3", want: []string{"reviews", "ratings-canary"}},
+		{cluster: "cluster-4", want: []string{"reviews", "ratings"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.cluster.String(), func(t *testing.T) {
+			assert.Equal(t, names(ps.VirtualServicesForCluster(vss, tt.cluster)), tt.want)
+		})
+	}
+
+	unscoped := []config.Config{vs("reviews", "", "reviews")}
+	assert.Equal(t, ps.VirtualServicesForCluster(unscoped, "cluster-1"), unscoped)
+}
+
+func TestDestinationRuleForCluster(t *testing.T) {
+	ps := NewPushContext()
+	ps.Mesh = &meshconfig.MeshConfig{RootNamespace: "istio-system"}
+	ps.exportToDefaults.destinationRule = map[visibility.Instance]bool{visibility.Public: true}
+	dr := func(name, clusters string) config.Config {
+		meta := config.Meta{Name: name, Namespace: "test"}
+		if clusters != "" {
+			meta.Annotations = map[string]string{constants.ClusterSelectorAnnotation: clusters}
+		}
+		return config.Config{Meta: meta, Spec: &networking.DestinationRule{Host: "reviews.test.svc.cluster.local"}}
+	}
+	ps.setDestinationRules([]config.Config{dr("reviews", ""), dr("reviews-canary", "cluster-1")})
+
+	svc := &Service{Hostname: "reviews.test.svc.cluster.local", Attributes: ServiceAttributes{Namespace: "test"}}
+	// the rules scoped to different clusters are not merged
+	drs := ps.destinationRule("test", svc)
+	assert.Equal(t, len(drs), 2)
+
+	sc := &SidecarScope{Namespace: "test", destinationRules: map[host.Name][]*ConsolidatedDestRule{svc.Hostname: drs}}
+	for id, want := range map[cluster.ID]string{"cluster-1": "reviews-canary", "cluster-2": "reviews"} {
+		proxy := &Proxy{Metadata: &NodeMetadata{ClusterID: id}}
+		got := sc.DestinationRule(TrafficDirectionOutbound, proxy, svc.Hostname)
+		assert.Equal(t, got.GetRule().Name, want)
+	}
+}
@@ -17,10 +17,12 @@ package model
 import (
 	"sort"
 	"strings"
+	"time"
 
 	udpa "github.com/cncf/xds/go/udpa/type/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/schema/collection"
@@ -71,6 +73,45 @@ func (key ConfigKey) String() string {
 	return key.Kind.String() + "/" + key.Namespace + "/" + key.Name
 }
 
+// ConfigDrift is a copy of a config in a remote primary cluster diverging from the config of the config cluster.
+type ConfigDrift struct {
+	Kind      string     `json:"kind"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	Cluster   cluster.ID `json:"cluster"`
+	// Fields are the paths of the fields of the spec which differ between the copies.
+	Fields []string `json:"fields"`
+}
+
+// ControllerDependencies is the dependency graph of a controller: the inputs whose changes recompute its outputs.
+type ControllerDependencies struct {
+	Controller string `json:"controller"`
+	// Output is the kind of the objects reconciled by the controller.
+	Output string            `json:"output"`
+	Inputs []ControllerInput `json:"inputs"`
+	// Failed are the outputs no longer retried, as their reconcile failed too many times.
+	Failed []FailedReconcile `json:"failed,omitempty"`
+}
+
+// FailedReconcile is an output of a controller whose reconcile is no longer retried, until its inputs change.
+type FailedReconcile struct {
+	Output   string    `json:"output"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	Time     time.Time `json:"time"`
+}
+
+// ControllerInput is an input of a controller, with the recomputations of the outputs its changes triggered.
+type ControllerInput struct {
+	Input string `json:"input"`
+	// Trigger describes the outputs recomputed on a change of the input.
+	Trigger string `json:"trigger"`
+	// Events is the number of changes of the input.
+	Events uint64 `json:"events"`
+	// Recomputations is the number of outputs the changes of the input queued for recomputation.
+	Recomputations uint64 `json:"recomputations"`
+}
+
 // ConfigsOfKind extracts configs of the specified kind.
 func ConfigsOfKind(configs sets.Set[ConfigKey], kind kind.Kind) sets.Set[ConfigKey] {
 	ret := make(sets.Set[ConfigKey])
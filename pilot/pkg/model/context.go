@@ -106,6 +106,10 @@ type Environment struct {
 	// EndpointShards for a service. This is a global (per-server) list, built from
 	// incremental updates. This is keyed by service and namespace
 	EndpointIndex *EndpointIndex
+
+	// TrustDomainMigration, if set, adds the previous trust domain of the mesh to the trust domain aliases
+	// of the mesh config of the pushes during the migration window.
+	TrustDomainMigration *TrustDomainMigration
 }
 
 func (e *Environment) Mesh() *meshconfig.MeshConfig {
@@ -1217,12 +1221,17 @@ func (node *Proxy) EnableHBONE() bool {
 type WaypointScope struct {
 	Namespace      string
 	ServiceAccount string // optional
+	// Cluster, if set, restricts the scope to waypoints and workloads in the given cluster. Waypoints only
+	// serve workloads in their own cluster, so traffic to a workload traverses exactly one waypoint,
+	// local to the workload, regardless of which cluster originated the request.
+	Cluster cluster.ID // optional
 }
 
 func (node *Proxy) WaypointScope() WaypointScope {
 	return WaypointScope{
 		Namespace:      node.ConfigNamespace,
 		ServiceAccount: node.Metadata.Annotations[constants.WaypointServiceAccount],
+		Cluster:        node.Metadata.ClusterID,
 	}
 }
 
@@ -40,6 +40,7 @@ import (
 func (ps *PushContext) mergeDestinationRule(p *consolidatedDestRules, destRuleConfig config.Config, exportToMap map[visibility.Instance]bool) {
 	rule := destRuleConfig.Spec.(*networking.DestinationRule)
 	resolvedHost := ResolveShortnameToFQDN(rule.Host, destRuleConfig.Meta)
+	clusterSelector := ClusterSelector(destRuleConfig.Meta)
 
 	var destRules map[host.Name][]*ConsolidatedDestRule
 
@@ -63,6 +64,11 @@ func (ps *PushContext) mergeDestinationRule(p *consolidatedDestRules, destRuleCo
 				// if the selectors do not match
 				continue
 			}
+			if !clusterSelector.Equals(mdr.clusterSelector) {
+				// Destination rules scoped to different clusters are never merged, the rule of each proxy is picked by
+				// its cluster
+				continue
+			}
 			// If both the destination rules are without a workload selector or with matching workload selectors, simply merge them.
 			// If the incoming rule has a workload selector, it has to be merged with the existing rules with workload selector, and
 			// at the same time added as a unique entry in the processedDestRules.
@@ -154,13 +160,15 @@ func (ps *PushContext) inheritDestinationRule(parent, child *ConsolidatedDestRul
 	out.rule = &merged
 	out.from = append(out.from, parent.from...)
 	out.from = append(out.from, child.from...)
+	out.clusterSelector = child.clusterSelector
 	return out
 }
 
 func ConvertConsolidatedDestRule(cfg *config.Config) *ConsolidatedDestRule {
 	return &ConsolidatedDestRule{
-		rule: cfg,
-		from: []types.NamespacedName{config.NamespacedName(cfg)},
+		rule:            cfg,
+		from:            []types.NamespacedName{config.NamespacedName(cfg)},
+		clusterSelector: ClusterSelector(cfg.Meta),
 	}
 }
 
@@ -155,6 +155,23 @@ func (e *EndpointIndex) Shardz() map[string]map[string]*EndpointShards {
 	return out
 }
 
+// EndpointsByCluster returns the number of endpoints of each cluster.
+func (e *EndpointIndex) EndpointsByCluster() map[cluster.ID]int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := map[cluster.ID]int{}
+	for _, byNamespace := range e.shardsBySvc {
+		for _, shards := range byNamespace {
+			shards.RLock()
+			for k, eps := range shards.Shards {
+				out[k.Cluster] += len(eps)
+			}
+			shards.RUnlock()
+		}
+	}
+	return out
+}
+
 // ShardsForService returns the shards and true if they are found, or returns nil, false.
 func (e *EndpointIndex) ShardsForService(serviceName, namespace string) (*EndpointShards, bool) {
 	e.mu.RLock()
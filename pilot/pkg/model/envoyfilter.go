@@ -22,6 +22,9 @@ import (
 
 	networking "istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/envoyfilter"
+	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/xds"
 	"istio.io/istio/pkg/util/sets"
@@ -32,7 +35,12 @@ type EnvoyFilterWrapper struct {
 	Name             string
 	Namespace        string
 	workloadSelector labels.Instance
-	Patches          map[networking.EnvoyFilter_ApplyTo][]*EnvoyFilterConfigPatchWrapper
+	// targeted is set if the EnvoyFilter applies to a Kubernetes Gateway or Service of its namespace, the
+	// workloadSelector then selecting the proxies of the Gateway.
+	targeted bool
+	// targetService is the hostname of the Service whose workloads the EnvoyFilter applies to, if any.
+	targetService host.Name
+	Patches       map[networking.EnvoyFilter_ApplyTo][]*EnvoyFilterConfigPatchWrapper
 }
 
 // EnvoyFilterConfigPatchWrapper is a wrapper over the EnvoyFilter ConfigPatch api object
@@ -145,6 +153,19 @@ func convertToEnvoyFilterWrapper(local *config.Config) *EnvoyFilterWrapper {
 	return out
 }
 
+// setTargetRef makes the EnvoyFilter apply to the target of its target-ref annotation rather than to the workloads
+// matching its selector.
+func (efw *EnvoyFilterWrapper) setTargetRef(ref envoyfilter.TargetRef, domainSuffix string) {
+	efw.targeted = true
+	switch ref.Kind {
+	case envoyfilter.GatewayKind:
+		efw.workloadSelector = labels.Instance{constants.GatewayNameLabel: ref.Name}
+	case envoyfilter.ServiceKind:
+		efw.workloadSelector = nil
+		efw.targetService = host.Name(ref.Name + "." + efw.Namespace + ".svc." + domainSuffix)
+	}
+}
+
 func proxyMatch(proxy *Proxy, cp *EnvoyFilterConfigPatchWrapper) bool {
 	if cp.Match.Proxy == nil {
 		return true
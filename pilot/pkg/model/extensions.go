@@ -32,7 +32,9 @@ import (
 	istionetworking "istio.io/istio/pilot/pkg/networking"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/wasmplugin"
 	"istio.io/istio/pkg/util/protomarshal"
 )
 
@@ -159,7 +161,13 @@ func convertToWasmPluginWrapper(originPlugin config.Config) *WasmPluginWrapper {
 	}
 	// Normalize the image pull secret to the full resource name.
 	wasmPlugin.ImagePullSecret = toSecretResourceName(wasmPlugin.ImagePullSecret, plugin.Namespace)
-	datasource := buildDataSource(u, wasmPlugin)
+	var pull wasmplugin.PullOptions
+	if value, f := plugin.Annotations[constants.WasmPullAnnotation]; f {
+		if pull, err = wasmplugin.ParsePullOptions(value); err != nil {
+			log.Warnf("wasmplugin %v/%v: ignoring invalid %s annotation: %v", plugin.Namespace, plugin.Name, constants.WasmPullAnnotation, err)
+		}
+	}
+	datasource := buildDataSource(u, wasmPlugin, pull)
 	resourceName := plugin.Namespace + "." + plugin.Name
 	wasmExtensionConfig := &envoyWasmFilterV3.Wasm{
 		Config: &envoyExtensionsWasmV3.PluginConfig{
@@ -204,7 +212,7 @@ func toSecretResourceName(name, pluginNamespace string) string {
 	return sr.KubernetesResourceName()
 }
 
-func buildDataSource(u *url.URL, wasmPlugin *extensions.WasmPlugin) *core.AsyncDataSource {
+func buildDataSource(u *url.URL, wasmPlugin *extensions.WasmPlugin, pull wasmplugin.PullOptions) *core.AsyncDataSource {
 	if u.Scheme == fileScheme {
 		return &core.AsyncDataSource{
 			Specifier: &core.AsyncDataSource_Local{
@@ -217,20 +225,27 @@ func buildDataSource(u *url.URL, wasmPlugin *extensions.WasmPlugin) *core.AsyncD
 		}
 	}
 
-	return &core.AsyncDataSource{
-		Specifier: &core.AsyncDataSource_Remote{
-			Remote: &core.RemoteDataSource{
-				HttpUri: &core.HttpUri{
-					Uri:     u.String(),
-					Timeout: durationpb.New(30 * time.Second),
-					HttpUpstreamType: &core.HttpUri_Cluster{
-						// this will be fetched by the agent anyway, so no need for a cluster
-						Cluster: "_",
-					},
-				},
-				Sha256: wasmPlugin.Sha256,
+	timeout := 30 * time.Second
+	if pull.Timeout != nil {
+		timeout = *pull.Timeout
+	}
+	remote := &core.RemoteDataSource{
+		HttpUri: &core.HttpUri{
+			Uri:     u.String(),
+			Timeout: durationpb.New(timeout),
+			HttpUpstreamType: &core.HttpUri_Cluster{
+				// this will be fetched by the agent anyway, so no need for a cluster
+				Cluster: "_",
 			},
 		},
+		Sha256: wasmPlugin.Sha256,
+	}
+	if pull.Retries != nil {
+		// Read by the agent, which fetches the module.
+		remote.RetryPolicy = &core.RetryPolicy{NumRetries: wrapperspb.UInt32(*pull.Retries)}
+	}
+	return &core.AsyncDataSource{
+		Specifier: &core.AsyncDataSource_Remote{Remote: remote},
 	}
 }
 
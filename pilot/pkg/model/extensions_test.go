@@ -22,12 +22,15 @@ import (
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoyExtensionsWasmV3 "github.com/envoyproxy/go-control-plane/envoy/extensions/wasm/v3"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	extensions "istio.io/api/extensions/v1alpha1"
 	"istio.io/api/type/v1beta1"
 	"istio.io/istio/pilot/pkg/model/credentials"
 	"istio.io/istio/pilot/pkg/networking"
 	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/wasmplugin"
+	"istio.io/istio/pkg/ptr"
 	"istio.io/istio/pkg/test/util/assert"
 )
 
@@ -35,6 +38,7 @@ func TestBuildDataSource(t *testing.T) {
 	cases := []struct {
 		url        string
 		wasmPlugin *extensions.WasmPlugin
+		pull       wasmplugin.PullOptions
 
 		expected *core.AsyncDataSource
 	}{
@@ -73,13 +77,35 @@ func TestBuildDataSource(t *testing.T) {
 				},
 			},
 		},
+		{
+			url: "https://example.com/fake.wasm",
+			wasmPlugin: &extensions.WasmPlugin{
+				Sha256: "fake-sha256",
+			},
+			pull: wasmplugin.PullOptions{Timeout: ptr.Of(time.Minute), Retries: ptr.Of(uint32(3))},
+			expected: &core.AsyncDataSource{
+				Specifier: &core.AsyncDataSource_Remote{
+					Remote: &core.RemoteDataSource{
+						HttpUri: &core.HttpUri{
+							Uri:     "https://example.com/fake.wasm",
+							Timeout: durationpb.New(time.Minute),
+							HttpUpstreamType: &core.HttpUri_Cluster{
+								Cluster: "_",
+							},
+						},
+						Sha256:      "fake-sha256",
+						RetryPolicy: &core.RetryPolicy{NumRetries: wrapperspb.UInt32(3)},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run("", func(t *testing.T) {
 			u, err := url.Parse(tc.url)
 			assert.NoError(t, err)
-			got := buildDataSource(u, tc.wasmPlugin)
+			got := buildDataSource(u, tc.wasmPlugin, tc.pull)
 			assert.Equal(t, tc.expected, got)
 		})
 	}
@@ -506,6 +506,25 @@ func ParseGatewayRDSRouteName(name string) (portNumber int, portName, gatewayNam
 	return
 }
 
+// HTTPRouteRuleName returns the name of the HTTP route of the VirtualService generated from the rule at index of the
+// Kubernetes HTTPRoute name in namespace. Format: <namespace>.<name>.<index>
+func HTTPRouteRuleName(namespace, name string, index int) string {
+	return namespace + "." + name + "." + strconv.Itoa(index)
+}
+
+// IsHTTPRouteRuleName returns whether the Envoy route named routeName is generated from a rule of the Kubernetes
+// HTTPRoute name in namespace. The Envoy route is named after the HTTP route, optionally followed by the name of its
+// match.
+func IsHTTPRouteRuleName(routeName, namespace, name string) bool {
+	index, f := strings.CutPrefix(routeName, namespace+"."+name+".")
+	if !f {
+		return false
+	}
+	index, _, _ = strings.Cut(index, ".")
+	_, err := strconv.Atoi(index)
+	return err == nil
+}
+
 // convert ./host to currentNamespace/Host
 // */host to just host
 // */* to just *
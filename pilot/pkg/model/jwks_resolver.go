@@ -109,6 +109,31 @@ type jwtPubKeyEntry struct {
 	lastUsedTime time.Time
 }
 
+// JwksIssuerConfig holds the settings to fetch the JWKS of a single issuer, configured with PILOT_JWKS_ISSUERS.
+type JwksIssuerConfig struct {
+	Issuer string `json:"issuer"`
+	// Proxy is the URL of the HTTP proxy, for example an egress gateway, to fetch the JWKS through.
+	// If not set, the HTTP_PROXY and HTTPS_PROXY environment variables of istiod are used.
+	Proxy string `json:"proxy,omitempty"`
+	// CABundle is the path of a PEM bundle of additional CA certificates to validate the server with.
+	CABundle string `json:"caBundle,omitempty"`
+	// RefreshInterval is the interval to refresh the JWKS of the issuer. If not set, the JWKS is
+	// refreshed with the other issuers, every PILOT_JWT_PUB_KEY_REFRESH_INTERVAL.
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// RefreshIntervalOnFailure is the initial interval of the exponential backoff to refresh the JWKS
+	// of the issuer after a failure. Defaults to the RefreshInterval.
+	RefreshIntervalOnFailure string `json:"refreshIntervalOnFailure,omitempty"`
+}
+
+// jwksIssuer is a parsed JwksIssuerConfig.
+type jwksIssuer struct {
+	httpClient       *http.Client
+	secureHTTPClient *http.Client
+
+	refreshInterval          time.Duration
+	refreshIntervalOnFailure time.Duration
+}
+
 // jwtKey is a key in the JwksResolver keyEntries map.
 type jwtKey struct {
 	jwksURI string
@@ -150,6 +175,14 @@ type JwksResolver struct {
 
 	// Whenever istiod fails to fetch the pubkey from jwksuri in main flow this variable becomes true for background trigger
 	jwksUribackgroundChannel bool
+
+	// issuers holds the issuers with specific fetch settings, keyed by issuer.
+	issuers map[string]*jwksIssuer
+
+	// stop shuts down the refresher jobs of the issuers.
+	stop chan struct{}
+	// closeOnce guards Close, as closing stop twice panics and nothing receives a second closeChan message.
+	closeOnce sync.Once
 }
 
 func init() {
@@ -180,12 +213,46 @@ func newJwksResolverWithCABundlePaths(
 		refreshDefaultInterval:   refreshDefaultInterval,
 		refreshIntervalOnFailure: refreshIntervalOnFailure,
 		retryInterval:            retryInterval,
-		httpClient: &http.Client{
-			Timeout: jwksHTTPTimeOutInSec * time.Second,
-			Transport: &http.Transport{
-				Proxy:             http.ProxyFromEnvironment,
-				DisableKeepAlives: true,
-			},
+		issuers:                  map[string]*jwksIssuer{},
+		stop:                     make(chan struct{}),
+	}
+	ret.httpClient, ret.secureHTTPClient = newJwksHTTPClients(http.ProxyFromEnvironment, caBundlePaths)
+
+	// NewServer already refused to start istiod on an invalid PILOT_JWKS_ISSUERS, only a resolver created on its own,
+	// e.g. by a test, gets here with one.
+	configs, err := ParseJwksIssuers(features.JwksIssuers)
+	if err != nil {
+		log.Errorf("Ignoring invalid PILOT_JWKS_ISSUERS: %v", err)
+	}
+	for _, cfg := range configs {
+		issuer, err := newJwksIssuer(cfg, caBundlePaths)
+		if err != nil {
+			log.Errorf("Ignoring invalid PILOT_JWKS_ISSUERS entry for issuer %q: %v", cfg.Issuer, err)
+			continue
+		}
+		ret.issuers[cfg.Issuer] = issuer
+	}
+
+	atomic.StoreUint64(&ret.refreshJobKeyChangedCount, 0)
+	atomic.StoreUint64(&ret.refreshJobFetchFailedCount, 0)
+	go ret.refresher()
+	for name, issuer := range ret.issuers {
+		if issuer.refreshInterval > 0 {
+			go ret.issuerRefresher(name, issuer)
+		}
+	}
+
+	return ret
+}
+
+// newJwksHTTPClients creates the clients to fetch JWKS with, over HTTP and HTTPS. The HTTPS client trusts the
+// system CAs and the CAs of the given bundles, it is nil if there is no CA to trust.
+func newJwksHTTPClients(proxy func(*http.Request) (*url.URL, error), caBundlePaths []string) (*http.Client, *http.Client) {
+	httpClient := &http.Client{
+		Timeout: jwksHTTPTimeOutInSec * time.Second,
+		Transport: &http.Transport{
+			Proxy:             proxy,
+			DisableKeepAlives: true,
 		},
 	}
 
@@ -205,25 +272,77 @@ func newJwksResolverWithCABundlePaths(
 		}
 	}
 
-	if caCertsFound {
-		ret.secureHTTPClient = &http.Client{
-			Timeout: jwksHTTPTimeOutInSec * time.Second,
-			Transport: &http.Transport{
-				Proxy:             http.ProxyFromEnvironment,
-				DisableKeepAlives: true,
-				TLSClientConfig: &tls.Config{
-					RootCAs:    caCertPool,
-					MinVersion: tls.VersionTLS12,
-				},
+	if !caCertsFound {
+		return httpClient, nil
+	}
+	return httpClient, &http.Client{
+		Timeout: jwksHTTPTimeOutInSec * time.Second,
+		Transport: &http.Transport{
+			Proxy:             proxy,
+			DisableKeepAlives: true,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    caCertPool,
+				MinVersion: tls.VersionTLS12,
 			},
-		}
+		},
 	}
+}
 
-	atomic.StoreUint64(&ret.refreshJobKeyChangedCount, 0)
-	atomic.StoreUint64(&ret.refreshJobFetchFailedCount, 0)
-	go ret.refresher()
+// ParseJwksIssuers parses the PILOT_JWKS_ISSUERS setting, returning an error if any of its issuers is invalid.
+func ParseJwksIssuers(value string) ([]JwksIssuerConfig, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var configs []JwksIssuerConfig
+	d := json.NewDecoder(strings.NewReader(value))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&configs); err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		if _, err := newJwksIssuer(cfg, nil); err != nil {
+			return nil, fmt.Errorf("issuer %q: %v", cfg.Issuer, err)
+		}
+	}
+	return configs, nil
+}
 
-	return ret
+func newJwksIssuer(cfg JwksIssuerConfig, caBundlePaths []string) (*jwksIssuer, error) {
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("issuer is required")
+	}
+	proxy := http.ProxyFromEnvironment
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %v", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	if cfg.CABundle != "" {
+		if _, err := os.Stat(cfg.CABundle); err != nil {
+			return nil, fmt.Errorf("invalid caBundle: %v", err)
+		}
+		caBundlePaths = append(append([]string{}, caBundlePaths...), cfg.CABundle)
+	}
+	ret := &jwksIssuer{}
+	var err error
+	if cfg.RefreshInterval != "" {
+		if ret.refreshInterval, err = time.ParseDuration(cfg.RefreshInterval); err != nil || ret.refreshInterval <= 0 {
+			return nil, fmt.Errorf("invalid refreshInterval %q", cfg.RefreshInterval)
+		}
+		ret.refreshIntervalOnFailure = ret.refreshInterval
+	}
+	if cfg.RefreshIntervalOnFailure != "" {
+		if ret.refreshInterval == 0 {
+			return nil, fmt.Errorf("refreshIntervalOnFailure requires refreshInterval")
+		}
+		if ret.refreshIntervalOnFailure, err = time.ParseDuration(cfg.RefreshIntervalOnFailure); err != nil || ret.refreshIntervalOnFailure <= 0 {
+			return nil, fmt.Errorf("invalid refreshIntervalOnFailure %q", cfg.RefreshIntervalOnFailure)
+		}
+	}
+	ret.httpClient, ret.secureHTTPClient = newJwksHTTPClients(proxy, caBundlePaths)
+	return ret, nil
 }
 
 var errEmptyPubKeyFoundInCache = errors.New("empty public key found in cache")
@@ -255,7 +374,7 @@ func (r *JwksResolver) GetPublicKey(issuer string, jwksURI string) (string, erro
 		log.Errorf("Failed to jwks URI from %q: %v", issuer, err)
 	} else {
 		var resp []byte
-		resp, err = r.getRemoteContentWithRetry(jwksURI, networkFetchRetryCountOnMainFlow)
+		resp, err = r.getRemoteContentWithRetry(issuer, jwksURI, networkFetchRetryCountOnMainFlow)
 		if err != nil {
 			log.Errorf("Failed to fetch public key from %q: %v", jwksURI, err)
 		}
@@ -309,7 +428,7 @@ func CreateFakeJwks(jwksURI string) string {
 // Resolve jwks_uri through openID discovery.
 func (r *JwksResolver) resolveJwksURIUsingOpenID(issuer string) (string, error) {
 	// Try to get jwks_uri through OpenID Discovery.
-	body, err := r.getRemoteContentWithRetry(issuer+openIDDiscoveryCfgURLSuffix, networkFetchRetryCountOnMainFlow)
+	body, err := r.getRemoteContentWithRetry(issuer, issuer+openIDDiscoveryCfgURLSuffix, networkFetchRetryCountOnMainFlow)
 	if err != nil {
 		log.Errorf("Failed to fetch jwks_uri from %q: %v", issuer+openIDDiscoveryCfgURLSuffix, err)
 		return "", err
@@ -327,21 +446,24 @@ func (r *JwksResolver) resolveJwksURIUsingOpenID(issuer string) (string, error)
 	return jwksURI, nil
 }
 
-func (r *JwksResolver) getRemoteContentWithRetry(uri string, retry int) ([]byte, error) {
+func (r *JwksResolver) getRemoteContentWithRetry(issuer, uri string, retry int) ([]byte, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
 		log.Errorf("Failed to parse %q", uri)
 		return nil, err
 	}
 
-	client := r.httpClient
+	client, secureClient := r.httpClient, r.secureHTTPClient
+	if iss, f := r.issuers[issuer]; f {
+		client, secureClient = iss.httpClient, iss.secureHTTPClient
+	}
 	if strings.EqualFold(u.Scheme, "https") {
 		// https client may be uninitialized because of root CA bundle missing.
-		if r.secureHTTPClient == nil {
+		if secureClient == nil {
 			return nil, fmt.Errorf("pilot does not support fetch public key through https endpoint %q", uri)
 		}
 
-		client = r.secureHTTPClient
+		client = secureClient
 	}
 
 	getPublicKey := func() (b []byte, e error) {
@@ -412,26 +534,66 @@ func (r *JwksResolver) refresher() {
 
 func (r *JwksResolver) refreshCache(lastHasError bool) bool {
 	currentHasError := r.refresh()
-	if currentHasError {
-		if lastHasError {
-			// update to exponential backoff if last time also failed.
-			r.refreshInterval *= 2
-			if r.refreshInterval > JwtPubKeyRefreshIntervalOnFailureResetThreshold {
-				r.refreshInterval = JwtPubKeyRefreshIntervalOnFailureResetThreshold
-			}
-		} else {
-			// change to the refreshIntervalOnFailure if failed for the first time.
-			r.refreshInterval = r.refreshIntervalOnFailure
+	r.refreshInterval = nextRefreshInterval(r.refreshInterval, r.refreshDefaultInterval, r.refreshIntervalOnFailure,
+		currentHasError, lastHasError)
+	r.refreshTicker.Reset(r.refreshInterval)
+	return currentHasError
+}
+
+// issuerRefresher refreshes the keys of an issuer with its own refresh interval.
+func (r *JwksResolver) issuerRefresher(name string, issuer *jwksIssuer) {
+	interval := issuer.refreshInterval
+	ticker := time.NewTicker(interval)
+	lastHasError := false
+	for {
+		select {
+		case <-ticker.C:
+			hasError := r.refreshEntries(func(k jwtKey, _ jwtPubKeyEntry) bool {
+				return k.issuer == name
+			})
+			interval = nextRefreshInterval(interval, issuer.refreshInterval, issuer.refreshIntervalOnFailure, hasError, lastHasError)
+			lastHasError = hasError
+			ticker.Reset(interval)
+		case <-r.stop:
+			ticker.Stop()
+			return
 		}
-	} else {
+	}
+}
+
+// nextRefreshInterval returns the interval until the next refresh. It is reset to the default interval on success,
+// and backs off exponentially from the interval on failure, up to JwtPubKeyRefreshIntervalOnFailureResetThreshold.
+func nextRefreshInterval(current, defaultInterval, intervalOnFailure time.Duration, hasError, lastHasError bool) time.Duration {
+	if !hasError {
 		// reset the refresh interval if success.
-		r.refreshInterval = r.refreshDefaultInterval
+		return defaultInterval
 	}
-	r.refreshTicker.Reset(r.refreshInterval)
-	return currentHasError
+	if !lastHasError {
+		// change to the refreshIntervalOnFailure if failed for the first time.
+		return intervalOnFailure
+	}
+	// update to exponential backoff if last time also failed.
+	next := current * 2
+	if next > JwtPubKeyRefreshIntervalOnFailureResetThreshold {
+		next = JwtPubKeyRefreshIntervalOnFailureResetThreshold
+	}
+	return next
 }
 
 func (r *JwksResolver) refresh() bool {
+	return r.refreshEntries(func(k jwtKey, e jwtPubKeyEntry) bool {
+		if r.jwksUribackgroundChannel {
+			// Only fetch the keys that failed in the main flow.
+			return e.pubKey == ""
+		}
+		// The issuers with their own refresh interval are refreshed by their own refresher job.
+		iss, f := r.issuers[k.issuer]
+		return !f || iss.refreshInterval == 0
+	})
+}
+
+// refreshEntries refreshes the cached keys selected by the filter, returning true if any refresh failed.
+func (r *JwksResolver) refreshEntries(filter func(jwtKey, jwtPubKeyEntry) bool) bool {
 	var wg sync.WaitGroup
 	hasChange := false
 	hasErrors := false
@@ -440,7 +602,7 @@ func (r *JwksResolver) refresh() bool {
 		k := key.(jwtKey)
 		e := value.(jwtPubKeyEntry)
 
-		if e.pubKey != "" && r.jwksUribackgroundChannel {
+		if !filter(k, e) {
 			return true
 		}
 		// Remove cached item for either of the following 2 situations
@@ -475,7 +637,7 @@ func (r *JwksResolver) refresh() bool {
 				r.keyEntries.Delete(k)
 				k.jwksURI = jwksURI
 			}
-			resp, err := r.getRemoteContentWithRetry(jwksURI, networkFetchRetryCountOnRefreshFlow)
+			resp, err := r.getRemoteContentWithRetry(k.issuer, jwksURI, networkFetchRetryCountOnRefreshFlow)
 			if err != nil {
 				hasErrors = true
 				log.Errorf("Failed to refresh JWT public key from %q: %v", jwksURI, err)
@@ -523,7 +685,10 @@ func (r *JwksResolver) refresh() bool {
 // TODO: may need to figure out the right place to call this function.
 // (right now calls it from initDiscoveryService in pkg/bootstrap/server.go).
 func (r *JwksResolver) Close() {
-	closeChan <- true
+	r.closeOnce.Do(func() {
+		closeChan <- true
+		close(r.stop)
+	})
 }
 
 // Compare two JWKS responses, returning true if there is a difference and false otherwise
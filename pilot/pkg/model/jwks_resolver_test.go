@@ -16,13 +16,17 @@ package model
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"go.opencensus.io/stats/view"
 
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model/test"
+	istiotest "istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/util/retry"
 )
 
@@ -585,3 +589,125 @@ func TestCompareJWKSResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPublicKeyUsingIssuerCABundle(t *testing.T) {
+	istiotest.SetForTest(t, &features.JwksIssuers,
+		`[{"issuer":"https://private.example.com","caBundle":"./test/testcert/cert.pem"}]`)
+	r := newJwksResolverWithCABundlePaths(
+		JwtPubKeyEvictionDuration,
+		JwtPubKeyRefreshInterval,
+		testRetryInterval,
+		testRetryInterval,
+		[]string{},
+	)
+	defer r.Close()
+
+	ms, err := test.StartNewTLSServer("./test/testcert/cert.pem", "./test/testcert/key.pem")
+	defer ms.Stop()
+	if err != nil {
+		t.Fatal("failed to start a mock server")
+	}
+
+	mockCertURL := ms.URL + "/oauth2/v3/certs"
+	pk, err := r.GetPublicKey("https://private.example.com", mockCertURL)
+	if err != nil {
+		t.Errorf("GetPublicKey(\"https://private.example.com\", %+v) fails: expected no error, got (%v)", mockCertURL, err)
+	}
+	if test.JwtPubKey1 != pk {
+		t.Errorf("GetPublicKey(\"https://private.example.com\", %+v): expected (%s), got (%s)", mockCertURL, test.JwtPubKey1, pk)
+	}
+
+	// The CA bundle of the issuer is not trusted for other issuers.
+	if _, err := r.GetPublicKey("https://other.example.com", mockCertURL); err == nil {
+		t.Errorf("GetPublicKey(\"https://other.example.com\", %+v) did not fail: expected bad certificate error", mockCertURL)
+	}
+}
+
+func TestGetPublicKeyUsingIssuerProxy(t *testing.T) {
+	var proxiedHost atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxiedHost.Store(req.URL.Host)
+		_, _ = w.Write([]byte(test.JwtPubKey2))
+	}))
+	defer proxy.Close()
+
+	istiotest.SetForTest(t, &features.JwksIssuers,
+		fmt.Sprintf(`[{"issuer":"https://airgapped.example.com","proxy":%q}]`, proxy.URL))
+	r := NewJwksResolver(JwtPubKeyEvictionDuration, JwtPubKeyRefreshInterval, JwtPubKeyRefreshIntervalOnFailure, testRetryInterval)
+	defer r.Close()
+
+	pk, err := r.GetPublicKey("https://airgapped.example.com", "http://idp.example.com/jwks")
+	if err != nil {
+		t.Fatalf("GetPublicKey() fails: expected no error, got (%v)", err)
+	}
+	if test.JwtPubKey2 != pk {
+		t.Errorf("GetPublicKey(): expected (%s), got (%s)", test.JwtPubKey2, pk)
+	}
+	if got := proxiedHost.Load(); got != "idp.example.com" {
+		t.Errorf("expected the request to idp.example.com to go through the proxy, got %v", got)
+	}
+}
+
+func TestIssuerRefreshInterval(t *testing.T) {
+	ms, err := test.StartNewServer()
+	defer ms.Stop()
+	if err != nil {
+		t.Fatal("failed to start a mock server")
+	}
+
+	istiotest.SetForTest(t, &features.JwksIssuers,
+		`[{"issuer":"https://fast.example.com","refreshInterval":"10ms","refreshIntervalOnFailure":"5ms"}]`)
+	r := NewJwksResolver(JwtPubKeyEvictionDuration, time.Hour, time.Hour, testRetryInterval)
+	defer r.Close()
+
+	mockCertURL := ms.URL + "/oauth2/v3/certs"
+	if _, err := r.GetPublicKey("https://fast.example.com", mockCertURL); err != nil {
+		t.Fatalf("GetPublicKey() fails: expected no error, got (%v)", err)
+	}
+	// The issuer is refreshed on its own interval, although the default refresh interval is an hour.
+	retry.UntilSuccessOrFail(t, func() error {
+		if got := atomic.LoadUint64(&ms.PubKeyHitNum); got < 3 {
+			return fmt.Errorf("expected the issuer keys to be refreshed, got %d hits", got)
+		}
+		return nil
+	}, retry.Timeout(5*time.Second))
+}
+
+func TestCloseTwice(t *testing.T) {
+	r := NewJwksResolver(JwtPubKeyEvictionDuration, JwtPubKeyRefreshInterval, JwtPubKeyRefreshIntervalOnFailure, testRetryInterval)
+	r.Close()
+	// The second call must neither panic nor block.
+	r.Close()
+}
+
+func TestParseJwksIssuers(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  JwksIssuerConfig
+		wantErr bool
+	}{
+		{"default", JwksIssuerConfig{Issuer: "https://a"}, false},
+		{"missing issuer", JwksIssuerConfig{}, true},
+		{"refresh intervals", JwksIssuerConfig{Issuer: "https://a", RefreshInterval: "5m", RefreshIntervalOnFailure: "10s"}, false},
+		{"invalid refresh interval", JwksIssuerConfig{Issuer: "https://a", RefreshInterval: "soon"}, true},
+		{"failure interval without interval", JwksIssuerConfig{Issuer: "https://a", RefreshIntervalOnFailure: "10s"}, true},
+		{"missing ca bundle", JwksIssuerConfig{Issuer: "https://a", CABundle: "./test/testcert/missing.pem"}, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newJwksIssuer(tt.config, nil)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if got, err := ParseJwksIssuers(`[{"issuer":"https://a","proxy":"http://egress:3128"}]`); err != nil || len(got) != 1 ||
+		got[0].Proxy != "http://egress:3128" {
+		t.Fatalf("unexpected issuers %v: %v", got, err)
+	}
+	for _, invalid := range []string{`not json`, `[{"issuer":""}]`, `[{"issuer":"https://a","proxyURL":"http://egress:3128"}]`} {
+		if _, err := ParseJwksIssuers(invalid); err == nil {
+			t.Fatalf("expected an error for the issuers %s", invalid)
+		}
+	}
+}
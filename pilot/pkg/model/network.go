@@ -44,6 +44,8 @@ type NetworkGateway struct {
 	Addr string
 	// gateway port
 	Port uint32
+	// HBONEPort is the port of the HBONE listener of the gateway, 0 if the gateway does not accept HBONE.
+	HBONEPort uint32
 }
 
 type NetworkGatewaysWatcher interface {
@@ -34,6 +34,7 @@ import (
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/envoyfilter"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/protocol"
@@ -106,6 +107,9 @@ type virtualServiceIndex struct {
 
 	// Map of VS hostname -> referenced hostnames
 	referencedDestinations map[string]sets.String
+
+	// The cluster selectors of the virtual services scoped to clusters, keyed by virtual service
+	clusterSelectors map[ConfigKey]cluster.Selector
 }
 
 func newVirtualServiceIndex() virtualServiceIndex {
@@ -115,6 +119,7 @@ func newVirtualServiceIndex() virtualServiceIndex {
 		exportedToNamespaceByGateway: map[types.NamespacedName][]config.Config{},
 		delegates:                    map[ConfigKey][]ConfigKey{},
 		referencedDestinations:       map[string]sets.String{},
+		clusterSelectors:             map[ConfigKey]cluster.Selector{},
 	}
 	if features.FilterGatewayClusterConfig {
 		out.destinationsByGateway = make(map[string]sets.String)
@@ -210,6 +215,10 @@ type PushContext struct {
 	// serviceAccounts contains a map of hostname and port to service accounts.
 	serviceAccounts map[serviceAccountKey][]string
 
+	// secureNamingMismatches are the identities of the endpoints which do not match the subject alt names of their
+	// destination rules, when PILOT_SECURE_NAMING_AUDIT is enabled.
+	secureNamingMismatches sets.Set[secureNamingMismatch]
+
 	// virtualServiceIndex is the index of virtual services by various fields.
 	virtualServiceIndex virtualServiceIndex
 
@@ -290,6 +299,8 @@ type ConsolidatedDestRule struct {
 	rule *config.Config
 	// the original dest rules from which above rule is merged.
 	from []types.NamespacedName
+	// the clusters the rule is scoped to, parsed from the cluster-selector annotation of the rule.
+	clusterSelector cluster.Selector
 }
 
 // XDSUpdater is used for direct updates of the xDS model and incremental push.
@@ -417,6 +428,8 @@ const (
 	NamespaceUpdate TriggerReason = "namespace"
 	// ClusterUpdate describes a push triggered by a Cluster change
 	ClusterUpdate TriggerReason = "cluster"
+	// TapTrigger describes a push of a tap session to a proxy
+	TapTrigger TriggerReason = "tap"
 )
 
 // Merge two update requests together
@@ -505,6 +518,11 @@ func (pr *PushRequest) IsRequest() bool {
 	return len(pr.Reason) == 1 && pr.Reason[0] == ProxyRequest
 }
 
+// IsTap returns whether the request only pushes a tap session, which does not change the config of the proxy.
+func (pr *PushRequest) IsTap() bool {
+	return len(pr.Reason) == 1 && pr.Reason[0] == TapTrigger
+}
+
 func (pr *PushRequest) IsProxyUpdate() bool {
 	for _, r := range pr.Reason {
 		if r == ProxyUpdate {
@@ -1171,7 +1189,7 @@ func (ps *PushContext) InitContext(env *Environment, oldPushContext *PushContext
 		return nil
 	}
 
-	ps.Mesh = env.Mesh()
+	ps.Mesh = env.TrustDomainMigration.Apply(env.Mesh(), time.Now())
 	ps.Networks = env.MeshNetworks()
 	ps.LedgerVersion = env.Version()
 
@@ -1190,6 +1208,10 @@ func (ps *PushContext) InitContext(env *Environment, oldPushContext *PushContext
 		}
 	}
 
+	if features.SecureNamingAudit {
+		ps.initSecureNamingAudit(env, oldPushContext, pushReq)
+	}
+
 	ps.networkMgr = env.NetworkManager
 
 	ps.clusterLocalHosts = env.ClusterLocal().GetClusterLocalHosts()
@@ -1307,6 +1329,7 @@ func (ps *PushContext) updateContext(
 
 	if telemetryChanged {
 		ps.initTelemetry(env)
+		ps.Telemetry.retainRouteTelemetryKeys(oldPushContext.Telemetry, pushReq.ConfigsUpdated)
 	} else {
 		ps.Telemetry = oldPushContext.Telemetry
 	}
@@ -1465,6 +1488,7 @@ func (ps *PushContext) initVirtualServices(env *Environment) {
 	ps.virtualServiceIndex.privateByNamespaceAndGateway = map[types.NamespacedName][]config.Config{}
 	ps.virtualServiceIndex.publicByGateway = map[string][]config.Config{}
 	ps.virtualServiceIndex.referencedDestinations = map[string]sets.String{}
+	ps.virtualServiceIndex.clusterSelectors = map[ConfigKey]cluster.Selector{}
 
 	if features.FilterGatewayClusterConfig {
 		ps.virtualServiceIndex.destinationsByGateway = make(map[string]sets.String)
@@ -1496,6 +1520,9 @@ func (ps *PushContext) initVirtualServices(env *Environment) {
 	vservices, ps.virtualServiceIndex.delegates = mergeVirtualServicesIfNeeded(vservices, ps.exportToDefaults.virtualService)
 
 	for _, virtualService := range vservices {
+		if selector := ClusterSelector(virtualService.Meta); len(selector) > 0 {
+			ps.virtualServiceIndex.clusterSelectors[virtualServiceKey(virtualService)] = selector
+		}
 		ns := virtualService.Namespace
 		rule := virtualService.Spec.(*networking.VirtualService)
 		gwNames := getGatewayNames(rule)
@@ -1900,6 +1927,14 @@ func (ps *PushContext) initEnvoyFilters(env *Environment) {
 	ps.envoyFiltersByNamespace = make(map[string][]*EnvoyFilterWrapper)
 	for _, envoyFilterConfig := range envoyFilterConfigs {
 		efw := convertToEnvoyFilterWrapper(&envoyFilterConfig)
+		if v, f := envoyFilterConfig.Annotations[constants.EnvoyFilterTargetRefAnnotation]; f {
+			ref, err := envoyfilter.ParseTargetRef(v)
+			if err != nil {
+				log.Warnf("ignoring envoyfilter %s/%s: %v", envoyFilterConfig.Namespace, envoyFilterConfig.Name, err)
+				continue
+			}
+			efw.setTargetRef(ref, env.DomainSuffix)
+		}
 		if _, exists := ps.envoyFiltersByNamespace[envoyFilterConfig.Namespace]; !exists {
 			ps.envoyFiltersByNamespace[envoyFilterConfig.Namespace] = make([]*EnvoyFilterWrapper, 0)
 		}
@@ -1950,9 +1985,22 @@ func (ps *PushContext) EnvoyFilters(proxy *Proxy) *EnvoyFilterWrapper {
 
 // if there is no workload selector, the config applies to all workloads
 // if there is a workload selector, check for matching workload labels
+// if there is a target, the config applies to the proxies of the Gateway or the workloads of the Service
 func (ps *PushContext) getMatchedEnvoyFilters(proxy *Proxy, namespaces string) []*EnvoyFilterWrapper {
 	matchedEnvoyFilters := make([]*EnvoyFilterWrapper, 0)
 	for _, efw := range ps.envoyFiltersByNamespace[namespaces] {
+		if efw.targeted && proxy.ConfigNamespace != efw.Namespace {
+			// The targets are in the namespace of the EnvoyFilter, even in the root namespace.
+			continue
+		}
+		if efw.targetService != "" {
+			svc := ps.ServiceIndex.HostnameAndNamespace[efw.targetService][efw.Namespace]
+			if svc != nil && len(svc.Attributes.LabelSelectors) > 0 &&
+				labels.Instance(svc.Attributes.LabelSelectors).SubsetOf(proxy.Labels) {
+				matchedEnvoyFilters = append(matchedEnvoyFilters, efw)
+			}
+			continue
+		}
 		if efw.workloadSelector == nil || efw.workloadSelector.SubsetOf(proxy.Labels) {
 			matchedEnvoyFilters = append(matchedEnvoyFilters, efw)
 		}
@@ -2182,3 +2230,8 @@ func (ps *PushContext) WaypointsFor(scope WaypointScope) sets.Set[netip.Addr] {
 func (ps *PushContext) WorkloadsForWaypoint(scope WaypointScope) []*WorkloadInfo {
 	return ps.ambientIndex.WorkloadsForWaypoint(scope)
 }
+
+// AmbientInteropMode returns how sidecars send traffic to ambient workloads in the namespace.
+func (ps *PushContext) AmbientInteropMode(namespace string) string {
+	return ps.ambientIndex.InteropMode(namespace)
+}
@@ -462,6 +462,72 @@ func TestEnvoyFilterOrder(t *testing.T) {
 	}
 }
 
+func TestEnvoyFilterTargetRef(t *testing.T) {
+	env := &Environment{DomainSuffix: "cluster.local"}
+	store := NewFakeStore()
+	patches := []*networking.EnvoyFilter_EnvoyConfigObjectPatch{{
+		ApplyTo: networking.EnvoyFilter_CLUSTER,
+		Patch:   &networking.EnvoyFilter_Patch{Operation: networking.EnvoyFilter_Patch_REMOVE},
+	}}
+	envoyFilters := []config.Config{
+		{
+			Meta: config.Meta{
+				Name: "gateway", Namespace: "testns", GroupVersionKind: gvk.EnvoyFilter,
+				Annotations: map[string]string{
+					constants.EnvoyFilterTargetRefAnnotation: `{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "ingress"}`,
+				},
+			},
+			Spec: &networking.EnvoyFilter{ConfigPatches: patches},
+		},
+		{
+			Meta: config.Meta{
+				Name: "service", Namespace: "testns", GroupVersionKind: gvk.EnvoyFilter,
+				Annotations: map[string]string{constants.EnvoyFilterTargetRefAnnotation: `{"kind": "Service", "name": "reviews"}`},
+			},
+			Spec: &networking.EnvoyFilter{ConfigPatches: patches},
+		},
+		{
+			Meta: config.Meta{
+				Name: "invalid", Namespace: "testns", GroupVersionKind: gvk.EnvoyFilter,
+				Annotations: map[string]string{constants.EnvoyFilterTargetRefAnnotation: `{"kind": "Gateway", "name": "ingress"}`},
+			},
+			Spec: &networking.EnvoyFilter{ConfigPatches: patches},
+		},
+	}
+	for _, cfg := range envoyFilters {
+		_, _ = store.Create(cfg)
+	}
+	env.ConfigStore = store
+	m := mesh.DefaultMeshConfig()
+	env.Watcher = mesh.NewFixedWatcher(m)
+	env.Init()
+
+	pc := NewPushContext()
+	pc.Mesh = m
+	pc.initEnvoyFilters(env)
+	pc.ServiceIndex.HostnameAndNamespace["reviews.testns.svc.cluster.local"] = map[string]*Service{
+		"testns": {Attributes: ServiceAttributes{LabelSelectors: map[string]string{"app": "reviews"}}},
+	}
+
+	cases := []struct {
+		name      string
+		labels    map[string]string
+		namespace string
+		want      []string
+	}{
+		{"gateway", map[string]string{constants.GatewayNameLabel: "ingress"}, "testns", []string{"testns/gateway"}},
+		{"service", map[string]string{"app": "reviews", "version": "v1"}, "testns", []string{"testns/service"}},
+		{"other namespace", map[string]string{"app": "reviews"}, "otherns", nil},
+		{"no target", map[string]string{"app": "ratings"}, "testns", nil},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &Proxy{Labels: tt.labels, Metadata: &NodeMetadata{Labels: tt.labels}, ConfigNamespace: tt.namespace}
+			assert.Equal(t, pc.EnvoyFilters(proxy).Keys(), tt.want)
+		})
+	}
+}
+
 func TestWasmPlugins(t *testing.T) {
 	env := &Environment{}
 	store := NewFakeStore()
@@ -0,0 +1,155 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/pkg/monitoring"
+)
+
+var (
+	namespaceTag = monitoring.MustCreateLabel("namespace")
+	hostTag      = monitoring.MustCreateLabel("host")
+
+	secureNamingMismatches = monitoring.NewGauge(
+		"pilot_secure_naming_mismatches",
+		"The number of identities of the endpoints of a service which do not match the subject alt names of the "+
+			"ISTIO_MUTUAL settings of a destination rule, by the namespace of the destination rule and the hostname "+
+			"of the service, when the secure naming is audited.",
+		monitoring.WithLabels(namespaceTag, hostTag),
+	)
+)
+
+func init() {
+	monitoring.MustRegister(secureNamingMismatches)
+}
+
+// secureNamingMismatch is an identity of the endpoints of a service port not matching the subject alt names a
+// destination rule sets for it.
+type secureNamingMismatch struct {
+	namespace       string
+	destinationRule string
+	hostname        host.Name
+	port            int
+	identity        string
+}
+
+// initSecureNamingAudit finds the identities of the endpoints of the services which do not match the subject alt names
+// of the ISTIO_MUTUAL settings of their destination rules. The subject alt names sent to the proxies are not changed,
+// so the connections to these endpoints fail; at run time, they are counted by the
+// cluster.<name>.ssl.fail_verify_san stat of Envoy. The audit only depends on the services and the destination rules,
+// so it is only recomputed when they change, and only the new mismatches are logged.
+func (ps *PushContext) initSecureNamingAudit(env *Environment, oldPushContext *PushContext, pushReq *PushRequest) {
+	var previous sets.Set[secureNamingMismatch]
+	if oldPushContext != nil && oldPushContext.InitDone.Load() {
+		previous = oldPushContext.secureNamingMismatches
+		if pushReq != nil && len(pushReq.ConfigsUpdated) > 0 &&
+			!HasConfigsOfKind(pushReq.ConfigsUpdated, kind.ServiceEntry) &&
+			!HasConfigsOfKind(pushReq.ConfigsUpdated, kind.DestinationRule) {
+			ps.secureNamingMismatches = previous
+			return
+		}
+	}
+
+	ps.secureNamingMismatches = ps.findSecureNamingMismatches(env.List(gvk.DestinationRule, NamespaceAll))
+	type countKey struct {
+		namespace string
+		hostname  host.Name
+	}
+	counts := map[countKey]int{}
+	for m := range previous {
+		counts[countKey{m.namespace, m.hostname}] = 0
+	}
+	for m := range ps.secureNamingMismatches {
+		counts[countKey{m.namespace, m.hostname}]++
+		if !previous.Contains(m) {
+			log.Warnf("secure naming mismatch: identity %s of %s:%d does not match the subject alt names of destination rule %s/%s",
+				m.identity, m.hostname, m.port, m.namespace, m.destinationRule)
+		}
+	}
+	for k, count := range counts {
+		secureNamingMismatches.With(namespaceTag.Value(k.namespace), hostTag.Value(string(k.hostname))).Record(float64(count))
+	}
+}
+
+// findSecureNamingMismatches returns the identities of the endpoints not matching the subject alt names of the
+// top level or port level ISTIO_MUTUAL settings of the destination rules. The settings of the subsets are not audited,
+// as the identities are not known by subset. The hosts of the rules are resolved as when looking up the destination
+// rule of a service: short names are relative to the namespace of the rule, and a wildcard host only applies to the
+// services no more specific host matches.
+func (ps *PushContext) findSecureNamingMismatches(destRules []config.Config) sets.Set[secureNamingMismatch] {
+	specific := map[host.Name][]config.Config{}
+	wildcard := map[host.Name][]config.Config{}
+	for _, dr := range destRules {
+		h := ResolveShortnameToFQDN(dr.Spec.(*networking.DestinationRule).Host, dr.Meta)
+		if h.IsWildCarded() {
+			wildcard[h] = append(wildcard[h], dr)
+		} else {
+			specific[h] = append(specific[h], dr)
+		}
+	}
+	out := sets.New[secureNamingMismatch]()
+	for hostname, services := range ps.ServiceIndex.HostnameAndNamespace {
+		_, rules, ok := MostSpecificHostMatch(hostname, specific, wildcard)
+		if !ok {
+			continue
+		}
+		for _, dr := range rules {
+			rule := dr.Spec.(*networking.DestinationRule)
+			for ns, svc := range services {
+				for _, port := range svc.Ports {
+					sans := istioMutualSubjectAltNames(rule.GetTrafficPolicy(), port.Port)
+					if len(sans) == 0 {
+						continue
+					}
+					expected := sets.New(sans...)
+					for _, sa := range ps.ServiceAccounts(svc.Hostname, ns, port.Port) {
+						if !expected.Contains(sa) {
+							out.Insert(secureNamingMismatch{
+								namespace:       dr.Namespace,
+								destinationRule: dr.Name,
+								hostname:        svc.Hostname,
+								port:            port.Port,
+								identity:        sa,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// istioMutualSubjectAltNames returns the subject alt names of the ISTIO_MUTUAL settings of the traffic policy for the
+// port, if any.
+func istioMutualSubjectAltNames(policy *networking.TrafficPolicy, port int) []string {
+	tls := policy.GetTls()
+	for _, pls := range policy.GetPortLevelSettings() {
+		if int(pls.GetPort().GetNumber()) == port && pls.GetTls() != nil {
+			tls = pls.GetTls()
+			break
+		}
+	}
+	if tls.GetMode() != networking.ClientTLSSettings_ISTIO_MUTUAL {
+		return nil
+	}
+	return tls.GetSubjectAltNames()
+}
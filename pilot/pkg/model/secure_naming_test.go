@@ -0,0 +1,96 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/util/sets"
+)
+
+func TestFindSecureNamingMismatches(t *testing.T) {
+	ps := NewPushContext()
+	svc := &Service{
+		Hostname:   "foo.ns.svc.cluster.local",
+		Ports:      PortList{{Port: 80}, {Port: 443}},
+		Attributes: ServiceAttributes{Namespace: "ns"},
+	}
+	ps.ServiceIndex.HostnameAndNamespace[svc.Hostname] = map[string]*Service{"ns": svc}
+	for _, port := range []int{80, 443} {
+		ps.serviceAccounts[serviceAccountKey{hostname: svc.Hostname, namespace: "ns", port: port}] = []string{
+			"spiffe://cluster.local/ns/ns/sa/a",
+			"spiffe://cluster.local/ns/ns/sa/b",
+		}
+	}
+	dr := config.Config{
+		Meta: config.Meta{GroupVersionKind: gvk.DestinationRule, Name: "foo", Namespace: "ns"},
+		Spec: &networking.DestinationRule{
+			Host: "foo.ns.svc.cluster.local",
+			TrafficPolicy: &networking.TrafficPolicy{
+				Tls: &networking.ClientTLSSettings{
+					Mode:            networking.ClientTLSSettings_ISTIO_MUTUAL,
+					SubjectAltNames: []string{"spiffe://cluster.local/ns/ns/sa/a"},
+				},
+				PortLevelSettings: []*networking.TrafficPolicy_PortTrafficPolicy{{
+					Port: &networking.PortSelector{Number: 443},
+					Tls:  &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE},
+				}},
+			},
+		},
+	}
+
+	got := ps.findSecureNamingMismatches([]config.Config{dr})
+	assert.Equal(t, got, sets.New(secureNamingMismatch{
+		namespace:       "ns",
+		destinationRule: "foo",
+		hostname:        host.Name("foo.ns.svc.cluster.local"),
+		port:            80,
+		identity:        "spiffe://cluster.local/ns/ns/sa/b",
+	}))
+
+	// A short name is resolved in the namespace of the rule.
+	short := dr.DeepCopy()
+	short.Domain = "cluster.local"
+	short.Spec.(*networking.DestinationRule).Host = "foo"
+	assert.Equal(t, ps.findSecureNamingMismatches([]config.Config{short}), got)
+
+	// A wildcard host applies to the service, unless a more specific rule exists.
+	wildcard := dr.DeepCopy()
+	wildcard.Name = "wildcard"
+	wildcard.Spec.(*networking.DestinationRule).Host = "*.ns.svc.cluster.local"
+	wildcard.Spec.(*networking.DestinationRule).TrafficPolicy.PortLevelSettings = nil
+	assert.Equal(t, ps.findSecureNamingMismatches([]config.Config{wildcard}), sets.New(
+		secureNamingMismatch{
+			namespace:       "ns",
+			destinationRule: "wildcard",
+			hostname:        host.Name("foo.ns.svc.cluster.local"),
+			port:            80,
+			identity:        "spiffe://cluster.local/ns/ns/sa/b",
+		},
+		secureNamingMismatch{
+			namespace:       "ns",
+			destinationRule: "wildcard",
+			hostname:        host.Name("foo.ns.svc.cluster.local"),
+			port:            443,
+			identity:        "spiffe://cluster.local/ns/ns/sa/b",
+		},
+	))
+	assert.Equal(t, ps.findSecureNamingMismatches([]config.Config{wildcard, dr}), got)
+}
@@ -606,6 +606,12 @@ type ServiceAttributes struct {
 	// Applicable to both Kubernetes and ServiceEntries.
 	LabelSelectors map[string]string
 
+	// For ServiceEntries
+
+	// DynamicForwardProxy is true if the HTTP requests to the wildcard host of the ServiceEntry are sent to the host
+	// of the request, resolved on demand. It is set by the networking.istio.io/dynamic-forward-proxy annotation.
+	DynamicForwardProxy bool
+
 	// For Kubernetes platform
 
 	// ClusterExternalAddresses is a mapping between a cluster name and the external
@@ -726,7 +732,7 @@ func (s *ServiceAttributes) Equals(other *ServiceAttributes) bool {
 			return false
 		}
 	}
-	return s.Name == other.Name && s.Namespace == other.Namespace &&
+	return s.Name == other.Name && s.Namespace == other.Namespace && s.DynamicForwardProxy == other.DynamicForwardProxy &&
 		s.ServiceRegistry == other.ServiceRegistry && s.K8sAttributes == other.K8sAttributes
 }
 
@@ -802,6 +808,9 @@ type AmbientIndexes interface {
 	Policies(requested sets.Set[ConfigKey]) []*workloadapi.Authorization
 	Waypoint(scope WaypointScope) sets.Set[netip.Addr]
 	WorkloadsForWaypoint(scope WaypointScope) []*WorkloadInfo
+	// InteropMode returns how sidecars send traffic to ambient workloads in the namespace.
+	// See constants.AmbientInteropMode.
+	InteropMode(namespace string) string
 }
 
 // NoopAmbientIndexes provides an implementation of AmbientIndexes that always returns nil, to easily "skip" it.
@@ -831,6 +840,10 @@ func (u NoopAmbientIndexes) WorkloadsForWaypoint(scope WaypointScope) []*Workloa
 	return nil
 }
 
+func (u NoopAmbientIndexes) InteropMode(string) string {
+	return ""
+}
+
 var _ AmbientIndexes = NoopAmbientIndexes{}
 
 type WorkloadInfo struct {
@@ -1019,7 +1032,10 @@ func (s *Service) GetAddressForProxy(node *Proxy) string {
 			}
 		}
 
-		if node.Metadata.DNSCapture && node.Metadata.DNSAutoAllocate && s.DefaultAddress == constants.UnspecifiedIP {
+		// Kubernetes services without cluster IP in the cluster of the proxy may have been allocated an address as well,
+		// rather than using the cluster IP of another cluster.
+		if node.Metadata.DNSCapture && node.Metadata.DNSAutoAllocate &&
+			(s.DefaultAddress == constants.UnspecifiedIP || s.Attributes.ServiceRegistry == provider.Kubernetes) {
 			if node.SupportsIPv4() && s.AutoAllocatedIPv4Address != "" {
 				return s.AutoAllocatedIPv4Address
 			}
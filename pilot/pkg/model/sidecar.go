@@ -570,10 +570,17 @@ func (sc *SidecarScope) AddConfigDependencies(dependencies ...ConfigHash) {
 func (sc *SidecarScope) DestinationRule(direction TrafficDirection, proxy *Proxy, svc host.Name) *ConsolidatedDestRule {
 	destinationRules := sc.destinationRules[svc]
 	var catchAllDr *ConsolidatedDestRule
+	catchAllScoped := false
 	for _, destRule := range destinationRules {
 		destinationRule := destRule.rule.Spec.(*networking.DestinationRule)
-		if destinationRule.GetWorkloadSelector() == nil {
+		clusterSelector := destRule.clusterSelector
+		if !clusterSelector.Matches(proxy.Metadata.ClusterID) {
+			continue
+		}
+		// A destination rule scoped to the cluster of the proxy takes precedence over the one without cluster selector
+		if destinationRule.GetWorkloadSelector() == nil && (len(clusterSelector) > 0 || !catchAllScoped) {
 			catchAllDr = destRule
+			catchAllScoped = len(clusterSelector) > 0
 		}
 		// filter DestinationRule based on workloadSelector for outbound configs.
 		// WorkloadSelector configuration is honored only for outbound configuration, because
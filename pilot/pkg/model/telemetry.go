@@ -15,6 +15,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -28,6 +29,8 @@ import (
 	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	wasmfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/wasm/v3"
 	wasm "github.com/envoyproxy/go-control-plane/envoy/extensions/wasm/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -37,13 +40,19 @@ import (
 	"istio.io/api/envoy/extensions/stats"
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	tpb "istio.io/api/telemetry/v1alpha1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/networking"
 	"istio.io/istio/pilot/pkg/util/protoconv"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
+	telemetryconfig "istio.io/istio/pkg/config/telemetry"
 	"istio.io/istio/pkg/config/xds"
 	"istio.io/istio/pkg/util/protomarshal"
 	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 // Telemetry holds configuration for Telemetry API resources.
@@ -51,6 +60,37 @@ type Telemetry struct {
 	Name      string         `json:"name"`
 	Namespace string         `json:"namespace"`
 	Spec      *tpb.Telemetry `json:"spec"`
+	telemetryAnnotations
+}
+
+// telemetryAnnotations are the values of the annotations of a Telemetry configuring what its spec can not.
+type telemetryAnnotations struct {
+	// HistogramBuckets is the value of the histogram-buckets annotation.
+	HistogramBuckets string `json:"histogramBuckets,omitempty"`
+	// MetricExpiry is the value of the metric-expiry annotation.
+	MetricExpiry string `json:"metricExpiry,omitempty"`
+	// TraceLabelTags is the value of the trace-label-tags annotation.
+	TraceLabelTags string `json:"traceLabelTags,omitempty"`
+	// TraceContextPropagation is the value of the trace-context-propagation annotation.
+	TraceContextPropagation string `json:"traceContextPropagation,omitempty"`
+}
+
+func newTelemetryAnnotations(annotations map[string]string) telemetryAnnotations {
+	return telemetryAnnotations{
+		HistogramBuckets:        annotations[constants.HistogramBucketsAnnotation],
+		MetricExpiry:            annotations[constants.MetricExpiryAnnotation],
+		TraceLabelTags:          annotations[constants.TraceLabelTagsAnnotation],
+		TraceContextPropagation: annotations[constants.TraceContextPropagationAnnotation],
+	}
+}
+
+// RouteTelemetry holds a Telemetry targeting the routes of a VirtualService or HTTPRoute with its target-ref
+// annotation. Only its tracing sampling percentage applies to the routes.
+type RouteTelemetry struct {
+	Name      string                    `json:"name"`
+	Namespace string                    `json:"namespace"`
+	TargetRef telemetryconfig.TargetRef `json:"targetRef"`
+	Spec      *tpb.Telemetry            `json:"spec"`
 }
 
 // Telemetries organizes Telemetry configuration by namespace.
@@ -61,6 +101,13 @@ type Telemetries struct {
 	// The name of the root namespace.
 	RootNamespace string `json:"root_namespace"`
 
+	// RouteTelemetries are the Telemetry configs targeting routes rather than workloads, ordered by creation time.
+	RouteTelemetries []RouteTelemetry `json:"route_telemetries,omitempty"`
+
+	// routeTelemetryKeys are the keys of the RouteTelemetries, and of the updated Telemetries which targeted routes
+	// in the previous push context, as only these impact the routes.
+	routeTelemetryKeys sets.Set[ConfigKey]
+
 	// Computed meshConfig
 	meshConfig *meshconfig.MeshConfig
 
@@ -76,6 +123,7 @@ type Telemetries struct {
 	// As result, this cache will live until any Telemetry is modified.
 	computedMetricsFilters map[metricsKey]any
 	computedLoggingConfig  map[loggingKey][]LoggingConfig
+	computedZtunnelLogging map[telemetryKey]*workloadapi.AccessLogging
 	mu                     sync.Mutex
 }
 
@@ -112,6 +160,8 @@ func getTelemetries(env *Environment) *Telemetries {
 		meshConfig:             env.Mesh(),
 		computedMetricsFilters: map[metricsKey]any{},
 		computedLoggingConfig:  map[loggingKey][]LoggingConfig{},
+		computedZtunnelLogging: map[telemetryKey]*workloadapi.AccessLogging{},
+		routeTelemetryKeys:     sets.New[ConfigKey](),
 	}
 
 	fromEnv := env.List(gvk.Telemetry, NamespaceAll)
@@ -121,6 +171,49 @@ func getTelemetries(env *Environment) *Telemetries {
 			Name:      config.Name,
 			Namespace: config.Namespace,
 			Spec:      config.Spec.(*tpb.Telemetry),
+
+			telemetryAnnotations: newTelemetryAnnotations(config.Annotations),
+		}
+		if v, f := config.Annotations[constants.TelemetryTargetRefAnnotation]; f {
+			ref, err := telemetryconfig.ParseTargetRef(v)
+			if err != nil {
+				log.Warnf("ignoring telemetry %s/%s: %v", config.Namespace, config.Name, err)
+				continue
+			}
+			telemetries.RouteTelemetries = append(telemetries.RouteTelemetries, RouteTelemetry{
+				Name:      config.Name,
+				Namespace: config.Namespace,
+				TargetRef: ref,
+				Spec:      telemetry.Spec,
+			})
+			telemetries.routeTelemetryKeys.Insert(ConfigKey{Kind: kind.Telemetry, Name: config.Name, Namespace: config.Namespace})
+			continue
+		}
+		if v, f := config.Annotations[constants.AccessLogProviderFiltersAnnotation]; f {
+			filters, err := telemetryconfig.ParseAccessLogProviderFilters(v)
+			if err != nil {
+				log.Warnf("ignoring access log provider filters of telemetry %s/%s: %v", config.Namespace, config.Name, err)
+			} else {
+				telemetry.Spec = applyAccessLogProviderFilters(telemetry.Spec, filters)
+			}
+		}
+		if v, f := config.Annotations[constants.RouteMetricDimensionsAnnotation]; f {
+			dims, err := telemetryconfig.ParseRouteDimensions(v)
+			if err != nil {
+				log.Warnf("ignoring route metric dimensions of telemetry %s/%s: %v", config.Namespace, config.Name, err)
+			} else {
+				telemetry.Spec = applyHTTPMetricDimensions(telemetry.Spec, dims)
+			}
+		}
+		if v, f := config.Annotations[constants.RequestOperationsAnnotation]; f {
+			ops, err := telemetryconfig.ParseRequestOperations(v)
+			if err != nil {
+				log.Warnf("ignoring request operations of telemetry %s/%s: %v", config.Namespace, config.Name, err)
+			} else if len(ops) > 0 {
+				telemetry.Spec = applyHTTPMetricDimensions(telemetry.Spec, map[string]string{
+					telemetryconfig.RequestOperationDimension: telemetryconfig.RequestOperationExpression(ops),
+				})
+			}
 		}
 		telemetries.NamespaceToTelemetries[config.Namespace] = append(telemetries.NamespaceToTelemetries[config.Namespace], telemetry)
 	}
@@ -132,6 +225,8 @@ type metricsConfig struct {
 	ClientMetrics     metricConfig
 	ServerMetrics     metricConfig
 	ReportingInterval *durationpb.Duration
+	// MetricExpiry is the duration after which the metrics which are not updated are expired.
+	MetricExpiry *durationpb.Duration
 }
 
 type metricConfig struct {
@@ -181,6 +276,8 @@ type computedTelemetries struct {
 	Metrics []*tpb.Metrics
 	Logging []*computedAccessLogging
 	Tracing []*tpb.Tracing
+	// Annotations are the annotations of the Telemetries, ordered by precedence.
+	Annotations []telemetryAnnotations
 }
 
 // computedAccessLogging contains the various AccessLogging configurations in scope for a given proxy,
@@ -202,6 +299,11 @@ type TracingSpec struct {
 	RandomSamplingPercentage     float64
 	CustomTags                   map[string]*tpb.Tracing_CustomTag
 	UseRequestIDForTraceSampling bool
+	// LabelTags are the values of the custom tags sourced from the labels of the workload, by tag name.
+	LabelTags map[string]string
+	// TraceContexts are the trace context formats accepted and propagated by the workload, overriding the context of
+	// the provider.
+	TraceContexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext
 }
 
 type LoggingConfig struct {
@@ -352,6 +454,15 @@ func (t *Telemetries) Tracing(proxy *Proxy) *TracingConfig {
 		}
 	}
 
+	if labelTags := traceLabelTags(ct.Annotations, proxy.Labels); len(labelTags) > 0 {
+		clientSpec.LabelTags = labelTags
+		serverSpec.LabelTags = labelTags
+	}
+	if contexts := traceContexts(ct.Annotations); len(contexts) > 0 {
+		clientSpec.TraceContexts = contexts
+		serverSpec.TraceContexts = contexts
+	}
+
 	// If no provider is configured (and retrieved) for the tracing specs,
 	// then we will disable the configuration.
 	if clientSpec.Provider == nil {
@@ -389,12 +500,16 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 	if t == nil {
 		return computedTelemetries{}
 	}
+	return t.applicableTelemetriesForWorkload(proxy.ConfigNamespace, proxy.Labels)
+}
 
-	namespace := proxy.ConfigNamespace
+// applicableTelemetriesForWorkload computes the Telemetries in scope for a workload with the given namespace and labels.
+func (t *Telemetries) applicableTelemetriesForWorkload(namespace string, workloadLabels map[string]string) computedTelemetries {
 	// Order here matters. The latter elements will override the first elements
 	ms := []*tpb.Metrics{}
 	ls := []*computedAccessLogging{}
 	ts := []*tpb.Tracing{}
+	var annotations []telemetryAnnotations
 	key := telemetryKey{}
 	if t.RootNamespace != "" {
 		telemetry := t.namespaceWideTelemetryConfig(t.RootNamespace)
@@ -410,6 +525,7 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 				})
 			}
 			ts = append(ts, telemetry.Spec.GetTracing()...)
+			annotations = append(annotations, telemetry.telemetryAnnotations)
 		}
 	}
 
@@ -427,6 +543,7 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 				})
 			}
 			ts = append(ts, telemetry.Spec.GetTracing()...)
+			annotations = append(annotations, telemetry.telemetryAnnotations)
 		}
 	}
 
@@ -436,7 +553,7 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 			continue
 		}
 		selector := labels.Instance(spec.GetSelector().GetMatchLabels())
-		if selector.SubsetOf(proxy.Labels) {
+		if selector.SubsetOf(workloadLabels) {
 			key.Workload = NamespacedName{Name: telemetry.Name, Namespace: telemetry.Namespace}
 			ms = append(ms, spec.GetMetrics()...)
 			if len(telemetry.Spec.GetAccessLogging()) != 0 {
@@ -448,6 +565,7 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 				})
 			}
 			ts = append(ts, spec.GetTracing()...)
+			annotations = append(annotations, telemetry.telemetryAnnotations)
 			break
 		}
 	}
@@ -457,7 +575,135 @@ func (t *Telemetries) applicableTelemetries(proxy *Proxy) computedTelemetries {
 		Metrics:      ms,
 		Logging:      ls,
 		Tracing:      ts,
+		Annotations:  annotations,
+	}
+}
+
+// RouteSamplingPercentage returns the random sampling percentage of the traces of the Envoy route named routeName,
+// generated from the VirtualService, set by the Telemetries targeting the route. The Telemetries targeting the HTTP
+// route by name take precedence over the ones targeting all the routes.
+func (t *Telemetries) RouteSamplingPercentage(virtualService config.Meta, routeName string) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	var sampling *wrappers.DoubleValue
+	var bySection bool
+	for _, rt := range t.RouteTelemetries {
+		if rt.Namespace != virtualService.Namespace || !rt.targetsRoute(virtualService, routeName) {
+			continue
+		}
+		s := tracingSamplingPercentage(rt.Spec)
+		if s == nil || (sampling != nil && (bySection || rt.TargetRef.SectionName == "")) {
+			continue
+		}
+		sampling, bySection = s, rt.TargetRef.SectionName != ""
+	}
+	if sampling == nil {
+		return 0, false
+	}
+	return sampling.GetValue(), true
+}
+
+// TargetsRoutes returns whether the Telemetry targets routes, or targeted routes before it was updated, so that an
+// update of it impacts the routes.
+func (t *Telemetries) TargetsRoutes(key ConfigKey) bool {
+	if t == nil {
+		return false
+	}
+	return t.routeTelemetryKeys.Contains(key)
+}
+
+// retainRouteTelemetryKeys keeps the keys of the updated Telemetries which targeted routes in the previous push
+// context, so that removing a Telemetry or its target-ref annotation also rebuilds the routes.
+func (t *Telemetries) retainRouteTelemetryKeys(previous *Telemetries, updated sets.Set[ConfigKey]) {
+	if previous == nil {
+		return
+	}
+	for key := range updated {
+		if previous.routeTelemetryKeys.Contains(key) {
+			t.routeTelemetryKeys.Insert(key)
+		}
+	}
+}
+
+// RouteTelemetryKeys returns the keys of the Telemetries targeting the routes of the namespaces of the
+// VirtualServices, so the routes are rebuilt when they change.
+func (t *Telemetries) RouteTelemetryKeys(virtualServices []config.Config) []ConfigHash {
+	if t == nil || len(t.RouteTelemetries) == 0 {
+		return nil
+	}
+	namespaces := sets.New[string]()
+	for _, vs := range virtualServices {
+		namespaces.Insert(vs.Namespace)
+	}
+	var out []ConfigHash
+	for _, rt := range t.RouteTelemetries {
+		if namespaces.Contains(rt.Namespace) {
+			out = append(out, ConfigKey{Kind: kind.Telemetry, Name: rt.Name, Namespace: rt.Namespace}.HashCode())
+		}
+	}
+	return out
+}
+
+// targetsRoute returns whether the Telemetry targets the Envoy route named routeName, generated from the
+// VirtualService. The names of the Envoy routes are the names of their HTTP route, followed by the name of the match
+// if any.
+func (rt RouteTelemetry) targetsRoute(virtualService config.Meta, routeName string) bool {
+	generated := virtualService.Annotations[constants.InternalRouteSemantics] == constants.RouteSemanticsGateway
+	switch rt.TargetRef.Kind {
+	case telemetryconfig.VirtualServiceKind:
+		if generated || virtualService.Name != rt.TargetRef.Name {
+			return false
+		}
+		section := rt.TargetRef.SectionName
+		return section == "" || routeName == section || strings.HasPrefix(routeName, section+".")
+	case telemetryconfig.HTTPRouteKind:
+		return generated && IsHTTPRouteRuleName(routeName, rt.Namespace, rt.TargetRef.Name)
+	}
+	return false
+}
+
+// tracingSamplingPercentage returns the random sampling percentage of the tracing of the Telemetry, if set.
+func tracingSamplingPercentage(spec *tpb.Telemetry) *wrappers.DoubleValue {
+	var sampling *wrappers.DoubleValue
+	for _, tr := range spec.GetTracing() {
+		if tr.GetRandomSamplingPercentage() != nil {
+			sampling = tr.GetRandomSamplingPercentage()
+		}
 	}
+	return sampling
+}
+
+// HistogramBuckets returns the bucket boundaries of the histograms of the standard Istio metrics of the workload,
+// as the value of its statsHistogramBuckets annotation, or an empty string if no Telemetry overrides them.
+// The buckets of a metric set by a workload Telemetry take precedence over the namespace and root namespace ones.
+func (t *Telemetries) HistogramBuckets(namespace string, workloadLabels map[string]string) string {
+	if t == nil {
+		return ""
+	}
+	merged := telemetryconfig.HistogramBuckets{}
+	for _, a := range t.applicableTelemetriesForWorkload(namespace, workloadLabels).Annotations {
+		v := a.HistogramBuckets
+		if v == "" {
+			continue
+		}
+		buckets, err := telemetryconfig.ParseHistogramBuckets(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation: %v", constants.HistogramBucketsAnnotation, err)
+			continue
+		}
+		for metric, b := range buckets {
+			merged[metric] = b
+		}
+	}
+	if len(merged) == 0 {
+		return ""
+	}
+	out, err := json.Marshal(merged.StatBuckets())
+	if err != nil {
+		return ""
+	}
+	return string(out)
 }
 
 // telemetryFilters computes the filters for the given proxy/class and protocol. This computes the
@@ -486,6 +732,12 @@ func (t *Telemetries) telemetryFilters(proxy *Proxy, class networking.ListenerCl
 
 	// First, take all the metrics configs and transform them into a normalized form
 	tmm := mergeMetrics(c.Metrics, t.meshConfig)
+	if expiry := metricExpiry(c.Annotations); expiry != nil {
+		for k, v := range tmm {
+			v.MetricExpiry = expiry
+			tmm[k] = v
+		}
+	}
 	log.Debugf("merged metrics, proxyID: %s metrics: %+v", proxy.ID, tmm)
 	// Additionally, fetch relevant access logging configurations
 	tml := mergeLogs(c.Logging, t.meshConfig, workloadMode(class))
@@ -537,6 +789,135 @@ func (t *Telemetries) telemetryFilters(proxy *Proxy, class networking.ListenerCl
 	return res
 }
 
+// traceLabelTags returns the values of the tags of the trace-label-tags annotations of the Telemetries, ordered by
+// precedence, for the workload of the labels. Like the custom tags of the tracing, the most specific annotation
+// overrides the others.
+func traceLabelTags(annotations []telemetryAnnotations, labels map[string]string) map[string]string {
+	var tags telemetryconfig.TraceLabelTags
+	for _, a := range annotations {
+		v := a.TraceLabelTags
+		if v == "" {
+			continue
+		}
+		t, err := telemetryconfig.ParseTraceLabelTags(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation: %v", constants.TraceLabelTagsAnnotation, err)
+			continue
+		}
+		tags = t
+	}
+	return tags.Values(labels)
+}
+
+// traceContexts returns the trace context formats of the trace-context-propagation annotations of the Telemetries,
+// ordered by precedence, or nil if none of them is set and valid.
+func traceContexts(annotations []telemetryAnnotations) []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext {
+	var contexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext
+	for _, a := range annotations {
+		v := a.TraceContextPropagation
+		if v == "" {
+			continue
+		}
+		c, err := telemetryconfig.ParseTraceContexts(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation: %v", constants.TraceContextPropagationAnnotation, err)
+			continue
+		}
+		if len(c) > 0 {
+			contexts = c
+		}
+	}
+	return contexts
+}
+
+// metricExpiry returns the metric expiry of the metric-expiry annotations of the Telemetries, ordered by precedence,
+// or nil if none of them is set and valid.
+func metricExpiry(annotations []telemetryAnnotations) *durationpb.Duration {
+	var expiry *durationpb.Duration
+	for _, a := range annotations {
+		v := a.MetricExpiry
+		if v == "" {
+			continue
+		}
+		d, err := telemetryconfig.ParseMetricExpiry(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation: %v", constants.MetricExpiryAnnotation, err)
+			continue
+		}
+		expiry = durationpb.New(d)
+	}
+	return expiry
+}
+
+// applyAccessLogProviderFilters returns the Telemetry with the providers of its access logging that have a filter
+// split into their own access logging, combining their filter with the one of the access logging they are listed in.
+func applyAccessLogProviderFilters(spec *tpb.Telemetry, filters telemetryconfig.AccessLogProviderFilters) *tpb.Telemetry {
+	if len(filters) == 0 || len(spec.GetAccessLogging()) == 0 {
+		return spec
+	}
+	spec = proto.Clone(spec).(*tpb.Telemetry)
+	logging := make([]*tpb.AccessLogging, 0, len(spec.AccessLogging))
+	for _, l := range spec.AccessLogging {
+		var providers []*tpb.ProviderRef
+		var split []*tpb.AccessLogging
+		for _, p := range l.Providers {
+			f, ok := filters[p.GetName()]
+			if !ok {
+				providers = append(providers, p)
+				continue
+			}
+			pl := proto.Clone(l).(*tpb.AccessLogging)
+			pl.Providers = []*tpb.ProviderRef{p}
+			pl.Filter = nil
+			if expr := f.Combine(l.GetFilter().GetExpression()); expr != "" {
+				pl.Filter = &tpb.AccessLogging_Filter{Expression: expr}
+			}
+			split = append(split, pl)
+		}
+		if len(providers) > 0 || len(split) == 0 {
+			l.Providers = providers
+			logging = append(logging, l)
+		}
+		logging = append(logging, split...)
+	}
+	spec.AccessLogging = logging
+	return spec
+}
+
+// httpMetrics are the standard Istio metrics of HTTP requests, which the route and operation dimensions are added to.
+var httpMetrics = []tpb.MetricSelector_IstioMetric{
+	tpb.MetricSelector_REQUEST_COUNT,
+	tpb.MetricSelector_REQUEST_DURATION,
+	tpb.MetricSelector_REQUEST_SIZE,
+	tpb.MetricSelector_RESPONSE_SIZE,
+}
+
+// applyHTTPMetricDimensions returns the Telemetry with the dimensions, by name to their CEL expression, added to the
+// HTTP request metrics of the providers of its last metrics, or of the providers it inherits if it has none.
+func applyHTTPMetricDimensions(spec *tpb.Telemetry, dims map[string]string) *tpb.Telemetry {
+	if len(dims) == 0 {
+		return spec
+	}
+	spec = proto.Clone(spec).(*tpb.Telemetry)
+	tags := make(map[string]*tpb.MetricsOverrides_TagOverride, len(dims))
+	for name, expr := range dims {
+		tags[name] = &tpb.MetricsOverrides_TagOverride{
+			Operation: tpb.MetricsOverrides_TagOverride_UPSERT,
+			Value:     expr,
+		}
+	}
+	overrides := make([]*tpb.MetricsOverrides, 0, len(httpMetrics))
+	for _, metric := range httpMetrics {
+		overrides = append(overrides, &tpb.MetricsOverrides{
+			Match:        &tpb.MetricSelector{MetricMatch: &tpb.MetricSelector_Metric{Metric: metric}},
+			TagOverrides: tags,
+		})
+	}
+	// Metrics without providers apply to the providers of the previous metrics, or the inherited ones.
+	spec.Metrics = append(spec.Metrics, &tpb.Metrics{Overrides: overrides})
+	return spec
+}
+
 // mergeLogs returns the set of providers for the given logging configuration.
 // The provider names are mapped to any applicable access logging filter that has been applied in provider configuration.
 func mergeLogs(logs []*computedAccessLogging, mesh *meshconfig.MeshConfig, mode tpb.WorkloadMode) map[string]loggingSpec {
@@ -1061,6 +1442,9 @@ func generateSDConfig(class networking.ListenerClass, telemetryConfig telemetryF
 	}
 
 	cfg.MetricExpiryDuration = durationpb.New(1 * time.Hour)
+	if telemetryConfig.MetricExpiry != nil {
+		cfg.MetricExpiryDuration = telemetryConfig.MetricExpiry
+	}
 	// In WASM we are not actually processing protobuf at all, so we need to encode this to JSON
 	cfgJSON, _ := protomarshal.MarshalProtoNames(&cfg)
 
@@ -1114,9 +1498,14 @@ func generateStatsConfig(class networking.ListenerClass, filterConfig telemetryF
 			Name:       metricName,
 			Drop:       override.Disabled,
 		}
+		tcp := telemetryconfig.IsTCPMetric(override.Name)
 		for _, t := range override.Tags {
 			if t.Remove {
 				mc.TagsToRemove = append(mc.TagsToRemove, t.Name)
+			} else if tcp && telemetryconfig.UsesHTTPAttributes(t.Value) {
+				// The dimensions set for all the metrics may use the request attributes, which would fail to
+				// evaluate for the TCP metrics.
+				log.Debugf("skipping dimension %s of metric %s, which uses request or response attributes", t.Name, metricName)
 			} else {
 				mc.Dimensions[t.Name] = t.Value
 			}
@@ -1124,9 +1513,30 @@ func generateStatsConfig(class networking.ListenerClass, filterConfig telemetryF
 		cfg.Metrics = append(cfg.Metrics, mc)
 	}
 
+	if features.EnableDryRunAuthzMetrics && class != networking.ListenerClassSidecarOutbound {
+		cfg.Metrics = append(cfg.Metrics, dryRunAuthzMetricConfig)
+	}
+
 	return protoconv.MessageToAny(&cfg)
 }
 
+// dryRunAuthzMetricConfig adds the shadow decisions of the dry-run AuthorizationPolicies, written by the
+// RBAC filter to the dynamic metadata, as dimensions of the request count. The dimensions are "unknown" if
+// there is no dry-run policy of the action for the workload.
+var dryRunAuthzMetricConfig = &stats.MetricConfig{
+	Name: "requests_total",
+	Dimensions: map[string]string{
+		"dry_run_allow_policy": dryRunAuthzMetadata("istio_dry_run_allow_shadow_effective_policy_id"),
+		"dry_run_allow_result": dryRunAuthzMetadata("istio_dry_run_allow_shadow_engine_result"),
+		"dry_run_deny_policy":  dryRunAuthzMetadata("istio_dry_run_deny_shadow_effective_policy_id"),
+		"dry_run_deny_result":  dryRunAuthzMetadata("istio_dry_run_deny_shadow_engine_result"),
+	},
+}
+
+func dryRunAuthzMetadata(key string) string {
+	return fmt.Sprintf("metadata.filter_metadata['%s']['%s']", wellknown.HTTPRoleBasedAccessControl, key)
+}
+
 func disableHostHeaderFallback(class networking.ListenerClass) bool {
 	return class == networking.ListenerClassSidecarInbound || class == networking.ListenerClassGateway
 }
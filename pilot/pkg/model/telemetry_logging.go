@@ -15,6 +15,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -32,9 +33,12 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 
 	meshconfig "istio.io/api/mesh/v1alpha1"
+	tpb "istio.io/api/telemetry/v1alpha1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/util/protomarshal"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 const (
@@ -136,7 +140,7 @@ func telemetryAccessLog(push *PushContext, fp *meshconfig.MeshConfig_ExtensionPr
 	case *meshconfig.MeshConfig_ExtensionProvider_EnvoyTcpAls:
 		al = tcpGrpcAccessLogFromTelemetry(push, prov.EnvoyTcpAls)
 	case *meshconfig.MeshConfig_ExtensionProvider_EnvoyOtelAls:
-		al = openTelemetryLog(push, prov.EnvoyOtelAls)
+		al = openTelemetryLog(push, prov.EnvoyOtelAls, otelAccessLogSettings[fp.Name])
 	}
 
 	return al
@@ -402,8 +406,94 @@ func FileAccessLogFromMeshConfig(path string, mesh *meshconfig.MeshConfig) *acce
 	return al
 }
 
+// otelAccessLogProviderSettings are the settings of an envoyOtelAls extension provider which are not part of the
+// mesh config.
+type otelAccessLogProviderSettings struct {
+	Provider string `json:"provider"`
+	// ResourceAttributes are the OpenTelemetry resource attributes of the logs, e.g. service.name.
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+	// Body is the structured body of the logs, replacing the text format of the provider.
+	Body map[string]string `json:"body,omitempty"`
+	// Headers are the headers sent to the collector, e.g. for authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+	// AttributeMapping is the preset mapping of the attributes of the logs: "istio", the fields of the default JSON
+	// access log format, or "otel", the attributes named by the OpenTelemetry semantic conventions. The labels of
+	// the log format of the provider take precedence over the attributes of the mapping.
+	AttributeMapping string `json:"attributeMapping,omitempty"`
+}
+
+// otelAttributeMappings are the preset mappings of the attributes of the OpenTelemetry access logs, by name.
+var otelAttributeMappings = map[string]map[string]string{
+	"istio": structStringValues(EnvoyJSONLogFormatIstio),
+	"otel": {
+		"http.request.method":       "%REQ(:METHOD)%",
+		"url.path":                  "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
+		"http.response.status_code": "%RESPONSE_CODE%",
+		"http.request.body.size":    "%BYTES_RECEIVED%",
+		"http.response.body.size":   "%BYTES_SENT%",
+		"server.address":            "%REQ(:AUTHORITY)%",
+		"client.address":            "%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%",
+		"user_agent.original":       "%REQ(USER-AGENT)%",
+		"envoy.protocol":            "%PROTOCOL%",
+		"envoy.duration":            "%DURATION%",
+		"envoy.response_flags":      "%RESPONSE_FLAGS%",
+		"envoy.route_name":          "%ROUTE_NAME%",
+		"envoy.request_id":          "%REQ(X-REQUEST-ID)%",
+		"envoy.upstream.host":       "%UPSTREAM_HOST%",
+		"envoy.upstream.cluster":    "%UPSTREAM_CLUSTER%",
+	},
+}
+
+func structStringValues(s *structpb.Struct) map[string]string {
+	out := make(map[string]string, len(s.GetFields()))
+	for k, v := range s.GetFields() {
+		out[k] = v.GetStringValue()
+	}
+	return out
+}
+
+// otelAccessLogSettings are the settings of the envoyOtelAls extension providers, keyed by provider name.
+// This package is also loaded by istioctl, which must not fail on a setting of istiod: an invalid value is only logged
+// here, NewServer refuses to start istiod with it.
+var otelAccessLogSettings = func() map[string]*otelAccessLogProviderSettings {
+	settings, err := parseOtelAccessLogProviderSettings(features.OtelAccessLogProviderSettings)
+	if err != nil {
+		log.Errorf("Ignoring invalid PILOT_OTEL_ACCESS_LOG_PROVIDER_SETTINGS: %v", err)
+	}
+	return settings
+}()
+
+// ValidateOtelAccessLogProviderSettings returns an error if the PILOT_OTEL_ACCESS_LOG_PROVIDER_SETTINGS setting is
+// invalid.
+func ValidateOtelAccessLogProviderSettings(value string) error {
+	_, err := parseOtelAccessLogProviderSettings(value)
+	return err
+}
+
+// parseOtelAccessLogProviderSettings parses the PILOT_OTEL_ACCESS_LOG_PROVIDER_SETTINGS setting, by provider name.
+func parseOtelAccessLogProviderSettings(value string) (map[string]*otelAccessLogProviderSettings, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var settings []*otelAccessLogProviderSettings
+	d := json.NewDecoder(strings.NewReader(value))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&settings); err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*otelAccessLogProviderSettings, len(settings))
+	for _, s := range settings {
+		if _, f := otelAttributeMappings[s.AttributeMapping]; s.AttributeMapping != "" && !f {
+			return nil, fmt.Errorf("invalid attribute mapping %q of provider %s, must be istio or otel", s.AttributeMapping, s.Provider)
+		}
+		ret[s.Provider] = s
+	}
+	return ret, nil
+}
+
 func openTelemetryLog(pushCtx *PushContext,
 	provider *meshconfig.MeshConfig_ExtensionProvider_EnvoyOpenTelemetryLogProvider,
+	settings *otelAccessLogProviderSettings,
 ) *accesslog.AccessLog {
 	hostname, cluster, err := clusterLookupFn(pushCtx, provider.Service, int(provider.Port))
 	if err != nil {
@@ -427,6 +517,7 @@ func openTelemetryLog(pushCtx *PushContext,
 	}
 
 	cfg := buildOpenTelemetryAccessLogConfig(logName, hostname, cluster, f, labels)
+	applyOtelAccessLogProviderSettings(cfg, settings)
 
 	return &accesslog.AccessLog{
 		Name:       OtelEnvoyALSName,
@@ -468,6 +559,70 @@ func buildOpenTelemetryAccessLogConfig(logName, hostname, clusterName, format st
 	return cfg
 }
 
+// applyOtelAccessLogProviderSettings applies the settings not part of the mesh config to the OpenTelemetry access log.
+func applyOtelAccessLogProviderSettings(cfg *otelaccesslog.OpenTelemetryAccessLogConfig, settings *otelAccessLogProviderSettings) {
+	if settings == nil {
+		return
+	}
+	if len(settings.ResourceAttributes) > 0 {
+		cfg.ResourceAttributes = &otlpcommon.KeyValueList{Values: stringAttributeKeyValues(settings.ResourceAttributes)}
+	}
+	if len(settings.Body) > 0 {
+		cfg.Body = &otlpcommon.AnyValue{
+			Value: &otlpcommon.AnyValue_KvlistValue{
+				KvlistValue: &otlpcommon.KeyValueList{Values: stringAttributeKeyValues(settings.Body)},
+			},
+		}
+	}
+	if mapping := otelAttributeMappings[settings.AttributeMapping]; mapping != nil {
+		attrs := stringAttributeKeyValues(mapping)
+		if cfg.Attributes != nil {
+			attrs = mergeAttributeKeyValues(attrs, cfg.Attributes.Values)
+		}
+		cfg.Attributes = &otlpcommon.KeyValueList{Values: attrs}
+	}
+	if len(settings.Headers) > 0 {
+		keys := maps.Keys(settings.Headers)
+		sort.Strings(keys)
+		for _, k := range keys {
+			cfg.CommonConfig.GrpcService.InitialMetadata = append(cfg.CommonConfig.GrpcService.InitialMetadata,
+				&core.HeaderValue{Key: strings.ToLower(k), Value: settings.Headers[k]})
+		}
+	}
+}
+
+func stringAttributeKeyValues(values map[string]string) []*otlpcommon.KeyValue {
+	keys := maps.Keys(values)
+	sort.Strings(keys)
+	attrList := make([]*otlpcommon.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		attrList = append(attrList, &otlpcommon.KeyValue{
+			Key:   key,
+			Value: &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: values[key]}},
+		})
+	}
+	return attrList
+}
+
+// mergeAttributeKeyValues returns the attributes sorted by key, with the overrides replacing the attributes of the
+// same key.
+func mergeAttributeKeyValues(attrs, overrides []*otlpcommon.KeyValue) []*otlpcommon.KeyValue {
+	byKey := make(map[string]*otlpcommon.KeyValue, len(attrs)+len(overrides))
+	for _, kv := range attrs {
+		byKey[kv.Key] = kv
+	}
+	for _, kv := range overrides {
+		byKey[kv.Key] = kv
+	}
+	keys := maps.Keys(byKey)
+	sort.Strings(keys)
+	out := make([]*otlpcommon.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, byKey[k])
+	}
+	return out
+}
+
 func ConvertStructToAttributeKeyValues(labels map[string]*structpb.Value) []*otlpcommon.KeyValue {
 	if len(labels) == 0 {
 		return nil
@@ -477,16 +632,33 @@ func ConvertStructToAttributeKeyValues(labels map[string]*structpb.Value) []*otl
 	keys := maps.Keys(labels)
 	sort.Strings(keys)
 	for _, key := range keys {
-		value := labels[key]
 		kv := &otlpcommon.KeyValue{
 			Key:   key,
-			Value: &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: value.GetStringValue()}},
+			Value: convertStructValueToAnyValue(labels[key]),
 		}
 		attrList = append(attrList, kv)
 	}
 	return attrList
 }
 
+// convertStructValueToAnyValue converts the value of a label to an attribute value, keeping the nested structs and
+// lists so the attributes of the logs can be structured.
+func convertStructValueToAnyValue(value *structpb.Value) *otlpcommon.AnyValue {
+	switch v := value.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		return &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_KvlistValue{
+			KvlistValue: &otlpcommon.KeyValueList{Values: ConvertStructToAttributeKeyValues(v.StructValue.GetFields())},
+		}}
+	case *structpb.Value_ListValue:
+		values := make([]*otlpcommon.AnyValue, 0, len(v.ListValue.GetValues()))
+		for _, lv := range v.ListValue.GetValues() {
+			values = append(values, convertStructValueToAnyValue(lv))
+		}
+		return &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_ArrayValue{ArrayValue: &otlpcommon.ArrayValue{Values: values}}}
+	}
+	return &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: value.GetStringValue()}}
+}
+
 // FIXME: this is a copy of extensionproviders.LookupCluster to avoid import cycle
 func LookupCluster(push *PushContext, service string, port int) (hostname string, cluster string, err error) {
 	if service == "" {
@@ -524,3 +696,106 @@ func LookupCluster(push *PushContext, service string, port int) (hostname string
 	err = fmt.Errorf("could not find service %s in Istio service registry", service)
 	return
 }
+
+// ZtunnelAccessLogging returns the access logging configuration for a workload captured by ztunnel.
+// ztunnel only logs to its own output, so only file access log providers are considered, and their path and any
+// filters are ignored. If nil is returned, access logs are not configured via Telemetry and ztunnel should use its
+// own configuration.
+func (t *Telemetries) ZtunnelAccessLogging(namespace string, workloadLabels map[string]string) *workloadapi.AccessLogging {
+	if t == nil {
+		return nil
+	}
+	ct := t.applicableTelemetriesForWorkload(namespace, workloadLabels)
+	if len(ct.Logging) == 0 && len(t.meshConfig.GetDefaultProviders().GetAccessLogging()) == 0 {
+		// No Telemetry API configured, fall back to ztunnel configuration
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if precomputed, ok := t.computedZtunnelLogging[ct.telemetryKey]; ok {
+		return precomputed
+	}
+
+	server := t.ztunnelLogProvider(ct.Logging, tpb.WorkloadMode_SERVER)
+	client := t.ztunnelLogProvider(ct.Logging, tpb.WorkloadMode_CLIENT)
+	cfg := &workloadapi.AccessLogging{
+		Server: server != nil,
+		Client: client != nil,
+	}
+	// ztunnel has a single log format, so we prefer the format of the server side provider.
+	fp := server
+	if fp == nil {
+		fp = client
+	}
+	if fp != nil {
+		cfg.TextFormat, cfg.Labels = t.ztunnelLogFormat(fp)
+	}
+
+	if t.computedZtunnelLogging == nil {
+		t.computedZtunnelLogging = map[telemetryKey]*workloadapi.AccessLogging{}
+	}
+	t.computedZtunnelLogging[ct.telemetryKey] = cfg
+	return cfg
+}
+
+// ztunnelLogProvider returns the enabled file access log provider, if any, for the given mode.
+// If multiple are enabled, the first by name is used.
+func (t *Telemetries) ztunnelLogProvider(logs []*computedAccessLogging, mode tpb.WorkloadMode) *meshconfig.MeshConfig_ExtensionProvider {
+	providers := mergeLogs(logs, t.meshConfig, mode)
+	names := maps.Keys(providers)
+	sort.Strings(names)
+	for _, name := range names {
+		if providers[name].Disabled {
+			continue
+		}
+		fp := t.fetchProvider(name)
+		if fp.GetEnvoyFileAccessLog() == nil {
+			log.Debugf("access log provider %s is not supported by ztunnel", name)
+			continue
+		}
+		return fp
+	}
+	return nil
+}
+
+// ztunnelLogFormat converts the format of a file access log provider into the text format or JSON labels used by ztunnel.
+// Only string valued labels are supported.
+func (t *Telemetries) ztunnelLogFormat(fp *meshconfig.MeshConfig_ExtensionProvider) (string, map[string]string) {
+	format := fp.GetEnvoyFileAccessLog().GetLogFormat()
+	if fp.Name == builtinEnvoyAccessLogProvider && format == nil {
+		// As with sidecars, the built-in provider falls back to MeshConfig for formatting options.
+		if t.meshConfig.GetAccessLogEncoding() == meshconfig.MeshConfig_TEXT {
+			return t.meshConfig.GetAccessLogFormat(), nil
+		}
+		if t.meshConfig.GetAccessLogFormat() == "" {
+			return "", nil
+		}
+		parsed := &structpb.Struct{}
+		if err := protomarshal.UnmarshalAllowUnknown([]byte(t.meshConfig.GetAccessLogFormat()), parsed); err != nil {
+			log.Errorf("error parsing provided json log format, default log format will be used: %v", err)
+			return "", nil
+		}
+		return "", stringLabels(parsed)
+	}
+	switch f := format.GetLogFormat().(type) {
+	case *meshconfig.MeshConfig_ExtensionProvider_EnvoyFileAccessLogProvider_LogFormat_Text:
+		return f.Text, nil
+	case *meshconfig.MeshConfig_ExtensionProvider_EnvoyFileAccessLogProvider_LogFormat_Labels:
+		return "", stringLabels(f.Labels)
+	}
+	return "", nil
+}
+
+func stringLabels(s *structpb.Struct) map[string]string {
+	if len(s.GetFields()) == 0 {
+		return nil
+	}
+	res := make(map[string]string, len(s.GetFields()))
+	for k, v := range s.GetFields() {
+		if sv, ok := v.GetKind().(*structpb.Value_StringValue); ok {
+			res[k] = sv.StringValue
+		}
+	}
+	return res
+}
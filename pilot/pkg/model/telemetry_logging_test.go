@@ -36,9 +36,12 @@ import (
 	"istio.io/istio/pilot/pkg/serviceregistry/provider"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/util/protomarshal"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 func TestFileAccessLogFormat(t *testing.T) {
@@ -895,6 +898,37 @@ func TestAccessLoggingWithFilter(t *testing.T) {
 	}
 }
 
+func TestAccessLoggingWithProviderFilters(t *testing.T) {
+	sidecar := &Proxy{
+		ConfigNamespace: "default",
+		Labels:          map[string]string{"app": "test"},
+		Metadata:        &NodeMetadata{},
+	}
+	telemetry := newTelemetry("default", &tpb.Telemetry{
+		AccessLogging: []*tpb.AccessLogging{
+			{
+				Providers: []*tpb.ProviderRef{{Name: "envoy"}, {Name: "envoy-json"}},
+				Filter: &tpb.AccessLogging_Filter{
+					Expression: "request.path != '/health'",
+				},
+			},
+		},
+	})
+	telemetry.Annotations = map[string]string{
+		constants.AccessLogProviderFiltersAnnotation: `{"envoy": {"minLevel": "ERROR"}}`,
+	}
+
+	telemetries, ctx := createTestTelemetries([]config.Config{telemetry}, t)
+	got := map[string]string{}
+	for _, cfg := range telemetries.AccessLogging(ctx, sidecar, networking.ListenerClassSidecarOutbound) {
+		got[cfg.Provider.Name] = cfg.Filter.GetExpression()
+	}
+	assert.Equal(t, got, map[string]string{
+		"envoy":      "(request.path != '/health') && (response.code >= 500)",
+		"envoy-json": "request.path != '/health'",
+	})
+}
+
 func TestAccessLoggingCache(t *testing.T) {
 	sidecar := &Proxy{ConfigNamespace: "default", Metadata: &NodeMetadata{Labels: map[string]string{"app": "test"}}}
 	otherNamespace := &Proxy{ConfigNamespace: "common", Metadata: &NodeMetadata{Labels: map[string]string{"app": "test"}}}
@@ -923,6 +957,110 @@ func TestAccessLoggingCache(t *testing.T) {
 	}
 }
 
+func TestZtunnelAccessLogging(t *testing.T) {
+	labels := map[string]string{"app": "test"}
+	envoy := &tpb.Telemetry{
+		AccessLogging: []*tpb.AccessLogging{{
+			Providers: []*tpb.ProviderRef{{Name: "envoy"}},
+		}},
+	}
+	disabled := &tpb.Telemetry{
+		AccessLogging: []*tpb.AccessLogging{{
+			Disabled: &wrappers.BoolValue{Value: true},
+		}},
+	}
+	server := &tpb.Telemetry{
+		AccessLogging: []*tpb.AccessLogging{{
+			Match:     &tpb.AccessLogging_LogSelector{Mode: tpb.WorkloadMode_SERVER},
+			Providers: []*tpb.ProviderRef{{Name: "envoy-text-formatters"}},
+		}},
+	}
+	workloadJSON := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.Telemetry,
+			Name:             "workload",
+			Namespace:        "default",
+		},
+		Spec: &tpb.Telemetry{
+			Selector: &v1beta1.WorkloadSelector{MatchLabels: labels},
+			AccessLogging: []*tpb.AccessLogging{{
+				Providers: []*tpb.ProviderRef{{Name: "envoy-json-formatters"}},
+			}},
+		},
+	}
+	stackdriver := &tpb.Telemetry{
+		AccessLogging: []*tpb.AccessLogging{{
+			Providers: []*tpb.ProviderRef{{Name: "stackdriver"}},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		cfgs     []config.Config
+		labels   map[string]string
+		expected *workloadapi.AccessLogging
+	}{
+		{
+			name:     "empty",
+			expected: nil,
+		},
+		{
+			name:     "root namespace",
+			cfgs:     []config.Config{newTelemetry("istio-system", envoy)},
+			expected: &workloadapi.AccessLogging{Server: true, Client: true},
+		},
+		{
+			name:     "disabled in namespace",
+			cfgs:     []config.Config{newTelemetry("istio-system", envoy), newTelemetry("default", disabled)},
+			expected: &workloadapi.AccessLogging{},
+		},
+		{
+			name: "server only",
+			cfgs: []config.Config{newTelemetry("default", server)},
+			expected: &workloadapi.AccessLogging{
+				Server:     true,
+				TextFormat: "%REQ_WITHOUT_QUERY(key1:val1)% REQ_WITHOUT_QUERY(key2:val1)% %METADATA(UPSTREAM_HOST:istio)% %METADATA(CLUSTER:istio)%\n",
+			},
+		},
+		{
+			name:   "workload labels",
+			cfgs:   []config.Config{newTelemetry("default", disabled), workloadJSON},
+			labels: labels,
+			expected: &workloadapi.AccessLogging{
+				Server: true,
+				Client: true,
+				Labels: map[string]string{
+					"req1": "%REQ_WITHOUT_QUERY(key1:val1)%",
+					"req2": "%REQ_WITHOUT_QUERY(key2:val1)%",
+					"key1": "%METADATA(CLUSTER:istio)%",
+					"key2": "%METADATA(UPSTREAM_HOST:istio)%",
+				},
+			},
+		},
+		{
+			name:     "workload not selected",
+			cfgs:     []config.Config{newTelemetry("default", disabled), workloadJSON},
+			labels:   map[string]string{"app": "other"},
+			expected: &workloadapi.AccessLogging{},
+		},
+		{
+			name:     "unsupported provider",
+			cfgs:     []config.Config{newTelemetry("default", stackdriver)},
+			expected: &workloadapi.AccessLogging{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry, _ := createTestTelemetries(tt.cfgs, t)
+			telemetry.RootNamespace = "istio-system"
+			got := telemetry.ZtunnelAccessLogging("default", tt.labels)
+			assert.Equal(t, got, tt.expected)
+			// The result is cached
+			assert.Equal(t, telemetry.ZtunnelAccessLogging("default", tt.labels), got)
+		})
+	}
+}
+
 func TestBuildOpenTelemetryAccessLogConfig(t *testing.T) {
 	fakeCluster := "outbound|55680||otel-collector.monitoring.svc.cluster.local"
 	fakeAuthority := "otel-collector.monitoring.svc.cluster.local"
@@ -1010,6 +1148,65 @@ func TestBuildOpenTelemetryAccessLogConfig(t *testing.T) {
 	}
 }
 
+func TestApplyOtelAccessLogProviderSettings(t *testing.T) {
+	settings, err := parseOtelAccessLogProviderSettings(`[{"provider": "otel",
+		"resourceAttributes": {"service.namespace": "shop", "deployment.environment": "prod"},
+		"body": {"method": "%REQ(:METHOD)%", "code": "%RESPONSE_CODE%"},
+		"headers": {"X-API-Key": "secret"}}]`)
+	assert.NoError(t, err)
+	cfg := buildOpenTelemetryAccessLogConfig("otel", "collector", "outbound|4317||collector", EnvoyTextLogFormat, nil)
+	applyOtelAccessLogProviderSettings(cfg, settings["otel"])
+
+	stringValue := func(v string) *otlpcommon.AnyValue {
+		return &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: v}}
+	}
+	assert.Equal(t, cfg.ResourceAttributes, &otlpcommon.KeyValueList{Values: []*otlpcommon.KeyValue{
+		{Key: "deployment.environment", Value: stringValue("prod")},
+		{Key: "service.namespace", Value: stringValue("shop")},
+	}})
+	assert.Equal(t, cfg.Body, &otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_KvlistValue{
+		KvlistValue: &otlpcommon.KeyValueList{Values: []*otlpcommon.KeyValue{
+			{Key: "code", Value: stringValue("%RESPONSE_CODE%")},
+			{Key: "method", Value: stringValue("%REQ(:METHOD)%")},
+		}},
+	}})
+	assert.Equal(t, cfg.CommonConfig.GrpcService.InitialMetadata, []*core.HeaderValue{{Key: "x-api-key", Value: "secret"}})
+
+	for _, invalid := range []string{
+		"invalid",
+		`[{"provider": "otel", "header": {"X-API-Key": "secret"}}]`,
+		`[{"provider": "otel", "attributeMapping": "unknown"}]`,
+	} {
+		if _, err := parseOtelAccessLogProviderSettings(invalid); err == nil {
+			t.Fatalf("expected an error for the settings %s", invalid)
+		}
+	}
+}
+
+func TestOtelAccessLogAttributeMapping(t *testing.T) {
+	settings, err := parseOtelAccessLogProviderSettings(`[{"provider": "otel", "attributeMapping": "otel"}]`)
+	assert.NoError(t, err)
+
+	labels := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"url.path": structpb.NewStringValue("%REQ(:PATH)%"),
+		"peer": structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+			"namespace": structpb.NewStringValue("%FILTER_STATE(wasm.downstream_peer)%"),
+		}}),
+	}}
+	cfg := buildOpenTelemetryAccessLogConfig("otel", "collector", "outbound|4317||collector", EnvoyTextLogFormat, labels)
+	applyOtelAccessLogProviderSettings(cfg, settings["otel"])
+
+	attrs := map[string]*otlpcommon.AnyValue{}
+	for _, kv := range cfg.Attributes.Values {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, len(attrs), len(otelAttributeMappings["otel"])+1)
+	assert.Equal(t, attrs["http.request.method"].GetStringValue(), "%REQ(:METHOD)%")
+	// The labels of the provider take precedence over the mapping
+	assert.Equal(t, attrs["url.path"].GetStringValue(), "%REQ(:PATH)%")
+	assert.Equal(t, attrs["peer"].GetKvlistValue().GetValues()[0].GetKey(), "namespace")
+}
+
 func TestTelemetryAccessLog(t *testing.T) {
 	stdoutFormat := &meshconfig.MeshConfig_ExtensionProvider{
 		Name: "stdout",
@@ -30,16 +30,23 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
 
+	sd "istio.io/api/envoy/extensions/stackdriver/config/v1alpha1"
 	"istio.io/api/envoy/extensions/stats"
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	tpb "istio.io/api/telemetry/v1alpha1"
+	"istio.io/api/type/v1beta1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/networking"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/schema/collection"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/util/protomarshal"
+	"istio.io/istio/pkg/util/sets"
 )
 
 var (
@@ -978,3 +985,343 @@ func TestTelemetryFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRunAuthzMetrics(t *testing.T) {
+	test.SetForTest(t, &features.EnableDryRunAuthzMetrics, true)
+	dryRunDimensions := func(class networking.ListenerClass) map[string]string {
+		t.Helper()
+		cfg := &stats.PluginConfig{}
+		if err := generateStatsConfig(class, telemetryFilterConfig{Metrics: true}).UnmarshalTo(cfg); err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range cfg.Metrics {
+			if m.Name == "requests_total" {
+				return m.Dimensions
+			}
+		}
+		return nil
+	}
+
+	inbound := dryRunDimensions(networking.ListenerClassSidecarInbound)
+	assert.Equal(t, inbound["dry_run_allow_policy"],
+		"metadata.filter_metadata['envoy.filters.http.rbac']['istio_dry_run_allow_shadow_effective_policy_id']")
+	assert.Equal(t, len(inbound), 4)
+	assert.Equal(t, len(dryRunDimensions(networking.ListenerClassGateway)), 4)
+	// Authorization policies do not apply to outbound traffic
+	assert.Equal(t, len(dryRunDimensions(networking.ListenerClassSidecarOutbound)), 0)
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{})
+	root.Annotations = map[string]string{
+		constants.HistogramBucketsAnnotation: `{"REQUEST_DURATION": [1, 10, 100], "RESPONSE_SIZE": [100, 1000]}`,
+	}
+	workload := newTelemetry("default", &tpb.Telemetry{
+		Selector: &v1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "fast"}},
+	})
+	workload.Name = "fast"
+	workload.Annotations = map[string]string{
+		constants.HistogramBucketsAnnotation: `{"REQUEST_DURATION": [0.1, 0.5, 1]}`,
+	}
+	invalid := newTelemetry("invalid", &tpb.Telemetry{})
+	invalid.Annotations = map[string]string{
+		constants.HistogramBucketsAnnotation: `{"REQUEST_DURATION": [10, 1]}`,
+	}
+	telemetries, _ := createTestTelemetries([]config.Config{root, workload, invalid}, t)
+
+	cases := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		want      string
+	}{
+		{
+			"root", "default", map[string]string{"app": "slow"},
+			`{"istiocustom.istio_request_duration_milliseconds":[1,10,100],"istiocustom.istio_response_bytes":[100,1000]}`,
+		},
+		{
+			"workload", "default", map[string]string{"app": "fast"},
+			`{"istiocustom.istio_request_duration_milliseconds":[0.1,0.5,1],"istiocustom.istio_response_bytes":[100,1000]}`,
+		},
+		{
+			"invalid ignored", "invalid", nil,
+			`{"istiocustom.istio_request_duration_milliseconds":[1,10,100],"istiocustom.istio_response_bytes":[100,1000]}`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, telemetries.HistogramBuckets(tt.namespace, tt.labels), tt.want)
+		})
+	}
+	assert.Equal(t, (*Telemetries)(nil).HistogramBuckets("default", nil), "")
+}
+
+func TestRouteMetricDimensions(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Metrics: []*tpb.Metrics{{Providers: []*tpb.ProviderRef{{Name: "prometheus"}}}},
+	})
+	workload := newTelemetry("default", &tpb.Telemetry{
+		Selector: &v1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "test"}},
+	})
+	workload.Annotations = map[string]string{
+		constants.RouteMetricDimensionsAnnotation: "route_name,virtual_service",
+	}
+	telemetries, _ := createTestTelemetries([]config.Config{root, workload}, t)
+
+	dimensions := func(labels map[string]string) map[string]map[string]string {
+		t.Helper()
+		proxy := &Proxy{
+			ConfigNamespace: "default",
+			Labels:          labels,
+			Metadata:        &NodeMetadata{Labels: labels},
+		}
+		got := map[string]map[string]string{}
+		for _, f := range telemetries.telemetryFilters(proxy, networking.ListenerClassSidecarOutbound, networking.ListenerProtocolHTTP).([]*hcm.HttpFilter) {
+			cfg := &stats.PluginConfig{}
+			if err := f.GetTypedConfig().UnmarshalTo(cfg); err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range cfg.Metrics {
+				got[m.Name] = m.Dimensions
+			}
+		}
+		return got
+	}
+
+	want := map[string]string{
+		"route_name":      "xds.route_name",
+		"virtual_service": "xds.route_metadata.filter_metadata['istio']['config']",
+	}
+	got := dimensions(map[string]string{"app": "test"})
+	for _, metric := range []string{"requests_total", "request_duration_milliseconds", "request_bytes", "response_bytes"} {
+		assert.Equal(t, got[metric], want)
+	}
+	assert.Equal(t, len(got), 4)
+	assert.Equal(t, len(dimensions(map[string]string{"app": "other"})), 0)
+}
+
+func TestRouteSamplingPercentage(t *testing.T) {
+	routeTelemetry := func(name, ref string, sampling float64) config.Config {
+		c := newTelemetry("default", &tpb.Telemetry{
+			Tracing: []*tpb.Tracing{{RandomSamplingPercentage: &wrappers.DoubleValue{Value: sampling}}},
+		})
+		c.Name = name
+		c.Annotations = map[string]string{constants.TelemetryTargetRefAnnotation: ref}
+		return c
+	}
+	telemetries, _ := createTestTelemetries([]config.Config{
+		routeTelemetry("reviews", `{"kind": "VirtualService", "name": "reviews"}`, 10),
+		routeTelemetry("reviews-errors", `{"kind": "VirtualService", "name": "reviews", "sectionName": "errors"}`, 100),
+		routeTelemetry("ratings", `{"kind": "HTTPRoute", "name": "ratings"}`, 50),
+		routeTelemetry("invalid", `{"kind": "Service", "name": "reviews"}`, 1),
+	}, t)
+	// Route Telemetries do not apply to workloads
+	assert.Equal(t, len(telemetries.NamespaceToTelemetries["default"]), 0)
+	assert.Equal(t, len(telemetries.RouteTelemetries), 3)
+
+	reviews := config.Meta{Name: "reviews", Namespace: "default"}
+	generated := config.Meta{
+		Name:        "ratings-0-istio-autogenerated-k8s-gateway",
+		Namespace:   "default",
+		Annotations: map[string]string{constants.InternalRouteSemantics: constants.RouteSemanticsGateway},
+	}
+	cases := []struct {
+		name  string
+		vs    config.Meta
+		route string
+		want  float64
+		found bool
+	}{
+		{"virtual service", reviews, "default", 10, true},
+		{"section", reviews, "errors", 100, true},
+		{"section with match", reviews, "errors.v2", 100, true},
+		{"other namespace", config.Meta{Name: "reviews", Namespace: "other"}, "errors", 0, false},
+		{"other virtual service", config.Meta{Name: "ratings", Namespace: "default"}, "default.ratings.0", 0, false},
+		{"http route", generated, "default.ratings.0", 50, true},
+		{"other http route", generated, "default.details.0", 0, false},
+		{"http route with a longer name", generated, "default.ratings.v2.0", 0, false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := telemetries.RouteSamplingPercentage(tt.vs, tt.route)
+			assert.Equal(t, found, tt.found)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+	assert.Equal(t, len(telemetries.RouteTelemetryKeys([]config.Config{{Meta: reviews}})), 3)
+	assert.Equal(t, len(telemetries.RouteTelemetryKeys([]config.Config{{Meta: config.Meta{Namespace: "other"}}})), 0)
+
+	key := func(name string) ConfigKey {
+		return ConfigKey{Kind: kind.Telemetry, Name: name, Namespace: "default"}
+	}
+	assert.Equal(t, telemetries.TargetsRoutes(key("reviews")), true)
+	assert.Equal(t, telemetries.TargetsRoutes(key("invalid")), false)
+	// A Telemetry no longer targeting routes still impacts them when it is updated
+	updated, _ := createTestTelemetries([]config.Config{newTelemetry("default", &tpb.Telemetry{})}, t)
+	updated.retainRouteTelemetryKeys(telemetries, sets.New(key("reviews")))
+	assert.Equal(t, updated.TargetsRoutes(key("reviews")), true)
+	assert.Equal(t, updated.TargetsRoutes(key("ratings")), false)
+}
+
+func TestTCPMetricDimensions(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Metrics: []*tpb.Metrics{{
+			Providers: []*tpb.ProviderRef{{Name: "prometheus"}},
+			Overrides: []*tpb.MetricsOverrides{{
+				Match: &tpb.MetricSelector{MetricMatch: &tpb.MetricSelector_Metric{Metric: tpb.MetricSelector_TCP_SENT_BYTES}},
+				TagOverrides: map[string]*tpb.MetricsOverrides_TagOverride{
+					"sni":    {Operation: tpb.MetricsOverrides_TagOverride_UPSERT, Value: "connection.requested_server_name"},
+					"tenant": {Operation: tpb.MetricsOverrides_TagOverride_UPSERT, Value: "filter_state['tenant']"},
+				},
+			}, {
+				TagOverrides: map[string]*tpb.MetricsOverrides_TagOverride{
+					"api_version": {Operation: tpb.MetricsOverrides_TagOverride_UPSERT, Value: "request.headers['x-api-version']"},
+					"upstream":    {Operation: tpb.MetricsOverrides_TagOverride_UPSERT, Value: "upstream.address"},
+				},
+			}},
+		}},
+	})
+	telemetries, _ := createTestTelemetries([]config.Config{root}, t)
+	proxy := &Proxy{ConfigNamespace: "default", Metadata: &NodeMetadata{}}
+
+	for _, class := range []networking.ListenerClass{networking.ListenerClassSidecarInbound, networking.ListenerClassSidecarOutbound} {
+		filters := telemetries.TCPFilters(proxy, class)
+		assert.Equal(t, len(filters), 1)
+		cfg := &stats.PluginConfig{}
+		if err := filters[0].GetTypedConfig().UnmarshalTo(cfg); err != nil {
+			t.Fatal(err)
+		}
+		dimensions := map[string]map[string]string{}
+		for _, m := range cfg.Metrics {
+			dimensions[m.Name] = m.Dimensions
+		}
+		// The dimensions using the request attributes only apply to the HTTP metrics
+		assert.Equal(t, dimensions["requests_total"], map[string]string{
+			"api_version": "request.headers['x-api-version']",
+			"upstream":    "upstream.address",
+		})
+		assert.Equal(t, dimensions["tcp_sent_bytes_total"], map[string]string{
+			"sni":      "connection.requested_server_name",
+			"tenant":   "filter_state['tenant']",
+			"upstream": "upstream.address",
+		})
+		assert.Equal(t, dimensions["tcp_received_bytes_total"], map[string]string{"upstream": "upstream.address"})
+	}
+}
+
+func TestMetricExpiry(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Metrics: []*tpb.Metrics{{Providers: []*tpb.ProviderRef{{Name: "stackdriver"}}}},
+	})
+	root.Annotations = map[string]string{constants.MetricExpiryAnnotation: "30m"}
+	workload := newTelemetry("default", &tpb.Telemetry{
+		Selector: &v1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "churn"}},
+	})
+	workload.Name = "churn"
+	workload.Annotations = map[string]string{constants.MetricExpiryAnnotation: "5m"}
+	invalid := newTelemetry("invalid", &tpb.Telemetry{})
+	invalid.Annotations = map[string]string{constants.MetricExpiryAnnotation: "1s"}
+	telemetries, _ := createTestTelemetries([]config.Config{root, workload, invalid}, t)
+
+	expiry := func(namespace string, labels map[string]string) string {
+		t.Helper()
+		proxy := &Proxy{ConfigNamespace: namespace, Labels: labels, Metadata: &NodeMetadata{Labels: labels}}
+		filters := telemetries.telemetryFilters(proxy, networking.ListenerClassSidecarInbound, networking.ListenerProtocolHTTP).([]*hcm.HttpFilter)
+		assert.Equal(t, len(filters), 1)
+		w := &httpwasm.Wasm{}
+		if err := filters[0].GetTypedConfig().UnmarshalTo(w); err != nil {
+			t.Fatal(err)
+		}
+		cfg := &wrappers.StringValue{}
+		if err := w.GetConfig().GetConfiguration().UnmarshalTo(cfg); err != nil {
+			t.Fatal(err)
+		}
+		sdCfg := &sd.PluginConfig{}
+		if err := protomarshal.Unmarshal([]byte(cfg.GetValue()), sdCfg); err != nil {
+			t.Fatal(err)
+		}
+		return sdCfg.MetricExpiryDuration.AsDuration().String()
+	}
+	assert.Equal(t, expiry("default", map[string]string{"app": "other"}), "30m0s")
+	assert.Equal(t, expiry("default", map[string]string{"app": "churn"}), "5m0s")
+	assert.Equal(t, expiry("invalid", nil), "30m0s")
+}
+
+func TestRequestOperationDimension(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Metrics: []*tpb.Metrics{{Providers: []*tpb.ProviderRef{{Name: "prometheus"}}}},
+	})
+	root.Annotations = map[string]string{
+		constants.RequestOperationsAnnotation: `[{"name": "GetReviews", "method": "GET", "path": "/books/*/reviews"}]`,
+	}
+	telemetries, _ := createTestTelemetries([]config.Config{root}, t)
+	proxy := &Proxy{ConfigNamespace: "default", Metadata: &NodeMetadata{}}
+
+	filters := telemetries.telemetryFilters(proxy, networking.ListenerClassSidecarInbound, networking.ListenerProtocolHTTP).([]*hcm.HttpFilter)
+	assert.Equal(t, len(filters), 1)
+	cfg := &stats.PluginConfig{}
+	if err := filters[0].GetTypedConfig().UnmarshalTo(cfg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(cfg.Metrics), 4)
+	for _, m := range cfg.Metrics {
+		assert.Equal(t, m.Dimensions, map[string]string{
+			"request_operation": `(request.method == 'GET' && request.url_path.matches(r'^/books/[^/]+/reviews$')) ? 'GetReviews' : 'unknown'`,
+		})
+	}
+}
+
+func TestTraceLabelTags(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Tracing: []*tpb.Tracing{{Providers: []*tpb.ProviderRef{{Name: "stackdriver"}}}},
+	})
+	root.Annotations = map[string]string{constants.TraceLabelTagsAnnotation: "team=team"}
+	workload := newTelemetry("default", &tpb.Telemetry{
+		Selector: &v1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "checkout"}},
+	})
+	workload.Name = "checkout"
+	workload.Annotations = map[string]string{constants.TraceLabelTagsAnnotation: "build=version,owner=team"}
+	invalid := newTelemetry("invalid", &tpb.Telemetry{})
+	invalid.Annotations = map[string]string{constants.TraceLabelTagsAnnotation: "build"}
+	telemetries, _ := createTestTelemetries([]config.Config{root, workload, invalid}, t)
+
+	labelTags := func(namespace string, labels map[string]string) map[string]string {
+		t.Helper()
+		proxy := &Proxy{ConfigNamespace: namespace, Labels: labels, Metadata: &NodeMetadata{Labels: labels}}
+		tracing := telemetries.Tracing(proxy)
+		assert.Equal(t, tracing.ClientSpec.LabelTags, tracing.ServerSpec.LabelTags)
+		return tracing.ServerSpec.LabelTags
+	}
+	assert.Equal(t, labelTags("default", map[string]string{"app": "other", "team": "payments"}),
+		map[string]string{"team": "payments"})
+	assert.Equal(t, labelTags("default", map[string]string{"app": "checkout", "team": "payments", "version": "v2"}),
+		map[string]string{"build": "v2", "owner": "payments"})
+	assert.Equal(t, labelTags("default", map[string]string{"app": "other"}), nil)
+	assert.Equal(t, labelTags("invalid", map[string]string{"team": "payments"}),
+		map[string]string{"team": "payments"})
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	root := newTelemetry("istio-system", &tpb.Telemetry{
+		Tracing: []*tpb.Tracing{{Providers: []*tpb.ProviderRef{{Name: "stackdriver"}}}},
+	})
+	legacy := newTelemetry("legacy", &tpb.Telemetry{})
+	legacy.Annotations = map[string]string{constants.TraceContextPropagationAnnotation: "B3,W3C_TRACE_CONTEXT"}
+	invalid := newTelemetry("invalid", &tpb.Telemetry{})
+	invalid.Annotations = map[string]string{constants.TraceContextPropagationAnnotation: "DATADOG"}
+	telemetries, _ := createTestTelemetries([]config.Config{root, legacy, invalid}, t)
+
+	contexts := func(namespace string) []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext {
+		t.Helper()
+		proxy := &Proxy{ConfigNamespace: namespace, Metadata: &NodeMetadata{}}
+		tracing := telemetries.Tracing(proxy)
+		assert.Equal(t, tracing.ClientSpec.TraceContexts, tracing.ServerSpec.TraceContexts)
+		return tracing.ServerSpec.TraceContexts
+	}
+	assert.Equal(t, contexts("legacy"), []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext{
+		meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_B3,
+		meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_W3C_TRACE_CONTEXT,
+	})
+	assert.Equal(t, contexts("default"), nil)
+	assert.Equal(t, contexts("invalid"), nil)
+}
@@ -0,0 +1,71 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// TrustDomainMigration is a window during which proxies accept the identities of a previous trust domain of the
+// mesh, while the certificates are already issued with the trust domain of the mesh config.
+type TrustDomainMigration struct {
+	// From is the previous trust domain of the mesh.
+	From string `json:"from"`
+	// Until is the end of the migration window, after which the identities of the previous trust domain are
+	// no longer accepted unless it is one of the trust domain aliases of the mesh config.
+	Until time.Time `json:"until"`
+}
+
+// ParseTrustDomainMigration parses the trust domain migration of the PILOT_TRUST_DOMAIN_MIGRATION setting.
+func ParseTrustDomainMigration(value string) (*TrustDomainMigration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	m := &TrustDomainMigration{}
+	if err := json.Unmarshal([]byte(value), m); err != nil {
+		return nil, err
+	}
+	if m.From == "" {
+		return nil, fmt.Errorf("the previous trust domain is required")
+	}
+	if m.Until.IsZero() {
+		return nil, fmt.Errorf("the end of the migration window is required")
+	}
+	return m, nil
+}
+
+// Active returns true if the migration window is open at the given time.
+func (m *TrustDomainMigration) Active(now time.Time) bool {
+	return m != nil && now.Before(m.Until)
+}
+
+// Apply returns the mesh config with the previous trust domain added to its trust domain aliases while the
+// migration window is open, so the proxies accept both trust domains.
+func (m *TrustDomainMigration) Apply(mesh *meshconfig.MeshConfig, now time.Time) *meshconfig.MeshConfig {
+	if !m.Active(now) || mesh == nil || mesh.TrustDomain == m.From ||
+		sets.New(mesh.TrustDomainAliases...).Contains(m.From) {
+		return mesh
+	}
+	mesh = proto.Clone(mesh).(*meshconfig.MeshConfig)
+	mesh.TrustDomainAliases = append(mesh.TrustDomainAliases, m.From)
+	return mesh
+}
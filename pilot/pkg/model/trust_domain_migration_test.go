@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+func TestParseTrustDomainMigration(t *testing.T) {
+	until := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		value   string
+		want    *TrustDomainMigration
+		wantErr bool
+	}{
+		{"unset", "", nil, false},
+		{"valid", `{"from":"old.example.com","until":"2024-01-01T00:00:00Z"}`, &TrustDomainMigration{From: "old.example.com", Until: until}, false},
+		{"invalid JSON", `{"from"`, nil, true},
+		{"missing trust domain", `{"until":"2024-01-01T00:00:00Z"}`, nil, true},
+		{"missing end", `{"from":"old.example.com"}`, nil, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTrustDomainMigration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrustDomainMigrationApply(t *testing.T) {
+	now := time.Now()
+	m := &TrustDomainMigration{From: "old.example.com", Until: now.Add(time.Hour)}
+	cases := []struct {
+		name      string
+		migration *TrustDomainMigration
+		mesh      *meshconfig.MeshConfig
+		now       time.Time
+		want      []string
+	}{
+		{"no migration", nil, &meshconfig.MeshConfig{TrustDomain: "cluster.local"}, now, nil},
+		{"active", m, &meshconfig.MeshConfig{TrustDomain: "cluster.local"}, now, []string{"old.example.com"}},
+		{"ended", m, &meshconfig.MeshConfig{TrustDomain: "cluster.local"}, now.Add(2 * time.Hour), nil},
+		{
+			"already aliased", m,
+			&meshconfig.MeshConfig{TrustDomain: "cluster.local", TrustDomainAliases: []string{"old.example.com"}},
+			now, []string{"old.example.com"},
+		},
+		{
+			"other aliases", m,
+			&meshconfig.MeshConfig{TrustDomain: "cluster.local", TrustDomainAliases: []string{"other.example.com"}},
+			now, []string{"other.example.com", "old.example.com"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			aliases := append([]string{}, tt.mesh.TrustDomainAliases...)
+			got := tt.migration.Apply(tt.mesh, tt.now)
+			if !reflect.DeepEqual(got.TrustDomainAliases, tt.want) {
+				t.Fatalf("expected trust domain aliases %v, got %v", tt.want, got.TrustDomainAliases)
+			}
+			if !reflect.DeepEqual(append([]string{}, tt.mesh.TrustDomainAliases...), aliases) {
+				t.Fatalf("the mesh config was modified: %v", tt.mesh.TrustDomainAliases)
+			}
+		})
+	}
+}
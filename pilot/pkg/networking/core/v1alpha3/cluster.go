@@ -195,12 +195,16 @@ func (configgen *ConfigGeneratorImpl) buildClusters(proxy *model.Proxy, req *mod
 		if proxy.Type == model.Router && proxy.MergedGateway != nil && proxy.MergedGateway.ContainsAutoPassthroughGateways {
 			clusters = append(clusters, configgen.buildOutboundSniDnatClusters(proxy, req, patcher)...)
 		}
+		if isHBONEEastWestGateway(proxy) {
+			clusters = append(clusters, cb.buildEastWestPassthroughCluster())
+		}
 		clusters = append(clusters, patcher.insertedClusters()...)
 	}
 
 	// OutboundTunnel cluster is needed for sidecar and gateway.
 	if proxy.EnableHBONE() {
 		clusters = append(clusters, cb.buildConnectOriginate(proxy, req.Push, nil))
+		clusters = append(clusters, cb.buildEastWestOriginateClusters(proxy, req.Push)...)
 	}
 
 	// if credential socket exists, create a cluster for it
@@ -254,11 +258,15 @@ func (configgen *ConfigGeneratorImpl) buildOutboundClusters(cb *ClusterBuilder,
 			miss += len(cached)
 
 			// We have a cache miss, so we will re-generate the cluster and later store it in the cache.
-			lbEndpoints := cb.buildLocalityLbEndpoints(clusterKey.proxyView, service, port.Port, nil)
-
 			// create default cluster
-			discoveryType := convertResolution(cb.proxyType, service)
-			defaultCluster := cb.buildDefaultCluster(clusterKey.clusterName, discoveryType, lbEndpoints, model.TrafficDirectionOutbound, port, service, nil)
+			var defaultCluster *MutableCluster
+			if useDynamicForwardProxy(service, port) {
+				defaultCluster = cb.buildDynamicForwardProxyCluster(clusterKey.clusterName, port, service)
+			} else {
+				lbEndpoints := cb.buildLocalityLbEndpoints(clusterKey.proxyView, service, port.Port, nil)
+				discoveryType := convertResolution(cb.proxyType, service)
+				defaultCluster = cb.buildDefaultCluster(clusterKey.clusterName, discoveryType, lbEndpoints, model.TrafficDirectionOutbound, port, service, nil)
+			}
 			if defaultCluster == nil {
 				continue
 			}
@@ -767,7 +775,7 @@ func applyLoadBalancer(c *cluster.Cluster, lb *networking.LoadBalancerSettings,
 	// Use locality lb settings from load balancer settings if present, else use mesh wide locality lb settings
 	applyLocalityLBSetting(locality, proxyLabels, c, localityLbSetting)
 
-	if c.GetType() == cluster.Cluster_ORIGINAL_DST {
+	if c.GetType() == cluster.Cluster_ORIGINAL_DST || c.GetClusterType().GetName() == DynamicForwardProxyClusterType {
 		c.LbPolicy = cluster.Cluster_CLUSTER_PROVIDED
 		return
 	}
@@ -376,23 +376,7 @@ func (cb *ClusterBuilder) buildDefaultCluster(name string, discoveryType cluster
 	ec := NewMutableCluster(c)
 	switch discoveryType {
 	case cluster.Cluster_STRICT_DNS, cluster.Cluster_LOGICAL_DNS:
-		if networkutil.AllIPv4(cb.proxyIPAddresses) {
-			// IPv4 only
-			c.DnsLookupFamily = cluster.Cluster_V4_ONLY
-		} else if networkutil.AllIPv6(cb.proxyIPAddresses) {
-			// IPv6 only
-			c.DnsLookupFamily = cluster.Cluster_V6_ONLY
-		} else {
-			// Dual Stack
-			if features.EnableDualStack {
-				// If dual-stack, it may be [IPv4, IPv6] or [IPv6, IPv4]
-				// using Cluster_ALL to enable Happy Eyeballsfor upstream connections
-				c.DnsLookupFamily = cluster.Cluster_ALL
-			} else {
-				// keep the original logic if Dual Stack is disable
-				c.DnsLookupFamily = cluster.Cluster_V4_ONLY
-			}
-		}
+		c.DnsLookupFamily = dnsLookupFamily(cb.proxyIPAddresses)
 		dnsRate := cb.req.Push.Mesh.DnsRefreshRate
 		c.DnsRefreshRate = dnsRate
 		c.RespectDnsTtl = true
@@ -436,6 +420,25 @@ func (cb *ClusterBuilder) buildDefaultCluster(name string, discoveryType cluster
 	return ec
 }
 
+// dnsLookupFamily returns the DNS lookup family of the proxy listening on the IP addresses.
+func dnsLookupFamily(proxyIPAddresses []string) cluster.Cluster_DnsLookupFamily {
+	if networkutil.AllIPv4(proxyIPAddresses) {
+		// IPv4 only
+		return cluster.Cluster_V4_ONLY
+	} else if networkutil.AllIPv6(proxyIPAddresses) {
+		// IPv6 only
+		return cluster.Cluster_V6_ONLY
+	}
+	// Dual Stack
+	if features.EnableDualStack {
+		// If dual-stack, it may be [IPv4, IPv6] or [IPv6, IPv4]
+		// using Cluster_ALL to enable Happy Eyeballsfor upstream connections
+		return cluster.Cluster_ALL
+	}
+	// keep the original logic if Dual Stack is disable
+	return cluster.Cluster_V4_ONLY
+}
+
 // buildInboundClusterForPortOrUDS constructs a single inbound cluster. The cluster will be bound to
 // `inbound|clusterPort||`, and send traffic to <bind>:<instance.Endpoint.EndpointPort>. A workload
 // will have a single inbound cluster per port. In general this works properly, with the exception of
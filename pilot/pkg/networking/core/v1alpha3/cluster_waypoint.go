@@ -32,6 +32,7 @@ import (
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/spiffe"
@@ -87,7 +88,9 @@ func (configgen *ConfigGeneratorImpl) buildWaypointInboundClusters(
 }
 
 // `inbound-vip||hostname|port`. EDS routing to the internal listener for each pod in the VIP.
-func (cb *ClusterBuilder) buildWaypointInboundVIPCluster(svc *model.Service, port model.Port, subset string) *MutableCluster {
+func (cb *ClusterBuilder) buildWaypointInboundVIPCluster(svc *model.Service, port model.Port, subset string,
+	policy *networking.TrafficPolicy, destRule *config.Config,
+) *MutableCluster {
 	clusterName := model.BuildSubsetKey(model.TrafficDirectionInboundVIP, subset, svc.Hostname, port.Port)
 
 	clusterType := cluster.Cluster_EDS
@@ -109,35 +112,54 @@ func (cb *ClusterBuilder) buildWaypointInboundVIPCluster(svc *model.Service, por
 	// no TLS, we are just going to internal address
 	localCluster.cluster.TransportSocketMatches = nil
 	localCluster.cluster.TransportSocket = util.TunnelHostInternalUpstreamTransportSocket
+	cb.applyWaypointTrafficPolicy(localCluster, &port, policy)
 	maybeApplyEdsConfig(localCluster.cluster)
+	if destRule != nil {
+		localCluster.cluster.Metadata = util.AddConfigInfoMetadata(localCluster.cluster.Metadata, destRule.Meta)
+	}
 	return localCluster
 }
 
+// applyWaypointTrafficPolicy applies a DestinationRule traffic policy to a waypoint VIP cluster.
+// The waypoint, rather than the source, selects the endpoint of the service, so it owns load balancing, outlier detection,
+// and the connection pool to the endpoints. The source only applies the traffic policy to its connection to the waypoint.
+// TLS settings are ignored, as the waypoint always reaches endpoints over HBONE.
+func (cb *ClusterBuilder) applyWaypointTrafficPolicy(mc *MutableCluster, port *model.Port, policy *networking.TrafficPolicy) {
+	connectionPool, outlierDetection, loadBalancer, _ := selectTrafficPolicyComponents(policy)
+	if connectionPool == nil {
+		connectionPool = &networking.ConnectionPoolSettings{}
+	}
+	cb.applyConnectionPool(cb.req.Push.Mesh, mc, connectionPool)
+	applyOutlierDetection(mc.cluster, outlierDetection)
+	applyLoadBalancer(mc.cluster, loadBalancer, port, cb.locality, cb.proxyLabels, cb.req.Push.Mesh)
+}
+
 // `inbound-vip|protocol|hostname|port`. EDS routing to the internal listener for each pod in the VIP.
 func (cb *ClusterBuilder) buildWaypointInboundVIP(svcs map[host.Name]*model.Service) []*cluster.Cluster {
 	clusters := []*cluster.Cluster{}
 
 	for _, svc := range svcs {
+		destRule := cb.unsafeWaypointOnlyProxy.SidecarScope.DestinationRule(model.TrafficDirectionInbound, cb.unsafeWaypointOnlyProxy, svc.Hostname).GetRule()
+		destinationRule := CastDestinationRule(destRule)
 		for _, port := range svc.Ports {
 			if port.Protocol == protocol.UDP {
 				continue
 			}
+			policy := MergeTrafficPolicy(nil, destinationRule.GetTrafficPolicy(), port)
 			if port.Protocol.IsUnsupported() || port.Protocol.IsTCP() {
-				clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "tcp").build())
+				clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "tcp", policy, destRule).build())
 			}
 			if port.Protocol.IsUnsupported() || port.Protocol.IsHTTP() {
-				clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "http").build())
+				clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "http", policy, destRule).build())
 			}
-			cfg := cb.unsafeWaypointOnlyProxy.SidecarScope.DestinationRule(model.TrafficDirectionInbound, cb.unsafeWaypointOnlyProxy, svc.Hostname).GetRule()
-			if cfg != nil {
-				destinationRule := cfg.Spec.(*networking.DestinationRule)
-				for _, ss := range destinationRule.Subsets {
-					if port.Protocol.IsUnsupported() || port.Protocol.IsTCP() {
-						clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "tcp/"+ss.Name).build())
-					}
-					if port.Protocol.IsUnsupported() || port.Protocol.IsHTTP() {
-						clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "http/"+ss.Name).build())
-					}
+			for _, ss := range destinationRule.GetSubsets() {
+				// As with sidecars, subset policies are merged over the policy of the DestinationRule.
+				subsetPolicy := MergeTrafficPolicy(policy, ss.TrafficPolicy, port)
+				if port.Protocol.IsUnsupported() || port.Protocol.IsTCP() {
+					clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "tcp/"+ss.Name, subsetPolicy, destRule).build())
+				}
+				if port.Protocol.IsUnsupported() || port.Protocol.IsHTTP() {
+					clusters = append(clusters, cb.buildWaypointInboundVIPCluster(svc, *port, "http/"+ss.Name, subsetPolicy, destRule).build())
 				}
 			}
 		}
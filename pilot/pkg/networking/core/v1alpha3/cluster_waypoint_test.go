@@ -0,0 +1,89 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha3
+
+import (
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/test/xdstest"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestWaypointInboundVIPTrafficPolicy(t *testing.T) {
+	svc := &model.Service{
+		Hostname:   "example.default.svc.cluster.local",
+		Ports:      model.PortList{{Name: "http", Port: 80, Protocol: protocol.HTTP}},
+		Attributes: model.ServiceAttributes{Name: "example", Namespace: "default"},
+	}
+	cg := NewConfigGenTest(t, TestOptions{
+		Services: []*model.Service{svc},
+		ConfigString: `
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: example
+  namespace: default
+spec:
+  host: example.default.svc.cluster.local
+  trafficPolicy:
+    loadBalancer:
+      simple: ROUND_ROBIN
+    outlierDetection:
+      consecutive5xxErrors: 3
+    connectionPool:
+      tcp:
+        maxConnections: 10
+    tls:
+      mode: SIMPLE
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+    trafficPolicy:
+      loadBalancer:
+        simple: RANDOM
+`,
+	})
+	proxy := cg.SetupProxy(&model.Proxy{
+		Type:            model.Router,
+		ConfigNamespace: "default",
+		Labels:          map[string]string{"gateway.istio.io/managed": "istio.io-mesh-controller"},
+	})
+	cb := NewClusterBuilder(proxy, &model.PushRequest{Push: cg.PushContext()}, nil)
+	clusters := xdstest.ExtractClusters(cb.buildWaypointInboundVIP(map[host.Name]*model.Service{svc.Hostname: svc}))
+
+	c := clusters["inbound-vip|80|http|example.default.svc.cluster.local"]
+	if c == nil {
+		t.Fatalf("expected inbound-vip cluster, got %v", xdstest.MapKeys(clusters))
+	}
+	assert.Equal(t, c.LbPolicy, cluster.Cluster_ROUND_ROBIN)
+	assert.Equal(t, c.OutlierDetection.GetConsecutive_5Xx().GetValue(), uint32(3))
+	assert.Equal(t, c.CircuitBreakers.GetThresholds()[0].GetMaxConnections().GetValue(), uint32(10))
+	// The waypoint always reaches endpoints over HBONE, so TLS settings do not apply.
+	assert.Equal(t, c.TransportSocket.GetName(), "internal_upstream")
+
+	subset := clusters["inbound-vip|80|http/v1|example.default.svc.cluster.local"]
+	if subset == nil {
+		t.Fatalf("expected subset cluster, got %v", xdstest.MapKeys(clusters))
+	}
+	assert.Equal(t, subset.LbPolicy, cluster.Cluster_RANDOM)
+	// Settings not overridden by the subset are inherited from the DestinationRule.
+	assert.Equal(t, subset.OutlierDetection.GetConsecutive_5Xx().GetValue(), uint32(3))
+}
@@ -0,0 +1,107 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha3
+
+import (
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	dfpcluster "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/dynamic_forward_proxy/v3"
+	dfpcommon "github.com/envoyproxy/go-control-plane/envoy/extensions/common/dynamic_forward_proxy/v3"
+	dfp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/dynamic_forward_proxy/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry/provider"
+	"istio.io/istio/pilot/pkg/util/protoconv"
+)
+
+const (
+	// DynamicForwardProxyClusterType is the type of the clusters resolving the host of the requests on demand.
+	DynamicForwardProxyClusterType = "envoy.clusters.dynamic_forward_proxy"
+
+	// DynamicForwardProxyFilterName is the name of the HTTP filter resolving the host of the requests routed to the
+	// dynamic forward proxy clusters.
+	DynamicForwardProxyFilterName = "envoy.filters.http.dynamic_forward_proxy"
+
+	// dynamicForwardProxyDNSCacheName is the name of the DNS cache shared by the filter and the clusters: the
+	// configurations of the caches of the same name must be identical.
+	dynamicForwardProxyDNSCacheName = "istio_dynamic_forward_proxy"
+)
+
+// useDynamicForwardProxy returns true if the HTTP requests to the port of the service are sent to the host of the
+// request, resolved on demand, instead of the endpoints of the service: the service is a ServiceEntry of a wildcard
+// host resolved by DNS opting in with the networking.istio.io/dynamic-forward-proxy annotation, every subdomain of
+// the host is then resolved and load balanced on its own.
+func useDynamicForwardProxy(service *model.Service, port *model.Port) bool {
+	return service.Attributes.DynamicForwardProxy && service.Attributes.ServiceRegistry == provider.External &&
+		service.Hostname.IsWildCarded() && (service.Resolution == model.DNSLB || service.Resolution == model.DNSRoundRobinLB) &&
+		port.Protocol.IsHTTP()
+}
+
+// needsDynamicForwardProxyFilter returns true if some of the services visible to the proxy are sent through the
+// dynamic forward proxy, its HTTP connection managers then need the filter resolving the host of the requests.
+func needsDynamicForwardProxyFilter(proxy *model.Proxy) bool {
+	if proxy.SidecarScope == nil {
+		return false
+	}
+	for _, service := range proxy.SidecarScope.Services() {
+		for _, port := range service.Ports {
+			if useDynamicForwardProxy(service, port) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dynamicForwardProxyDNSCache returns the configuration of the DNS cache of the dynamic forward proxy of the proxy
+// listening on the IP addresses.
+func dynamicForwardProxyDNSCache(proxyIPAddresses []string, mesh *meshconfig.MeshConfig) *dfpcommon.DnsCacheConfig {
+	return &dfpcommon.DnsCacheConfig{
+		Name:            dynamicForwardProxyDNSCacheName,
+		DnsLookupFamily: dnsLookupFamily(proxyIPAddresses),
+		DnsRefreshRate:  mesh.DnsRefreshRate,
+	}
+}
+
+// buildDynamicForwardProxyFilter builds the HTTP filter resolving the host of the requests routed to the dynamic
+// forward proxy clusters. The requests routed to other clusters are left untouched.
+func buildDynamicForwardProxyFilter(proxy *model.Proxy, push *model.PushContext) *hcm.HttpFilter {
+	return &hcm.HttpFilter{
+		Name: DynamicForwardProxyFilterName,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: protoconv.MessageToAny(&dfp.FilterConfig{
+				DnsCacheConfig: dynamicForwardProxyDNSCache(proxy.IPAddresses, push.Mesh),
+			}),
+		},
+	}
+}
+
+// buildDynamicForwardProxyCluster builds the cluster of the port of the service sending the requests to their host.
+func (cb *ClusterBuilder) buildDynamicForwardProxyCluster(name string, port *model.Port, service *model.Service) *MutableCluster {
+	// The cluster has no endpoints, it is built as an EDS cluster before its type is replaced.
+	mc := cb.buildDefaultCluster(name, cluster.Cluster_EDS, nil, model.TrafficDirectionOutbound, port, service, nil)
+	if mc == nil {
+		return nil
+	}
+	mc.cluster.ClusterDiscoveryType = &cluster.Cluster_ClusterType{ClusterType: &cluster.Cluster_CustomClusterType{
+		Name: DynamicForwardProxyClusterType,
+		TypedConfig: protoconv.MessageToAny(&dfpcluster.ClusterConfig{
+			DnsCacheConfig: dynamicForwardProxyDNSCache(cb.proxyIPAddresses, cb.req.Push.Mesh),
+		}),
+	}}
+	mc.cluster.LbPolicy = cluster.Cluster_CLUSTER_PROVIDED
+	return mc
+}
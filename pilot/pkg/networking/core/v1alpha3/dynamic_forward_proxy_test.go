@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha3
+
+import (
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	dfpcluster "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/dynamic_forward_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry/provider"
+	"istio.io/istio/pilot/test/xdstest"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestDynamicForwardProxy(t *testing.T) {
+	service := &model.Service{
+		Hostname: host.Name("*.example.com"),
+		Ports: []*model.Port{
+			{Name: "http", Port: 80, Protocol: protocol.HTTP},
+			{Name: "tcp", Port: 9000, Protocol: protocol.TCP},
+		},
+		Resolution:   model.DNSLB,
+		MeshExternal: true,
+		Attributes: model.ServiceAttributes{
+			Namespace:           TestServiceNamespace,
+			ServiceRegistry:     provider.External,
+			DynamicForwardProxy: true,
+		},
+	}
+	cg := NewConfigGenTest(t, TestOptions{Services: []*model.Service{service}})
+	proxy := cg.SetupProxy(nil)
+	clusters := cg.Clusters(proxy)
+	xdstest.ValidateClusters(t, clusters)
+
+	c := xdstest.ExtractCluster("outbound|80||*.example.com", clusters)
+	if c == nil {
+		t.Fatalf("dynamic forward proxy cluster not found")
+	}
+	assert.Equal(t, c.GetClusterType().GetName(), DynamicForwardProxyClusterType)
+	assert.Equal(t, c.LbPolicy, cluster.Cluster_CLUSTER_PROVIDED)
+	cfg := xdstest.UnmarshalAny[dfpcluster.ClusterConfig](t, c.GetClusterType().GetTypedConfig())
+	assert.Equal(t, cfg.DnsCacheConfig.Name, dynamicForwardProxyDNSCacheName)
+	// The TCP port keeps resolving the wildcard host, the cluster without endpoints is ignored.
+	assert.Equal(t, xdstest.ExtractCluster("outbound|9000||*.example.com", clusters), nil)
+
+	l := xdstest.ExtractListener("0.0.0.0_80", cg.Listeners(proxy))
+	if l == nil {
+		t.Fatalf("listener not found")
+	}
+	var filters []string
+	for _, f := range xdstest.ExtractHTTPConnectionManager(t, l.FilterChains[0]).HttpFilters {
+		filters = append(filters, f.Name)
+	}
+	// The filter precedes the router.
+	assert.Equal(t, filters[len(filters)-2:], []string{DynamicForwardProxyFilterName, wellknown.Router})
+}
+
+func TestDynamicForwardProxyOptIn(t *testing.T) {
+	service := &model.Service{
+		Hostname:     host.Name("*.example.com"),
+		Ports:        []*model.Port{{Name: "http", Port: 80, Protocol: protocol.HTTP}},
+		Resolution:   model.DNSLB,
+		MeshExternal: true,
+		Attributes: model.ServiceAttributes{
+			Namespace:       TestServiceNamespace,
+			ServiceRegistry: provider.External,
+		},
+	}
+	cg := NewConfigGenTest(t, TestOptions{Services: []*model.Service{service}})
+	proxy := cg.SetupProxy(nil)
+
+	// The ServiceEntry does not opt in, the wildcard host keeps being resolved as a single cluster, which is ignored
+	// without endpoints.
+	assert.Equal(t, xdstest.ExtractCluster("outbound|80||*.example.com", cg.Clusters(proxy)), nil)
+	l := xdstest.ExtractListener("0.0.0.0_80", cg.Listeners(proxy))
+	if l == nil {
+		t.Fatalf("listener not found")
+	}
+	for _, f := range xdstest.ExtractHTTPConnectionManager(t, l.FilterChains[0]).HttpFilters {
+		if f.Name == DynamicForwardProxyFilterName {
+			t.Fatalf("unexpected dynamic forward proxy filter")
+		}
+	}
+}
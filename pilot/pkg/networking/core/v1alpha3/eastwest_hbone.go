@@ -0,0 +1,193 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha3
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	proxyprotocollistener "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
+	tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	proxyprotocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"google.golang.org/protobuf/types/known/durationpb"
+	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/api/label"
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/util/protoconv"
+	xdsfilters "istio.io/istio/pilot/pkg/xds/filters"
+)
+
+const (
+	// EastWestOriginate is the name prefix of the resources of the proxies originating the HBONE tunnels to the
+	// workloads of the other networks through their east-west gateway, one per gateway.
+	EastWestOriginate = "east_west_originate"
+
+	// EastWestPassthrough is the name of the resources of the east-west gateway relaying the HBONE tunnels of the
+	// proxies of the other networks to the workloads of its network.
+	EastWestPassthrough = "east_west_passthrough"
+)
+
+// EastWestOriginateName returns the name of the internal listener and the cluster originating the HBONE tunnels
+// through the east-west gateway.
+func EastWestOriginateName(gw model.NetworkGateway) string {
+	return EastWestOriginate + "|" + net.JoinHostPort(gw.Addr, strconv.Itoa(int(gw.HBONEPort)))
+}
+
+// isHBONEEastWestGateway returns true if the proxy is the east-west gateway of a network accepting HBONE.
+func isHBONEEastWestGateway(node *model.Proxy) bool {
+	return features.EnableHBONEEastWest && node.Type == model.Router && node.EnableHBONE() &&
+		node.Labels[label.TopologyNetwork.Name] != ""
+}
+
+// remoteHBONEGateways returns the gateways of the other networks accepting HBONE.
+func remoteHBONEGateways(node *model.Proxy, push *model.PushContext) []model.NetworkGateway {
+	if !features.EnableHBONEEastWest || push.NetworkManager() == nil {
+		return nil
+	}
+	var out []model.NetworkGateway
+	for _, gw := range push.NetworkManager().AllGateways() {
+		if gw.HBONEPort > 0 && !node.InNetwork(gw.Network) {
+			out = append(out, gw)
+		}
+	}
+	return out
+}
+
+// buildEastWestOriginateListeners builds the internal listeners originating the HBONE tunnels to the workloads of
+// the other networks. The mTLS and the CONNECT are established with the workload itself, so it sees the identity
+// of the proxy: the east-west gateway only relays the bytes of the tunnel, to the address found in the PROXY
+// protocol header sent by the cluster of the gateway.
+func buildEastWestOriginateListeners(node *model.Proxy, push *model.PushContext) []*listener.Listener {
+	var out []*listener.Listener
+	for _, gw := range remoteHBONEGateways(node, push) {
+		name := EastWestOriginateName(gw)
+		out = append(out, &listener.Listener{
+			Name:              name,
+			UseOriginalDst:    wrappers.Bool(false),
+			ListenerSpecifier: &listener.Listener_InternalListener{InternalListener: &listener.Listener_InternalListenerConfig{}},
+			ListenerFilters:   []*listener.ListenerFilter{xdsfilters.SetDstAddress},
+			FilterChains: []*listener.FilterChain{{
+				Filters: []*listener.Filter{{
+					Name: wellknown.TCPProxy,
+					ConfigType: &listener.Filter_TypedConfig{
+						TypedConfig: protoconv.MessageToAny(&tcp.TcpProxy{
+							StatPrefix:       name,
+							ClusterSpecifier: &tcp.TcpProxy_Cluster{Cluster: name},
+							TunnelingConfig: &tcp.TcpProxy_TunnelingConfig{
+								Hostname: "%DOWNSTREAM_LOCAL_ADDRESS%",
+							},
+						}),
+					},
+				}},
+			}},
+		})
+	}
+	return out
+}
+
+// buildEastWestOriginateClusters builds the clusters sending the HBONE tunnels to the workloads of the other
+// networks to their east-west gateway, preceded by a PROXY protocol header holding the HBONE address of the workload.
+func (cb *ClusterBuilder) buildEastWestOriginateClusters(proxy *model.Proxy, push *model.PushContext) []*cluster.Cluster {
+	var out []*cluster.Cluster
+	for _, gw := range remoteHBONEGateways(proxy, push) {
+		name := EastWestOriginateName(gw)
+		out = append(out, &cluster.Cluster{
+			Name:                 name,
+			ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_STATIC},
+			LoadAssignment: &endpoint.ClusterLoadAssignment{
+				ClusterName: name,
+				Endpoints: []*endpoint.LocalityLbEndpoints{{
+					LbEndpoints: []*endpoint.LbEndpoint{{
+						HostIdentifier: &endpoint.LbEndpoint_Endpoint{Endpoint: &endpoint.Endpoint{
+							Address: util.BuildAddress(gw.Addr, gw.HBONEPort),
+						}},
+					}},
+				}},
+			},
+			ConnectTimeout:                durationpb.New(2 * time.Second),
+			TypedExtensionProtocolOptions: h2connectUpgrade(),
+			TransportSocket: &core.TransportSocket{
+				Name: "envoy.transport_sockets.upstream_proxy_protocol",
+				ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: protoconv.MessageToAny(&proxyprotocol.ProxyProtocolUpstreamTransport{
+					// The destination of the header is the local address of the internal listener, the HBONE address
+					// of the workload.
+					Config: &core.ProxyProtocolConfig{Version: core.ProxyProtocolConfig_V2},
+					TransportSocket: &core.TransportSocket{
+						Name: "tls",
+						ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: protoconv.MessageToAny(&tls.UpstreamTlsContext{
+							CommonTlsContext: buildCommonConnectTLSContext(proxy, push),
+						})},
+					},
+				})},
+			},
+		})
+	}
+	return out
+}
+
+// buildEastWestPassthroughListener builds the listener of the east-west gateway relaying the HBONE tunnels of the
+// proxies of the other networks to the workloads of its network. The tunnels are not terminated: the mTLS is
+// established between the proxy and the workload, and the HBONE address of the workload is read from the PROXY
+// protocol header preceding the tunnel.
+func (lb *ListenerBuilder) buildEastWestPassthroughListener() *listener.Listener {
+	actualWildcard, _ := getActualWildcardAndLocalHost(lb.node)
+	return &listener.Listener{
+		Name:    EastWestPassthrough,
+		Address: util.BuildAddress(actualWildcard, model.HBoneInboundListenPort),
+		ListenerFilters: []*listener.ListenerFilter{{
+			Name: wellknown.ProxyProtocol,
+			ConfigType: &listener.ListenerFilter_TypedConfig{
+				TypedConfig: protoconv.MessageToAny(&proxyprotocollistener.ProxyProtocol{}),
+			},
+		}},
+		FilterChains: []*listener.FilterChain{{
+			Filters: []*listener.Filter{{
+				Name: wellknown.TCPProxy,
+				ConfigType: &listener.Filter_TypedConfig{
+					TypedConfig: protoconv.MessageToAny(&tcp.TcpProxy{
+						StatPrefix:       EastWestPassthrough,
+						ClusterSpecifier: &tcp.TcpProxy_Cluster{Cluster: EastWestPassthrough},
+					}),
+				},
+			}},
+		}},
+	}
+}
+
+// buildEastWestPassthroughCluster builds the cluster of the east-west gateway relaying the HBONE tunnels to the
+// workloads of its network.
+func (cb *ClusterBuilder) buildEastWestPassthroughCluster() *cluster.Cluster {
+	return &cluster.Cluster{
+		Name:                 EastWestPassthrough,
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_ORIGINAL_DST},
+		LbPolicy:             cluster.Cluster_CLUSTER_PROVIDED,
+		LbConfig: &cluster.Cluster_OriginalDstLbConfig_{OriginalDstLbConfig: &cluster.Cluster_OriginalDstLbConfig{
+			// Only the HBONE port of the workloads is reachable through the gateway.
+			UpstreamPortOverride: &wrappers.UInt32Value{Value: model.HBoneInboundListenPort},
+		}},
+		ConnectTimeout:  durationpb.New(2 * time.Second),
+		CleanupInterval: durationpb.New(60 * time.Second),
+	}
+}
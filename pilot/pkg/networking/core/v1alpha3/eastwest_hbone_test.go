@@ -0,0 +1,116 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha3
+
+import (
+	"strings"
+	"testing"
+
+	"istio.io/api/label"
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/test/xdstest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestEastWestHBONEGateway(t *testing.T) {
+	test.SetForTest(t, &features.EnableHBONE, true)
+	cg := NewConfigGenTest(t, TestOptions{
+		ConfigString: `
+apiVersion: networking.istio.io/v1alpha3
+kind: Gateway
+metadata:
+  name: cross-network
+  namespace: istio-system
+spec:
+  selector:
+    istio: eastwestgateway
+  servers:
+  - port:
+      number: 15443
+      name: tls
+      protocol: TLS
+    tls:
+      mode: AUTO_PASSTHROUGH
+    hosts:
+    - "*.local"
+`,
+	})
+	gateway := func(network string) *model.Proxy {
+		labels := map[string]string{"istio": "eastwestgateway"}
+		if network != "" {
+			labels[label.TopologyNetwork.Name] = network
+		}
+		return cg.SetupProxy(&model.Proxy{
+			Type:            model.Router,
+			ConfigNamespace: "istio-system",
+			Labels:          labels,
+			Metadata:        &model.NodeMetadata{EnableHBONE: true, Labels: labels, Network: "network-1"},
+		})
+	}
+	names := func(p *model.Proxy) (listeners, clusters map[string]bool) {
+		listeners, clusters = map[string]bool{}, map[string]bool{}
+		for _, name := range xdstest.ExtractListenerNames(cg.Listeners(p)) {
+			listeners[name] = true
+		}
+		for name := range xdstest.ExtractClusters(cg.Clusters(p)) {
+			clusters[name] = true
+		}
+		return
+	}
+
+	listeners, clusters := names(gateway("network-1"))
+	assert.Equal(t, listeners[EastWestPassthrough], false)
+	assert.Equal(t, clusters[EastWestPassthrough], false)
+
+	test.SetForTest(t, &features.EnableHBONEEastWest, true)
+	listeners, clusters = names(gateway("network-1"))
+	assert.Equal(t, listeners[EastWestPassthrough], true)
+	assert.Equal(t, clusters[EastWestPassthrough], true)
+	// The gateway relays the tunnels without terminating them
+	assert.Equal(t, listeners[ConnectTerminate], false)
+
+	// Only the gateways of a network relay HBONE
+	listeners, _ = names(gateway(""))
+	assert.Equal(t, listeners[EastWestPassthrough], false)
+}
+
+func TestEastWestHBONEOriginate(t *testing.T) {
+	test.SetForTest(t, &features.EnableHBONE, true)
+	test.SetForTest(t, &features.EnableHBONEEastWest, true)
+	cg := NewConfigGenTest(t, TestOptions{
+		Gateways: []model.NetworkGateway{
+			{Network: "network-1", Cluster: "cluster-1", Addr: "1.1.1.1", Port: 15443, HBONEPort: 15008},
+			{Network: "network-2", Cluster: "cluster-2", Addr: "2.2.2.2", Port: 15443, HBONEPort: 15008},
+			{Network: "network-3", Cluster: "cluster-3", Addr: "3.3.3.3", Port: 15443},
+		},
+	})
+	proxy := cg.SetupProxy(&model.Proxy{Metadata: &model.NodeMetadata{EnableHBONE: true, Network: "network-1"}})
+
+	// The proxy originates the tunnels to the workloads of network-2 through its gateway, the only remote one
+	// accepting HBONE.
+	name := EastWestOriginateName(model.NetworkGateway{Addr: "2.2.2.2", HBONEPort: 15008})
+	listeners := xdstest.ExtractListenerNames(cg.Listeners(proxy))
+	var originate []string
+	for _, l := range listeners {
+		if strings.HasPrefix(l, EastWestOriginate) {
+			originate = append(originate, l)
+		}
+	}
+	assert.Equal(t, originate, []string{name})
+	c := xdstest.ExtractClusters(cg.Clusters(proxy))[name]
+	assert.Equal(t, c.GetTransportSocket().GetName(), "envoy.transport_sockets.upstream_proxy_protocol")
+}
@@ -38,11 +38,13 @@ import (
 	istionetworking "istio.io/istio/pilot/pkg/networking"
 	istio_route "istio.io/istio/pilot/pkg/networking/core/v1alpha3/route"
 	"istio.io/istio/pilot/pkg/networking/core/v1alpha3/tunnelingconfig"
+	"istio.io/istio/pilot/pkg/networking/plugin/authz"
 	"istio.io/istio/pilot/pkg/networking/telemetry"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	xdsfilters "istio.io/istio/pilot/pkg/xds/filters"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/gateway"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/protocol"
@@ -155,13 +157,18 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 				mutable = mopts.mutable
 			}
 
+			chainOpts := mutableopts[lname].opts.filterChainOpts
 			for cnum := range mutable.FilterChains {
 				if util.IsIstioVersionGE117(builder.node.IstioVersion) {
 					mutable.FilterChains[cnum].TCP = append(mutable.FilterChains[cnum].TCP, xdsfilters.IstioNetworkAuthenticationFilter)
 				}
 				if mutable.FilterChains[cnum].ListenerProtocol == istionetworking.ListenerProtocolTCP {
-					mutable.FilterChains[cnum].TCP = append(mutable.FilterChains[cnum].TCP, builder.authzCustomBuilder.BuildTCP()...)
-					mutable.FilterChains[cnum].TCP = append(mutable.FilterChains[cnum].TCP, builder.authzBuilder.BuildTCP()...)
+					authzCustomBuilder, authzBuilder := builder.authzCustomBuilder, builder.authzBuilder
+					if cnum < len(chainOpts) && chainOpts[cnum].authzBuilder != nil {
+						authzCustomBuilder, authzBuilder = chainOpts[cnum].authzCustomBuilder, chainOpts[cnum].authzBuilder
+					}
+					mutable.FilterChains[cnum].TCP = append(mutable.FilterChains[cnum].TCP, authzCustomBuilder.BuildTCP()...)
+					mutable.FilterChains[cnum].TCP = append(mutable.FilterChains[cnum].TCP, authzBuilder.BuildTCP()...)
 				}
 			}
 		}
@@ -191,6 +198,10 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 		return builder
 	}
 
+	if isHBONEEastWestGateway(builder.node) {
+		listeners = append(listeners, builder.buildEastWestPassthroughListener())
+	}
+
 	builder.gatewayListeners = listeners
 	return builder
 }
@@ -208,10 +219,13 @@ func (configgen *ConfigGeneratorImpl) buildGatewayTCPBasedFilterChains(
 	if p.IsHTTP() {
 		// We have a list of HTTP servers on this port. Build a single listener for the server port.
 		port := &networking.Port{Number: port.Number, Protocol: port.Protocol}
-		opts.filterChainOpts = []*filterChainOpts{
-			configgen.createGatewayHTTPFilterChainOpts(builder.node, port, nil, serversForPort.RouteName,
-				proxyConfig, istionetworking.ListenerProtocolTCP, builder.push),
-		}
+		chainOpts := configgen.createGatewayHTTPFilterChainOpts(builder.node, port, nil, serversForPort.RouteName,
+			proxyConfig, istionetworking.ListenerProtocolTCP, builder.push)
+		chainOpts.tlsContext = builder.gatewayMTLSContext(port)
+		// The plain text HTTP servers on the port share the filter chain, so policies targeting any of their
+		// listeners apply to all of them.
+		chainOpts.httpOpts.authzCustomBuilder, chainOpts.httpOpts.authzBuilder = builder.gatewayAuthzBuilders(serversForPort.Servers...)
+		opts.filterChainOpts = []*filterChainOpts{chainOpts}
 		newFilterChains = append(newFilterChains, istionetworking.FilterChain{
 			ListenerProtocol: istionetworking.ListenerProtocolHTTP,
 		})
@@ -226,8 +240,10 @@ func (configgen *ConfigGeneratorImpl) buildGatewayTCPBasedFilterChains(
 			if gateway.IsHTTPSServerWithTLSTermination(server) {
 				routeName := mergedGateway.TLSServerInfo[server].RouteName
 				// This is a HTTPS server, where we are doing TLS termination. Build a http connection manager with TLS context
-				tcpFilterChainOpts = append(tcpFilterChainOpts, configgen.createGatewayHTTPFilterChainOpts(builder.node, server.Port, server,
-					routeName, proxyConfig, istionetworking.TransportProtocolTCP, builder.push))
+				chainOpts := configgen.createGatewayHTTPFilterChainOpts(builder.node, server.Port, server,
+					routeName, proxyConfig, istionetworking.TransportProtocolTCP, builder.push)
+				chainOpts.httpOpts.authzCustomBuilder, chainOpts.httpOpts.authzBuilder = builder.gatewayAuthzBuilders(server)
+				tcpFilterChainOpts = append(tcpFilterChainOpts, chainOpts)
 				newFilterChains = append(newFilterChains, istionetworking.FilterChain{
 					ListenerProtocol: istionetworking.ListenerProtocolHTTP,
 				})
@@ -235,6 +251,13 @@ func (configgen *ConfigGeneratorImpl) buildGatewayTCPBasedFilterChains(
 				// This is the case of TCP or PASSTHROUGH.
 				tcpChainOpts := configgen.createGatewayTCPFilterChainOpts(builder.node, builder.push,
 					server, port.Number, mergedGateway.GatewayNameForServer[server], tlsHostsByPort)
+				authzCustomBuilder, authzBuilder := builder.gatewayAuthzBuilders(server)
+				for _, chainOpts := range tcpChainOpts {
+					chainOpts.authzCustomBuilder, chainOpts.authzBuilder = authzCustomBuilder, authzBuilder
+					if server.Tls == nil {
+						chainOpts.tlsContext = builder.gatewayMTLSContext(server.Port)
+					}
+				}
 				tcpFilterChainOpts = append(tcpFilterChainOpts, tcpChainOpts...)
 				for i := 0; i < len(tcpChainOpts); i++ {
 					newFilterChains = append(newFilterChains, istionetworking.FilterChain{
@@ -265,8 +288,10 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTP3FilterChains(
 		// Here it is assumed that this HTTP/3 server is a mirror of an existing HTTPS
 		// server. So the same route name would be reused instead of creating new one.
 		routeName := mergedGateway.TLSServerInfo[server].RouteName
-		quicFilterChainOpts = append(quicFilterChainOpts, configgen.createGatewayHTTPFilterChainOpts(builder.node, server.Port, server,
-			routeName, proxyConfig, istionetworking.TransportProtocolQUIC, builder.push))
+		chainOpts := configgen.createGatewayHTTPFilterChainOpts(builder.node, server.Port, server,
+			routeName, proxyConfig, istionetworking.TransportProtocolQUIC, builder.push)
+		chainOpts.httpOpts.authzCustomBuilder, chainOpts.httpOpts.authzBuilder = builder.gatewayAuthzBuilders(server)
+		quicFilterChainOpts = append(quicFilterChainOpts, chainOpts)
 		newFilterChains = append(newFilterChains, istionetworking.FilterChain{
 			// Make sure that this is set to HTTP so that JWT and Authorization
 			// filters that are applied to HTTPS are also applied to this chain.
@@ -279,6 +304,52 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTP3FilterChains(
 	return newFilterChains
 }
 
+// gatewayAuthzBuilders returns the authorization builders for a filter chain serving the given servers, which differ
+// from the builders of the proxy if policies target the Kubernetes Gateway listeners the servers were generated from.
+func (lb *ListenerBuilder) gatewayAuthzBuilders(servers ...*networking.Server) (*authz.Builder, *authz.Builder) {
+	listeners := kubernetesGatewayListeners(lb.node, servers)
+	authzBuilder := authz.NewListenerBuilder(authz.Local, lb.push, lb.node, listeners)
+	if authzBuilder == nil {
+		return nil, nil
+	}
+	return authz.NewListenerBuilder(authz.Custom, lb.push, lb.node, listeners), authzBuilder
+}
+
+// gatewayMTLSContext returns the TLS context of the filter chains of plain text servers on the port, which require
+// Istio mTLS if the port level settings of the PeerAuthentication policies applied to the gateway make the port STRICT.
+// Servers with TLS settings keep them, so TLS-terminated external traffic can be served on other ports.
+func (lb *ListenerBuilder) gatewayMTLSContext(port *networking.Port) *tls.DownstreamTlsContext {
+	if lb.authnBuilder.ForGatewayPort(port.Number) != model.MTLSStrict {
+		return nil
+	}
+	proto := protocol.TLS
+	if protocol.Parse(port.Protocol).IsHTTP() {
+		proto = protocol.HTTPS
+	}
+	server := &networking.Server{
+		Port: &networking.Port{Number: port.Number, Protocol: string(proto)},
+		Tls:  &networking.ServerTLSSettings{Mode: networking.ServerTLSSettings_ISTIO_MUTUAL},
+	}
+	return buildGatewayListenerTLSContext(lb.push.Mesh, server, lb.node, istionetworking.TransportProtocolTCP)
+}
+
+// kubernetesGatewayListeners returns the names of the listeners of the Kubernetes Gateway of the proxy the servers
+// were generated from. Each listener is converted to a Gateway named <gateway>-istio-autogenerated-k8s-gateway-<listener>.
+func kubernetesGatewayListeners(node *model.Proxy, servers []*networking.Server) []string {
+	gateway := node.Labels[constants.GatewayNameLabel]
+	if gateway == "" || node.MergedGateway == nil {
+		return nil
+	}
+	prefix := node.ConfigNamespace + "/" + gateway + "-" + constants.KubernetesGatewayName + "-"
+	var listeners []string
+	for _, server := range servers {
+		if listener, ok := strings.CutPrefix(node.MergedGateway.GatewayNameForServer[server], prefix); ok {
+			listeners = append(listeners, listener)
+		}
+	}
+	return listeners
+}
+
 func getListenerName(bind string, port int, transport istionetworking.TransportProtocol) string {
 	switch transport {
 	case istionetworking.TransportProtocolTCP:
@@ -378,7 +449,7 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTPRouteConfig(node *model.Pr
 		var exists bool
 
 		if virtualServices, exists = gatewayVirtualServices[gatewayName]; !exists {
-			virtualServices = push.VirtualServicesForGateway(node.ConfigNamespace, gatewayName)
+			virtualServices = push.VirtualServicesForCluster(push.VirtualServicesForGateway(node.ConfigNamespace, gatewayName), node.Metadata.ClusterID)
 			gatewayVirtualServices[gatewayName] = virtualServices
 		}
 
@@ -414,6 +485,7 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTPRouteConfig(node *model.Pr
 					log.Debugf("%s omitting routes for virtual service %v/%v due to error: %v", node.ID, virtualService.Namespace, virtualService.Name, err)
 					continue
 				}
+				istio_route.ApplyRouteTracing(push, virtualService, routes)
 				gatewayRoutes[gatewayName][vskey] = routes
 			}
 			// This is the service that is exposed on gateway using VirtualService.
@@ -780,7 +852,7 @@ func buildGatewayNetworkFiltersFromTCPRoutes(node *model.Proxy, push *model.Push
 		gatewayServerHosts[host.Name(hostname)] = true
 	}
 
-	virtualServices := push.VirtualServicesForGateway(node.ConfigNamespace, gateway)
+	virtualServices := push.VirtualServicesForCluster(push.VirtualServicesForGateway(node.ConfigNamespace, gateway), node.Metadata.ClusterID)
 	if len(virtualServices) == 0 {
 		log.Warnf("no virtual service bound to gateway: %v", gateway)
 	}
@@ -845,7 +917,7 @@ func buildGatewayNetworkFiltersFromTLSRoutes(node *model.Proxy, push *model.Push
 			filterChains = append(filterChains, builtAutoPassthroughFilterChains(push, node, node.MergedGateway.TLSServerInfo[server].SNIHosts)...)
 		}
 	} else {
-		virtualServices := push.VirtualServicesForGateway(node.ConfigNamespace, gatewayName)
+		virtualServices := push.VirtualServicesForCluster(push.VirtualServicesForGateway(node.ConfigNamespace, gatewayName), node.Metadata.ClusterID)
 		for _, v := range virtualServices {
 			vsvc := v.Spec.(*networking.VirtualService)
 			// We have two cases here:
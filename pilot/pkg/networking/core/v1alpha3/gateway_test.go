@@ -3387,3 +3387,155 @@ func TestGatewayHCMInternalAddressConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGatewayListenerAuthorizationPolicy(t *testing.T) {
+	cg := NewConfigGenTest(t, TestOptions{ConfigString: `
+apiVersion: networking.istio.io/v1alpha3
+kind: Gateway
+metadata:
+  name: gw-istio-autogenerated-k8s-gateway-http
+  namespace: testns
+spec:
+  selector:
+    istio.io/gateway-name: gw
+  servers:
+  - port:
+      number: 80
+      name: default
+      protocol: HTTP
+    hosts: ["*"]
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: Gateway
+metadata:
+  name: gw-istio-autogenerated-k8s-gateway-admin
+  namespace: testns
+spec:
+  selector:
+    istio.io/gateway-name: gw
+  servers:
+  - port:
+      number: 8080
+      name: default
+      protocol: HTTP
+    hosts: ["*"]
+---
+apiVersion: security.istio.io/v1beta1
+kind: AuthorizationPolicy
+metadata:
+  name: admin-only
+  namespace: testns
+  annotations:
+    security.istio.io/target-gateway: gw
+    security.istio.io/target-section-name: admin
+spec:
+  rules:
+  - from:
+    - source:
+        principals: ["cluster.local/ns/testns/sa/admin"]
+`})
+	proxy := cg.SetupProxy(&pilot_model.Proxy{
+		Type:            pilot_model.Router,
+		ConfigNamespace: "testns",
+		Labels:          map[string]string{"istio.io/gateway-name": "gw"},
+		Metadata: &pilot_model.NodeMetadata{
+			Namespace: "testns",
+			Labels:    map[string]string{"istio.io/gateway-name": "gw"},
+		},
+	})
+	listeners := cg.ConfigGen.buildGatewayListeners(NewListenerBuilder(proxy, cg.PushContext())).gatewayListeners
+
+	hasRBAC := func(name string) bool {
+		l := xdstest.ExtractListener(name, listeners)
+		if l == nil {
+			t.Fatalf("listener %s not found in %v", name, xdstest.ExtractListenerNames(listeners))
+		}
+		for _, f := range xdstest.ExtractHTTPConnectionManager(t, l.FilterChains[0]).HttpFilters {
+			if f.Name == wellknown.HTTPRoleBasedAccessControl {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasRBAC("0.0.0.0_8080") {
+		t.Errorf("expected the policy to apply to the admin listener")
+	}
+	if hasRBAC("0.0.0.0_80") {
+		t.Errorf("expected the policy not to apply to the http listener")
+	}
+}
+
+func TestGatewayPortLevelPeerAuthentication(t *testing.T) {
+	cg := NewConfigGenTest(t, TestOptions{ConfigString: `
+apiVersion: networking.istio.io/v1alpha3
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: not-default
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 80
+      name: http
+      protocol: HTTP
+    hosts: ["*"]
+  - port:
+      number: 8080
+      name: internal
+      protocol: HTTP
+    hosts: ["*"]
+  - port:
+      number: 443
+      name: https
+      protocol: HTTPS
+    hosts: ["*"]
+    tls:
+      mode: SIMPLE
+      credentialName: external
+---
+apiVersion: security.istio.io/v1beta1
+kind: PeerAuthentication
+metadata:
+  name: gateway
+  namespace: not-default
+spec:
+  selector:
+    matchLabels:
+      istio: ingressgateway
+  mtls:
+    mode: STRICT
+  portLevelMtls:
+    8080:
+      mode: STRICT
+    443:
+      mode: STRICT
+`})
+	proxy := cg.SetupProxy(&proxyGateway)
+	listeners := cg.ConfigGen.buildGatewayListeners(NewListenerBuilder(proxy, cg.PushContext())).gatewayListeners
+
+	tlsContext := func(name string) *auth.DownstreamTlsContext {
+		l := xdstest.ExtractListener(name, listeners)
+		if l == nil {
+			t.Fatalf("listener %s not found in %v", name, xdstest.ExtractListenerNames(listeners))
+		}
+		ts := l.FilterChains[0].GetTransportSocket()
+		if ts == nil {
+			return nil
+		}
+		return xdstest.UnmarshalAny[auth.DownstreamTlsContext](t, ts.GetTypedConfig())
+	}
+	// The port level setting requires mTLS on the plain text server.
+	if ctx := tlsContext("0.0.0.0_8080"); ctx == nil || !ctx.GetRequireClientCertificate().GetValue() {
+		t.Errorf("expected mTLS to be required on port 8080, got %v", ctx)
+	}
+	// Other settings do not apply to gateways.
+	if ctx := tlsContext("0.0.0.0_80"); ctx != nil {
+		t.Errorf("expected plain text on port 80, got %v", ctx)
+	}
+	// Servers with TLS settings keep them.
+	if ctx := tlsContext("0.0.0.0_443"); ctx == nil || ctx.GetRequireClientCertificate().GetValue() {
+		t.Errorf("expected TLS without client certificates on port 443, got %v", ctx)
+	}
+}
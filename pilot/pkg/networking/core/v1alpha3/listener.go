@@ -35,6 +35,7 @@ import (
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	istionetworking "istio.io/istio/pilot/pkg/networking"
+	"istio.io/istio/pilot/pkg/networking/plugin/authz"
 	"istio.io/istio/pilot/pkg/networking/util"
 	authnmodel "istio.io/istio/pilot/pkg/security/model"
 	"istio.io/istio/pilot/pkg/serviceregistry/provider"
@@ -114,6 +115,7 @@ func (configgen *ConfigGeneratorImpl) BuildListeners(node *model.Proxy,
 	l := builder.getListeners()
 	if builder.node.EnableHBONE() && !builder.node.IsAmbient() {
 		l = append(l, outboundTunnelListener(builder.node))
+		l = append(l, buildEastWestOriginateListeners(builder.node, builder.push)...)
 	}
 
 	return l
@@ -336,7 +338,7 @@ func (lb *ListenerBuilder) buildSidecarOutboundListeners(node *model.Proxy,
 	for _, egressListener := range node.SidecarScope.EgressListeners {
 
 		services := egressListener.Services()
-		virtualServices := egressListener.VirtualServices()
+		virtualServices := push.VirtualServicesForCluster(egressListener.VirtualServices(), node.Metadata.ClusterID)
 
 		// determine the bindToPort setting for listeners
 		bindToPort := false
@@ -1128,6 +1130,11 @@ type httpListenerOpts struct {
 
 	// Waypoint-specific modifications in HCM
 	isWaypoint bool
+
+	// authzBuilder and authzCustomBuilder replace the authorization builders of the proxy if set, for gateway
+	// filter chains serving listeners targeted by authorization policies.
+	authzBuilder       *authz.Builder
+	authzCustomBuilder *authz.Builder
 }
 
 // filterChainOpts describes a filter chain: a set of filters with the same TLS context
@@ -1142,6 +1149,11 @@ type filterChainOpts struct {
 	listenerFilters  []*listener.ListenerFilter
 	networkFilters   []*listener.Filter
 	filterChain      istionetworking.FilterChain
+
+	// authzBuilder and authzCustomBuilder replace the authorization builders of the proxy if set, for gateway
+	// TCP filter chains serving listeners targeted by authorization policies.
+	authzBuilder       *authz.Builder
+	authzCustomBuilder *authz.Builder
 }
 
 // buildListenerOpts are the options required to build a Listener
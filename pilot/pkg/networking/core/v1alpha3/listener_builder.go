@@ -69,6 +69,9 @@ type ListenerBuilder struct {
 	authzBuilder *authz.Builder
 	// authzCustomBuilder provides access to CUSTOM authz configuration for the given proxy.
 	authzCustomBuilder *authz.Builder
+
+	// dynamicForwardProxy is true if some of the services of the proxy are sent through the dynamic forward proxy.
+	dynamicForwardProxy bool
 }
 
 // enabledInspector captures if for a given listener, listener filter inspectors are added
@@ -85,6 +88,7 @@ func NewListenerBuilder(node *model.Proxy, push *model.PushContext) *ListenerBui
 	builder.authnBuilder = authn.NewBuilder(push, node)
 	builder.authzBuilder = authz.NewBuilder(authz.Local, push, node)
 	builder.authzCustomBuilder = authz.NewBuilder(authz.Custom, push, node)
+	builder.dynamicForwardProxy = needsDynamicForwardProxyFilter(node)
 	return builder
 }
 
@@ -371,12 +375,21 @@ func (lb *ListenerBuilder) buildHTTPConnectionManager(httpOpts *httpListenerOpts
 		if features.MetadataExchange && !httpOpts.hbone && !lb.node.IsAmbient() {
 			filters = append(filters, xdsfilters.HTTPMx)
 		}
+		// Tap sessions capture the inbound traffic of the proxy, including the requests denied by authorization policies.
+		if features.EnableTapSessions &&
+			(httpOpts.class == istionetworking.ListenerClassSidecarInbound || httpOpts.class == istionetworking.ListenerClassGateway) {
+			filters = append(filters, xdsfilters.Tap)
+		}
 		// TODO: how to deal with ext-authz? It will be in the ordering twice
-		filters = append(filters, lb.authzCustomBuilder.BuildHTTP(httpOpts.class)...)
+		authzCustomBuilder, authzBuilder := lb.authzCustomBuilder, lb.authzBuilder
+		if httpOpts.authzBuilder != nil {
+			authzCustomBuilder, authzBuilder = httpOpts.authzCustomBuilder, httpOpts.authzBuilder
+		}
+		filters = append(filters, authzCustomBuilder.BuildHTTP(httpOpts.class)...)
 		filters = extension.PopAppend(filters, wasm, extensions.PluginPhase_AUTHN)
 		filters = append(filters, lb.authnBuilder.BuildHTTP(httpOpts.class)...)
 		filters = extension.PopAppend(filters, wasm, extensions.PluginPhase_AUTHZ)
-		filters = append(filters, lb.authzBuilder.BuildHTTP(httpOpts.class)...)
+		filters = append(filters, authzBuilder.BuildHTTP(httpOpts.class)...)
 		// TODO: these feel like the wrong place to insert, but this retains backwards compatibility with the original implementation
 		filters = extension.PopAppend(filters, wasm, extensions.PluginPhase_STATS)
 		filters = extension.PopAppend(filters, wasm, extensions.PluginPhase_UNSPECIFIED_PHASE)
@@ -400,6 +413,11 @@ func (lb *ListenerBuilder) buildHTTPConnectionManager(httpOpts *httpListenerOpts
 	if !httpOpts.isWaypoint {
 		filters = append(filters, lb.push.Telemetry.HTTPFilters(lb.node, httpOpts.class)...)
 	}
+	// The dynamic forward proxy filter must precede the router, it resolves the host of the requests routed to the
+	// wildcard ServiceEntries.
+	if lb.dynamicForwardProxy && httpOpts.class != istionetworking.ListenerClassSidecarInbound {
+		filters = append(filters, buildDynamicForwardProxyFilter(lb.node, lb.push))
+	}
 	// Add EmptySessionFilter so that it can be overridden at route level per service.
 	if features.EnablePersistentSessionFilter && httpOpts.class != istionetworking.ListenerClassSidecarInbound {
 		filters = append(filters, xdsfilters.EmptySessionFilter)
@@ -46,6 +46,7 @@ import (
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	xdsfilters "istio.io/istio/pilot/pkg/xds/filters"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/proto"
@@ -169,6 +170,11 @@ func (lb *ListenerBuilder) buildWaypointInternal(wls []WorkloadAndServices, svcs
 	chains := []*listener.FilterChain{}
 	pre, post := lb.buildWaypointHTTPFilters()
 	for _, svc := range svcs {
+		if svc.GetAddressForProxy(lb.node) == constants.UnspecifiedIP {
+			// Headless services have no VIP to match; their pods are addressed directly and handled by the
+			// direct pod access chain below, which preserves the identity of each backing pod.
+			continue
+		}
 		portMapper := match.NewDestinationPort()
 		for _, port := range svc.Ports {
 			if port.Protocol == protocol.UDP {
@@ -407,7 +413,8 @@ func buildWaypointInboundHTTPRouteConfig(lb *ListenerBuilder, svc *model.Service
 	if svc == nil {
 		return buildSidecarInboundHTTPRouteConfig(lb, cc)
 	}
-	vss := getConfigsForHost(svc.Hostname, lb.node.SidecarScope.EgressListeners[0].VirtualServices())
+	vss := getConfigsForHost(svc.Hostname,
+		lb.push.VirtualServicesForCluster(lb.node.SidecarScope.EgressListeners[0].VirtualServices(), lb.node.Metadata.ClusterID))
 	if len(vss) == 0 {
 		return buildSidecarInboundHTTPRouteConfig(lb, cc)
 	}
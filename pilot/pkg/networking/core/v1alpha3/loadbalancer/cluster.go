@@ -0,0 +1,167 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"math"
+	"sort"
+
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/loadbalancing"
+)
+
+// ApplyClusterWeights distributes the traffic between the clusters the endpoints reside in by their weights. The
+// weights of the endpoints are scaled so the total weight of the endpoints of each cluster is proportional to the
+// weight of the cluster, and the endpoints of the clusters without weight are removed.
+func ApplyClusterWeights(
+	loadAssignment *endpoint.ClusterLoadAssignment,
+	wrappedLocalityLbEndpoints []*WrappedLocalityLbEndpoints,
+	weights loadbalancing.ClusterWeights,
+) {
+	if loadAssignment == nil || len(weights) == 0 {
+		return
+	}
+	// the total weight of the endpoints of each cluster
+	totals := map[cluster.ID]float64{}
+	var total, totalClusterWeight float64
+	for _, wrapped := range wrappedLocalityLbEndpoints {
+		for i, ep := range wrapped.IstioEndpoints {
+			w := float64(lbEndpointWeight(wrapped.LocalityLbEndpoints.LbEndpoints[i]))
+			if totals[ep.Locality.ClusterID] == 0 {
+				totalClusterWeight += float64(weights[ep.Locality.ClusterID])
+			}
+			totals[ep.Locality.ClusterID] += w
+			total += w
+		}
+	}
+	if totalClusterWeight == 0 {
+		// none of the weighted clusters has endpoints; remove them all, like the unmatched localities of distribute
+		for _, wrapped := range wrappedLocalityLbEndpoints {
+			wrapped.IstioEndpoints = nil
+			wrapped.LocalityLbEndpoints.LbEndpoints = nil
+			wrapped.LocalityLbEndpoints.LoadBalancingWeight = nil
+		}
+		return
+	}
+	// scale the weights so the endpoints of a cluster keep their relative weights, bounding the total weight
+	// as the weights of a locality must not exceed uint32 in Envoy
+	scale := math.Min(total*100, math.MaxUint32/2/totalClusterWeight)
+
+	for _, wrapped := range wrappedLocalityLbEndpoints {
+		llb := wrapped.LocalityLbEndpoints
+		var istioEndpoints []*model.IstioEndpoint
+		var lbEndpoints []*endpoint.LbEndpoint
+		var localityWeight uint32
+		for i, ep := range wrapped.IstioEndpoints {
+			clusterWeight := weights[ep.Locality.ClusterID]
+			if clusterWeight == 0 {
+				continue
+			}
+			lbEp := llb.LbEndpoints[i]
+			w := uint32(math.Max(1, math.Ceil(float64(lbEndpointWeight(lbEp))*float64(clusterWeight)*scale/totals[ep.Locality.ClusterID])))
+			// the LbEndpoints are shared with the cache, so they are copied rather than mutated
+			istioEndpoints = append(istioEndpoints, ep)
+			lbEndpoints = append(lbEndpoints, &endpoint.LbEndpoint{
+				HostIdentifier:      lbEp.HostIdentifier,
+				HealthStatus:        lbEp.HealthStatus,
+				Metadata:            lbEp.Metadata,
+				LoadBalancingWeight: &wrappers.UInt32Value{Value: w},
+			})
+			localityWeight += w
+		}
+		wrapped.IstioEndpoints = istioEndpoints
+		llb.LbEndpoints = lbEndpoints
+		if localityWeight > 0 {
+			llb.LoadBalancingWeight = &wrappers.UInt32Value{Value: localityWeight}
+		} else {
+			llb.LoadBalancingWeight = nil
+		}
+	}
+}
+
+// ApplyClusterFailover sets the priority of the endpoints by the failover order of the clusters they reside in: the
+// endpoints of the first cluster get the highest priority, and those of the clusters not listed the lowest.
+func ApplyClusterFailover(
+	loadAssignment *endpoint.ClusterLoadAssignment,
+	wrappedLocalityLbEndpoints []*WrappedLocalityLbEndpoints,
+	order []cluster.ID,
+) {
+	if loadAssignment == nil || len(order) == 0 {
+		return
+	}
+	clusterPriority := make(map[cluster.ID]int, len(order))
+	for i, id := range order {
+		clusterPriority[id] = i
+	}
+	lowestPriority := len(order)
+
+	var localityLbEndpoints []*endpoint.LocalityLbEndpoints
+	// key is priority, value is the index of the LocalityLbEndpoints in localityLbEndpoints
+	priorityMap := map[int][]int{}
+	for _, wrapped := range wrappedLocalityLbEndpoints {
+		// split the endpoints of the locality by the priority of their cluster
+		byPriority := map[int][]int{}
+		for i, ep := range wrapped.IstioEndpoints {
+			priority, f := clusterPriority[ep.Locality.ClusterID]
+			if !f {
+				priority = lowestPriority
+			}
+			byPriority[priority] = append(byPriority[priority], i)
+		}
+		for priority, indexes := range byPriority {
+			out := util.CloneLocalityLbEndpoint(wrapped.LocalityLbEndpoints)
+			out.LbEndpoints = make([]*endpoint.LbEndpoint, 0, len(indexes))
+			var weight uint32
+			for _, i := range indexes {
+				lbEp := wrapped.LocalityLbEndpoints.LbEndpoints[i]
+				out.LbEndpoints = append(out.LbEndpoints, lbEp)
+				weight += lbEndpointWeight(lbEp)
+			}
+			out.LoadBalancingWeight = &wrappers.UInt32Value{Value: weight}
+			out.Priority = uint32(priority)
+			priorityMap[priority] = append(priorityMap[priority], len(localityLbEndpoints))
+			localityLbEndpoints = append(localityLbEndpoints, out)
+		}
+	}
+
+	// since Priorities should range from 0 (highest) to N (lowest) without skipping.
+	priorities := make([]int, 0, len(priorityMap))
+	for priority := range priorityMap {
+		priorities = append(priorities, priority)
+	}
+	sort.Ints(priorities)
+	for i, priority := range priorities {
+		for _, index := range priorityMap[priority] {
+			localityLbEndpoints[index].Priority = uint32(i)
+		}
+	}
+	// keep the output stable, ordered by priority and then by locality
+	sort.SliceStable(localityLbEndpoints, func(i, j int) bool {
+		return localityLbEndpoints[i].Priority < localityLbEndpoints[j].Priority
+	})
+	loadAssignment.Endpoints = localityLbEndpoints
+}
+
+func lbEndpointWeight(ep *endpoint.LbEndpoint) uint32 {
+	if w := ep.GetLoadBalancingWeight().GetValue(); w > 0 {
+		return w
+	}
+	return 1
+}
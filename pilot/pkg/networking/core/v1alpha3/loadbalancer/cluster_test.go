@@ -0,0 +1,142 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/loadbalancing"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+// buildMultiClusterEndpoints returns the endpoints of two localities, the first with endpoints in clusters c1 and
+// c2, and the second in clusters c2 and c3.
+func buildMultiClusterEndpoints() (*endpoint.ClusterLoadAssignment, []*WrappedLocalityLbEndpoints) {
+	localities := []struct {
+		locality  *core.Locality
+		endpoints map[string]cluster.ID
+		order     []string
+	}{
+		{
+			locality:  &core.Locality{Region: "region1"},
+			endpoints: map[string]cluster.ID{"1.1.1.1": "c1", "1.1.1.2": "c1", "2.2.2.1": "c2"},
+			order:     []string{"1.1.1.1", "1.1.1.2", "2.2.2.1"},
+		},
+		{
+			locality:  &core.Locality{Region: "region2"},
+			endpoints: map[string]cluster.ID{"2.2.2.2": "c2", "3.3.3.1": "c3"},
+			order:     []string{"2.2.2.2", "3.3.3.1"},
+		},
+	}
+	cla := &endpoint.ClusterLoadAssignment{ClusterName: "outbound|80||reviews.default.svc.cluster.local"}
+	wrapped := make([]*WrappedLocalityLbEndpoints, 0, len(localities))
+	for _, l := range localities {
+		llb := &endpoint.LocalityLbEndpoints{
+			Locality:            l.locality,
+			LoadBalancingWeight: &wrappers.UInt32Value{Value: uint32(len(l.order))},
+		}
+		w := &WrappedLocalityLbEndpoints{LocalityLbEndpoints: llb}
+		for _, ip := range l.order {
+			llb.LbEndpoints = append(llb.LbEndpoints, &endpoint.LbEndpoint{
+				HostIdentifier:      buildEndpoint(ip),
+				LoadBalancingWeight: &wrappers.UInt32Value{Value: 1},
+			})
+			w.IstioEndpoints = append(w.IstioEndpoints, &model.IstioEndpoint{
+				Address:  ip,
+				Locality: model.Locality{ClusterID: l.endpoints[ip]},
+			})
+		}
+		cla.Endpoints = append(cla.Endpoints, llb)
+		wrapped = append(wrapped, w)
+	}
+	return cla, wrapped
+}
+
+func addresses(llb *endpoint.LocalityLbEndpoints) []string {
+	var out []string
+	for _, ep := range llb.LbEndpoints {
+		out = append(out, ep.GetEndpoint().GetAddress().GetSocketAddress().GetAddress())
+	}
+	return out
+}
+
+func TestApplyClusterWeights(t *testing.T) {
+	cla, wrapped := buildMultiClusterEndpoints()
+	original := cla.Endpoints[0].LbEndpoints[0]
+	ApplyClusterWeights(cla, wrapped, loadbalancing.ClusterWeights{"c1": 80, "c2": 20})
+
+	clusterWeights := map[cluster.ID]uint32{}
+	for i, w := range wrapped {
+		assert.Equal(t, len(w.IstioEndpoints), len(cla.Endpoints[i].LbEndpoints))
+		var localityWeight uint32
+		for j, ep := range w.IstioEndpoints {
+			weight := cla.Endpoints[i].LbEndpoints[j].GetLoadBalancingWeight().GetValue()
+			clusterWeights[ep.Locality.ClusterID] += weight
+			localityWeight += weight
+		}
+		assert.Equal(t, cla.Endpoints[i].GetLoadBalancingWeight().GetValue(), localityWeight)
+	}
+	// the endpoints of c3 are removed
+	assert.Equal(t, addresses(cla.Endpoints[1]), []string{"2.2.2.2"})
+	assert.Equal(t, clusterWeights, map[cluster.ID]uint32{"c1": 40000, "c2": 10000})
+	// the shared endpoints are not mutated
+	assert.Equal(t, original.GetLoadBalancingWeight().GetValue(), uint32(1))
+}
+
+func TestApplyClusterWeightsWithoutEndpoints(t *testing.T) {
+	cla, wrapped := buildMultiClusterEndpoints()
+	ApplyClusterWeights(cla, wrapped, loadbalancing.ClusterWeights{"c4": 100})
+	for _, llb := range cla.Endpoints {
+		assert.Equal(t, len(llb.LbEndpoints), 0)
+		assert.Equal(t, llb.LoadBalancingWeight, nil)
+	}
+}
+
+func TestApplyClusterFailover(t *testing.T) {
+	cla, wrapped := buildMultiClusterEndpoints()
+	ApplyClusterFailover(cla, wrapped, []cluster.ID{"c2", "c1"})
+
+	type group struct {
+		Region    string
+		Priority  uint32
+		Weight    uint32
+		Addresses []string
+	}
+	var got []group
+	for _, llb := range cla.Endpoints {
+		got = append(got, group{llb.Locality.Region, llb.Priority, llb.GetLoadBalancingWeight().GetValue(), addresses(llb)})
+	}
+	assert.Equal(t, got, []group{
+		{"region1", 0, 1, []string{"2.2.2.1"}},
+		{"region2", 0, 1, []string{"2.2.2.2"}},
+		{"region1", 1, 2, []string{"1.1.1.1", "1.1.1.2"}},
+		{"region2", 2, 1, []string{"3.3.3.1"}},
+	})
+
+	// the priorities do not skip the clusters without endpoints
+	cla, wrapped = buildMultiClusterEndpoints()
+	ApplyClusterFailover(cla, wrapped, []cluster.ID{"c4", "c3"})
+	var priorities []uint32
+	for _, llb := range cla.Endpoints {
+		priorities = append(priorities, llb.Priority)
+	}
+	assert.Equal(t, priorities, []uint32{0, 1, 1})
+}
@@ -19,8 +19,20 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	dnsProto "istio.io/istio/pkg/dns/proto"
 	dnsServer "istio.io/istio/pkg/dns/server"
+	"istio.io/pkg/log"
 )
 
+// dnsForwardingRules are the conditional forwarding rules of the DNS proxy of the agents. They are parsed when the
+// package is loaded, before NewServer rejects an invalid PILOT_DNS_FORWARDING_RULES, so the error is only logged here.
+var dnsForwardingRules = func() []*dnsProto.NameTable_ForwardingRule {
+	rules, err := dnsServer.ParseForwardingRules(features.DNSForwardingRules)
+	if err != nil {
+		log.Errorf("ignoring invalid PILOT_DNS_FORWARDING_RULES: %v", err)
+		return nil
+	}
+	return rules
+}()
+
 // BuildNameTable produces a table of hostnames and their associated IPs that can then
 // be used by the agent to resolve DNS. This logic is always active. However, local DNS resolution
 // will only be effective if DNS capture is enabled in the proxy
@@ -29,5 +41,7 @@ func (configgen *ConfigGeneratorImpl) BuildNameTable(node *model.Proxy, push *mo
 		Node:                        node,
 		Push:                        push,
 		MulticlusterHeadlessEnabled: features.MulticlusterHeadlessEnabled,
+		SearchDomains:               features.DNSSearchDomains,
+		ForwardingRules:             dnsForwardingRules,
 	})
 }
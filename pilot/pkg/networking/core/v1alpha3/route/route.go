@@ -23,6 +23,7 @@ import (
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xdsfault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	extauthzhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	xdshttpfault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	xdstype "github.com/envoyproxy/go-control-plane/envoy/type/v3"
@@ -40,12 +41,12 @@ import (
 	"istio.io/istio/pilot/pkg/networking/telemetry"
 	"istio.io/istio/pilot/pkg/networking/util"
 	authz "istio.io/istio/pilot/pkg/security/authz/model"
-	"istio.io/istio/pilot/pkg/util/constant"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/security"
 	"istio.io/istio/pkg/util/grpc"
 	"istio.io/pkg/log"
 )
@@ -104,6 +105,9 @@ func BuildSidecarVirtualHostWrapper(routeCache *Cache, node *model.Proxy, push *
 		hashByDestination, destinationRules := hashForVirtualService(push, node, virtualService)
 		dependentDestinationRules = append(dependentDestinationRules, destinationRules...)
 		wrappers := buildSidecarVirtualHostsForVirtualService(node, virtualService, serviceRegistry, hashByDestination, listenPort, push.Mesh)
+		for _, w := range wrappers {
+			ApplyRouteTracing(push, virtualService, w.Routes)
+		}
 		out = append(out, wrappers...)
 	}
 
@@ -307,19 +311,21 @@ func BuildHTTPRoutesForVirtualService(
 	}
 
 	out := make([]*route.Route, 0, len(vs.Http))
+	// The annotation is parsed once for all the routes of the VirtualService.
+	authzExt := extAuthzContextExtensions(virtualService)
 
 	catchall := false
 	for _, http := range vs.Http {
 		if len(http.Match) == 0 {
 			if r := translateRoute(node, http, nil, listenPort, virtualService, serviceRegistry,
-				hashByDestination, gatewayNames, isHTTP3AltSvcHeaderNeeded, mesh); r != nil {
+				hashByDestination, gatewayNames, isHTTP3AltSvcHeaderNeeded, mesh, authzExt); r != nil {
 				out = append(out, r)
 			}
 			catchall = true
 		} else {
 			for _, match := range http.Match {
 				if r := translateRoute(node, http, match, listenPort, virtualService, serviceRegistry,
-					hashByDestination, gatewayNames, isHTTP3AltSvcHeaderNeeded, mesh); r != nil {
+					hashByDestination, gatewayNames, isHTTP3AltSvcHeaderNeeded, mesh, authzExt); r != nil {
 					out = append(out, r)
 					// This is a catch all path. Routes are matched in order, so we will never go beyond this match
 					// As an optimization, we can just top sending any more routes here.
@@ -341,6 +347,21 @@ func BuildHTTPRoutesForVirtualService(
 	return out, nil
 }
 
+// ApplyRouteTracing sets the sampling percentage of the traces of the routes of the VirtualService targeted by a
+// Telemetry, overriding the sampling of the HTTP connection manager.
+func ApplyRouteTracing(push *model.PushContext, virtualService config.Config, routes []*route.Route) {
+	if push.Telemetry == nil || len(push.Telemetry.RouteTelemetries) == 0 {
+		return
+	}
+	for _, r := range routes {
+		if sampling, f := push.Telemetry.RouteSamplingPercentage(virtualService.Meta, r.Name); f {
+			r.Tracing = &route.Tracing{
+				RandomSampling: translatePercentToFractionalPercent(&networking.Percent{Value: sampling}),
+			}
+		}
+	}
+}
+
 // sourceMatchHttp checks if the sourceLabels or the gateways in a match condition match with the
 // labels for the proxy or the gateway name for which we are generating a route
 func sourceMatchHTTP(match *networking.HTTPMatchRequest, proxyLabels labels.Instance, gatewayNames map[string]bool, proxyNamespace string) bool {
@@ -362,6 +383,22 @@ func sourceMatchHTTP(match *networking.HTTPMatchRequest, proxyLabels labels.Inst
 	return false
 }
 
+// extAuthzContextExtensions returns the context extensions sent to the CUSTOM authorization provider for the HTTP
+// routes of the VirtualService, set with its ext-authz-context-extensions annotation.
+func extAuthzContextExtensions(virtualService config.Config) security.ExtAuthzContextExtensions {
+	value, f := virtualService.Annotations[constants.ExtAuthzContextExtensionsAnnotation]
+	if !f {
+		return nil
+	}
+	ext, err := security.ParseExtAuthzContextExtensions(value)
+	if err != nil {
+		log.Warnf("ignoring %s annotation of virtual service %s/%s: %v",
+			constants.ExtAuthzContextExtensionsAnnotation, virtualService.Namespace, virtualService.Name, err)
+		return nil
+	}
+	return ext
+}
+
 // translateRoute translates HTTP routes
 func translateRoute(
 	node *model.Proxy,
@@ -374,6 +411,7 @@ func translateRoute(
 	gatewayNames map[string]bool,
 	isHTTP3AltSvcHeaderNeeded bool,
 	mesh *meshconfig.MeshConfig,
+	authzExt security.ExtAuthzContextExtensions,
 ) *route.Route {
 	// When building routes, it's okay if the target cluster cannot be
 	// resolved Traffic to such clusters will blackhole.
@@ -427,6 +465,16 @@ func translateRoute(
 		out.TypedPerFilterConfig = make(map[string]*anypb.Any)
 		out.TypedPerFilterConfig[wellknown.Fault] = protoconv.MessageToAny(TranslateFault(in.Fault))
 	}
+	if ext := authzExt.ForRoute(in.Name); len(ext) > 0 {
+		if out.TypedPerFilterConfig == nil {
+			out.TypedPerFilterConfig = make(map[string]*anypb.Any)
+		}
+		out.TypedPerFilterConfig[wellknown.HTTPExternalAuthorization] = protoconv.MessageToAny(&extauthzhttp.ExtAuthzPerRoute{
+			Override: &extauthzhttp.ExtAuthzPerRoute_CheckSettings{
+				CheckSettings: &extauthzhttp.CheckSettings{ContextExtensions: ext},
+			},
+		})
+	}
 
 	if isHTTP3AltSvcHeaderNeeded {
 		http3AltSvcHeader := buildHTTP3AltSvcHeader(listenPort, util.ALPNHttp3OverQUIC)
@@ -932,15 +980,17 @@ func isCatchAllStringMatch(in *networking.StringMatch) bool {
 // or the header format is invalid for generating metadata matcher.
 //
 // The currently only supported header is @request.auth.claims for JWT claims matching. Claims of type string or list of string
-// are supported and nested claims are also supported using `.` as a separator for claim names.
+// are supported and nested claims are also supported using `.` as a separator for claim names, or by surrounding each
+// claim name with brackets, which allows claim names containing `.`.
 // Examples:
 // - `@request.auth.claims.admin` matches the claim "admin".
 // - `@request.auth.claims.group.id` matches the nested claims "group" and "id".
+// - `@request.auth.claims[https://example.com/roles]` matches the claim "https://example.com/roles".
 func translateMetadataMatch(name string, in *networking.StringMatch) *matcher.MetadataMatcher {
-	if !strings.HasPrefix(strings.ToLower(name), constant.HeaderJWTClaim) {
+	claims, err := security.ParseJWTClaimHeader(name)
+	if err != nil {
 		return nil
 	}
-	claims := strings.Split(name[len(constant.HeaderJWTClaim):], ".")
 	return authz.MetadataMatcherForJWTClaims(claims, util.ConvertToEnvoyMatch(in))
 }
 
@@ -56,6 +56,7 @@ type Cache struct {
 	DelegateVirtualServices []model.ConfigHash
 	DestinationRules        []*model.ConsolidatedDestRule
 	EnvoyFilterKeys         []string
+	RouteTelemetries        []model.ConfigHash
 }
 
 func (r *Cache) Type() string {
@@ -86,7 +87,7 @@ func (r *Cache) Cacheable() bool {
 }
 
 func (r *Cache) DependentConfigs() []model.ConfigHash {
-	size := len(r.Services) + len(r.VirtualServices) + len(r.DelegateVirtualServices) + len(r.EnvoyFilterKeys)
+	size := len(r.Services) + len(r.VirtualServices) + len(r.DelegateVirtualServices) + len(r.EnvoyFilterKeys) + len(r.RouteTelemetries)
 	for _, mergedDR := range r.DestinationRules {
 		size += len(mergedDR.GetFrom())
 	}
@@ -116,6 +117,7 @@ func (r *Cache) DependentConfigs() []model.ConfigHash {
 		items := strings.Split(efKey, "/")
 		configs = append(configs, model.ConfigKey{Kind: kind.EnvoyFilter, Name: items[1], Namespace: items[0]}.HashCode())
 	}
+	configs = append(configs, r.RouteTelemetries...)
 	return configs
 }
 
@@ -181,6 +183,12 @@ func (r *Cache) Key() any {
 	}
 	h.Write(Separator)
 
+	for _, rt := range r.RouteTelemetries {
+		h.Write(hashToBytes(rt))
+		h.Write(Separator)
+	}
+	h.Write(Separator)
+
 	return h.Sum64()
 }
 
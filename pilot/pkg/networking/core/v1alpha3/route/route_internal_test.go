@@ -538,6 +538,17 @@ func TestTranslateMetadataMatch(t *testing.T) {
 			in:   &networking.StringMatch{MatchType: &networking.StringMatch_Prefix{Prefix: "prefix"}},
 			want: authz.MetadataMatcherForJWTClaims([]string{"prefix"}, authzmatcher.StringMatcher("prefix*")),
 		},
+		{
+			name: "@request.auth.claims[key1][https://example.com/key2]",
+			in:   &networking.StringMatch{MatchType: &networking.StringMatch_Exact{Exact: "exact"}},
+			want: authz.MetadataMatcherForJWTClaims([]string{"key1", "https://example.com/key2"}, authzmatcher.StringMatcher("exact")),
+		},
+		{
+			name: "@request.auth.claims[key1",
+		},
+		{
+			name: "@request.auth.claims.key1..key2",
+		},
 		{
 			name: "@request.auth.claims.regex",
 			in:   &networking.StringMatch{MatchType: &networking.StringMatch_Regex{Regex: ".+?\\..+?\\..+?"}},
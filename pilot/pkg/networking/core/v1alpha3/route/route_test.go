@@ -21,7 +21,9 @@ import (
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	extauthzhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/onsi/gomega"
 	"google.golang.org/protobuf/types/known/durationpb"
 
@@ -100,6 +102,22 @@ func TestBuildHTTPRoutes(t *testing.T) {
 		}))
 	})
 
+	t.Run("for virtual service with ext authz context extensions", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		cg := v1alpha3.NewConfigGenTest(t, v1alpha3.TestOptions{})
+
+		vs := virtualServicePlain.DeepCopy()
+		vs.Annotations = map[string]string{
+			constants.ExtAuthzContextExtensionsAnnotation: `{"*": {"app": "acme"}}`,
+		}
+		routes, err := route.BuildHTTPRoutesForVirtualService(node(cg), vs, serviceRegistry, nil, 8080, gatewayNames, false, nil)
+		xdstest.ValidateRoutes(t, routes)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(len(routes)).To(gomega.Equal(1))
+		perRoute := xdstest.UnmarshalAny[extauthzhttp.ExtAuthzPerRoute](t, routes[0].TypedPerFilterConfig[wellknown.HTTPExternalAuthorization])
+		g.Expect(perRoute.GetCheckSettings().GetContextExtensions()).To(gomega.Equal(map[string]string{"app": "acme"}))
+	})
+
 	t.Run("for virtual service with changed default timeout", func(t *testing.T) {
 		g := gomega.NewWithT(t)
 		cg := v1alpha3.NewConfigGenTest(t, v1alpha3.TestOptions{})
@@ -88,7 +88,7 @@ func configureTracingFromTelemetry(
 		// use the prior configuration bits of sampling and custom tags
 		h.Tracing = &hcm.HttpConnectionManager_Tracing{}
 		configureSampling(h.Tracing, proxyConfigSamplingValue(proxyCfg))
-		configureCustomTags(h.Tracing, map[string]*telemetrypb.Tracing_CustomTag{}, proxyCfg, proxy)
+		configureCustomTags(h.Tracing, map[string]*telemetrypb.Tracing_CustomTag{}, nil, proxyCfg, proxy)
 		if proxyCfg.GetTracing().GetMaxPathTagLength() != 0 {
 			h.Tracing.MaxPathTagLength = wrapperspb.UInt32(proxyCfg.GetTracing().MaxPathTagLength)
 		}
@@ -105,7 +105,7 @@ func configureTracingFromTelemetry(
 
 	var routerFilterCtx *xdsfilters.RouterFilterContext
 	if spec.Provider != nil {
-		tcfg, rfCtx, err := configureFromProviderConfig(push, proxy, spec.Provider)
+		tcfg, rfCtx, err := configureFromProviderConfig(push, proxy, spec.Provider, spec.TraceContexts)
 		if err != nil {
 			log.Warnf("Not able to configure requested tracing provider %q: %v", spec.Provider.Name, err)
 			return nil, nil
@@ -123,7 +123,7 @@ func configureTracingFromTelemetry(
 	// gracefully fallback to MeshConfig configuration. It will act as an implicit
 	// parent configuration during transition period.
 	configureSampling(h.Tracing, spec.RandomSamplingPercentage)
-	configureCustomTags(h.Tracing, spec.CustomTags, proxyCfg, proxy)
+	configureCustomTags(h.Tracing, spec.CustomTags, spec.LabelTags, proxyCfg, proxy)
 
 	// if there is configured max tag length somewhere, fallback to it.
 	if h.GetTracing().GetMaxPathTagLength() == nil && proxyCfg.GetTracing().GetMaxPathTagLength() != 0 {
@@ -137,6 +137,7 @@ func configureTracingFromTelemetry(
 
 func configureFromProviderConfig(pushCtx *model.PushContext, proxy *model.Proxy,
 	providerCfg *meshconfig.MeshConfig_ExtensionProvider,
+	traceContexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext,
 ) (*hcm.HttpConnectionManager_Tracing, *xdsfilters.RouterFilterContext, error) {
 	var rfCtx *xdsfilters.RouterFilterContext
 	var serviceCluster string
@@ -189,7 +190,7 @@ func configureFromProviderConfig(pushCtx *model.PushContext, proxy *model.Proxy,
 		maxTagLength = provider.Opencensus.GetMaxTagLength()
 		providerName = envoyOpenCensus
 		providerConfig = func() (*anypb.Any, error) {
-			return opencensusConfig(provider.Opencensus)
+			return opencensusConfig(provider.Opencensus, traceContexts)
 		}
 	case *meshconfig.MeshConfig_ExtensionProvider_Skywalking:
 		maxTagLength = 0
@@ -209,7 +210,7 @@ func configureFromProviderConfig(pushCtx *model.PushContext, proxy *model.Proxy,
 		maxTagLength = provider.Stackdriver.GetMaxTagLength()
 		providerName = envoyOpenCensus
 		providerConfig = func() (*anypb.Any, error) {
-			return stackdriverConfig(proxy.Metadata, provider.Stackdriver)
+			return stackdriverConfig(proxy.Metadata, provider.Stackdriver, traceContexts)
 		}
 	case *meshconfig.MeshConfig_ExtensionProvider_Opentelemetry:
 		maxTagLength = provider.Opentelemetry.GetMaxTagLength()
@@ -223,6 +224,12 @@ func configureFromProviderConfig(pushCtx *model.PushContext, proxy *model.Proxy,
 		}
 
 	}
+	// Only the OpenCensus tracer of Envoy, which the stackdriver provider also uses, can change its trace context
+	// formats, the other tracers always propagate their own.
+	if len(traceContexts) > 0 && providerName != envoyOpenCensus {
+		log.Warnf("ignoring the %s annotation for proxy %s: tracing provider %q can not change its trace context formats",
+			constants.TraceContextPropagationAnnotation, proxy.ID, providerCfg.Name)
+	}
 	tracing, err := buildHCMTracing(providerName, maxTagLength, providerConfig)
 	return tracing, rfCtx, err
 }
@@ -263,20 +270,27 @@ func otelConfig(serviceName, hostname, cluster string) (*anypb.Any, error) {
 	return anypb.New(dc)
 }
 
-func opencensusConfig(opencensusProvider *meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider) (*anypb.Any, error) {
+func opencensusConfig(opencensusProvider *meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider,
+	traceContexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext,
+) (*anypb.Any, error) {
+	if len(traceContexts) == 0 {
+		traceContexts = opencensusProvider.GetContext()
+	}
 	oc := &tracingcfg.OpenCensusConfig{
 		OcagentAddress:         fmt.Sprintf("%s:%d", opencensusProvider.GetService(), opencensusProvider.GetPort()),
 		OcagentExporterEnabled: true,
 		// this is incredibly dangerous for proxy stability, as switching provider config for OC providers
 		// is not allowed during the lifetime of a proxy.
-		IncomingTraceContext: convert(opencensusProvider.GetContext()),
-		OutgoingTraceContext: convert(opencensusProvider.GetContext()),
+		IncomingTraceContext: convert(traceContexts),
+		OutgoingTraceContext: convert(traceContexts),
 	}
 
 	return protoconv.MessageToAnyWithError(oc)
 }
 
-func stackdriverConfig(proxyMetaData *model.NodeMetadata, sdProvider *meshconfig.MeshConfig_ExtensionProvider_StackdriverProvider) (*anypb.Any, error) {
+func stackdriverConfig(proxyMetaData *model.NodeMetadata, sdProvider *meshconfig.MeshConfig_ExtensionProvider_StackdriverProvider,
+	traceContexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext,
+) (*anypb.Any, error) {
 	proj, ok := proxyMetaData.PlatformMetadata[platform.GCPProject]
 	if !ok {
 		proj, ok = proxyMetaData.PlatformMetadata[platform.GCPProjectNumber]
@@ -288,8 +302,8 @@ func stackdriverConfig(proxyMetaData *model.NodeMetadata, sdProvider *meshconfig
 	sd := &tracingcfg.OpenCensusConfig{
 		StackdriverExporterEnabled: true,
 		StackdriverProjectId:       proj,
-		IncomingTraceContext:       allContexts,
-		OutgoingTraceContext:       allContexts,
+		IncomingTraceContext:       convert(traceContexts),
+		OutgoingTraceContext:       convert(traceContexts),
 		// supporting dynamic control is considered harmful, as OC can only be configured once per lifetime
 		StdoutExporterEnabled: false,
 		TraceConfig: &opb.TraceConfig{
@@ -568,7 +582,8 @@ func proxyConfigSamplingValue(config *meshconfig.ProxyConfig) float64 {
 }
 
 func configureCustomTags(hcmTracing *hcm.HttpConnectionManager_Tracing,
-	providerTags map[string]*telemetrypb.Tracing_CustomTag, proxyCfg *meshconfig.ProxyConfig, node *model.Proxy,
+	providerTags map[string]*telemetrypb.Tracing_CustomTag, labelTags map[string]string, proxyCfg *meshconfig.ProxyConfig,
+	node *model.Proxy,
 ) {
 	var tags []*tracing.CustomTag
 
@@ -586,6 +601,7 @@ func configureCustomTags(hcmTracing *hcm.HttpConnectionManager_Tracing,
 	} else {
 		tags = append(tags, buildCustomTagsFromProvider(providerTags)...)
 	}
+	tags = append(tags, buildLabelTags(labelTags)...)
 
 	// looping over customTags, a map, results in the returned value
 	// being non-deterministic when multiple tags were defined; sort by the tag name
@@ -642,6 +658,22 @@ func buildCustomTagsFromProvider(providerTags map[string]*telemetrypb.Tracing_Cu
 	return tags
 }
 
+// buildLabelTags returns the literal tags of the values of the labels of the workload, by tag name.
+func buildLabelTags(labelTags map[string]string) []*tracing.CustomTag {
+	tags := make([]*tracing.CustomTag, 0, len(labelTags))
+	for tagName, value := range labelTags {
+		tags = append(tags, &tracing.CustomTag{
+			Tag: tagName,
+			Type: &tracing.CustomTag_Literal_{
+				Literal: &tracing.CustomTag_Literal{
+					Value: value,
+				},
+			},
+		})
+	}
+	return tags
+}
+
 func buildCustomTagsFromProxyConfig(customTags map[string]*meshconfig.Tracing_CustomTag) []*tracing.CustomTag {
 	var tags []*tracing.CustomTag
 
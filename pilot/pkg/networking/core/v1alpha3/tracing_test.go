@@ -97,6 +97,14 @@ func TestConfigureTracing(t *testing.T) {
 			wantRfCtx:       nil,
 			wantReqIDExtCtx: &defaultUUIDExtensionCtx,
 		},
+		{
+			name:            "only telemetry api (with provider) with label tags",
+			inSpec:          fakeTracingSpecWithLabelTags(fakeZipkin(), map[string]string{"team": "payments"}),
+			opts:            fakeOptsOnlyZipkinTelemetryAPI(),
+			want:            fakeTracingConfig(fakeZipkinProvider(clusterName, authority, true), 99.999, 256, append(defaultTracingTags(), fakeTeamTag, fakeEnvTag)),
+			wantRfCtx:       nil,
+			wantReqIDExtCtx: &defaultUUIDExtensionCtx,
+		},
 		{
 			name:            "zipkin enable 64bit trace id",
 			inSpec:          fakeTracingSpec(fakeZipkinEnable64bitTraceID(), 99.999, false, true),
@@ -523,6 +531,13 @@ func tracingSpec(provider *meshconfig.MeshConfig_ExtensionProvider, sampling flo
 	}
 }
 
+func fakeTracingSpecWithLabelTags(provider *meshconfig.MeshConfig_ExtensionProvider, labelTags map[string]string) *model.TracingConfig {
+	t := fakeTracingSpec(provider, 99.999, false, true)
+	t.ClientSpec.LabelTags = labelTags
+	t.ServerSpec.LabelTags = labelTags
+	return t
+}
+
 func fakeTracingSpecWithNilCustomTag(provider *meshconfig.MeshConfig_ExtensionProvider, sampling float64, disableReporting bool,
 	useRequestIDForTraceSampling bool,
 ) *model.TracingConfig {
@@ -584,6 +599,15 @@ var fakeEnvTag = &tracing.CustomTag{
 	},
 }
 
+var fakeTeamTag = &tracing.CustomTag{
+	Tag: "team",
+	Type: &tracing.CustomTag_Literal_{
+		Literal: &tracing.CustomTag_Literal{
+			Value: "payments",
+		},
+	},
+}
+
 func fakeZipkinProvider(expectClusterName, expectAuthority string, enableTraceID bool) *tracingcfg.Tracing_Http {
 	fakeZipkinProviderConfig := &tracingcfg.ZipkinConfig{
 		CollectorCluster:         expectClusterName,
@@ -630,3 +654,38 @@ func fakeDatadogProvider(expectServcieName, expectHostName, expectClusterName st
 		ConfigType: &tracingcfg.Tracing_Http_TypedConfig{TypedConfig: fakeAny},
 	}
 }
+
+func TestOpencensusTraceContexts(t *testing.T) {
+	provider := &meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider{
+		Service: "oc-agent",
+		Port:    55678,
+		Context: []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext{
+			meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_B3,
+		},
+	}
+	contexts := func(traceContexts ...meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext) []tracingcfg.OpenCensusConfig_TraceContext {
+		t.Helper()
+		cfg, err := opencensusConfig(provider, traceContexts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oc := &tracingcfg.OpenCensusConfig{}
+		if err := cfg.UnmarshalTo(oc); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(oc.IncomingTraceContext, oc.OutgoingTraceContext); diff != "" {
+			t.Fatalf("incoming and outgoing trace contexts differ:\n%s", diff)
+		}
+		return oc.IncomingTraceContext
+	}
+
+	if diff := cmp.Diff(contexts(), []tracingcfg.OpenCensusConfig_TraceContext{tracingcfg.OpenCensusConfig_B3}); diff != "" {
+		t.Fatalf("unexpected provider trace contexts:\n%s", diff)
+	}
+	got := contexts(meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_W3C_TRACE_CONTEXT,
+		meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_B3)
+	want := []tracingcfg.OpenCensusConfig_TraceContext{tracingcfg.OpenCensusConfig_TRACE_CONTEXT, tracingcfg.OpenCensusConfig_B3}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected overridden trace contexts:\n%s", diff)
+	}
+}
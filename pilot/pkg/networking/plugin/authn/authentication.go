@@ -52,6 +52,19 @@ func (b *Builder) ForPort(port uint32) authn.MTLSSettings {
 	return b.applier.InboundMTLSSettings(port, b.proxy, b.trustDomains, authn.NoOverride)
 }
 
+// ForGatewayPort returns the mTLS mode set for the port by the port level settings of the PeerAuthentication
+// policies applied to a gateway, or MTLSUnknown if there is none. Other settings do not apply to gateways,
+// whose servers configure TLS.
+func (b *Builder) ForGatewayPort(port uint32) model.MutualTLSMode {
+	if b == nil {
+		return model.MTLSUnknown
+	}
+	if _, f := b.applier.PortLevelSetting()[port]; !f {
+		return model.MTLSUnknown
+	}
+	return b.applier.GetMutualTLSModeForPort(port)
+}
+
 func (b *Builder) ForHBONE() authn.MTLSSettings {
 	if b == nil {
 		return authn.MTLSSettings{
@@ -103,6 +116,9 @@ func (b *Builder) BuildHTTP(class networking.ListenerClass) []*hcm.HttpFilter {
 	if filter := b.applier.JwtFilter(); filter != nil {
 		res = append(res, filter)
 	}
+	if filter := b.applier.JwtClaimHeadersFilter(); filter != nil {
+		res = append(res, filter)
+	}
 	forSidecar := b.proxy.Type == model.SidecarProxy
 	if filter := b.applier.AuthNFilter(forSidecar); filter != nil {
 		res = append(res, filter)
@@ -44,11 +44,28 @@ type Builder struct {
 }
 
 func NewBuilder(actionType ActionType, push *model.PushContext, proxy *model.Proxy) *Builder {
+	policies := push.AuthzPolicies.ListAuthorizationPolicies(proxy.ConfigNamespace, proxy.Labels)
+	return newBuilder(actionType, push, proxy, policies)
+}
+
+// NewListenerBuilder returns a builder for a filter chain serving the given listeners of a Kubernetes Gateway,
+// or nil if no policy targets any of the listeners, in which case the builder of the proxy applies.
+func NewListenerBuilder(actionType ActionType, push *model.PushContext, proxy *model.Proxy, listeners []string) *Builder {
+	if len(listeners) == 0 {
+		return nil
+	}
+	policies, targeted := push.AuthzPolicies.ListAuthorizationPoliciesForListeners(proxy.ConfigNamespace, proxy.Labels, listeners)
+	if !targeted {
+		return nil
+	}
+	return newBuilder(actionType, push, proxy, policies)
+}
+
+func newBuilder(actionType ActionType, push *model.PushContext, proxy *model.Proxy, policies model.AuthorizationPoliciesResult) *Builder {
 	tdBundle := trustdomain.NewBundle(push.Mesh.TrustDomain, push.Mesh.TrustDomainAliases)
 	option := builder.Option{
 		IsCustomBuilder: actionType == Custom,
 	}
-	policies := push.AuthzPolicies.ListAuthorizationPolicies(proxy.ConfigNamespace, proxy.Labels)
 	if !util.IsIstioVersionGE117(proxy.IstioVersion) {
 		option.UseAuthenticated = true
 	}
@@ -35,6 +35,10 @@ type PolicyApplier interface {
 	// It may return nil, if no JWT validation is needed.
 	JwtFilter() *hcm.HttpFilter
 
+	// JwtClaimHeadersFilter returns the HTTP filter, following the JWT filter, joining the list claims copied to
+	// headers. It may return nil, if no claim is copied to headers.
+	JwtClaimHeadersFilter() *hcm.HttpFilter
+
 	// AuthNFilter returns the (authn) HTTP filter to enforce the underlying authentication policy.
 	// It may return nil, if no authentication is needed.
 	AuthNFilter(forSidecar bool) *hcm.HttpFilter
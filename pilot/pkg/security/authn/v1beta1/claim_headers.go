@@ -0,0 +1,107 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+
+	authn_model "istio.io/istio/pilot/pkg/security/model"
+	"istio.io/istio/pilot/pkg/util/protoconv"
+	"istio.io/istio/pkg/config/security"
+)
+
+// claimHeadersScript joins the list claims copied to headers with ",", as the JWT filter only copies the claims of
+// type string, number or bool. It reads the payloads of the validated JWTs from the metadata of the JWT filter, keyed
+// by issuer, and is prepended with the claims table, holding the issuer, claim path and header of each claim.
+const claimHeadersScript = `
+function envoy_on_request(request_handle)
+  local payloads = request_handle:streamInfo():dynamicMetadata():get("` + authn_model.EnvoyJwtFilterName + `")
+  if payloads == nil then
+    return
+  end
+  for _, c in ipairs(claims) do
+    local value = payloads[c.issuer]
+    for _, name in ipairs(c.path) do
+      if type(value) ~= "table" then
+        value = nil
+        break
+      end
+      value = value[name]
+    end
+    if type(value) == "table" and #value > 0 then
+      local values = {}
+      for i, v in ipairs(value) do
+        values[i] = tostring(v)
+      end
+      request_handle:headers():replace(c.header, table.concat(values, ","))
+    end
+  end
+end
+`
+
+// JwtClaimHeadersFilter returns the Lua filter joining the list claims copied to headers by the JWT filter.
+func (a *v1beta1PolicyApplier) JwtClaimHeadersFilter() *hcm.HttpFilter {
+	var claims strings.Builder
+	for _, rule := range a.processedJwtRules {
+		for _, c := range rule.OutputClaimToHeaders {
+			path, err := security.ParseJWTClaimPath(c.Claim)
+			if err != nil {
+				continue
+			}
+			quoted := make([]string, 0, len(path))
+			for _, name := range path {
+				quoted = append(quoted, luaQuote(name))
+			}
+			fmt.Fprintf(&claims, "  {issuer = %s, path = {%s}, header = %s},\n",
+				luaQuote(rule.Issuer), strings.Join(quoted, ", "), luaQuote(strings.ToLower(c.Header)))
+		}
+	}
+	if claims.Len() == 0 {
+		return nil
+	}
+	return &hcm.HttpFilter{
+		Name: authn_model.JwtClaimHeadersFilterName,
+		ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: protoconv.MessageToAny(&lua.Lua{
+			DefaultSourceCode: &core.DataSource{Specifier: &core.DataSource_InlineString{
+				InlineString: "local claims = {\n" + claims.String() + "}\n" + claimHeadersScript,
+			}},
+		})},
+	}
+}
+
+// luaQuote returns the Lua string literal of s, escaping the quotes, backslashes and non printable characters.
+func luaQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
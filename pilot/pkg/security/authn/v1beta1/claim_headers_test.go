@@ -0,0 +1,60 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+
+	"istio.io/api/security/v1beta1"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestJwtClaimHeadersFilter(t *testing.T) {
+	applier := func(claims ...*v1beta1.ClaimToHeader) *v1beta1PolicyApplier {
+		return NewPolicyApplier("root-namespace", []*config.Config{{
+			Spec: &v1beta1.RequestAuthentication{
+				JwtRules: []*v1beta1.JWTRule{{Issuer: "https://secret.foo.com", OutputClaimToHeaders: claims}},
+			},
+		}}, nil, &model.PushContext{}).(*v1beta1PolicyApplier)
+	}
+
+	assert.Equal(t, applier().JwtClaimHeadersFilter() == nil, true)
+
+	filter := applier(
+		&v1beta1.ClaimToHeader{Header: "X-Groups", Claim: "groups"},
+		&v1beta1.ClaimToHeader{Header: "x-roles", Claim: `[realm_access][roles "all"]`},
+	).JwtClaimHeadersFilter()
+	assert.Equal(t, filter.Name, "istio.jwt_claim_headers")
+	cfg := &lua.Lua{}
+	if err := filter.GetTypedConfig().UnmarshalTo(cfg); err != nil {
+		t.Fatal(err)
+	}
+	script := cfg.GetDefaultSourceCode().GetInlineString()
+	assert.Equal(t, strings.HasPrefix(script, `local claims = {
+  {issuer = "https://secret.foo.com", path = {"groups"}, header = "x-groups"},
+  {issuer = "https://secret.foo.com", path = {"realm_access", "roles \"all\""}, header = "x-roles"},
+}
+`), true)
+}
+
+func TestLuaQuote(t *testing.T) {
+	assert.Equal(t, luaQuote(`a"b\c`), `"a\"b\\c"`)
+	assert.Equal(t, luaQuote("a\nb"), `"a\010b"`)
+}
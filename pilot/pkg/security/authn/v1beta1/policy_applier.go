@@ -202,6 +202,16 @@ func (a *v1beta1PolicyApplier) InboundMTLSSettings(
 	}
 }
 
+// envoyClaimName converts the claim copied to a header to the claim name used by the JWT filter,
+// which separates nested claims with ".", e.g. "[group][id]" is converted to "group.id".
+func envoyClaimName(claim string) string {
+	claims, err := security.ParseJWTClaimPath(claim)
+	if err != nil {
+		return claim
+	}
+	return strings.Join(claims, ".")
+}
+
 // convertToEnvoyJwtConfig converts a list of JWT rules into Envoy JWT filter config to enforce it.
 // Each rule is expected corresponding to one JWT issuer (provider).
 // The behavior of the filter should reject all requests with invalid token. On the other hand,
@@ -233,7 +243,7 @@ func convertToEnvoyJwtConfig(jwtRules []*v1beta1.JWTRule, push *model.PushContex
 		for _, claimAndHeader := range jwtRule.OutputClaimToHeaders {
 			provider.ClaimToHeaders = append(provider.ClaimToHeaders, &envoy_jwt.JwtClaimToHeader{
 				HeaderName: claimAndHeader.Header,
-				ClaimName:  claimAndHeader.Claim,
+				ClaimName:  envoyClaimName(claimAndHeader.Claim),
 			})
 		}
 
@@ -850,6 +850,8 @@ func TestJwtFilter(t *testing.T) {
 								OutputClaimToHeaders: []*v1beta1.ClaimToHeader{
 									{Header: "x-jwt-key1", Claim: "value1"},
 									{Header: "x-jwt-key2", Claim: "value2"},
+									{Header: "x-jwt-key3", Claim: "nested.value3"},
+									{Header: "x-jwt-key4", Claim: "[nested][value4]"},
 								},
 							},
 						},
@@ -904,6 +906,8 @@ func TestJwtFilter(t *testing.T) {
 									ClaimToHeaders: []*envoy_jwt.JwtClaimToHeader{
 										{HeaderName: "x-jwt-key1", ClaimName: "value1"},
 										{HeaderName: "x-jwt-key2", ClaimName: "value2"},
+										{HeaderName: "x-jwt-key3", ClaimName: "nested.value3"},
+										{HeaderName: "x-jwt-key4", ClaimName: "nested.value4"},
 									},
 									PayloadInMetadata: "https://secret.foo.com",
 								},
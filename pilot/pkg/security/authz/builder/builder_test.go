@@ -18,6 +18,7 @@ import (
 	"os"
 	"testing"
 
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"google.golang.org/protobuf/proto"
@@ -455,3 +456,38 @@ func node(version *model.IstioVersion) *model.Proxy {
 		IstioVersion: version,
 	}
 }
+
+func TestExtAuthzProviderSettings(t *testing.T) {
+	settings := `[{"provider":"default","metadataContextNamespaces":["envoy.filters.http.jwt_authn"],` +
+		`"initialMetadata":{"x-tenant":"shop","x-env":"prod"}}]`
+	orig := extAuthzSettings
+	var err error
+	if extAuthzSettings, err = parseExtAuthzProviderSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { extAuthzSettings = orig })
+
+	t.Run("grpc", func(t *testing.T) {
+		got := processExtensionProvider(push(t, "http/custom-simple-http-in.yaml", meshConfigGRPC))["default"]
+		if got.err != nil {
+			t.Fatal(got.err)
+		}
+		if ns := got.http.MetadataContextNamespaces; len(ns) != 1 || ns[0] != "envoy.filters.http.jwt_authn" {
+			t.Errorf("unexpected metadata context namespaces %v", ns)
+		}
+		for name, md := range map[string][]*core.HeaderValue{
+			"http": got.http.GetGrpcService().InitialMetadata,
+			"tcp":  got.tcp.GrpcService.InitialMetadata,
+		} {
+			if len(md) != 2 || md[0].Key != "x-env" || md[0].Value != "prod" || md[1].Key != "x-tenant" || md[1].Value != "shop" {
+				t.Errorf("unexpected %s initial metadata %v", name, md)
+			}
+		}
+	})
+	t.Run("http", func(t *testing.T) {
+		got := processExtensionProvider(push(t, "http/custom-simple-http-in.yaml", meshConfigHTTP))["default"]
+		if got.err == nil {
+			t.Fatalf("expected initialMetadata to be rejected for HTTP providers")
+		}
+	})
+}
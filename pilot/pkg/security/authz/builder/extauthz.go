@@ -15,6 +15,7 @@
 package builder
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"sort"
@@ -34,6 +35,7 @@ import (
 
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	"istio.io/istio/pilot/pkg/extensionproviders"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	authzmodel "istio.io/istio/pilot/pkg/security/authz/model"
 	"istio.io/istio/pkg/config/validation"
@@ -64,6 +66,48 @@ var (
 	}()
 )
 
+// extAuthzProviderSettings are the settings of an ext_authz extension provider that are not part of the mesh config.
+type extAuthzProviderSettings struct {
+	Provider                  string            `json:"provider"`
+	MetadataContextNamespaces []string          `json:"metadataContextNamespaces,omitempty"`
+	InitialMetadata           map[string]string `json:"initialMetadata,omitempty"`
+}
+
+// extAuthzSettings are the settings of the ext_authz extension providers, keyed by provider name.
+// The package is initialized before NewServer checks the setting, so an invalid value is logged here and then
+// stops istiod.
+var extAuthzSettings = func() map[string]extAuthzProviderSettings {
+	settings, err := parseExtAuthzProviderSettings(features.ExtAuthzProviderSettings)
+	if err != nil {
+		authzLog.Errorf("Ignoring invalid PILOT_EXT_AUTHZ_PROVIDER_SETTINGS: %v", err)
+	}
+	return settings
+}()
+
+// ValidateExtAuthzProviderSettings returns an error if the PILOT_EXT_AUTHZ_PROVIDER_SETTINGS setting is invalid.
+func ValidateExtAuthzProviderSettings(value string) error {
+	_, err := parseExtAuthzProviderSettings(value)
+	return err
+}
+
+// parseExtAuthzProviderSettings parses the PILOT_EXT_AUTHZ_PROVIDER_SETTINGS setting, by provider name.
+func parseExtAuthzProviderSettings(value string) (map[string]extAuthzProviderSettings, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var settings []extAuthzProviderSettings
+	d := json.NewDecoder(strings.NewReader(value))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&settings); err != nil {
+		return nil, err
+	}
+	ret := make(map[string]extAuthzProviderSettings, len(settings))
+	for _, s := range settings {
+		ret[s.Provider] = s
+	}
+	return ret, nil
+}
+
 type builtExtAuthz struct {
 	http *extauthzhttp.ExtAuthz
 	tcp  *extauthztcp.ExtAuthz
@@ -99,6 +143,10 @@ func processExtensionProvider(push *model.PushContext) map[string]*builtExtAuthz
 		}
 		if parsed == nil {
 			parsed = &builtExtAuthz{}
+		} else if settings, f := extAuthzSettings[config.Name]; f {
+			if err := applyExtAuthzProviderSettings(parsed, settings); err != nil {
+				errs = multierror.Append(errs, multierror.Prefix(err, fmt.Sprintf("failed to apply settings of extension provider %q:", config.Name)))
+			}
 		}
 		parsed.err = errs
 		resolved[config.Name] = parsed
@@ -110,6 +158,41 @@ func processExtensionProvider(push *model.PushContext) map[string]*builtExtAuthz
 	return resolved
 }
 
+// applyExtAuthzProviderSettings applies the settings not part of the mesh config to the ext_authz filters of
+// the provider.
+func applyExtAuthzProviderSettings(parsed *builtExtAuthz, settings extAuthzProviderSettings) error {
+	// The TCP filter sends no metadata context, the namespaces only apply to the HTTP filter.
+	if parsed.http != nil && len(settings.MetadataContextNamespaces) > 0 {
+		parsed.http.MetadataContextNamespaces = settings.MetadataContextNamespaces
+	}
+	if len(settings.InitialMetadata) > 0 {
+		// The HTTP and the TCP filters of a gRPC provider both call the service with the initial metadata.
+		var services []*core.GrpcService
+		for _, grpc := range []*core.GrpcService{parsed.http.GetGrpcService(), parsed.tcp.GetGrpcService()} {
+			if grpc != nil && (len(services) == 0 || services[0] != grpc) {
+				services = append(services, grpc)
+			}
+		}
+		if len(services) == 0 {
+			return fmt.Errorf("initialMetadata is only supported by envoyExtAuthzGrpc providers")
+		}
+		keys := make([]string, 0, len(settings.InitialMetadata))
+		for k := range settings.InitialMetadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if k != strings.ToLower(k) {
+				return fmt.Errorf("initialMetadata key %q must be lower case", k)
+			}
+			for _, grpc := range services {
+				grpc.InitialMetadata = append(grpc.InitialMetadata, &core.HeaderValue{Key: k, Value: settings.InitialMetadata[k]})
+			}
+		}
+	}
+	return nil
+}
+
 func notAllTheSame(names []string) bool {
 	for i := 1; i < len(names); i++ {
 		if names[i-1] != names[i] {
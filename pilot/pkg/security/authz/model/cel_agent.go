@@ -1,3 +1,6 @@
+//go:build agent
+// +build agent
+
 // Copyright Istio Authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -12,9 +15,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package constant
+package model
+
+import (
+	"fmt"
 
-const (
-	// HeaderJWTClaim is the special header name used in virtual service for routing based on JWT claims.
-	HeaderJWTClaim = "@request.auth.claims."
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 )
+
+// CEL conditions are not supported in the istio-agent binary, to isolate the `go-cel` package
+func parseCEL(expr string) (*exprpb.Expr, error) {
+	return nil, fmt.Errorf("CEL expressions are not supported")
+}
@@ -0,0 +1,41 @@
+//go:build !agent
+// +build !agent
+
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+func parseCEL(expr string) (*exprpb.Expr, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Parse(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %v", issues.Err())
+	}
+	parsed, err := cel.AstToParsedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.GetExpr(), nil
+}
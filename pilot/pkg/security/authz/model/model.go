@@ -16,9 +16,11 @@ package model
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	rbacpb "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 
 	authzpb "istio.io/api/security/v1beta1"
 	"istio.io/istio/pilot/pkg/security/trustdomain"
@@ -32,19 +34,22 @@ const (
 	RBACShadowRulesDenyStatPrefix     = "istio_dry_run_deny_"
 	RBACExtAuthzShadowRulesStatPrefix = "istio_ext_authz_"
 
-	attrRequestHeader    = "request.headers"             // header name is surrounded by brackets, e.g. "request.headers[User-Agent]".
-	attrSrcIP            = "source.ip"                   // supports both single ip and cidr, e.g. "10.1.2.3" or "10.1.0.0/16".
-	attrRemoteIP         = "remote.ip"                   // original client ip determined from x-forwarded-for or proxy protocol.
-	attrSrcNamespace     = "source.namespace"            // e.g. "default".
-	attrSrcPrincipal     = "source.principal"            // source identity, e,g, "cluster.local/ns/default/sa/productpage".
-	attrRequestPrincipal = "request.auth.principal"      // authenticated principal of the request.
-	attrRequestAudiences = "request.auth.audiences"      // intended audience(s) for this authentication information.
-	attrRequestPresenter = "request.auth.presenter"      // authorized presenter of the credential.
-	attrRequestClaims    = "request.auth.claims"         // claim name is surrounded by brackets, e.g. "request.auth.claims[iss]".
-	attrDestIP           = "destination.ip"              // supports both single ip and cidr, e.g. "10.1.2.3" or "10.1.0.0/16".
-	attrDestPort         = "destination.port"            // must be in the range [0, 65535].
-	attrConnSNI          = "connection.sni"              // server name indication, e.g. "www.example.com".
-	attrEnvoyFilter      = "experimental.envoy.filters." // an experimental attribute for checking Envoy Metadata directly.
+	attrRequestHeader    = "request.headers"                // header name is surrounded by brackets, e.g. "request.headers[User-Agent]".
+	attrSrcIP            = "source.ip"                      // supports both single ip and cidr, e.g. "10.1.2.3" or "10.1.0.0/16".
+	attrRemoteIP         = "remote.ip"                      // original client ip determined from x-forwarded-for or proxy protocol.
+	attrSrcNamespace     = "source.namespace"               // e.g. "default".
+	attrSrcPrincipal     = "source.principal"               // source identity, e,g, "cluster.local/ns/default/sa/productpage".
+	attrRequestPrincipal = "request.auth.principal"         // authenticated principal of the request.
+	attrRequestAudiences = "request.auth.audiences"         // intended audience(s) for this authentication information.
+	attrRequestPresenter = "request.auth.presenter"         // authorized presenter of the credential.
+	attrRequestClaims    = "request.auth.claims"            // claim name is surrounded by brackets, e.g. "request.auth.claims[iss]".
+	attrDestIP           = "destination.ip"                 // supports both single ip and cidr, e.g. "10.1.2.3" or "10.1.0.0/16".
+	attrDestPort         = "destination.port"               // must be in the range [0, 65535].
+	attrConnSNI          = "connection.sni"                 // server name indication, e.g. "www.example.com".
+	attrEnvoyFilter      = "experimental.envoy.filters."    // an experimental attribute for checking Envoy Metadata directly.
+	attrRequestCEL       = "request.cel"                    // CEL expression on the request attributes, e.g. "request.headers['x-id'] == request.host".
+	attrClientCertSub    = "connection.client_cert.subject" // subject DN of the client certificate, e.g. "CN=client,O=example".
+	attrClientCertSAN    = "connection.client_cert.san"     // first URI or DNS SAN of the client certificate, e.g. "client.example.com".
 
 	// Internal names used to generate corresponding Envoy matcher.
 	methodHeader = ":method"
@@ -68,6 +73,7 @@ type ruleList struct {
 type Model struct {
 	permissions []ruleList
 	principals  []ruleList
+	conditions  ruleList
 }
 
 // New returns a model representing a single authorization policy.
@@ -107,6 +113,13 @@ func New(r *authzpb.Rule) (*Model, error) {
 			basePrincipal.appendLast(requestHeaderGenerator{}, k, when.Values, when.NotValues)
 		case strings.HasPrefix(k, attrRequestClaims):
 			basePrincipal.appendLast(requestClaimGenerator{}, k, when.Values, when.NotValues)
+		case k == attrRequestCEL:
+			// The CEL expressions can not be consolidated into permission or principal, they are
+			// combined into the condition of the policy instead.
+			m.conditions.appendLast(nil, k, when.Values, when.NotValues)
+		case k == attrClientCertSub || k == attrClientCertSAN:
+			// The attributes of the client certificate are only available to the CEL expressions of the condition.
+			m.conditions.appendLast(nil, k, clientCertCEL(k, when.Values), clientCertCEL(k, when.NotValues))
 		default:
 			return nil, fmt.Errorf("unknown attribute %s", when.Key)
 		}
@@ -186,12 +199,82 @@ func (m Model) Generate(forTCP bool, useAuthenticated bool, action rbacpb.RBAC_A
 		return nil, fmt.Errorf("must have at least 1 principal")
 	}
 
+	condition, err := generateCondition(m.conditions, forTCP, action)
+	if err != nil {
+		return nil, err
+	}
+
 	return &rbacpb.Policy{
 		Permissions: permissions,
 		Principals:  principals,
+		Condition:   condition,
 	}, nil
 }
 
+// generateCondition combines the CEL expressions of the rule into a single expression, the values of each
+// condition are ORed and the conditions are ANDed, as for the other attributes.
+func generateCondition(rl ruleList, forTCP bool, action rbacpb.RBAC_Action) (*exprpb.Expr, error) {
+	var and []string
+	for _, r := range rl.rules {
+		if forTCP && r.key == attrRequestCEL {
+			if err := r.checkError(action, fmt.Errorf("%q is HTTP only", r.key)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(r.values) > 0 {
+			and = append(and, celOr(r.values))
+		}
+		if len(r.notValues) > 0 {
+			and = append(and, "!"+celOr(r.notValues))
+		}
+	}
+	if len(and) == 0 {
+		return nil, nil
+	}
+
+	expr, err := parseCEL(strings.Join(and, " && "))
+	if err != nil {
+		return nil, rl.rules[0].checkError(action, err)
+	}
+	return expr, nil
+}
+
+// clientCertCEL returns the CEL expressions matching the attribute of the client certificate against the values,
+// which support a prefix or suffix wildcard as the other string attributes.
+func clientCertCEL(key string, values []string) []string {
+	attrs := []string{"connection.subject_peer_certificate"}
+	if key == attrClientCertSAN {
+		attrs = []string{"connection.uri_san_peer_certificate", "connection.dns_san_peer_certificate"}
+	}
+	exprs := make([]string, 0, len(values))
+	for _, v := range values {
+		var or []string
+		for _, attr := range attrs {
+			switch {
+			case v == "*":
+				or = append(or, attr+" != ''")
+			case strings.HasPrefix(v, "*"):
+				or = append(or, attr+".endsWith("+strconv.Quote(strings.TrimPrefix(v, "*"))+")")
+			case strings.HasSuffix(v, "*"):
+				or = append(or, attr+".startsWith("+strconv.Quote(strings.TrimSuffix(v, "*"))+")")
+			default:
+				or = append(or, attr+" == "+strconv.Quote(v))
+			}
+		}
+		exprs = append(exprs, strings.Join(or, " || "))
+	}
+	return exprs
+}
+
+func celOr(exprs []string) string {
+	var or []string
+	for _, expr := range exprs {
+		or = append(or, "("+expr+")")
+	}
+	return "(" + strings.Join(or, " || ") + ")"
+}
+
 func generatePermission(rl ruleList, forTCP bool, action rbacpb.RBAC_Action) (*rbacpb.Permission, error) {
 	var and []*rbacpb.Permission
 	for _, r := range rl.rules {
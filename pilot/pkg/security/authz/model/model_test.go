@@ -125,6 +125,33 @@ when:
   values: ["10.0.0.1"]
   notValues: ["10.0.0.2"]
 `)
+	celRule := yamlRule(t, `
+to:
+- operation:
+    ports: ["8001"]
+when:
+- key: "request.cel"
+  values: ["request.headers['x-tenant'] in metadata.filter_metadata['istio_authn']['request.auth.claims']['tenant']"]
+  notValues: ["'x-debug' in request.headers"]
+`)
+	invalidCELRule := yamlRule(t, `
+to:
+- operation:
+    ports: ["8001"]
+when:
+- key: "request.cel"
+  values: ["request.headers['x-tenant'] =="]
+`)
+	clientCertRule := yamlRule(t, `
+to:
+- operation:
+    ports: ["8001"]
+when:
+- key: "connection.client_cert.subject"
+  values: ["CN=client,O=example", "*,O=partner"]
+- key: "connection.client_cert.san"
+  notValues: ["blocked.example.com"]
+`)
 
 	cases := []struct {
 		name    string
@@ -255,6 +282,80 @@ when:
 				"td-1/ns/foo/sa/sleep-4",
 			},
 		},
+		{
+			name:   "allow-http-cel",
+			action: rbacpb.RBAC_ALLOW,
+			rule:   celRule,
+			want: []string{
+				"condition:",
+				"x-tenant",
+				"istio_authn",
+				"request.auth.claims",
+				"x-debug",
+				"8001",
+			},
+		},
+		{
+			name:    "allow-tcp-cel",
+			action:  rbacpb.RBAC_ALLOW,
+			forTCP:  true,
+			rule:    celRule,
+			notWant: []string{"8001"},
+		},
+		{
+			name:   "deny-tcp-cel",
+			action: rbacpb.RBAC_DENY,
+			forTCP: true,
+			rule:   celRule,
+			want:   []string{"8001"},
+			notWant: []string{
+				"condition:",
+				"x-tenant",
+			},
+		},
+		{
+			name:   "allow-http-client-cert",
+			action: rbacpb.RBAC_ALLOW,
+			rule:   clientCertRule,
+			want: []string{
+				"condition:",
+				"subject_peer_certificate",
+				"CN=client,O=example",
+				"endsWith",
+				"uri_san_peer_certificate",
+				"dns_san_peer_certificate",
+				"blocked.example.com",
+				"8001",
+			},
+		},
+		{
+			name:   "allow-tcp-client-cert",
+			action: rbacpb.RBAC_ALLOW,
+			forTCP: true,
+			rule:   clientCertRule,
+			want: []string{
+				"condition:",
+				"subject_peer_certificate",
+				"8001",
+			},
+		},
+		{
+			name:   "allow-http-invalid-cel",
+			action: rbacpb.RBAC_ALLOW,
+			rule:   invalidCELRule,
+			notWant: []string{
+				"8001",
+			},
+		},
+		{
+			name:   "deny-http-invalid-cel",
+			action: rbacpb.RBAC_DENY,
+			rule:   invalidCELRule,
+			want:   []string{"8001"},
+			notWant: []string{
+				"condition:",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -56,6 +56,9 @@ const (
 	// EnvoyJwtFilterName is the name of the Envoy JWT filter. This should be the same as the name defined
 	// in https://github.com/envoyproxy/envoy/blob/v1.9.1/source/extensions/filters/http/well_known_names.h#L48
 	EnvoyJwtFilterName = "envoy.filters.http.jwt_authn"
+
+	// JwtClaimHeadersFilterName is the name of the Lua filter joining the list claims copied to headers.
+	JwtClaimHeadersFilterName = "istio.jwt_claim_headers"
 )
 
 var SDSAdsConfig = &core.ConfigSource{
@@ -61,7 +61,7 @@ func (c *Controller) Waypoint(scope model.WaypointScope) sets.Set[netip.Addr] {
 	if !features.EnableAmbientControllers {
 		return res
 	}
-	for _, p := range c.GetRegistries() {
+	for _, p := range c.waypointRegistries(scope) {
 		res = res.Merge(p.Waypoint(scope))
 	}
 	return res
@@ -72,12 +72,40 @@ func (c *Controller) WorkloadsForWaypoint(scope model.WaypointScope) []*model.Wo
 	if !features.EnableAmbientControllers {
 		return res
 	}
-	for _, p := range c.GetRegistries() {
+	for _, p := range c.waypointRegistries(scope) {
 		res = append(res, p.WorkloadsForWaypoint(scope)...)
 	}
 	return res
 }
 
+func (c *Controller) InteropMode(namespace string) string {
+	if !features.EnableAmbientControllers {
+		return ""
+	}
+	for _, p := range c.GetRegistries() {
+		if mode := p.InteropMode(namespace); mode != "" {
+			return mode
+		}
+	}
+	return ""
+}
+
+// waypointRegistries returns the registries to consult for a waypoint scope. If the scope is bound to a cluster,
+// only that cluster's registries are used, as waypoints never serve workloads in other clusters.
+func (c *Controller) waypointRegistries(scope model.WaypointScope) []serviceregistry.Instance {
+	registries := c.GetRegistries()
+	if scope.Cluster == "" {
+		return registries
+	}
+	res := make([]serviceregistry.Instance, 0, len(registries))
+	for _, r := range registries {
+		if r.Cluster().Equals(scope.Cluster) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
 func (c *Controller) AdditionalPodSubscriptions(proxy *model.Proxy, addr, cur sets.Set[types.NamespacedName]) sets.Set[types.NamespacedName] {
 	res := sets.New[types.NamespacedName]()
 	if !features.EnableAmbientControllers {
@@ -233,6 +261,7 @@ func (c *Controller) Services() []*model.Service {
 	// are installed in multiple clusters.
 	smap := make(map[host.Name]int)
 	index := 0
+	clusters := 0
 	services := make([]*model.Service, 0)
 	// Locking Registries list while walking it to prevent inconsistent results
 	for _, r := range c.GetRegistries() {
@@ -241,6 +270,7 @@ func (c *Controller) Services() []*model.Service {
 			index += len(svcs)
 			services = append(services, svcs...)
 		} else {
+			clusters++
 			for _, s := range svcs {
 				previous, ok := smap[s.Hostname]
 				if !ok {
@@ -265,6 +295,9 @@ func (c *Controller) Services() []*model.Service {
 			}
 		}
 	}
+	if features.EnableRemoteServiceAutoAllocation && clusters > 1 {
+		allocateRemoteServiceAddresses(services, clusters)
+	}
 	return services
 }
 
@@ -16,6 +16,7 @@ package aggregate
 
 import (
 	"fmt"
+	"net/netip"
 	"reflect"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"go.uber.org/atomic"
 
 	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/serviceregistry"
 	"istio.io/istio/pilot/pkg/serviceregistry/memory"
@@ -30,7 +32,11 @@ import (
 	"istio.io/istio/pilot/pkg/serviceregistry/provider"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/test/util/retry"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 type mockMeshConfigHolder struct {
@@ -179,6 +185,62 @@ func TestServicesForMultiCluster(t *testing.T) {
 	}
 }
 
+func TestServicesRemoteAutoAllocation(t *testing.T) {
+	test.SetForTest(t, &features.EnableRemoteServiceAutoAllocation, true)
+	kubeService := func(hostname host.Name, address string, clusterID cluster.ID) *model.Service {
+		svc := mock.MakeService(mock.ServiceArgs{Hostname: hostname, Address: address, ClusterID: clusterID})
+		svc.Attributes.ServiceRegistry = provider.Kubernetes
+		return svc
+	}
+	remoteOnly := kubeService(mock.WorldService.Hostname, "10.2.0.0", "cluster-2")
+	ctl := NewController(Options{})
+	ctl.AddRegistry(serviceregistry.Simple{
+		ProviderID:       provider.Kubernetes,
+		ClusterID:        "cluster-1",
+		ServiceDiscovery: memory.NewServiceDiscovery(kubeService(mock.HelloService.Hostname, "10.1.0.0", "cluster-1")),
+		Controller:       &mock.Controller{},
+	})
+	ctl.AddRegistry(serviceregistry.Simple{
+		ProviderID:       provider.Kubernetes,
+		ClusterID:        "cluster-2",
+		ServiceDiscovery: memory.NewServiceDiscovery(kubeService(mock.HelloService.Hostname, "10.1.2.0", "cluster-2"), remoteOnly),
+		Controller:       &mock.Controller{},
+	})
+
+	addresses := map[host.Name]string{}
+	for _, svc := range ctl.Services() {
+		addresses[svc.Hostname] = svc.AutoAllocatedIPv4Address
+	}
+	// Only the service missing from a cluster is allocated an address
+	assert.Equal(t, addresses[mock.HelloService.Hostname], "")
+	worldAddress := addresses[mock.WorldService.Hostname]
+	assert.Equal(t, netip.MustParsePrefix("240.241.0.0/16").Contains(netip.MustParseAddr(worldAddress)), true)
+	assert.Equal(t, remoteOnly.AutoAllocatedIPv4Address, "")
+
+	// The address is stable
+	var world *model.Service
+	for _, svc := range ctl.Services() {
+		if svc.Hostname == mock.WorldService.Hostname {
+			world = svc
+		}
+	}
+	assert.Equal(t, world.AutoAllocatedIPv4Address, worldAddress)
+
+	// The proxies requesting auto allocation resolve the remote service to the allocated address, the others to the
+	// cluster IP of the remote cluster.
+	proxy := func(clusterID cluster.ID, autoAllocate bool) *model.Proxy {
+		p := &model.Proxy{
+			Metadata:    &model.NodeMetadata{ClusterID: clusterID, DNSCapture: true, DNSAutoAllocate: model.StringBool(autoAllocate)},
+			IPAddresses: []string{"10.0.0.1"},
+		}
+		p.DiscoverIPMode()
+		return p
+	}
+	assert.Equal(t, world.GetAddressForProxy(proxy("cluster-1", true)), worldAddress)
+	assert.Equal(t, world.GetAddressForProxy(proxy("cluster-1", false)), "10.2.0.0")
+	assert.Equal(t, world.GetAddressForProxy(proxy("cluster-2", true)), "10.2.0.0")
+}
+
 func TestServices(t *testing.T) {
 	aggregateCtl := buildMockController()
 	// List Services from aggregate controller
@@ -508,3 +570,49 @@ func TestDeferredRun(t *testing.T) {
 		expectRunningOrFail(t, ctrl, true)
 	})
 }
+
+// waypointDiscovery is a ServiceDiscovery with a static set of waypoints and workloads.
+type waypointDiscovery struct {
+	*memory.ServiceDiscovery
+	waypoint  netip.Addr
+	workloads []*model.WorkloadInfo
+}
+
+func (w waypointDiscovery) Waypoint(model.WaypointScope) sets.Set[netip.Addr] {
+	return sets.New(w.waypoint)
+}
+
+func (w waypointDiscovery) WorkloadsForWaypoint(model.WaypointScope) []*model.WorkloadInfo {
+	return w.workloads
+}
+
+func TestWaypointMultiCluster(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	ctls := NewController(Options{})
+	for _, c := range []string{"cluster-1", "cluster-2"} {
+		ctls.AddRegistry(serviceregistry.Simple{
+			ProviderID: provider.Kubernetes,
+			ClusterID:  cluster.ID(c),
+			ServiceDiscovery: waypointDiscovery{
+				ServiceDiscovery: memory.NewServiceDiscovery(),
+				waypoint:         netip.MustParseAddr(map[string]string{"cluster-1": "10.0.0.1", "cluster-2": "10.0.0.2"}[c]),
+				workloads:        []*model.WorkloadInfo{{Workload: &workloadapi.Workload{Name: "pod-" + c}}},
+			},
+			Controller: &mock.Controller{},
+		})
+	}
+
+	// Without a cluster, all waypoints are returned
+	assert.Equal(t, ctls.Waypoint(model.WaypointScope{Namespace: "ns"}),
+		sets.New(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")))
+	assert.Equal(t, len(ctls.WorkloadsForWaypoint(model.WaypointScope{Namespace: "ns"})), 2)
+
+	// With a cluster, only the local waypoint serves the workloads
+	scope := model.WaypointScope{Namespace: "ns", Cluster: "cluster-2"}
+	assert.Equal(t, ctls.Waypoint(scope), sets.New(netip.MustParseAddr("10.0.0.2")))
+	wls := ctls.WorkloadsForWaypoint(scope)
+	assert.Equal(t, len(wls), 1)
+	assert.Equal(t, wls[0].Name, "pod-cluster-2")
+
+	assert.Equal(t, len(ctls.Waypoint(model.WaypointScope{Namespace: "ns", Cluster: "cluster-3"})), 0)
+}
@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregate
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry/provider"
+	"istio.io/pkg/log"
+)
+
+// remoteServiceSlots is the number of addresses of the 240.241.0.0/16 range, next to the 240.240.0.0/16 range
+// allocated to the ServiceEntries, excluding the .0 and .255 addresses.
+const remoteServiceSlots = 256 * 254
+
+// allocateRemoteServiceAddresses allocates an address to the Kubernetes services missing from some of the clusters,
+// so the proxies of these clusters can resolve and call them although they have no cluster IP there, as if a
+// ServiceEntry was created for them in every cluster.
+//
+// The address is derived from the hostname, so it is kept as long as the hostname does not collide with the one of
+// another service.
+func allocateRemoteServiceAddresses(services []*model.Service, clusters int) {
+	var candidates []int
+	for i, svc := range services {
+		if svc.Attributes.ServiceRegistry == provider.Kubernetes && svc.Resolution != model.Passthrough &&
+			!svc.Hostname.IsWildCarded() && len(svc.ClusterVIPs.GetAddresses()) < clusters {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return services[candidates[i]].Hostname < services[candidates[j]].Hostname
+	})
+
+	allocated := make(map[uint32]bool, len(candidates))
+	for _, i := range candidates {
+		if len(allocated) == remoteServiceSlots {
+			log.Errorf("out of addresses to allocate for remote services")
+			return
+		}
+		hash := fnv.New32a()
+		hash.Write([]byte(services[i].Hostname))
+		slot := hash.Sum32() % remoteServiceSlots
+		for allocated[slot] {
+			slot = (slot + 1) % remoteServiceSlots
+		}
+		allocated[slot] = true
+
+		// The services are shared with the registries, they must not be modified.
+		svc := services[i].DeepCopy()
+		a, b := slot/254, slot%254+1
+		svc.AutoAllocatedIPv4Address = fmt.Sprintf("240.241.%d.%d", a, b)
+		svc.AutoAllocatedIPv6Address = fmt.Sprintf("2001:2::f0f1:%x", a<<8|b)
+		services[i] = svc
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+
+	"istio.io/api/security/v1beta1"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
+)
+
+// ztunnel only understands authorization policies, so PeerAuthentication is enforced by converting it into DENY policies
+// rejecting plaintext traffic, which has no peer principal. These are attached to the selected workloads explicitly,
+// as the mode of a workload depends on the combination of its mesh, namespace, and workload level policies.
+const (
+	// convertedPeerAuthenticationPrefix prefixes the name of policies converted from a workload level PeerAuthentication.
+	// '_' is not allowed in Kubernetes names, so these cannot conflict with an AuthorizationPolicy.
+	convertedPeerAuthenticationPrefix = "converted_peer_authentication_"
+	// staticStrictPolicyName is the name of the policy, in the root namespace, rejecting all plaintext traffic to
+	// workloads with STRICT mutual TLS and no port level exceptions.
+	staticStrictPolicyName = "istio_converted_static_strict"
+)
+
+func convertedPeerAuthenticationName(name string) string {
+	return convertedPeerAuthenticationPrefix + name
+}
+
+// PeerAuthenticationHandler updates the workloads whose mutual TLS mode may have changed, along with their converted policies.
+func (c *Controller) PeerAuthenticationHandler(old config.Config, obj config.Config, ev model.Event) {
+	// A namespace or mesh level policy changes the mode inherited by every workload in its scope, so unlike
+	// AuthorizationPolicy all workloads in the namespace are recomputed.
+	ns := obj.Namespace
+	if ns == c.meshWatcher.Mesh().GetRootNamespace() {
+		ns = metav1.NamespaceAll
+	}
+	var workloadEntries []*model.WorkloadInstance
+	c.workloadInstancesIndex.ForEach(func(si *model.WorkloadInstance) {
+		if ns == metav1.NamespaceAll || si.Namespace == ns {
+			workloadEntries = append(workloadEntries, si)
+		}
+	})
+	updates := c.updateWorkloadPolicies(c.podsClient.List(ns, klabels.Everything()), workloadEntries)
+
+	// The converted policies depend on the inherited mode as well. The changed policy and the static policy are always
+	// included, so that they are removed if they no longer apply.
+	rootns := c.meshWatcher.Mesh().GetRootNamespace()
+	updates[model.ConfigKey{Kind: kind.AuthorizationPolicy, Name: staticStrictPolicyName, Namespace: rootns}] = struct{}{}
+	updates[model.ConfigKey{Kind: kind.AuthorizationPolicy, Name: convertedPeerAuthenticationName(obj.Name), Namespace: obj.Namespace}] = struct{}{}
+	for _, cfg := range c.configController.List(gvk.PeerAuthentication, ns) {
+		updates[model.ConfigKey{Kind: kind.AuthorizationPolicy, Name: convertedPeerAuthenticationName(cfg.Name), Namespace: cfg.Namespace}] = struct{}{}
+	}
+
+	c.opts.XDSUpdater.ConfigUpdate(&model.PushRequest{
+		ConfigsUpdated: updates,
+		Reason:         []model.TriggerReason{model.AmbientUpdate},
+	})
+}
+
+// peerAuthenticationPolicies returns the converted policies enforcing the PeerAuthentication of a workload.
+func (c *Controller) peerAuthenticationPolicies(ns string, lbls map[string]string) []string {
+	inherited := c.inheritedPeerAuthenticationMode(ns)
+	mode := inherited
+	if cfg := oldestPeerAuthentication(c.configController.List(gvk.PeerAuthentication, ns), func(pa *v1beta1.PeerAuthentication) bool {
+		return pa.Selector != nil && labels.Instance(pa.Selector.MatchLabels).SubsetOf(lbls)
+	}); cfg != nil {
+		if len(cfg.Spec.(*v1beta1.PeerAuthentication).PortLevelMtls) > 0 {
+			if pol := convertPeerAuthentication(*cfg, inherited); pol != nil {
+				return []string{pol.Namespace + "/" + pol.Name}
+			}
+			return nil
+		}
+		if m := cfg.Spec.(*v1beta1.PeerAuthentication).GetMtls().GetMode(); m != v1beta1.PeerAuthentication_MutualTLS_UNSET {
+			mode = m
+		}
+	}
+	if mode == v1beta1.PeerAuthentication_MutualTLS_STRICT {
+		return []string{c.meshWatcher.Mesh().GetRootNamespace() + "/" + staticStrictPolicyName}
+	}
+	return nil
+}
+
+// convertedPeerAuthenticationPolicies returns the policies converted from PeerAuthentication.
+func (c *Controller) convertedPeerAuthenticationPolicies(requested sets.Set[model.ConfigKey]) []*workloadapi.Authorization {
+	rootns := c.meshWatcher.Mesh().GetRootNamespace()
+	isRequested := func(ns, name string) bool {
+		return len(requested) == 0 || requested.Contains(model.ConfigKey{Kind: kind.AuthorizationPolicy, Name: name, Namespace: ns})
+	}
+	var res []*workloadapi.Authorization
+	// The static policy is only needed once some workload may be STRICT.
+	anyStrict := false
+	inherited := map[string]v1beta1.PeerAuthentication_MutualTLS_Mode{}
+	for _, cfg := range c.configController.List(gvk.PeerAuthentication, metav1.NamespaceAll) {
+		if cfg.Spec.(*v1beta1.PeerAuthentication).GetMtls().GetMode() == v1beta1.PeerAuthentication_MutualTLS_STRICT {
+			anyStrict = true
+		}
+		if !isRequested(cfg.Namespace, convertedPeerAuthenticationName(cfg.Name)) {
+			continue
+		}
+		mode, f := inherited[cfg.Namespace]
+		if !f {
+			mode = c.inheritedPeerAuthenticationMode(cfg.Namespace)
+			inherited[cfg.Namespace] = mode
+		}
+		if pol := convertPeerAuthentication(cfg, mode); pol != nil {
+			res = append(res, pol)
+		}
+	}
+	if anyStrict && isRequested(rootns, staticStrictPolicyName) {
+		res = append(res, staticStrictPolicy(rootns))
+	}
+	return res
+}
+
+// inheritedPeerAuthenticationMode returns the mutual TLS mode of workloads in the namespace without a workload level policy.
+func (c *Controller) inheritedPeerAuthenticationMode(ns string) v1beta1.PeerAuthentication_MutualTLS_Mode {
+	for _, n := range []string{ns, c.meshWatcher.Mesh().GetRootNamespace()} {
+		cfg := oldestPeerAuthentication(c.configController.List(gvk.PeerAuthentication, n), func(pa *v1beta1.PeerAuthentication) bool {
+			return pa.Selector == nil
+		})
+		if cfg == nil {
+			continue
+		}
+		if mode := cfg.Spec.(*v1beta1.PeerAuthentication).GetMtls().GetMode(); mode != v1beta1.PeerAuthentication_MutualTLS_UNSET {
+			return mode
+		}
+	}
+	return v1beta1.PeerAuthentication_MutualTLS_PERMISSIVE
+}
+
+// oldestPeerAuthentication returns the oldest matching policy. As with sidecars, when multiple policies apply
+// at the same level, the oldest one is used.
+func oldestPeerAuthentication(cfgs []config.Config, matches func(pa *v1beta1.PeerAuthentication) bool) *config.Config {
+	var oldest *config.Config
+	for i := range cfgs {
+		cfg := &cfgs[i]
+		if !matches(cfg.Spec.(*v1beta1.PeerAuthentication)) {
+			continue
+		}
+		if oldest == nil || cfg.CreationTimestamp.Before(oldest.CreationTimestamp) ||
+			(cfg.CreationTimestamp.Equal(oldest.CreationTimestamp) && cfg.Name < oldest.Name) {
+			oldest = cfg
+		}
+	}
+	return oldest
+}
+
+// convertPeerAuthentication converts a workload level PeerAuthentication with port level mutual TLS into a DENY policy
+// rejecting plaintext traffic on the ports where STRICT applies. Ports without a mode, like the policy itself without
+// a mode, inherit the mode of the namespace or mesh. Other policies are enforced by the static strict policy instead.
+func convertPeerAuthentication(cfg config.Config, inherited v1beta1.PeerAuthentication_MutualTLS_Mode) *workloadapi.Authorization {
+	pa := cfg.Spec.(*v1beta1.PeerAuthentication)
+	if pa.Selector == nil || len(pa.PortLevelMtls) == 0 {
+		return nil
+	}
+	mode := pa.GetMtls().GetMode()
+	if mode == v1beta1.PeerAuthentication_MutualTLS_UNSET {
+		mode = inherited
+	}
+	strict := mode == v1beta1.PeerAuthentication_MutualTLS_STRICT
+	// exceptions are the ports whose mode differs from the workload mode.
+	var exceptions []uint32
+	for port, mtls := range pa.PortLevelMtls {
+		portMode := mtls.GetMode()
+		if portMode == v1beta1.PeerAuthentication_MutualTLS_UNSET {
+			portMode = mode
+		}
+		if (portMode == v1beta1.PeerAuthentication_MutualTLS_STRICT) != strict {
+			exceptions = append(exceptions, port)
+		}
+	}
+	sort.Slice(exceptions, func(i, j int) bool { return exceptions[i] < exceptions[j] })
+
+	match := plaintextMatch()
+	if strict {
+		match.NotDestinationPorts = exceptions
+	} else {
+		if len(exceptions) == 0 {
+			// No port is STRICT, so there is nothing to enforce.
+			return nil
+		}
+		match.DestinationPorts = exceptions
+	}
+	return denyPolicy(cfg.Namespace, convertedPeerAuthenticationName(cfg.Name), match)
+}
+
+func staticStrictPolicy(rootns string) *workloadapi.Authorization {
+	return denyPolicy(rootns, staticStrictPolicyName, plaintextMatch())
+}
+
+// plaintextMatch matches traffic without a peer principal, which is only the case for plaintext traffic.
+func plaintextMatch() *workloadapi.Match {
+	return &workloadapi.Match{
+		NotPrincipals: []*workloadapi.StringMatch{{MatchType: &workloadapi.StringMatch_Presence{Presence: &emptypb.Empty{}}}},
+	}
+}
+
+func denyPolicy(ns, name string, match *workloadapi.Match) *workloadapi.Authorization {
+	return &workloadapi.Authorization{
+		Name:      name,
+		Namespace: ns,
+		Scope:     workloadapi.Scope_WORKLOAD_SELECTOR,
+		Action:    workloadapi.Action_DENY,
+		Groups: []*workloadapi.Group{{
+			Rules: []*workloadapi.Rules{{
+				Matches: []*workloadapi.Match{match},
+			}},
+		}},
+	}
+}
@@ -54,6 +54,8 @@ type AmbientIndex struct {
 	byService map[string][]*model.WorkloadInfo
 	// byPod indexes by Pod IP address.
 	byPod map[string]*model.WorkloadInfo
+	// byWorkloadEntry indexes WorkloadEntry (typically VM) workloads by IP address.
+	byWorkloadEntry map[string]*model.WorkloadInfo
 
 	// Map of ServiceAccount -> IP
 	// TODO: currently, this is derived from pods. To be agnostic to the implementation,
@@ -75,6 +77,10 @@ func (a *AmbientIndex) Lookup(ip string) []*model.WorkloadInfo {
 	if p, f := a.byPod[ip]; f {
 		return []*model.WorkloadInfo{p}
 	}
+	// ...then at WorkloadEntry
+	if w, f := a.byWorkloadEntry[ip]; f {
+		return []*model.WorkloadInfo{w}
+	}
 	// Fallback to service. Note: these IP ranges should be non-overlapping
 	return a.byService[ip]
 }
@@ -103,7 +109,7 @@ func (a *AmbientIndex) updateWaypoint(scope model.WaypointScope, ipStr string, i
 	addr := netip.MustParseAddr(ipStr).AsSlice()
 	updates := sets.New[model.ConfigKey]()
 	if isDelete {
-		for _, wl := range a.byPod {
+		for _, wl := range a.allWorkloads() {
 			if wl.Labels[constants.ManagedGatewayLabel] == constants.ManagedGatewayMeshControllerLabel {
 				continue
 			}
@@ -128,7 +134,7 @@ func (a *AmbientIndex) updateWaypoint(scope model.WaypointScope, ipStr string, i
 			updates.Merge(c.updateEndpointsOnWaypointChange(wl))
 		}
 	} else {
-		for _, wl := range a.byPod {
+		for _, wl := range a.allWorkloads() {
 			if wl.Labels[constants.ManagedGatewayLabel] == constants.ManagedGatewayMeshControllerLabel {
 				continue
 			}
@@ -158,11 +164,19 @@ func (a *AmbientIndex) updateWaypoint(scope model.WaypointScope, ipStr string, i
 func (a *AmbientIndex) All() []*model.WorkloadInfo {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	res := make([]*model.WorkloadInfo, 0, len(a.byPod))
-	// byPod will not have any duplicates, so we can just iterate over that.
+	return a.allWorkloads()
+}
+
+// allWorkloads returns all pod and WorkloadEntry workloads. The caller must hold the lock.
+func (a *AmbientIndex) allWorkloads() []*model.WorkloadInfo {
+	res := make([]*model.WorkloadInfo, 0, len(a.byPod)+len(a.byWorkloadEntry))
+	// byPod and byWorkloadEntry will not have any duplicates, so we can just iterate over them.
 	for _, wl := range a.byPod {
 		res = append(res, wl)
 	}
+	for _, wl := range a.byWorkloadEntry {
+		res = append(res, wl)
+	}
 	return res
 }
 
@@ -172,7 +186,7 @@ func (c *Controller) WorkloadsForWaypoint(scope model.WaypointScope) []*model.Wo
 	defer a.mu.RUnlock()
 	var res []*model.WorkloadInfo
 	// TODO: try to precompute
-	for _, w := range a.byPod {
+	for _, w := range a.allWorkloads() {
 		if a.matchesScope(scope, w) {
 			res = append(res, w)
 		}
@@ -186,6 +200,8 @@ func (c *Controller) Waypoint(scope model.WaypointScope) sets.Set[netip.Addr] {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	res := sets.New[netip.Addr]()
+	// The index only holds a single cluster, so the cluster is not part of the key.
+	scope.Cluster = ""
 	for ip := range a.waypoints[scope] {
 		res.Insert(netip.MustParseAddr(ip))
 	}
@@ -223,6 +239,32 @@ func (a *AmbientIndex) matchesScope(scope model.WaypointScope, w *model.Workload
 	return true
 }
 
+// InteropMode returns how sidecars send traffic to ambient workloads in the namespace, as configured by the
+// constants.AmbientInteropMode namespace annotation.
+func (c *Controller) InteropMode(namespace string) string {
+	ns := c.namespaces.Get(namespace, "")
+	if ns == nil {
+		return ""
+	}
+	return ns.Annotations[constants.AmbientInteropMode]
+}
+
+// onNamespaceInteropEvent triggers a full push when the interop mode of a namespace changes, as it impacts
+// the configuration of every sidecar sending traffic to the namespace.
+func (c *Controller) onNamespaceInteropEvent(old, cur *v1.Namespace) {
+	prev := ""
+	if old != nil {
+		prev = old.Annotations[constants.AmbientInteropMode]
+	}
+	if prev == cur.Annotations[constants.AmbientInteropMode] {
+		return
+	}
+	c.opts.XDSUpdater.ConfigUpdate(&model.PushRequest{
+		Full:   true,
+		Reason: []model.TriggerReason{model.NamespaceUpdate},
+	})
+}
+
 func (c *Controller) Policies(requested sets.Set[model.ConfigKey]) []*workloadapi.Authorization {
 	if !c.configCluster {
 		return nil
@@ -248,10 +290,14 @@ func (c *Controller) Policies(requested sets.Set[model.ConfigKey]) []*workloadap
 		}
 		res = append(res, pol)
 	}
-	return res
+	return append(res, c.convertedPeerAuthenticationPolicies(requested)...)
 }
 
 func (c *Controller) selectorAuthorizationPolicies(ns string, lbls map[string]string) []string {
+	// Controllers built without a config store, such as in some tests, have no policies to select.
+	if c.configController == nil {
+		return nil
+	}
 	global := c.configController.List(gvk.AuthorizationPolicy, c.meshWatcher.Mesh().GetRootNamespace())
 	local := c.configController.List(gvk.AuthorizationPolicy, ns)
 	res := sets.New[string]()
@@ -270,6 +316,7 @@ func (c *Controller) selectorAuthorizationPolicies(ns string, lbls map[string]st
 			}
 		}
 	}
+	res.InsertAll(c.peerAuthenticationPolicies(ns, lbls)...)
 	return sets.SortedList(res)
 }
 
@@ -299,34 +346,43 @@ func (c *Controller) AuthorizationPolicyHandler(old config.Config, obj config.Co
 		}
 	}
 
-	pods := map[string]*v1.Pod{}
-	for _, p := range c.getPodsInPolicy(obj.Namespace, sel) {
-		pods[p.Status.PodIP] = p
-	}
+	pods := c.getPodsInPolicy(obj.Namespace, sel)
 	if oldSel != nil {
-		for _, p := range c.getPodsInPolicy(obj.Namespace, oldSel) {
-			pods[p.Status.PodIP] = p
-		}
+		pods = append(pods, c.getPodsInPolicy(obj.Namespace, oldSel)...)
 	}
+	updates := c.updateWorkloadPolicies(pods, c.getWorkloadEntriesInPolicy(obj.Namespace, sel, oldSel))
 
+	if len(updates) > 0 {
+		c.opts.XDSUpdater.ConfigUpdate(&model.PushRequest{
+			ConfigsUpdated: updates,
+			Reason:         []model.TriggerReason{model.AmbientUpdate},
+		})
+	}
+}
+
+// updateWorkloadPolicies recomputes the given workloads, which may have new policies, returning the addresses to push.
+func (c *Controller) updateWorkloadPolicies(pods []*v1.Pod, workloadEntries []*model.WorkloadInstance) map[model.ConfigKey]struct{} {
 	updates := map[model.ConfigKey]struct{}{}
-	for ip, pod := range pods {
+	for _, pod := range pods {
 		newWl := c.extractWorkload(pod)
 		if newWl != nil {
 			// Update the pod, since it now has new VIP info
 			c.ambientIndex.mu.Lock()
-			c.ambientIndex.byPod[ip] = newWl
+			c.ambientIndex.byPod[pod.Status.PodIP] = newWl
 			c.ambientIndex.mu.Unlock()
 			updates[model.ConfigKey{Kind: kind.Address, Name: newWl.ResourceName()}] = struct{}{}
 		}
 	}
-
-	if len(updates) > 0 {
-		c.opts.XDSUpdater.ConfigUpdate(&model.PushRequest{
-			ConfigsUpdated: updates,
-			Reason:         []model.TriggerReason{model.AmbientUpdate},
-		})
+	for _, si := range workloadEntries {
+		newWl := c.extractWorkloadEntry(si)
+		if newWl != nil {
+			c.ambientIndex.mu.Lock()
+			c.ambientIndex.byWorkloadEntry[si.Endpoint.Address] = newWl
+			c.ambientIndex.mu.Unlock()
+			updates[model.ConfigKey{Kind: kind.Address, Name: newWl.ResourceName()}] = struct{}{}
+		}
 	}
+	return updates
 }
 
 func (c *Controller) getPodsInPolicy(ns string, sel map[string]string) []*v1.Pod {
@@ -336,8 +392,29 @@ func (c *Controller) getPodsInPolicy(ns string, sel map[string]string) []*v1.Pod
 	return c.podsClient.List(ns, klabels.ValidatedSetSelector(sel))
 }
 
+// getWorkloadEntriesInPolicy returns the WorkloadEntry instances selected by any of the given selectors.
+func (c *Controller) getWorkloadEntriesInPolicy(ns string, selectors ...map[string]string) []*model.WorkloadInstance {
+	var res []*model.WorkloadInstance
+	c.workloadInstancesIndex.ForEach(func(si *model.WorkloadInstance) {
+		if si.Namespace != ns {
+			return
+		}
+		for _, sel := range selectors {
+			if sel != nil && klabels.ValidatedSetSelector(sel).Matches(klabels.Set(si.Endpoint.Labels)) {
+				res = append(res, si)
+				return
+			}
+		}
+	})
+	return res
+}
+
 func convertAuthorizationPolicy(rootns string, obj config.Config) *workloadapi.Authorization {
 	pol := obj.Spec.(*v1beta1.AuthorizationPolicy)
+	if obj.Annotations[constants.TargetGatewayAnnotation] != "" {
+		// Policies targeting a Gateway are enforced by the gateway proxy.
+		return nil
+	}
 
 	scope := workloadapi.Scope_WORKLOAD_SELECTOR
 	if pol.Selector == nil {
@@ -576,6 +653,30 @@ func (c *Controller) extractWorkload(p *v1.Pod) *model.WorkloadInfo {
 	}
 }
 
+// extractWorkloadEntry builds the ambient workload for a WorkloadEntry, allowing VMs to participate in ambient.
+func (c *Controller) extractWorkloadEntry(si *model.WorkloadInstance) *model.WorkloadInfo {
+	if si == nil || si.Kind != model.WorkloadEntryKind || si.Endpoint == nil {
+		return nil
+	}
+	sa := ""
+	if id, err := spiffe.ParseIdentity(si.Endpoint.ServiceAccount); err == nil {
+		sa = id.ServiceAccount
+	}
+	waypoints := c.ambientIndex.waypoints[model.WaypointScope{Namespace: si.Namespace, ServiceAccount: sa}]
+	if len(waypoints) == 0 {
+		waypoints = c.ambientIndex.waypoints[model.WaypointScope{Namespace: si.Namespace}]
+	}
+	policies := c.selectorAuthorizationPolicies(si.Namespace, si.Endpoint.Labels)
+	wl := c.constructWorkloadFromWorkloadEntry(si, sa, sets.SortedList(waypoints), policies)
+	if wl == nil {
+		return nil
+	}
+	return &model.WorkloadInfo{
+		Workload: wl,
+		Labels:   si.Endpoint.Labels,
+	}
+}
+
 // updateEndpointsOnWaypointChange ensures that endpoints are synced for Envoy clients. Envoy clients
 // maintain information about waypoints for each destination in metadata. If the waypoint changes, we need
 // to sync this metadata again (add/remove waypoint IP).
@@ -597,10 +698,12 @@ func (c *Controller) updateEndpointsOnWaypointChange(wl *model.WorkloadInfo) set
 
 func (c *Controller) setupIndex() *AmbientIndex {
 	idx := AmbientIndex{
-		byService: map[string][]*model.WorkloadInfo{},
-		byPod:     map[string]*model.WorkloadInfo{},
-		waypoints: map[model.WaypointScope]sets.String{},
+		byService:       map[string][]*model.WorkloadInfo{},
+		byPod:           map[string]*model.WorkloadInfo{},
+		byWorkloadEntry: map[string]*model.WorkloadInfo{},
+		waypoints:       map[model.WaypointScope]sets.String{},
 	}
+	metrics := controllers.NewMetrics("ambient index", c.Cluster())
 
 	podHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
@@ -631,7 +734,7 @@ func (c *Controller) setupIndex() *AmbientIndex {
 			}
 		},
 	}
-	c.podsClient.AddEventHandler(podHandler)
+	c.podsClient.AddEventHandler(metrics.SyncHandler(kind.Pod, podHandler))
 
 	serviceHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
@@ -677,7 +780,7 @@ func (c *Controller) setupIndex() *AmbientIndex {
 			}
 		},
 	}
-	c.services.AddEventHandler(serviceHandler)
+	c.services.AddEventHandler(metrics.SyncHandler(kind.Service, serviceHandler))
 	idx.serviceVipIndex = kclient.CreateIndex[*v1.Service](c.services, getVIPs)
 	return &idx
 }
@@ -759,6 +862,46 @@ func (a *AmbientIndex) handlePod(oldObj, newObj any, isDelete bool, c *Controlle
 	return updates
 }
 
+// handleWorkloadEntry updates the index for a WorkloadEntry event, returning the addresses that need to be pushed.
+func (a *AmbientIndex) handleWorkloadEntry(si *model.WorkloadInstance, isDelete bool, c *Controller) sets.Set[model.ConfigKey] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	updates := sets.New[model.ConfigKey]()
+	ip := si.Endpoint.Address
+	var wl *model.WorkloadInfo
+	if !isDelete {
+		wl = c.extractWorkloadEntry(si)
+	}
+	oldWl := a.byWorkloadEntry[ip]
+	if wl == nil {
+		delete(a.byWorkloadEntry, ip)
+		if oldWl != nil {
+			for vip := range oldWl.VirtualIps {
+				a.dropWorkloadFromService(vip, ip)
+			}
+			log.Debugf("%v: workload entry removed, pushing", ip)
+			updates.Insert(model.ConfigKey{Kind: kind.Address, Name: ip})
+		}
+		return updates
+	}
+	if oldWl != nil && proto.Equal(wl.Workload, oldWl.Workload) {
+		log.Debugf("%v: no change, skipping", wl.ResourceName())
+		return updates
+	}
+	a.byWorkloadEntry[ip] = wl
+	if oldWl != nil {
+		for vip := range oldWl.VirtualIps {
+			a.dropWorkloadFromService(vip, wl.ResourceName())
+		}
+	}
+	for vip := range wl.VirtualIps {
+		a.insertWorkloadToService(vip, wl)
+	}
+	log.Debugf("%v: workload entry updated, pushing", wl.ResourceName())
+	updates.Insert(model.ConfigKey{Kind: kind.Address, Name: ip})
+	return updates
+}
+
 func (a *AmbientIndex) handlePods(pods []*v1.Pod, c *Controller) {
 	updates := sets.New[model.ConfigKey]()
 	for _, p := range pods {
@@ -785,11 +928,30 @@ func (a *AmbientIndex) handleService(obj any, isDelete bool, c *Controller) map[
 			a.byPod[p.Status.PodIP] = wl
 			wls = append(wls, wl)
 		}
-
+	}
+	for ip, old := range a.byWorkloadEntry {
+		if old.Namespace != svc.Namespace || svc.Spec.Selector == nil ||
+			!klabels.ValidatedSetSelector(svc.Spec.Selector).Matches(klabels.Set(old.Labels)) {
+			continue
+		}
+		for _, si := range c.workloadInstancesIndex.GetByIP(ip) {
+			// Update the WorkloadEntry, since it now has new VIP info
+			if wl := c.extractWorkloadEntry(si); wl != nil {
+				a.byWorkloadEntry[ip] = wl
+				wls = append(wls, wl)
+			}
+		}
 	}
 
 	// We send an update for each *workload* IP address previously in the service; they may have changed
 	updates := map[model.ConfigKey]struct{}{}
+	if len(vips) == 0 {
+		// Headless services have no VIP to index, as their pods are addressed directly. The pods may still have
+		// changed, for example in health if the service publishes not ready addresses, so update them directly.
+		for _, wl := range wls {
+			updates[model.ConfigKey{Kind: kind.Address, Name: wl.ResourceName()}] = struct{}{}
+		}
+	}
 	for _, vip := range vips {
 		for _, wl := range a.byService[vip] {
 			updates[model.ConfigKey{Kind: kind.Address, Name: wl.ResourceName()}] = struct{}{}
@@ -845,7 +1007,8 @@ func (c *Controller) PodInformation(addresses sets.Set[types.NamespacedName]) ([
 func (c *Controller) constructWorkload(pod *v1.Pod, waypoints []string, policies []string) *workloadapi.Workload {
 	vips := map[string]*workloadapi.PortList{}
 	allServices := c.services.List(pod.Namespace, klabels.Everything())
-	if services := getPodServices(allServices, pod); len(services) > 0 {
+	services := getPodServices(allServices, pod)
+	if len(services) > 0 {
 		for _, svc := range services {
 			for _, vip := range getVIPs(svc) {
 				if vips[vip] == nil {
@@ -881,7 +1044,7 @@ func (c *Controller) constructWorkload(pod *v1.Pod, waypoints []string, policies
 		Status:                workloadapi.WorkloadStatus_HEALTHY,
 		ClusterId:             c.Cluster().String(),
 	}
-	if !IsPodReady(pod) {
+	if !IsPodReady(pod) && !publishesNotReadyAddresses(services) {
 		wl.Status = workloadapi.WorkloadStatus_UNHEALTHY
 	}
 	if td := spiffe.GetTrustDomain(); td != "cluster.local" {
@@ -911,6 +1074,94 @@ func (c *Controller) constructWorkload(pod *v1.Pod, waypoints []string, policies
 	return wl
 }
 
+func (c *Controller) constructWorkloadFromWorkloadEntry(si *model.WorkloadInstance, sa string,
+	waypoints []string, policies []string,
+) *workloadapi.Workload {
+	addr := parseIP(si.Endpoint.Address)
+	if addr == nil {
+		// Ambient requires an IP address; DNS addressed WorkloadEntries are not supported.
+		return nil
+	}
+	vips := map[string]*workloadapi.PortList{}
+	allServices := c.services.List(si.Namespace, klabels.Everything())
+	dummyPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: si.Namespace, Labels: si.Endpoint.Labels}}
+	for _, svc := range getPodServices(allServices, dummyPod) {
+		for _, vip := range getVIPs(svc) {
+			if vips[vip] == nil {
+				vips[vip] = &workloadapi.PortList{}
+			}
+			for _, port := range svc.Spec.Ports {
+				if port.Protocol != v1.ProtocolTCP {
+					continue
+				}
+				targetPort := uint32(port.TargetPort.IntValue())
+				if named, f := si.PortMap[port.TargetPort.String()]; f {
+					targetPort = named
+				} else if named, f := si.PortMap[port.Name]; f {
+					targetPort = named
+				}
+				if targetPort == 0 {
+					targetPort = uint32(port.Port)
+				}
+				vips[vip].Ports = append(vips[vip].Ports, &workloadapi.Port{
+					ServicePort: uint32(port.Port),
+					TargetPort:  targetPort,
+				})
+			}
+		}
+	}
+
+	network := si.Endpoint.Network.String()
+	if network == "" {
+		network = c.network.String()
+	}
+	wl := &workloadapi.Workload{
+		Name:                  si.Name,
+		Namespace:             si.Namespace,
+		Address:               addr,
+		Network:               network,
+		ServiceAccount:        sa,
+		VirtualIps:            vips,
+		AuthorizationPolicies: policies,
+		Status:                workloadapi.WorkloadStatus_HEALTHY,
+		ClusterId:             c.Cluster().String(),
+	}
+	if si.Endpoint.HealthStatus == model.UnHealthy {
+		wl.Status = workloadapi.WorkloadStatus_UNHEALTHY
+	}
+	if td := spiffe.GetTrustDomain(); td != "cluster.local" {
+		wl.TrustDomain = td
+	}
+	// There is no VM workload type; like a bare pod, the workload is identified by its own name.
+	wl.WorkloadName, wl.WorkloadType = si.Name, workloadapi.WorkloadType_POD
+	wl.CanonicalName, wl.CanonicalRevision = kubelabels.CanonicalService(si.Endpoint.Labels, wl.WorkloadName)
+	if len(waypoints) > 0 {
+		ips := make([][]byte, 0, len(waypoints))
+		for _, r := range waypoints {
+			ips = append(ips, netip.MustParseAddr(r).AsSlice())
+		}
+		wl.WaypointAddresses = ips
+	}
+	// VMs advertise HBONE support the same way pods do, with the tunnel label on the WorkloadEntry.
+	if model.SupportsTunnel(si.Endpoint.Labels, model.TunnelHTTP) {
+		wl.Protocol = workloadapi.Protocol_HTTP
+		wl.NativeHbone = true
+	}
+	return wl
+}
+
+// publishesNotReadyAddresses returns true if any of the headless services publishes its pods before they are ready.
+// This is typically used by headless services of StatefulSets, whose pods need to discover each other to become ready.
+// Services with a VIP are ignored, as their not ready pods would otherwise receive the traffic load balanced to it.
+func publishesNotReadyAddresses(services []*v1.Service) bool {
+	for _, svc := range services {
+		if svc.Spec.PublishNotReadyAddresses && len(getVIPs(svc)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func parseIP(ip string) []byte {
 	addr, err := netip.ParseAddr(ip)
 	if err != nil {
@@ -997,6 +1248,12 @@ func workloadNameAndType(pod *v1.Pod) (string, workloadapi.WorkloadType) {
 		return name, workloadapi.WorkloadType_DEPLOYMENT
 	}
 
+	if controllerRef.Kind == "StatefulSet" {
+		// StatefulSet pods have stable names, but should be reported under the StatefulSet like with sidecars.
+		// There is no dedicated workload type, so these are reported as pods.
+		return controllerRef.Name, workloadapi.WorkloadType_POD
+	}
+
 	if controllerRef.Kind == "Job" {
 		// figure out how to go from Job -> CronJob
 		return controllerRef.Name, workloadapi.WorkloadType_JOB
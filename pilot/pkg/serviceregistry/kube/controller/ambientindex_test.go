@@ -39,6 +39,8 @@ import (
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/schema/collections"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/ptr"
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/test/util/file"
@@ -409,6 +411,175 @@ func TestPodLifecycleWorkloadGates(t *testing.T) {
 	// name3 isn't running at all
 }
 
+func TestAmbientIndexHeadlessServices(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	cfg := memory.NewSyncController(memory.MakeSkipValidation(collections.PilotGatewayAPI))
+	controller, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{
+		ConfigController: cfg,
+		MeshWatcher:      mesh.NewFixedWatcher(&meshconfig.MeshConfig{RootNamespace: "istio-system"}),
+	})
+	go cfg.Run(test.NewStop(t))
+	assertEvent := func(ip ...string) {
+		t.Helper()
+		want := strings.Join(ip, ",")
+		fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: want})
+	}
+	assertStatus := func(ip string, status workloadapi.WorkloadStatus) {
+		t.Helper()
+		assert.EventuallyEqual(t, func() workloadapi.WorkloadStatus {
+			wls := controller.ambientIndex.Lookup(ip)
+			if len(wls) != 1 {
+				return -1
+			}
+			return wls[0].Status
+		}, status, retry.Timeout(time.Second*3))
+	}
+
+	// A StatefulSet pod which is not ready yet
+	pod := generatePod("127.0.0.1", "web-0", "ns1", "sa1", "node1", map[string]string{"app": "web"}, nil)
+	pod.GenerateName = "web-"
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       "StatefulSet",
+		Name:       "web",
+		Controller: ptr.Of(true),
+	}}
+	pod.Status = corev1.PodStatus{}
+	newPod, err := controller.client.Kube().CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPod.Status.PodIP = "127.0.0.1"
+	newPod.Status.Phase = corev1.PodRunning
+	if _, err := controller.client.Kube().CoreV1().Pods(pod.Namespace).UpdateStatus(context.TODO(), newPod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assertEvent("127.0.0.1")
+	assertStatus("127.0.0.1", workloadapi.WorkloadStatus_UNHEALTHY)
+	// Telemetry is reported under the StatefulSet rather than each pod
+	wl := controller.ambientIndex.Lookup("127.0.0.1")[0]
+	assert.Equal(t, wl.WorkloadName, "web")
+	assert.Equal(t, wl.CanonicalName, "web")
+
+	// A headless service publishing not ready addresses lets the pods discover each other before they are ready
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns1"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			Ports:                    []corev1.ServicePort{{Name: "tcp", Port: 80, Protocol: corev1.ProtocolTCP}},
+			Selector:                 map[string]string{"app": "web"},
+			PublishNotReadyAddresses: true,
+		},
+	}
+	clienttest.Wrap(t, controller.services).CreateOrUpdate(svc)
+	assertEvent("127.0.0.1")
+	assertStatus("127.0.0.1", workloadapi.WorkloadStatus_HEALTHY)
+	// Headless services have no VIP
+	assert.Equal(t, len(controller.ambientIndex.Lookup("127.0.0.1")[0].VirtualIps), 0)
+
+	svc.Spec.PublishNotReadyAddresses = false
+	clienttest.Wrap(t, controller.services).CreateOrUpdate(svc)
+	assertEvent("127.0.0.1")
+	assertStatus("127.0.0.1", workloadapi.WorkloadStatus_UNHEALTHY)
+
+	// Services with a VIP do not send traffic to the not ready pods, even if they publish them.
+	svc.Name = "web-vip"
+	svc.Spec.ClusterIP = "10.0.0.10"
+	svc.Spec.PublishNotReadyAddresses = true
+	clienttest.Wrap(t, controller.services).CreateOrUpdate(svc)
+	assertEvent("127.0.0.1")
+	assertStatus("127.0.0.1", workloadapi.WorkloadStatus_UNHEALTHY)
+}
+
+func TestAmbientIndexWorkloadEntries(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	cfg := memory.NewSyncController(memory.MakeSkipValidation(collections.PilotGatewayAPI))
+	controller, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{
+		ConfigController: cfg,
+		MeshWatcher:      mesh.NewFixedWatcher(&meshconfig.MeshConfig{RootNamespace: "istio-system"}),
+		ClusterID:        "cluster0",
+	})
+	go cfg.Run(test.NewStop(t))
+	assertEvent := func(ip ...string) {
+		t.Helper()
+		want := strings.Join(ip, ",")
+		fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: want})
+	}
+	lookupNames := func(ip string) []string {
+		var res []string
+		for _, wl := range controller.ambientIndex.Lookup(ip) {
+			res = append(res, wl.Name)
+		}
+		return res
+	}
+
+	createServiceWait(controller, "svc1", "ns1", map[string]string{}, []int32{80}, map[string]string{"app": "a"}, t)
+	fx.Clear()
+
+	vm := &model.WorkloadInstance{
+		Name:      "vm1",
+		Namespace: "ns1",
+		Kind:      model.WorkloadEntryKind,
+		Endpoint: &model.IstioEndpoint{
+			Address:        "10.1.0.1",
+			ServiceAccount: "spiffe://cluster.local/ns/ns1/sa/vm-sa",
+			Labels:         map[string]string{"app": "a", model.TunnelLabel: model.TunnelHTTP},
+		},
+	}
+	controller.WorkloadInstanceHandler(vm, model.EventAdd)
+	assertEvent("10.1.0.1")
+	assert.Equal(t, controller.ambientIndex.Lookup("10.1.0.1"), []*model.WorkloadInfo{{
+		Workload: &workloadapi.Workload{
+			Name:              "vm1",
+			Namespace:         "ns1",
+			Address:           netip.MustParseAddr("10.1.0.1").AsSlice(),
+			ServiceAccount:    "vm-sa",
+			Protocol:          workloadapi.Protocol_HTTP,
+			NativeHbone:       true,
+			CanonicalName:     "a",
+			CanonicalRevision: "latest",
+			WorkloadType:      workloadapi.WorkloadType_POD,
+			WorkloadName:      "vm1",
+			ClusterId:         "cluster0",
+			VirtualIps:        map[string]*workloadapi.PortList{"10.0.0.1": {}},
+		},
+		Labels: map[string]string{"app": "a", model.TunnelLabel: model.TunnelHTTP},
+	}})
+	// The VM is reachable through the Service VIP
+	assert.Equal(t, lookupNames("10.0.0.1"), []string{"vm1"})
+	assert.Equal(t, len(controller.ambientIndex.All()), 1)
+
+	controller.WorkloadInstanceHandler(vm, model.EventDelete)
+	assertEvent("10.1.0.1")
+	assert.Equal(t, len(controller.ambientIndex.Lookup("10.1.0.1")), 0)
+	assert.Equal(t, len(lookupNames("10.0.0.1")), 0)
+}
+
+func TestAmbientInteropMode(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	controller, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{})
+	namespaces := clienttest.Wrap(t, controller.namespaces)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ns1",
+		Annotations: map[string]string{constants.AmbientInteropMode: constants.AmbientInteropModeWaypoint},
+	}}
+	namespaces.Create(ns)
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds full"})
+	assert.Equal(t, controller.InteropMode("ns1"), constants.AmbientInteropModeWaypoint)
+	assert.Equal(t, controller.InteropMode("ns2"), "")
+
+	// Unrelated changes do not trigger a push
+	ns.Labels = map[string]string{"foo": "bar"}
+	namespaces.Update(ns)
+	fx.AssertEmpty(t, 40*time.Millisecond)
+
+	ns.Annotations = nil
+	namespaces.Update(ns)
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds full"})
+	assert.Equal(t, controller.InteropMode("ns1"), "")
+}
+
 func TestRBACConvert(t *testing.T) {
 	files := file.ReadDirOrFail(t, "testdata")
 	if len(files) == 0 {
@@ -434,3 +605,172 @@ func TestRBACConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestAmbientPeerAuthentication(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	cfg := memory.NewSyncController(memory.MakeSkipValidation(collections.PilotGatewayAPI))
+	controller, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{
+		ConfigController: cfg,
+		MeshWatcher:      mesh.NewFixedWatcher(&meshconfig.MeshConfig{RootNamespace: "istio-system"}),
+		ClusterID:        "cluster0",
+		ConfigCluster:    true,
+	})
+	cfg.RegisterEventHandler(gvk.PeerAuthentication, controller.PeerAuthenticationHandler)
+	go cfg.Run(test.NewStop(t))
+	addPeerAuthentication := func(name, ns string, selector map[string]string, spec *authz.PeerAuthentication) {
+		t.Helper()
+		if selector != nil {
+			spec.Selector = &v1beta1.WorkloadSelector{MatchLabels: selector}
+		}
+		p := config.Config{
+			Meta: config.Meta{
+				GroupVersionKind: gvk.PeerAuthentication,
+				Name:             name,
+				Namespace:        ns,
+			},
+			Spec: spec,
+		}
+		if _, err := cfg.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertPolicies := func(ip string, policies ...string) {
+		t.Helper()
+		assert.EventuallyEqual(t, func() []string {
+			wls := controller.ambientIndex.Lookup(ip)
+			if len(wls) == 0 {
+				return nil
+			}
+			return wls[0].AuthorizationPolicies
+		}, policies, retry.Timeout(time.Second*3))
+	}
+
+	pod := generatePod("127.0.0.1", "name1", "ns1", "sa1", "node1", map[string]string{"app": "a"}, nil)
+	addPods(t, controller, fx, pod)
+	fx.Clear()
+	assertPolicies("127.0.0.1")
+
+	addPeerAuthentication("strict", "istio-system", nil, &authz.PeerAuthentication{
+		Mtls: &authz.PeerAuthentication_MutualTLS{Mode: authz.PeerAuthentication_MutualTLS_STRICT},
+	})
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: "127.0.0.1,converted_peer_authentication_strict,istio_converted_static_strict"})
+	assertPolicies("127.0.0.1", "istio-system/istio_converted_static_strict")
+
+	addPeerAuthentication("ports", "ns1", map[string]string{"app": "a"}, &authz.PeerAuthentication{
+		PortLevelMtls: map[uint32]*authz.PeerAuthentication_MutualTLS{
+			8080: {Mode: authz.PeerAuthentication_MutualTLS_PERMISSIVE},
+		},
+	})
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: "127.0.0.1,converted_peer_authentication_ports,istio_converted_static_strict"})
+	assertPolicies("127.0.0.1", "ns1/converted_peer_authentication_ports")
+
+	policies := map[string]*workloadapi.Authorization{}
+	for _, p := range controller.Policies(nil) {
+		policies[p.Namespace+"/"+p.Name] = p
+	}
+	assert.Equal(t, len(policies), 2)
+	assert.Equal(t, policies["ns1/converted_peer_authentication_ports"].Groups[0].Rules[0].Matches[0].NotDestinationPorts, []uint32{8080})
+	if _, f := policies["istio-system/istio_converted_static_strict"]; !f {
+		t.Fatalf("expected static strict policy, got %v", policies)
+	}
+
+	cfg.Delete(gvk.PeerAuthentication, "ports", "ns1", nil)
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: "127.0.0.1,converted_peer_authentication_ports,istio_converted_static_strict"})
+	assertPolicies("127.0.0.1", "istio-system/istio_converted_static_strict")
+
+	cfg.Delete(gvk.PeerAuthentication, "strict", "istio-system", nil)
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: "127.0.0.1,converted_peer_authentication_strict,istio_converted_static_strict"})
+	assertPolicies("127.0.0.1")
+}
+
+func TestConvertPeerAuthentication(t *testing.T) {
+	strict := authz.PeerAuthentication_MutualTLS_STRICT
+	permissive := authz.PeerAuthentication_MutualTLS_PERMISSIVE
+	disable := authz.PeerAuthentication_MutualTLS_DISABLE
+	unset := authz.PeerAuthentication_MutualTLS_UNSET
+	cases := []struct {
+		name                string
+		mode                authz.PeerAuthentication_MutualTLS_Mode
+		ports               map[uint32]authz.PeerAuthentication_MutualTLS_Mode
+		inherited           authz.PeerAuthentication_MutualTLS_Mode
+		noSelector          bool
+		destinationPorts    []uint32
+		notDestinationPorts []uint32
+		none                bool
+	}{
+		{
+			name:                "strict with exceptions",
+			mode:                strict,
+			ports:               map[uint32]authz.PeerAuthentication_MutualTLS_Mode{9090: disable, 8080: permissive, 7070: strict},
+			inherited:           permissive,
+			notDestinationPorts: []uint32{8080, 9090},
+		},
+		{
+			name:             "permissive with strict port",
+			mode:             permissive,
+			ports:            map[uint32]authz.PeerAuthentication_MutualTLS_Mode{8080: strict, 9090: unset},
+			inherited:        strict,
+			destinationPorts: []uint32{8080},
+		},
+		{
+			name:                "inherit strict",
+			mode:                unset,
+			ports:               map[uint32]authz.PeerAuthentication_MutualTLS_Mode{8080: disable},
+			inherited:           strict,
+			notDestinationPorts: []uint32{8080},
+		},
+		{
+			name:      "no strict ports",
+			mode:      unset,
+			ports:     map[uint32]authz.PeerAuthentication_MutualTLS_Mode{8080: permissive},
+			inherited: disable,
+			none:      true,
+		},
+		{
+			name:      "no port level",
+			mode:      strict,
+			inherited: permissive,
+			none:      true,
+		},
+		{
+			name:       "no selector",
+			mode:       strict,
+			ports:      map[uint32]authz.PeerAuthentication_MutualTLS_Mode{8080: permissive},
+			inherited:  permissive,
+			noSelector: true,
+			none:       true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			pa := &authz.PeerAuthentication{
+				Mtls: &authz.PeerAuthentication_MutualTLS{Mode: tt.mode},
+			}
+			if !tt.noSelector {
+				pa.Selector = &v1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "a"}}
+			}
+			if len(tt.ports) > 0 {
+				pa.PortLevelMtls = map[uint32]*authz.PeerAuthentication_MutualTLS{}
+				for port, mode := range tt.ports {
+					pa.PortLevelMtls[port] = &authz.PeerAuthentication_MutualTLS{Mode: mode}
+				}
+			}
+			got := convertPeerAuthentication(config.Config{
+				Meta: config.Meta{GroupVersionKind: gvk.PeerAuthentication, Name: "pa", Namespace: "ns1"},
+				Spec: pa,
+			}, tt.inherited)
+			if tt.none {
+				if got != nil {
+					t.Fatalf("expected no policy, got %v", got)
+				}
+				return
+			}
+			assert.Equal(t, got.Name, "converted_peer_authentication_pa")
+			assert.Equal(t, got.Action, workloadapi.Action_DENY)
+			match := got.Groups[0].Rules[0].Matches[0]
+			assert.Equal(t, len(match.NotPrincipals), 1)
+			assert.Equal(t, match.DestinationPorts, tt.destinationPorts)
+			assert.Equal(t, match.NotDestinationPorts, tt.notDestinationPorts)
+		})
+	}
+}
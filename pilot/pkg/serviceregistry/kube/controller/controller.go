@@ -151,6 +151,13 @@ type Options struct {
 
 	ConfigController model.ConfigStoreController
 	ConfigCluster    bool
+
+	// DefaultNetwork is the network of the endpoints of the cluster whose network is not otherwise known, as
+	// declared by the registration of the cluster.
+	DefaultNetwork network.ID
+	// DefaultLocality is the locality of the pods of the cluster running on nodes without topology labels, as
+	// declared by the registration of the cluster.
+	DefaultLocality string
 }
 
 func (o *Options) GetFilter() namespace.DiscoveryFilter {
@@ -211,6 +218,7 @@ type kubernetesNode struct {
 // controllerInterface is a simplified interface for the Controller used for testing.
 type controllerInterface interface {
 	getPodLocality(pod *v1.Pod) string
+	workloadNetwork(nodeName, namespace string) network.ID
 	Network(endpointIP string, labels labels.Instance) network.ID
 	Cluster() cluster.ID
 }
@@ -302,15 +310,19 @@ func NewController(kubeClient kubelib.Client, options Options) *Controller {
 	}
 
 	c.namespaces = kclient.New[*v1.Namespace](kubeClient)
-	if c.opts.SystemNamespace != "" {
+	if c.opts.SystemNamespace != "" || features.EnableAmbientControllers {
 		registerHandlers[*v1.Namespace](
 			c,
 			c.namespaces,
 			"Namespaces",
 			func(old *v1.Namespace, cur *v1.Namespace, event model.Event) error {
-				if cur.Namespace == c.opts.SystemNamespace {
+				if features.EnableAmbientControllers {
+					c.onNamespaceInteropEvent(old, cur)
+				}
+				if c.opts.SystemNamespace != "" && cur.Name == c.opts.SystemNamespace {
 					return c.onSystemNamespaceEvent(old, cur, event)
 				}
+				c.onWorkloadNetworkLabelEvent(old, cur, event)
 				return nil
 			},
 			nil,
@@ -465,7 +477,8 @@ func (c *Controller) Network(endpointIP string, labels labels.Instance) network.
 		return nw
 	}
 
-	return ""
+	// 4. use the network declared by the registration of the cluster
+	return c.opts.DefaultNetwork
 }
 
 func (c *Controller) Cleanup() error {
@@ -576,7 +589,8 @@ func (c *Controller) buildEndpointsForService(svc *model.Service, updateCache bo
 	return endpoints
 }
 
-func (c *Controller) onNodeEvent(_, node *v1.Node, event model.Event) error {
+func (c *Controller) onNodeEvent(old, node *v1.Node, event model.Event) error {
+	c.onWorkloadNetworkLabelEvent(old, node, event)
 	var updatedNeeded bool
 	if event == model.EventDelete {
 		updatedNeeded = true
@@ -839,7 +853,7 @@ func (c *Controller) getPodLocality(pod *v1.Pod) string {
 		if pod.Spec.NodeName != "" {
 			log.Warnf("unable to get node %q for pod %q/%q", pod.Spec.NodeName, pod.Namespace, pod.Name)
 		}
-		return ""
+		return c.opts.DefaultLocality
 	}
 
 	region := getLabelValue(node.ObjectMeta, NodeRegionLabelGA, NodeRegionLabel)
@@ -847,7 +861,7 @@ func (c *Controller) getPodLocality(pod *v1.Pod) string {
 	subzone := getLabelValue(node.ObjectMeta, label.TopologySubzone.Name, "")
 
 	if region == "" && zone == "" && subzone == "" {
-		return ""
+		return c.opts.DefaultLocality
 	}
 
 	return region + "/" + zone + "/" + subzone // Format: "%s/%s/%s"
@@ -1084,6 +1098,15 @@ func (c *Controller) serviceInstancesFromWorkloadInstance(si *model.WorkloadInst
 
 // WorkloadInstanceHandler defines the handler for service instances generated by other registries
 func (c *Controller) WorkloadInstanceHandler(si *model.WorkloadInstance, event model.Event) {
+	if c.ambientIndex != nil && si.Kind == model.WorkloadEntryKind {
+		updates := c.ambientIndex.handleWorkloadEntry(si, event == model.EventDelete, c)
+		if len(updates) > 0 {
+			c.opts.XDSUpdater.ConfigUpdate(&model.PushRequest{
+				ConfigsUpdated: updates,
+				Reason:         []model.TriggerReason{model.AmbientUpdate},
+			})
+		}
+	}
 	// ignore malformed workload entries. And ignore any workload entry that does not have a label
 	// as there is no way for us to select them
 	if si.Namespace == "" || len(si.Endpoint.Labels) == 0 {
@@ -1315,14 +1338,22 @@ func (c *Controller) GetProxyWorkloadLabels(proxy *model.Proxy) labels.Instance
 			// TODO: remove this when 1.16 is EOL?
 			nodeName = pod.Spec.NodeName
 		}
-		if len(locality) == 0 && len(nodeName) == 0 {
+		var networkID network.ID
+		if pod.Labels[label.TopologyNetwork.Name] == "" {
+			networkID = c.workloadNetwork(pod.Spec.NodeName, pod.Namespace)
+		}
+		if len(locality) == 0 && len(nodeName) == 0 && networkID == "" {
 			return pod.Labels
 		}
 
-		out := make(labels.Instance, len(pod.Labels)+2)
+		out := make(labels.Instance, len(pod.Labels)+3)
 		for k, v := range pod.Labels {
 			out[k] = v
 		}
+		if networkID != "" {
+			// set the network of the node or the namespace of the pod, so the proxy is aware of it
+			out[label.TopologyNetwork.Name] = networkID.String()
+		}
 		if len(locality) > 0 {
 			// Add locality labels to support locality Load balancing for proxy without service instances.
 			// As this may contain node topology labels, which could not be got from aggregator controller
@@ -151,6 +151,12 @@ func (b *EndpointBuilder) endpointNetwork(endpointIP string) network.ID {
 	if b.metaNetwork != "" {
 		return b.metaNetwork
 	}
+	// Otherwise, prefer the network of the node or the namespace of the workload, if not labeled itself.
+	if b.labels[label.TopologyNetwork.Name] == "" {
+		if nw := b.controller.workloadNetwork(b.nodeName, b.namespace); nw != "" {
+			return nw
+		}
+	}
 
 	return b.controller.Network(endpointIP, b.labels)
 }
@@ -253,6 +253,10 @@ func (c testController) getPodLocality(*v1.Pod) string {
 	return c.locality
 }
 
+func (c testController) workloadNetwork(string, string) network.ID {
+	return ""
+}
+
 func (c testController) Network(ip string, instance labels.Instance) network.ID {
 	if n := instance[label.TopologyNetwork.Name]; n != "" {
 		return network.ID(n)
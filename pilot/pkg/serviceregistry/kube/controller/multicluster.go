@@ -203,6 +203,8 @@ func (m *Multicluster) addCluster(cluster *multicluster.Cluster) (*kubeControlle
 
 	options := m.opts
 	options.ClusterID = cluster.ID
+	options.DefaultNetwork = cluster.Network
+	options.DefaultLocality = cluster.Locality
 	// different clusters may have different k8s version, re-apply conditional default
 	options.EndpointMode = DetectEndpointMode(client)
 	if !configCluster {
@@ -237,6 +239,7 @@ func (m *Multicluster) initializeCluster(cluster *multicluster.Cluster, kubeCont
 	}
 	if m.configController != nil && features.EnableAmbientControllers {
 		m.configController.RegisterEventHandler(gvk.AuthorizationPolicy, kubeRegistry.AuthorizationPolicyHandler)
+		m.configController.RegisterEventHandler(gvk.PeerAuthentication, kubeRegistry.PeerAuthenticationHandler)
 	}
 
 	if configCluster && m.serviceEntryController != nil && features.EnableEnhancedResourceScoping {
@@ -322,6 +325,14 @@ func (m *Multicluster) initializeCluster(cluster *multicluster.Cluster, kubeCont
 			}
 		}
 	}
+	// Provision the injection webhook and the mesh config of the remote clusters pointing at the external istiod,
+	// instead of relying on the istiod-remote chart.
+	if shouldLead && !configCluster && m.caBundleWatcher != nil && features.RemoteClusterBootstrapAddress != "" {
+		log.Infof("initializing remote bootstrap controller for cluster %s", cluster.ID)
+		rb := newRemoteBootstrapController(client, cluster.ID, options.DefaultNetwork, m.revision, options.SystemNamespace,
+			features.RemoteClusterBootstrapAddress, options.MeshWatcher, m.caBundleWatcher)
+		go rb.Run(clusterStopCh)
+	}
 
 	// setting up the serviceexport controller if and only if it is turned on in the meshconfig.
 	if features.EnableMCSAutoExport {
@@ -22,6 +22,7 @@ import (
 
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/keycertbundle"
+	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/kube/inject"
@@ -67,7 +68,8 @@ func NewNamespaceController(kubeClient kube.Client, caBundleWatcher *keycertbund
 	}
 	c.queue = controllers.NewQueue("namespace controller",
 		controllers.WithReconciler(c.insertDataForNamespace),
-		controllers.WithMaxAttempts(maxRetries))
+		controllers.WithMaxAttempts(maxRetries),
+		controllers.WithKind(kind.Namespace))
 
 	c.configmaps = kclient.New[*v1.ConfigMap](kubeClient)
 	c.namespaces = kclient.New[*v1.Namespace](kubeClient)
@@ -147,7 +149,7 @@ func (nc *NamespaceController) insertDataForNamespace(o types.NamespacedName) er
 		Namespace: ns,
 		Labels:    configMapLabel,
 	}
-	return k8s.InsertDataToConfigMap(nc.configmaps, meta, nc.caBundleWatcher.GetCABundle())
+	return k8s.InsertCADataToConfigMap(nc.configmaps, meta, nc.caBundleWatcher.GetCABundle(), nc.caBundleWatcher.GetCRL())
 }
 
 // On namespace change, update the config map.
@@ -22,9 +22,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"istio.io/api/label"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/network"
 )
 
@@ -81,6 +83,34 @@ func (c *Controller) onDefaultNetworkChange() {
 	c.reloadNetworkGateways()
 }
 
+// workloadNetwork returns the network of the workloads running on the node or in the namespace, set by their
+// topology.istio.io/network label, so a single cluster can span several networks, e.g. with edge node pools not
+// routable from the rest of the cluster. The label of the node takes precedence over the label of the namespace.
+func (c *Controller) workloadNetwork(nodeName, namespace string) network.ID {
+	if nodeName != "" {
+		if node := c.nodes.Get(nodeName, ""); node != nil && node.Labels[label.TopologyNetwork.Name] != "" {
+			return network.ID(node.Labels[label.TopologyNetwork.Name])
+		}
+	}
+	if namespace != "" {
+		if ns := c.namespaces.Get(namespace, ""); ns != nil && ns.Labels[label.TopologyNetwork.Name] != "" {
+			return network.ID(ns.Labels[label.TopologyNetwork.Name])
+		}
+	}
+	return ""
+}
+
+// onWorkloadNetworkLabelEvent refreshes the network of the endpoints when the network label of a node or a
+// namespace changes.
+func (c *Controller) onWorkloadNetworkLabelEvent(old, cur controllers.Object, event model.Event) {
+	if event != model.EventUpdate || controllers.IsNil(old) {
+		return
+	}
+	if old.GetLabels()[label.TopologyNetwork.Name] != cur.GetLabels()[label.TopologyNetwork.Name] {
+		c.onDefaultNetworkChange()
+	}
+}
+
 // reloadNetworkLookup refreshes the meshNetworks configuration, network for each endpoint, and
 // recomputes network gateways.
 func (c *Controller) reloadNetworkLookup() {
@@ -220,6 +250,12 @@ func (c *Controller) extractGatewaysInner(svc *model.Service) bool {
 			if nodePort, exists := nodePortMap[gw.Port]; exists {
 				gw.Port = nodePort
 			}
+			if svcPort, exists := svc.Ports.GetByPort(model.HBoneInboundListenPort); exists && features.EnableHBONEEastWest {
+				gw.HBONEPort = uint32(svcPort.Port)
+				if nodePort, exists := nodePortMap[gw.HBONEPort]; exists {
+					gw.HBONEPort = nodePort
+				}
+			}
 
 			gw.Cluster = c.Cluster()
 			gw.Addr = addr
@@ -28,8 +28,13 @@ import (
 
 	"istio.io/api/label"
 	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/network"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/test/util/retry"
 )
 
@@ -173,3 +178,74 @@ func addMeshNetworksFromRegistryGateway(t *testing.T, c *FakeController, watcher
 		},
 	}})
 }
+
+func TestHBONENetworkGateway(t *testing.T) {
+	test.SetForTest(t, &features.EnableHBONEEastWest, true)
+	c, _ := NewFakeControllerWithOptions(t, FakeControllerOptions{ClusterID: "Kubernetes", DomainSuffix: "cluster.local"})
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-eastwestgateway", Namespace: "istio-system", Labels: map[string]string{
+			label.TopologyNetwork.Name: "nw0",
+		}},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{{Port: 15443, Protocol: corev1.ProtocolTCP}, {Port: 15008, Protocol: corev1.ProtocolTCP}},
+		},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "2.3.4.6"}}}},
+	}
+	if _, err := c.client.Kube().CoreV1().Services("istio-system").Create(context.TODO(), svc, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []model.NetworkGateway{{Network: "nw0", Cluster: "Kubernetes", Addr: "2.3.4.6", Port: 15443, HBONEPort: 15008}}
+	assert.EventuallyEqual(t, c.NetworkGateways, want)
+}
+
+func TestWorkloadNetwork(t *testing.T) {
+	c, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{ClusterID: "Kubernetes"})
+	addNodes(t, c,
+		generateNode("edge", map[string]string{label.TopologyNetwork.Name: "nw-edge"}),
+		generateNode("core", nil))
+	namespaces := clienttest.Wrap(t, c.namespaces)
+	namespaces.Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "iot", Labels: map[string]string{label.TopologyNetwork.Name: "nw-iot"}}})
+	namespaces.Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	retry.UntilOrFail(t, func() bool { return c.namespaces.Get("default", "") != nil })
+
+	endpointNetwork := func(pod *corev1.Pod) network.ID {
+		return NewEndpointBuilder(c, pod).buildIstioEndpoint(pod.Status.PodIP, 80, "http", model.AlwaysDiscoverable, model.Healthy).Network
+	}
+	cases := []struct {
+		name string
+		pod  *corev1.Pod
+		want network.ID
+	}{
+		{"cluster network", generatePod("10.0.0.1", "a", "default", "", "core", nil, nil), ""},
+		{"node network", generatePod("10.0.0.2", "b", "default", "", "edge", nil, nil), "nw-edge"},
+		{"namespace network", generatePod("10.0.0.3", "c", "iot", "", "core", nil, nil), "nw-iot"},
+		{"node over namespace", generatePod("10.0.0.4", "d", "iot", "", "edge", nil, nil), "nw-edge"},
+		{
+			"pod label over node",
+			generatePod("10.0.0.5", "e", "iot", "", "edge", map[string]string{label.TopologyNetwork.Name: "nw-pod"}, nil),
+			"nw-pod",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, endpointNetwork(tt.pod), tt.want)
+		})
+	}
+
+	// The proxy learns the network of its node
+	pod := generatePod("10.0.0.2", "b", "default", "", "edge", map[string]string{"app": "edge"}, nil)
+	addPods(t, c, fx, pod)
+	proxy := &model.Proxy{IPAddresses: []string{"10.0.0.2"}, Metadata: &model.NodeMetadata{}, ConfigNamespace: "default"}
+	assert.Equal(t, c.GetProxyWorkloadLabels(proxy)[label.TopologyNetwork.Name], "nw-edge")
+
+	createServiceWait(c, "edge", "default", nil, []int32{80}, map[string]string{"app": "edge"}, t)
+	createEndpoints(t, c, "edge", "default", []string{"http"}, []string{"10.0.0.2"},
+		[]*corev1.ObjectReference{{Kind: "Pod", Name: "b", Namespace: "default"}}, nil)
+	assert.Equal(t, fx.WaitOrFail(t, "eds").Endpoints[0].Network, "nw-edge")
+
+	// Relabeling a node moves its workloads to the new network
+	fx.Clear()
+	addNodes(t, c, generateNode("edge", map[string]string{label.TopologyNetwork.Name: "nw-edge-2"}))
+	assert.Equal(t, fx.WaitOrFail(t, "eds").Endpoints[0].Network, "nw-edge-2")
+}
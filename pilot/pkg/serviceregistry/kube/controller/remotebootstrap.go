@@ -0,0 +1,345 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/keycertbundle"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/network"
+	"istio.io/istio/pkg/util/protomarshal"
+)
+
+const (
+	// remoteBootstrapInjectionPort is the port of istiod serving the sidecar injection webhook.
+	remoteBootstrapInjectionPort = "15017"
+	// remoteBootstrapDiscoveryPort is the port of istiod serving the xDS of the proxies.
+	remoteBootstrapDiscoveryPort = "15012"
+)
+
+// remoteBootstrapKey is the only key of the queue of the remoteBootstrapController: the webhook configuration and the
+// mesh config are always reconciled together.
+var remoteBootstrapKey = types.NamespacedName{Name: "remote-bootstrap"}
+
+// remoteBootstrapController provisions and maintains, in a remote cluster controlled by an external istiod, the
+// resources pointing the cluster at the external istiod: the sidecar injection webhook configuration and the mesh
+// config of the proxies. The root certificate of the mesh is distributed by the namespace controller.
+//
+// Only the resources labeled with the revision of istiod are updated, the resources installed by other means are left
+// untouched.
+type remoteBootstrapController struct {
+	clusterID       cluster.ID
+	network         network.ID
+	revision        string
+	systemNamespace string
+	// address is the host of the external istiod reachable from the remote cluster.
+	address         string
+	meshWatcher     mesh.Watcher
+	caBundleWatcher *keycertbundle.Watcher
+
+	client kube.Client
+	queue  controllers.Queue
+	// webhooks and configmaps only watch the managed webhook configuration and mesh config, rather than sharing the
+	// cluster wide informers of their types.
+	webhooks   cache.SharedIndexInformer
+	configmaps cache.SharedIndexInformer
+}
+
+func newRemoteBootstrapController(client kube.Client, clusterID cluster.ID, network network.ID, revision, systemNamespace, address string,
+	meshWatcher mesh.Watcher, caBundleWatcher *keycertbundle.Watcher,
+) *remoteBootstrapController {
+	c := &remoteBootstrapController{
+		client:          client,
+		clusterID:       clusterID,
+		network:         network,
+		revision:        revision,
+		systemNamespace: systemNamespace,
+		address:         address,
+		meshWatcher:     meshWatcher,
+		caBundleWatcher: caBundleWatcher,
+	}
+	c.queue = controllers.NewQueue("remote bootstrap",
+		controllers.WithReconciler(c.reconcile),
+		controllers.WithMaxAttempts(maxRetries))
+
+	webhookSelector := fields.OneTermEqualSelector(metav1.ObjectNameField, c.injectionWebhookConfigName()).String()
+	c.webhooks = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = webhookSelector
+				return client.Kube().AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = webhookSelector
+				return client.Kube().AdmissionregistrationV1().MutatingWebhookConfigurations().Watch(context.TODO(), opts)
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{}, 0, cache.Indexers{},
+	)
+	configMapSelector := fields.OneTermEqualSelector(metav1.ObjectNameField, c.meshConfigMapName()).String()
+	c.configmaps = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = configMapSelector
+				return client.Kube().CoreV1().ConfigMaps(systemNamespace).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = configMapSelector
+				return client.Kube().CoreV1().ConfigMaps(systemNamespace).Watch(context.TODO(), opts)
+			},
+		},
+		&v1.ConfigMap{}, 0, cache.Indexers{},
+	)
+	_ = c.webhooks.SetTransform(kube.StripUnusedFields)
+	_ = c.configmaps.SetTransform(kube.StripUnusedFields)
+	enqueue := func(controllers.Object) { c.queue.Add(remoteBootstrapKey) }
+	_, _ = c.webhooks.AddEventHandler(controllers.FilteredObjectHandler(enqueue, func(o controllers.Object) bool {
+		return o.GetName() == c.injectionWebhookConfigName()
+	}))
+	_, _ = c.configmaps.AddEventHandler(controllers.FilteredObjectHandler(enqueue, func(o controllers.Object) bool {
+		return o.GetNamespace() == c.systemNamespace && o.GetName() == c.meshConfigMapName()
+	}))
+	meshWatcher.AddMeshHandler(func() { c.queue.Add(remoteBootstrapKey) })
+	return c
+}
+
+// Run starts the remoteBootstrapController until a value is sent to stopCh.
+func (c *remoteBootstrapController) Run(stopCh <-chan struct{}) {
+	go c.webhooks.Run(stopCh)
+	go c.configmaps.Run(stopCh)
+	if !kube.WaitForCacheSync(stopCh, c.webhooks.HasSynced, c.configmaps.HasSynced) {
+		log.Errorf("failed to sync remote bootstrap controller cache of cluster %s", c.clusterID)
+		return
+	}
+	go c.startCaBundleWatcher(stopCh)
+	c.queue.Add(remoteBootstrapKey)
+	c.queue.Run(stopCh)
+}
+
+// startCaBundleWatcher listens for updates to the CA bundle and updates the webhook configuration.
+func (c *remoteBootstrapController) startCaBundleWatcher(stop <-chan struct{}) {
+	id, watchCh := c.caBundleWatcher.AddWatcher()
+	defer c.caBundleWatcher.RemoveWatcher(id)
+	for {
+		select {
+		case <-watchCh:
+			c.queue.Add(remoteBootstrapKey)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (c *remoteBootstrapController) reconcile(types.NamespacedName) error {
+	if err := c.reconcileInjectionWebhook(); err != nil {
+		return fmt.Errorf("failed to reconcile injection webhook of cluster %s: %v", c.clusterID, err)
+	}
+	if err := c.reconcileMeshConfig(); err != nil {
+		return fmt.Errorf("failed to reconcile mesh config of cluster %s: %v", c.clusterID, err)
+	}
+	return nil
+}
+
+func (c *remoteBootstrapController) reconcileInjectionWebhook() error {
+	want := c.injectionWebhookConfig()
+	webhooks := c.client.Kube().AdmissionregistrationV1().MutatingWebhookConfigurations()
+	obj, _, _ := c.webhooks.GetStore().GetByKey(want.Name)
+	if obj == nil {
+		_, err := webhooks.Create(context.TODO(), want, metav1.CreateOptions{})
+		return err
+	}
+	current := obj.(*admissionregistrationv1.MutatingWebhookConfiguration)
+	if !c.owns(current.ObjectMeta) {
+		log.Debugf("skipping injection webhook %s of cluster %s, not owned by revision %s", current.Name, c.clusterID, c.revision)
+		return nil
+	}
+	if reflect.DeepEqual(current.Webhooks, want.Webhooks) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Webhooks = want.Webhooks
+	_, err := webhooks.Update(context.TODO(), updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *remoteBootstrapController) reconcileMeshConfig() error {
+	want, err := c.meshConfigMap()
+	if err != nil {
+		return err
+	}
+	configmaps := c.client.Kube().CoreV1().ConfigMaps(want.Namespace)
+	obj, _, _ := c.configmaps.GetStore().GetByKey(want.Namespace + "/" + want.Name)
+	if obj == nil {
+		_, err := configmaps.Create(context.TODO(), want, metav1.CreateOptions{})
+		return err
+	}
+	current := obj.(*v1.ConfigMap)
+	if !c.owns(current.ObjectMeta) {
+		log.Debugf("skipping mesh config %s of cluster %s, not owned by revision %s", current.Name, c.clusterID, c.revision)
+		return nil
+	}
+	if reflect.DeepEqual(current.Data, want.Data) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Data = want.Data
+	_, err = configmaps.Update(context.TODO(), updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *remoteBootstrapController) owns(meta metav1.ObjectMeta) bool {
+	return meta.Labels[label.IoIstioRev.Name] == c.revision
+}
+
+func (c *remoteBootstrapController) objectMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    map[string]string{label.IoIstioRev.Name: c.revision},
+	}
+}
+
+func (c *remoteBootstrapController) isDefaultRevision() bool {
+	return c.revision == "" || c.revision == "default"
+}
+
+func (c *remoteBootstrapController) injectionWebhookConfigName() string {
+	if c.isDefaultRevision() {
+		return features.InjectionWebhookConfigName
+	}
+	return features.InjectionWebhookConfigName + "-" + c.revision
+}
+
+func (c *remoteBootstrapController) meshConfigMapName() string {
+	if c.isDefaultRevision() {
+		return "istio"
+	}
+	return "istio-" + c.revision
+}
+
+// injectionWebhookConfig builds the injection webhook configuration of the remote cluster, matching the one installed
+// by the istiod-remote chart: the pods are injected by the external istiod, with the cluster and the network of the
+// remote cluster in the path of the webhook.
+func (c *remoteBootstrapController) injectionWebhookConfig() *admissionregistrationv1.MutatingWebhookConfiguration {
+	url := "https://" + net.JoinHostPort(c.address, remoteBootstrapInjectionPort) + "/inject/cluster/" + c.clusterID.String()
+	if c.network != "" {
+		url += "/net/" + c.network.String()
+	}
+	revision := c.revision
+	if revision == "" {
+		revision = "default"
+	}
+	webhook := func(name string, namespaceSelector, objectSelector []metav1.LabelSelectorRequirement) admissionregistrationv1.MutatingWebhook {
+		sideEffects := admissionregistrationv1.SideEffectClassNone
+		failurePolicy := admissionregistrationv1.Fail
+		reinvocationPolicy := admissionregistrationv1.NeverReinvocationPolicy
+		// The defaults of the API server are set, so the webhook configuration read back is not updated again.
+		matchPolicy := admissionregistrationv1.Equivalent
+		scope := admissionregistrationv1.AllScopes
+		timeout := int32(10)
+		return admissionregistrationv1.MutatingWebhook{
+			Name: name,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				URL:      &url,
+				CABundle: c.caBundleWatcher.GetCABundle(),
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+					Scope:       &scope,
+				},
+			}},
+			SideEffects:             &sideEffects,
+			FailurePolicy:           &failurePolicy,
+			ReinvocationPolicy:      &reinvocationPolicy,
+			MatchPolicy:             &matchPolicy,
+			TimeoutSeconds:          &timeout,
+			AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			NamespaceSelector:       &metav1.LabelSelector{MatchExpressions: namespaceSelector},
+			ObjectSelector:          &metav1.LabelSelector{MatchExpressions: objectSelector},
+		}
+	}
+	notDisabled := metav1.LabelSelectorRequirement{
+		Key: annotation.SidecarInject.Name, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"false"},
+	}
+	noInjectionLabel := metav1.LabelSelectorRequirement{Key: "istio-injection", Operator: metav1.LabelSelectorOpDoesNotExist}
+	noRevisionLabel := metav1.LabelSelectorRequirement{Key: label.IoIstioRev.Name, Operator: metav1.LabelSelectorOpDoesNotExist}
+	revisionLabel := metav1.LabelSelectorRequirement{Key: label.IoIstioRev.Name, Operator: metav1.LabelSelectorOpIn, Values: []string{revision}}
+
+	webhooks := []admissionregistrationv1.MutatingWebhook{
+		webhook("rev.namespace."+webhookName, []metav1.LabelSelectorRequirement{revisionLabel, noInjectionLabel},
+			[]metav1.LabelSelectorRequirement{notDisabled}),
+		webhook("rev.object."+webhookName, []metav1.LabelSelectorRequirement{noRevisionLabel, noInjectionLabel},
+			[]metav1.LabelSelectorRequirement{notDisabled, revisionLabel}),
+	}
+	if c.isDefaultRevision() {
+		webhooks = append(webhooks,
+			webhook("namespace."+webhookName, []metav1.LabelSelectorRequirement{
+				{Key: "istio-injection", Operator: metav1.LabelSelectorOpIn, Values: []string{"enabled"}},
+			}, []metav1.LabelSelectorRequirement{notDisabled}),
+			webhook("object."+webhookName, []metav1.LabelSelectorRequirement{noRevisionLabel, noInjectionLabel},
+				[]metav1.LabelSelectorRequirement{
+					{Key: annotation.SidecarInject.Name, Operator: metav1.LabelSelectorOpIn, Values: []string{"true"}},
+					noRevisionLabel,
+				}))
+	}
+
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: c.objectMeta(c.injectionWebhookConfigName(), ""),
+		Webhooks:   webhooks,
+	}
+}
+
+// meshConfigMap builds the mesh config of the remote cluster: the mesh config of istiod, with the proxies connecting
+// to the external istiod.
+func (c *remoteBootstrapController) meshConfigMap() (*v1.ConfigMap, error) {
+	m := proto.Clone(c.meshWatcher.Mesh()).(*meshconfig.MeshConfig)
+	if m.DefaultConfig == nil {
+		m.DefaultConfig = &meshconfig.ProxyConfig{}
+	}
+	m.DefaultConfig.DiscoveryAddress = net.JoinHostPort(c.address, remoteBootstrapDiscoveryPort)
+	value, err := protomarshal.ToYAML(m)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ConfigMap{
+		ObjectMeta: c.objectMeta(c.meshConfigMapName(), c.systemNamespace),
+		Data:       map[string]string{"mesh": value},
+	}, nil
+}
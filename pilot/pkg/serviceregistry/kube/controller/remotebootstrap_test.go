@@ -0,0 +1,104 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/label"
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/keycertbundle"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+func TestRemoteBootstrapController(t *testing.T) {
+	client := kube.NewFakeClient()
+	t.Cleanup(client.Shutdown)
+	watcher := keycertbundle.NewWatcher()
+	watcher.SetAndNotify(nil, nil, []byte("caBundle"))
+	meshWatcher := mesh.NewTestWatcher(&meshconfig.MeshConfig{TrustDomain: "cluster.local"})
+	rb := newRemoteBootstrapController(client, "remote", "network-1", "canary", "istio-system", "istiod.example.com",
+		meshWatcher, watcher)
+	webhooks := clienttest.Wrap(t, kclient.New[*admissionregistrationv1.MutatingWebhookConfiguration](client))
+	configmaps := clienttest.Wrap(t, kclient.New[*v1.ConfigMap](client))
+	stop := test.NewStop(t)
+	client.RunAndWait(stop)
+	go rb.Run(stop)
+
+	webhookURLs := func() []string {
+		wh := webhooks.Get("istio-sidecar-injector-canary", "")
+		if wh == nil {
+			return nil
+		}
+		var urls []string
+		for _, w := range wh.Webhooks {
+			urls = append(urls, *w.ClientConfig.URL+" "+string(w.ClientConfig.CABundle))
+		}
+		return urls
+	}
+	want := "https://istiod.example.com:15017/inject/cluster/remote/net/network-1 caBundle"
+	assert.EventuallyEqual(t, webhookURLs, []string{want, want})
+
+	meshConfig := func() string {
+		cm := configmaps.Get("istio-canary", "istio-system")
+		if cm == nil {
+			return ""
+		}
+		return cm.Data["mesh"]
+	}
+	retry.UntilOrFail(t, func() bool {
+		m := meshConfig()
+		return strings.Contains(m, "discoveryAddress: istiod.example.com:15012") && strings.Contains(m, "trustDomain: cluster.local")
+	})
+
+	// A rotation of the root certificate is propagated to the webhook configuration
+	watcher.SetAndNotify(nil, nil, []byte("caBundle-new"))
+	want = "https://istiod.example.com:15017/inject/cluster/remote/net/network-1 caBundle-new"
+	assert.EventuallyEqual(t, webhookURLs, []string{want, want})
+
+	// Changes to the managed resources are reverted
+	wh := webhooks.Get("istio-sidecar-injector-canary", "").DeepCopy()
+	wh.Webhooks = wh.Webhooks[:1]
+	webhooks.Update(wh)
+	assert.EventuallyEqual(t, webhookURLs, []string{want, want})
+
+	// Mesh config changes are propagated
+	assert.NoError(t, meshWatcher.Update(&meshconfig.MeshConfig{TrustDomain: "example.com"}, 5))
+	retry.UntilOrFail(t, func() bool {
+		return strings.Contains(meshConfig(), "trustDomain: example.com")
+	})
+
+	// Resources not owned by the revision are left untouched
+	configmaps.CreateOrUpdate(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-canary", Namespace: "istio-system", Labels: map[string]string{label.IoIstioRev.Name: "other"}},
+		Data:       map[string]string{"mesh": "{}"},
+	})
+	webhooks.CreateOrUpdate(&admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector-canary"},
+	})
+	assert.EventuallyEqual(t, webhookURLs, nil)
+	assert.Equal(t, configmaps.Get("istio-canary", "istio-system").Data, map[string]string{"mesh": "{}"})
+}
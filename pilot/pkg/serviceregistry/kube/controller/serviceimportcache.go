@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	mcsapi "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
@@ -62,6 +63,8 @@ type importedService struct {
 //
 // The synthetic MCS service is a copy of the real k8s Service (e.g. cluster.local) with the same
 // namespaced name, but with the hostname and VIPs changed to the appropriate ClusterSet values.
+// For a Headless ServiceImport, which has no ClusterSet VIPs, the synthetic service is headless as
+// well, so the MCS host resolves directly to the endpoints exported by the clusters.
 // The real k8s Service can live anywhere in the mesh and does not have to reside in the same
 // cluster as the ServiceImport.
 type serviceImportCache interface {
@@ -115,11 +118,11 @@ func (ic *serviceImportCacheImpl) onServiceEvent(_, curr *model.Service, event m
 
 		// Get the ClusterSet VIPs for this service in this cluster. Will only be populated if the
 		// service has a ServiceImport in this cluster.
-		vips := ic.getClusterSetIPs(namespacedName)
+		vips, imported := ic.getClusterSetIPs(namespacedName)
 		name := namespacedName.Name
 		ns := namespacedName.Namespace
 
-		if len(vips) == 0 || (event == model.EventDelete &&
+		if !imported || (event == model.EventDelete &&
 			ic.opts.MeshServiceController.GetService(kube.ServiceHostname(name, ns, ic.opts.DomainSuffix)) == nil) {
 			if prevMcsService != nil {
 				// There are no vips in this cluster. Just delete the MCS service now.
@@ -154,8 +157,9 @@ func (ic *serviceImportCacheImpl) onServiceImportEvent(_, obj controllers.Object
 	mcsService := ic.GetService(mcsHost)
 
 	ips := GetServiceImportIPs(si)
+	imported := len(ips) > 0 || isHeadlessServiceImport(si)
 	if mcsService == nil {
-		if event == model.EventDelete || len(ips) == 0 {
+		if event == model.EventDelete || !imported {
 			// We never created the service. Nothing to delete.
 			return nil
 		}
@@ -175,7 +179,7 @@ func (ic *serviceImportCacheImpl) onServiceImportEvent(_, obj controllers.Object
 		// Create the MCS service from the cluster.local service.
 		mcsService = ic.genMCSService(realService, mcsHost, ips)
 	} else {
-		if event == model.EventDelete || len(ips) == 0 {
+		if event == model.EventDelete || !imported {
 			ic.deleteService(mcsService)
 			return nil
 		}
@@ -183,7 +187,18 @@ func (ic *serviceImportCacheImpl) onServiceImportEvent(_, obj controllers.Object
 		// The service already existed. Treat it as an update.
 		event = model.EventUpdate
 
-		if ic.updateIPs(mcsService, ips) {
+		if ic.typeChanged(mcsService, ips) {
+			// The ServiceImport switched between Headless and ClusterSetIP. Regenerate the MCS service from
+			// the cluster.local service, so that its resolution and default address match the new type.
+			realService := ic.opts.MeshServiceController.GetService(kube.ServiceHostnameForKR(si, ic.opts.DomainSuffix))
+			if realService == nil {
+				log.Warnf("failed processing %s event for ServiceImport %s/%s in cluster %s. No matching service found in cluster",
+					event, si.GetNamespace(), si.GetName(), ic.Cluster())
+				return nil
+			}
+			mcsService = ic.genMCSService(realService, mcsHost, ips)
+			needsFullPush = true
+		} else if ic.updateIPs(mcsService, ips) {
 			needsFullPush = true
 		}
 	}
@@ -201,14 +216,24 @@ func (ic *serviceImportCacheImpl) onServiceImportEvent(_, obj controllers.Object
 
 func (ic *serviceImportCacheImpl) updateIPs(mcsService *model.Service, ips []string) (updated bool) {
 	prevIPs := mcsService.ClusterVIPs.GetAddressesFor(ic.Cluster())
+	ips = clusterSetAddresses(ips)
 	if !slices.Equal(prevIPs, ips) {
 		// Update the VIPs
 		mcsService.ClusterVIPs.SetAddressesFor(ic.Cluster(), ips)
+		mcsService.DefaultAddress = ips[0]
 		updated = true
 	}
 	return
 }
 
+// typeChanged returns true if the MCS service is headless but the ServiceImport now has ClusterSet VIPs, or the
+// other way around.
+func (ic *serviceImportCacheImpl) typeChanged(mcsService *model.Service, ips []string) bool {
+	prevIPs := mcsService.ClusterVIPs.GetAddressesFor(ic.Cluster())
+	wasHeadless := len(prevIPs) == 1 && prevIPs[0] == constants.UnspecifiedIP
+	return wasHeadless != (len(ips) == 0)
+}
+
 func (ic *serviceImportCacheImpl) doFullPush(mcsHost host.Name, ns string) {
 	pushReq := &model.PushRequest{
 		Full:           true,
@@ -237,10 +262,32 @@ func GetServiceImportIPs(si *unstructured.Unstructured) []string {
 	return ips
 }
 
-// genMCSService generates an MCS service based on the given real k8s service. The list of vips must be non-empty.
+// isHeadlessServiceImport returns true if the ServiceImport is of the Headless type, so it has no ClusterSet VIPs.
+func isHeadlessServiceImport(si *unstructured.Unstructured) bool {
+	if spec, ok := si.Object["spec"].(map[string]any); ok {
+		return spec["type"] == string(mcsapi.Headless)
+	}
+	return false
+}
+
+// clusterSetAddresses returns the addresses of the MCS service for the ClusterSet VIPs. Without VIPs, the
+// service is headless and has the unspecified address, like headless k8s Services.
+func clusterSetAddresses(vips []string) []string {
+	if len(vips) == 0 {
+		return []string{constants.UnspecifiedIP}
+	}
+	return vips
+}
+
+// genMCSService generates an MCS service based on the given real k8s service. If the list of vips is empty, the
+// generated service is headless.
 func (ic *serviceImportCacheImpl) genMCSService(realService *model.Service, mcsHost host.Name, vips []string) *model.Service {
 	mcsService := realService.DeepCopy()
 	mcsService.Hostname = mcsHost
+	if len(vips) == 0 {
+		mcsService.Resolution = model.Passthrough
+	}
+	vips = clusterSetAddresses(vips)
 	mcsService.DefaultAddress = vips[0]
 	mcsService.ClusterVIPs.Addresses = map[cluster.ID][]string{
 		ic.Cluster(): vips,
@@ -249,12 +296,16 @@ func (ic *serviceImportCacheImpl) genMCSService(realService *model.Service, mcsH
 	return mcsService
 }
 
-func (ic *serviceImportCacheImpl) getClusterSetIPs(name types.NamespacedName) []string {
+// getClusterSetIPs returns the ClusterSet VIPs of the ServiceImport of the service in this cluster, and whether the
+// service is imported, either with ClusterSet VIPs or as a Headless service.
+func (ic *serviceImportCacheImpl) getClusterSetIPs(name types.NamespacedName) ([]string, bool) {
 	si := ic.serviceImports.Get(name.Name, name.Namespace)
-	if si != nil {
-		return GetServiceImportIPs(si.(*unstructured.Unstructured))
+	if si == nil {
+		return nil, false
 	}
-	return nil
+	usi := si.(*unstructured.Unstructured)
+	ips := GetServiceImportIPs(usi)
+	return ips, len(ips) > 0 || isHeadlessServiceImport(usi)
 }
 
 func (ic *serviceImportCacheImpl) ImportedServices() []importedService {
@@ -277,7 +328,7 @@ func (ic *serviceImportCacheImpl) ImportedServices() []importedService {
 		hostName := serviceClusterSetLocalHostnameForKR(usi)
 		svc := ic.servicesMap[hostName]
 		if svc != nil {
-			if vips := svc.ClusterVIPs.GetAddressesFor(ic.Cluster()); len(vips) > 0 {
+			if vips := svc.ClusterVIPs.GetAddressesFor(ic.Cluster()); len(vips) > 0 && vips[0] != constants.UnspecifiedIP {
 				info.clusterSetVIP = vips[0]
 			}
 		}
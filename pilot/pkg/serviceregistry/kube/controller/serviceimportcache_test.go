@@ -33,6 +33,7 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/serviceregistry/kube"
 	"istio.io/istio/pilot/pkg/serviceregistry/util/xdsfake"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/kube/mcs"
 	"istio.io/istio/pkg/test"
@@ -121,8 +122,48 @@ func TestHeadlessServiceImported(t *testing.T) {
 			ic.createKubeService(t, c)
 			ic.createServiceImport(t, mcsapi.Headless, nil)
 
-			// Verify that we did not generate the synthetic service for the headless service.
+			// Verify that we generated a headless synthetic service, without ClusterSet VIPs.
 			ic.checkServiceInstances(t)
+			svc := ic.GetService(serviceImportClusterSetHost)
+			assert.Equal(t, svc.Resolution, model.Passthrough)
+			assert.Equal(t, svc.DefaultAddress, constants.UnspecifiedIP)
+		})
+	}
+}
+
+func TestServiceImportTypeChanged(t *testing.T) {
+	for _, mode := range []EndpointMode{EndpointsOnly, EndpointSliceOnly} {
+		t.Run(mode.String(), func(t *testing.T) {
+			// Create and run the controller.
+			c, ic := newTestServiceImportCache(t, mode)
+
+			ic.createKubeService(t, c)
+			ic.createServiceImport(t, mcsapi.Headless, nil)
+			ic.checkServiceInstances(t)
+
+			// Switch to a ClusterSetIP import and verify that the service is no longer headless.
+			ic.setServiceImportType(t, mcsapi.ClusterSetIP, serviceImportVIPs)
+			retry.UntilSuccessOrFail(t, func() error {
+				svc := ic.GetService(serviceImportClusterSetHost)
+				if svc == nil || svc.DefaultAddress != serviceImportVIPs[0] {
+					return fmt.Errorf("expected default address %s for %s", serviceImportVIPs[0], serviceImportClusterSetHost)
+				}
+				return nil
+			}, serviceImportTimeout)
+			svc := ic.GetService(serviceImportClusterSetHost)
+			assert.Equal(t, svc.Resolution, model.ClientSideLB)
+			assert.Equal(t, svc.ClusterVIPs.GetAddressesFor(ic.Cluster()), serviceImportVIPs)
+
+			// Switch back to a Headless import.
+			ic.setServiceImportType(t, mcsapi.Headless, nil)
+			retry.UntilSuccessOrFail(t, func() error {
+				svc := ic.GetService(serviceImportClusterSetHost)
+				if svc == nil || svc.DefaultAddress != constants.UnspecifiedIP {
+					return fmt.Errorf("expected %s to be headless", serviceImportClusterSetHost)
+				}
+				return nil
+			}, serviceImportTimeout)
+			assert.Equal(t, ic.GetService(serviceImportClusterSetHost).Resolution, model.Passthrough)
 		})
 	}
 }
@@ -389,6 +430,10 @@ func (ic *serviceImportCacheImpl) checkServiceInstances(t *testing.T) {
 		expectedIPs = si.Spec.IPs
 		expectedServiceCount = 2
 		expectMCSService = true
+	} else if si != nil && si.Spec.Type == mcsapi.Headless {
+		expectedIPs = []string{constants.UnspecifiedIP}
+		expectedServiceCount = 2
+		expectMCSService = true
 	}
 
 	instances := ic.getProxyServiceInstances()
@@ -422,7 +467,7 @@ func (ic *serviceImportCacheImpl) createServiceImport(t *testing.T, importType m
 		t.Fatal(err)
 	}
 
-	shouldCreateMCSService := importType == mcsapi.ClusterSetIP && len(vips) > 0 &&
+	shouldCreateMCSService := (importType == mcsapi.Headless || len(vips) > 0) &&
 		ic.GetService(ic.clusterLocalHost()) != nil
 
 	// Wait for the import to be processed by the controller.
@@ -483,6 +528,18 @@ func (ic *serviceImportCacheImpl) setServiceImportVIPs(t *testing.T, vips []stri
 	}
 }
 
+func (ic *serviceImportCacheImpl) setServiceImportType(t *testing.T, importType mcsapi.ServiceImportType, vips []string) {
+	t.Helper()
+
+	si := ic.getServiceImport(t)
+	si.Spec.Type = importType
+	si.Spec.IPs = vips
+	if _, err := ic.client.Dynamic().Resource(mcs.ServiceImportGVR).Namespace(serviceImportNamespace).Update(
+		context.TODO(), toUnstructured(si), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func (ic *serviceImportCacheImpl) unimportService(t *testing.T) {
 	t.Helper()
 
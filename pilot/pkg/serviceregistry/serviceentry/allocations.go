@@ -0,0 +1,244 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"sync"
+
+	"go.uber.org/atomic"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+)
+
+const (
+	// IPAllocationsConfigMapName is the name of the ConfigMap persisting the addresses auto-allocated to the
+	// ServiceEntries.
+	IPAllocationsConfigMapName = "istio-ip-allocations"
+	// ipAllocationsKey is the key of the ConfigMap holding the allocations, a JSON object mapping the namespace/host
+	// of the services to their IPv4 address.
+	ipAllocationsKey = "allocations"
+)
+
+// ipAllocationTable persists the addresses auto-allocated to the ServiceEntries in a ConfigMap of the system
+// namespace, so a service keeps its address across the restarts and the upgrades of istiod, as well as when other
+// services are added or removed: the long-lived connections of the clients to the address are not broken.
+//
+// Every istiod reads the table before allocating, so the services of the table keep their address, and the leader
+// writes back the allocations of the current services: the istiods converge to the same allocations.
+type ipAllocationTable struct {
+	namespace string
+	// hasSynced returns true once the ServiceEntries are synced: the allocations of a partial list of services must
+	// not be persisted, as the services missing from the list would lose their address.
+	hasSynced func() bool
+	// onChange is called when the table is modified by another istiod.
+	onChange func()
+
+	client kube.Client
+	// configmaps only watches the ConfigMap of the table, rather than sharing the cluster wide ConfigMaps informer.
+	configmaps cache.SharedIndexInformer
+	queue      controllers.Queue
+	// writer is true while this istiod is the leader persisting the table.
+	writer *atomic.Bool
+
+	mu sync.Mutex
+	// desired is the table to persist, nil until the allocations of the synced services are known.
+	desired map[string]octetPair
+}
+
+func newIPAllocationTable(client kube.Client, namespace string, hasSynced func() bool, onChange func()) *ipAllocationTable {
+	t := &ipAllocationTable{
+		client:    client,
+		namespace: namespace,
+		hasSynced: hasSynced,
+		onChange:  onChange,
+		writer:    atomic.NewBool(false),
+	}
+	t.queue = controllers.NewQueue("serviceentry ip allocations",
+		controllers.WithReconciler(t.reconcile),
+		controllers.WithMaxAttempts(5))
+	selector := fields.OneTermEqualSelector(metav1.ObjectNameField, IPAllocationsConfigMapName).String()
+	t.configmaps = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = selector
+				return client.Kube().CoreV1().ConfigMaps(namespace).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = selector
+				return client.Kube().CoreV1().ConfigMaps(namespace).Watch(context.TODO(), opts)
+			},
+		},
+		&v1.ConfigMap{}, 0, cache.Indexers{},
+	)
+	_ = t.configmaps.SetTransform(kube.StripUnusedFields)
+	_, _ = t.configmaps.AddEventHandler(controllers.FilteredObjectHandler(t.onConfigMapEvent, func(o controllers.Object) bool {
+		return o.GetNamespace() == t.namespace && o.GetName() == IPAllocationsConfigMapName
+	}))
+	return t
+}
+
+// Run watches the persisted allocations until a value is sent to stopCh. Once the table and the ServiceEntries are
+// synced, the addresses are reallocated, so the allocations of the services are persisted even if none of them
+// changes.
+func (t *ipAllocationTable) Run(stopCh <-chan struct{}) {
+	go t.configmaps.Run(stopCh)
+	if !kube.WaitForCacheSync(stopCh, t.configmaps.HasSynced, t.hasSynced) {
+		log.Errorf("failed to sync ip allocations cache")
+		return
+	}
+	t.onChange()
+	t.queue.Run(stopCh)
+}
+
+// RunWriter persists the allocations until a value is sent to stopCh. It is only run by the leader, so the istiods do
+// not race to write their own table.
+func (t *ipAllocationTable) RunWriter(stopCh <-chan struct{}) {
+	t.writer.Store(true)
+	// Write the allocations recorded before the leadership was acquired
+	t.queue.Add(types.NamespacedName{Name: IPAllocationsConfigMapName, Namespace: t.namespace})
+	<-stopCh
+	t.writer.Store(false)
+}
+
+// get returns the persisted allocations, keyed by the namespace/host of the services.
+func (t *ipAllocationTable) get() map[string]octetPair {
+	cm := t.configMap()
+	if cm == nil {
+		return nil
+	}
+	allocations, err := parseIPAllocations(cm.Data[ipAllocationsKey])
+	if err != nil {
+		log.Warnf("ignoring invalid ip allocations of %s/%s: %v", t.namespace, IPAllocationsConfigMapName, err)
+		return nil
+	}
+	return allocations
+}
+
+// set records the allocations of the services, to be persisted.
+func (t *ipAllocationTable) set(allocations map[string]octetPair) {
+	// Until the table is read, the allocations are not based on the persisted ones and must not override them.
+	if !t.configmaps.HasSynced() || !t.hasSynced() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if reflect.DeepEqual(t.desired, allocations) {
+		return
+	}
+	t.desired = allocations
+	t.queue.Add(types.NamespacedName{Name: IPAllocationsConfigMapName, Namespace: t.namespace})
+}
+
+func (t *ipAllocationTable) onConfigMapEvent(o controllers.Object) {
+	cm := o.(*v1.ConfigMap)
+	t.mu.Lock()
+	desired := t.desired
+	t.mu.Unlock()
+	if cm.DeletionTimestamp == nil && cm.Data[ipAllocationsKey] == marshalIPAllocations(desired) {
+		// Our own write.
+		return
+	}
+	t.onChange()
+}
+
+func (t *ipAllocationTable) configMap() *v1.ConfigMap {
+	obj, _, _ := t.configmaps.GetStore().GetByKey(t.namespace + "/" + IPAllocationsConfigMapName)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*v1.ConfigMap)
+}
+
+func (t *ipAllocationTable) reconcile(key types.NamespacedName) error {
+	if !t.writer.Load() {
+		return nil
+	}
+	t.mu.Lock()
+	desired := t.desired
+	t.mu.Unlock()
+	if desired == nil {
+		return nil
+	}
+	data := marshalIPAllocations(desired)
+	configmaps := t.client.Kube().CoreV1().ConfigMaps(key.Namespace)
+	cm := t.configMap()
+	if cm == nil {
+		_, err := configmaps.Create(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string]string{ipAllocationsKey: data},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if cm.Data[ipAllocationsKey] == data {
+		return nil
+	}
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[ipAllocationsKey] = data
+	// A conflict with the write of a previous leader is retried with the table it wrote.
+	_, err := configmaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return err
+}
+
+func marshalIPAllocations(allocations map[string]octetPair) string {
+	if allocations == nil {
+		return ""
+	}
+	addresses := make(map[string]string, len(allocations))
+	for key, pair := range allocations {
+		addresses[key] = pair.ipv4()
+	}
+	// The keys of the maps are sorted, the table is only written when it changes.
+	out, _ := json.Marshal(addresses)
+	return string(out)
+}
+
+func parseIPAllocations(data string) (map[string]octetPair, error) {
+	if data == "" {
+		return nil, nil
+	}
+	addresses := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &addresses); err != nil {
+		return nil, err
+	}
+	allocations := make(map[string]octetPair, len(addresses))
+	for key, address := range addresses {
+		ip, err := netip.ParseAddr(address)
+		if err != nil || !ip.Is4() {
+			return nil, fmt.Errorf("invalid address %q of %s", address, key)
+		}
+		b := ip.As4()
+		if b[0] != 240 || b[1] != 240 || b[3] == 0 || b[3] == 255 {
+			return nil, fmt.Errorf("address %s of %s is out of the allocation range 240.240.0.0/16", address, key)
+		}
+		allocations[key] = octetPair{thirdOctet: int(b[2]), fourthOctet: int(b[3])}
+	}
+	return allocations, nil
+}
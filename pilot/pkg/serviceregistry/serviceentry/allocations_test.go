@@ -0,0 +1,132 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceentry
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/atomic"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
+	"istio.io/istio/pkg/kube/kclient/clienttest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func allocationServices(hosts ...string) []*model.Service {
+	var services []*model.Service
+	for _, h := range hosts {
+		services = append(services, &model.Service{
+			Hostname:       host.Name(h),
+			Resolution:     model.ClientSideLB,
+			DefaultAddress: constants.UnspecifiedIP,
+			Attributes:     model.ServiceAttributes{Namespace: "ns"},
+		})
+	}
+	return services
+}
+
+// addresses returns the addresses of the allocations, octetPair cannot be compared by assert.
+func addresses(allocations map[string]octetPair) map[string]string {
+	if allocations == nil {
+		return nil
+	}
+	out := make(map[string]string, len(allocations))
+	for key, pair := range allocations {
+		out[key] = pair.ipv4()
+	}
+	return out
+}
+
+func TestAllocateIPsPinned(t *testing.T) {
+	services := allocationServices("a.com", "b.com", "c.com")
+	pinned := map[string]octetPair{
+		"ns/a.com": {1, 1},
+		// Conflicts with a.com, b.com is allocated another address.
+		"ns/b.com": {1, 1},
+		// Not a service anymore, its address is not allocated.
+		"ns/gone.com": {2, 2},
+	}
+	allocations := allocateIPs(services, pinned)
+
+	assert.Equal(t, services[0].AutoAllocatedIPv4Address, "240.240.1.1")
+	assert.Equal(t, services[0].AutoAllocatedIPv6Address, "2001:2::f0f0:11")
+	assert.Equal(t, len(allocations), 3)
+	assert.Equal(t, allocations["ns/a.com"].ipv4(), "240.240.1.1")
+	for _, svc := range services[1:] {
+		if svc.AutoAllocatedIPv4Address == "" || svc.AutoAllocatedIPv4Address == "240.240.1.1" {
+			t.Fatalf("unexpected address %q allocated to %s", svc.AutoAllocatedIPv4Address, svc.Hostname)
+		}
+		assert.Equal(t, allocations[makeServiceKey(svc)].ipv4(), svc.AutoAllocatedIPv4Address)
+	}
+
+	// The persisted addresses are kept when services are added.
+	services = allocationServices("0.com", "a.com", "b.com", "c.com")
+	reallocated := allocateIPs(services, allocations)
+	for _, svc := range services[1:] {
+		assert.Equal(t, reallocated[makeServiceKey(svc)].ipv4(), allocations[makeServiceKey(svc)].ipv4())
+	}
+}
+
+func TestIPAllocationTable(t *testing.T) {
+	client := kube.NewFakeClient()
+	changes := atomic.NewInt32(0)
+	table := newIPAllocationTable(client, "istio-system", func() bool { return true }, func() { changes.Inc() })
+	configmaps := clienttest.Wrap(t, kclient.New[*v1.ConfigMap](client))
+	stop := test.NewStop(t)
+	client.RunAndWait(stop)
+	go table.Run(stop)
+	// The addresses are reallocated once synced, to persist them.
+	assert.EventuallyEqual(t, changes.Load, int32(1))
+
+	persisted := func() string {
+		cm := configmaps.Get(IPAllocationsConfigMapName, "istio-system")
+		if cm == nil {
+			return ""
+		}
+		return cm.Data[ipAllocationsKey]
+	}
+	table.set(map[string]octetPair{"ns/a.com": {1, 1}, "ns/b.com": {0, 2}})
+	// Only the leader writes the table.
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, persisted(), "")
+	go table.RunWriter(stop)
+	assert.EventuallyEqual(t, persisted, `{"ns/a.com":"240.240.1.1","ns/b.com":"240.240.0.2"}`)
+	assert.Equal(t, addresses(table.get()), map[string]string{"ns/a.com": "240.240.1.1", "ns/b.com": "240.240.0.2"})
+	assert.Equal(t, changes.Load(), int32(1))
+
+	// The table written by another istiod triggers a reallocation.
+	configmaps.Update(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: IPAllocationsConfigMapName, Namespace: "istio-system"},
+		Data:       map[string]string{ipAllocationsKey: `{"ns/a.com":"240.240.1.1","ns/c.com":"240.240.0.3"}`},
+	})
+	assert.EventuallyEqual(t, changes.Load, int32(2))
+	assert.Equal(t, addresses(table.get()), map[string]string{"ns/a.com": "240.240.1.1", "ns/c.com": "240.240.0.3"})
+
+	// An invalid table is ignored.
+	configmaps.Update(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: IPAllocationsConfigMapName, Namespace: "istio-system"},
+		Data:       map[string]string{ipAllocationsKey: `{"ns/a.com":"10.0.0.1"}`},
+	})
+	assert.EventuallyEqual(t, changes.Load, int32(3))
+	assert.Equal(t, addresses(table.get()), nil)
+}
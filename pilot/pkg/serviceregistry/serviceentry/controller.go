@@ -37,6 +37,8 @@ import (
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/network"
 	"istio.io/istio/pkg/queue"
 	"istio.io/istio/pkg/util/protomarshal"
@@ -66,6 +68,10 @@ type octetPair struct {
 	fourthOctet int
 }
 
+func (o octetPair) ipv4() string {
+	return fmt.Sprintf("240.240.%d.%d", o.thirdOctet, o.fourthOctet)
+}
+
 func makeInstanceKey(i *model.ServiceInstance) instancesKey {
 	return instancesKey{i.Service.Hostname, i.Service.Attributes.Namespace}
 }
@@ -115,6 +121,9 @@ type Controller struct {
 	// Indicates whether this controller is for workload entries.
 	workloadEntryController bool
 
+	// allocations persists the auto-allocated addresses, if enabled.
+	allocations *ipAllocationTable
+
 	model.NoopAmbientIndexes
 	model.NetworkGatewaysHandler
 }
@@ -133,14 +142,22 @@ func WithNetworkIDCb(cb func(endpointIP string, labels labels.Instance) network.
 	}
 }
 
+// WithIPAllocationTable persists the addresses auto-allocated to the services in a ConfigMap of the namespace, so they
+// are kept across the restarts of istiod. hasSynced returns true once the ServiceEntries are synced.
+func WithIPAllocationTable(client kube.Client, namespace string, hasSynced func() bool) Option {
+	return func(o *Controller) {
+		o.allocations = newIPAllocationTable(client, namespace, hasSynced, o.onIPAllocationsChange)
+	}
+}
+
 // NewController creates a new ServiceEntry discovery service.
 func NewController(configController model.ConfigStoreController, xdsUpdater model.XDSUpdater,
 	options ...Option,
 ) *Controller {
 	s := newController(configController, xdsUpdater, options...)
 	if configController != nil {
-		configController.RegisterEventHandler(gvk.ServiceEntry, s.serviceEntryHandler)
-		configController.RegisterEventHandler(gvk.WorkloadEntry, s.workloadEntryHandler)
+		configController.RegisterEventHandler(gvk.ServiceEntry, s.instrumented(kind.ServiceEntry, s.serviceEntryHandler))
+		configController.RegisterEventHandler(gvk.WorkloadEntry, s.instrumented(kind.WorkloadEntry, s.workloadEntryHandler))
 	}
 	return s
 }
@@ -157,11 +174,22 @@ func NewWorkloadEntryController(configController model.ConfigStoreController, xd
 	}
 
 	if configController != nil {
-		configController.RegisterEventHandler(gvk.WorkloadEntry, s.workloadEntryHandler)
+		configController.RegisterEventHandler(gvk.WorkloadEntry, s.instrumented(kind.WorkloadEntry, s.workloadEntryHandler))
 	}
 	return s
 }
 
+// instrumented wraps the handler of the configs of the kind, recording their events and handling durations.
+func (s *Controller) instrumented(k kind.Kind, h model.EventHandler) model.EventHandler {
+	metrics := controllers.NewMetrics("serviceentry", s.clusterID)
+	return func(old, curr config.Config, event model.Event) {
+		start := time.Now()
+		metrics.Event(k, event.String())
+		h(old, curr, event)
+		metrics.Reconcile(k, start, nil)
+	}
+}
+
 func newController(store model.ConfigStore, xdsUpdater model.XDSUpdater, options ...Option) *Controller {
 	s := &Controller{
 		XdsUpdater: xdsUpdater,
@@ -593,9 +621,21 @@ func (s *Controller) AppendWorkloadHandler(h func(*model.WorkloadInstance, model
 
 // Run is used by some controllers to execute background jobs after init is done.
 func (s *Controller) Run(stopCh <-chan struct{}) {
+	if s.allocations != nil {
+		go s.allocations.Run(stopCh)
+	}
 	s.edsQueue.Run(stopCh)
 }
 
+// RunIPAllocationsWriter persists the addresses auto-allocated to the services until a value is sent to stopCh. It is
+// run by the leader only.
+func (s *Controller) RunIPAllocationsWriter(stopCh <-chan struct{}) {
+	if s.allocations == nil {
+		return
+	}
+	s.allocations.RunWriter(stopCh)
+}
+
 // HasSynced always returns true for SE
 func (s *Controller) HasSynced() bool {
 	return true
@@ -607,7 +647,11 @@ func (s *Controller) Services() []*model.Service {
 	allServices := s.services.getAllServices()
 	out := make([]*model.Service, 0, len(allServices))
 	if s.services.allocateNeeded {
-		autoAllocateIPs(allServices)
+		if s.allocations != nil {
+			s.allocations.set(allocateIPs(allServices, s.allocations.get()))
+		} else {
+			autoAllocateIPs(allServices)
+		}
 		s.services.allocateNeeded = false
 	}
 	s.mutex.Unlock()
@@ -621,6 +665,18 @@ func (s *Controller) Services() []*model.Service {
 	return out
 }
 
+// onIPAllocationsChange reallocates the addresses of the services when the persisted allocations are modified by
+// another istiod.
+func (s *Controller) onIPAllocationsChange() {
+	s.mutex.Lock()
+	s.services.allocateNeeded = true
+	s.mutex.Unlock()
+	s.XdsUpdater.ConfigUpdate(&model.PushRequest{
+		Full:   true,
+		Reason: []model.TriggerReason{model.ServiceUpdate},
+	})
+}
+
 // GetService retrieves a service by host name if it exists.
 // NOTE: The service entry implementation is used only for tests.
 func (s *Controller) GetService(hostname host.Name) *model.Service {
@@ -857,6 +913,31 @@ func servicesDiff(os []*model.Service, ns []*model.Service) ([]*model.Service, [
 //
 // The current algorithm to allocate IPs is deterministic across all istiods.
 func autoAllocateIPs(services []*model.Service) []*model.Service {
+	allocateIPs(services, nil)
+	return services
+}
+
+// allocateIPs allocates the IPs of the services as autoAllocateIPs, keeping the persisted allocations of the services
+// found in the pinned table, keyed by the namespace/host of the services. It returns the allocations of the services.
+func allocateIPs(services []*model.Service, pinned map[string]octetPair) map[string]octetPair {
+	allocations := make(map[string]octetPair)
+	used := sets.New[octetPair]()
+	for _, svc := range services {
+		if !needsAutoAllocation(svc) {
+			continue
+		}
+		n := makeServiceKey(svc)
+		pair, ok := allocations[n]
+		if !ok {
+			if pair, ok = pinned[n]; !ok || used.Contains(pair) {
+				continue
+			}
+			allocations[n] = pair
+			used.Insert(pair)
+		}
+		setAutoAllocatedIPs(svc, pair)
+	}
+
 	hashedServices := make([]*model.Service, maxIPs)
 	hash := fnv.New32a()
 	// First iterate through the range of services and determine its position by hash
@@ -874,9 +955,13 @@ func autoAllocateIPs(services []*model.Service) []*model.Service {
 		//   for NONE because we will not know the original DST IP that the application requested.
 		// 2. the address is not set (0.0.0.0)
 		// 3. the hostname is not a wildcard
-		if svc.DefaultAddress == constants.UnspecifiedIP && !svc.Hostname.IsWildCarded() &&
-			svc.Resolution != model.Passthrough {
-			hash.Write([]byte(makeServiceKey(svc)))
+		// 4. the service has no persisted allocation
+		if needsAutoAllocation(svc) {
+			n := makeServiceKey(svc)
+			if _, f := allocations[n]; f {
+				continue
+			}
+			hash.Write([]byte(n))
 			// First hash is calculated by
 			s := hash.Sum32()
 			firstHash := s % uint32(maxIPs)
@@ -908,37 +993,45 @@ func autoAllocateIPs(services []*model.Service) []*model.Service {
 	// For example, when X=510, the resulting IP would be 240.240.2.0 (invalid)
 	// So we bump X to 511, so that the resulting IP is 240.240.2.1
 	x := 0
-	hnMap := make(map[string]octetPair)
-	allocated := 0
+	next := func() {
+		x++
+		if x%255 == 0 {
+			x++
+		}
+	}
+	allocated := used.Len()
 	for _, svc := range hashedServices {
 		if svc == nil {
 			// There is no service in the slot. Just increment x and move forward.
-			x++
-			if x%255 == 0 {
-				x++
-			}
+			next()
 			continue
 		}
 		n := makeServiceKey(svc)
-		if v, ok := hnMap[n]; ok {
+		if v, ok := allocations[n]; ok {
 			log.Debugf("Reuse IP for domain %s", n)
 			setAutoAllocatedIPs(svc, v)
 		} else {
-			x++
-			if x%255 == 0 {
-				x++
+			next()
+			// Skip the IPs persisted for other services.
+			for used.Contains(octetPair{x / 255, x % 255}) {
+				next()
 			}
 			if allocated >= maxIPs {
 				log.Errorf("out of IPs to allocate for service entries. x:= %d, maxips:= %d", x, maxIPs)
-				return services
+				return allocations
 			}
 			allocated++
 			pair := octetPair{x / 255, x % 255}
 			setAutoAllocatedIPs(svc, pair)
-			hnMap[n] = pair
+			allocations[n] = pair
 		}
 	}
-	return services
+	return allocations
+}
+
+func needsAutoAllocation(svc *model.Service) bool {
+	return svc.DefaultAddress == constants.UnspecifiedIP && !svc.Hostname.IsWildCarded() &&
+		svc.Resolution != model.Passthrough
 }
 
 func makeServiceKey(svc *model.Service) string {
@@ -948,7 +1041,7 @@ func makeServiceKey(svc *model.Service) string {
 func setAutoAllocatedIPs(svc *model.Service, octets octetPair) {
 	a := octets.thirdOctet
 	b := octets.fourthOctet
-	svc.AutoAllocatedIPv4Address = fmt.Sprintf("240.240.%d.%d", a, b)
+	svc.AutoAllocatedIPv4Address = octets.ipv4()
 	if a == 0 {
 		svc.AutoAllocatedIPv6Address = fmt.Sprintf("2001:2::f0f0:%x", b)
 	} else {
@@ -202,12 +202,13 @@ func convertServices(cfg config.Config) []*model.Service {
 	}
 
 	return buildServices(hostAddresses, cfg.Name, cfg.Namespace, svcPorts, serviceEntry.Location, resolution,
-		exportTo, labelSelectors, serviceEntry.SubjectAltNames, creationTime, cfg.Labels)
+		exportTo, labelSelectors, serviceEntry.SubjectAltNames, creationTime, cfg.Labels,
+		cfg.Annotations[constants.DynamicForwardProxyAnnotation] == "true")
 }
 
 func buildServices(hostAddresses []*HostAddress, name, namespace string, ports model.PortList, location networking.ServiceEntry_Location,
 	resolution model.Resolution, exportTo map[visibility.Instance]bool, selectors map[string]string, saccounts []string,
-	ctime time.Time, labels map[string]string,
+	ctime time.Time, labels map[string]string, dynamicForwardProxy bool,
 ) []*model.Service {
 	out := make([]*model.Service, 0, len(hostAddresses))
 	lbls := labels
@@ -229,6 +230,8 @@ func buildServices(hostAddresses []*HostAddress, name, namespace string, ports m
 				Labels:          lbls,
 				ExportTo:        exportTo,
 				LabelSelectors:  selectors,
+
+				DynamicForwardProxy: dynamicForwardProxy,
 			},
 			ServiceAccounts: saccounts,
 		})
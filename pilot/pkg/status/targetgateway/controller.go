@@ -0,0 +1,169 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package targetgateway writes the TargetResolved condition to the status of the AuthorizationPolicies targeting a
+// Kubernetes Gateway, so that a policy applying to no listener is visible on the resource itself.
+package targetgateway
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/meta/v1alpha1"
+	"istio.io/istio/pilot/pkg/model"
+	istiostatus "istio.io/istio/pilot/pkg/model/status"
+	"istio.io/istio/pilot/pkg/status"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/controllers"
+	istiolog "istio.io/pkg/log"
+)
+
+var log = istiolog.RegisterScope("targetgateway", "authorization policy target gateway status", 0)
+
+// Controller reconciles the TargetResolved condition of the AuthorizationPolicies annotated with
+// constants.TargetGatewayAnnotation. Events are watched from creation, but only handled while Run is in progress, which
+// is while this istiod holds the status leader lock.
+type Controller struct {
+	store  model.ConfigStoreController
+	writer *status.Controller
+
+	mu sync.Mutex
+	// queue is the queue of the policies to reconcile, nil when not running.
+	queue *controllers.Queue
+}
+
+func NewController(store model.ConfigStoreController) *Controller {
+	c := &Controller{store: store}
+	store.RegisterEventHandler(gvk.AuthorizationPolicy, func(_, cfg config.Config, _ model.Event) {
+		c.enqueue(types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name})
+	})
+	store.RegisterEventHandler(gvk.KubernetesGateway, func(_, cfg config.Config, _ model.Event) {
+		for _, policy := range store.List(gvk.AuthorizationPolicy, cfg.Namespace) {
+			if policy.Annotations[constants.TargetGatewayAnnotation] == cfg.Name {
+				c.enqueue(types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
+			}
+		}
+	})
+	return c
+}
+
+// Run reconciles every AuthorizationPolicy, then those affected by config events, until stop is closed. The conditions
+// are written through the status manager, which only exists once istiod started.
+func (c *Controller) Run(stop <-chan struct{}, m *status.Manager) {
+	c.writer = m.CreateIstioStatusController(func(current *v1alpha1.IstioStatus, context any) *v1alpha1.IstioStatus {
+		desired := context.(*v1alpha1.IstioCondition)
+		if current == nil {
+			current = &v1alpha1.IstioStatus{}
+		}
+		if desired == nil {
+			current.Conditions = removeCondition(current.Conditions, istiostatus.ConditionTargetResolved)
+		} else {
+			current.Conditions = istiostatus.UpdateCondition(current.Conditions, desired)
+		}
+		return current
+	})
+	q := controllers.NewQueue("authorization policy target gateway",
+		controllers.WithReconciler(c.reconcile),
+		controllers.WithMaxAttempts(5))
+	c.mu.Lock()
+	c.queue = &q
+	c.mu.Unlock()
+	for _, policy := range c.store.List(gvk.AuthorizationPolicy, metav1.NamespaceAll) {
+		q.Add(types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
+	}
+	q.Run(stop)
+	c.mu.Lock()
+	c.queue = nil
+	c.mu.Unlock()
+}
+
+func (c *Controller) enqueue(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.queue != nil {
+		c.queue.Add(key)
+	}
+}
+
+func (c *Controller) reconcile(key types.NamespacedName) error {
+	policy := c.store.Get(gvk.AuthorizationPolicy, key.Name, key.Namespace)
+	if policy == nil {
+		return nil
+	}
+	desired := c.targetCondition(*policy)
+	current := istiostatus.GetConditionFromSpec(*policy, istiostatus.ConditionTargetResolved)
+	if desired == nil && current == nil {
+		return nil
+	}
+	// Writing the status triggers an event for the policy, only write when the condition changes to not loop.
+	if desired != nil && current != nil && desired.Status == current.Status && desired.Reason == current.Reason &&
+		desired.Message == current.Message {
+		return nil
+	}
+	log.Debugf("updating %s condition of authorization policy %s: %v", istiostatus.ConditionTargetResolved, key, desired)
+	c.writer.EnqueueStatusUpdateResource(desired, status.ResourceFromModelConfig(*policy))
+	return nil
+}
+
+// targetCondition returns the TargetResolved condition of the policy, nil if it does not target a Gateway.
+func (c *Controller) targetCondition(policy config.Config) *v1alpha1.IstioCondition {
+	gateway := policy.Annotations[constants.TargetGatewayAnnotation]
+	if gateway == "" {
+		return nil
+	}
+	cond := &v1alpha1.IstioCondition{
+		Type:               istiostatus.ConditionTargetResolved,
+		Status:             istiostatus.StatusTrue,
+		Reason:             istiostatus.ReasonResolved,
+		LastProbeTime:      timestamppb.Now(),
+		LastTransitionTime: timestamppb.Now(),
+	}
+	gw := c.store.Get(gvk.KubernetesGateway, gateway, policy.Namespace)
+	if gw == nil {
+		cond.Status = istiostatus.StatusFalse
+		cond.Reason = istiostatus.ReasonGatewayNotFound
+		cond.Message = fmt.Sprintf("gateway %s/%s not found", policy.Namespace, gateway)
+		return cond
+	}
+	section := policy.Annotations[constants.TargetSectionNameAnnotation]
+	if section == "" {
+		return cond
+	}
+	for _, l := range gw.Spec.(*v1beta1.GatewaySpec).Listeners {
+		if string(l.Name) == section {
+			return cond
+		}
+	}
+	cond.Status = istiostatus.StatusFalse
+	cond.Reason = istiostatus.ReasonListenerNotFound
+	cond.Message = fmt.Sprintf("listener %s not found in gateway %s/%s, the policy applies to no traffic", section, policy.Namespace, gateway)
+	return cond
+}
+
+func removeCondition(conditions []*v1alpha1.IstioCondition, condition string) []*v1alpha1.IstioCondition {
+	ret := make([]*v1alpha1.IstioCondition, 0, len(conditions))
+	for _, cond := range conditions {
+		if cond.Type != condition {
+			ret = append(ret, cond)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,98 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targetgateway
+
+import (
+	"testing"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	authpb "istio.io/api/security/v1beta1"
+	"istio.io/istio/pilot/pkg/config/memory"
+	istiostatus "istio.io/istio/pilot/pkg/model/status"
+	"istio.io/istio/pilot/pkg/status"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/collections"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestTargetResolvedCondition(t *testing.T) {
+	store := memory.NewSyncController(memory.MakeSkipValidation(collections.PilotGatewayAPI))
+	stop := test.NewStop(t)
+	go store.Run(stop)
+	m := status.NewManager(store)
+	m.Start(stop)
+	c := NewController(store)
+	go c.Run(stop, m)
+
+	policy := func(name, gateway, section string) config.Config {
+		annotations := map[string]string{}
+		if gateway != "" {
+			annotations[constants.TargetGatewayAnnotation] = gateway
+		}
+		if section != "" {
+			annotations[constants.TargetSectionNameAnnotation] = section
+		}
+		return config.Config{
+			Meta: config.Meta{
+				GroupVersionKind: gvk.AuthorizationPolicy,
+				Name:             name,
+				Namespace:        "ns",
+				Annotations:      annotations,
+			},
+			Spec: &authpb.AuthorizationPolicy{},
+		}
+	}
+	condition := func(name string) func() string {
+		return func() string {
+			cfg := store.Get(gvk.AuthorizationPolicy, name, "ns")
+			if cfg == nil {
+				return ""
+			}
+			cond := istiostatus.GetConditionFromSpec(*cfg, istiostatus.ConditionTargetResolved)
+			if cond == nil {
+				return ""
+			}
+			return cond.Status + "/" + cond.Reason
+		}
+	}
+	create := func(cfg config.Config) {
+		t.Helper()
+		if _, err := store.Create(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	create(policy("selector", "", ""))
+	create(policy("gateway", "gw", ""))
+	create(policy("listener", "gw", "https"))
+	assert.EventuallyEqual(t, condition("gateway"), "False/"+istiostatus.ReasonGatewayNotFound)
+	assert.EventuallyEqual(t, condition("listener"), "False/"+istiostatus.ReasonGatewayNotFound)
+
+	create(config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.KubernetesGateway,
+			Name:             "gw",
+			Namespace:        "ns",
+		},
+		Spec: &v1beta1.GatewaySpec{Listeners: []v1beta1.Listener{{Name: "http"}}},
+	})
+	assert.EventuallyEqual(t, condition("gateway"), "True/"+istiostatus.ReasonResolved)
+	assert.EventuallyEqual(t, condition("listener"), "False/"+istiostatus.ReasonListenerNotFound)
+	assert.Equal(t, condition("selector")(), "")
+}
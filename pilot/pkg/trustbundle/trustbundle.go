@@ -62,6 +62,7 @@ const (
 	SourceMeshConfig
 	SourceIstioRA
 	sourceSpiffeEndpoints
+	SourceSpire
 
 	RemoteDefaultPollPeriod = 30 * time.Minute
 )
@@ -87,6 +88,7 @@ func NewTrustBundle(remoteCaCertPool *x509.CertPool) *TrustBundle {
 			SourceMeshConfig:      {Certs: []string{}},
 			SourceIstioRA:         {Certs: []string{}},
 			sourceSpiffeEndpoints: {Certs: []string{}},
+			SourceSpire:           {Certs: []string{}},
 		},
 		mergedCerts:        []string{},
 		updatecb:           nil,
@@ -233,6 +235,30 @@ func (tb *TrustBundle) AddMeshConfigUpdate(cfg *meshconfig.MeshConfig) error {
 	return nil
 }
 
+// UpdateSpireTrustBundle : Replace the trustAnchors published by SPIRE with the PEM encoded bundle
+func (tb *TrustBundle) UpdateSpireTrustBundle(bundle []byte) error {
+	certs := []string{}
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certs = append(certs, string(pem.EncodeToMemory(block)))
+	}
+	err := tb.UpdateTrustAnchor(&TrustAnchorUpdate{
+		TrustAnchorConfig: TrustAnchorConfig{Certs: certs},
+		Source:            SourceSpire,
+	})
+	if err != nil {
+		trustBundleLog.Errorf("failed to update SPIRE trustAnchors: %v", err)
+	}
+	return err
+}
+
 func (tb *TrustBundle) fetchRemoteTrustAnchors() {
 	var err error
 
@@ -255,6 +255,46 @@ func TestUpdateTrustAnchor(t *testing.T) {
 	}
 }
 
+func TestUpdateSpireTrustBundle(t *testing.T) {
+	tb := NewTrustBundle(nil)
+	cbCounter := 0
+	tb.UpdateCb(func() { cbCounter++ })
+
+	// The SPIRE k8sbundle notifier publishes all roots concatenated in a single PEM file
+	err := tb.UpdateSpireTrustBundle([]byte(rootCACert + intermediateCACert))
+	if err != nil {
+		t.Errorf("spire bundle update failed. Error: %v", err)
+	}
+	expectTbCount(t, tb, 2, time.Second, "spire bundle update failed")
+	if cbCounter != 1 {
+		t.Errorf("spire bundle update failed. Callback value is %v", cbCounter)
+	}
+
+	// Roots from SPIRE are merged with roots from other sources
+	err = tb.UpdateTrustAnchor(&TrustAnchorUpdate{
+		TrustAnchorConfig: TrustAnchorConfig{Certs: []string{rootCACert}},
+		Source:            SourceIstioCA,
+	})
+	if err != nil {
+		t.Errorf("istioca update failed. Error: %v", err)
+	}
+	expectTbCount(t, tb, 2, time.Second, "merge with istioca failed")
+
+	// A non CA certificate is rejected and leaves the bundle unchanged
+	err = tb.UpdateSpireTrustBundle([]byte(nonCaCert))
+	if err == nil {
+		t.Errorf("non CA spire bundle update succeeded. Expected error")
+	}
+	expectTbCount(t, tb, 2, time.Second, "non CA spire bundle update changed trustbundle")
+
+	// An empty bundle removes the SPIRE roots only
+	err = tb.UpdateSpireTrustBundle(nil)
+	if err != nil {
+		t.Errorf("empty spire bundle update failed. Error: %v", err)
+	}
+	expectTbCount(t, tb, 1, time.Second, "empty spire bundle update failed")
+}
+
 func expectTbCount(t *testing.T, tb *TrustBundle, expAnchorCount int, ti time.Duration, strPrefix string) {
 	t.Helper()
 	retry.UntilSuccessOrFail(t, func() error {
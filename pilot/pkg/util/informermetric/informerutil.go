@@ -16,6 +16,7 @@ package informermetric
 
 import (
 	"sync"
+	"time"
 
 	"k8s.io/client-go/tools/cache"
 
@@ -34,8 +35,19 @@ var (
 
 	mu       sync.RWMutex
 	handlers = map[cluster.ID]cache.WatchErrorHandler{}
+	failures = map[cluster.ID]*watchFailures{}
 )
 
+// watchRecoveryWindow is how long the watches of a cluster must not fail to be considered recovered: the reflectors
+// retry a failed list or watch with a backoff of at most a minute.
+const watchRecoveryWindow = 2 * time.Minute
+
+// watchFailures tracks when the watches of a cluster started failing, and last failed.
+type watchFailures struct {
+	since time.Time
+	last  time.Time
+}
+
 func init() {
 	monitoring.MustRegister(errorMetric)
 }
@@ -55,8 +67,39 @@ func ErrorHandlerForCluster(clusterID cluster.ID) cache.WatchErrorHandler {
 	clusterMetric := errorMetric.With(clusterLabel.Value(clusterID.String()))
 	h := func(_ *cache.Reflector, err error) {
 		clusterMetric.Increment()
+		recordWatchFailure(clusterID, time.Now())
 		log.Errorf("watch error in cluster %s: %v", clusterID, err)
 	}
 	handlers[clusterID] = h
 	return h
 }
+
+func recordWatchFailure(clusterID cluster.ID, now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+	f := failures[clusterID]
+	if f == nil || now.Sub(f.last) > watchRecoveryWindow {
+		f = &watchFailures{since: now}
+		failures[clusterID] = f
+	}
+	f.last = now
+}
+
+// WatchFailingSince returns when the watches of the cluster started failing, if they are still failing, so that the
+// data watched from the cluster may be out of date.
+func WatchFailingSince(clusterID cluster.ID, now time.Time) (time.Time, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f := failures[clusterID]
+	if f == nil || now.Sub(f.last) > watchRecoveryWindow {
+		return time.Time{}, false
+	}
+	return f.since, true
+}
+
+// ForgetWatchFailures drops the watch failures recorded for the cluster, once it is removed.
+func ForgetWatchFailures(clusterID cluster.ID) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(failures, clusterID)
+}
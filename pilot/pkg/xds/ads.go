@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"istio.io/api/label"
 	"istio.io/istio/pilot/pkg/autoregistration"
 	"istio.io/istio/pilot/pkg/features"
 	istiogrpc "istio.io/istio/pilot/pkg/grpc"
@@ -36,6 +37,7 @@ import (
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/network"
 	"istio.io/istio/pkg/util/sets"
 	"istio.io/pkg/env"
 	istiolog "istio.io/pkg/log"
@@ -628,6 +630,12 @@ func setTopologyLabels(proxy *model.Proxy) {
 		}
 	}
 
+	// The network of the proxy may be set by the registry, from the node or the namespace of the workload, rather than by
+	// the injection.
+	if proxy.Metadata.Network == "" && proxy.Labels[label.TopologyNetwork.Name] != "" {
+		proxy.Metadata.Network = network.ID(proxy.Labels[label.TopologyNetwork.Name])
+	}
+
 	locality := util.LocalityToString(proxy.Locality)
 	// add topology labels to proxy labels
 	proxy.Labels = labelutil.AugmentLabels(proxy.Labels, proxy.Metadata.ClusterID, locality, proxy.GetNodeName(), proxy.Metadata.Network)
@@ -736,6 +744,11 @@ func (s *DiscoveryServer) DeltaAggregatedResources(stream discovery.AggregatedDi
 func (s *DiscoveryServer) pushConnection(con *Connection, pushEv *Event) error {
 	pushRequest := pushEv.pushRequest
 
+	if pushRequest.IsTap() {
+		// Only the tap session is pushed, the rest of the config of the proxy is unchanged.
+		return s.pushXds(con, con.Watched(v3.TapType), pushRequest)
+	}
+
 	if pushRequest.Full {
 		// Update Proxy with current information.
 		s.computeProxyState(con.proxy, pushRequest)
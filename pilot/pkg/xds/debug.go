@@ -47,6 +47,7 @@ import (
 	"istio.io/istio/pkg/config/xds"
 	"istio.io/istio/pkg/network"
 	"istio.io/istio/pkg/security"
+	"istio.io/istio/pkg/spiffe"
 	"istio.io/istio/pkg/util/protomarshal"
 	"istio.io/istio/pkg/util/sets"
 	istiolog "istio.io/pkg/log"
@@ -195,6 +196,8 @@ func (s *DiscoveryServer) AddDebugHandlers(mux, internalMux *http.ServeMux, enab
 	s.addDebugHandler(mux, internalMux, "/debug/instancesz", "Debug support for service instances", s.instancesz)
 
 	s.addDebugHandler(mux, internalMux, "/debug/authorizationz", "Internal authorization policies", s.authorizationz)
+	s.addDebugHandler(mux, internalMux, "/debug/dryrunz", "Shadow decisions of dry-run authorization policies reported by connected proxies", s.dryrunz)
+	s.addSystemDebugHandler(mux, internalMux, "/debug/tapz", "Run a time-boxed tap session on a connected proxy and return the captured traffic", s.tapz)
 	s.addDebugHandler(mux, internalMux, "/debug/telemetryz", "Debug Telemetry configuration", s.telemetryz)
 	s.addDebugHandler(mux, internalMux, "/debug/config_dump", "ConfigDump in the form of the Envoy admin config dump API for passed in proxyID", s.ConfigDump)
 	s.addDebugHandler(mux, internalMux, "/debug/push_status", "Last PushContext Details", s.pushStatusHandler)
@@ -204,8 +207,11 @@ func (s *DiscoveryServer) AddDebugHandlers(mux, internalMux *http.ServeMux, enab
 	s.addDebugHandler(mux, internalMux, "/debug/inject", "Active inject template", s.injectTemplateHandler(webhook))
 	s.addDebugHandler(mux, internalMux, "/debug/mesh", "Active mesh config", s.meshHandler)
 	s.addDebugHandler(mux, internalMux, "/debug/clusterz", "List remote clusters where istiod reads endpoints", s.clusterz)
+	s.addDebugHandler(mux, internalMux, "/debug/configdriftz", "List configs diverging across primary clusters", s.configDriftz)
+	s.addDebugHandler(mux, internalMux, "/debug/controllerz", "Dependency graph, recomputations and failed reconciles of the controllers", s.controllerz)
 	s.addDebugHandler(mux, internalMux, "/debug/networkz", "List cross-network gateways", s.networkz)
 	s.addDebugHandler(mux, internalMux, "/debug/mcsz", "List information about Kubernetes MCS services", s.mcsz)
+	s.addDebugHandler(mux, internalMux, "/debug/ztunnelz", "Config dump and certificates of connected ztunnels, keyed by node", s.ztunnelz)
 
 	s.addDebugHandler(mux, internalMux, "/debug/list", "List all supported debug commands in json", s.list)
 }
@@ -222,6 +228,18 @@ func (s *DiscoveryServer) addDebugHandler(mux *http.ServeMux, internalMux *http.
 	mux.HandleFunc(path, s.allowAuthenticatedOrLocalhost(http.HandlerFunc(handler)))
 }
 
+// addSystemDebugHandler adds a debug handler exposing the traffic of the proxies, which is only allowed from localhost
+// or to the identities of the system namespace. Over xDS, the DebugGen only allows these identities as well.
+func (s *DiscoveryServer) addSystemDebugHandler(mux *http.ServeMux, internalMux *http.ServeMux,
+	path string, help string, handler func(http.ResponseWriter, *http.Request),
+) {
+	s.debugHandlers[path] = help
+	if internalMux != nil {
+		internalMux.HandleFunc(path, handler)
+	}
+	mux.HandleFunc(path, s.allowSystemNamespaceOrLocalhost(http.HandlerFunc(handler)))
+}
+
 func (s *DiscoveryServer) allowAuthenticatedOrLocalhost(next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		// Request is from localhost, no need to authenticate
@@ -229,21 +247,7 @@ func (s *DiscoveryServer) allowAuthenticatedOrLocalhost(next http.Handler) http.
 			next.ServeHTTP(w, req)
 			return
 		}
-		// Authenticate request with the same method as XDS
-		authFailMsgs := make([]string, 0)
-		var ids []string
-		authRequest := security.AuthContext{Request: req}
-		for _, authn := range s.Authenticators {
-			u, err := authn.Authenticate(authRequest)
-			// If one authenticator passes, return
-			if u != nil && u.Identities != nil && err == nil {
-				ids = u.Identities
-				break
-			}
-			authFailMsgs = append(authFailMsgs, fmt.Sprintf("Authenticator %s: %v", authn.AuthenticatorType(), err))
-		}
-		if ids == nil {
-			istiolog.Errorf("Failed to authenticate %s %v", req.URL, authFailMsgs)
+		if s.authenticateRequest(req) == nil {
 			// Not including detailed info in the response, XDS doesn't either (returns a generic "authentication failure).
 			w.WriteHeader(http.StatusUnauthorized)
 			return
@@ -254,6 +258,44 @@ func (s *DiscoveryServer) allowAuthenticatedOrLocalhost(next http.Handler) http.
 	}
 }
 
+func (s *DiscoveryServer) allowSystemNamespaceOrLocalhost(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if isRequestFromLocalhost(req) {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ids := s.authenticateRequest(req)
+		if ids == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		for _, id := range ids {
+			if identity, err := spiffe.ParseIdentity(id); err == nil && identity.Namespace == s.systemNamespace {
+				next.ServeHTTP(w, req)
+				return
+			}
+		}
+		istiolog.Warnf("Denied %s to %v, not in namespace %s", req.URL.Path, ids, s.systemNamespace)
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
+
+// authenticateRequest authenticates the request with the same method as XDS, it returns nil if it fails.
+func (s *DiscoveryServer) authenticateRequest(req *http.Request) []string {
+	authFailMsgs := make([]string, 0)
+	authRequest := security.AuthContext{Request: req}
+	for _, authn := range s.Authenticators {
+		u, err := authn.Authenticate(authRequest)
+		// If one authenticator passes, return
+		if u != nil && u.Identities != nil && err == nil {
+			return u.Identities
+		}
+		authFailMsgs = append(authFailMsgs, fmt.Sprintf("Authenticator %s: %v", authn.AuthenticatorType(), err))
+	}
+	istiolog.Errorf("Failed to authenticate %s %v", req.URL, authFailMsgs)
+	return nil
+}
+
 func isRequestFromLocalhost(r *http.Request) bool {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
@@ -1110,12 +1152,46 @@ func sortMCSServices(svcs []model.MCSServiceInfo) []model.MCSServiceInfo {
 	return svcs
 }
 
+func (s *DiscoveryServer) configDriftz(w http.ResponseWriter, req *http.Request) {
+	if s.ConfigDrifts == nil {
+		w.WriteHeader(400)
+		return
+	}
+	writeJSON(w, s.ConfigDrifts.ConfigDrifts(), req)
+}
+
+// RegisterController exposes the dependency graph of the controller on /debug/controllerz, replacing the controller of
+// the same name, e.g. when a controller is recreated on a new leader election.
+func (s *DiscoveryServer) RegisterController(c ControllerDependencyLister) {
+	s.controllersMutex.Lock()
+	defer s.controllersMutex.Unlock()
+	s.controllers[c.Dependencies().Controller] = c
+}
+
+func (s *DiscoveryServer) controllerz(w http.ResponseWriter, req *http.Request) {
+	s.controllersMutex.RLock()
+	res := make([]model.ControllerDependencies, 0, len(s.controllers))
+	for _, c := range s.controllers {
+		res = append(res, c.Dependencies())
+	}
+	s.controllersMutex.RUnlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Controller < res[j].Controller
+	})
+	writeJSON(w, res, req)
+}
+
 func (s *DiscoveryServer) clusterz(w http.ResponseWriter, req *http.Request) {
 	if s.ListRemoteClusters == nil {
 		w.WriteHeader(400)
 		return
 	}
-	writeJSON(w, s.ListRemoteClusters(), req)
+	clusters := s.ListRemoteClusters()
+	endpoints := s.Env.EndpointIndex.EndpointsByCluster()
+	for i := range clusters {
+		clusters[i].Endpoints = endpoints[clusters[i].ID]
+	}
+	writeJSON(w, clusters, req)
 }
 
 // handlePushRequest handles a ?push=true query param and triggers a push.
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	tapsvc "github.com/envoyproxy/go-control-plane/envoy/service/tap/v3"
 	"github.com/google/uuid"
 	"go.uber.org/atomic"
 	"golang.org/x/time/rate"
@@ -38,6 +39,7 @@ import (
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/schema/kind"
+	kubelib "istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/security"
 )
 
@@ -69,6 +71,17 @@ type debounceOptions struct {
 	enableEDSDebounce bool
 }
 
+// ConfigDriftLister lists the copies of the configs diverging across the primary clusters of a multi-primary mesh.
+type ConfigDriftLister interface {
+	ConfigDrifts() []model.ConfigDrift
+}
+
+// ControllerDependencyLister exposes the dependency graph of a controller, with the recomputations each of its inputs
+// triggered.
+type ControllerDependencyLister interface {
+	Dependencies() model.ControllerDependencies
+}
+
 // DiscoveryServer is Pilot's gRPC implementation for Envoy's xds APIs
 type DiscoveryServer struct {
 	// Env is the model environment.
@@ -125,6 +138,10 @@ type DiscoveryServer struct {
 	// Authenticators for XDS requests. Should be same/subset of the CA authenticators.
 	Authenticators []security.Authenticator
 
+	// ZtunnelAdminClient, if set, reaches the admin interface of the ztunnels through port forwards, as it only
+	// listens on localhost.
+	ZtunnelAdminClient kubelib.CLIClient
+
 	// StatusGen is notified of connect/disconnect/nack on all connections
 	StatusGen               *StatusGen
 	WorkloadEntryController *autoregistration.Controller
@@ -147,9 +164,22 @@ type DiscoveryServer struct {
 	// ListRemoteClusters collects debug information about other clusters this istiod reads from.
 	ListRemoteClusters func() []cluster.DebugInfo
 
+	// ConfigDrifts lists the configs of the remote primary clusters diverging from the config cluster.
+	ConfigDrifts ConfigDriftLister
+
 	// ClusterAliases are aliase names for cluster. When a proxy connects with a cluster ID
 	// and if it has a different alias we should use that a cluster ID for proxy.
 	ClusterAliases map[cluster.ID]cluster.ID
+
+	// taps holds the tap sessions started through /debug/tapz.
+	taps *TapGenerator
+
+	// systemNamespace is the namespace of istiod, whose identities are allowed to run the tap sessions.
+	systemNamespace string
+
+	// controllers are the controllers exposing their dependency graph on /debug/controllerz, by name.
+	controllers      map[string]ControllerDependencyLister
+	controllersMutex sync.RWMutex
 }
 
 // NewDiscoveryServer creates DiscoveryServer that sources data from Pilot's internal mesh data structures
@@ -171,9 +201,11 @@ func NewDiscoveryServer(env *model.Environment, instanceID string, clusterID clu
 			debounceMax:       features.DebounceMax,
 			enableEDSDebounce: features.EnableEDSDebounce,
 		},
-		Cache:      model.DisabledCache{},
-		instanceID: instanceID,
-		clusterID:  clusterID,
+		Cache:       model.DisabledCache{},
+		instanceID:  instanceID,
+		clusterID:   clusterID,
+		taps:        NewTapGenerator(),
+		controllers: map[string]ControllerDependencyLister{},
 	}
 
 	out.ClusterAliases = make(map[cluster.ID]cluster.ID)
@@ -221,6 +253,8 @@ func (s *DiscoveryServer) closeJwksResolver() {
 func (s *DiscoveryServer) Register(rpcs *grpc.Server) {
 	// Register v3 server
 	discovery.RegisterAggregatedDiscoveryServiceServer(rpcs, s)
+	// Register the sink of the traces of the tap sessions
+	tapsvc.RegisterTapSinkServiceServer(rpcs, s)
 }
 
 var processStartTime = time.Now()
@@ -574,7 +608,9 @@ func (s *DiscoveryServer) InitGenerators(env *model.Environment, systemNameSpace
 
 	s.Generators["event"] = s.StatusGen
 	s.Generators[v3.DebugType] = NewDebugGen(s, systemNameSpace, internalDebugMux)
+	s.systemNamespace = systemNameSpace
 	s.Generators[v3.BootstrapType] = &BootstrapGenerator{Server: s}
+	s.Generators[v3.TapType] = s.taps
 }
 
 // Shutdown shuts down DiscoveryServer components.
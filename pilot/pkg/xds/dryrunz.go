@@ -0,0 +1,180 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"golang.org/x/sync/errgroup"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+)
+
+const (
+	dryRunRequestsMetric = "istio_requests_total"
+	// dryRunUnknown is reported by the stats filter when there is no dry-run policy of the action for the workload.
+	dryRunUnknown = "unknown"
+	// dryRunMaxConcurrentFetches bounds the number of proxies queried at the same time.
+	dryRunMaxConcurrentFetches = 16
+)
+
+// DryRunPolicyResult is the number of requests for which a dry-run policy would have made a decision.
+type DryRunPolicyResult struct {
+	// Action is the action of the dry-run policies, ALLOW or DENY.
+	Action string `json:"action"`
+	// Policy is the effective policy ID of the matched rule, empty if no dry-run policy of the action matched.
+	Policy string `json:"policy,omitempty"`
+	// Result is the shadow decision of the dry-run policies, allowed or denied.
+	Result   string  `json:"result"`
+	Requests float64 `json:"requests"`
+}
+
+// DryRunDump holds the dry-run results aggregated across proxies.
+type DryRunDump struct {
+	Results []DryRunPolicyResult `json:"results"`
+	// Errors holds the proxies whose stats could not be fetched, keyed by proxy ID.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// dryrunz aggregates the shadow decisions of the dry-run AuthorizationPolicies reported by the sidecars and
+// gateways connected to this istiod, from the metric dimensions added by PILOT_ENABLE_DRY_RUN_AUTHZ_METRICS.
+// The proxies must be filtered with the "proxyID" or "namespace" query parameters, so a single request does not
+// query every proxy of the mesh.
+func (s *DiscoveryServer) dryrunz(w http.ResponseWriter, req *http.Request) {
+	if !features.EnableDryRunAuthzMetrics {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Dry-run AuthorizationPolicy metrics are not enabled, set PILOT_ENABLE_DRY_RUN_AUTHZ_METRICS.\n"))
+		return
+	}
+	proxyID := req.URL.Query().Get("proxyID")
+	namespace := req.URL.Query().Get("namespace")
+	if proxyID == "" && namespace == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("A proxyID or namespace query parameter is required.\n"))
+		return
+	}
+
+	counts := map[DryRunPolicyResult]float64{}
+	dump := DryRunDump{Results: []DryRunPolicyResult{}}
+	mu := sync.Mutex{}
+	g := errgroup.Group{}
+	g.SetLimit(dryRunMaxConcurrentFetches)
+	for _, con := range s.Clients() {
+		proxy := con.proxy
+		if proxy == nil || (proxy.Type != model.SidecarProxy && proxy.Type != model.Router) {
+			continue
+		}
+		if proxyID != "" && proxyID != proxy.ID {
+			continue
+		}
+		if namespace != "" && namespace != proxy.ConfigNamespace {
+			continue
+		}
+		g.Go(func() error {
+			results, err := fetchDryRunResults(req.Context(), proxy)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if dump.Errors == nil {
+					dump.Errors = map[string]string{}
+				}
+				dump.Errors[proxy.ID] = err.Error()
+				return nil
+			}
+			for _, r := range results {
+				requests := r.Requests
+				r.Requests = 0
+				counts[r] += requests
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for r, requests := range counts {
+		r.Requests = requests
+		dump.Results = append(dump.Results, r)
+	}
+	sort.Slice(dump.Results, func(i, j int) bool {
+		a, b := dump.Results[i], dump.Results[j]
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Result < b.Result
+	})
+	writeJSON(w, dump, req)
+}
+
+// fetchDryRunResults queries the stats of the proxy and returns the dry-run results it reported.
+func fetchDryRunResults(ctx context.Context, proxy *model.Proxy) ([]DryRunPolicyResult, error) {
+	if len(proxy.IPAddresses) == 0 {
+		return nil, fmt.Errorf("proxy has no known address")
+	}
+	address := net.JoinHostPort(proxy.IPAddresses[0], strconv.Itoa(features.ProxyStatsPort))
+	body, err := proxyAdminRequest(ctx, address, "/stats/prometheus")
+	if err != nil {
+		return nil, err
+	}
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stats: %v", err)
+	}
+	family, f := families[dryRunRequestsMetric]
+	if !f {
+		return nil, nil
+	}
+	var results []DryRunPolicyResult
+	for _, m := range family.GetMetric() {
+		labels := map[string]string{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		for action, prefix := range map[string]string{"ALLOW": "dry_run_allow_", "DENY": "dry_run_deny_"} {
+			if r, ok := dryRunResult(action, labels[prefix+"policy"], labels[prefix+"result"], m); ok {
+				results = append(results, r)
+			}
+		}
+	}
+	return results, nil
+}
+
+func dryRunResult(action, policy, result string, m *dto.Metric) (DryRunPolicyResult, bool) {
+	if result == "" || result == dryRunUnknown {
+		return DryRunPolicyResult{}, false
+	}
+	if policy == dryRunUnknown {
+		policy = ""
+	}
+	return DryRunPolicyResult{
+		Action:   action,
+		Policy:   policy,
+		Result:   result,
+		Requests: m.GetCounter().GetValue(),
+	}, true
+}
@@ -0,0 +1,94 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"istio.io/istio/pilot/pkg/features"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+const dryRunStats = `# TYPE istio_requests_total counter
+istio_requests_total{reporter="destination",response_code="200",dry_run_allow_policy="ns[foo]-policy[allow-sleep]-rule[0]",dry_run_allow_result="allowed",dry_run_deny_policy="unknown",dry_run_deny_result="unknown"} 3
+istio_requests_total{reporter="destination",response_code="503",dry_run_allow_policy="ns[foo]-policy[allow-sleep]-rule[0]",dry_run_allow_result="allowed",dry_run_deny_policy="unknown",dry_run_deny_result="unknown"} 1
+istio_requests_total{reporter="destination",response_code="200",dry_run_allow_policy="unknown",dry_run_allow_result="denied",dry_run_deny_policy="ns[foo]-policy[deny-debug]-rule[1]",dry_run_deny_result="denied"} 2
+istio_requests_total{reporter="source",response_code="200"} 7
+`
+
+func TestDryrunz(t *testing.T) {
+	stats := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats/prometheus" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(dryRunStats))
+	}))
+	defer stats.Close()
+	_, port, _ := net.SplitHostPort(stats.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	test.SetForTest(t, &features.ProxyStatsPort, portNum)
+
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	ads := s.ConnectADS().
+		WithType(v3.ClusterType).
+		WithTimeout(time.Second * 10).
+		WithID("sidecar~127.0.0.1~app.foo~foo.svc.cluster.local")
+	ads.RequestResponseAck(t, &discovery.DiscoveryRequest{})
+
+	fetch := func(query string) (int, DryRunDump) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/debug/dryrunz"+query, nil)
+		rr := httptest.NewRecorder()
+		s.Discovery.dryrunz(rr, req)
+		res := DryRunDump{}
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rr.Code, res
+	}
+
+	code, _ := fetch("")
+	assert.Equal(t, code, http.StatusBadRequest)
+
+	test.SetForTest(t, &features.EnableDryRunAuthzMetrics, true)
+	// The proxies must be filtered
+	code, _ = fetch("")
+	assert.Equal(t, code, http.StatusBadRequest)
+
+	code, res := fetch("?namespace=foo")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, len(res.Errors), 0)
+	assert.Equal(t, res.Results, []DryRunPolicyResult{
+		{Action: "ALLOW", Result: "denied", Requests: 2},
+		{Action: "ALLOW", Policy: "ns[foo]-policy[allow-sleep]-rule[0]", Result: "allowed", Requests: 4},
+		{Action: "DENY", Policy: "ns[foo]-policy[deny-debug]-rule[1]", Result: "denied", Requests: 2},
+	})
+
+	_, res = fetch("?namespace=bar")
+	assert.Equal(t, len(res.Results), 0)
+}
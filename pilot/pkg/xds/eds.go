@@ -282,7 +282,12 @@ func (s *DiscoveryServer) generateEndpoints(b EndpointBuilder) *endpoint.Cluster
 	// will never detect the hosts are unhealthy and redirect traffic.
 	enableFailover, lb := getOutlierDetectionAndLoadBalancerSettings(b.DestinationRule(), b.port, b.subsetName)
 	lbSetting := loadbalancer.GetLocalityLbSetting(b.push.Mesh.GetLocalityLbSetting(), lb.GetLocalityLbSetting())
-	if lbSetting != nil {
+	clusterWeights, clusterFailover := b.clusterLoadBalancing()
+	if !enableFailover {
+		// Like the locality failover, the cluster failover needs outlier detection.
+		clusterFailover = nil
+	}
+	if lbSetting != nil || len(clusterWeights) > 0 || len(clusterFailover) > 0 {
 		// Make a shallow copy of the cla as we are mutating the endpoints with priorities/weights relative to the calling proxy
 		l = util.CloneClusterLoadAssignment(l)
 		wrappedLocalityLbEndpoints := make([]*loadbalancer.WrappedLocalityLbEndpoints, len(localityLbEndpoints))
@@ -292,7 +297,14 @@ func (s *DiscoveryServer) generateEndpoints(b EndpointBuilder) *endpoint.Cluster
 				LocalityLbEndpoints: l.Endpoints[i],
 			}
 		}
-		loadbalancer.ApplyLocalityLBSetting(l, wrappedLocalityLbEndpoints, b.locality, b.proxy.Labels, lbSetting, enableFailover)
+		// The cluster weights and failover take precedence over the distribute and failover of the locality
+		// load balancing, respectively.
+		loadbalancer.ApplyClusterWeights(l, wrappedLocalityLbEndpoints, clusterWeights)
+		if len(clusterFailover) > 0 {
+			loadbalancer.ApplyClusterFailover(l, wrappedLocalityLbEndpoints, clusterFailover)
+		} else if lbSetting != nil && (len(clusterWeights) == 0 || lbSetting.GetDistribute() == nil) {
+			loadbalancer.ApplyLocalityLBSetting(l, wrappedLocalityLbEndpoints, b.locality, b.proxy.Labels, lbSetting, enableFailover)
+		}
 	}
 	return l
 }
@@ -35,6 +35,7 @@ import (
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/loadbalancing"
 	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/network"
 	"istio.io/istio/pkg/spiffe"
@@ -58,6 +59,8 @@ type EndpointBuilder struct {
 	clusterLocal           bool
 	nodeType               model.NodeType
 	failoverPriorityLabels []byte
+	// interopMode is how sidecars send traffic to the ambient workloads of the namespace of the service.
+	interopMode string
 
 	// These fields are provided for convenience only
 	subsetName string
@@ -79,8 +82,10 @@ func NewEndpointBuilder(clusterName string, proxy *model.Proxy, push *model.Push
 	svc := push.ServiceForHostname(proxy, hostname)
 
 	var dr *model.ConsolidatedDestRule
+	var interopMode string
 	if svc != nil {
 		dr = proxy.SidecarScope.DestinationRule(model.TrafficDirectionOutbound, proxy, svc.Hostname)
+		interopMode = push.AmbientInteropMode(svc.Attributes.Namespace)
 	}
 	b := EndpointBuilder{
 		clusterName:     clusterName,
@@ -92,6 +97,7 @@ func NewEndpointBuilder(clusterName string, proxy *model.Proxy, push *model.Push
 		clusterLocal:    push.IsClusterLocal(svc),
 		destinationRule: dr,
 		nodeType:        proxy.Type,
+		interopMode:     interopMode,
 
 		push:       push,
 		proxy:      proxy,
@@ -118,6 +124,34 @@ func (b *EndpointBuilder) DestinationRule() *networkingapi.DestinationRule {
 	return nil
 }
 
+// clusterLoadBalancing returns the weights and failover order of the clusters set by the annotations of the
+// DestinationRule. Invalid annotations, rejected by validation, are ignored.
+func (b *EndpointBuilder) clusterLoadBalancing() (loadbalancing.ClusterWeights, []cluster.ID) {
+	dr := b.destinationRule.GetRule()
+	if dr == nil {
+		return nil, nil
+	}
+	var weights loadbalancing.ClusterWeights
+	var failover []cluster.ID
+	if v := dr.Annotations[constants.ClusterWeightsAnnotation]; v != "" {
+		parsed, err := loadbalancing.ParseClusterWeights(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation of DestinationRule %s/%s: %v", constants.ClusterWeightsAnnotation, dr.Namespace, dr.Name, err)
+		} else {
+			weights = parsed
+		}
+	}
+	if v := dr.Annotations[constants.ClusterFailoverAnnotation]; v != "" {
+		parsed, err := loadbalancing.ParseClusterFailover(v)
+		if err != nil {
+			log.Warnf("ignoring invalid %s annotation of DestinationRule %s/%s: %v", constants.ClusterFailoverAnnotation, dr.Namespace, dr.Name, err)
+		} else {
+			failover = parsed
+		}
+	}
+	return weights, failover
+}
+
 func (b *EndpointBuilder) Type() string {
 	return model.EDSType
 }
@@ -141,6 +175,17 @@ func (b *EndpointBuilder) Key() any {
 		h.Write([]byte(strconv.FormatBool(b.proxy.IsProxylessGrpc())))
 		h.Write(Separator)
 	}
+	if features.EnableHBONEEastWest && b.proxy != nil {
+		h.Write([]byte(strconv.FormatBool(b.proxy.EnableHBONE())))
+		h.Write(Separator)
+	}
+	if b.interopMode != "" && b.proxy != nil {
+		// The interop mode depends on the HBONE support of the client proxy
+		h.Write([]byte(b.interopMode))
+		h.Write([]byte(strconv.FormatBool(b.proxy.IsProxylessGrpc())))
+		h.Write([]byte(strconv.FormatBool(b.proxy.EnableHBONE())))
+		h.Write(Separator)
+	}
 	h.Write([]byte(util.LocalityToString(b.locality)))
 	h.Write(Separator)
 	if len(b.failoverPriorityLabels) > 0 {
@@ -301,8 +346,9 @@ func (b *EndpointBuilder) buildLocalityLbEndpointsFromShards(
 
 			// Currently the HBONE implementation leads to different endpoint generation depending on if the
 			// client proxy supports HBONE or not. This breaks the cache.
-			// For now, just disable caching if the global HBONE flag is enabled.
-			if ep.EnvoyEndpoint == nil || features.EnableHBONE {
+			// For now, just disable caching if the global HBONE flag is enabled, or an ambient interop mode
+			// applies to the service, as it depends on the client proxy as well.
+			if ep.EnvoyEndpoint == nil || features.EnableHBONE || b.interopMode != "" {
 				eep := buildEnvoyLbEndpoint(b, ep)
 				if eep == nil {
 					continue
@@ -414,7 +460,8 @@ func buildEnvoyLbEndpoint(b *EndpointBuilder, e *model.IstioEndpoint) *endpoint.
 	}
 
 	// Otherwise has ambient enabled. Note: this is a synthetic label, not existing in the real Pod.
-	if b.push.SupportsTunnel(e.Address) {
+	ambientDestination := b.push.SupportsTunnel(e.Address)
+	if ambientDestination {
 		supportsTunnel = true
 	}
 	// Otherwise supports tunnel
@@ -432,6 +479,22 @@ func buildEnvoyLbEndpoint(b *EndpointBuilder, e *model.IstioEndpoint) *endpoint.
 		supportsTunnel = false
 	}
 
+	// Sidecars sending to ambient workloads follow the interop mode of the destination namespace.
+	if ambientDestination && b.dir == model.TrafficDirectionOutbound && b.proxy.Type == model.SidecarProxy {
+		switch b.interopMode {
+		case constants.AmbientInteropModeMTLS:
+			supportsTunnel = false
+		case constants.AmbientInteropModeHBONE:
+			if !supportsTunnel {
+				return nil
+			}
+		case constants.AmbientInteropModeWaypoint:
+			if !supportsTunnel || len(findWaypoints(b.push, e)) == 0 {
+				return nil
+			}
+		}
+	}
+
 	// Setup tunnel information, if needed
 	if b.dir == model.TrafficDirectionInboundVIP {
 		// This is only used in waypoint proxy
@@ -484,6 +547,10 @@ func waypointInScope(waypoint *model.Proxy, e *model.IstioEndpoint) bool {
 	if scope.Namespace != e.Namespace {
 		return false
 	}
+	// Waypoints only serve endpoints in their own cluster; endpoints in other clusters are served by their local waypoint.
+	if scope.Cluster != "" && e.Locality.ClusterID != "" && !scope.Cluster.Equals(e.Locality.ClusterID) {
+		return false
+	}
 	ident, _ := spiffe.ParseIdentity(e.ServiceAccount)
 	if scope.ServiceAccount != "" && (scope.ServiceAccount != ident.ServiceAccount) {
 		return false
@@ -496,6 +563,7 @@ func findWaypoints(push *model.PushContext, e *model.IstioEndpoint) []netip.Addr
 	ips := push.WaypointsFor(model.WaypointScope{
 		Namespace:      e.Namespace,
 		ServiceAccount: ident.ServiceAccount,
+		Cluster:        e.Locality.ClusterID,
 	}).UnsortedList()
 	return ips
 }
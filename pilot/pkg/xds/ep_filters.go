@@ -16,13 +16,18 @@ package xds
 
 import (
 	"math"
+	"net"
+	"strconv"
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
 
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
+	networking "istio.io/istio/pilot/pkg/networking/core/v1alpha3"
 	"istio.io/istio/pilot/pkg/networking/util"
 	labelutil "istio.io/istio/pilot/pkg/serviceregistry/util/label"
 	"istio.io/istio/pkg/cluster"
@@ -98,6 +103,13 @@ func (b *EndpointBuilder) EndpointsByNetworkFilter(endpoints []*LocalityEndpoint
 				continue
 			}
 
+			// Workloads accepting HBONE are reached through an HBONE tunnel to a gateway of their network, which keeps
+			// each of them visible to load balancing and telemetry.
+			if hboneGateways := b.selectHBONEGateways(gateways, istioEndpoint); len(hboneGateways) > 0 {
+				lbEndpoints.append(istioEndpoint, buildHBONEGatewayEndpoint(lbEp, istioEndpoint, hboneGateways[i%len(hboneGateways)]))
+				continue
+			}
+
 			// Cross-network traffic relies on mTLS to be enabled for SNI routing
 			// TODO BTS may allow us to work around this
 			if b.mtlsChecker.isMtlsDisabled(lbEp) {
@@ -164,6 +176,50 @@ func (b *EndpointBuilder) EndpointsByNetworkFilter(endpoints []*LocalityEndpoint
 	return filtered
 }
 
+// selectHBONEGateways returns the gateways accepting HBONE through which the endpoint of another network is
+// tunneled, or nil if it must be reached through the SNI based mTLS passthrough. The gateway relays the HBONE tunnel
+// of the proxy to the endpoint, so the endpoint must support HBONE as well.
+func (b *EndpointBuilder) selectHBONEGateways(gateways []model.NetworkGateway, e *model.IstioEndpoint) []model.NetworkGateway {
+	if !features.EnableHBONEEastWest || !b.proxy.EnableHBONE() || b.proxy.IsProxylessGrpc() {
+		return nil
+	}
+	if !e.SupportsTunnel(model.TunnelHTTP) && !b.push.SupportsTunnel(e.Address) {
+		return nil
+	}
+	var out []model.NetworkGateway
+	for _, gw := range gateways {
+		if gw.HBONEPort > 0 {
+			out = append(out, gw)
+		}
+	}
+	return out
+}
+
+// buildHBONEGatewayEndpoint builds the endpoint tunneling the traffic to the endpoint of another network through the
+// HBONE listener of the gateway. The tunnel is established with the HBONE port of the endpoint itself, by the
+// internal listener dedicated to the gateway.
+func buildHBONEGatewayEndpoint(lbEp *endpoint.LbEndpoint, e *model.IstioEndpoint, gw model.NetworkGateway) *endpoint.LbEndpoint {
+	ep := proto.Clone(lbEp).(*endpoint.LbEndpoint)
+	if ep.Metadata == nil {
+		ep.Metadata = &core.Metadata{}
+	}
+	if ep.Metadata.FilterMetadata == nil {
+		ep.Metadata.FilterMetadata = map[string]*structpb.Struct{}
+	}
+	target := net.JoinHostPort(e.Address, strconv.Itoa(int(e.EndpointPort)))
+	ep.HostIdentifier = &endpoint.LbEndpoint_Endpoint{Endpoint: &endpoint.Endpoint{
+		Address: util.BuildInternalAddressWithIdentifier(networking.EastWestOriginateName(gw), target),
+	}}
+	ep.Metadata.FilterMetadata[model.TunnelLabelShortName] = util.BuildTunnelMetadataStruct(e.Address, e.Address,
+		int(e.EndpointPort), model.HBoneInboundListenPort)
+	ep.Metadata.FilterMetadata[util.EnvoyTransportSocketMetadataKey] = &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			model.TunnelLabelShortName: {Kind: &structpb.Value_StringValue{StringValue: model.TunnelHTTP}},
+		},
+	}
+	return ep
+}
+
 // selectNetworkGateways chooses the gateways that best match the network and cluster. If there is
 // no match for the network+cluster, then all gateways matching the network are returned. Preferring
 // gateways that match against cluster has the following advantages:
@@ -24,6 +24,7 @@ import (
 	networking "istio.io/api/networking/v1alpha3"
 	security "istio.io/api/security/v1beta1"
 	"istio.io/api/type/v1beta1"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config"
@@ -31,6 +32,7 @@ import (
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/network"
 	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
 )
 
 type LbEpInfo struct {
@@ -856,3 +858,51 @@ func getLbEndpointAddrs(ep *endpoint.LocalityLbEndpoints) []string {
 	}
 	return addrs
 }
+
+func TestEndpointsByNetworkFilter_HBONE(t *testing.T) {
+	test.SetForTest(t, &features.EnableHBONE, true)
+	test.SetForTest(t, &features.EnableHBONEEastWest, true)
+	ds := NewFakeDiscoveryServer(t, FakeOptions{
+		Services: []*model.Service{{
+			Hostname:   "example.ns.svc.cluster.local",
+			Attributes: model.ServiceAttributes{Name: "example", Namespace: "ns"},
+		}},
+		Gateways: []model.NetworkGateway{
+			{Network: "network1", Cluster: "cluster1a", Addr: "1.1.1.1", Port: 15443, HBONEPort: 15008},
+			// only the gateway of cluster2a accepts HBONE
+			{Network: "network2", Cluster: "cluster2a", Addr: "2.2.2.2", Port: 15443, HBONEPort: 15008},
+			{Network: "network2", Cluster: "cluster2b", Addr: "2.2.2.20", Port: 15443},
+		},
+	})
+	shards := testShards()
+	for _, eps := range shards.Shards {
+		for _, ep := range eps {
+			ep.Labels[model.TunnelLabel] = model.TunnelHTTP
+		}
+	}
+
+	proxy := ds.SetupProxy(&model.Proxy{Metadata: &model.NodeMetadata{Network: "network1", ClusterID: "cluster1a", EnableHBONE: true}})
+	b := NewEndpointBuilder("outbound|80||example.ns.svc.cluster.local", proxy, ds.PushContext())
+	filtered := b.EndpointsByNetworkFilter(b.buildLocalityLbEndpointsFromShards(shards, &model.Port{Name: "http", Port: 80, Protocol: protocol.HTTP}))
+
+	tunnels := map[string]string{}
+	var direct []string
+	for _, ep := range filtered[0].llbEndpoints.LbEndpoints {
+		if internal := ep.GetEndpoint().GetAddress().GetEnvoyInternalAddress(); internal != nil {
+			tunnels[internal.EndpointId] = internal.GetServerListenerName() + " " +
+				ep.Metadata.FilterMetadata[model.TunnelLabelShortName].Fields["address"].GetStringValue()
+			continue
+		}
+		direct = append(direct, ep.GetEndpoint().GetAddress().GetSocketAddress().GetAddress())
+	}
+	sort.Strings(direct)
+	// All the tunnels are established with the endpoints, the one of cluster2a through its gateway, and the endpoints
+	// of cluster2b go through the SNI gateway.
+	assert.Equal(t, tunnels, map[string]string{
+		"10.0.0.1:8080": "connect_originate 10.0.0.1:15008",
+		"10.0.0.2:8080": "connect_originate 10.0.0.2:15008",
+		"20.0.0.1:8080": "east_west_originate|2.2.2.2:15008 20.0.0.1:15008",
+		"40.0.0.1:8080": "connect_originate 40.0.0.1:15008",
+	})
+	assert.Equal(t, direct, []string{"2.2.2.20"})
+}
@@ -51,6 +51,10 @@ func rdsNeedsPush(req *model.PushRequest) bool {
 		return true
 	}
 	for config := range req.ConfigsUpdated {
+		if config.Kind == kind.Telemetry && req.Push != nil && req.Push.Telemetry.TargetsRoutes(config) {
+			// Only the Telemetries targeting routes impact RDS.
+			return true
+		}
 		if _, f := skippedRdsConfigs[config.Kind]; !f {
 			return true
 		}
@@ -0,0 +1,349 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	admin "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/config/common/matcher/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tap "github.com/envoyproxy/go-control-plane/envoy/config/tap/v3"
+	tapdata "github.com/envoyproxy/go-control-plane/envoy/data/tap/v3"
+	tapsvc "github.com/envoyproxy/go-control-plane/envoy/service/tap/v3"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	authzmatcher "istio.io/istio/pilot/pkg/security/authz/matcher"
+	"istio.io/istio/pilot/pkg/util/protoconv"
+	"istio.io/istio/pilot/pkg/xds/filters"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/envoy"
+	"istio.io/istio/pkg/spiffe"
+	"istio.io/istio/pkg/util/protomarshal"
+)
+
+const (
+	defaultTapDuration  = 30 * time.Second
+	maxTapDuration      = 5 * time.Minute
+	defaultTapMaxTraces = 100
+	maxTapMaxTraces     = 1000
+	// tapReportTimeout bounds how long we wait for the agent to report the traces once the session expired.
+	tapReportTimeout = 10 * time.Second
+)
+
+// TapDump holds the traces captured by a tap session.
+type TapDump struct {
+	// Traces are the envoy.data.tap.v3.TraceWrapper captured by the session.
+	Traces []json.RawMessage `json:"traces"`
+}
+
+type tapSession struct {
+	envoy.TapSession
+	pushed bool
+	// identity is the verified identity of the proxy, the traces are only accepted from it when set.
+	identity *spiffe.Identity
+	// traces are the traces reported by the agent, set once done is closed.
+	traces []*tapdata.TraceWrapper
+	done   chan struct{}
+}
+
+// TapGenerator pushes the pending tap session of a proxy to its agent, and collects the traces the agent reports
+// on the tap sink stream.
+type TapGenerator struct {
+	mu sync.Mutex
+	// sessions holds the active session of each proxy, keyed by proxy ID.
+	sessions map[string]*tapSession
+}
+
+var _ model.XdsResourceGenerator = &TapGenerator{}
+
+func NewTapGenerator() *TapGenerator {
+	return &TapGenerator{sessions: map[string]*tapSession{}}
+}
+
+// Generate returns the session of the proxy, if it was not pushed yet.
+func (g *TapGenerator) Generate(proxy *model.Proxy, w *model.WatchedResource, req *model.PushRequest) (model.Resources, model.XdsLogDetails, error) {
+	g.mu.Lock()
+	session := g.sessions[proxy.ID]
+	if session == nil || session.pushed {
+		g.mu.Unlock()
+		if req.IsRequest() {
+			// Respond to the subscription of the agent, which ignores empty responses.
+			return model.Resources{}, model.DefaultXdsLogDetails, nil
+		}
+		return nil, model.DefaultXdsLogDetails, nil
+	}
+	session.pushed = true
+	g.mu.Unlock()
+
+	b, err := json.Marshal(session.TapSession)
+	if err != nil {
+		return nil, model.DefaultXdsLogDetails, err
+	}
+	return model.Resources{{
+		Name:     session.ID,
+		Resource: protoconv.MessageToAny(wrapperspb.String(string(b))),
+	}}, model.DefaultXdsLogDetails, nil
+}
+
+// start registers the session of the proxy, it returns nil if the proxy already has an active session.
+func (g *TapGenerator) start(proxy *model.Proxy, session envoy.TapSession) *tapSession {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, f := g.sessions[proxy.ID]; f {
+		return nil
+	}
+	s := &tapSession{TapSession: session, identity: proxy.VerifiedIdentity, done: make(chan struct{})}
+	g.sessions[proxy.ID] = s
+	return s
+}
+
+func (g *TapGenerator) stop(proxyID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sessions, proxyID)
+}
+
+// get returns the active session of the proxy with the ID, nil if there is none.
+func (g *TapGenerator) get(proxyID, sessionID string) *tapSession {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	session := g.sessions[proxyID]
+	if session == nil || session.ID != sessionID {
+		return nil
+	}
+	return session
+}
+
+// complete hands the traces reported by the agent to the session, if it is still active and was not reported yet.
+func (g *TapGenerator) complete(proxyID string, session *tapSession, traces []*tapdata.TraceWrapper) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.sessions[proxyID] != session {
+		return
+	}
+	select {
+	case <-session.done:
+	default:
+		session.traces = traces
+		close(session.done)
+	}
+}
+
+// StreamTaps receives the traces of a tap session from the agent of the proxy it ran on. The first message of the
+// stream identifies the proxy by its service node, and the session by its tap ID; the agent closes the stream once the
+// session ended.
+func (s *DiscoveryServer) StreamTaps(stream tapsvc.TapSinkService_StreamTapsServer) error {
+	ids, err := s.authenticate(stream.Context())
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	// The node ID is the service node of the proxy, the ID of the proxy is its third part.
+	proxyID := req.GetIdentifier().GetNode().GetId()
+	if parts := strings.Split(proxyID, "~"); len(parts) == 4 {
+		proxyID = parts[2]
+	}
+	sessionID := req.GetIdentifier().GetTapId()
+	session := s.taps.get(proxyID, sessionID)
+	if session == nil {
+		return status.Errorf(codes.NotFound, "unknown tap session %s of %s", sessionID, proxyID)
+	}
+	if session.identity != nil && !slices.Contains(ids, session.identity.String()) {
+		return status.Errorf(codes.PermissionDenied, "tap session %s of %s reported by another identity", sessionID, proxyID)
+	}
+
+	var traces []*tapdata.TraceWrapper
+	for {
+		if req.GetTrace() != nil && len(traces) < session.MaxTraces {
+			traces = append(traces, req.GetTrace())
+		}
+		req, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.taps.complete(proxyID, session, traces)
+	return stream.SendAndClose(&tapsvc.StreamTapsResponse{})
+}
+
+// newTapSession builds the session from the "duration", "maxTraces", "method", "path", "header" and "full" query
+// parameters. The method, path and header values match as in AuthorizationPolicy: "*" matches any value,
+// and a leading or trailing "*" a suffix or prefix. Headers are formatted as "name:value". Unless full is true, only
+// the metadata of the requests is captured: the bodies are not buffered, and the agent redacts the values of the
+// headers.
+func newTapSession(query url.Values) (envoy.TapSession, error) {
+	session := envoy.TapSession{
+		ID:        uuid.New().String(),
+		Duration:  defaultTapDuration,
+		MaxTraces: defaultTapMaxTraces,
+	}
+	if d := query.Get("duration"); d != "" {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return session, fmt.Errorf("invalid duration %q: %v", d, err)
+		}
+		if duration <= 0 || duration > maxTapDuration {
+			return session, fmt.Errorf("duration %v must be positive and at most %v", duration, maxTapDuration)
+		}
+		session.Duration = duration
+	}
+	if m := query.Get("maxTraces"); m != "" {
+		maxTraces, err := strconv.Atoi(m)
+		if err != nil || maxTraces <= 0 || maxTraces > maxTapMaxTraces {
+			return session, fmt.Errorf("maxTraces %q must be a number between 1 and %d", m, maxTapMaxTraces)
+		}
+		session.MaxTraces = maxTraces
+	}
+	if f := query.Get("full"); f != "" {
+		full, err := strconv.ParseBool(f)
+		if err != nil {
+			return session, fmt.Errorf("invalid full %q: %v", f, err)
+		}
+		session.Full = full
+	}
+
+	var headers []*route.HeaderMatcher
+	if method := query.Get("method"); method != "" {
+		headers = append(headers, authzmatcher.HeaderMatcher(":method", method))
+	}
+	if path := query.Get("path"); path != "" {
+		headers = append(headers, authzmatcher.HeaderMatcher(":path", path))
+	}
+	for _, h := range query["header"] {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || name == "" || value == "" {
+			return session, fmt.Errorf("invalid header %q, expected name:value", h)
+		}
+		headers = append(headers, authzmatcher.HeaderMatcher(strings.ToLower(name), value))
+	}
+	match := &matcher.MatchPredicate{Rule: &matcher.MatchPredicate_AnyMatch{AnyMatch: true}}
+	if len(headers) > 0 {
+		match = &matcher.MatchPredicate{Rule: &matcher.MatchPredicate_HttpRequestHeadersMatch{
+			HttpRequestHeadersMatch: &matcher.HttpHeadersMatch{Headers: headers},
+		}}
+	}
+
+	output := &tap.OutputConfig{
+		Sinks: []*tap.OutputSink{{
+			Format:         tap.OutputSink_JSON_BODY_AS_STRING,
+			OutputSinkType: &tap.OutputSink_StreamingAdmin{StreamingAdmin: &tap.StreamingAdminSink{}},
+		}},
+	}
+	if !session.Full {
+		output.MaxBufferedRxBytes = wrapperspb.UInt32(0)
+		output.MaxBufferedTxBytes = wrapperspb.UInt32(0)
+	}
+	request, err := protomarshal.MarshalProtoNames(&admin.TapRequest{
+		ConfigId:  filters.TapConfigID,
+		TapConfig: &tap.TapConfig{Match: match, OutputConfig: output},
+	})
+	if err != nil {
+		return session, err
+	}
+	session.Request = request
+	return session, nil
+}
+
+// tapz runs a time-boxed tap session on the proxy set by the "proxyID" query parameter, and returns the traces
+// of the requests it captured. See newTapSession for the parameters of the session.
+func (s *DiscoveryServer) tapz(w http.ResponseWriter, req *http.Request) {
+	if !features.EnableTapSessions {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Tap sessions are not enabled, set PILOT_ENABLE_TAP_SESSIONS.\n"))
+		return
+	}
+	proxyID := req.URL.Query().Get("proxyID")
+	var con *Connection
+	for _, c := range s.Clients() {
+		if proxyID != "" && c.proxy != nil && c.proxy.ID == proxyID {
+			con = c
+			break
+		}
+	}
+	if con == nil {
+		s.errorHandler(w, proxyID, con)
+		return
+	}
+	if con.proxy.Type != model.SidecarProxy && con.proxy.Type != model.Router {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Tap sessions are only supported for sidecars and gateways.\n"))
+		return
+	}
+	if con.Watched(v3.TapType) == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("The proxy agent does not support tap sessions.\n"))
+		return
+	}
+	session, err := newTapSession(req.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(err.Error() + "\n"))
+		return
+	}
+	active := s.taps.start(con.proxy, session)
+	if active == nil {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("A tap session is already running on the proxy.\n"))
+		return
+	}
+	defer s.taps.stop(proxyID)
+
+	s.pushQueue.Enqueue(con, &model.PushRequest{
+		Push:   s.globalPushContext(),
+		Start:  time.Now(),
+		Reason: []model.TriggerReason{model.TapTrigger},
+	})
+
+	timer := time.NewTimer(session.Duration + tapReportTimeout)
+	defer timer.Stop()
+	select {
+	case <-active.done:
+		dump := TapDump{Traces: make([]json.RawMessage, 0, len(active.traces))}
+		for _, trace := range active.traces {
+			b, err := protomarshal.MarshalProtoNames(trace)
+			if err != nil {
+				log.Warnf("dropping invalid trace of tap session %s: %v", session.ID, err)
+				continue
+			}
+			dump.Traces = append(dump.Traces, b)
+		}
+		writeJSON(w, dump, req)
+	case <-timer.C:
+		w.WriteHeader(http.StatusGatewayTimeout)
+		_, _ = w.Write([]byte("The proxy did not report the traces of the tap session.\n"))
+	case <-req.Context().Done():
+	}
+}
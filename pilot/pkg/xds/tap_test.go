@@ -0,0 +1,177 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tapdata "github.com/envoyproxy/go-control-plane/envoy/data/tap/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	tapsvc "github.com/envoyproxy/go-control-plane/envoy/service/tap/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/istio/pilot/pkg/features"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/envoy"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestNewTapSession(t *testing.T) {
+	cases := []struct {
+		name    string
+		query   string
+		match   string
+		wantErr bool
+	}{
+		{name: "defaults", query: "", match: `"any_match":true`},
+		{name: "method and path", query: "method=POST&path=/api/*", match: `{"name":":path","prefix_match":"/api/"}`},
+		{name: "header", query: "header=X-User:jason", match: `{"name":"x-user","exact_match":"jason"}`},
+		{name: "invalid header", query: "header=x-user", wantErr: true},
+		{name: "invalid duration", query: "duration=10", wantErr: true},
+		{name: "duration too long", query: "duration=1h", wantErr: true},
+		{name: "invalid max traces", query: "maxTraces=0", wantErr: true},
+		{name: "metadata only", query: "", match: `"max_buffered_rx_bytes":0`},
+		{name: "full", query: "full=true", match: `"any_match":true`},
+		{name: "invalid full", query: "full=yes", wantErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			session, err := newTapSession(query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(session.Request), tt.match) {
+				t.Fatalf("tap request %s does not match %s", session.Request, tt.match)
+			}
+			if !strings.Contains(string(session.Request), `"config_id":"istio-tap"`) {
+				t.Fatalf("tap request %s does not have the tap filter config ID", session.Request)
+			}
+		})
+	}
+}
+
+func TestTapz(t *testing.T) {
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	ads := s.ConnectADS().
+		WithType(v3.TapType).
+		WithTimeout(time.Second * 10).
+		WithID("sidecar~127.0.0.1~app.foo~foo.svc.cluster.local")
+	// The subscription of the agent gets an empty response, until a session is started.
+	ads.Request(t, &discovery.DiscoveryRequest{})
+	select {
+	case resp := <-ads.responses:
+		assert.Equal(t, len(resp.Resources), 0)
+	case <-time.After(time.Second * 10):
+		t.Fatal("did not get response in time")
+	}
+
+	tapz := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/tapz"+query, nil)
+		rr := httptest.NewRecorder()
+		s.Discovery.tapz(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, tapz("?proxyID=app.foo").Code, http.StatusBadRequest)
+	test.SetForTest(t, &features.EnableTapSessions, true)
+	assert.Equal(t, tapz("?proxyID=unknown.foo").Code, http.StatusNotFound)
+	assert.Equal(t, tapz("?proxyID=app.foo&duration=1h").Code, http.StatusBadRequest)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- tapz("?proxyID=app.foo&duration=1s&method=GET")
+	}()
+
+	// The session is pushed to the agent, which reports the captured traces once it expired.
+	resp := ads.ExpectResponse(t)
+	assert.Equal(t, len(resp.Resources), 1)
+	value := &wrapperspb.StringValue{}
+	if err := resp.Resources[0].UnmarshalTo(value); err != nil {
+		t.Fatal(err)
+	}
+	session := envoy.TapSession{}
+	if err := json.Unmarshal([]byte(value.Value), &session); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, session.Duration, time.Second)
+	assert.Equal(t, session.Full, false)
+	ads.Request(t, &discovery.DiscoveryRequest{VersionInfo: resp.VersionInfo, ResponseNonce: resp.Nonce})
+
+	conn, err := grpc.Dial("buffcon",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return s.BufListener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	report := func(sessionID string, traces ...*tapdata.TraceWrapper) error {
+		stream, err := tapsvc.NewTapSinkServiceClient(conn).StreamTaps(context.Background())
+		if err != nil {
+			return err
+		}
+		requests := []*tapsvc.StreamTapsRequest{{Identifier: &tapsvc.StreamTapsRequest_Identifier{
+			Node:  &core.Node{Id: "sidecar~127.0.0.1~app.foo~foo.svc.cluster.local"},
+			TapId: sessionID,
+		}}}
+		for _, trace := range traces {
+			requests = append(requests, &tapsvc.StreamTapsRequest{Trace: trace})
+		}
+		for _, req := range requests {
+			if err := stream.Send(req); err != nil {
+				return err
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+	assert.Equal(t, status.Code(report("unknown")), codes.NotFound)
+	trace := &tapdata.TraceWrapper{Trace: &tapdata.TraceWrapper_HttpBufferedTrace{HttpBufferedTrace: &tapdata.HttpBufferedTrace{}}}
+	if err := report(session.ID, trace); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := <-done
+	assert.Equal(t, rr.Code, http.StatusOK)
+	dump := TapDump{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &dump); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(dump.Traces), 1)
+	assert.Equal(t, string(dump.Traces[0]), `{"http_buffered_trace":{}}`)
+	ads.ExpectNoResponse(t)
+}
@@ -39,6 +39,9 @@ const (
 	WorkloadType              = resource.APITypePrefix + "istio.workload.Workload"
 	WorkloadAuthorizationType = resource.APITypePrefix + "istio.security.Authorization"
 
+	// TapType pushes tap sessions to the agent, which reports the captured traces back with the same type.
+	TapType = "istio.io/tap"
+
 	// nolint
 	HttpProtocolOptionsType = "envoy.extensions.upstreams.http.v3.HttpProtocolOptions"
 )
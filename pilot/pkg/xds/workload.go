@@ -16,12 +16,14 @@ package xds
 
 import (
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/proto"
 	"k8s.io/apimachinery/pkg/types"
 
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config/schema/kind"
 	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 type WorkloadGenerator struct {
@@ -46,8 +48,10 @@ func (e WorkloadGenerator) GenerateDeltas(
 	w *model.WatchedResource,
 ) (model.Resources, model.DeletedResources, model.XdsLogDetails, bool, error) {
 	updatedAddresses := model.ConfigNamespacedNameOfKind(req.ConfigsUpdated, kind.Address)
+	// Telemetry configures the access logs of every workload, so changes to it require resending all workloads.
+	telemetryUpdated := model.HasConfigsOfKind(req.ConfigsUpdated, kind.Telemetry)
 	isReq := req.IsRequest()
-	if len(updatedAddresses) == 0 && len(req.ConfigsUpdated) > 0 {
+	if len(updatedAddresses) == 0 && len(req.ConfigsUpdated) > 0 && !telemetryUpdated {
 		// Nothing changed..
 		return nil, nil, model.XdsLogDetails{}, false, nil
 	}
@@ -79,7 +83,7 @@ func (e WorkloadGenerator) GenerateDeltas(
 	// TODO: it is needlessly wasteful to do a full sync just because the rest of Istio thought it was "full"
 	// The only things that can really trigger a "full" push here is trust domain or network changing, which is extremely rare
 	// We do a full push for wildcard requests (initial proxy sync) or for full pushes with no ConfigsUpdates (since we don't know what changed)
-	full := (isReq && w.Wildcard) || (!isReq && req.Full && len(req.ConfigsUpdated) == 0)
+	full := (isReq && w.Wildcard) || (!isReq && req.Full && len(req.ConfigsUpdated) == 0) || telemetryUpdated
 
 	// Nothing to do
 	if len(addresses) == 0 && !full {
@@ -92,6 +96,10 @@ func (e WorkloadGenerator) GenerateDeltas(
 	}
 
 	resources := make(model.Resources, 0)
+	if telemetryUpdated {
+		// Fetch everything
+		addresses = nil
+	}
 	wls, removed := e.s.Env.ServiceDiscovery.PodInformation(addresses)
 	// Note: while "removed" is a weird name for a resource that never existed, this is how the spec works:
 	// https://www.envoyproxy.io/docs/envoy/latest/api-docs/xds_protocol#id2
@@ -102,7 +110,7 @@ func (e WorkloadGenerator) GenerateDeltas(
 		have.Insert(n)
 		resources = append(resources, &discovery.Resource{
 			Name:     n,
-			Resource: protoconv.MessageToAny(wl), // TODO: pre-marshal
+			Resource: protoconv.MessageToAny(withAccessLogging(req.Push, wl)), // TODO: pre-marshal
 		})
 	}
 
@@ -120,6 +128,18 @@ func (e WorkloadGenerator) GenerateDeltas(
 	return resources, removed, model.XdsLogDetails{}, true, nil
 }
 
+// withAccessLogging returns the workload with the access logging configured for it by the Telemetry API, if any.
+// The workload is shared with the ambient index, so it is copied rather than modified.
+func withAccessLogging(push *model.PushContext, wl *model.WorkloadInfo) *workloadapi.Workload {
+	al := push.Telemetry.ZtunnelAccessLogging(wl.Namespace, wl.Labels)
+	if al == nil {
+		return wl.Workload
+	}
+	res := proto.Clone(wl.Workload).(*workloadapi.Workload)
+	res.AccessLogging = al
+	return res
+}
+
 func (e WorkloadGenerator) Generate(proxy *model.Proxy, w *model.WatchedResource, req *model.PushRequest) (model.Resources, model.XdsLogDetails, error) {
 	resources, _, details, _, err := e.GenerateDeltas(proxy, req, w)
 	return resources, details, err
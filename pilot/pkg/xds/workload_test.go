@@ -25,6 +25,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"istio.io/api/security/v1beta1"
+	telemetry "istio.io/api/telemetry/v1alpha1"
+	typev1beta1 "istio.io/api/type/v1beta1"
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
@@ -34,6 +36,7 @@ import (
 	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
 )
 
 func buildExpect(t *testing.T) func(resp *discovery.DeltaDiscoveryResponse, names ...string) {
@@ -328,3 +331,51 @@ func TestWorkloadRBAC(t *testing.T) {
 	createPod(s, "pod", "sa", "127.0.0.1", "node")
 	ads.ExpectNoResponse()
 }
+
+func TestWorkloadAccessLogging(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	expect := buildExpect(t)
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	ads := s.ConnectDeltaADS().WithType(v3.WorkloadType).WithMetadata(model.NodeMetadata{NodeName: "node"})
+
+	ads.Request(&discovery.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"*"},
+	})
+	ads.ExpectEmptyResponse()
+
+	createPod(s, "pod", "sa", "127.0.0.1", "node")
+	resp := ads.ExpectResponse()
+	expect(resp, "127.0.0.1")
+	// Without Telemetry, ztunnel uses its own configuration
+	assert.Equal(t, unmarshalWorkload(t, resp.Resources[0]).AccessLogging, nil)
+
+	// Telemetry applies to every workload, so all of them are pushed
+	if _, err := s.Env().Create(config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.Telemetry,
+			Name:             "logs",
+			Namespace:        "default",
+		},
+		Spec: &telemetry.Telemetry{
+			Selector: &typev1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "sa"}},
+			AccessLogging: []*telemetry.AccessLogging{{
+				Match:     &telemetry.AccessLogging_LogSelector{Mode: telemetry.WorkloadMode_SERVER},
+				Providers: []*telemetry.ProviderRef{{Name: "envoy"}},
+			}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp = ads.ExpectResponse()
+	expect(resp, "127.0.0.1")
+	assert.Equal(t, unmarshalWorkload(t, resp.Resources[0]).AccessLogging, &workloadapi.AccessLogging{Server: true})
+}
+
+func unmarshalWorkload(t *testing.T, r *discovery.Resource) *workloadapi.Workload {
+	t.Helper()
+	wl := &workloadapi.Workload{}
+	if err := r.Resource.UnmarshalTo(wl); err != nil {
+		t.Fatal(err)
+	}
+	return wl
+}
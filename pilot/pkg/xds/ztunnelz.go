@@ -0,0 +1,138 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"istio.io/istio/pilot/pkg/features"
+)
+
+// proxyAdminTimeout bounds how long we wait for a single proxy to respond.
+const proxyAdminTimeout = 5 * time.Second
+
+// ZtunnelDump holds the state reported by a single ztunnel admin interface.
+type ZtunnelDump struct {
+	ProxyID string `json:"proxy"`
+	// ConfigDump is the raw config dump of the ztunnel, excluding certificates.
+	ConfigDump map[string]json.RawMessage `json:"config_dump,omitempty"`
+	// Certificates is the certificate state of the ztunnel, split out of the config dump.
+	Certificates json.RawMessage `json:"certificates,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// ztunnelz aggregates the config dump and certificates of every ztunnel connected to this istiod, keyed by node.
+// The results can be filtered to a single node with the "node" query parameter.
+func (s *DiscoveryServer) ztunnelz(w http.ResponseWriter, req *http.Request) {
+	node := req.URL.Query().Get("node")
+	conns := s.ztunnelConnections(node)
+	if node != "" && len(conns) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("No ztunnel for the node is connected to this Pilot instance. It may be connected to another instance.\n"))
+		return
+	}
+
+	res := make(map[string]ZtunnelDump, len(conns))
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for nodeName, con := range conns {
+		nodeName, con := nodeName, con
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dump := s.fetchZtunnelDump(req.Context(), con)
+			mu.Lock()
+			res[nodeName] = dump
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	writeJSON(w, res, req)
+}
+
+// ztunnelConnections returns the connected ztunnels keyed by node name, optionally filtered to a single node.
+func (s *DiscoveryServer) ztunnelConnections(node string) map[string]*Connection {
+	conns := map[string]*Connection{}
+	for _, con := range s.Clients() {
+		if con.proxy == nil || !con.proxy.IsZTunnel() {
+			continue
+		}
+		nodeName := con.proxy.GetNodeName()
+		if nodeName == "" {
+			nodeName = con.proxy.ID
+		}
+		if node != "" && node != nodeName {
+			continue
+		}
+		conns[nodeName] = con
+	}
+	return conns
+}
+
+// fetchZtunnelDump fetches the config dump of the ztunnel. Its admin interface only listens on localhost, so it is
+// reached through a port forward to the ztunnel pod, which the proxy ID names.
+func (s *DiscoveryServer) fetchZtunnelDump(ctx context.Context, con *Connection) ZtunnelDump {
+	dump := ZtunnelDump{ProxyID: con.proxy.ID}
+	if s.ZtunnelAdminClient == nil {
+		dump.Error = "the ztunnel admin interface is not reachable without a Kubernetes client"
+		return dump
+	}
+	ns := con.proxy.ConfigNamespace
+	pod := strings.TrimSuffix(con.proxy.ID, "."+ns)
+	ctx, cancel := context.WithTimeout(ctx, proxyAdminTimeout)
+	defer cancel()
+	body, err := s.ZtunnelAdminClient.EnvoyDoWithPort(ctx, pod, ns, http.MethodGet, "config_dump", features.ZtunnelAdminPort)
+	if err != nil {
+		dump.Error = err.Error()
+		return dump
+	}
+	if err := json.Unmarshal(body, &dump.ConfigDump); err != nil {
+		dump.Error = fmt.Sprintf("failed to parse config dump: %v", err)
+		return dump
+	}
+	dump.Certificates = dump.ConfigDump["certificates"]
+	delete(dump.ConfigDump, "certificates")
+	return dump
+}
+
+// proxyAdminRequest issues a GET request against the admin or stats interface of a proxy at the given address.
+func proxyAdminRequest(ctx context.Context, address string, path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, proxyAdminTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proxy: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,85 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestZtunnelz(t *testing.T) {
+	test.SetForTest(t, &features.EnableAmbientControllers, true)
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	// The admin interface is reached through a port forward to the ztunnel pod.
+	s.Discovery.ZtunnelAdminClient = kube.MockClient{Results: map[string][]byte{
+		"ztunnel-abc": []byte(`{"workloads":{"10.0.0.1":{"name":"pod"}},` +
+			`"certificates":[{"identity":"spiffe://cluster.local/ns/default/sa/default"}]}`),
+	}}
+	ads := s.ConnectDeltaADS().
+		WithType(v3.WorkloadAuthorizationType).
+		WithTimeout(time.Second * 10).
+		WithID("ztunnel~127.0.0.1~ztunnel-abc.istio-system~istio-system.svc.cluster.local").
+		WithMetadata(model.NodeMetadata{NodeName: "node-1"})
+	ads.Request(&discovery.DeltaDiscoveryRequest{ResourceNamesSubscribe: []string{"*"}})
+	ads.ExpectEmptyResponse()
+
+	fetch := func(query string) (int, map[string]ZtunnelDump) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/debug/ztunnelz"+query, nil)
+		rr := httptest.NewRecorder()
+		s.Discovery.ztunnelz(rr, req)
+		res := map[string]ZtunnelDump{}
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rr.Code, res
+	}
+
+	code, res := fetch("")
+	assert.Equal(t, code, http.StatusOK)
+	dump, f := res["node-1"]
+	if !f {
+		t.Fatalf("expected node-1 in response, got %v", res)
+	}
+	assert.Equal(t, dump.Error, "")
+	assert.Equal(t, string(dump.Certificates), `[{"identity":"spiffe://cluster.local/ns/default/sa/default"}]`)
+	if _, f := dump.ConfigDump["certificates"]; f {
+		t.Fatalf("certificates should be split out of the config dump")
+	}
+	if _, f := dump.ConfigDump["workloads"]; !f {
+		t.Fatalf("expected workloads in config dump")
+	}
+
+	code, res = fetch("?node=node-1")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, len(res), 1)
+
+	code, _ = fetch("?node=node-2")
+	assert.Equal(t, code, http.StatusNotFound)
+}
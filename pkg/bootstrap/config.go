@@ -21,10 +21,12 @@ import (
 	"net/netip"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
@@ -37,6 +39,7 @@ import (
 	"istio.io/istio/pkg/bootstrap/option"
 	"istio.io/istio/pkg/bootstrap/platform"
 	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/telemetry"
 	"istio.io/istio/pkg/kube/labels"
 	"istio.io/istio/pkg/security"
 	"istio.io/istio/pkg/util/protomarshal"
@@ -290,9 +293,37 @@ func getStatsOptions(meta *model.BootstrapNodeMetadata) []option.Instance {
 			inclusionSuffixes, proxyConfigSuffixes)),
 		option.EnvoyStatsMatcherInclusionRegexp(parseOption(RegexAnno, requiredEnvoyStatsMatcherInclusionRegexes, proxyConfigRegexps)),
 		option.EnvoyExtraStatTags(extraStatTags),
+		option.EnvoyHistogramBuckets(histogramBucketSettings(meta.Annotations[constants.StatsHistogramBucketsAnnotation])),
 	}
 }
 
+// histogramBucketSettings returns the Envoy histogram bucket settings of the statsHistogramBuckets annotation.
+func histogramBucketSettings(value string) string {
+	if value == "" {
+		return ""
+	}
+	buckets, err := telemetry.ParseStatHistogramBuckets(value)
+	if err != nil {
+		log.Warnf("ignoring %s annotation: %v", constants.StatsHistogramBucketsAnnotation, err)
+		return ""
+	}
+	type bucketSettings struct {
+		Match   map[string]string `json:"match"`
+		Buckets []float64         `json:"buckets"`
+	}
+	prefixes := maps.Keys(buckets)
+	sort.Strings(prefixes)
+	settings := make([]bucketSettings, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		settings = append(settings, bucketSettings{Match: map[string]string{"prefix": prefix}, Buckets: buckets[prefix]})
+	}
+	out, err := json.Marshal(settings)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
 func lightstepAccessTokenFile(config string) string {
 	return path.Join(config, lightstepAccessTokenBase)
 }
@@ -270,6 +270,12 @@ func TestGolden(t *testing.T) {
 				regexps:  "http.[0-9]*\\.[0-9]*\\.[0-9]*\\.[0-9]*_8080.downstream_rq_time",
 			},
 		},
+		{
+			base: "stats_histogram_buckets",
+			annotations: map[string]string{
+				"sidecar.istio.io/statsHistogramBuckets": `{"istiocustom.istio_request_duration_milliseconds": [0.5, 1, 5, 10]}`,
+			},
+		},
 		{
 			base: "tracing_tls",
 		},
@@ -205,6 +205,11 @@ func EnvoyExtraStatTags(value []string) Instance {
 	return newStringArrayOptionOrSkipIfEmpty("extraStatTags", value)
 }
 
+// EnvoyHistogramBuckets sets the histogram_bucket_settings of the stats config, a JSON list.
+func EnvoyHistogramBuckets(value string) Instance {
+	return newOptionOrSkipIfZero("histogramBuckets", value)
+}
+
 func EnvoyStatsMatcherInclusionPrefix(value []string) Instance {
 	return newStringArrayOptionOrSkipIfEmpty("inclusionPrefix", value)
 }
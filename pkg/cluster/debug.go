@@ -14,6 +14,8 @@
 
 package cluster
 
+import "time"
+
 // DebugInfo contains minimal information about remote clusters.
 // This struct is defined here, in a package that avoids many imports, since xds/debug usually
 // affects agent binary size. We avoid embedding other parts of a "remote cluster" struct like kube clients.
@@ -21,4 +23,16 @@ type DebugInfo struct {
 	ID         ID     `json:"id"`
 	SecretName string `json:"secretName"`
 	SyncStatus string `json:"syncStatus"`
+	// Health is the health of the cluster, e.g. healthy, auth-failure or stale.
+	Health string `json:"health,omitempty"`
+	// HealthMessage details the health of the cluster, when it is not healthy.
+	HealthMessage string `json:"healthMessage,omitempty"`
+	// CredentialExpiry is when the credentials used to access the cluster expire, if known.
+	CredentialExpiry *time.Time `json:"credentialExpiry,omitempty"`
+	// LastError is the last error reaching the cluster. Unlike HealthMessage, it is kept once the cluster recovers.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is when LastError occurred.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+	// Endpoints is the number of endpoints read from the cluster.
+	Endpoints int `json:"endpoints"`
 }
@@ -0,0 +1,60 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"istio.io/istio/pkg/util/sets"
+)
+
+// Selector selects the clusters a config applies to. An empty selector selects every cluster.
+type Selector []ID
+
+// ParseSelector parses a comma separated list of cluster IDs, for example cluster-1,cluster-2.
+func ParseSelector(value string) (Selector, error) {
+	var selector Selector
+	seen := sets.New[string]()
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if seen.InsertContains(id) {
+			return nil, fmt.Errorf("duplicate cluster %q", id)
+		}
+		selector = append(selector, ID(id))
+	}
+	return selector, nil
+}
+
+// Matches returns true if the selector selects the cluster.
+func (s Selector) Matches(id ID) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, selected := range s {
+		if selected == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Equals returns true if both selectors select the same clusters.
+func (s Selector) Equals(other Selector) bool {
+	return sets.New(s...).Equals(sets.New(other...))
+}
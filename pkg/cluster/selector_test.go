@@ -0,0 +1,35 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestSelector(t *testing.T) {
+	s, err := ParseSelector(" cluster-1, cluster-2,")
+	assert.NoError(t, err)
+	assert.Equal(t, s, Selector{"cluster-1", "cluster-2"})
+	assert.Equal(t, s.Matches("cluster-2"), true)
+	assert.Equal(t, s.Matches("cluster-3"), false)
+	assert.Equal(t, Selector(nil).Matches("cluster-3"), true)
+	assert.Equal(t, s.Equals(Selector{"cluster-2", "cluster-1"}), true)
+	assert.Equal(t, s.Equals(nil), false)
+
+	_, err = ParseSelector("cluster-1,cluster-1")
+	assert.Error(t, err)
+}
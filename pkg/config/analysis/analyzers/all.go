@@ -16,6 +16,7 @@ package analyzers
 
 import (
 	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/analyzers/ambient"
 	"istio.io/istio/pkg/config/analysis/analyzers/annotations"
 	"istio.io/istio/pkg/config/analysis/analyzers/authz"
 	"istio.io/istio/pkg/config/analysis/analyzers/deployment"
@@ -38,8 +39,12 @@ import (
 func All() []analysis.Analyzer {
 	analyzers := []analysis.Analyzer{
 		// Please keep this list sorted alphabetically by pkg.name for convenience
+		&ambient.EnrollmentAnalyzer{},
+		&ambient.InteropAnalyzer{},
 		&annotations.K8sAnalyzer{},
 		&authz.AuthorizationPoliciesAnalyzer{},
+		&authz.TrustDomainAnalyzer{},
+		&authz.TargetGatewayAnalyzer{},
 		&deployment.ServiceAssociationAnalyzer{},
 		&deployment.ApplicationUIDAnalyzer{},
 		&deprecation.FieldAnalyzer{},
@@ -51,6 +56,7 @@ func All() []analysis.Analyzer {
 		&injection.ImageAnalyzer{},
 		&injection.ImageAutoAnalyzer{},
 		&multicluster.MeshNetworksAnalyzer{},
+		&multicluster.RemoteSecretAnalyzer{},
 		&service.PortNameAnalyzer{},
 		&sidecar.DefaultSelectorAnalyzer{},
 		&sidecar.SelectorAnalyzer{},
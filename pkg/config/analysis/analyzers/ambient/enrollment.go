@@ -0,0 +1,166 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/analyzers/util"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/envoyfilter"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// EnrollmentAnalyzer checks namespaces using ambient mode for configuration that only applies to sidecars,
+// and so silently stops working once the namespace is enrolled. To check a namespace before enrolling it,
+// analyze the namespace with the ambient label applied, for example `istioctl analyze namespace.yaml`.
+type EnrollmentAnalyzer struct{}
+
+var _ analysis.Analyzer = &EnrollmentAnalyzer{}
+
+// sidecarOnlyAnnotations are the pod annotations only read by the sidecar injector.
+// Any other annotation with the sidecar.istio.io/ prefix is also sidecar only.
+var sidecarOnlyAnnotations = sets.New(
+	annotation.InjectTemplates.Name,
+	annotation.ProxyConfig.Name,
+)
+
+// ignoredSidecarAnnotations are sidecar annotations that are expected on ambient pods.
+var ignoredSidecarAnnotations = sets.New(
+	// Injection is handled by the interop analyzer.
+	annotation.SidecarInject.Name,
+	annotation.SidecarStatus.Name,
+)
+
+const sidecarAnnotationPrefix = "sidecar.istio.io/"
+
+// Metadata implements Analyzer
+func (a *EnrollmentAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "ambient.EnrollmentAnalyzer",
+		Description: "Checks namespaces using ambient mode for configuration only applied to sidecars",
+		Inputs: []config.GroupVersionKind{
+			gvk.Namespace,
+			gvk.Pod,
+			gvk.EnvoyFilter,
+			gvk.Sidecar,
+			gvk.KubernetesGateway,
+		},
+	}
+}
+
+// Analyze implements Analyzer
+func (a *EnrollmentAnalyzer) Analyze(c analysis.Context) {
+	ambientNamespaces := sets.New[string]()
+	c.ForEach(gvk.Namespace, func(r *resource.Instance) bool {
+		ns := r.Metadata.FullName.String()
+		if util.IsSystemNamespace(resource.Namespace(ns)) || r.Metadata.Labels[constants.DataplaneMode] != constants.DataplaneModeAmbient {
+			return true
+		}
+		// Namespaces mixing sidecars and ambient may use sidecar only configuration for their sidecars.
+		_, hasRevision := r.Metadata.Labels[label.IoIstioRev.Name]
+		if r.Metadata.Labels[util.InjectionLabelName] == util.InjectionLabelEnableValue || hasRevision {
+			return true
+		}
+		ambientNamespaces.Insert(ns)
+		return true
+	})
+	if len(ambientNamespaces) == 0 {
+		return
+	}
+
+	// The waypoints of the namespaces are Envoy proxies, and so do apply the configuration selecting them.
+	waypoints := sets.New[resource.FullName]()
+	c.ForEach(gvk.KubernetesGateway, func(r *resource.Instance) bool {
+		if r.Message.(*v1beta1.GatewaySpec).GatewayClassName == constants.WaypointGatewayClassName {
+			waypoints.Insert(r.Metadata.FullName)
+		}
+		return true
+	})
+
+	for _, k := range []config.GroupVersionKind{gvk.EnvoyFilter, gvk.Sidecar} {
+		k := k
+		c.ForEach(k, func(r *resource.Instance) bool {
+			ns := r.Metadata.FullName.Namespace.String()
+			if ambientNamespaces.Contains(ns) && !appliesToWaypoint(r, waypoints) {
+				m := msg.NewAmbientUnsupportedResource(r, k.Kind, r.Metadata.FullName.Name.String(), ns)
+				if line, ok := util.ErrorLine(r, util.MetadataName); ok {
+					m.Line = line
+				}
+				c.Report(k, m)
+			}
+			return true
+		})
+	}
+
+	c.ForEach(gvk.Pod, func(r *resource.Instance) bool {
+		ns := r.Metadata.FullName.Namespace.String()
+		if !ambientNamespaces.Contains(ns) {
+			return true
+		}
+		// Pods with a sidecar, or opted out of redirection, are not captured by ztunnel.
+		if _, injected := r.Metadata.Annotations[annotation.SidecarStatus.Name]; injected {
+			return true
+		}
+		if r.Metadata.Annotations[constants.AmbientRedirection] == constants.AmbientRedirectionDisabled {
+			return true
+		}
+		var found []string
+		for k := range r.Metadata.Annotations {
+			if sidecarOnlyAnnotations.Contains(k) ||
+				(strings.HasPrefix(k, sidecarAnnotationPrefix) && !ignoredSidecarAnnotations.Contains(k)) {
+				found = append(found, k)
+			}
+		}
+		sort.Strings(found)
+		for _, k := range found {
+			c.Report(gvk.Pod, msg.NewAmbientUnsupportedPodAnnotation(r, r.Metadata.FullName.Name.String(), k, ns))
+		}
+		return true
+	})
+}
+
+// appliesToWaypoint returns whether the EnvoyFilter or Sidecar only applies to waypoints, through its target-ref
+// annotation or the labels of the waypoint pods in its workload selector.
+func appliesToWaypoint(r *resource.Instance, waypoints sets.Set[resource.FullName]) bool {
+	var selector map[string]string
+	switch spec := r.Message.(type) {
+	case *networking.EnvoyFilter:
+		if v, f := r.Metadata.Annotations[constants.EnvoyFilterTargetRefAnnotation]; f {
+			ref, err := envoyfilter.ParseTargetRef(v)
+			return err == nil && ref.Kind == gvk.KubernetesGateway.Kind &&
+				waypoints.Contains(resource.NewFullName(r.Metadata.FullName.Namespace, resource.LocalName(ref.Name)))
+		}
+		selector = spec.GetWorkloadSelector().GetLabels()
+	case *networking.Sidecar:
+		selector = spec.GetWorkloadSelector().GetLabels()
+	}
+	if selector[constants.ManagedGatewayLabel] == constants.ManagedGatewayMeshControllerLabel {
+		return true
+	}
+	name, f := selector[constants.GatewayNameLabel]
+	return f && waypoints.Contains(resource.NewFullName(r.Metadata.FullName.Namespace, resource.LocalName(name)))
+}
@@ -0,0 +1,91 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/api/label"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/analyzers/util"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// InteropAnalyzer checks the configuration of namespaces mixing sidecar and ambient workloads.
+type InteropAnalyzer struct{}
+
+var _ analysis.Analyzer = &InteropAnalyzer{}
+
+var validInteropModes = sets.New(
+	constants.AmbientInteropModeHBONE,
+	constants.AmbientInteropModeMTLS,
+	constants.AmbientInteropModeWaypoint,
+)
+
+// Metadata implements Analyzer
+func (a *InteropAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "ambient.InteropAnalyzer",
+		Description: "Checks the interoperation between sidecar and ambient workloads",
+		Inputs: []config.GroupVersionKind{
+			gvk.Namespace,
+			gvk.KubernetesGateway,
+		},
+	}
+}
+
+// Analyze implements Analyzer
+func (a *InteropAnalyzer) Analyze(c analysis.Context) {
+	waypointNamespaces := sets.New[string]()
+	c.ForEach(gvk.KubernetesGateway, func(r *resource.Instance) bool {
+		gw := r.Message.(*v1beta1.GatewaySpec)
+		if gw.GatewayClassName == constants.WaypointGatewayClassName {
+			waypointNamespaces.Insert(r.Metadata.FullName.Namespace.String())
+		}
+		return true
+	})
+
+	c.ForEach(gvk.Namespace, func(r *resource.Instance) bool {
+		ns := r.Metadata.FullName.String()
+		if util.IsSystemNamespace(resource.Namespace(ns)) {
+			return true
+		}
+		mode, hasMode := r.Metadata.Annotations[constants.AmbientInteropMode]
+		if hasMode && !validInteropModes.Contains(mode) {
+			m := msg.NewInvalidAmbientInteropMode(r, ns, constants.AmbientInteropMode, mode, sets.SortedList(validInteropModes))
+			if line, ok := util.ErrorLine(r, util.MetadataName); ok {
+				m.Line = line
+			}
+			c.Report(gvk.Namespace, m)
+			return true
+		}
+		if mode == constants.AmbientInteropModeWaypoint && !waypointNamespaces.Contains(ns) {
+			c.Report(gvk.Namespace, msg.NewAmbientInteropWaypointMissing(r, ns))
+		}
+
+		ambient := r.Metadata.Labels[constants.DataplaneMode] == constants.DataplaneModeAmbient
+		_, hasRevision := r.Metadata.Labels[label.IoIstioRev.Name]
+		injected := r.Metadata.Labels[util.InjectionLabelName] == util.InjectionLabelEnableValue || hasRevision
+		if ambient && injected && !hasMode {
+			c.Report(gvk.Namespace, msg.NewNamespaceMixedDataplaneModes(r, ns, constants.AmbientInteropMode))
+		}
+		return true
+	})
+}
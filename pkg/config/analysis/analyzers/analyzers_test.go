@@ -25,6 +25,7 @@ import (
 
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/analyzers/ambient"
 	"istio.io/istio/pkg/config/analysis/analyzers/annotations"
 	"istio.io/istio/pkg/config/analysis/analyzers/authz"
 	"istio.io/istio/pkg/config/analysis/analyzers/deployment"
@@ -70,6 +71,27 @@ type testCase struct {
 // * Expected messages are in the format {msg.ValidationMessageType, "<ResourceKind>/<Namespace>/<ResourceName>"}.
 //   - Note that if Namespace is omitted in the input YAML, it will be skipped here.
 var testGrid = []testCase{
+	{
+		name:       "ambientInterop",
+		inputFiles: []string{"testdata/ambient-interop.yaml"},
+		analyzer:   &ambient.InteropAnalyzer{},
+		expected: []message{
+			{msg.NamespaceMixedDataplaneModes, "Namespace mixed"},
+			{msg.InvalidAmbientInteropMode, "Namespace invalid"},
+			{msg.AmbientInteropWaypointMissing, "Namespace no-waypoint"},
+		},
+	},
+	{
+		name:       "ambientEnrollment",
+		inputFiles: []string{"testdata/ambient-enrollment.yaml"},
+		analyzer:   &ambient.EnrollmentAnalyzer{},
+		expected: []message{
+			{msg.AmbientUnsupportedResource, "EnvoyFilter ambient/filter"},
+			{msg.AmbientUnsupportedResource, "Sidecar ambient/default"},
+			{msg.AmbientUnsupportedPodAnnotation, "Pod ambient/annotated"},
+			{msg.AmbientUnsupportedPodAnnotation, "Pod ambient/annotated"},
+		},
+	},
 	{
 		name: "misannoted",
 		inputFiles: []string{
@@ -406,6 +428,16 @@ var testGrid = []testCase{
 			{msg.UnknownMeshNetworksServiceRegistry, "MeshNetworks istio-system/meshnetworks"},
 		},
 	},
+	{
+		name: "expired credentials of remote secrets",
+		inputFiles: []string{
+			"testdata/multicluster-remote-secret-expiry.yaml",
+		},
+		analyzer: &multicluster.RemoteSecretAnalyzer{},
+		expected: []message{
+			{msg.RemoteClusterCredentialExpired, "Secret istio-system/istio-remote-secret-expired"},
+		},
+	},
 	{
 		name: "authorizationpolicies",
 		inputFiles: []string{
@@ -796,6 +828,26 @@ var testGrid = []testCase{
 			{msg.Deprecated, "Telemetry istio-system/mesh-default"},
 		},
 	},
+	{
+		name:           "authorizationpolicy trust domains",
+		inputFiles:     []string{"testdata/authorizationpolicies-trustdomain.yaml"},
+		analyzer:       &authz.TrustDomainAnalyzer{},
+		meshConfigFile: "testdata/authorizationpolicies-trustdomain-meshconfig.yaml",
+		expected: []message{
+			{msg.UnknownTrustDomain, "AuthorizationPolicy default/unknown-td"},
+			{msg.UnknownTrustDomain, "AuthorizationPolicy default/unknown-td"},
+		},
+	},
+	{
+		name:       "authorizationpolicy target gateway",
+		inputFiles: []string{"testdata/authorizationpolicies-targetgateway.yaml"},
+		analyzer:   &authz.TargetGatewayAnalyzer{},
+		expected: []message{
+			{msg.ReferencedResourceNotFound, "AuthorizationPolicy default/unknown-gateway"},
+			{msg.ReferencedResourceNotFound, "AuthorizationPolicy default/unknown-listener"},
+			{msg.ReferencedResourceNotFound, "AuthorizationPolicy istio-system/other-namespace"},
+		},
+	},
 }
 
 // regex patterns for analyzer names that should be explicitly ignored for testing
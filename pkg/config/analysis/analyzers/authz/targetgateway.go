@@ -0,0 +1,67 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+// TargetGatewayAnalyzer checks that the Kubernetes Gateway and listener targeted by an authorization policy exist.
+type TargetGatewayAnalyzer struct{}
+
+var _ analysis.Analyzer = &TargetGatewayAnalyzer{}
+
+func (a *TargetGatewayAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "auth.TargetGatewayAnalyzer",
+		Description: "Checks that the Gateway listener targeted by an authorization policy exists",
+		Inputs: []config.GroupVersionKind{
+			gvk.AuthorizationPolicy,
+			gvk.KubernetesGateway,
+		},
+	}
+}
+
+func (a *TargetGatewayAnalyzer) Analyze(c analysis.Context) {
+	c.ForEach(gvk.AuthorizationPolicy, func(r *resource.Instance) bool {
+		gateway := r.Metadata.Annotations[constants.TargetGatewayAnnotation]
+		if gateway == "" {
+			return true
+		}
+		gw := c.Find(gvk.KubernetesGateway, resource.NewFullName(r.Metadata.FullName.Namespace, resource.LocalName(gateway)))
+		if gw == nil {
+			c.Report(gvk.AuthorizationPolicy, msg.NewReferencedResourceNotFound(r, "gateway", gateway))
+			return true
+		}
+		section := r.Metadata.Annotations[constants.TargetSectionNameAnnotation]
+		if section == "" {
+			return true
+		}
+		for _, l := range gw.Message.(*v1beta1.GatewaySpec).Listeners {
+			if string(l.Name) == section {
+				return true
+			}
+		}
+		c.Report(gvk.AuthorizationPolicy, msg.NewReferencedResourceNotFound(r, "gateway listener", gateway+"/"+section))
+		return true
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"istio.io/api/mesh/v1alpha1"
+	"istio.io/api/security/v1beta1"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/analyzers/util"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// TrustDomainAnalyzer checks that principals in authorization policies belong to a trust domain
+// the mesh trusts: the mesh trust domain, one of its aliases, or a federated trust domain with
+// CA certificates configured in the MeshConfig.
+type TrustDomainAnalyzer struct{}
+
+var _ analysis.Analyzer = &TrustDomainAnalyzer{}
+
+func (a *TrustDomainAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "auth.TrustDomainAnalyzer",
+		Description: "Checks that authorization policy principals belong to a trusted trust domain",
+		Inputs: []config.GroupVersionKind{
+			gvk.MeshConfig,
+			gvk.AuthorizationPolicy,
+		},
+	}
+}
+
+func (a *TrustDomainAnalyzer) Analyze(c analysis.Context) {
+	trusted := trustedDomains(c)
+	if trusted == nil {
+		return
+	}
+
+	c.ForEach(gvk.AuthorizationPolicy, func(r *resource.Instance) bool {
+		ap := r.Message.(*v1beta1.AuthorizationPolicy)
+		for i, rule := range ap.Rules {
+			for j, from := range rule.From {
+				if from.Source == nil {
+					continue
+				}
+				principals := append(append([]string{}, from.Source.Principals...), from.Source.NotPrincipals...)
+				for k, principal := range principals {
+					td, ok := principalTrustDomain(principal)
+					if !ok || trusted.Contains(td) {
+						continue
+					}
+					m := msg.NewUnknownTrustDomain(r, principal, td)
+					path := util.AuthorizationPolicyPrincipal
+					if k >= len(from.Source.Principals) {
+						k -= len(from.Source.Principals)
+						path = util.AuthorizationPolicyNotPrincipal
+					}
+					if line, ok := util.ErrorLine(r, fmt.Sprintf(path, i, j, k)); ok {
+						m.Line = line
+					}
+					c.Report(gvk.AuthorizationPolicy, m)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// trustedDomains returns the trust domains known to the mesh, or nil if there is no MeshConfig to check against.
+func trustedDomains(c analysis.Context) sets.String {
+	var mc *v1alpha1.MeshConfig
+	c.ForEach(gvk.MeshConfig, func(r *resource.Instance) bool {
+		mc = r.Message.(*v1alpha1.MeshConfig)
+		return r.Metadata.FullName.Name != util.MeshConfigName
+	})
+	if mc == nil {
+		return nil
+	}
+
+	// "cluster.local" always refers to the local trust domain.
+	trusted := sets.New(constants.DefaultClusterLocalDomain, mc.GetTrustDomain())
+	trusted.InsertAll(mc.GetTrustDomainAliases()...)
+	for _, ca := range mc.GetCaCertificates() {
+		trusted.InsertAll(ca.GetTrustDomains()...)
+	}
+	return trusted
+}
+
+// principalTrustDomain extracts the trust domain from a principal of the form <trust-domain>/ns/<ns>/sa/<sa>.
+// Principals that do not enforce a specific trust domain are ignored.
+func principalTrustDomain(principal string) (string, bool) {
+	td, rest, ok := strings.Cut(principal, "/")
+	if !ok || !strings.HasPrefix(rest, "ns/") || td == "" || strings.Contains(td, "*") {
+		return "", false
+	}
+	return td, true
+}
@@ -76,7 +76,7 @@ func (a *Analyzer) Analyze(c analysis.Context) {
 		nsRevision, okNewInjectionLabel := r.Metadata.Labels[RevisionInjectionLabelName]
 
 		if r.Metadata.Labels[constants.DataplaneMode] == constants.DataplaneModeAmbient {
-			// TODO (GregHanson): warn if namespace is labeled for injection and ambient?
+			// Namespaces labeled for both injection and ambient are checked by ambient.InteropAnalyzer
 			return true
 		}
 
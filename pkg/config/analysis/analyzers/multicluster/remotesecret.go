@@ -0,0 +1,74 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/multicluster"
+)
+
+// RemoteSecretAnalyzer checks the expiry of the credentials of the remote secrets.
+type RemoteSecretAnalyzer struct{}
+
+var _ analysis.Analyzer = &RemoteSecretAnalyzer{}
+
+// Metadata implements Analyzer
+func (s *RemoteSecretAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "multicluster.RemoteSecretAnalyzer",
+		Description: "Check the expiry of the credentials of the remote secrets",
+		Inputs: []config.GroupVersionKind{
+			gvk.Secret,
+		},
+	}
+}
+
+// Analyze implements Analyzer
+func (s *RemoteSecretAnalyzer) Analyze(c analysis.Context) {
+	now := time.Now()
+	c.ForEach(gvk.Secret, func(r *resource.Instance) bool {
+		if r.Metadata.Labels[multicluster.MultiClusterSecretLabel] != "true" {
+			return true
+		}
+		secret := r.Message.(*v1.Secret)
+		clusters := make([]string, 0, len(secret.Data))
+		for cluster := range secret.Data {
+			clusters = append(clusters, cluster)
+		}
+		sort.Strings(clusters)
+		for _, cluster := range clusters {
+			expiry := multicluster.CredentialExpiry(secret.Data[cluster])
+			if expiry.IsZero() {
+				continue
+			}
+			if !now.Before(expiry) {
+				c.Report(gvk.Secret, msg.NewRemoteClusterCredentialExpired(r, cluster, expiry.Format(time.RFC3339)))
+			} else if expiry.Sub(now) < features.RemoteClusterCredentialExpiryWarning {
+				c.Report(gvk.Secret, msg.NewRemoteClusterCredentialExpiring(r, cluster, expiry.Format(time.RFC3339)))
+			}
+		}
+		return true
+	})
+}
@@ -78,6 +78,14 @@ const (
 	// Required parameters: rule index, from index, namespace index.
 	AuthorizationPolicyNameSpace = "{.spec.rules[%d].from[%d].source.namespaces[%d]}"
 
+	// Path for principal in authorizationPolicy.
+	// Required parameters: rule index, from index, principal index.
+	AuthorizationPolicyPrincipal = "{.spec.rules[%d].from[%d].source.principals[%d]}"
+
+	// Path for notPrincipal in authorizationPolicy.
+	// Required parameters: rule index, from index, notPrincipal index.
+	AuthorizationPolicyNotPrincipal = "{.spec.rules[%d].from[%d].source.notPrincipals[%d]}"
+
 	// Path for annotation.
 	// Required parameters: annotation name.
 	Annotation = "{.metadata.annotations.%s}"
@@ -236,6 +236,38 @@ var (
 	// MultipleTelemetriesWithoutWorkloadSelectors defines a diag.MessageType for message "MultipleTelemetriesWithoutWorkloadSelectors".
 	// Description: More than one telemetry resource in a namespace has no workload selector
 	MultipleTelemetriesWithoutWorkloadSelectors = diag.NewMessageType(diag.Error, "IST0160", "The Telemetries %v in namespace %q have no workload selector, which can lead to undefined behavior.")
+
+	// NamespaceMixedDataplaneModes defines a diag.MessageType for message "NamespaceMixedDataplaneModes".
+	// Description: A namespace is labeled for both ambient mode and sidecar injection, without configuring how they interoperate
+	NamespaceMixedDataplaneModes = diag.NewMessageType(diag.Warning, "IST0161", "The namespace %q is labeled for both ambient mode and sidecar injection. Set the %q annotation to make the interoperation between sidecar and ambient workloads explicit.")
+
+	// InvalidAmbientInteropMode defines a diag.MessageType for message "InvalidAmbientInteropMode".
+	// Description: A namespace has an invalid ambient interop mode
+	InvalidAmbientInteropMode = diag.NewMessageType(diag.Error, "IST0162", "The namespace %q has an invalid %q annotation value %q. Valid values are %v.")
+
+	// AmbientInteropWaypointMissing defines a diag.MessageType for message "AmbientInteropWaypointMissing".
+	// Description: A namespace requires sidecars to use a waypoint, but has no waypoint
+	AmbientInteropWaypointMissing = diag.NewMessageType(diag.Warning, "IST0163", "The namespace %q requires sidecars to send traffic through a waypoint, but no waypoint is deployed in the namespace. Sidecars will be unable to reach its ambient workloads.")
+
+	// AmbientUnsupportedResource defines a diag.MessageType for message "AmbientUnsupportedResource".
+	// Description: A resource only applied to sidecars is configured for a namespace using ambient mode
+	AmbientUnsupportedResource = diag.NewMessageType(diag.Warning, "IST0164", "The %s %q has no effect on the workloads in namespace %q, as it is only applied to sidecars and the namespace uses ambient mode.")
+
+	// AmbientUnsupportedPodAnnotation defines a diag.MessageType for message "AmbientUnsupportedPodAnnotation".
+	// Description: A pod using ambient mode has an annotation only applied to sidecars
+	AmbientUnsupportedPodAnnotation = diag.NewMessageType(diag.Warning, "IST0165", "The pod %q has the annotation %q, which has no effect as it is only applied to sidecars and the namespace %q uses ambient mode.")
+
+	// UnknownTrustDomain defines a diag.MessageType for message "UnknownTrustDomain".
+	// Description: An authorization policy references a principal from a trust domain the mesh does not trust
+	UnknownTrustDomain = diag.NewMessageType(diag.Warning, "IST0166", "The principal %q references the trust domain %q, which is not the mesh trust domain, one of its aliases, or a trust domain with configured CA certificates.")
+
+	// RemoteClusterCredentialExpired defines a diag.MessageType for message "RemoteClusterCredentialExpired".
+	// Description: The credentials of a remote cluster are expired
+	RemoteClusterCredentialExpired = diag.NewMessageType(diag.Error, "IST0167", "The credentials of the remote cluster %q expired at %v: istiod cannot watch the cluster.")
+
+	// RemoteClusterCredentialExpiring defines a diag.MessageType for message "RemoteClusterCredentialExpiring".
+	// Description: The credentials of a remote cluster expire soon
+	RemoteClusterCredentialExpiring = diag.NewMessageType(diag.Warning, "IST0168", "The credentials of the remote cluster %q expire at %v.")
 )
 
 // All returns a list of all known message types.
@@ -298,6 +330,14 @@ func All() []*diag.MessageType {
 		PodsIstioProxyImageMismatchInNamespace,
 		ConflictingTelemetryWorkloadSelectors,
 		MultipleTelemetriesWithoutWorkloadSelectors,
+		NamespaceMixedDataplaneModes,
+		InvalidAmbientInteropMode,
+		AmbientInteropWaypointMissing,
+		AmbientUnsupportedResource,
+		AmbientUnsupportedPodAnnotation,
+		UnknownTrustDomain,
+		RemoteClusterCredentialExpired,
+		RemoteClusterCredentialExpiring,
 	}
 }
 
@@ -860,3 +900,86 @@ func NewMultipleTelemetriesWithoutWorkloadSelectors(r *resource.Instance, confli
 		namespace,
 	)
 }
+
+// NewNamespaceMixedDataplaneModes returns a new diag.Message based on NamespaceMixedDataplaneModes.
+func NewNamespaceMixedDataplaneModes(r *resource.Instance, namespace string, annotation string) diag.Message {
+	return diag.NewMessage(
+		NamespaceMixedDataplaneModes,
+		r,
+		namespace,
+		annotation,
+	)
+}
+
+// NewInvalidAmbientInteropMode returns a new diag.Message based on InvalidAmbientInteropMode.
+func NewInvalidAmbientInteropMode(r *resource.Instance, namespace string, annotation string, mode string, validModes []string) diag.Message {
+	return diag.NewMessage(
+		InvalidAmbientInteropMode,
+		r,
+		namespace,
+		annotation,
+		mode,
+		validModes,
+	)
+}
+
+// NewAmbientInteropWaypointMissing returns a new diag.Message based on AmbientInteropWaypointMissing.
+func NewAmbientInteropWaypointMissing(r *resource.Instance, namespace string) diag.Message {
+	return diag.NewMessage(
+		AmbientInteropWaypointMissing,
+		r,
+		namespace,
+	)
+}
+
+// NewAmbientUnsupportedResource returns a new diag.Message based on AmbientUnsupportedResource.
+func NewAmbientUnsupportedResource(r *resource.Instance, kind string, name string, namespace string) diag.Message {
+	return diag.NewMessage(
+		AmbientUnsupportedResource,
+		r,
+		kind,
+		name,
+		namespace,
+	)
+}
+
+// NewAmbientUnsupportedPodAnnotation returns a new diag.Message based on AmbientUnsupportedPodAnnotation.
+func NewAmbientUnsupportedPodAnnotation(r *resource.Instance, pod string, annotation string, namespace string) diag.Message {
+	return diag.NewMessage(
+		AmbientUnsupportedPodAnnotation,
+		r,
+		pod,
+		annotation,
+		namespace,
+	)
+}
+
+// NewUnknownTrustDomain returns a new diag.Message based on UnknownTrustDomain.
+func NewUnknownTrustDomain(r *resource.Instance, principal string, trustDomain string) diag.Message {
+	return diag.NewMessage(
+		UnknownTrustDomain,
+		r,
+		principal,
+		trustDomain,
+	)
+}
+
+// NewRemoteClusterCredentialExpired returns a new diag.Message based on RemoteClusterCredentialExpired.
+func NewRemoteClusterCredentialExpired(r *resource.Instance, cluster string, expiry string) diag.Message {
+	return diag.NewMessage(
+		RemoteClusterCredentialExpired,
+		r,
+		cluster,
+		expiry,
+	)
+}
+
+// NewRemoteClusterCredentialExpiring returns a new diag.Message based on RemoteClusterCredentialExpiring.
+func NewRemoteClusterCredentialExpiring(r *resource.Instance, cluster string, expiry string) diag.Message {
+	return diag.NewMessage(
+		RemoteClusterCredentialExpiring,
+		r,
+		cluster,
+		expiry,
+	)
+}
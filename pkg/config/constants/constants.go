@@ -97,6 +97,9 @@ const (
 	// The data name in the ConfigMap of each namespace storing the root cert of non-Kube CA.
 	CACertNamespaceConfigMapDataName = "root-cert.pem"
 
+	// The data name in the ConfigMap of each namespace storing the certificate revocation list of the CA.
+	CACRLNamespaceConfigMapDataName = "ca-crl.pem"
+
 	// PodInfoLabelsPath is the filepath that pod labels will be stored
 	// This is typically set by the downward API
 	PodInfoLabelsPath = "./etc/istio/pod/labels"
@@ -164,6 +167,108 @@ const (
 	WaypointGatewayClassName = "istio-waypoint"
 	GatewayNameLabel         = "istio.io/gateway-name"
 
+	// The annotations below configure features of the Istio configs their API has no field for yet. They are an
+	// alpha API, whose format may change between releases. Their values are validated with the configs of the kinds
+	// they apply to, as listed in pkg/config/validation, against the format documented here.
+
+	// TargetGatewayAnnotation applies an AuthorizationPolicy to the managed Kubernetes Gateway of the given name
+	// in the namespace of the policy, instead of the workloads matching its selector.
+	TargetGatewayAnnotation = "security.istio.io/target-gateway"
+	// TargetSectionNameAnnotation restricts an AuthorizationPolicy targeting a Kubernetes Gateway to the listener
+	// of the given name.
+	TargetSectionNameAnnotation = "security.istio.io/target-section-name"
+	// ExtAuthzContextExtensionsAnnotation sets the context extensions sent to the CUSTOM authorization provider
+	// for the routes of a VirtualService. The value is a JSON object of the HTTP route names, or "*" for all the
+	// routes, to the context extensions, for example {"admin": {"tier": "admin"}, "*": {"app": "shop"}}.
+	ExtAuthzContextExtensionsAnnotation = "security.istio.io/ext-authz-context-extensions"
+	// AccessLogProviderFiltersAnnotation filters the access logs of the providers of a Telemetry, in addition to the
+	// filter of its accessLogging. The value is a JSON object of the provider names to their filter, with a CEL
+	// expression and a minimum level of INFO, WARNING or ERROR, for example {"stdout": {"minLevel": "ERROR"}}.
+	AccessLogProviderFiltersAnnotation = "telemetry.istio.io/access-log-provider-filters"
+	// HistogramBucketsAnnotation overrides the bucket boundaries of the histograms of the standard Istio metrics for
+	// the workloads a Telemetry applies to. The value is a JSON object of the REQUEST_DURATION, REQUEST_SIZE or
+	// RESPONSE_SIZE metrics to their buckets, for example {"REQUEST_DURATION": [0.5, 1, 2.5, 5, 10, 25, 50, 100]}.
+	// The buckets are set on the pods when they are injected, so they only apply to the pods created afterwards.
+	HistogramBucketsAnnotation = "telemetry.istio.io/histogram-buckets"
+	// TelemetryTargetRefAnnotation makes a Telemetry apply to the routes of a VirtualService or HTTPRoute in its
+	// namespace, rather than to workloads. The value is a JSON object with the kind, VirtualService or HTTPRoute, and
+	// name of the target, and optionally the sectionName of the HTTP route of the VirtualService, for example
+	// {"kind": "VirtualService", "name": "reviews", "sectionName": "errors"}. Only the randomSamplingPercentage of the
+	// tracing of the Telemetry applies to the routes, overriding the sampling of the workloads.
+	TelemetryTargetRefAnnotation = "telemetry.istio.io/target-ref"
+	// RequestOperationsAnnotation classifies the requests of the workloads a Telemetry applies to into named operations,
+	// added as the request_operation dimension of their HTTP request metrics. The value is a JSON list of operations
+	// with a name and the method and path template of their requests, in which * matches a path segment and a trailing
+	// ** any number of segments, for example [{"name": "GetReviews", "method": "GET", "path": "/books/*/reviews"}].
+	// The first matching operation classifies the request, and the requests not matching any are "unknown".
+	RequestOperationsAnnotation = "telemetry.istio.io/request-operations"
+	// MetricExpiryAnnotation sets the duration after which the stackdriver metric time series of the workloads a
+	// Telemetry applies to are expired if they are not updated, to bound the memory of the proxies when the peers and
+	// services change often, for example "10m". It defaults to one hour. It has no effect on the prometheus provider,
+	// as the stats of Envoy are never expired.
+	MetricExpiryAnnotation = "telemetry.istio.io/metric-expiry"
+	// TraceLabelTagsAnnotation adds custom tags to the spans of the workloads a Telemetry applies to, with the values
+	// of their labels, so the traces carry organizational metadata such as the team or build of the workload. The
+	// value is a comma separated list of tag=label pairs, for example team=team,build=app.kubernetes.io/version. The
+	// tags of the labels a workload does not have are omitted.
+	TraceLabelTagsAnnotation = "telemetry.istio.io/trace-label-tags"
+	// TraceContextPropagationAnnotation selects the trace context formats the workloads a Telemetry applies to accept
+	// and propagate, so workloads using different tracing libraries can interoperate during migrations. The value is
+	// a comma separated list of W3C_TRACE_CONTEXT, B3, GRPC_BIN and CLOUD_TRACE_CONTEXT, overriding the context of the
+	// provider. It only applies to the opencensus and stackdriver providers, as the other tracers of Envoy only support
+	// their own format: the annotation is ignored, with a warning, for the zipkin, datadog, opentelemetry and other
+	// providers. As Envoy can not change them, the formats only apply to the proxies started after the change.
+	TraceContextPropagationAnnotation = "telemetry.istio.io/trace-context-propagation"
+	// RouteMetricDimensionsAnnotation adds the route matched by the requests as dimensions of the HTTP request metrics
+	// of the workloads a Telemetry applies to, so the metrics can be scoped per route rather than per service. The
+	// value is a comma separated list of route_name and virtual_service. Each route adds to the cardinality of the
+	// metrics, so it should only be set for the workloads that need it.
+	RouteMetricDimensionsAnnotation = "telemetry.istio.io/route-metric-dimensions"
+	// ClusterWeightsAnnotation distributes the traffic to the host of a DestinationRule between the clusters its
+	// endpoints reside in, for capacity-aware multi-cluster traffic management. The value is a comma separated list
+	// of cluster=weight pairs, for example cluster-1=80,cluster-2=20. The endpoints of the clusters not listed do not
+	// receive traffic. It takes precedence over the distribute setting of the locality load balancing.
+	ClusterWeightsAnnotation = "networking.istio.io/cluster-weights"
+	// ClusterFailoverAnnotation orders the clusters the endpoints of the host of a DestinationRule reside in for
+	// failover, for example cluster-1,cluster-2: the endpoints of cluster-1 receive the traffic, failing over to the
+	// endpoints of cluster-2 and then to those of the clusters not listed. Like the locality failover, it requires
+	// outlier detection, and it takes precedence over the failover settings of the locality load balancing.
+	ClusterFailoverAnnotation = "networking.istio.io/cluster-failover"
+	// ClusterSelectorAnnotation scopes a VirtualService or DestinationRule to the workloads of some clusters, so the
+	// routing changes of a multi-primary mesh can be rolled out cluster by cluster. The value is a comma separated
+	// list of cluster IDs. In the selected clusters, the config takes precedence over the configs of the same hosts
+	// without the annotation.
+	ClusterSelectorAnnotation = "networking.istio.io/cluster-selector"
+	// DynamicForwardProxyAnnotation sends the HTTP requests to a ServiceEntry of a wildcard host resolved by DNS through
+	// an Envoy dynamic forward proxy when set to true: the host of every request is resolved on demand and load balanced
+	// on its own, instead of resolving the wildcard host as a single cluster. The ServiceEntry then needs no endpoints.
+	DynamicForwardProxyAnnotation = "networking.istio.io/dynamic-forward-proxy"
+	// GRPCProbeAnnotation checks the readiness of the workloads of a WorkloadGroup with the gRPC health checking
+	// protocol, as the probe of the WorkloadGroup only supports HTTP, TCP and exec checks. The value is a JSON object
+	// with the port, and optionally the host and service of the probe, for example {"port": 50051, "service": "reviews"}.
+	// It replaces the health check method of the probe, whose delays and thresholds still apply.
+	GRPCProbeAnnotation = "networking.istio.io/grpc-probe"
+	// WorkloadEntryCleanupAnnotation controls the garbage collection of the WorkloadEntries auto-registered for a
+	// WorkloadGroup, so fleets of ephemeral VMs do not leave stale entries. The value is a JSON object with the
+	// gracePeriod the entry of a disconnected workload is kept for it to reconnect, overriding
+	// PILOT_WORKLOAD_ENTRY_GRACE_PERIOD, and the maxDisconnectDuration the entry is kept when istiod could not record
+	// the disconnection, in addition to the max connection age of istiod, for example
+	// {"gracePeriod": "30s", "maxDisconnectDuration": "10m"}.
+	WorkloadEntryCleanupAnnotation = "networking.istio.io/workload-entry-cleanup"
+	// WasmPullAnnotation controls the fetching of the module of a WasmPlugin by the proxies, for the modules served
+	// by slow or flaky artifact stores. The value is a JSON object with the timeout of the fetch, 30s by default, and
+	// the number of retries of the failed HTTP requests of the modules fetched over http or https, overriding
+	// WASM_HTTP_REQUEST_MAX_RETRIES of the proxies, for example {"timeout": "2m", "retries": 10}.
+	WasmPullAnnotation = "extensions.istio.io/wasm-pull"
+	// EnvoyFilterTargetRefAnnotation makes an EnvoyFilter apply to the proxies of a Kubernetes Gateway or the workloads
+	// of a Service in its namespace, rather than to the workloads matching its selector, so the filter follows the
+	// target when its labels change. The value is a JSON object with the group, kind and name of the target, for example
+	// {"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "ingress"} or {"kind": "Service", "name": "reviews"}.
+	EnvoyFilterTargetRefAnnotation = "networking.istio.io/target-ref"
+	// StatsHistogramBucketsAnnotation sets the bucket boundaries of the Envoy histograms of a pod. The value is a JSON
+	// object of the stat prefixes to their buckets, for example {"istiocustom.istio_request_bytes": [100, 1000, 10000]}.
+	StatsHistogramBucketsAnnotation = "sidecar.istio.io/statsHistogramBuckets"
+
 	// DataplaneMode namespace label for determining ambient mesh behavior
 	DataplaneMode        = "istio.io/dataplane-mode"
 	DataplaneModeAmbient = "ambient"
@@ -175,4 +280,15 @@ const (
 	AmbientRedirectionEnabled = "enabled"
 	// AmbientRedirectionDisabled is an opt-out, configured by user.
 	AmbientRedirectionDisabled = "disabled"
+
+	// AmbientInteropMode is a namespace annotation controlling how sidecar-injected workloads send traffic to
+	// ambient workloads in the namespace. If unset, sidecars use HBONE when they support it, and mTLS otherwise.
+	AmbientInteropMode = "ambient.istio.io/interop-mode"
+	// AmbientInteropModeHBONE requires sidecars to use HBONE. Sidecars without HBONE support cannot reach the namespace.
+	AmbientInteropModeHBONE = "hbone"
+	// AmbientInteropModeMTLS has sidecars use regular mTLS, even if they support HBONE.
+	AmbientInteropModeMTLS = "mtls"
+	// AmbientInteropModeWaypoint requires sidecars to send traffic through the destination's waypoint, so waypoint
+	// policies are always enforced. Workloads without a waypoint cannot be reached from sidecars.
+	AmbientInteropModeWaypoint = "waypoint"
 )
@@ -0,0 +1,65 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envoyfilter contains the parsing of the annotations of the EnvoyFilters and the checks of the proxy
+// versions their patches apply to.
+package envoyfilter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// GatewayGroup is the group of the Kubernetes Gateway target of an EnvoyFilter.
+	GatewayGroup = "gateway.networking.k8s.io"
+	// GatewayKind is the kind of the Kubernetes Gateway target of an EnvoyFilter.
+	GatewayKind = "Gateway"
+	// ServiceKind is the kind of the Service target of an EnvoyFilter.
+	ServiceKind = "Service"
+)
+
+// TargetRef is the object an EnvoyFilter applies to, instead of the workloads of its namespace or selector: a
+// Kubernetes Gateway or a Service in the namespace of the EnvoyFilter.
+type TargetRef struct {
+	// Group is the group of the target, gateway.networking.k8s.io for a Gateway and empty for a Service. It is
+	// required for a Gateway, which would otherwise be mistaken with the Istio Gateway of the same kind.
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
+
+// ParseTargetRef parses the value of the target-ref annotation of an EnvoyFilter.
+func ParseTargetRef(value string) (TargetRef, error) {
+	var ref TargetRef
+	if err := json.Unmarshal([]byte(value), &ref); err != nil {
+		return TargetRef{}, fmt.Errorf("invalid target ref %q: %v", value, err)
+	}
+	switch ref.Kind {
+	case GatewayKind:
+		if ref.Group != GatewayGroup {
+			return TargetRef{}, fmt.Errorf("invalid target ref %q: group of %s must be %s", value, GatewayKind, GatewayGroup)
+		}
+	case ServiceKind:
+		if ref.Group != "" {
+			return TargetRef{}, fmt.Errorf("invalid target ref %q: group of %s must be empty", value, ServiceKind)
+		}
+	default:
+		return TargetRef{}, fmt.Errorf("invalid target ref kind %q, must be %s or %s", ref.Kind, GatewayKind, ServiceKind)
+	}
+	if ref.Name == "" {
+		return TargetRef{}, fmt.Errorf("invalid target ref %q: name is required", value)
+	}
+	return ref, nil
+}
@@ -0,0 +1,43 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseTargetRef(t *testing.T) {
+	ref, err := ParseTargetRef(`{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "ingress"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ref, TargetRef{Group: GatewayGroup, Kind: GatewayKind, Name: "ingress"})
+
+	ref, err = ParseTargetRef(`{"kind": "Service", "name": "reviews"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ref, TargetRef{Kind: ServiceKind, Name: "reviews"})
+
+	for _, invalid := range []string{
+		`{"kind": "Gateway", "name": "ingress"}`,
+		`{"group": "networking.istio.io", "kind": "Gateway", "name": "ingress"}`,
+		`{"group": "apps", "kind": "Service", "name": "reviews"}`,
+		`{"kind": "Deployment", "name": "reviews"}`,
+		`{"kind": "Service"}`,
+		`not json`,
+	} {
+		_, err := ParseTargetRef(invalid)
+		assert.Error(t, err)
+	}
+}
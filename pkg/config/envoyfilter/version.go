@@ -0,0 +1,33 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"regexp"
+)
+
+// releaseVersionRegexp matches the versions of the releases, as opposed to the unknown version of the development
+// builds.
+var releaseVersionRegexp = regexp.MustCompile(`^\d+\.\d+`)
+
+// TargetsVersion returns whether the patches scoped to the proxyVersion regex apply to the proxies of the version,
+// the version of istiod, whose Envoy API the patches of the proxies of the same version are built against. It returns
+// false for known if the version is not a release version, as for development builds, the targets being unknown.
+func TargetsVersion(proxyVersion *regexp.Regexp, version string) (targets bool, known bool) {
+	if !releaseVersionRegexp.MatchString(version) {
+		return false, false
+	}
+	return proxyVersion.MatchString(version), true
+}
@@ -0,0 +1,43 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"regexp"
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestTargetsVersion(t *testing.T) {
+	cases := []struct {
+		proxyVersion string
+		version      string
+		targets      bool
+		known        bool
+	}{
+		{`^1\.18.*`, "1.18.2", true, true},
+		{`^1\.17.*`, "1.18.2", false, true},
+		{`^1\.18.*`, "1.18-dev", true, true},
+		{`^1\.18.*`, "unknown", false, false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.proxyVersion+"/"+tt.version, func(t *testing.T) {
+			targets, known := TargetsVersion(regexp.MustCompile(tt.proxyVersion), tt.version)
+			assert.Equal(t, targets, tt.targets)
+			assert.Equal(t, known, tt.known)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loadbalancing parses the load balancing settings of the DestinationRules set through annotations.
+package loadbalancing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// ClusterWeights are the weights of the traffic sent to the endpoints of each cluster.
+type ClusterWeights map[cluster.ID]uint32
+
+// ParseClusterWeights parses the value of the cluster-weights annotation of a DestinationRule, a comma separated list
+// of cluster=weight pairs, for example cluster-1=80,cluster-2=20.
+func ParseClusterWeights(value string) (ClusterWeights, error) {
+	weights := ClusterWeights{}
+	var total uint64
+	for _, w := range strings.Split(value, ",") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		id, weight, f := strings.Cut(w, "=")
+		id, weight = strings.TrimSpace(id), strings.TrimSpace(weight)
+		if !f || id == "" {
+			return nil, fmt.Errorf("invalid cluster weight %q, must be cluster=weight", w)
+		}
+		if _, dup := weights[cluster.ID(id)]; dup {
+			return nil, fmt.Errorf("duplicate weight of cluster %q", id)
+		}
+		v, err := strconv.ParseUint(weight, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight %q of cluster %q: must be a non negative integer", weight, id)
+		}
+		weights[cluster.ID(id)] = uint32(v)
+		total += v
+	}
+	if len(weights) > 0 && total == 0 {
+		return nil, fmt.Errorf("the weights of the clusters must not all be 0")
+	}
+	return weights, nil
+}
+
+// ParseClusterFailover parses the value of the cluster-failover annotation of a DestinationRule, a comma separated
+// list of the clusters in failover order, for example cluster-1,cluster-2.
+func ParseClusterFailover(value string) ([]cluster.ID, error) {
+	var order []cluster.ID
+	seen := sets.New[string]()
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if seen.InsertContains(id) {
+			return nil, fmt.Errorf("duplicate failover cluster %q", id)
+		}
+		order = append(order, cluster.ID(id))
+	}
+	return order, nil
+}
@@ -0,0 +1,59 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancing
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseClusterWeights(t *testing.T) {
+	cases := []struct {
+		value string
+		want  ClusterWeights
+		err   bool
+	}{
+		{value: "", want: ClusterWeights{}},
+		{value: "cluster-1=80, cluster-2=20", want: ClusterWeights{"cluster-1": 80, "cluster-2": 20}},
+		{value: "cluster-1=100,cluster-2=0", want: ClusterWeights{"cluster-1": 100, "cluster-2": 0}},
+		{value: "cluster-1=0", err: true},
+		{value: "cluster-1", err: true},
+		{value: "=10", err: true},
+		{value: "cluster-1=ten", err: true},
+		{value: "cluster-1=10,cluster-1=20", err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseClusterWeights(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseClusterFailover(t *testing.T) {
+	got, err := ParseClusterFailover("cluster-1, cluster-2,")
+	assert.NoError(t, err)
+	assert.Equal(t, got, []cluster.ID{"cluster-1", "cluster-2"})
+
+	_, err = ParseClusterFailover("cluster-1,cluster-1")
+	assert.Error(t, err)
+}
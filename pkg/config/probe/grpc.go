@@ -0,0 +1,50 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probe parses the readiness probes of the WorkloadGroups set through annotations.
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+)
+
+// GRPC is a readiness probe calling the gRPC health checking protocol (grpc.health.v1.Health) of the workload.
+type GRPC struct {
+	// Host is the address of the workload, the first IP address of the proxy by default.
+	Host string `json:"host,omitempty"`
+	// Port is the port of the gRPC server.
+	Port int32 `json:"port"`
+	// Service is the name of the service reported to the health server, the whole server by default.
+	Service string `json:"service,omitempty"`
+}
+
+// ParseGRPC parses the value of the grpc-probe annotation of a WorkloadGroup, a JSON object with the port, and
+// optionally the host and service of the probe, for example {"port": 50051, "service": "reviews"}.
+func ParseGRPC(value string) (*GRPC, error) {
+	p := &GRPC{}
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, err
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		return nil, fmt.Errorf("invalid port %d, must be between 1 and 65535", p.Port)
+	}
+	if p.Host != "" {
+		if _, err := netip.ParseAddr(p.Host); err != nil && p.Host != "localhost" {
+			return nil, fmt.Errorf("invalid host %q, must be an IP address or localhost", p.Host)
+		}
+	}
+	return p, nil
+}
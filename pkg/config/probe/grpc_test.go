@@ -0,0 +1,48 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseGRPC(t *testing.T) {
+	cases := []struct {
+		value string
+		want  *GRPC
+		err   bool
+	}{
+		{value: `{"port": 50051}`, want: &GRPC{Port: 50051}},
+		{value: `{"port": 50051, "service": "reviews", "host": "127.0.0.1"}`, want: &GRPC{Host: "127.0.0.1", Port: 50051, Service: "reviews"}},
+		{value: `{"port": 50051, "host": "localhost"}`, want: &GRPC{Host: "localhost", Port: 50051}},
+		{value: `{}`, err: true},
+		{value: `{"port": 70000}`, err: true},
+		{value: `{"port": 50051, "host": "example.com"}`, err: true},
+		{value: `50051`, err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseGRPC(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 
 	k8sioapiadmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	k8sioapiappsv1 "k8s.io/api/apps/v1"
+	k8sioapiautoscalingv2 "k8s.io/api/autoscaling/v2"
 	k8sioapicertificatesv1 "k8s.io/api/certificates/v1"
 	k8sioapicorev1 "k8s.io/api/core/v1"
 	k8sioapidiscoveryv1 "k8s.io/api/discovery/v1"
 	k8sioapinetworkingv1 "k8s.io/api/networking/v1"
+	k8sioapipolicyv1 "k8s.io/api/policy/v1"
 	k8sioapiextensionsapiserverpkgapisapiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	sigsk8siogatewayapiapisv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	sigsk8siogatewayapiapisv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -95,6 +97,21 @@ var (
 		ValidateProto: validation.EmptyValidate,
 	}.MustBuild()
 
+	DaemonSet = resource.Builder{
+		Identifier:    "DaemonSet",
+		Group:         "apps",
+		Kind:          "DaemonSet",
+		Plural:        "daemonsets",
+		Version:       "v1",
+		Proto:         "k8s.io.api.apps.v1.DaemonSetSpec",
+		ReflectType:   reflect.TypeOf(&k8sioapiappsv1.DaemonSetSpec{}).Elem(),
+		ProtoPackage:  "k8s.io/api/apps/v1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       true,
+		ValidateProto: validation.EmptyValidate,
+	}.MustBuild()
+
 	Deployment = resource.Builder{
 		Identifier:    "Deployment",
 		Group:         "apps",
@@ -242,6 +259,21 @@ var (
 		ValidateProto: validation.ValidateHTTPRoute,
 	}.MustBuild()
 
+	HorizontalPodAutoscaler = resource.Builder{
+		Identifier:    "HorizontalPodAutoscaler",
+		Group:         "autoscaling",
+		Kind:          "HorizontalPodAutoscaler",
+		Plural:        "horizontalpodautoscalers",
+		Version:       "v2",
+		Proto:         "k8s.io.api.autoscaling.v2.HorizontalPodAutoscalerSpec",
+		ReflectType:   reflect.TypeOf(&k8sioapiautoscalingv2.HorizontalPodAutoscalerSpec{}).Elem(),
+		ProtoPackage:  "k8s.io/api/autoscaling/v2",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       true,
+		ValidateProto: validation.EmptyValidate,
+	}.MustBuild()
+
 	Ingress = resource.Builder{
 		Identifier: "Ingress",
 		Group:      "networking.k8s.io",
@@ -350,6 +382,21 @@ var (
 		ValidateProto: validation.EmptyValidate,
 	}.MustBuild()
 
+	NetworkPolicy = resource.Builder{
+		Identifier:    "NetworkPolicy",
+		Group:         "networking.k8s.io",
+		Kind:          "NetworkPolicy",
+		Plural:        "networkpolicies",
+		Version:       "v1",
+		Proto:         "k8s.io.api.networking.v1.NetworkPolicySpec",
+		ReflectType:   reflect.TypeOf(&k8sioapinetworkingv1.NetworkPolicySpec{}).Elem(),
+		ProtoPackage:  "k8s.io/api/networking/v1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       true,
+		ValidateProto: validation.EmptyValidate,
+	}.MustBuild()
+
 	Node = resource.Builder{
 		Identifier:    "Node",
 		Group:         "",
@@ -395,6 +442,21 @@ var (
 		ValidateProto: validation.EmptyValidate,
 	}.MustBuild()
 
+	PodDisruptionBudget = resource.Builder{
+		Identifier:    "PodDisruptionBudget",
+		Group:         "policy",
+		Kind:          "PodDisruptionBudget",
+		Plural:        "poddisruptionbudgets",
+		Version:       "v1",
+		Proto:         "k8s.io.api.policy.v1.PodDisruptionBudgetSpec",
+		ReflectType:   reflect.TypeOf(&k8sioapipolicyv1.PodDisruptionBudgetSpec{}).Elem(),
+		ProtoPackage:  "k8s.io/api/policy/v1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       true,
+		ValidateProto: validation.EmptyValidate,
+	}.MustBuild()
+
 	ProxyConfig = resource.Builder{
 		Identifier: "ProxyConfig",
 		Group:      "networking.istio.io",
@@ -524,6 +586,21 @@ var (
 		ValidateProto: validation.ValidateSidecar,
 	}.MustBuild()
 
+	StatefulSet = resource.Builder{
+		Identifier:    "StatefulSet",
+		Group:         "apps",
+		Kind:          "StatefulSet",
+		Plural:        "statefulsets",
+		Version:       "v1",
+		Proto:         "k8s.io.api.apps.v1.StatefulSetSpec",
+		ReflectType:   reflect.TypeOf(&k8sioapiappsv1.StatefulSetSpec{}).Elem(),
+		ProtoPackage:  "k8s.io/api/apps/v1",
+		ClusterScoped: false,
+		Synthetic:     false,
+		Builtin:       true,
+		ValidateProto: validation.EmptyValidate,
+	}.MustBuild()
+
 	TCPRoute = resource.Builder{
 		Identifier: "TCPRoute",
 		Group:      "gateway.networking.k8s.io",
@@ -674,6 +751,7 @@ var (
 		MustAdd(CertificateSigningRequest).
 		MustAdd(ConfigMap).
 		MustAdd(CustomResourceDefinition).
+		MustAdd(DaemonSet).
 		MustAdd(Deployment).
 		MustAdd(DestinationRule).
 		MustAdd(EndpointSlice).
@@ -683,6 +761,7 @@ var (
 		MustAdd(Gateway).
 		MustAdd(GatewayClass).
 		MustAdd(HTTPRoute).
+		MustAdd(HorizontalPodAutoscaler).
 		MustAdd(Ingress).
 		MustAdd(IngressClass).
 		MustAdd(KubernetesGateway).
@@ -690,9 +769,11 @@ var (
 		MustAdd(MeshNetworks).
 		MustAdd(MutatingWebhookConfiguration).
 		MustAdd(Namespace).
+		MustAdd(NetworkPolicy).
 		MustAdd(Node).
 		MustAdd(PeerAuthentication).
 		MustAdd(Pod).
+		MustAdd(PodDisruptionBudget).
 		MustAdd(ProxyConfig).
 		MustAdd(ReferenceGrant).
 		MustAdd(RequestAuthentication).
@@ -701,6 +782,7 @@ var (
 		MustAdd(ServiceAccount).
 		MustAdd(ServiceEntry).
 		MustAdd(Sidecar).
+		MustAdd(StatefulSet).
 		MustAdd(TCPRoute).
 		MustAdd(TLSRoute).
 		MustAdd(Telemetry).
@@ -717,23 +799,28 @@ var (
 		MustAdd(CertificateSigningRequest).
 		MustAdd(ConfigMap).
 		MustAdd(CustomResourceDefinition).
+		MustAdd(DaemonSet).
 		MustAdd(Deployment).
 		MustAdd(EndpointSlice).
 		MustAdd(Endpoints).
 		MustAdd(GRPCRoute).
 		MustAdd(GatewayClass).
 		MustAdd(HTTPRoute).
+		MustAdd(HorizontalPodAutoscaler).
 		MustAdd(Ingress).
 		MustAdd(IngressClass).
 		MustAdd(KubernetesGateway).
 		MustAdd(MutatingWebhookConfiguration).
 		MustAdd(Namespace).
+		MustAdd(NetworkPolicy).
 		MustAdd(Node).
 		MustAdd(Pod).
+		MustAdd(PodDisruptionBudget).
 		MustAdd(ReferenceGrant).
 		MustAdd(Secret).
 		MustAdd(Service).
 		MustAdd(ServiceAccount).
+		MustAdd(StatefulSet).
 		MustAdd(TCPRoute).
 		MustAdd(TLSRoute).
 		MustAdd(UDPRoute).
@@ -15,6 +15,7 @@ var (
 	CertificateSigningRequest      = config.GroupVersionKind{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}
 	ConfigMap                      = config.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}
 	CustomResourceDefinition       = config.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+	DaemonSet                      = config.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}
 	Deployment                     = config.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
 	DestinationRule                = config.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "DestinationRule"}
 	EndpointSlice                  = config.GroupVersionKind{Group: "", Version: "v1", Kind: "EndpointSlice"}
@@ -24,6 +25,7 @@ var (
 	Gateway                        = config.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Gateway"}
 	GatewayClass                   = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "GatewayClass"}
 	HTTPRoute                      = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"}
+	HorizontalPodAutoscaler        = config.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}
 	Ingress                        = config.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
 	IngressClass                   = config.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}
 	KubernetesGateway              = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "Gateway"}
@@ -31,9 +33,11 @@ var (
 	MeshNetworks                   = config.GroupVersionKind{Group: "", Version: "v1alpha1", Kind: "MeshNetworks"}
 	MutatingWebhookConfiguration   = config.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}
 	Namespace                      = config.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"}
+	NetworkPolicy                  = config.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}
 	Node                           = config.GroupVersionKind{Group: "", Version: "v1", Kind: "Node"}
 	PeerAuthentication             = config.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "PeerAuthentication"}
 	Pod                            = config.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
+	PodDisruptionBudget            = config.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
 	ProxyConfig                    = config.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "ProxyConfig"}
 	ReferenceGrant                 = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "ReferenceGrant"}
 	RequestAuthentication          = config.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "RequestAuthentication"}
@@ -42,6 +46,7 @@ var (
 	ServiceAccount                 = config.GroupVersionKind{Group: "", Version: "v1", Kind: "ServiceAccount"}
 	ServiceEntry                   = config.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "ServiceEntry"}
 	Sidecar                        = config.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Sidecar"}
+	StatefulSet                    = config.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
 	TCPRoute                       = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
 	TLSRoute                       = config.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TLSRoute"}
 	Telemetry                      = config.GroupVersionKind{Group: "telemetry.istio.io", Version: "v1alpha1", Kind: "Telemetry"}
@@ -64,6 +69,8 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.ConfigMap, true
 	case CustomResourceDefinition:
 		return gvr.CustomResourceDefinition, true
+	case DaemonSet:
+		return gvr.DaemonSet, true
 	case Deployment:
 		return gvr.Deployment, true
 	case DestinationRule:
@@ -82,6 +89,8 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.GatewayClass, true
 	case HTTPRoute:
 		return gvr.HTTPRoute, true
+	case HorizontalPodAutoscaler:
+		return gvr.HorizontalPodAutoscaler, true
 	case Ingress:
 		return gvr.Ingress, true
 	case IngressClass:
@@ -96,12 +105,16 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.MutatingWebhookConfiguration, true
 	case Namespace:
 		return gvr.Namespace, true
+	case NetworkPolicy:
+		return gvr.NetworkPolicy, true
 	case Node:
 		return gvr.Node, true
 	case PeerAuthentication:
 		return gvr.PeerAuthentication, true
 	case Pod:
 		return gvr.Pod, true
+	case PodDisruptionBudget:
+		return gvr.PodDisruptionBudget, true
 	case ProxyConfig:
 		return gvr.ProxyConfig, true
 	case ReferenceGrant:
@@ -118,6 +131,8 @@ func ToGVR(g config.GroupVersionKind) (schema.GroupVersionResource, bool) {
 		return gvr.ServiceEntry, true
 	case Sidecar:
 		return gvr.Sidecar, true
+	case StatefulSet:
+		return gvr.StatefulSet, true
 	case TCPRoute:
 		return gvr.TCPRoute, true
 	case TLSRoute:
@@ -162,6 +177,8 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return ConfigMap, true
 	case gvr.CustomResourceDefinition:
 		return CustomResourceDefinition, true
+	case gvr.DaemonSet:
+		return DaemonSet, true
 	case gvr.Deployment:
 		return Deployment, true
 	case gvr.DestinationRule:
@@ -180,6 +197,8 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return GatewayClass, true
 	case gvr.HTTPRoute:
 		return HTTPRoute, true
+	case gvr.HorizontalPodAutoscaler:
+		return HorizontalPodAutoscaler, true
 	case gvr.Ingress:
 		return Ingress, true
 	case gvr.IngressClass:
@@ -194,12 +213,16 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return MutatingWebhookConfiguration, true
 	case gvr.Namespace:
 		return Namespace, true
+	case gvr.NetworkPolicy:
+		return NetworkPolicy, true
 	case gvr.Node:
 		return Node, true
 	case gvr.PeerAuthentication:
 		return PeerAuthentication, true
 	case gvr.Pod:
 		return Pod, true
+	case gvr.PodDisruptionBudget:
+		return PodDisruptionBudget, true
 	case gvr.ProxyConfig:
 		return ProxyConfig, true
 	case gvr.ReferenceGrant:
@@ -216,6 +239,8 @@ func FromGVR(g schema.GroupVersionResource) (config.GroupVersionKind, bool) {
 		return ServiceEntry, true
 	case gvr.Sidecar:
 		return Sidecar, true
+	case gvr.StatefulSet:
+		return StatefulSet, true
 	case gvr.TCPRoute:
 		return TCPRoute, true
 	case gvr.TLSRoute:
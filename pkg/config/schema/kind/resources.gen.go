@@ -14,6 +14,7 @@ const (
 	CertificateSigningRequest
 	ConfigMap
 	CustomResourceDefinition
+	DaemonSet
 	Deployment
 	DestinationRule
 	EndpointSlice
@@ -23,6 +24,7 @@ const (
 	Gateway
 	GatewayClass
 	HTTPRoute
+	HorizontalPodAutoscaler
 	Ingress
 	IngressClass
 	KubernetesGateway
@@ -30,9 +32,11 @@ const (
 	MeshNetworks
 	MutatingWebhookConfiguration
 	Namespace
+	NetworkPolicy
 	Node
 	PeerAuthentication
 	Pod
+	PodDisruptionBudget
 	ProxyConfig
 	ReferenceGrant
 	RequestAuthentication
@@ -41,6 +45,7 @@ const (
 	ServiceAccount
 	ServiceEntry
 	Sidecar
+	StatefulSet
 	TCPRoute
 	TLSRoute
 	Telemetry
@@ -64,6 +69,8 @@ func (k Kind) String() string {
 		return "ConfigMap"
 	case CustomResourceDefinition:
 		return "CustomResourceDefinition"
+	case DaemonSet:
+		return "DaemonSet"
 	case Deployment:
 		return "Deployment"
 	case DestinationRule:
@@ -82,6 +89,8 @@ func (k Kind) String() string {
 		return "GatewayClass"
 	case HTTPRoute:
 		return "HTTPRoute"
+	case HorizontalPodAutoscaler:
+		return "HorizontalPodAutoscaler"
 	case Ingress:
 		return "Ingress"
 	case IngressClass:
@@ -96,12 +105,16 @@ func (k Kind) String() string {
 		return "MutatingWebhookConfiguration"
 	case Namespace:
 		return "Namespace"
+	case NetworkPolicy:
+		return "NetworkPolicy"
 	case Node:
 		return "Node"
 	case PeerAuthentication:
 		return "PeerAuthentication"
 	case Pod:
 		return "Pod"
+	case PodDisruptionBudget:
+		return "PodDisruptionBudget"
 	case ProxyConfig:
 		return "ProxyConfig"
 	case ReferenceGrant:
@@ -118,6 +131,8 @@ func (k Kind) String() string {
 		return "ServiceEntry"
 	case Sidecar:
 		return "Sidecar"
+	case StatefulSet:
+		return "StatefulSet"
 	case TCPRoute:
 		return "TCPRoute"
 	case TLSRoute:
@@ -151,6 +166,8 @@ func MustFromGVK(g config.GroupVersionKind) Kind {
 		return ConfigMap
 	case gvk.CustomResourceDefinition:
 		return CustomResourceDefinition
+	case gvk.DaemonSet:
+		return DaemonSet
 	case gvk.Deployment:
 		return Deployment
 	case gvk.DestinationRule:
@@ -169,6 +186,8 @@ func MustFromGVK(g config.GroupVersionKind) Kind {
 		return GatewayClass
 	case gvk.HTTPRoute:
 		return HTTPRoute
+	case gvk.HorizontalPodAutoscaler:
+		return HorizontalPodAutoscaler
 	case gvk.Ingress:
 		return Ingress
 	case gvk.IngressClass:
@@ -183,12 +202,16 @@ func MustFromGVK(g config.GroupVersionKind) Kind {
 		return MutatingWebhookConfiguration
 	case gvk.Namespace:
 		return Namespace
+	case gvk.NetworkPolicy:
+		return NetworkPolicy
 	case gvk.Node:
 		return Node
 	case gvk.PeerAuthentication:
 		return PeerAuthentication
 	case gvk.Pod:
 		return Pod
+	case gvk.PodDisruptionBudget:
+		return PodDisruptionBudget
 	case gvk.ProxyConfig:
 		return ProxyConfig
 	case gvk.ReferenceGrant:
@@ -205,6 +228,8 @@ func MustFromGVK(g config.GroupVersionKind) Kind {
 		return ServiceEntry
 	case gvk.Sidecar:
 		return Sidecar
+	case gvk.StatefulSet:
+		return StatefulSet
 	case gvk.TCPRoute:
 		return TCPRoute
 	case gvk.TLSRoute:
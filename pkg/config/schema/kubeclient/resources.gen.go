@@ -10,10 +10,12 @@ import (
 
 	k8sioapiadmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	k8sioapiappsv1 "k8s.io/api/apps/v1"
+	k8sioapiautoscalingv2 "k8s.io/api/autoscaling/v2"
 	k8sioapicertificatesv1 "k8s.io/api/certificates/v1"
 	k8sioapicorev1 "k8s.io/api/core/v1"
 	k8sioapidiscoveryv1 "k8s.io/api/discovery/v1"
 	k8sioapinetworkingv1 "k8s.io/api/networking/v1"
+	k8sioapipolicyv1 "k8s.io/api/policy/v1"
 	k8sioapiextensionsapiserverpkgapisapiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kubeext "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	kubeextinformer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -83,6 +85,8 @@ func GetClient[T runtime.Object](c ClientGetter, namespace string) ktypes.WriteA
 		return c.Kube().CoreV1().ConfigMaps(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapiextensionsapiserverpkgapisapiextensionsv1.CustomResourceDefinition:
 		return c.Ext().ApiextensionsV1().CustomResourceDefinitions().(ktypes.WriteAPI[T])
+	case *k8sioapiappsv1.DaemonSet:
+		return c.Kube().AppsV1().DaemonSets(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapiappsv1.Deployment:
 		return c.Kube().AppsV1().Deployments(namespace).(ktypes.WriteAPI[T])
 	case *apiistioioapinetworkingv1alpha3.DestinationRule:
@@ -101,6 +105,8 @@ func GetClient[T runtime.Object](c ClientGetter, namespace string) ktypes.WriteA
 		return c.GatewayAPI().GatewayV1beta1().GatewayClasses().(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1beta1.HTTPRoute:
 		return c.GatewayAPI().GatewayV1beta1().HTTPRoutes(namespace).(ktypes.WriteAPI[T])
+	case *k8sioapiautoscalingv2.HorizontalPodAutoscaler:
+		return c.Kube().AutoscalingV2().HorizontalPodAutoscalers(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapinetworkingv1.Ingress:
 		return c.Kube().NetworkingV1().Ingresses(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapinetworkingv1.IngressClass:
@@ -111,12 +117,16 @@ func GetClient[T runtime.Object](c ClientGetter, namespace string) ktypes.WriteA
 		return c.Kube().AdmissionregistrationV1().MutatingWebhookConfigurations().(ktypes.WriteAPI[T])
 	case *k8sioapicorev1.Namespace:
 		return c.Kube().CoreV1().Namespaces().(ktypes.WriteAPI[T])
+	case *k8sioapinetworkingv1.NetworkPolicy:
+		return c.Kube().NetworkingV1().NetworkPolicies(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapicorev1.Node:
 		return c.Kube().CoreV1().Nodes().(ktypes.WriteAPI[T])
 	case *apiistioioapisecurityv1beta1.PeerAuthentication:
 		return c.Istio().SecurityV1beta1().PeerAuthentications(namespace).(ktypes.WriteAPI[T])
 	case *k8sioapicorev1.Pod:
 		return c.Kube().CoreV1().Pods(namespace).(ktypes.WriteAPI[T])
+	case *k8sioapipolicyv1.PodDisruptionBudget:
+		return c.Kube().PolicyV1().PodDisruptionBudgets(namespace).(ktypes.WriteAPI[T])
 	case *apiistioioapinetworkingv1beta1.ProxyConfig:
 		return c.Istio().NetworkingV1beta1().ProxyConfigs(namespace).(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1alpha2.ReferenceGrant:
@@ -133,6 +143,8 @@ func GetClient[T runtime.Object](c ClientGetter, namespace string) ktypes.WriteA
 		return c.Istio().NetworkingV1alpha3().ServiceEntries(namespace).(ktypes.WriteAPI[T])
 	case *apiistioioapinetworkingv1alpha3.Sidecar:
 		return c.Istio().NetworkingV1alpha3().Sidecars(namespace).(ktypes.WriteAPI[T])
+	case *k8sioapiappsv1.StatefulSet:
+		return c.Kube().AppsV1().StatefulSets(namespace).(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1alpha2.TCPRoute:
 		return c.GatewayAPI().GatewayV1alpha2().TCPRoutes(namespace).(ktypes.WriteAPI[T])
 	case *sigsk8siogatewayapiapisv1alpha2.TLSRoute:
@@ -189,6 +201,13 @@ func GetInformerFiltered[T runtime.Object](c ClientGetter, opts ktypes.InformerO
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Ext().ApiextensionsV1().CustomResourceDefinitions().Watch(context.Background(), options)
 		}
+	case *k8sioapiappsv1.DaemonSet:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Kube().AppsV1().DaemonSets("").List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Kube().AppsV1().DaemonSets("").Watch(context.Background(), options)
+		}
 	case *k8sioapiappsv1.Deployment:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.Kube().AppsV1().Deployments("").List(context.Background(), options)
@@ -252,6 +271,13 @@ func GetInformerFiltered[T runtime.Object](c ClientGetter, opts ktypes.InformerO
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.GatewayAPI().GatewayV1beta1().HTTPRoutes("").Watch(context.Background(), options)
 		}
+	case *k8sioapiautoscalingv2.HorizontalPodAutoscaler:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Kube().AutoscalingV2().HorizontalPodAutoscalers("").List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Kube().AutoscalingV2().HorizontalPodAutoscalers("").Watch(context.Background(), options)
+		}
 	case *k8sioapinetworkingv1.Ingress:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.Kube().NetworkingV1().Ingresses("").List(context.Background(), options)
@@ -287,6 +313,13 @@ func GetInformerFiltered[T runtime.Object](c ClientGetter, opts ktypes.InformerO
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Kube().CoreV1().Namespaces().Watch(context.Background(), options)
 		}
+	case *k8sioapinetworkingv1.NetworkPolicy:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Kube().NetworkingV1().NetworkPolicies("").List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Kube().NetworkingV1().NetworkPolicies("").Watch(context.Background(), options)
+		}
 	case *k8sioapicorev1.Node:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.Kube().CoreV1().Nodes().List(context.Background(), options)
@@ -308,6 +341,13 @@ func GetInformerFiltered[T runtime.Object](c ClientGetter, opts ktypes.InformerO
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Kube().CoreV1().Pods("").Watch(context.Background(), options)
 		}
+	case *k8sioapipolicyv1.PodDisruptionBudget:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Kube().PolicyV1().PodDisruptionBudgets("").List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Kube().PolicyV1().PodDisruptionBudgets("").Watch(context.Background(), options)
+		}
 	case *apiistioioapinetworkingv1beta1.ProxyConfig:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.Istio().NetworkingV1beta1().ProxyConfigs("").List(context.Background(), options)
@@ -364,6 +404,13 @@ func GetInformerFiltered[T runtime.Object](c ClientGetter, opts ktypes.InformerO
 		w = func(options metav1.ListOptions) (watch.Interface, error) {
 			return c.Istio().NetworkingV1alpha3().Sidecars("").Watch(context.Background(), options)
 		}
+	case *k8sioapiappsv1.StatefulSet:
+		l = func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.Kube().AppsV1().StatefulSets("").List(context.Background(), options)
+		}
+		w = func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.Kube().AppsV1().StatefulSets("").Watch(context.Background(), options)
+		}
 	case *sigsk8siogatewayapiapisv1alpha2.TCPRoute:
 		l = func(options metav1.ListOptions) (runtime.Object, error) {
 			return c.GatewayAPI().GatewayV1alpha2().TCPRoutes("").List(context.Background(), options)
@@ -461,6 +508,8 @@ func GetInformer[T runtime.Object](c ClientGetter) cache.SharedIndexInformer {
 		return c.KubeInformer().Core().V1().ConfigMaps().Informer()
 	case *k8sioapiextensionsapiserverpkgapisapiextensionsv1.CustomResourceDefinition:
 		return c.ExtInformer().Apiextensions().V1().CustomResourceDefinitions().Informer()
+	case *k8sioapiappsv1.DaemonSet:
+		return c.KubeInformer().Apps().V1().DaemonSets().Informer()
 	case *k8sioapiappsv1.Deployment:
 		return c.KubeInformer().Apps().V1().Deployments().Informer()
 	case *apiistioioapinetworkingv1alpha3.DestinationRule:
@@ -479,6 +528,8 @@ func GetInformer[T runtime.Object](c ClientGetter) cache.SharedIndexInformer {
 		return c.GatewayAPIInformer().Gateway().V1beta1().GatewayClasses().Informer()
 	case *sigsk8siogatewayapiapisv1beta1.HTTPRoute:
 		return c.GatewayAPIInformer().Gateway().V1beta1().HTTPRoutes().Informer()
+	case *k8sioapiautoscalingv2.HorizontalPodAutoscaler:
+		return c.KubeInformer().Autoscaling().V2().HorizontalPodAutoscalers().Informer()
 	case *k8sioapinetworkingv1.Ingress:
 		return c.KubeInformer().Networking().V1().Ingresses().Informer()
 	case *k8sioapinetworkingv1.IngressClass:
@@ -489,12 +540,16 @@ func GetInformer[T runtime.Object](c ClientGetter) cache.SharedIndexInformer {
 		return c.KubeInformer().Admissionregistration().V1().MutatingWebhookConfigurations().Informer()
 	case *k8sioapicorev1.Namespace:
 		return c.KubeInformer().Core().V1().Namespaces().Informer()
+	case *k8sioapinetworkingv1.NetworkPolicy:
+		return c.KubeInformer().Networking().V1().NetworkPolicies().Informer()
 	case *k8sioapicorev1.Node:
 		return c.KubeInformer().Core().V1().Nodes().Informer()
 	case *apiistioioapisecurityv1beta1.PeerAuthentication:
 		return c.IstioInformer().Security().V1beta1().PeerAuthentications().Informer()
 	case *k8sioapicorev1.Pod:
 		return c.KubeInformer().Core().V1().Pods().Informer()
+	case *k8sioapipolicyv1.PodDisruptionBudget:
+		return c.KubeInformer().Policy().V1().PodDisruptionBudgets().Informer()
 	case *apiistioioapinetworkingv1beta1.ProxyConfig:
 		return c.IstioInformer().Networking().V1beta1().ProxyConfigs().Informer()
 	case *sigsk8siogatewayapiapisv1alpha2.ReferenceGrant:
@@ -511,6 +566,8 @@ func GetInformer[T runtime.Object](c ClientGetter) cache.SharedIndexInformer {
 		return c.IstioInformer().Networking().V1alpha3().ServiceEntries().Informer()
 	case *apiistioioapinetworkingv1alpha3.Sidecar:
 		return c.IstioInformer().Networking().V1alpha3().Sidecars().Informer()
+	case *k8sioapiappsv1.StatefulSet:
+		return c.KubeInformer().Apps().V1().StatefulSets().Informer()
 	case *sigsk8siogatewayapiapisv1alpha2.TCPRoute:
 		return c.GatewayAPIInformer().Gateway().V1alpha2().TCPRoutes().Informer()
 	case *sigsk8siogatewayapiapisv1alpha2.TLSRoute:
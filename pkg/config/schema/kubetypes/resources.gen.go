@@ -8,10 +8,12 @@ import (
 
 	k8sioapiadmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	k8sioapiappsv1 "k8s.io/api/apps/v1"
+	k8sioapiautoscalingv2 "k8s.io/api/autoscaling/v2"
 	k8sioapicertificatesv1 "k8s.io/api/certificates/v1"
 	k8sioapicorev1 "k8s.io/api/core/v1"
 	k8sioapidiscoveryv1 "k8s.io/api/discovery/v1"
 	k8sioapinetworkingv1 "k8s.io/api/networking/v1"
+	k8sioapipolicyv1 "k8s.io/api/policy/v1"
 	k8sioapiextensionsapiserverpkgapisapiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	sigsk8siogatewayapiapisv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -38,6 +40,8 @@ func GetGVK[T runtime.Object]() config.GroupVersionKind {
 		return gvk.ConfigMap
 	case *k8sioapiextensionsapiserverpkgapisapiextensionsv1.CustomResourceDefinition:
 		return gvk.CustomResourceDefinition
+	case *k8sioapiappsv1.DaemonSet:
+		return gvk.DaemonSet
 	case *k8sioapiappsv1.Deployment:
 		return gvk.Deployment
 	case *istioioapinetworkingv1alpha3.DestinationRule:
@@ -56,6 +60,8 @@ func GetGVK[T runtime.Object]() config.GroupVersionKind {
 		return gvk.GatewayClass
 	case *sigsk8siogatewayapiapisv1beta1.HTTPRoute:
 		return gvk.HTTPRoute
+	case *k8sioapiautoscalingv2.HorizontalPodAutoscaler:
+		return gvk.HorizontalPodAutoscaler
 	case *k8sioapinetworkingv1.Ingress:
 		return gvk.Ingress
 	case *k8sioapinetworkingv1.IngressClass:
@@ -70,12 +76,16 @@ func GetGVK[T runtime.Object]() config.GroupVersionKind {
 		return gvk.MutatingWebhookConfiguration
 	case *k8sioapicorev1.Namespace:
 		return gvk.Namespace
+	case *k8sioapinetworkingv1.NetworkPolicy:
+		return gvk.NetworkPolicy
 	case *k8sioapicorev1.Node:
 		return gvk.Node
 	case *istioioapisecurityv1beta1.PeerAuthentication:
 		return gvk.PeerAuthentication
 	case *k8sioapicorev1.Pod:
 		return gvk.Pod
+	case *k8sioapipolicyv1.PodDisruptionBudget:
+		return gvk.PodDisruptionBudget
 	case *istioioapinetworkingv1beta1.ProxyConfig:
 		return gvk.ProxyConfig
 	case *sigsk8siogatewayapiapisv1alpha2.ReferenceGrant:
@@ -92,6 +102,8 @@ func GetGVK[T runtime.Object]() config.GroupVersionKind {
 		return gvk.ServiceEntry
 	case *istioioapinetworkingv1alpha3.Sidecar:
 		return gvk.Sidecar
+	case *k8sioapiappsv1.StatefulSet:
+		return gvk.StatefulSet
 	case *sigsk8siogatewayapiapisv1alpha2.TCPRoute:
 		return gvk.TCPRoute
 	case *sigsk8siogatewayapiapisv1alpha2.TLSRoute:
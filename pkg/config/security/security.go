@@ -15,6 +15,7 @@
 package security
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/netip"
 	"net/url"
@@ -55,8 +56,110 @@ const (
 	attrDestUser         = "destination.user"       // service account, e.g. "bookinfo-productpage".
 	attrConnSNI          = "connection.sni"         // server name indication, e.g. "www.example.com".
 	attrExperimental     = "experimental.envoy.filters."
+	attrClientCertSub    = "connection.client_cert.subject" // subject DN of the client certificate, e.g. "CN=client,O=example".
+	attrClientCertSAN    = "connection.client_cert.san"     // first URI or DNS SAN of the client certificate.
+	// The issuer of the client certificate is not an attribute of Envoy, neither in the RBAC principals nor in the CEL
+	// attributes of the connection, so it can not be matched by the RBAC filter. The issuers are instead restricted
+	// by the caCertificates the gateway server validates the client certificates with.
+	attrClientCertIssuer = "connection.client_cert.issuer"
+
+	// AttrRequestCEL is the attribute for CEL expressions evaluated against the Envoy request attributes. The JWT
+	// claims are lists in the istio_authn filter metadata, e.g.
+	// "request.headers['x-tenant'] in metadata.filter_metadata['istio_authn']['request.auth.claims']['tenant']".
+	AttrRequestCEL = "request.cel"
 )
 
+// JWTClaimHeaderPrefix is the special header name prefix used in routes for matching on the claims of the
+// validated JWT, e.g. "@request.auth.claims.group" or "@request.auth.claims[group]".
+const JWTClaimHeaderPrefix = "@" + attrRequestClaims
+
+// IsJWTClaimHeader returns true if the header name used in a route match refers to a JWT claim.
+func IsJWTClaimHeader(name string) bool {
+	_, ok := jwtClaimHeaderPath(name)
+	return ok
+}
+
+// ParseJWTClaimHeader returns the claim path referred to by a JWT claim header name used in a route match.
+// Nested claims are separated with ".", e.g. "@request.auth.claims.group.id", or surrounded by brackets,
+// e.g. "@request.auth.claims[group][id]", which also allows claim names containing ".".
+func ParseJWTClaimHeader(name string) ([]string, error) {
+	path, ok := jwtClaimHeaderPath(name)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a JWT claim header, expecting %s.<CLAIM> or %s[<CLAIM>]",
+			name, JWTClaimHeaderPrefix, JWTClaimHeaderPrefix)
+	}
+	return ParseJWTClaimPath(path)
+}
+
+func jwtClaimHeaderPath(name string) (string, bool) {
+	if !strings.HasPrefix(strings.ToLower(name), JWTClaimHeaderPrefix) {
+		return "", false
+	}
+	path := name[len(JWTClaimHeaderPrefix):]
+	if strings.HasPrefix(path, ".") {
+		return path[1:], true
+	}
+	return path, strings.HasPrefix(path, "[")
+}
+
+// ExtAuthzContextExtensions are the context extensions sent to the CUSTOM authorization provider, keyed by the
+// name of the HTTP route they apply to, or "*" for all the routes.
+type ExtAuthzContextExtensions map[string]map[string]string
+
+// ParseExtAuthzContextExtensions parses the value of the ext-authz-context-extensions annotation of a VirtualService.
+func ParseExtAuthzContextExtensions(value string) (ExtAuthzContextExtensions, error) {
+	var ext ExtAuthzContextExtensions
+	if err := json.Unmarshal([]byte(value), &ext); err != nil {
+		return nil, fmt.Errorf("invalid context extensions %q: %v", value, err)
+	}
+	return ext, nil
+}
+
+// ForRoute returns the context extensions of the route of the given name, which take precedence over the ones
+// of all the routes.
+func (e ExtAuthzContextExtensions) ForRoute(name string) map[string]string {
+	if len(e["*"]) == 0 && len(e[name]) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(e["*"])+len(e[name]))
+	for k, v := range e["*"] {
+		out[k] = v
+	}
+	if name != "" {
+		for k, v := range e[name] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// ParseJWTClaimPath parses the path of a possibly nested JWT claim. The claim names are either separated
+// with ".", e.g. "group.id", or each surrounded by brackets, e.g. "[group][id]".
+func ParseJWTClaimPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("claim must not be empty")
+	}
+	if !strings.HasPrefix(path, "[") {
+		claims := strings.Split(path, ".")
+		for _, claim := range claims {
+			if claim == "" || strings.ContainsAny(claim, "[]") {
+				return nil, fmt.Errorf("invalid claim %q, expecting <NAME>[.<NAME>...]", path)
+			}
+		}
+		return claims, nil
+	}
+	var claims []string
+	for rest := path; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end == -1 || end == 1 || strings.Contains(rest[1:end], "[") {
+			return nil, fmt.Errorf("invalid claim %q, expecting [<NAME>][<NAME>...]", path)
+		}
+		claims = append(claims, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return claims, nil
+}
+
 // ParseJwksURI parses the input URI and returns the corresponding hostname, port, and whether SSL is used.
 // URI must start with "http://" or "https://", which corresponding to "http" or "https" scheme.
 // Port number is extracted from URI if available (i.e from postfix :<port>, eg. ":80"), or assigned
@@ -123,8 +226,12 @@ func ValidateAttribute(key string, values []string) error {
 	case isEqual(key, attrDestPort):
 		return ValidatePorts(values)
 	case isEqual(key, attrConnSNI):
+	case isEqual(key, AttrRequestCEL):
+	case isEqual(key, attrClientCertSub, attrClientCertSAN):
 	case hasPrefix(key, attrExperimental):
 		return validateMapKey(key)
+	case isEqual(key, attrClientCertIssuer):
+		return fmt.Errorf("attribute %s is not supported: restrict the issuers with the caCertificates of the gateway server", key)
 	case isEqual(key, attrDestNamespace):
 		return fmt.Errorf("attribute %s is replaced by the metadata.namespace", key)
 	case hasPrefix(key, attrDestLabel):
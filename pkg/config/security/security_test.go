@@ -201,6 +201,19 @@ func TestValidateCondition(t *testing.T) {
 			key:    "connection.sni",
 			values: []string{"value"},
 		},
+		{
+			key:    "connection.client_cert.subject",
+			values: []string{"CN=client,O=example"},
+		},
+		{
+			key:    "connection.client_cert.san",
+			values: []string{"client.example.com"},
+		},
+		{
+			key:       "connection.client_cert.issuer",
+			values:    []string{"CN=ca,O=example"},
+			wantError: true,
+		},
 		{
 			key:    "experimental.envoy.filters.a.b[c]",
 			values: []string{"value"},
@@ -218,3 +231,61 @@ func TestValidateCondition(t *testing.T) {
 		}
 	}
 }
+
+func TestParseJWTClaimHeader(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "@request.auth.claims.sub", want: []string{"sub"}},
+		{name: "@Request.Auth.Claims.Group.ID", want: []string{"Group", "ID"}},
+		{name: "@request.auth.claims[sub]", want: []string{"sub"}},
+		{name: "@request.auth.claims[group][https://example.com/id]", want: []string{"group", "https://example.com/id"}},
+		{name: "@request.auth.claims", wantErr: true},
+		{name: "@request.auth.claims.", wantErr: true},
+		{name: "@request.auth.claims-sub", wantErr: true},
+		{name: "@request.auth.claims.group..id", wantErr: true},
+		{name: "@request.auth.claims[group", wantErr: true},
+		{name: "@request.auth.claims[group]id", wantErr: true},
+		{name: "@request.auth.claims[]", wantErr: true},
+		{name: "x-header", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := security.ParseJWTClaimHeader(c.name)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseExtAuthzContextExtensions(t *testing.T) {
+	ext, err := security.ParseExtAuthzContextExtensions(`{"admin": {"tier": "admin", "app": "admin"}, "*": {"app": "shop"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		route string
+		want  map[string]string
+	}{
+		{"admin", map[string]string{"tier": "admin", "app": "admin"}},
+		{"cart", map[string]string{"app": "shop"}},
+		{"", map[string]string{"app": "shop"}},
+	}
+	for _, c := range cases {
+		if got := ext.ForRoute(c.route); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("route %q: got %v, want %v", c.route, got, c.want)
+		}
+	}
+	if got := security.ExtAuthzContextExtensions(nil).ForRoute("admin"); got != nil {
+		t.Errorf("expected no context extensions, got %v", got)
+	}
+	if _, err := security.ParseExtAuthzContextExtensions(`{"admin": "tier"}`); err == nil {
+		t.Errorf("expected error for invalid context extensions")
+	}
+}
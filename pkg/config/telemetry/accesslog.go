@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The minimum levels of the access logs of a provider, by the response code of the requests.
+const (
+	// LevelInfo logs all the requests.
+	LevelInfo = "INFO"
+	// LevelWarning logs the requests with a 4xx or 5xx response code.
+	LevelWarning = "WARNING"
+	// LevelError logs the requests with a 5xx response code.
+	LevelError = "ERROR"
+)
+
+var levelExpressions = map[string]string{
+	LevelInfo:    "",
+	LevelWarning: "response.code >= 400",
+	LevelError:   "response.code >= 500",
+}
+
+// AccessLogProviderFilter is the filter of the access logs of a single provider.
+type AccessLogProviderFilter struct {
+	// Expression is a CEL expression selecting the requests logged by the provider.
+	Expression string `json:"expression,omitempty"`
+	// MinLevel is the minimum level of the requests logged by the provider: INFO, WARNING or ERROR.
+	MinLevel string `json:"minLevel,omitempty"`
+}
+
+// AccessLogProviderFilters are the filters of the access logs, by provider name.
+type AccessLogProviderFilters map[string]AccessLogProviderFilter
+
+// ParseAccessLogProviderFilters parses the value of the access-log-provider-filters annotation of a Telemetry.
+func ParseAccessLogProviderFilters(value string) (AccessLogProviderFilters, error) {
+	var filters AccessLogProviderFilters
+	if err := json.Unmarshal([]byte(value), &filters); err != nil {
+		return nil, fmt.Errorf("invalid access log provider filters %q: %v", value, err)
+	}
+	for provider, f := range filters {
+		if _, ok := levelExpressions[f.MinLevel]; !ok && f.MinLevel != "" {
+			return nil, fmt.Errorf("invalid min level %q of provider %s, must be one of %s, %s or %s",
+				f.MinLevel, provider, LevelInfo, LevelWarning, LevelError)
+		}
+	}
+	return filters, nil
+}
+
+// Combine returns the CEL expression selecting the requests matching both the given expression and the filter.
+func (f AccessLogProviderFilter) Combine(expression string) string {
+	var and []string
+	for _, expr := range []string{expression, f.Expression, levelExpressions[f.MinLevel]} {
+		if expr != "" {
+			and = append(and, expr)
+		}
+	}
+	if len(and) == 1 {
+		return and[0]
+	}
+	for i, expr := range and {
+		and[i] = "(" + expr + ")"
+	}
+	return strings.Join(and, " && ")
+}
@@ -0,0 +1,76 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccessLogProviderFilters(t *testing.T) {
+	cases := []struct {
+		name    string
+		value   string
+		want    AccessLogProviderFilters
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: `{"stdout": {"minLevel": "ERROR"}, "otel": {"expression": "request.path != '/health'"}}`,
+			want: AccessLogProviderFilters{
+				"stdout": {MinLevel: LevelError},
+				"otel":   {Expression: "request.path != '/health'"},
+			},
+		},
+		{name: "invalid JSON", value: `{"stdout"`, wantErr: true},
+		{name: "invalid level", value: `{"stdout": {"minLevel": "DEBUG"}}`, wantErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccessLogProviderFilters(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessLogProviderFilterCombine(t *testing.T) {
+	cases := []struct {
+		name       string
+		filter     AccessLogProviderFilter
+		expression string
+		want       string
+	}{
+		{"empty", AccessLogProviderFilter{}, "", ""},
+		{"expression only", AccessLogProviderFilter{}, "response.code == 404", "response.code == 404"},
+		{"info level", AccessLogProviderFilter{MinLevel: LevelInfo}, "", ""},
+		{"error level", AccessLogProviderFilter{MinLevel: LevelError}, "", "response.code >= 500"},
+		{
+			"all", AccessLogProviderFilter{Expression: "request.path != '/health'", MinLevel: LevelWarning}, "a == b",
+			"(a == b) && (request.path != '/health') && (response.code >= 400)",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Combine(tt.expression); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
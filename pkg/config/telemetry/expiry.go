@@ -0,0 +1,36 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"fmt"
+	"time"
+)
+
+// minMetricExpiry is the minimum expiry of the metrics, so the metrics of active peers are not expired between
+// two reports.
+const minMetricExpiry = time.Minute
+
+// ParseMetricExpiry parses the value of the metric-expiry annotation of a Telemetry.
+func ParseMetricExpiry(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid metric expiry %q: %v", value, err)
+	}
+	if d < minMetricExpiry {
+		return 0, fmt.Errorf("invalid metric expiry %q: must be at least %v", value, minMetricExpiry)
+	}
+	return d, nil
+}
@@ -0,0 +1,33 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseMetricExpiry(t *testing.T) {
+	d, err := ParseMetricExpiry("10m")
+	assert.NoError(t, err)
+	assert.Equal(t, d, 10*time.Minute)
+
+	for _, invalid := range []string{"10s", "-1h", "forever"} {
+		_, err := ParseMetricExpiry(invalid)
+		assert.Error(t, err)
+	}
+}
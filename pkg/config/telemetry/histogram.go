@@ -0,0 +1,92 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// histogramStatPrefixes are the Envoy stat prefixes of the histograms of the standard Istio metrics, by the name
+// of the metric in the Telemetry API.
+var histogramStatPrefixes = map[string]string{
+	"REQUEST_DURATION": "istiocustom.istio_request_duration_milliseconds",
+	"REQUEST_SIZE":     "istiocustom.istio_request_bytes",
+	"RESPONSE_SIZE":    "istiocustom.istio_response_bytes",
+}
+
+// HistogramBuckets are the bucket boundaries of the histograms of the standard Istio metrics, by metric name:
+// REQUEST_DURATION, REQUEST_SIZE or RESPONSE_SIZE.
+type HistogramBuckets map[string][]float64
+
+// ParseHistogramBuckets parses the value of the histogram-buckets annotation of a Telemetry.
+func ParseHistogramBuckets(value string) (HistogramBuckets, error) {
+	var buckets HistogramBuckets
+	if err := json.Unmarshal([]byte(value), &buckets); err != nil {
+		return nil, fmt.Errorf("invalid histogram buckets %q: %v", value, err)
+	}
+	for metric, b := range buckets {
+		if _, f := histogramStatPrefixes[metric]; !f {
+			return nil, fmt.Errorf("invalid histogram metric %q, must be one of REQUEST_DURATION, REQUEST_SIZE or RESPONSE_SIZE", metric)
+		}
+		if err := validateBuckets(b); err != nil {
+			return nil, fmt.Errorf("invalid buckets of %s: %v", metric, err)
+		}
+	}
+	return buckets, nil
+}
+
+// StatBuckets returns the bucket boundaries by Envoy stat prefix, the format of the statsHistogramBuckets
+// annotation of the pods.
+func (b HistogramBuckets) StatBuckets() map[string][]float64 {
+	if len(b) == 0 {
+		return nil
+	}
+	out := make(map[string][]float64, len(b))
+	for metric, buckets := range b {
+		out[histogramStatPrefixes[metric]] = buckets
+	}
+	return out
+}
+
+// ParseStatHistogramBuckets parses the value of the statsHistogramBuckets annotation of a pod, the bucket
+// boundaries of the histograms by Envoy stat prefix.
+func ParseStatHistogramBuckets(value string) (map[string][]float64, error) {
+	var buckets map[string][]float64
+	if err := json.Unmarshal([]byte(value), &buckets); err != nil {
+		return nil, fmt.Errorf("invalid histogram buckets %q: %v", value, err)
+	}
+	for prefix, b := range buckets {
+		if err := validateBuckets(b); err != nil {
+			return nil, fmt.Errorf("invalid buckets of %s: %v", prefix, err)
+		}
+	}
+	return buckets, nil
+}
+
+func validateBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		return fmt.Errorf("at least one bucket is required")
+	}
+	for i, b := range buckets {
+		if b <= 0 {
+			return fmt.Errorf("bucket %v must be positive", b)
+		}
+		if i > 0 && b <= buckets[i-1] {
+			return fmt.Errorf("buckets must be in increasing order")
+		}
+	}
+	return nil
+}
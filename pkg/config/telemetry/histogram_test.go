@@ -0,0 +1,54 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHistogramBuckets(t *testing.T) {
+	cases := []struct {
+		name    string
+		value   string
+		want    map[string][]float64
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: `{"REQUEST_DURATION": [0.5, 1, 5], "RESPONSE_SIZE": [100, 1000]}`,
+			want: map[string][]float64{
+				"istiocustom.istio_request_duration_milliseconds": {0.5, 1, 5},
+				"istiocustom.istio_response_bytes":                {100, 1000},
+			},
+		},
+		{name: "unknown metric", value: `{"REQUEST_COUNT": [1, 2]}`, wantErr: true},
+		{name: "no buckets", value: `{"REQUEST_DURATION": []}`, wantErr: true},
+		{name: "not increasing", value: `{"REQUEST_DURATION": [1, 1]}`, wantErr: true},
+		{name: "not positive", value: `{"REQUEST_DURATION": [0, 1]}`, wantErr: true},
+		{name: "invalid JSON", value: `[1, 2]`, wantErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHistogramBuckets(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got.StatBuckets(), tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got.StatBuckets())
+			}
+		})
+	}
+}
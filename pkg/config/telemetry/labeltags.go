@@ -0,0 +1,59 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TraceLabelTags are the custom tags of the spans sourced from the labels of the workloads, by tag name to label.
+type TraceLabelTags map[string]string
+
+// ParseTraceLabelTags parses the value of the trace-label-tags annotation of a Telemetry, a comma separated list
+// of tag=label pairs, for example team=team,build=app.kubernetes.io/version.
+func ParseTraceLabelTags(value string) (TraceLabelTags, error) {
+	tags := TraceLabelTags{}
+	for _, t := range strings.Split(value, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		tag, label, f := strings.Cut(t, "=")
+		tag, label = strings.TrimSpace(tag), strings.TrimSpace(label)
+		if !f || tag == "" {
+			return nil, fmt.Errorf("invalid trace label tag %q, must be tag=label", t)
+		}
+		if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label %q of trace tag %q: %s", label, tag, strings.Join(errs, "; "))
+		}
+		tags[tag] = label
+	}
+	return tags, nil
+}
+
+// Values returns the values of the tags for the workload of the labels. The tags of the labels the workload does
+// not have are omitted.
+func (t TraceLabelTags) Values(labels map[string]string) map[string]string {
+	values := map[string]string{}
+	for tag, label := range t {
+		if v, f := labels[label]; f {
+			values[tag] = v
+		}
+	}
+	return values
+}
@@ -0,0 +1,42 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseTraceLabelTags(t *testing.T) {
+	tags, err := ParseTraceLabelTags("team=team, build = app.kubernetes.io/version")
+	assert.NoError(t, err)
+	assert.Equal(t, tags, TraceLabelTags{
+		"team":  "team",
+		"build": "app.kubernetes.io/version",
+	})
+	assert.Equal(t, tags.Values(map[string]string{"team": "payments", "app": "checkout"}), map[string]string{
+		"team": "payments",
+	})
+
+	tags, err = ParseTraceLabelTags("")
+	assert.NoError(t, err)
+	assert.Equal(t, len(tags), 0)
+
+	for _, v := range []string{"team", "=team", "team=", "team=not a label"} {
+		_, err = ParseTraceLabelTags(v)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"regexp"
+
+	"istio.io/istio/pkg/util/sets"
+)
+
+// tcpMetrics are the standard metrics reported for the TCP connections rather than for the HTTP requests.
+var tcpMetrics = sets.New("TCP_OPENED_CONNECTIONS", "TCP_CLOSED_CONNECTIONS", "TCP_SENT_BYTES", "TCP_RECEIVED_BYTES")
+
+// httpAttributeRegex matches the references to the request and response attributes in an expression, outside of the
+// keys of a map.
+var httpAttributeRegex = regexp.MustCompile(`(^|[^\w.'"])(request|response)\.`)
+
+// IsTCPMetric returns whether the standard metric, named as in the MetricSelector of the Telemetry API, is reported
+// for the TCP connections.
+func IsTCPMetric(name string) bool {
+	return tcpMetrics.Contains(name)
+}
+
+// UsesHTTPAttributes returns whether the expression of a dimension references the request or response attributes,
+// which are not available for the TCP metrics. The dimensions of the TCP metrics are computed from the connection,
+// upstream, source, destination and filter_state attributes, the last three including the metadata of the peer
+// exchanged over mTLS.
+func UsesHTTPAttributes(expr string) bool {
+	return httpAttributeRegex.MatchString(expr)
+}
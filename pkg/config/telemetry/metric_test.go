@@ -0,0 +1,30 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestUsesHTTPAttributes(t *testing.T) {
+	for _, expr := range []string{"request.host", "response.code == 200 ? 'ok' : 'error'", "has(request.headers['x'])"} {
+		assert.Equal(t, UsesHTTPAttributes(expr), true)
+	}
+	for _, expr := range []string{"connection.requested_server_name", "upstream.address", "filter_state['request.id']"} {
+		assert.Equal(t, UsesHTTPAttributes(expr), false)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RequestOperationDimension is the metric dimension of the operation the request is classified into.
+const RequestOperationDimension = "request_operation"
+
+// unknownOperation is the operation of the requests not matching any operation.
+const unknownOperation = "unknown"
+
+var operationNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// RequestOperation is a named operation the requests matching its method and path are classified into.
+type RequestOperation struct {
+	Name string `json:"name"`
+	// Method is the HTTP method of the requests. If empty, the requests of any method match.
+	Method string `json:"method,omitempty"`
+	// Path is the path template of the requests, in which * matches a single segment and a trailing ** matches any
+	// number of segments, for example /books/*/reviews. If empty, the requests of any path match.
+	Path string `json:"path,omitempty"`
+}
+
+// ParseRequestOperations parses the value of the request-operations annotation of a Telemetry, a JSON list of
+// operations, the first matching operation classifying the request.
+func ParseRequestOperations(value string) ([]RequestOperation, error) {
+	var ops []RequestOperation
+	if err := json.Unmarshal([]byte(value), &ops); err != nil {
+		return nil, fmt.Errorf("invalid request operations %q: %v", value, err)
+	}
+	for _, op := range ops {
+		if !operationNameRegex.MatchString(op.Name) {
+			return nil, fmt.Errorf("invalid request operation name %q", op.Name)
+		}
+		if op.Method == "" && op.Path == "" {
+			return nil, fmt.Errorf("request operation %s must have a method or a path", op.Name)
+		}
+		if strings.ContainsAny(op.Method, `'\`) {
+			return nil, fmt.Errorf("invalid method %q of request operation %s", op.Method, op.Name)
+		}
+		if op.Path != "" {
+			if !strings.HasPrefix(op.Path, "/") || strings.ContainsAny(op.Path, `'\`) {
+				return nil, fmt.Errorf("invalid path %q of request operation %s", op.Path, op.Name)
+			}
+			if i := strings.Index(op.Path, "**"); i >= 0 && i != len(op.Path)-2 {
+				return nil, fmt.Errorf("invalid path %q of request operation %s: ** is only supported at the end", op.Path, op.Name)
+			}
+		}
+	}
+	return ops, nil
+}
+
+// RequestOperationExpression returns the CEL expression classifying the requests into the operations, evaluating to
+// the name of the first matching operation, or unknown.
+func RequestOperationExpression(ops []RequestOperation) string {
+	var sb strings.Builder
+	for _, op := range ops {
+		var conds []string
+		if op.Method != "" {
+			conds = append(conds, fmt.Sprintf("request.method == '%s'", op.Method))
+		}
+		if op.Path != "" {
+			conds = append(conds, fmt.Sprintf("request.url_path.matches(r'%s')", pathTemplateRegex(op.Path)))
+		}
+		fmt.Fprintf(&sb, "(%s) ? '%s' : ", strings.Join(conds, " && "), op.Name)
+	}
+	sb.WriteString("'" + unknownOperation + "'")
+	return sb.String()
+}
+
+// pathTemplateRegex returns the regex matching the paths of the path template.
+func pathTemplateRegex(path string) string {
+	suffix := ""
+	if strings.HasSuffix(path, "**") {
+		path, suffix = strings.TrimSuffix(path, "**"), ".*"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s == "*" {
+			segments[i] = "[^/]+"
+		} else {
+			segments[i] = regexp.QuoteMeta(s)
+		}
+	}
+	return "^" + strings.Join(segments, "/") + suffix + "$"
+}
@@ -0,0 +1,66 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"regexp"
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestRequestOperationExpression(t *testing.T) {
+	ops, err := ParseRequestOperations(`[
+		{"name": "GetReviews", "method": "GET", "path": "/books/*/reviews"},
+		{"name": "Static", "path": "/static/**"},
+		{"name": "Write", "method": "POST"}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, RequestOperationExpression(ops),
+		`(request.method == 'GET' && request.url_path.matches(r'^/books/[^/]+/reviews$')) ? 'GetReviews' : `+
+			`(request.url_path.matches(r'^/static/.*$')) ? 'Static' : `+
+			`(request.method == 'POST') ? 'Write' : 'unknown'`)
+
+	for _, invalid := range []string{
+		`[{"name": "", "path": "/"}]`,
+		`[{"name": "Any"}]`,
+		`[{"name": "Relative", "path": "books"}]`,
+		`[{"name": "Quote", "path": "/books/'"}]`,
+		`[{"name": "Middle", "path": "/books/**/reviews"}]`,
+		`not json`,
+	} {
+		_, err := ParseRequestOperations(invalid)
+		assert.Error(t, err)
+	}
+}
+
+func TestPathTemplateRegex(t *testing.T) {
+	cases := []struct {
+		template string
+		path     string
+		match    bool
+	}{
+		{"/books/*/reviews", "/books/1/reviews", true},
+		{"/books/*/reviews", "/books/1/2/reviews", false},
+		{"/books/*", "/books/", false},
+		{"/static/**", "/static/css/main.css", true},
+		{"/static/**", "/other/main.css", false},
+		{"/v1.0/books", "/v1x0/books", false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.template+tt.path, func(t *testing.T) {
+			assert.Equal(t, regexp.MustCompile(pathTemplateRegex(tt.template)).MatchString(tt.path), tt.match)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+// ParseTraceContexts parses the value of the trace-context-propagation annotation of a Telemetry, a comma separated
+// list of the trace context formats W3C_TRACE_CONTEXT, B3, GRPC_BIN and CLOUD_TRACE_CONTEXT.
+func ParseTraceContexts(value string) ([]meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext, error) {
+	var contexts []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		v, f := meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext_value[c]
+		if !f || v == int32(meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_UNSPECIFIED) {
+			return nil, fmt.Errorf("invalid trace context %q, must be W3C_TRACE_CONTEXT, B3, GRPC_BIN or CLOUD_TRACE_CONTEXT", c)
+		}
+		contexts = append(contexts, meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext(v))
+	}
+	return contexts, nil
+}
@@ -0,0 +1,40 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseTraceContexts(t *testing.T) {
+	contexts, err := ParseTraceContexts("W3C_TRACE_CONTEXT, B3")
+	assert.NoError(t, err)
+	assert.Equal(t, contexts, []meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_TraceContext{
+		meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_W3C_TRACE_CONTEXT,
+		meshconfig.MeshConfig_ExtensionProvider_OpenCensusAgentTracingProvider_B3,
+	})
+
+	contexts, err = ParseTraceContexts("")
+	assert.NoError(t, err)
+	assert.Equal(t, len(contexts), 0)
+
+	for _, v := range []string{"UNSPECIFIED", "DATADOG", "b3"} {
+		_, err = ParseTraceContexts(v)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// RouteNameDimension is the metric dimension of the name of the route matched by the request.
+	RouteNameDimension = "route_name"
+	// VirtualServiceDimension is the metric dimension of the VirtualService of the route matched by the request,
+	// including the VirtualServices generated from HTTPRoutes.
+	VirtualServiceDimension = "virtual_service"
+)
+
+// routeDimensionValues are the CEL expressions of the route dimensions, evaluated by the proxy.
+var routeDimensionValues = map[string]string{
+	RouteNameDimension:      "xds.route_name",
+	VirtualServiceDimension: "xds.route_metadata.filter_metadata['istio']['config']",
+}
+
+// RouteDimensions are the dimensions of the route matched by the requests added to the standard Istio metrics, by
+// dimension name to the CEL expression of their value.
+type RouteDimensions map[string]string
+
+// ParseRouteDimensions parses the value of the route-metric-dimensions annotation of a Telemetry, a comma separated
+// list of route_name and virtual_service.
+func ParseRouteDimensions(value string) (RouteDimensions, error) {
+	dims := RouteDimensions{}
+	for _, d := range strings.Split(value, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		expr, f := routeDimensionValues[d]
+		if !f {
+			return nil, fmt.Errorf("invalid route metric dimension %q, must be %s or %s", d, RouteNameDimension, VirtualServiceDimension)
+		}
+		dims[d] = expr
+	}
+	return dims, nil
+}
+
+// Names returns the sorted names of the dimensions.
+func (d RouteDimensions) Names() []string {
+	names := make([]string, 0, len(d))
+	for n := range d {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
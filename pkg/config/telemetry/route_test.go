@@ -0,0 +1,38 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseRouteDimensions(t *testing.T) {
+	dims, err := ParseRouteDimensions("route_name, virtual_service")
+	assert.NoError(t, err)
+	assert.Equal(t, dims, RouteDimensions{
+		"route_name":      "xds.route_name",
+		"virtual_service": "xds.route_metadata.filter_metadata['istio']['config']",
+	})
+	assert.Equal(t, dims.Names(), []string{"route_name", "virtual_service"})
+
+	dims, err = ParseRouteDimensions("")
+	assert.NoError(t, err)
+	assert.Equal(t, len(dims), 0)
+
+	_, err = ParseRouteDimensions("route_name,path")
+	assert.Error(t, err)
+}
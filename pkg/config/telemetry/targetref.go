@@ -0,0 +1,58 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// VirtualServiceKind is the kind of the VirtualService target of a Telemetry.
+	VirtualServiceKind = "VirtualService"
+	// HTTPRouteKind is the kind of the HTTPRoute target of a Telemetry.
+	HTTPRouteKind = "HTTPRoute"
+)
+
+// TargetRef is the route a Telemetry applies to, instead of the workloads of its namespace or selector: a
+// VirtualService or HTTPRoute in the namespace of the Telemetry.
+type TargetRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// SectionName is the name of the HTTP route of the VirtualService the Telemetry applies to. If empty, it
+	// applies to all of its routes.
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// ParseTargetRef parses the value of the target-ref annotation of a Telemetry.
+func ParseTargetRef(value string) (TargetRef, error) {
+	var ref TargetRef
+	if err := json.Unmarshal([]byte(value), &ref); err != nil {
+		return TargetRef{}, fmt.Errorf("invalid target ref %q: %v", value, err)
+	}
+	switch ref.Kind {
+	case VirtualServiceKind:
+	case HTTPRouteKind:
+		if ref.SectionName != "" {
+			return TargetRef{}, fmt.Errorf("invalid target ref %q: sectionName is only supported for %s", value, VirtualServiceKind)
+		}
+	default:
+		return TargetRef{}, fmt.Errorf("invalid target ref kind %q, must be %s or %s", ref.Kind, VirtualServiceKind, HTTPRouteKind)
+	}
+	if ref.Name == "" {
+		return TargetRef{}, fmt.Errorf("invalid target ref %q: name is required", value)
+	}
+	return ref, nil
+}
@@ -0,0 +1,41 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseTargetRef(t *testing.T) {
+	ref, err := ParseTargetRef(`{"kind": "VirtualService", "name": "reviews", "sectionName": "errors"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ref, TargetRef{Kind: VirtualServiceKind, Name: "reviews", SectionName: "errors"})
+
+	ref, err = ParseTargetRef(`{"kind": "HTTPRoute", "name": "reviews"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ref, TargetRef{Kind: HTTPRouteKind, Name: "reviews"})
+
+	for _, invalid := range []string{
+		`{"kind": "Service", "name": "reviews"}`,
+		`{"kind": "VirtualService"}`,
+		`{"kind": "HTTPRoute", "name": "reviews", "sectionName": "errors"}`,
+		`not json`,
+	} {
+		_, err := ParseTargetRef(invalid)
+		assert.Error(t, err)
+	}
+}
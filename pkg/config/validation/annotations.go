@@ -0,0 +1,79 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"sort"
+
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+// configAnnotations are the alpha annotations of pkg/config/constants, by the kinds they apply to. Their values are
+// validated with the config of these kinds, against the format documented by their constant; they are warned about
+// on the configs of the other kinds, where they are ignored.
+var configAnnotations = map[string][]config.GroupVersionKind{
+	constants.AccessLogProviderFiltersAnnotation:  {gvk.Telemetry},
+	constants.TargetGatewayAnnotation:             {gvk.AuthorizationPolicy},
+	constants.TargetSectionNameAnnotation:         {gvk.AuthorizationPolicy},
+	constants.ExtAuthzContextExtensionsAnnotation: {gvk.VirtualService},
+	constants.HistogramBucketsAnnotation:          {gvk.Telemetry},
+	constants.RouteMetricDimensionsAnnotation:     {gvk.Telemetry},
+	constants.TelemetryTargetRefAnnotation:        {gvk.Telemetry},
+	constants.MetricExpiryAnnotation:              {gvk.Telemetry},
+	constants.RequestOperationsAnnotation:         {gvk.Telemetry},
+	constants.TraceLabelTagsAnnotation:            {gvk.Telemetry},
+	constants.TraceContextPropagationAnnotation:   {gvk.Telemetry},
+	constants.ClusterWeightsAnnotation:            {gvk.DestinationRule},
+	constants.ClusterFailoverAnnotation:           {gvk.DestinationRule},
+	constants.ClusterSelectorAnnotation:           {gvk.VirtualService, gvk.DestinationRule},
+	constants.DynamicForwardProxyAnnotation:       {gvk.ServiceEntry},
+	constants.GRPCProbeAnnotation:                 {gvk.WorkloadGroup},
+	constants.WorkloadEntryCleanupAnnotation:      {gvk.WorkloadGroup},
+	constants.WasmPullAnnotation:                  {gvk.WasmPlugin},
+	constants.EnvoyFilterTargetRefAnnotation:      {gvk.EnvoyFilter},
+}
+
+// validateConfigAnnotations warns about the alpha annotations of the config which do not apply to its kind.
+func validateConfigAnnotations(cfg config.Config) (v Validation) {
+	if cfg.GroupVersionKind.Kind == "" {
+		// The kind of the config is not known.
+		return
+	}
+	var names []string
+	for name := range cfg.Annotations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kinds, f := configAnnotations[name]
+		if !f || containsKind(kinds, cfg.GroupVersionKind) {
+			continue
+		}
+		v = appendValidation(v, Warningf("annotation %s does not apply to %s and is ignored", name, cfg.GroupVersionKind.Kind))
+	}
+	return
+}
+
+// containsKind returns whether the kind is one of the kinds, in any version.
+func containsKind(kinds []config.GroupVersionKind, k config.GroupVersionKind) bool {
+	for _, kind := range kinds {
+		if kind.Group == k.Group && kind.Kind == k.Kind {
+			return true
+		}
+	}
+	return false
+}
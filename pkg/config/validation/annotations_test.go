@@ -0,0 +1,56 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+func TestValidateConfigAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		warning     bool
+	}{
+		{"none", nil, false},
+		{"applies", map[string]string{constants.ClusterSelectorAnnotation: "cluster-1"}, false},
+		{"unrelated", map[string]string{"example.com/owner": "team"}, false},
+		{"other kind", map[string]string{constants.AccessLogProviderFiltersAnnotation: "{}"}, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := ValidateDestinationRule(config.Config{
+				Meta: config.Meta{
+					GroupVersionKind: gvk.DestinationRule,
+					Name:             "dr",
+					Namespace:        "default",
+					Annotations:      tt.annotations,
+				},
+				Spec: &networking.DestinationRule{Host: "reviews"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := warning != nil; got != tt.warning {
+				t.Fatalf("expected warning %v, got %v", tt.warning, warning)
+			}
+		})
+	}
+}
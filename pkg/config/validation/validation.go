@@ -48,15 +48,21 @@ import (
 	telemetry "istio.io/api/telemetry/v1alpha1"
 	type_beta "istio.io/api/type/v1beta1"
 	"istio.io/istio/pilot/pkg/features"
-	"istio.io/istio/pilot/pkg/util/constant"
+	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/envoyfilter"
 	"istio.io/istio/pkg/config/gateway"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/loadbalancing"
+	"istio.io/istio/pkg/config/probe"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/config/security"
+	telemetryconfig "istio.io/istio/pkg/config/telemetry"
 	"istio.io/istio/pkg/config/visibility"
+	"istio.io/istio/pkg/config/wasmplugin"
+	"istio.io/istio/pkg/config/workloadgroup"
 	"istio.io/istio/pkg/config/xds"
 	"istio.io/istio/pkg/kube/apimirror"
 	"istio.io/istio/pkg/util/grpc"
@@ -64,6 +70,7 @@ import (
 	"istio.io/istio/pkg/util/protomarshal"
 	"istio.io/istio/pkg/util/sets"
 	"istio.io/pkg/log"
+	"istio.io/pkg/version"
 )
 
 // Constants for duration fields
@@ -223,7 +230,8 @@ func validateAnnotationDryRun(f ValidateFunc) ValidateFunc {
 		if err := checkDryRunAnnotation(config, isAuthz); err != nil {
 			return nil, err
 		}
-		return f(config)
+		warning, err := f(config)
+		return appendValidation(Validation{Err: err, Warning: warning}, validateConfigAnnotations(config)).Unwrap()
 	}
 }
 
@@ -707,10 +715,60 @@ var ValidateDestinationRule = registerValidateFunc("ValidateDestinationRule",
 			validateExportTo(cfg.Namespace, rule.ExportTo, false, rule.GetWorkloadSelector() != nil))
 
 		v = appendValidation(v, validateWorkloadSelector(rule.GetWorkloadSelector()))
+		v = appendValidation(v, validateClusterLoadBalancing(cfg.Annotations))
+		v = appendValidation(v, validateClusterSelector(cfg.Annotations))
 
 		return v.Unwrap()
 	})
 
+// validateClusterLoadBalancing validates the cluster weights and failover annotations of a DestinationRule.
+func validateClusterLoadBalancing(annotations map[string]string) (v Validation) {
+	if value := annotations[constants.ClusterWeightsAnnotation]; value != "" {
+		if _, err := loadbalancing.ParseClusterWeights(value); err != nil {
+			v = appendValidation(v, fmt.Errorf("invalid %s annotation: %v", constants.ClusterWeightsAnnotation, err))
+		}
+	}
+	if value := annotations[constants.ClusterFailoverAnnotation]; value != "" {
+		if _, err := loadbalancing.ParseClusterFailover(value); err != nil {
+			v = appendValidation(v, fmt.Errorf("invalid %s annotation: %v", constants.ClusterFailoverAnnotation, err))
+		}
+	}
+	return
+}
+
+// validateClusterSelector validates the cluster selector annotation of a VirtualService or DestinationRule.
+func validateClusterSelector(annotations map[string]string) error {
+	value, f := annotations[constants.ClusterSelectorAnnotation]
+	if !f {
+		return nil
+	}
+	selector, err := cluster.ParseSelector(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %v", constants.ClusterSelectorAnnotation, err)
+	}
+	if len(selector) == 0 {
+		return fmt.Errorf("invalid %s annotation: must select at least one cluster", constants.ClusterSelectorAnnotation)
+	}
+	return nil
+}
+
+// validateDynamicForwardProxy validates the dynamic forward proxy annotation of a ServiceEntry, returning whether the
+// ServiceEntry opts in to the dynamic forward proxy.
+func validateDynamicForwardProxy(annotations map[string]string) (bool, error) {
+	value, f := annotations[constants.DynamicForwardProxyAnnotation]
+	if !f {
+		return false, nil
+	}
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid %s annotation %q: must be true or false", constants.DynamicForwardProxyAnnotation, value)
+	}
+}
+
 func validateExportTo(namespace string, exportTo []string, isServiceEntry bool, isDestinationRuleWithSelector bool) (errs error) {
 	if len(exportTo) > 0 {
 		// Make sure there are no duplicates
@@ -817,6 +875,7 @@ var ValidateEnvoyFilter = registerValidateFunc("ValidateEnvoyFilter",
 		if warning != nil {
 			errs = appendValidation(errs, WrapWarning(fmt.Errorf("Envoy filter: %s, will be applied to all services in namespace", warning))) // nolint: stylecheck
 		}
+		errs = appendValidation(errs, validateEnvoyFilterTargetRef(cfg.Annotations, rule))
 
 		for _, cp := range rule.ConfigPatches {
 			if cp == nil {
@@ -841,11 +900,21 @@ var ValidateEnvoyFilter = registerValidateFunc("ValidateEnvoyFilter",
 			}
 
 			// ensure that the supplied regex for proxy version compiles
+			strict := false
 			if cp.Match != nil && cp.Match.Proxy != nil && cp.Match.Proxy.ProxyVersion != "" {
-				if _, err := regexp.Compile(cp.Match.Proxy.ProxyVersion); err != nil {
+				proxyVersion, err := regexp.Compile(cp.Match.Proxy.ProxyVersion)
+				if err != nil {
 					errs = appendValidation(errs, fmt.Errorf("Envoy filter: invalid regex for proxy version, [%v]", err)) // nolint: stylecheck
 					continue
 				}
+				// The patches of the proxies of the version of istiod are built against its Envoy API, so they are
+				// validated strictly. The patches of the other versions stop applying once the proxies are upgraded.
+				targets, known := envoyfilter.TargetsVersion(proxyVersion, istiodVersion)
+				if known && !targets {
+					errs = appendValidation(errs, WrapWarning(fmt.Errorf("Envoy filter: patch for proxy version %q does not apply to the proxies of version %s", // nolint: stylecheck
+						cp.Match.Proxy.ProxyVersion, istiodVersion)))
+				}
+				strict = targets
 			}
 			// ensure that applyTo, match and patch all line up
 			switch cp.ApplyTo {
@@ -915,8 +984,11 @@ var ValidateEnvoyFilter = registerValidateFunc("ValidateEnvoyFilter",
 			} else {
 				// Run with strict validation, and emit warnings. This helps capture cases like unknown fields
 				// We do not want to reject in case the proto is valid but our libraries are outdated
+				// Patches scoped to the version of istiod are rejected instead, as they target its Envoy API.
 				obj, err := xds.BuildXDSObjectFromStruct(cp.ApplyTo, cp.Patch.Value, true)
-				if err != nil {
+				if err != nil && strict {
+					errs = appendValidation(errs, err)
+				} else if err != nil {
 					errs = appendValidation(errs, WrapWarning(err))
 				}
 
@@ -931,6 +1003,24 @@ var ValidateEnvoyFilter = registerValidateFunc("ValidateEnvoyFilter",
 		return errs.Unwrap()
 	})
 
+// istiodVersion is the version of istiod, the patches of EnvoyFilters scoped to the proxies of this version are
+// validated against its Envoy API.
+var istiodVersion = version.Info.Version
+
+func validateEnvoyFilterTargetRef(annotations map[string]string, spec *networking.EnvoyFilter) (v Validation) {
+	value, f := annotations[constants.EnvoyFilterTargetRefAnnotation]
+	if !f {
+		return
+	}
+	if _, err := envoyfilter.ParseTargetRef(value); err != nil {
+		return appendValidation(v, err)
+	}
+	if spec.WorkloadSelector != nil {
+		v = appendErrorf(v, "workloadSelector may not be set with the %s annotation", constants.EnvoyFilterTargetRefAnnotation)
+	}
+	return
+}
+
 func validateListenerMatchName(name string) error {
 	if newName, f := xds.ReverseDeprecatedFilterNames[name]; f {
 		return WrapWarning(fmt.Errorf("using deprecated filter name %q; use %q instead", name, newName))
@@ -1970,6 +2060,15 @@ var ValidateAuthorizationPolicy = registerValidateFunc("ValidateAuthorizationPol
 		if err := validateWorkloadSelector(in.Selector); err != nil {
 			errs = appendErrors(errs, err)
 		}
+		if cfg.Annotations[constants.TargetGatewayAnnotation] != "" {
+			if len(in.GetSelector().GetMatchLabels()) > 0 {
+				errs = appendErrors(errs, fmt.Errorf("selector must not be set with the %s annotation",
+					constants.TargetGatewayAnnotation))
+			}
+		} else if cfg.Annotations[constants.TargetSectionNameAnnotation] != "" {
+			errs = appendErrors(errs, fmt.Errorf("the %s annotation requires the %s annotation",
+				constants.TargetSectionNameAnnotation, constants.TargetGatewayAnnotation))
+		}
 
 		if in.Action == security_beta.AuthorizationPolicy_CUSTOM {
 			if in.Rules == nil {
@@ -2109,6 +2208,10 @@ var ValidateAuthorizationPolicy = registerValidateFunc("ValidateAuthorizationPol
 						if err := security.ValidateAttribute(key, condition.GetNotValues()); err != nil {
 							errs = appendErrors(errs, fmt.Errorf("invalid `notValue` for `key` %s: %v", key, err))
 						}
+						if key == security.AttrRequestCEL {
+							errs = appendErrors(errs, validateCELConditions(key, condition.GetValues()))
+							errs = appendErrors(errs, validateCELConditions(key, condition.GetNotValues()))
+						}
 					}
 				}
 			}
@@ -2194,10 +2297,28 @@ func validateJwtRule(rule *security_beta.JWTRule) (errs error) {
 		if err := ValidateHTTPHeaderValue(claimAndHeaders.Header); err != nil {
 			errs = multierror.Append(errs, err)
 		}
+		if err := validateOutputClaim(claimAndHeaders.Claim); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("outputClaimToHeaders: %v", err))
+		}
 	}
 	return
 }
 
+// validateOutputClaim checks the claim copied to a header, which may be nested, e.g. "group.id" or "[group][id]".
+func validateOutputClaim(claim string) error {
+	claims, err := security.ParseJWTClaimPath(claim)
+	if err != nil {
+		return err
+	}
+	for _, c := range claims {
+		// The proxy always separates nested claims with ".".
+		if strings.Contains(c, ".") {
+			return fmt.Errorf("claim %q: nested claim names must not contain \".\"", claim)
+		}
+	}
+	return nil
+}
+
 // ValidatePeerAuthentication checks that peer authentication spec is well-formed.
 var ValidatePeerAuthentication = registerValidateFunc("ValidatePeerAuthentication",
 	func(cfg config.Config) (Warning, error) {
@@ -2254,6 +2375,12 @@ var ValidateVirtualService = registerValidateFunc("ValidateVirtualService",
 			}
 		}
 
+		if v, f := cfg.Annotations[constants.ExtAuthzContextExtensionsAnnotation]; f {
+			if _, err := security.ParseExtAuthzContextExtensions(v); err != nil {
+				errs = appendValidation(errs, fmt.Errorf("invalid %s annotation: %v", constants.ExtAuthzContextExtensionsAnnotation, err))
+			}
+		}
+
 		appliesToMesh := false
 		appliesToGateway := false
 		if len(virtualService.Gateways) == 0 {
@@ -2269,22 +2396,26 @@ var ValidateVirtualService = registerValidateFunc("ValidateVirtualService",
 			}
 		}
 
-		if !appliesToGateway {
-			validateJWTClaimRoute := func(headers map[string]*networking.StringMatch) {
-				for key := range headers {
-					if strings.HasPrefix(key, constant.HeaderJWTClaim) {
-						msg := fmt.Sprintf("JWT claim based routing (key: %s) is only supported for gateway, found no gateways: %v", key, virtualService.Gateways)
-						errs = appendValidation(errs, errors.New(msg))
-					}
+		validateJWTClaimRoute := func(headers map[string]*networking.StringMatch) {
+			for key := range headers {
+				if !security.IsJWTClaimHeader(key) {
+					continue
 				}
-			}
-			for _, http := range virtualService.GetHttp() {
-				for _, m := range http.GetMatch() {
-					validateJWTClaimRoute(m.GetHeaders())
-					validateJWTClaimRoute(m.GetWithoutHeaders())
+				if !appliesToGateway {
+					msg := fmt.Sprintf("JWT claim based routing (key: %s) is only supported for gateway, found no gateways: %v", key, virtualService.Gateways)
+					errs = appendValidation(errs, errors.New(msg))
+				}
+				if _, err := security.ParseJWTClaimHeader(key); err != nil {
+					errs = appendValidation(errs, fmt.Errorf("JWT claim based routing (key: %s): %v", key, err))
 				}
 			}
 		}
+		for _, http := range virtualService.GetHttp() {
+			for _, m := range http.GetMatch() {
+				validateJWTClaimRoute(m.GetHeaders())
+				validateJWTClaimRoute(m.GetWithoutHeaders())
+			}
+		}
 
 		allHostsValid := true
 		for _, virtualHost := range virtualService.Hosts {
@@ -2353,6 +2484,8 @@ var ValidateVirtualService = registerValidateFunc("ValidateVirtualService",
 		analyzeUnreachableTCPRules(virtualService.Tcp, warnUnused, warnIneffective)
 		analyzeUnreachableTLSRules(virtualService.Tls, warnUnused, warnIneffective)
 
+		errs = appendValidation(errs, validateClusterSelector(cfg.Annotations))
+
 		return errs.Unwrap()
 	})
 
@@ -3194,7 +3327,8 @@ func validateWorkloadEntry(we *networking.WorkloadEntry) (Warning, error) {
 	}
 
 	errs = appendValidation(errs,
-		labels.Instance(we.Labels).Validate())
+		labels.Instance(we.Labels).Validate(),
+		validateEndpointLocality(we.Locality))
 	for name, port := range we.Ports {
 		// TODO: Validate port is part of Service Port - which is tricky to validate with out service entry.
 		errs = appendValidation(errs,
@@ -3224,10 +3358,23 @@ var ValidateWorkloadGroup = registerValidateFunc("ValidateWorkloadGroup",
 			}
 		}
 
-		return nil, validateReadinessProbe(wg.Probe)
+		grpcProbe, hasGRPCProbe := cfg.Annotations[constants.GRPCProbeAnnotation]
+		if hasGRPCProbe {
+			if _, err := probe.ParseGRPC(grpcProbe); err != nil {
+				errs = appendErrors(errs, fmt.Errorf("invalid %s annotation: %v", constants.GRPCProbeAnnotation, err))
+			}
+		}
+		if cleanup, f := cfg.Annotations[constants.WorkloadEntryCleanupAnnotation]; f {
+			if _, err := workloadgroup.ParseCleanupPolicy(cleanup); err != nil {
+				errs = appendErrors(errs, fmt.Errorf("invalid %s annotation: %v", constants.WorkloadEntryCleanupAnnotation, err))
+			}
+		}
+		return nil, appendErrors(errs, validateReadinessProbe(wg.Probe, hasGRPCProbe))
 	})
 
-func validateReadinessProbe(probe *networking.ReadinessProbe) (errs error) {
+// validateReadinessProbe validates the probe of a WorkloadGroup. The health check method may be omitted if the
+// workloads are checked with a gRPC probe instead.
+func validateReadinessProbe(probe *networking.ReadinessProbe, grpcProbe bool) (errs error) {
 	if probe == nil {
 		return nil
 	}
@@ -3280,6 +3427,10 @@ func validateReadinessProbe(probe *networking.ReadinessProbe) (errs error) {
 		if len(h.Command) == 0 {
 			errs = appendErrors(errs, fmt.Errorf("exec.command is required"))
 		}
+	case nil:
+		if !grpcProbe {
+			errs = appendErrors(errs, fmt.Errorf("unknown health check method %T", m))
+		}
 	default:
 		errs = appendErrors(errs, fmt.Errorf("unknown health check method %T", m))
 	}
@@ -3365,6 +3516,9 @@ var ValidateServiceEntry = registerValidateFunc("ValidateServiceEntry",
 				ValidatePort(int(port.Number)))
 		}
 
+		dynamicForwardProxy, err := validateDynamicForwardProxy(cfg.Annotations)
+		errs = appendValidation(errs, err)
+
 		switch serviceEntry.Resolution {
 		case networking.ServiceEntry_NONE:
 			if len(serviceEntry.Endpoints) != 0 {
@@ -3393,6 +3547,7 @@ var ValidateServiceEntry = registerValidateFunc("ValidateServiceEntry",
 					}
 				}
 				errs = appendValidation(errs, labels.Instance(endpoint.Labels).Validate())
+				errs = appendValidation(errs, validateEndpointLocality(endpoint.Locality))
 			}
 			if unixEndpoint && len(serviceEntry.Ports) != 1 {
 				errs = appendValidation(errs, errors.New("exactly 1 service port required for unix endpoints"))
@@ -3400,6 +3555,10 @@ var ValidateServiceEntry = registerValidateFunc("ValidateServiceEntry",
 		case networking.ServiceEntry_DNS, networking.ServiceEntry_DNS_ROUND_ROBIN:
 			if len(serviceEntry.Endpoints) == 0 {
 				for _, hostname := range serviceEntry.Hosts {
+					if dynamicForwardProxy && strings.HasPrefix(hostname, "*.") {
+						// The subdomains of the wildcard host are resolved on demand by the dynamic forward proxy.
+						hostname = strings.TrimPrefix(hostname, "*.")
+					}
 					if err := ValidateFQDN(hostname); err != nil {
 						errs = appendValidation(errs,
 							fmt.Errorf("hosts must be FQDN if no endpoints are provided for resolution mode %s", serviceEntry.Resolution))
@@ -3419,7 +3578,8 @@ var ValidateServiceEntry = registerValidateFunc("ValidateServiceEntry",
 					}
 				}
 				errs = appendValidation(errs,
-					labels.Instance(endpoint.Labels).Validate())
+					labels.Instance(endpoint.Labels).Validate(),
+					validateEndpointLocality(endpoint.Locality))
 				for name, port := range endpoint.Ports {
 					if !servicePorts[name] {
 						errs = appendValidation(errs, fmt.Errorf("endpoint port %v is not defined by the service entry", port))
@@ -3656,6 +3816,22 @@ func validateLocalities(localities []string) error {
 	return nil
 }
 
+// validateEndpointLocality warns about the locality of an endpoint which is not region/zone/subzone, with the zone and
+// subzone optional. The endpoints of the same locality are weighted together by the sum of their weights, so a
+// malformed locality changes the balancing; it is only warned about as such localities used to be accepted.
+func validateEndpointLocality(locality string) (v Validation) {
+	if locality == "" {
+		return
+	}
+	if strings.Contains(locality, "*") {
+		return Warningf("endpoint locality %q must not contain wildcards", locality)
+	}
+	if _, _, subZone, _, err := getLocalityParam(locality); err != nil || strings.Contains(subZone, "/") {
+		return Warningf("endpoint locality %q must be region/zone/subzone, without empty parts", locality)
+	}
+	return
+}
+
 func getLocalityParam(locality string) (string, string, string, int, error) {
 	var region, zone, subZone string
 	items := strings.SplitN(locality, "/", 3)
@@ -3765,6 +3941,14 @@ var ValidateTelemetry = registerValidateFunc("ValidateTelemetry",
 			validateTelemetryMetrics(spec.Metrics),
 			validateTelemetryTracing(spec.Tracing),
 			validateTelemetryAccessLogging(spec.AccessLogging),
+			validateAccessLogProviderFilters(cfg.Annotations[constants.AccessLogProviderFiltersAnnotation]),
+			validateHistogramBuckets(cfg.Annotations[constants.HistogramBucketsAnnotation]),
+			validateRouteMetricDimensions(cfg.Annotations[constants.RouteMetricDimensionsAnnotation]),
+			validateTelemetryTargetRef(cfg.Annotations, spec),
+			validateMetricExpiry(cfg.Annotations[constants.MetricExpiryAnnotation]),
+			validateRequestOperations(cfg.Annotations[constants.RequestOperationsAnnotation]),
+			validateTraceLabelTags(cfg.Annotations[constants.TraceLabelTagsAnnotation]),
+			validateTraceContextPropagation(cfg.Annotations[constants.TraceContextPropagationAnnotation]),
 		)
 		return errs.Unwrap()
 	})
@@ -3782,6 +3966,98 @@ func validateTelemetryAccessLogging(logging []*telemetry.AccessLogging) (v Valid
 	return
 }
 
+func validateAccessLogProviderFilters(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	filters, err := telemetryconfig.ParseAccessLogProviderFilters(value)
+	if err != nil {
+		return appendValidation(v, err)
+	}
+	for _, f := range filters {
+		if f.Expression != "" {
+			v = appendValidation(v, validateTelemetryFilter(&telemetry.AccessLogging_Filter{Expression: f.Expression}))
+		}
+	}
+	return
+}
+
+func validateHistogramBuckets(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	if _, err := telemetryconfig.ParseHistogramBuckets(value); err != nil {
+		v = appendValidation(v, err)
+	}
+	return
+}
+
+func validateRouteMetricDimensions(value string) (v Validation) {
+	if _, err := telemetryconfig.ParseRouteDimensions(value); err != nil {
+		v = appendValidation(v, err)
+	}
+	return
+}
+
+func validateRequestOperations(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	ops, err := telemetryconfig.ParseRequestOperations(value)
+	if err != nil {
+		return appendValidation(v, err)
+	}
+	return appendValidation(v, validateTagOverrideValue(telemetryconfig.RequestOperationDimension,
+		telemetryconfig.RequestOperationExpression(ops)))
+}
+
+func validateMetricExpiry(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	if _, err := telemetryconfig.ParseMetricExpiry(value); err != nil {
+		v = appendValidation(v, err)
+	}
+	return
+}
+
+func validateTraceLabelTags(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	if _, err := telemetryconfig.ParseTraceLabelTags(value); err != nil {
+		v = appendValidation(v, err)
+	}
+	return
+}
+
+func validateTraceContextPropagation(value string) (v Validation) {
+	if value == "" {
+		return
+	}
+	if _, err := telemetryconfig.ParseTraceContexts(value); err != nil {
+		v = appendValidation(v, err)
+	}
+	return
+}
+
+func validateTelemetryTargetRef(annotations map[string]string, spec *telemetry.Telemetry) (v Validation) {
+	value, f := annotations[constants.TelemetryTargetRefAnnotation]
+	if !f {
+		return
+	}
+	if _, err := telemetryconfig.ParseTargetRef(value); err != nil {
+		return appendValidation(v, err)
+	}
+	if spec.Selector != nil {
+		v = appendErrorf(v, "selector may not be set with the %s annotation", constants.TelemetryTargetRefAnnotation)
+	}
+	if len(spec.Metrics) > 0 || len(spec.AccessLogging) > 0 {
+		v = appendWarningf(v, "only tracing applies to the routes of the %s annotation", constants.TelemetryTargetRefAnnotation)
+	}
+	return
+}
+
 func validateTelemetryTracing(tracing []*telemetry.Tracing) (v Validation) {
 	if len(tracing) > 1 {
 		v = appendWarningf(v, "multiple tracing is not currently supported")
@@ -3847,6 +4123,12 @@ func validateTelemetryMetrics(metrics []*telemetry.Metrics) (v Validation) {
 				case telemetry.MetricsOverrides_TagOverride_UPSERT:
 					if to.Value == "" {
 						v = appendErrorf(v, "tagOverrides.value must be set when operation is UPSERT")
+					} else {
+						v = appendValidation(v, validateTagOverrideValue(tagName, to.Value))
+						if telemetryconfig.IsTCPMetric(o.GetMatch().GetMetric().String()) && telemetryconfig.UsesHTTPAttributes(to.Value) {
+							v = appendWarningf(v, "tagOverrides.value of %s uses request or response attributes, which are not available "+
+								"for TCP metrics; use the connection, upstream, source, destination or filter_state attributes", tagName)
+						}
 					}
 				case telemetry.MetricsOverrides_TagOverride_REMOVE:
 					if to.Value != "" {
@@ -3892,6 +4174,11 @@ var ValidateWasmPlugin = registerValidateFunc("ValidateWasmPlugin",
 			validateWasmPluginVMConfig(spec.VmConfig),
 			validateWasmPluginMatch(spec.Match),
 		)
+		if pull, f := cfg.Annotations[constants.WasmPullAnnotation]; f {
+			if _, err := wasmplugin.ParsePullOptions(pull); err != nil {
+				errs = appendValidation(errs, fmt.Errorf("invalid %s annotation: %v", constants.WasmPullAnnotation, err))
+			}
+		}
 		return errs.Unwrap()
 	})
 
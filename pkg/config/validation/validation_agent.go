@@ -25,3 +25,13 @@ import (
 func validateTelemetryFilter(filter *telemetry.AccessLogging_Filter) error {
 	return nil
 }
+
+// NOP validation that isolated `go-cel` package for istio-agent binary
+func validateCELConditions(key string, exprs []string) error {
+	return nil
+}
+
+// NOP validation that isolated `go-cel` package for istio-agent binary
+func validateTagOverrideValue(tagName, expr string) (v Validation) {
+	return
+}
@@ -35,3 +35,25 @@ func validateTelemetryFilter(filter *telemetry.AccessLogging_Filter) error {
 
 	return nil
 }
+
+func validateCELConditions(key string, exprs []string) error {
+	env, _ := cel.NewEnv()
+	for _, expr := range exprs {
+		if _, issue := env.Parse(expr); issue.Err() != nil {
+			return fmt.Errorf("invalid CEL expression for `key` %s: %w", key, issue.Err())
+		}
+	}
+
+	return nil
+}
+
+// validateTagOverrideValue validates the value of a metric tag override, which is a CEL expression over the
+// attributes of the request and the peers, e.g. `request.url_path.startsWith('/v2/') ? 'v2' : 'v1'`. As the
+// expression is evaluated by the proxy, whose CEL support may differ from the parser here, a failure is a warning.
+func validateTagOverrideValue(tagName, expr string) (v Validation) {
+	env, _ := cel.NewEnv()
+	if _, issue := env.Parse(expr); issue.Err() != nil {
+		v = appendWarningf(v, "tagOverrides.value of %s must be a valid CEL expression, %v", tagName, issue.Err())
+	}
+	return
+}
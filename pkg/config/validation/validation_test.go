@@ -3275,6 +3275,60 @@ func TestValidateVirtualService(t *testing.T) {
 				},
 			}},
 		}, valid: false, warning: false},
+		{name: "jwt claim route with nested claim in brackets", in: &networking.VirtualService{
+			Hosts:    []string{"foo.bar"},
+			Gateways: []string{"ns1/gateway"},
+			Http: []*networking.HTTPRoute{{
+				Route: []*networking.HTTPRouteDestination{{
+					Destination: &networking.Destination{Host: "foo.baz"},
+				}},
+				Match: []*networking.HTTPMatchRequest{
+					{
+						Headers: map[string]*networking.StringMatch{
+							"@request.auth.claims[example.com/roles][id]": {
+								MatchType: &networking.StringMatch_Exact{Exact: "bar"},
+							},
+						},
+					},
+				},
+			}},
+		}, valid: true, warning: false},
+		{name: "jwt claim route with invalid claim", in: &networking.VirtualService{
+			Hosts:    []string{"foo.bar"},
+			Gateways: []string{"ns1/gateway"},
+			Http: []*networking.HTTPRoute{{
+				Route: []*networking.HTTPRouteDestination{{
+					Destination: &networking.Destination{Host: "foo.baz"},
+				}},
+				Match: []*networking.HTTPMatchRequest{
+					{
+						Headers: map[string]*networking.StringMatch{
+							"@request.auth.claims[foo": {
+								MatchType: &networking.StringMatch_Exact{Exact: "bar"},
+							},
+						},
+					},
+				},
+			}},
+		}, valid: false, warning: false},
+		{name: "jwt claim route with empty nested claim", in: &networking.VirtualService{
+			Hosts:    []string{"foo.bar"},
+			Gateways: []string{"ns1/gateway"},
+			Http: []*networking.HTTPRoute{{
+				Route: []*networking.HTTPRouteDestination{{
+					Destination: &networking.Destination{Host: "foo.baz"},
+				}},
+				Match: []*networking.HTTPMatchRequest{
+					{
+						Headers: map[string]*networking.StringMatch{
+							"@request.auth.claims.foo..bar": {
+								MatchType: &networking.StringMatch_Exact{Exact: "bar"},
+							},
+						},
+					},
+				},
+			}},
+		}, valid: false, warning: false},
 	}
 
 	for _, tc := range testCases {
@@ -3312,6 +3366,29 @@ func TestValidateWorkloadEntry(t *testing.T) {
 			in:    &networking.WorkloadEntry{Address: "unix:///lon/google/com", Ports: map[string]uint32{"7777": 7777}},
 			valid: false,
 		},
+		{
+			name:  "valid locality",
+			in:    &networking.WorkloadEntry{Address: "1.2.3.4", Locality: "us-east/zone1/subzone1", Weight: 3},
+			valid: true,
+		},
+		{
+			name:    "empty zone locality",
+			in:      &networking.WorkloadEntry{Address: "1.2.3.4", Locality: "us-east//subzone1"},
+			valid:   true,
+			warning: true,
+		},
+		{
+			name:    "too many parts locality",
+			in:      &networking.WorkloadEntry{Address: "1.2.3.4", Locality: "us-east/zone1/subzone1/rack1"},
+			valid:   true,
+			warning: true,
+		},
+		{
+			name:    "wildcard locality",
+			in:      &networking.WorkloadEntry{Address: "1.2.3.4", Locality: "us-east/*"},
+			valid:   true,
+			warning: true,
+		},
 		{
 			name:  "valid FQDN",
 			in:    &networking.WorkloadEntry{Address: "validdns.com", Ports: map[string]uint32{"7777": 7777}},
@@ -3338,10 +3415,11 @@ func TestValidateWorkloadEntry(t *testing.T) {
 
 func TestValidateWorkloadGroup(t *testing.T) {
 	testCases := []struct {
-		name    string
-		in      proto.Message
-		valid   bool
-		warning bool
+		name        string
+		in          proto.Message
+		annotations map[string]string
+		valid       bool
+		warning     bool
 	}{
 		{
 			name:  "valid",
@@ -3363,6 +3441,33 @@ func TestValidateWorkloadGroup(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "probe grpc",
+			in: &networking.WorkloadGroup{
+				Template: &networking.WorkloadEntry{},
+				Probe:    &networking.ReadinessProbe{PeriodSeconds: 5},
+			},
+			annotations: map[string]string{constants.GRPCProbeAnnotation: `{"port": 50051, "service": "reviews"}`},
+			valid:       true,
+		},
+		{
+			name:        "cleanup policy",
+			in:          &networking.WorkloadGroup{Template: &networking.WorkloadEntry{}},
+			annotations: map[string]string{constants.WorkloadEntryCleanupAnnotation: `{"gracePeriod": "30s", "maxDisconnectDuration": "10m"}`},
+			valid:       true,
+		},
+		{
+			name:        "cleanup policy invalid",
+			in:          &networking.WorkloadGroup{Template: &networking.WorkloadEntry{}},
+			annotations: map[string]string{constants.WorkloadEntryCleanupAnnotation: `{"gracePeriod": "-30s"}`},
+			valid:       false,
+		},
+		{
+			name:        "probe grpc invalid",
+			in:          &networking.WorkloadGroup{Template: &networking.WorkloadEntry{}},
+			annotations: map[string]string{constants.GRPCProbeAnnotation: `{"service": "reviews"}`},
+			valid:       false,
+		},
 		{
 			name: "probe nil",
 			in: &networking.WorkloadGroup{
@@ -3454,7 +3559,7 @@ func TestValidateWorkloadGroup(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			warn, err := ValidateWorkloadGroup(config.Config{Spec: tc.in})
+			warn, err := ValidateWorkloadGroup(config.Config{Meta: config.Meta{Annotations: tc.annotations}, Spec: tc.in})
 			checkValidation(t, warn, err, tc.valid, tc.warning)
 		})
 	}
@@ -4466,6 +4571,30 @@ func TestValidateServiceEntries(t *testing.T) {
 			},
 			valid: true,
 		},
+		{
+			name: "discovery type STATIC, weighted localities", in: &networking.ServiceEntry{
+				Hosts: []string{"weighted.example.com"},
+				Ports: []*networking.ServicePort{{Number: 80, Protocol: "http", Name: "http"}},
+				Endpoints: []*networking.WorkloadEntry{
+					{Address: "1.1.1.1", Locality: "us-east/zone1", Weight: 3},
+					{Address: "2.2.2.2", Locality: "us-west/zone2", Weight: 1},
+				},
+				Resolution: networking.ServiceEntry_STATIC,
+			},
+			valid: true,
+		},
+		{
+			name: "discovery type STATIC, invalid locality", in: &networking.ServiceEntry{
+				Hosts: []string{"weighted.example.com"},
+				Ports: []*networking.ServicePort{{Number: 80, Protocol: "http", Name: "http"}},
+				Endpoints: []*networking.WorkloadEntry{
+					{Address: "1.1.1.1", Locality: "us-east//subzone1", Weight: 3},
+				},
+				Resolution: networking.ServiceEntry_STATIC,
+			},
+			valid:   true,
+			warning: true,
+		},
 		{
 			name: "discovery type DNS Round Robin", in: &networking.ServiceEntry{
 				Hosts: []string{"*.istio.io"},
@@ -5750,6 +5879,39 @@ func TestValidateAuthorizationPolicy(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "cel-condition",
+			in: &security_beta.AuthorizationPolicy{
+				Rules: []*security_beta.Rule{
+					{
+						When: []*security_beta.Condition{
+							{
+								Key:       "request.cel",
+								Values:    []string{"request.headers['x-tenant'] in metadata.filter_metadata['istio_authn']['request.auth.claims']['tenant']"},
+								NotValues: []string{"size(request.headers['x-token']) > 1024"},
+							},
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "cel-condition-invalid",
+			in: &security_beta.AuthorizationPolicy{
+				Rules: []*security_beta.Rule{
+					{
+						When: []*security_beta.Condition{
+							{
+								Key:    "request.cel",
+								Values: []string{"request.headers['x-tenant'] =="},
+							},
+						},
+					},
+				},
+			},
+			valid: false,
+		},
 		{
 			name: "invalid ip and port in ipBlocks",
 			in: &security_beta.AuthorizationPolicy{
@@ -6027,6 +6189,37 @@ func TestValidateAuthorizationPolicy(t *testing.T) {
 			valid:   true,
 			Warning: false,
 		},
+		{
+			name: "target gateway listener",
+			annotations: map[string]string{
+				constants.TargetGatewayAnnotation:     "gateway",
+				constants.TargetSectionNameAnnotation: "admin",
+			},
+			in: &security_beta.AuthorizationPolicy{
+				Rules: []*security_beta.Rule{{From: []*security_beta.Rule_From{{Source: &security_beta.Source{Principals: []string{"sa1"}}}}}},
+			},
+			valid: true,
+		},
+		{
+			name: "target gateway with selector",
+			annotations: map[string]string{
+				constants.TargetGatewayAnnotation: "gateway",
+			},
+			in: &security_beta.AuthorizationPolicy{
+				Selector: &api.WorkloadSelector{
+					MatchLabels: map[string]string{"app": "httpbin"},
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "target section name without gateway",
+			annotations: map[string]string{
+				constants.TargetSectionNameAnnotation: "admin",
+			},
+			in:    &security_beta.AuthorizationPolicy{},
+			valid: false,
+		},
 	}
 
 	for _, c := range cases {
@@ -7529,6 +7722,82 @@ func TestValidateRequestAuthentication(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name:       "nested claim in outputClaimToHeader",
+			configName: constants.DefaultAuthenticationPolicyName,
+			in: &security_beta.RequestAuthentication{
+				JwtRules: []*security_beta.JWTRule{
+					{
+						Issuer:  "foo.com",
+						JwksUri: "https://foo.com",
+						OutputClaimToHeaders: []*security_beta.ClaimToHeader{
+							{
+								Header: "x-jwt-claim",
+								Claim:  "group.id",
+							},
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			name:       "nested claim in brackets in outputClaimToHeader",
+			configName: constants.DefaultAuthenticationPolicyName,
+			in: &security_beta.RequestAuthentication{
+				JwtRules: []*security_beta.JWTRule{
+					{
+						Issuer:  "foo.com",
+						JwksUri: "https://foo.com",
+						OutputClaimToHeaders: []*security_beta.ClaimToHeader{
+							{
+								Header: "x-jwt-claim",
+								Claim:  "[group][id]",
+							},
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		{
+			name:       "empty nested claim in outputClaimToHeader",
+			configName: constants.DefaultAuthenticationPolicyName,
+			in: &security_beta.RequestAuthentication{
+				JwtRules: []*security_beta.JWTRule{
+					{
+						Issuer:  "foo.com",
+						JwksUri: "https://foo.com",
+						OutputClaimToHeaders: []*security_beta.ClaimToHeader{
+							{
+								Header: "x-jwt-claim",
+								Claim:  "group..id",
+							},
+						},
+					},
+				},
+			},
+			valid: false,
+		},
+		{
+			name:       "nested claim with dot in outputClaimToHeader",
+			configName: constants.DefaultAuthenticationPolicyName,
+			in: &security_beta.RequestAuthentication{
+				JwtRules: []*security_beta.JWTRule{
+					{
+						Issuer:  "foo.com",
+						JwksUri: "https://foo.com",
+						OutputClaimToHeaders: []*security_beta.ClaimToHeader{
+							{
+								Header: "x-jwt-claim",
+								Claim:  "[example.com][id]",
+							},
+						},
+					},
+				},
+			},
+			valid: false,
+		},
 	}
 
 	for _, c := range cases {
@@ -8057,6 +8326,88 @@ func TestValidateTelemetry(t *testing.T) {
 			},
 			"", "",
 		},
+		{
+			"metrics tag value from request attributes",
+			&telemetry.Telemetry{
+				Metrics: []*telemetry.Metrics{{
+					Overrides: []*telemetry.MetricsOverrides{
+						{
+							TagOverrides: map[string]*telemetry.MetricsOverrides_TagOverride{
+								"api_version": {
+									Operation: telemetry.MetricsOverrides_TagOverride_UPSERT,
+									Value:     "request.url_path.startsWith('/v2/') ? 'v2' : 'v1'",
+								},
+							},
+						},
+					},
+				}},
+			},
+			"", "",
+		},
+		{
+			"tcp metrics tag value from filter state",
+			&telemetry.Telemetry{
+				Metrics: []*telemetry.Metrics{{
+					Overrides: []*telemetry.MetricsOverrides{
+						{
+							Match: &telemetry.MetricSelector{
+								MetricMatch: &telemetry.MetricSelector_Metric{Metric: telemetry.MetricSelector_TCP_SENT_BYTES},
+							},
+							TagOverrides: map[string]*telemetry.MetricsOverrides_TagOverride{
+								"sni": {
+									Operation: telemetry.MetricsOverrides_TagOverride_UPSERT,
+									Value:     "connection.requested_server_name",
+								},
+								"tenant": {
+									Operation: telemetry.MetricsOverrides_TagOverride_UPSERT,
+									Value:     "filter_state['tenant']",
+								},
+							},
+						},
+					},
+				}},
+			},
+			"", "",
+		},
+		{
+			"tcp metrics tag value from request attributes",
+			&telemetry.Telemetry{
+				Metrics: []*telemetry.Metrics{{
+					Overrides: []*telemetry.MetricsOverrides{
+						{
+							Match: &telemetry.MetricSelector{
+								MetricMatch: &telemetry.MetricSelector_Metric{Metric: telemetry.MetricSelector_TCP_OPENED_CONNECTIONS},
+							},
+							TagOverrides: map[string]*telemetry.MetricsOverrides_TagOverride{
+								"path": {
+									Operation: telemetry.MetricsOverrides_TagOverride_UPSERT,
+									Value:     "request.url_path",
+								},
+							},
+						},
+					},
+				}},
+			},
+			"", "not available for TCP metrics",
+		},
+		{
+			"invalid metrics tag value",
+			&telemetry.Telemetry{
+				Metrics: []*telemetry.Metrics{{
+					Overrides: []*telemetry.MetricsOverrides{
+						{
+							TagOverrides: map[string]*telemetry.MetricsOverrides_TagOverride{
+								"api_version": {
+									Operation: telemetry.MetricsOverrides_TagOverride_UPSERT,
+									Value:     "request.url_path.startsWith('/v2/' ?",
+								},
+							},
+						},
+					},
+				}},
+			},
+			"", "tagOverrides.value of api_version must be a valid CEL expression",
+		},
 		{
 			"multi-accessloggings",
 			&telemetry.Telemetry{
@@ -8185,6 +8536,173 @@ func TestValidateTelemetryFilter(t *testing.T) {
 	}
 }
 
+func TestValidateAccessLogProviderFilters(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{value: "", valid: true},
+		{value: `{"stdout": {"minLevel": "ERROR", "expression": "request.path != '/health'"}}`, valid: true},
+		{value: `{"stdout": {"minLevel": "DEBUG"}}`, valid: false},
+		{value: `{"stdout": {"expression": ")++++"}}`, valid: false},
+		{value: `not json`, valid: false},
+	}
+	for _, tc := range cases {
+		t.Run("", func(t *testing.T) {
+			_, err := validateAccessLogProviderFilters(tc.value).Unwrap()
+			if tc.valid != (err == nil) {
+				t.Errorf("validateAccessLogProviderFilters(%v): expected valid %v, got %v", tc.value, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateTelemetryTargetRef(t *testing.T) {
+	ref := map[string]string{constants.TelemetryTargetRefAnnotation: `{"kind": "VirtualService", "name": "reviews"}`}
+	sampling := []*telemetry.Tracing{{RandomSamplingPercentage: &wrapperspb.DoubleValue{Value: 100}}}
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		spec        *telemetry.Telemetry
+		err         string
+		warning     string
+	}{
+		{"no annotation", nil, &telemetry.Telemetry{Selector: &api.WorkloadSelector{}}, "", ""},
+		{"valid", ref, &telemetry.Telemetry{Tracing: sampling}, "", ""},
+		{
+			"invalid kind",
+			map[string]string{constants.TelemetryTargetRefAnnotation: `{"kind": "Service", "name": "reviews"}`},
+			&telemetry.Telemetry{Tracing: sampling},
+			"invalid target ref kind", "",
+		},
+		{"selector", ref, &telemetry.Telemetry{Selector: &api.WorkloadSelector{}, Tracing: sampling}, "selector may not be set", ""},
+		{"metrics", ref, &telemetry.Telemetry{Metrics: []*telemetry.Metrics{{}}}, "", "only tracing applies"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warn, err := validateTelemetryTargetRef(tc.annotations, tc.spec).Unwrap()
+			checkValidationMessage(t, warn, err, tc.warning, tc.err)
+		})
+	}
+}
+
+func TestValidateRequestOperations(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{value: "", valid: true},
+		{value: `[{"name": "GetReviews", "method": "GET", "path": "/books/*/reviews"}, {"name": "Static", "path": "/static/**"}]`, valid: true},
+		{value: `[{"name": "Any"}]`, valid: false},
+		{value: `[{"name": "Quote", "method": "GET'"}]`, valid: false},
+	}
+	for _, tc := range cases {
+		t.Run("", func(t *testing.T) {
+			_, err := validateRequestOperations(tc.value).Unwrap()
+			if tc.valid != (err == nil) {
+				t.Errorf("validateRequestOperations(%v): expected valid %v, got %v", tc.value, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateTraceLabelTags(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{value: "", valid: true},
+		{value: "team=team,build=app.kubernetes.io/version", valid: true},
+		{value: "team", valid: false},
+		{value: "team=not a label", valid: false},
+	}
+	for _, tc := range cases {
+		t.Run("", func(t *testing.T) {
+			_, err := validateTraceLabelTags(tc.value).Unwrap()
+			if tc.valid != (err == nil) {
+				t.Errorf("validateTraceLabelTags(%v): expected valid %v, got %v", tc.value, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateTraceContextPropagation(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{value: "", valid: true},
+		{value: "W3C_TRACE_CONTEXT,B3", valid: true},
+		{value: "DATADOG", valid: false},
+		{value: "UNSPECIFIED", valid: false},
+	}
+	for _, tc := range cases {
+		t.Run("", func(t *testing.T) {
+			_, err := validateTraceContextPropagation(tc.value).Unwrap()
+			if tc.valid != (err == nil) {
+				t.Errorf("validateTraceContextPropagation(%v): expected valid %v, got %v", tc.value, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateClusterLoadBalancing(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		valid       bool
+	}{
+		{name: "none", annotations: nil, valid: true},
+		{name: "weights", annotations: map[string]string{constants.ClusterWeightsAnnotation: "cluster-1=80,cluster-2=20"}, valid: true},
+		{name: "zero weight", annotations: map[string]string{constants.ClusterWeightsAnnotation: "cluster-1=100,cluster-2=0"}, valid: true},
+		{name: "all zero weights", annotations: map[string]string{constants.ClusterWeightsAnnotation: "cluster-1=0"}, valid: false},
+		{name: "negative weight", annotations: map[string]string{constants.ClusterWeightsAnnotation: "cluster-1=-1"}, valid: false},
+		{name: "missing weight", annotations: map[string]string{constants.ClusterWeightsAnnotation: "cluster-1"}, valid: false},
+		{name: "failover", annotations: map[string]string{constants.ClusterFailoverAnnotation: "cluster-1,cluster-2"}, valid: true},
+		{name: "duplicate failover", annotations: map[string]string{constants.ClusterFailoverAnnotation: "cluster-1,cluster-1"}, valid: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateDestinationRule(config.Config{
+				Meta: config.Meta{Name: "reviews", Namespace: "default", Annotations: tc.annotations},
+				Spec: &networking.DestinationRule{Host: "reviews"},
+			})
+			if tc.valid != (err == nil) {
+				t.Errorf("ValidateDestinationRule(%v): expected valid %v, got %v", tc.annotations, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateClusterSelector(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		valid       bool
+	}{
+		{name: "none", annotations: nil, valid: true},
+		{name: "clusters", annotations: map[string]string{constants.ClusterSelectorAnnotation: "cluster-1,cluster-2"}, valid: true},
+		{name: "empty", annotations: map[string]string{constants.ClusterSelectorAnnotation: ""}, valid: false},
+		{name: "duplicate", annotations: map[string]string{constants.ClusterSelectorAnnotation: "cluster-1,cluster-1"}, valid: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := config.Meta{Name: "reviews", Namespace: "default", Annotations: tc.annotations}
+			_, err := ValidateDestinationRule(config.Config{Meta: meta, Spec: &networking.DestinationRule{Host: "reviews"}})
+			if tc.valid != (err == nil) {
+				t.Errorf("ValidateDestinationRule(%v): expected valid %v, got %v", tc.annotations, tc.valid, err)
+			}
+			_, err = ValidateVirtualService(config.Config{Meta: meta, Spec: &networking.VirtualService{
+				Hosts: []string{"reviews"},
+				Http:  []*networking.HTTPRoute{{Route: []*networking.HTTPRouteDestination{{Destination: &networking.Destination{Host: "reviews"}}}}},
+			}})
+			if tc.valid != (err == nil) {
+				t.Errorf("ValidateVirtualService(%v): expected valid %v, got %v", tc.annotations, tc.valid, err)
+			}
+		})
+	}
+}
+
 func TestValidateWasmPlugin(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -8303,6 +8821,97 @@ func TestValidateWasmPlugin(t *testing.T) {
 	}
 }
 
+func TestValidateWasmPluginPullAnnotation(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		out   string
+	}{
+		{"valid", `{"timeout": "1m", "retries": 3}`, ""},
+		{"invalid timeout", `{"timeout": "1"}`, "invalid extensions.istio.io/wasm-pull annotation"},
+		{"invalid retries", `{"retries": "3"}`, "invalid extensions.istio.io/wasm-pull annotation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := ValidateWasmPlugin(config.Config{
+				Meta: config.Meta{
+					Name:        someName,
+					Namespace:   someNamespace,
+					Annotations: map[string]string{constants.WasmPullAnnotation: tt.value},
+				},
+				Spec: &extensions.WasmPlugin{Url: "https://test.com/test"},
+			})
+			checkValidationMessage(t, warn, err, "", tt.out)
+		})
+	}
+}
+
+func TestValidateEnvoyFilterTargetRef(t *testing.T) {
+	patches := []*networking.EnvoyFilter_EnvoyConfigObjectPatch{{
+		ApplyTo: networking.EnvoyFilter_CLUSTER,
+		Patch:   &networking.EnvoyFilter_Patch{Operation: networking.EnvoyFilter_Patch_REMOVE},
+	}}
+	tests := []struct {
+		name     string
+		value    string
+		selector *networking.WorkloadSelector
+		out      string
+	}{
+		{"gateway", `{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "ingress"}`, nil, ""},
+		{"service", `{"kind": "Service", "name": "reviews"}`, nil, ""},
+		{"invalid", `{"kind": "Gateway", "name": "ingress"}`, nil, "invalid target ref"},
+		{
+			"selector", `{"kind": "Service", "name": "reviews"}`,
+			&networking.WorkloadSelector{Labels: map[string]string{"app": "reviews"}}, "workloadSelector may not be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := ValidateEnvoyFilter(config.Config{
+				Meta: config.Meta{
+					Name:        someName,
+					Namespace:   someNamespace,
+					Annotations: map[string]string{constants.EnvoyFilterTargetRefAnnotation: tt.value},
+				},
+				Spec: &networking.EnvoyFilter{WorkloadSelector: tt.selector, ConfigPatches: patches},
+			})
+			checkValidationMessage(t, warn, err, "", tt.out)
+		})
+	}
+}
+
+func TestValidateEnvoyFilterProxyVersion(t *testing.T) {
+	test.SetForTest(t, &istiodVersion, "1.18.2")
+	unknownField := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"unknown_field": {Kind: &structpb.Value_StringValue{StringValue: "value"}},
+	}}
+	tests := []struct {
+		name         string
+		proxyVersion string
+		warning      string
+		error        string
+	}{
+		{"unscoped", "", "unknown field", ""},
+		{"istiod version", `^1\.18.*`, "", "unknown field"},
+		{"other version", `^1\.17.*`, `does not apply to the proxies of version 1.18.2`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := ValidateEnvoyFilter(config.Config{
+				Meta: config.Meta{Name: someName, Namespace: someNamespace},
+				Spec: &networking.EnvoyFilter{ConfigPatches: []*networking.EnvoyFilter_EnvoyConfigObjectPatch{{
+					ApplyTo: networking.EnvoyFilter_CLUSTER,
+					Match: &networking.EnvoyFilter_EnvoyConfigObjectMatch{
+						Proxy: &networking.EnvoyFilter_ProxyMatch{ProxyVersion: tt.proxyVersion},
+					},
+					Patch: &networking.EnvoyFilter_Patch{Operation: networking.EnvoyFilter_Patch_MERGE, Value: unknownField},
+				}}},
+			})
+			checkValidationMessage(t, warn, err, tt.warning, tt.error)
+		})
+	}
+}
+
 func TestRecurseMissingTypedConfig(t *testing.T) {
 	good := &listener.Filter{
 		Name:       wellknown.TCPProxy,
@@ -8369,3 +8978,39 @@ func TestValidateHTTPHeaderValue(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDynamicForwardProxy(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		endpoints   []*networking.WorkloadEntry
+		valid       bool
+	}{
+		{name: "no endpoints", annotations: nil, valid: false},
+		{name: "opted in", annotations: map[string]string{constants.DynamicForwardProxyAnnotation: "true"}, valid: true},
+		{name: "opted out", annotations: map[string]string{constants.DynamicForwardProxyAnnotation: "false"}, valid: false},
+		{name: "invalid", annotations: map[string]string{constants.DynamicForwardProxyAnnotation: "yes"}, valid: false},
+		{
+			name:        "endpoints",
+			annotations: map[string]string{constants.DynamicForwardProxyAnnotation: "false"},
+			endpoints:   []*networking.WorkloadEntry{{Address: "lon.example.com"}},
+			valid:       true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValidateServiceEntry(config.Config{
+				Meta: config.Meta{Name: "wildcard", Namespace: "default", Annotations: tc.annotations},
+				Spec: &networking.ServiceEntry{
+					Hosts:      []string{"*.example.com"},
+					Ports:      []*networking.ServicePort{{Number: 80, Protocol: "http", Name: "http"}},
+					Endpoints:  tc.endpoints,
+					Resolution: networking.ServiceEntry_DNS,
+				},
+			})
+			if tc.valid != (err == nil) {
+				t.Errorf("ValidateServiceEntry(%v): expected valid %v, got %v", tc.annotations, tc.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wasmplugin parses the settings of the WasmPlugins set through annotations.
+package wasmplugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PullOptions controls the fetching of the module of a WasmPlugin by the proxies. The unset options are nil.
+type PullOptions struct {
+	// Timeout bounds the time taken to fetch the module, including the retries.
+	Timeout *time.Duration
+	// Retries is the number of retries of the failed HTTP requests fetching a module over http or https.
+	Retries *uint32
+}
+
+// ParsePullOptions parses the value of the wasm-pull annotation of a WasmPlugin, a JSON object with the timeout
+// duration and the number of retries, for example {"timeout": "1m", "retries": 3}.
+func ParsePullOptions(value string) (PullOptions, error) {
+	var raw struct {
+		Timeout string  `json:"timeout"`
+		Retries *uint32 `json:"retries"`
+	}
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return PullOptions{}, err
+	}
+	o := PullOptions{Retries: raw.Retries}
+	if raw.Timeout != "" {
+		d, err := time.ParseDuration(raw.Timeout)
+		if err != nil {
+			return PullOptions{}, fmt.Errorf("invalid timeout: %v", err)
+		}
+		if d <= 0 {
+			return PullOptions{}, fmt.Errorf("invalid timeout %q: must be positive", raw.Timeout)
+		}
+		o.Timeout = &d
+	}
+	return o, nil
+}
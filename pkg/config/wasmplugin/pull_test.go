@@ -0,0 +1,51 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmplugin
+
+import (
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParsePullOptions(t *testing.T) {
+	cases := []struct {
+		value string
+		want  PullOptions
+		err   bool
+	}{
+		{value: `{}`, want: PullOptions{}},
+		{value: `{"timeout": "1m"}`, want: PullOptions{Timeout: ptr.Of(time.Minute)}},
+		{value: `{"timeout": "10s", "retries": 0}`, want: PullOptions{Timeout: ptr.Of(10 * time.Second), Retries: ptr.Of(uint32(0))}},
+		{value: `{"retries": 3}`, want: PullOptions{Retries: ptr.Of(uint32(3))}},
+		{value: `{"timeout": "10"}`, err: true},
+		{value: `{"timeout": "-1s"}`, err: true},
+		{value: `{"retries": -1}`, err: true},
+		{value: `1m`, err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParsePullOptions(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
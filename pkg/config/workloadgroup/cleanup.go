@@ -0,0 +1,69 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workloadgroup parses the settings of the WorkloadGroups set through annotations.
+package workloadgroup
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CleanupPolicy controls the garbage collection of the WorkloadEntries auto-registered for a WorkloadGroup.
+// The unset durations are 0.
+type CleanupPolicy struct {
+	// GracePeriod is how long the entry of a disconnected workload is kept, for the workload to reconnect, before
+	// it is deleted.
+	GracePeriod time.Duration
+	// MaxDisconnectDuration is how long the entry of a workload is kept when its disconnection could not be
+	// recorded, for example because its istiod stopped at the same time, in addition to the max connection age of
+	// istiod: the workloads reconnect at least once per max connection age.
+	MaxDisconnectDuration time.Duration
+}
+
+// ParseCleanupPolicy parses the value of the workload-entry-cleanup annotation of a WorkloadGroup, a JSON object with
+// the gracePeriod and maxDisconnectDuration durations, for example {"gracePeriod": "30s", "maxDisconnectDuration": "1h"}.
+func ParseCleanupPolicy(value string) (CleanupPolicy, error) {
+	var raw struct {
+		GracePeriod           string `json:"gracePeriod"`
+		MaxDisconnectDuration string `json:"maxDisconnectDuration"`
+	}
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return CleanupPolicy{}, err
+	}
+	var p CleanupPolicy
+	var err error
+	if p.GracePeriod, err = parseDuration("gracePeriod", raw.GracePeriod); err != nil {
+		return CleanupPolicy{}, err
+	}
+	if p.MaxDisconnectDuration, err = parseDuration("maxDisconnectDuration", raw.MaxDisconnectDuration); err != nil {
+		return CleanupPolicy{}, err
+	}
+	return p, nil
+}
+
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be positive", name, value)
+	}
+	return d, nil
+}
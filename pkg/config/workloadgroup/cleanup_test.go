@@ -0,0 +1,52 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadgroup
+
+import (
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestParseCleanupPolicy(t *testing.T) {
+	cases := []struct {
+		value string
+		want  CleanupPolicy
+		err   bool
+	}{
+		{value: `{}`, want: CleanupPolicy{}},
+		{value: `{"gracePeriod": "30s"}`, want: CleanupPolicy{GracePeriod: 30 * time.Second}},
+		{
+			value: `{"gracePeriod": "1m", "maxDisconnectDuration": "2h"}`,
+			want:  CleanupPolicy{GracePeriod: time.Minute, MaxDisconnectDuration: 2 * time.Hour},
+		},
+		{value: `{"gracePeriod": "30"}`, err: true},
+		{value: `{"gracePeriod": "-1s"}`, err: true},
+		{value: `{"maxDisconnectDuration": "0s"}`, err: true},
+		{value: `30s`, err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseCleanupPolicy(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
@@ -20,12 +20,14 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/miekg/dns"
+	"golang.org/x/exp/slices"
 
 	"istio.io/istio/pilot/pkg/serviceregistry/provider"
 	"istio.io/istio/pkg/config/host"
@@ -77,6 +79,10 @@ type LookupTable struct {
 	// The cname records here (comprised of different variants of the hosts above,
 	// expanded by the search namespaces) pointing to the actual host.
 	cname map[string][]dns.RR
+
+	// The conditional forwarding rules of the queries not found in the table, sorted
+	// by decreasing length of their domain so the most specific rule matches first.
+	forwarding []*dnsProto.NameTable_ForwardingRule
 }
 
 const (
@@ -194,6 +200,15 @@ func (h *LocalDNSServer) UpdateLookupTable(nt *dnsProto.NameTable) {
 		cname:    map[string][]dns.RR{},
 	}
 	h.BuildAlternateHosts(nt, lookupTable.buildDNSAnswers)
+	lookupTable.forwarding = make([]*dnsProto.NameTable_ForwardingRule, 0, len(nt.ForwardingRules))
+	for _, rule := range nt.ForwardingRules {
+		if rule.Domain != "" && len(rule.Servers) > 0 {
+			lookupTable.forwarding = append(lookupTable.forwarding, rule)
+		}
+	}
+	sort.SliceStable(lookupTable.forwarding, func(i, j int) bool {
+		return len(lookupTable.forwarding[i].Domain) > len(lookupTable.forwarding[j].Domain)
+	})
 	h.lookupTable.Store(lookupTable)
 	h.nameTable.Store(nt)
 	log.Debugf("updated lookup table with %d hosts", len(lookupTable.allHosts))
@@ -204,6 +219,7 @@ func (h *LocalDNSServer) UpdateLookupTable(nt *dnsProto.NameTable) {
 func (h *LocalDNSServer) BuildAlternateHosts(nt *dnsProto.NameTable,
 	apply func(map[string]struct{}, []netip.Addr, []netip.Addr, []string),
 ) {
+	searchNamespaces := h.expandedSearchNamespaces(nt)
 	for hostname, ni := range nt.Table {
 		// Given a host
 		// if its a non-k8s host, store the host+. as the key with the pre-computed DNS RR records
@@ -223,17 +239,34 @@ func (h *LocalDNSServer) BuildAlternateHosts(nt *dnsProto.NameTable,
 			// malformed ips
 			continue
 		}
-		apply(altHosts, ipv4, ipv6, h.searchNamespaces)
+		apply(altHosts, ipv4, ipv6, searchNamespaces)
 	}
 }
 
+// expandedSearchNamespaces returns the search namespaces the hosts of the name table are expanded with: the first
+// search namespace of resolv.conf, as most clients do sequential dns resolution starting with it, and the search
+// domains pushed by istiod.
+func (h *LocalDNSServer) expandedSearchNamespaces(nt *dnsProto.NameTable) []string {
+	out := make([]string, 0, 1+len(nt.SearchDomains))
+	if len(h.searchNamespaces) > 0 {
+		out = append(out, strings.TrimSuffix(h.searchNamespaces[0], "."))
+	}
+	for _, domain := range nt.SearchDomains {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+		if domain != "" && !slices.Contains(out, domain) {
+			out = append(out, domain)
+		}
+	}
+	return out
+}
+
 // upstream sends the request to the upstream server, with associated logs and metrics
-func (h *LocalDNSServer) upstream(proxy *dnsProxy, req *dns.Msg, hostname string) *dns.Msg {
+func (h *LocalDNSServer) upstream(proxy *dnsProxy, req *dns.Msg, hostname string, servers []string) *dns.Msg {
 	upstreamRequests.Increment()
 	start := time.Now()
 	// We did not find the host in our internal cache. Query upstream and return the response as is.
 	log.Debugf("response for hostname %q not found in dns proxy, querying upstream", hostname)
-	response := h.queryUpstream(proxy.upstreamClient, req, log)
+	response := h.queryUpstream(proxy.upstreamClient, req, servers, log)
 	requestDuration.Record(time.Since(start).Seconds())
 	log.Debugf("upstream response for hostname %q : %v", hostname, response)
 	return response
@@ -262,7 +295,7 @@ func (h *LocalDNSServer) ServeDNS(proxy *dnsProxy, w dns.ResponseWriter, req *dn
 	hostname := strings.ToLower(req.Question[0].Name)
 	if lp == nil {
 		if h.respondBeforeSync {
-			response = h.upstream(proxy, req, hostname)
+			response = h.upstream(proxy, req, hostname, h.resolvConfServers)
 			response.Truncate(size(proxy.protocol, req))
 			_ = w.WriteMsg(response)
 		} else {
@@ -300,7 +333,7 @@ func (h *LocalDNSServer) ServeDNS(proxy *dnsProxy, w dns.ResponseWriter, req *dn
 		}
 		log.Debugf("response for hostname %q (found=true): %v", hostname, response)
 	} else {
-		response = h.upstream(proxy, req, hostname)
+		response = h.upstream(proxy, req, hostname, lookupTable.upstreamServers(hostname, h.resolvConfServers))
 	}
 	// Compress the response - we don't know if the incoming response was compressed or not. If it was,
 	// but we don't compress on the outbound, we will run into issues. For example, if the compressed
@@ -390,14 +423,14 @@ func (h *LocalDNSServer) Close() {
 	}
 }
 
-func (h *LocalDNSServer) queryUpstream(upstreamClient *dns.Client, req *dns.Msg, scope *istiolog.Scope) *dns.Msg {
+func (h *LocalDNSServer) queryUpstream(upstreamClient *dns.Client, req *dns.Msg, servers []string, scope *istiolog.Scope) *dns.Msg {
 	if h.forwardToUpstreamParallel {
-		return h.queryUpstreamParallel(upstreamClient, req, scope)
+		return h.queryUpstreamParallel(upstreamClient, req, servers, scope)
 	}
 
 	var response *dns.Msg
 
-	for _, upstream := range h.resolvConfServers {
+	for _, upstream := range servers {
 		cResponse, _, err := upstreamClient.Exchange(req, upstream)
 		if err == nil {
 			response = cResponse
@@ -422,7 +455,7 @@ func (h *LocalDNSServer) queryUpstream(upstreamClient *dns.Client, req *dns.Msg,
 //     response—or defer to the operating system, which we have no control over.
 //   - systemd-resolved: which is used as a default resolver in many Linux distributions nowadays also performs parallel
 //     lookups for multiple DNS servers and returns the first successful response.
-func (h *LocalDNSServer) queryUpstreamParallel(upstreamClient *dns.Client, req *dns.Msg, scope *istiolog.Scope) *dns.Msg {
+func (h *LocalDNSServer) queryUpstreamParallel(upstreamClient *dns.Client, req *dns.Msg, servers []string, scope *istiolog.Scope) *dns.Msg {
 	// Guarantee that the ctx we use below is done when this function returns.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -448,7 +481,7 @@ func (h *LocalDNSServer) queryUpstreamParallel(upstreamClient *dns.Client, req *
 		}
 	}
 
-	for _, upstream := range h.resolvConfServers {
+	for _, upstream := range servers {
 		go queryOne(upstream)
 	}
 
@@ -461,7 +494,7 @@ func (h *LocalDNSServer) queryUpstreamParallel(upstreamClient *dns.Client, req *
 		case <-errCh:
 			errorsCount++
 			// All servers returned error - return failure.
-			if errorsCount == len(h.resolvConfServers) {
+			if errorsCount == len(servers) {
 				scope.Infof("all upstream failed")
 				return serverFailure(req)
 			}
@@ -505,6 +538,18 @@ func generateAltHosts(hostname string, nameinfo *dnsProto.NameTable_NameInfo, pr
 	return out
 }
 
+// upstreamServers returns the servers the query of the host not found in the table is forwarded to: the servers of
+// the most specific forwarding rule matching the host, or the default servers.
+func (table *LookupTable) upstreamServers(hostname string, defaultServers []string) []string {
+	hostname = strings.TrimSuffix(hostname, ".")
+	for _, rule := range table.forwarding {
+		if hostname == rule.Domain || strings.HasSuffix(hostname, "."+rule.Domain) {
+			return rule.Servers
+		}
+	}
+	return defaultServers
+}
+
 // Given a host, this function first decides if the host is part of our service registry.
 // If it is not part of the registry, return nil so that caller queries upstream. If it is part
 // of registry, we will look it up in one of our tables, failing which we will return NXDOMAIN.
@@ -604,15 +649,15 @@ func (table *LookupTable) buildDNSAnswers(altHosts map[string]struct{}, ipv4 []n
 		if len(ipv6) > 0 {
 			table.name6[h] = aaaa(h, ipv6)
 		}
-		if len(searchNamespaces) > 0 {
-			// NOTE: Right now, rather than storing one expanded host for each one of the search namespace
-			// entries, we are going to store just the first one (assuming that most clients will
-			// do sequential dns resolution, starting with the first search namespace)
-
+		// NOTE: Right now, rather than storing one expanded host for each one of the search namespace
+		// entries of resolv.conf, we are going to store just the first one (assuming that most clients will
+		// do sequential dns resolution, starting with the first search namespace), and the search domains
+		// pushed by istiod.
+		for _, searchNamespace := range searchNamespaces {
 			// host h already ends with a .
 			// search namespace might not. So we append one in the end if needed
-			expandedHost := strings.ToLower(h + searchNamespaces[0])
-			if !strings.HasSuffix(searchNamespaces[0], ".") {
+			expandedHost := strings.ToLower(h + searchNamespace)
+			if !strings.HasSuffix(searchNamespace, ".") {
 				expandedHost += "."
 			}
 			// make sure this is not a proper hostname
@@ -399,6 +399,54 @@ var giantResponse = func() []dns.RR {
 	return a("aaaaaaaaaaaa.aaaaaa.", ips)
 }()
 
+func TestDNSForwardingRulesAndSearchDomains(t *testing.T) {
+	d := initDNS(t, false)
+	corp := makeUpstream(t, map[string]string{"db.corp.example.com.": "5.5.5.5"})
+	d.UpdateLookupTable(&dnsProto.NameTable{
+		Table: map[string]*dnsProto.NameTable_NameInfo{
+			"www.google.com": {
+				Ips:      []string{"1.1.1.1"},
+				Registry: "External",
+			},
+		},
+		SearchDomains: []string{"corp.example.com."},
+		ForwardingRules: []*dnsProto.NameTable_ForwardingRule{
+			{Domain: "example.com", Servers: []string{"127.0.0.1:1"}},
+			{Domain: "corp.example.com", Servers: []string{corp}},
+		},
+	})
+
+	query := func(hostname string) *dns.Msg {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetQuestion(hostname, dns.TypeA)
+		res, _, err := (&dns.Client{Timeout: 3 * time.Second}).Exchange(m, d.dnsProxies[0].Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// The queries of the domain are forwarded to the servers of its most specific rule.
+	res := query("db.corp.example.com.")
+	if !equalsDNSrecords(res.Answer, a("db.corp.example.com.", []netip.Addr{netip.MustParseAddr("5.5.5.5")})) {
+		t.Fatalf("unexpected answer %v", res.Answer)
+	}
+	// The other queries are forwarded to the servers of resolv.conf.
+	res = query("www.bing.com.")
+	if !equalsDNSrecords(res.Answer, a("www.bing.com.", []netip.Addr{netip.MustParseAddr("1.1.1.1")})) {
+		t.Fatalf("unexpected answer %v", res.Answer)
+	}
+	// The hosts are expanded with the pushed search domains, as well as the first search namespace of resolv.conf.
+	for _, expanded := range []string{"www.google.com.corp.example.com.", "www.google.com.ns1.svc.cluster.local."} {
+		res = query(expanded)
+		want := append(cname(expanded, "www.google.com."), a("www.google.com.", []netip.Addr{netip.MustParseAddr("1.1.1.1")})...)
+		if !equalsDNSrecords(res.Answer, want) {
+			t.Fatalf("unexpected answer for %s: %v", expanded, res.Answer)
+		}
+	}
+}
+
 func makeUpstream(t test.Failer, responses map[string]string) string {
 	mux := dns.NewServeMux()
 	mux.HandleFunc(".", func(resp dns.ResponseWriter, msg *dns.Msg) {
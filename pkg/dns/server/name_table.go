@@ -15,6 +15,8 @@
 package server
 
 import (
+	"fmt"
+	"net"
 	"strings"
 
 	"istio.io/istio/pilot/pkg/model"
@@ -32,6 +34,12 @@ type Config struct {
 	// MulticlusterHeadlessEnabled if true, the DNS name table for a headless service will resolve to
 	// same-network endpoints in any cluster.
 	MulticlusterHeadlessEnabled bool
+
+	// SearchDomains are the search domains the agent expands the hosts of the table with.
+	SearchDomains []string
+
+	// ForwardingRules are the conditional forwarding rules of the agent.
+	ForwardingRules []*dnsProto.NameTable_ForwardingRule
 }
 
 // BuildNameTable produces a table of hostnames and their associated IPs that can then
@@ -44,7 +52,9 @@ func BuildNameTable(cfg Config) *dnsProto.NameTable {
 	}
 
 	out := &dnsProto.NameTable{
-		Table: make(map[string]*dnsProto.NameTable_NameInfo),
+		Table:           make(map[string]*dnsProto.NameTable_NameInfo),
+		SearchDomains:   cfg.SearchDomains,
+		ForwardingRules: cfg.ForwardingRules,
 	}
 	for _, svc := range cfg.Node.SidecarScope.Services() {
 		svcAddress := svc.GetAddressForProxy(cfg.Node)
@@ -148,3 +158,37 @@ func BuildNameTable(cfg Config) *dnsProto.NameTable {
 	}
 	return out
 }
+
+// ParseForwardingRules parses the conditional forwarding rules of the DNS proxy of the agents, a comma separated
+// list of domain=server entries, e.g. "corp.example.com=10.0.0.10,corp.example.com=10.0.0.11:5353". The rules of
+// the same domain are merged, the port of the servers defaults to 53.
+func ParseForwardingRules(s string) ([]*dnsProto.NameTable_ForwardingRule, error) {
+	var rules []*dnsProto.NameTable_ForwardingRule
+	byDomain := map[string]*dnsProto.NameTable_ForwardingRule{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		domain, server, ok := strings.Cut(entry, "=")
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		server = strings.TrimSpace(server)
+		if !ok || domain == "" || server == "" {
+			return nil, fmt.Errorf("invalid forwarding rule %q, expected domain=server", entry)
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		if host, _, err := net.SplitHostPort(server); err != nil || !netutil.IsValidIPAddress(host) {
+			return nil, fmt.Errorf("invalid server %q of forwarding rule %q, expected ip or ip:port", server, entry)
+		}
+		rule, f := byDomain[domain]
+		if !f {
+			rule = &dnsProto.NameTable_ForwardingRule{Domain: domain}
+			byDomain[domain] = rule
+			rules = append(rules, rule)
+		}
+		rule.Servers = append(rule.Servers, server)
+	}
+	return rules, nil
+}
@@ -517,6 +517,25 @@ func TestNameTable(t *testing.T) {
 	}
 }
 
+func TestParseForwardingRules(t *testing.T) {
+	rules, err := dnsServer.ParseForwardingRules("Corp.Example.com.=10.0.0.10, example.org=1.1.1.1:5353,corp.example.com=::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*dnsProto.NameTable_ForwardingRule{
+		{Domain: "corp.example.com", Servers: []string{"10.0.0.10:53", "[::1]:53"}},
+		{Domain: "example.org", Servers: []string{"1.1.1.1:5353"}},
+	}
+	if diff := cmp.Diff(rules, want, protocmp.Transform()); diff != "" {
+		t.Fatalf("got diff: %v", diff)
+	}
+	for _, invalid := range []string{"example.org", "=1.1.1.1", "example.org=dns.example.org"} {
+		if _, err := dnsServer.ParseForwardingRules(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
 func makeInstances(proxy *model.Proxy, svc *model.Service, servicePort int, targetPort int) []*model.ServiceInstance {
 	ret := make([]*model.ServiceInstance, 0)
 	for _, p := range svc.Ports {
@@ -0,0 +1,123 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tapdata "github.com/envoyproxy/go-control-plane/envoy/data/tap/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"istio.io/istio/pkg/util/sets"
+)
+
+// redactedValue replaces the values of the headers of the traces captured without Full.
+const redactedValue = "[redacted]"
+
+// unredactedHeaders are the headers whose values are kept in the traces captured without Full, as they describe the
+// request rather than carrying credentials or user data. Pseudo-headers such as :method and :path are also kept.
+var unredactedHeaders = sets.New(
+	"content-length",
+	"content-type",
+	"grpc-status",
+	"user-agent",
+	"x-envoy-upstream-service-time",
+	"x-request-id",
+)
+
+// TapSession is a time-boxed tap of the HTTP traffic of Envoy, started by istiod and run by the agent through
+// the Envoy admin /tap endpoint.
+type TapSession struct {
+	// ID identifies the session, the agent reports the captured traces with it.
+	ID string `json:"id"`
+	// Duration is how long the traffic is tapped for.
+	Duration time.Duration `json:"duration"`
+	// MaxTraces ends the session early, once as many traces are captured.
+	MaxTraces int `json:"maxTraces"`
+	// Full keeps the values of all the headers and the bodies in the traces. Otherwise, only the metadata of the
+	// requests is captured: the bodies are dropped, and the values of the headers redacted.
+	Full bool `json:"full,omitempty"`
+	// Request is the JSON encoded envoy.admin.v3.TapRequest sent to the /tap endpoint.
+	Request json.RawMessage `json:"request"`
+}
+
+// Tap runs the tap session against the Envoy admin /tap endpoint, and returns the traces captured until the
+// session expires or its maximum number of traces is reached.
+func Tap(ctx context.Context, adminPort uint32, session TapSession) ([]*tapdata.TraceWrapper, error) {
+	ctx, cancel := context.WithTimeout(ctx, session.Duration)
+	defer cancel()
+	requestURL := fmt.Sprintf("http://localhost:%d/tap", adminPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, strings.NewReader(string(session.Request)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return nil, fmt.Errorf("unexpected status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// The traces are streamed as consecutive JSON objects, until the request is canceled.
+	traces := []*tapdata.TraceWrapper{}
+	decoder := json.NewDecoder(response.Body)
+	for session.MaxTraces <= 0 || len(traces) < session.MaxTraces {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				break
+			}
+			return traces, err
+		}
+		trace := &tapdata.TraceWrapper{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, trace); err != nil {
+			return traces, fmt.Errorf("invalid trace: %v", err)
+		}
+		if !session.Full {
+			redactTrace(trace)
+		}
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+// redactTrace drops the bodies of the trace and redacts the values of its headers, but the pseudo-headers and
+// unredactedHeaders.
+func redactTrace(trace *tapdata.TraceWrapper) {
+	buffered := trace.GetHttpBufferedTrace()
+	for _, m := range []*tapdata.HttpBufferedTrace_Message{buffered.GetRequest(), buffered.GetResponse()} {
+		if m == nil {
+			continue
+		}
+		m.Body = nil
+		for _, h := range append(m.Headers, m.Trailers...) {
+			if !strings.HasPrefix(h.Key, ":") && !unredactedHeaders.Contains(strings.ToLower(h.Key)) {
+				h.Value = redactedValue
+			}
+		}
+	}
+}
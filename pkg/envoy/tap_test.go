@@ -0,0 +1,123 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTap(t *testing.T) {
+	cases := []struct {
+		name      string
+		maxTraces int
+		want      int
+	}{
+		{name: "until expiry", want: 3},
+		{name: "max traces", maxTraces: 2, want: 2},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/tap" || r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				got, _ = io.ReadAll(r.Body)
+				for i := 0; i < 3; i++ {
+					_, _ = w.Write([]byte(`{"http_buffered_trace":{"request":{"headers":[]}}}` + "\n"))
+					w.(http.Flusher).Flush()
+				}
+				<-r.Context().Done()
+			}))
+			defer server.Close()
+			_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+			adminPort, _ := strconv.Atoi(port)
+
+			request := json.RawMessage(`{"config_id":"istio-tap"}`)
+			traces, err := Tap(context.Background(), uint32(adminPort), TapSession{
+				ID:        "test",
+				Duration:  200 * time.Millisecond,
+				MaxTraces: tt.maxTraces,
+				Request:   request,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(traces) != tt.want {
+				t.Fatalf("got %d traces, want %d", len(traces), tt.want)
+			}
+			if string(got) != string(request) {
+				t.Fatalf("got tap request %s, want %s", got, request)
+			}
+		})
+	}
+}
+
+func TestTapError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Unknown config id 'istio-tap'. No extension has registered with this id."))
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	adminPort, _ := strconv.Atoi(port)
+
+	if _, err := Tap(context.Background(), uint32(adminPort), TapSession{Duration: time.Second}); err == nil {
+		t.Fatal("expected error for a proxy without the tap filter")
+	}
+}
+
+func TestTapRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"http_buffered_trace":{"request":{"headers":[{"key":":path","value":"/api"},` +
+			`{"key":"authorization","value":"Bearer token"},{"key":"content-type","value":"application/json"}],` +
+			`"body":{"as_string":"secret"}}}}` + "\n"))
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	adminPort, _ := strconv.Atoi(port)
+
+	for _, full := range []bool{false, true} {
+		traces, err := Tap(context.Background(), uint32(adminPort), TapSession{Duration: time.Second, Full: full})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(traces) != 1 {
+			t.Fatalf("got %d traces, want 1", len(traces))
+		}
+		request := traces[0].GetHttpBufferedTrace().GetRequest()
+		want := map[string]string{":path": "/api", "authorization": "Bearer token", "content-type": "application/json"}
+		if !full {
+			want["authorization"] = redactedValue
+		}
+		for _, h := range request.GetHeaders() {
+			if h.Value != want[h.Key] {
+				t.Fatalf("got header %s: %s, want %s", h.Key, h.Value, want[h.Key])
+			}
+		}
+		if (request.GetBody() != nil) != full {
+			t.Fatalf("got body %v with full %v", request.GetBody(), full)
+		}
+	}
+}
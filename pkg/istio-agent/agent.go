@@ -75,6 +75,9 @@ const (
 	MetadataClientCertChain = "ISTIO_META_TLS_CLIENT_CERT_CHAIN"
 	// MetadataClientRootCert is ISTIO_META env var used for client root cert.
 	MetadataClientRootCert = "ISTIO_META_TLS_CLIENT_ROOT_CERT"
+	// MetadataGRPCReadinessProbe is the proxy metadata holding the gRPC readiness probe of the workload, the value of
+	// the grpc-probe annotation of its WorkloadGroup.
+	MetadataGRPCReadinessProbe = "GRPC_READINESS_PROBE"
 )
 
 var _ ready.Prober = &Agent{}
@@ -22,6 +22,7 @@ import (
 	"istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
 	"istio.io/istio/pilot/cmd/pilot-agent/status/ready"
+	"istio.io/istio/pkg/config/probe"
 	"istio.io/istio/pkg/kube/apimirror"
 )
 
@@ -51,6 +52,13 @@ const (
 	lastStateUnhealthy
 )
 
+func defaultHost(ipAddresses []string) string {
+	if len(ipAddresses) == 0 || status.LegacyLocalhostProbeDestination.Get() {
+		return "localhost"
+	}
+	return ipAddresses[0]
+}
+
 func fillInDefaults(cfg *v1alpha3.ReadinessProbe, ipAddresses []string) *v1alpha3.ReadinessProbe {
 	cfg = cfg.DeepCopy()
 	// Thresholds have a minimum of 1
@@ -71,20 +79,35 @@ func fillInDefaults(cfg *v1alpha3.ReadinessProbe, ipAddresses []string) *v1alpha
 		}
 		h.HttpGet.Scheme = strings.ToLower(h.HttpGet.Scheme)
 		if h.HttpGet.Host == "" {
-			if len(ipAddresses) == 0 || status.LegacyLocalhostProbeDestination.Get() {
-				h.HttpGet.Host = "localhost"
-			} else {
-				h.HttpGet.Host = ipAddresses[0]
-			}
+			h.HttpGet.Host = defaultHost(ipAddresses)
+		}
+	case *v1alpha3.ReadinessProbe_TcpSocket:
+		if h.TcpSocket.Host == "" {
+			h.TcpSocket.Host = defaultHost(ipAddresses)
 		}
 	}
 	return cfg
 }
 
-func NewWorkloadHealthChecker(cfg *v1alpha3.ReadinessProbe, envoyProbe ready.Prober, proxyAddrs []string, ipv6 bool) *WorkloadHealthChecker {
+func fillInGRPCDefaults(cfg *probe.GRPC, ipAddresses []string) *probe.GRPC {
+	out := *cfg
+	if out.Host == "" {
+		out.Host = defaultHost(ipAddresses)
+	}
+	return &out
+}
+
+// NewWorkloadHealthChecker returns the checker of the readiness probe of the workload. If set, the gRPC probe
+// replaces the health check method of the probe.
+func NewWorkloadHealthChecker(cfg *v1alpha3.ReadinessProbe, grpcProbe *probe.GRPC, envoyProbe ready.Prober,
+	proxyAddrs []string, ipv6 bool,
+) *WorkloadHealthChecker {
 	// if a config does not exist return a no-op prober
 	if cfg == nil {
-		return nil
+		if grpcProbe == nil {
+			return nil
+		}
+		cfg = &v1alpha3.ReadinessProbe{}
 	}
 	cfg = fillInDefaults(cfg, proxyAddrs)
 	var prober Prober
@@ -98,6 +121,9 @@ func NewWorkloadHealthChecker(cfg *v1alpha3.ReadinessProbe, envoyProbe ready.Pro
 	default:
 		prober = nil
 	}
+	if grpcProbe != nil {
+		prober = NewGRPCProber(fillInGRPCDefaults(grpcProbe, proxyAddrs), ipv6)
+	}
 
 	probers := []Prober{}
 	if envoyProbe != nil {
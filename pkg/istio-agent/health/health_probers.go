@@ -25,9 +25,14 @@ import (
 	"strconv"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcHealth "google.golang.org/grpc/health/grpc_health_v1"
+
 	"istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
 	"istio.io/istio/pilot/cmd/pilot-agent/status/ready"
+	"istio.io/istio/pkg/config/probe"
 	"istio.io/istio/pkg/test/echo/common/scheme"
 	"istio.io/pkg/log"
 )
@@ -166,6 +171,52 @@ func (t *TCPProber) Probe(timeout time.Duration) (ProbeResult, error) {
 	return Healthy, nil
 }
 
+type GRPCProber struct {
+	Config *probe.GRPC
+	// LocalAddr is the address the probes are sent from, so they are not captured by the proxy.
+	LocalAddr *net.TCPAddr
+}
+
+var _ Prober = &GRPCProber{}
+
+func NewGRPCProber(cfg *probe.GRPC, ipv6 bool) *GRPCProber {
+	g := &GRPCProber{Config: cfg, LocalAddr: status.UpstreamLocalAddressIPv4}
+	if ipv6 {
+		g.LocalAddr = status.UpstreamLocalAddressIPv6
+	}
+	return g
+}
+
+// Probe calls the gRPC health checking protocol of the target, which is healthy if it is SERVING.
+func (g *GRPCProber) Probe(timeout time.Duration) (ProbeResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// the DialOptions match the gRPC probes of the pods, see handleAppProbeGRPC of the status server.
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(g.Config.Host, strconv.Itoa(int(g.Config.Port))),
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("istio-probe/1.0"),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			d := status.ProbeDialer()
+			d.LocalAddr = g.LocalAddr
+			d.Timeout = timeout
+			return d.DialContext(ctx, "tcp", addr)
+		}))
+	// if we cant connect, count as fail
+	if err != nil {
+		return Unhealthy, err
+	}
+	defer conn.Close()
+	resp, err := grpcHealth.NewHealthClient(conn).Check(ctx, &grpcHealth.HealthCheckRequest{Service: g.Config.Service})
+	if err != nil {
+		return Unhealthy, err
+	}
+	if resp.GetStatus() != grpcHealth.HealthCheckResponse_SERVING {
+		return Unhealthy, fmt.Errorf("status was not SERVING, bad status %v", resp.GetStatus())
+	}
+	return Healthy, nil
+}
+
 type ExecProber struct {
 	Config *v1alpha3.ExecHealthCheckConfig
 }
@@ -24,7 +24,12 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	grpcHealthServer "google.golang.org/grpc/health"
+	grpcHealth "google.golang.org/grpc/health/grpc_health_v1"
+
 	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/config/probe"
 )
 
 func TestHttpProber(t *testing.T) {
@@ -119,6 +124,43 @@ func TestTcpProber(t *testing.T) {
 	}
 }
 
+func TestGRPCProber(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthServer := grpcHealthServer.NewServer()
+	healthServer.SetServingStatus("serving", grpcHealth.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", grpcHealth.HealthCheckResponse_NOT_SERVING)
+	grpcHealth.RegisterHealthServer(server, healthServer)
+	go server.Serve(l)
+	t.Cleanup(server.Stop)
+	port := int32(l.Addr().(*net.TCPAddr).Port)
+
+	tests := []struct {
+		desc                string
+		service             string
+		port                int32
+		expectedProbeResult ProbeResult
+	}{
+		{desc: "Healthy", service: "serving", port: port, expectedProbeResult: Healthy},
+		{desc: "Server", port: port, expectedProbeResult: Healthy},
+		{desc: "Not serving", service: "not-serving", port: port, expectedProbeResult: Unhealthy},
+		{desc: "Unknown service", service: "unknown", port: port, expectedProbeResult: Unhealthy},
+		{desc: "Unreachable", service: "serving", port: 1, expectedProbeResult: Unhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			prober := NewGRPCProber(&probe.GRPC{Host: "127.0.0.1", Port: tt.port, Service: tt.service}, false)
+			got, err := prober.Probe(time.Second)
+			if got != tt.expectedProbeResult || (err == nil) != (tt.expectedProbeResult == Healthy) {
+				t.Errorf("%s: got: %v, expected: %v, got error: %v", tt.desc, got, tt.expectedProbeResult, err)
+			}
+		})
+	}
+}
+
 func TestExecProber(t *testing.T) {
 	tests := []struct {
 		desc                string
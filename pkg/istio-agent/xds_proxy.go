@@ -44,6 +44,7 @@ import (
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/channels"
 	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/probe"
 	dnsProto "istio.io/istio/pkg/dns/proto"
 	"istio.io/istio/pkg/h2c"
 	"istio.io/istio/pkg/istio-agent/health"
@@ -94,6 +95,10 @@ type XdsProxy struct {
 	tapMutex           sync.RWMutex
 	tapResponseChannel chan *discovery.DiscoveryResponse
 
+	// tapSessions are the IDs of the Envoy tap sessions started by istiod, until they expire.
+	tapSessions      map[string]time.Time
+	tapSessionsMutex sync.Mutex
+
 	// connected stores the active gRPC stream. The proxy will only have 1 connection at a time
 	connected                 *ProxyConnection
 	initialHealthRequest      *discovery.DiscoveryRequest
@@ -136,14 +141,22 @@ func initXdsProxy(ia *Agent) (*XdsProxy, error) {
 		}
 	}
 
+	var grpcProbe *probe.GRPC
+	if v := ia.proxyConfig.ProxyMetadata[MetadataGRPCReadinessProbe]; v != "" {
+		if grpcProbe, err = probe.ParseGRPC(v); err != nil {
+			return nil, fmt.Errorf("invalid gRPC readiness probe: %v", err)
+		}
+	}
+
 	cache := wasm.NewLocalFileCache(constants.IstioDataDir, ia.cfg.WASMOptions)
 	proxy := &XdsProxy{
 		istiodAddress:         ia.proxyConfig.DiscoveryAddress,
 		istiodSAN:             ia.cfg.IstiodSAN,
 		clusterID:             ia.secOpts.ClusterID,
 		handlers:              map[string]ResponseHandler{},
+		tapSessions:           map[string]time.Time{},
 		stopChan:              make(chan struct{}),
-		healthChecker:         health.NewWorkloadHealthChecker(ia.proxyConfig.ReadinessProbe, envoyProbe, ia.cfg.ProxyIPAddresses, ia.cfg.IsIPv6),
+		healthChecker:         health.NewWorkloadHealthChecker(ia.proxyConfig.ReadinessProbe, grpcProbe, envoyProbe, ia.cfg.ProxyIPAddresses, ia.cfg.IsIPv6),
 		xdsHeaders:            ia.cfg.XDSHeaders,
 		xdsUdsPath:            ia.cfg.XdsUdsPath,
 		wasmCache:             cache,
@@ -180,6 +193,10 @@ func initXdsProxy(ia *Agent) (*XdsProxy, error) {
 		}
 	}
 
+	if !ia.cfg.DisableEnvoy {
+		proxy.handlers[v3.TapType] = proxy.tapSessionHandler(uint32(ia.proxyConfig.ProxyAdminPort))
+	}
+
 	proxyLog.Infof("Initializing with upstream address %q and cluster %q", proxy.istiodAddress, proxy.clusterID)
 
 	if err = proxy.initDownstreamServer(); err != nil {
@@ -265,6 +282,8 @@ type ProxyConnection struct {
 	upstream           xds.DiscoveryClient
 	downstreamDeltas   xds.DeltaDiscoveryStream
 	upstreamDeltas     xds.DeltaDiscoveryClient
+	// upstreamConn is the connection to istiod, protected by the connectedMutex of the proxy.
+	upstreamConn *grpc.ClientConn
 }
 
 // sendRequest is a small wrapper around sending to con.requestsChan. This ensures that we do not
@@ -340,6 +359,9 @@ func (p *XdsProxy) handleStream(downstream adsStream) error {
 		return err
 	}
 	defer upstreamConn.Close()
+	p.connectedMutex.Lock()
+	con.upstreamConn = upstreamConn
+	p.connectedMutex.Unlock()
 
 	xds := discovery.NewAggregatedDiscoveryServiceClient(upstreamConn)
 	ctx = metadata.AppendToOutgoingContext(context.Background(), "ClusterID", p.clusterID)
@@ -453,6 +475,12 @@ func (p *XdsProxy) handleUpstreamRequest(con *ProxyConnection) {
 						TypeUrl: v3.ProxyConfigType,
 					})
 				}
+				// subscribe to the tap sessions started through istiod
+				if _, f := p.handlers[v3.TapType]; f {
+					con.sendRequest(&discovery.DiscoveryRequest{
+						TypeUrl: v3.TapType,
+					})
+				}
 				// set flag before sending the initial request to prevent race.
 				initialRequestsSent.Store(true)
 				// Fire of a configured initial request, if there is one
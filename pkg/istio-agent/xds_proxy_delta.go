@@ -112,6 +112,9 @@ func (p *XdsProxy) DeltaAggregatedResources(downstream xds.DeltaDiscoveryStream)
 		return err
 	}
 	defer upstreamConn.Close()
+	p.connectedMutex.Lock()
+	con.upstreamConn = upstreamConn
+	p.connectedMutex.Unlock()
 
 	xds := discovery.NewAggregatedDiscoveryServiceClient(upstreamConn)
 	ctx = metadata.AppendToOutgoingContext(context.Background(), "ClusterID", p.clusterID)
@@ -0,0 +1,140 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istioagent
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tapsvc "github.com/envoyproxy/go-control-plane/envoy/service/tap/v3"
+	"google.golang.org/grpc/metadata"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"istio.io/istio/pkg/envoy"
+)
+
+// tapReportTimeout bounds the time to report the traces of a tap session to istiod.
+const tapReportTimeout = 10 * time.Second
+
+// tapSessionHandler handles the tap sessions pushed by istiod, running them in the background against the Envoy
+// admin port.
+func (p *XdsProxy) tapSessionHandler(adminPort uint32) ResponseHandler {
+	return func(resp *anypb.Any) error {
+		value := &wrapperspb.StringValue{}
+		if err := resp.UnmarshalTo(value); err != nil {
+			return err
+		}
+		session := envoy.TapSession{}
+		if err := json.Unmarshal([]byte(value.Value), &session); err != nil {
+			return err
+		}
+		if !p.startTapSession(session) {
+			return nil
+		}
+		go p.runTapSession(adminPort, session)
+		return nil
+	}
+}
+
+// startTapSession records the session until it expires, it returns false if the session was already started, as
+// istiod pushes it again when the agent reconnects, or if another session is still running, as Envoy only runs one
+// tap session at a time.
+func (p *XdsProxy) startTapSession(session envoy.TapSession) bool {
+	p.tapSessionsMutex.Lock()
+	defer p.tapSessionsMutex.Unlock()
+	now := time.Now()
+	for id, expiry := range p.tapSessions {
+		if now.After(expiry) {
+			delete(p.tapSessions, id)
+		}
+	}
+	if _, f := p.tapSessions[session.ID]; f {
+		proxyLog.Debugf("ignoring tap session %s, already started", session.ID)
+		return false
+	}
+	if len(p.tapSessions) > 0 {
+		proxyLog.Warnf("ignoring tap session %s, another session is running", session.ID)
+		return false
+	}
+	p.tapSessions[session.ID] = now.Add(session.Duration + tapReportTimeout)
+	return true
+}
+
+// runTapSession taps the traffic of Envoy for the duration of the session, and streams the captured traces to the
+// tap sink of the istiod the session was pushed by.
+func (p *XdsProxy) runTapSession(adminPort uint32, session envoy.TapSession) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	proxyLog.Infof("running tap session %s for %v", session.ID, session.Duration)
+	traces, err := envoy.Tap(ctx, adminPort, session)
+	if err != nil {
+		// The traces captured so far are still reported, istiod returns them when the session ends.
+		proxyLog.Warnf("tap session %s failed: %v", session.ID, err)
+	}
+
+	p.connectedMutex.RLock()
+	var con *ProxyConnection
+	if p.connected != nil && p.connected.upstreamConn != nil {
+		con = p.connected
+	}
+	p.connectedMutex.RUnlock()
+	if con == nil {
+		proxyLog.Warnf("dropping the %d traces of tap session %s, not connected to istiod", len(traces), session.ID)
+		return
+	}
+
+	ctx, cancelReport := context.WithTimeout(ctx, tapReportTimeout)
+	defer cancelReport()
+	ctx = metadata.AppendToOutgoingContext(ctx, "ClusterID", p.clusterID)
+	stream, err := tapsvc.NewTapSinkServiceClient(con.upstreamConn).StreamTaps(ctx)
+	if err != nil {
+		proxyLog.Warnf("failed to report tap session %s: %v", session.ID, err)
+		return
+	}
+	first := &tapsvc.StreamTapsRequest{
+		Identifier: &tapsvc.StreamTapsRequest_Identifier{
+			Node:  &core.Node{Id: p.ia.cfg.ServiceNode},
+			TapId: session.ID,
+		},
+	}
+	requests := []*tapsvc.StreamTapsRequest{first}
+	for i, trace := range traces {
+		if i == 0 {
+			first.Trace = trace
+			continue
+		}
+		requests = append(requests, &tapsvc.StreamTapsRequest{Trace: trace})
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			proxyLog.Warnf("failed to report tap session %s: %v", session.ID, err)
+			return
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		proxyLog.Warnf("failed to report tap session %s: %v", session.ID, err)
+	}
+}
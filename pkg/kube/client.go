@@ -262,6 +262,9 @@ func NewFakeClient(objects ...runtime.Object) CLIClient {
 	// If you are adding something to this list, consider other options like adding to the scheme.
 	gvrToListKind := map[schema.GroupVersionResource]string{
 		{Group: "testdata.istio.io", Version: "v1alpha1", Resource: "Kind1s"}: "Kind1List",
+		// The GatewayClassConfigs and ClusterRegistrations are watched with the dynamic client, as they have no typed client.
+		{Group: "gateway.istio.io", Version: "v1alpha1", Resource: "gatewayclassconfigs"}:       "GatewayClassConfigList",
+		{Group: "multicluster.istio.io", Version: "v1alpha1", Resource: "clusterregistrations"}: "ClusterRegistrationList",
 	}
 	c.dynamic = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(s, gvrToListKind)
 	c.dynamicInformer = dynamicinformer.NewDynamicSharedInformerFactory(c.dynamic, resyncInterval)
@@ -0,0 +1,179 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/pkg/monitoring"
+)
+
+// unknownKind is the kind label of the reconciles of the queues which do not set their kind.
+const unknownKind = "unknown"
+
+var (
+	controllerTag = monitoring.MustCreateLabel("controller")
+	kindTag       = monitoring.MustCreateLabel("kind")
+	clusterTag    = monitoring.MustCreateLabel("cluster")
+	eventTag      = monitoring.MustCreateLabel("event")
+	classTag      = monitoring.MustCreateLabel("class")
+
+	controllerEvents = monitoring.NewSum(
+		"istiod_controller_events_total",
+		"Events handled by the istiod controllers, by controller, kind of the object, cluster and event.",
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag, eventTag),
+	)
+
+	controllerReconcileDuration = monitoring.NewDistribution(
+		"istiod_controller_reconcile_duration_seconds",
+		"Time taken by the istiod controllers to reconcile an object, by controller, kind of the object, cluster "+
+			"and class of the object, for the controllers reconciling objects of several classes.",
+		[]float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag, classTag),
+	)
+
+	controllerReconcileErrors = monitoring.NewSum(
+		"istiod_controller_reconcile_errors_total",
+		"Failed reconciles of the istiod controllers, by controller, kind of the object, cluster and class of the "+
+			"object, for the controllers reconciling objects of several classes.",
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag, classTag),
+	)
+
+	controllerQueueDeduplicated = monitoring.NewSum(
+		"istiod_controller_queue_deduplicated_total",
+		"Items added to the queues of the istiod controllers while already pending, and handled once, "+
+			"by controller, kind of the object and cluster.",
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag),
+	)
+
+	controllerQueueRequeues = monitoring.NewSum(
+		"istiod_controller_queue_requeues_total",
+		"Items requeued with a backoff by the queues of the istiod controllers after a failed reconcile, "+
+			"by controller, kind of the object and cluster.",
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag),
+	)
+
+	controllerQueueDepth = monitoring.NewGauge(
+		"istiod_controller_queue_depth",
+		"Items waiting to be handled by the queues of the istiod controllers, excluding those waiting for a retry, "+
+			"by controller, kind of the object and cluster.",
+		monitoring.WithLabels(controllerTag, kindTag, clusterTag),
+	)
+)
+
+func init() {
+	monitoring.MustRegister(controllerEvents, controllerReconcileDuration, controllerReconcileErrors,
+		controllerQueueDeduplicated, controllerQueueRequeues, controllerQueueDepth)
+}
+
+// Metrics records the events handled and the reconciles run by a controller, for one cluster.
+type Metrics struct {
+	controller string
+	cluster    string
+}
+
+// NewMetrics returns the metrics of the controller for the cluster.
+func NewMetrics(controller string, c cluster.ID) Metrics {
+	return Metrics{controller: controller, cluster: c.String()}
+}
+
+// Event records an event of the kind, e.g. "add", "update" or "delete".
+func (m Metrics) Event(k kind.Kind, event string) {
+	controllerEvents.With(controllerTag.Value(m.controller), kindTag.Value(k.String()),
+		clusterTag.Value(m.cluster), eventTag.Value(event)).Increment()
+}
+
+// Reconcile records a reconcile of an object of the kind, started at start, which failed if err is set.
+func (m Metrics) Reconcile(k kind.Kind, start time.Time, err error) {
+	m.reconcile(k.String(), "", start, err)
+}
+
+// reconcile records a reconcile, labeled with the class of the object if set.
+func (m Metrics) reconcile(k, class string, start time.Time, err error) {
+	labels := []monitoring.LabelValue{controllerTag.Value(m.controller), kindTag.Value(k), clusterTag.Value(m.cluster)}
+	if class != "" {
+		labels = append(labels, classTag.Value(class))
+	}
+	controllerReconcileDuration.With(labels...).Record(time.Since(start).Seconds())
+	if err != nil {
+		controllerReconcileErrors.With(labels...).Increment()
+	}
+}
+
+func (m Metrics) deduplicated(k string) {
+	controllerQueueDeduplicated.With(controllerTag.Value(m.controller), kindTag.Value(k),
+		clusterTag.Value(m.cluster)).Increment()
+}
+
+func (m Metrics) queueDepth(k string, depth int) {
+	controllerQueueDepth.With(controllerTag.Value(m.controller), kindTag.Value(k),
+		clusterTag.Value(m.cluster)).Record(float64(depth))
+}
+
+func (m Metrics) requeued(k string) {
+	controllerQueueRequeues.With(controllerTag.Value(m.controller), kindTag.Value(k),
+		clusterTag.Value(m.cluster)).Increment()
+}
+
+// Handler wraps the handler, recording the events of the kind it handles. This is meant for controllers that
+// reconcile the objects in a Queue, which records the reconciles.
+func (m Metrics) Handler(k kind.Kind, h cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return metricsHandler{metrics: m, kind: k, handler: h}
+}
+
+// SyncHandler wraps the handler, recording the events of the kind it handles and the time taken to handle
+// them as reconciles. This is meant for controllers that reconcile the objects in their event handlers.
+func (m Metrics) SyncHandler(k kind.Kind, h cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return metricsHandler{metrics: m, kind: k, handler: h, sync: true}
+}
+
+type metricsHandler struct {
+	metrics Metrics
+	kind    kind.Kind
+	handler cache.ResourceEventHandler
+	sync    bool
+}
+
+func (h metricsHandler) record(event EventType) func() {
+	h.metrics.Event(h.kind, event.String())
+	if !h.sync {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		h.metrics.Reconcile(h.kind, start, nil)
+	}
+}
+
+func (h metricsHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	defer h.record(EventAdd)()
+	h.handler.OnAdd(obj, isInInitialList)
+}
+
+func (h metricsHandler) OnUpdate(oldObj, newObj interface{}) {
+	defer h.record(EventUpdate)()
+	h.handler.OnUpdate(oldObj, newObj)
+}
+
+func (h metricsHandler) OnDelete(obj interface{}) {
+	defer h.record(EventDelete)()
+	h.handler.OnDelete(obj)
+}
+
+var _ cache.ResourceEventHandler = metricsHandler{}
@@ -0,0 +1,177 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pkg/config/schema/kind"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+// metricRows returns the rows of the metric of the controller, keyed by their other labels. The class is only part of
+// the key when set.
+func metricRows(t *testing.T, metric, controller string) map[string]view.AggregationData {
+	t.Helper()
+	rows, err := view.RetrieveData(metric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := map[string]view.AggregationData{}
+	for _, row := range rows {
+		labels := map[string]string{}
+		for _, tg := range row.Tags {
+			labels[tg.Key.Name()] = tg.Value
+		}
+		if labels["controller"] != controller {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", labels["kind"], labels["cluster"], labels["event"])
+		if labels["class"] != "" {
+			key += "/" + labels["class"]
+		}
+		res[key] = row.Data
+	}
+	return res
+}
+
+// testController returns a controller name unique to this run of the test, as the metrics outlive it.
+func testController(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+}
+
+func sum(d view.AggregationData) float64 {
+	return d.(*view.SumData).Value
+}
+
+func count(d view.AggregationData) int64 {
+	return d.(*view.DistributionData).Count
+}
+
+func last(d view.AggregationData) float64 {
+	return d.(*view.LastValueData).Value
+}
+
+func TestQueueMetrics(t *testing.T) {
+	controller := testController(t)
+	q := NewQueue(controller, WithKind(kind.Pod), WithCluster("cluster-1"), WithMaxAttempts(1),
+		WithReconciler(func(key types.NamespacedName) error {
+			if key.Name == "fail" {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		}))
+	q.Add(types.NamespacedName{Name: "ok"})
+	q.Add(types.NamespacedName{Name: "fail"})
+	stop := make(chan struct{})
+	go q.Run(stop)
+	retry.UntilOrFail(t, q.HasSynced, retry.Delay(time.Microsecond))
+	close(stop)
+	assert.NoError(t, q.WaitForClose(time.Second))
+
+	durations := metricRows(t, "istiod_controller_reconcile_duration_seconds", controller)
+	assert.Equal(t, count(durations["Pod/cluster-1/"]), int64(2))
+	errors := metricRows(t, "istiod_controller_reconcile_errors_total", controller)
+	assert.Equal(t, sum(errors["Pod/cluster-1/"]), 1.0)
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	handled := 0
+	h := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { handled++ },
+		UpdateFunc: func(oldObj, newObj any) { handled++ },
+		DeleteFunc: func(obj any) { handled++ },
+	}
+	controller := testController(t)
+	m := NewMetrics(controller, "cluster-1")
+	events := m.Handler(kind.Service, h)
+	events.OnAdd(nil, false)
+	events.OnUpdate(nil, nil)
+	sync := m.SyncHandler(kind.Pod, h)
+	sync.OnAdd(nil, false)
+	sync.OnDelete(nil)
+	assert.Equal(t, handled, 4)
+
+	rows := metricRows(t, "istiod_controller_events_total", controller)
+	assert.Equal(t, sum(rows["Service/cluster-1/add"]), 1.0)
+	assert.Equal(t, sum(rows["Service/cluster-1/update"]), 1.0)
+	assert.Equal(t, sum(rows["Pod/cluster-1/add"]), 1.0)
+	assert.Equal(t, sum(rows["Pod/cluster-1/delete"]), 1.0)
+
+	// Only the sync handler records the handling of the events as reconciles.
+	durations := metricRows(t, "istiod_controller_reconcile_duration_seconds", controller)
+	assert.Equal(t, len(durations), 1)
+	assert.Equal(t, count(durations["Pod/cluster-1/"]), int64(2))
+}
+
+func TestQueueDedupAndRequeueMetrics(t *testing.T) {
+	controller := testController(t)
+	attempts := atomic.NewInt32(0)
+	q := NewQueue(controller, WithKind(kind.Pod), WithCluster("cluster-1"), WithMaxAttempts(3),
+		WithItemExponentialBackoff(time.Millisecond, time.Millisecond),
+		WithReconciler(func(key types.NamespacedName) error {
+			attempts.Inc()
+			return fmt.Errorf("failed")
+		}))
+	// The second addition is deduplicated.
+	q.Add(types.NamespacedName{Name: "fail"})
+	q.Add(types.NamespacedName{Name: "fail"})
+	stop := make(chan struct{})
+	go q.Run(stop)
+	retry.UntilOrFail(t, func() bool { return attempts.Load() == 3 }, retry.Delay(time.Millisecond))
+	close(stop)
+	assert.NoError(t, q.WaitForClose(time.Second))
+
+	deduplicated := metricRows(t, "istiod_controller_queue_deduplicated_total", controller)
+	assert.Equal(t, sum(deduplicated["Pod/cluster-1/"]), 1.0)
+	requeues := metricRows(t, "istiod_controller_queue_requeues_total", controller)
+	assert.Equal(t, sum(requeues["Pod/cluster-1/"]), 2.0)
+}
+
+func TestQueueClassAndDepthMetrics(t *testing.T) {
+	controller := testController(t)
+	q := NewQueue(controller, WithKind(kind.KubernetesGateway), WithCluster("cluster-1"),
+		WithClassOf(func(key types.NamespacedName) string {
+			return "class-" + key.Name
+		}),
+		WithReconciler(func(key types.NamespacedName) error {
+			return nil
+		}))
+	q.Add(types.NamespacedName{Name: "a"})
+	q.Add(types.NamespacedName{Name: "b"})
+	// The depth is reported by the queue as the items are added.
+	depth := metricRows(t, "istiod_controller_queue_depth", controller)
+	assert.Equal(t, last(depth["Gateway/cluster-1/"]), 2.0)
+
+	stop := make(chan struct{})
+	go q.Run(stop)
+	retry.UntilOrFail(t, q.HasSynced, retry.Delay(time.Microsecond))
+	close(stop)
+	assert.NoError(t, q.WaitForClose(time.Second))
+
+	durations := metricRows(t, "istiod_controller_reconcile_duration_seconds", controller)
+	assert.Equal(t, count(durations["Gateway/cluster-1//class-a"]), int64(1))
+	assert.Equal(t, count(durations["Gateway/cluster-1//class-b"]), int64(1))
+	depth = metricRows(t, "istiod_controller_queue_depth", controller)
+	assert.Equal(t, last(depth["Gateway/cluster-1/"]), 0.0)
+}
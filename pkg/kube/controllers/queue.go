@@ -16,18 +16,31 @@ package controllers
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 
+	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/schema/kind"
 	istiolog "istio.io/pkg/log"
 )
 
 type ReconcilerFn func(key types.NamespacedName) error
 
+// The defaults of the rate limiter of the queues, those of workqueue.DefaultControllerRateLimiter.
+const (
+	defaultBaseDelay = 5 * time.Millisecond
+	defaultItemDelay = 1000 * time.Second
+	defaultQPS       = 10
+	defaultBurst     = 100
+)
+
 // Queue defines an abstraction around Kubernetes' workqueue.
 // Items enqueued are deduplicated; this generally means relying on ordering of events in the queue is not feasible.
 type Queue struct {
@@ -38,6 +51,44 @@ type Queue struct {
 	workFn      func(key any) error
 	closed      chan struct{}
 	log         *istiolog.Scope
+	kind        string
+	cluster     cluster.ID
+	classOf     func(key any) string
+	metrics     Metrics
+
+	// rateLimiter delays the retries of the items, and the items added with AddRateLimited.
+	rateLimiter workqueue.RateLimiter
+	// baseDelay and itemDelay are the initial and maximal delays of the exponential backoff of the retries of an item.
+	baseDelay time.Duration
+	itemDelay time.Duration
+	// qps and burst limit the rate of the retries of all the items.
+	qps   float64
+	burst int
+	// maxDelay, when set, caps the delay of the retries, whatever the rate limiter.
+	maxDelay time.Duration
+
+	// pending holds the items added and not yet handled, to record the additions deduplicated by the queue.
+	pending *pendingItems
+	// failed holds the items whose retry budget was exceeded, until they are handled successfully.
+	failed *failedItems
+}
+
+type pendingItems struct {
+	mu    sync.Mutex
+	items map[any]struct{}
+}
+
+// FailedItem is an item of the queue which is no longer retried, as its retry budget was exceeded.
+type FailedItem struct {
+	Key      string
+	Error    string
+	Attempts int
+	Time     time.Time
+}
+
+type failedItems struct {
+	mu    sync.Mutex
+	items map[any]FailedItem
 }
 
 // WithName sets a name for the queue. This is used for logging
@@ -47,10 +98,61 @@ func WithName(name string) func(q *Queue) {
 	}
 }
 
-// WithRateLimiter allows defining a custom rate limitter for the queue
+// WithKind sets the kind of the objects reconciled by the queue. This is used for metrics
+func WithKind(k kind.Kind) func(q *Queue) {
+	return func(q *Queue) {
+		q.kind = k.String()
+	}
+}
+
+// WithCluster sets the cluster of the objects reconciled by the queue. This is used for metrics
+func WithCluster(c cluster.ID) func(q *Queue) {
+	return func(q *Queue) {
+		q.cluster = c
+	}
+}
+
+// WithClassOf sets the function returning the class of an object reconciled by the queue, such as the GatewayClass
+// of a Gateway. This is used for metrics
+func WithClassOf(f func(key types.NamespacedName) string) func(q *Queue) {
+	return func(q *Queue) {
+		q.classOf = func(key any) string {
+			return f(key.(types.NamespacedName))
+		}
+	}
+}
+
+// WithRateLimiter allows defining a custom rate limitter for the queue.
+// This overrides WithItemExponentialBackoff and WithOverallRateLimit.
 func WithRateLimiter(r workqueue.RateLimiter) func(q *Queue) {
 	return func(q *Queue) {
-		q.queue = workqueue.NewRateLimitingQueue(r)
+		q.rateLimiter = r
+	}
+}
+
+// WithItemExponentialBackoff sets the backoff of the retries of an item, doubling from base up to maxDelay.
+// If not set, the retries are delayed from 5ms up to 1000s.
+func WithItemExponentialBackoff(base, maxDelay time.Duration) func(q *Queue) {
+	return func(q *Queue) {
+		q.baseDelay = base
+		q.itemDelay = maxDelay
+	}
+}
+
+// WithOverallRateLimit sets the rate of the retries of all the items, a token bucket of burst tokens refilled at qps.
+// If not set, the retries are limited to 10 qps with a burst of 100.
+func WithOverallRateLimit(qps float64, burst int) func(q *Queue) {
+	return func(q *Queue) {
+		q.qps = qps
+		q.burst = burst
+	}
+}
+
+// WithMaxDelay caps the delay of the retries of an item, whatever the rate limiter of the queue. This bounds the time
+// an object stays out of sync when the API server is throttling the retries.
+func WithMaxDelay(d time.Duration) func(q *Queue) {
+	return func(q *Queue) {
+		q.maxDelay = d
 	}
 }
 
@@ -85,32 +187,75 @@ func NewQueue(name string, options ...func(*Queue)) Queue {
 		name:        name,
 		closed:      make(chan struct{}),
 		initialSync: atomic.NewBool(false),
+		kind:        unknownKind,
+		baseDelay:   defaultBaseDelay,
+		itemDelay:   defaultItemDelay,
+		qps:         defaultQPS,
+		burst:       defaultBurst,
+		pending:     &pendingItems{items: map[any]struct{}{}},
+		failed:      &failedItems{items: map[any]FailedItem{}},
 	}
 	for _, o := range options {
 		o(&q)
 	}
-	if q.queue == nil {
-		q.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	if q.rateLimiter == nil {
+		q.rateLimiter = workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(q.baseDelay, q.itemDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(q.qps), q.burst)},
+		)
+	}
+	if q.maxDelay > 0 {
+		q.rateLimiter = workqueue.NewWithMaxWaitRateLimiter(q.rateLimiter, q.maxDelay)
 	}
+	q.queue = workqueue.NewRateLimitingQueue(q.rateLimiter)
 	q.log = log.WithLabels("controller", q.name)
+	q.metrics = NewMetrics(q.name, q.cluster)
 	return q
 }
 
 // Add an item to the queue.
 func (q Queue) Add(item any) {
+	q.pending.mu.Lock()
+	if _, f := q.pending.items[item]; f {
+		q.metrics.deduplicated(q.kind)
+	} else {
+		q.pending.items[item] = struct{}{}
+	}
+	q.pending.mu.Unlock()
 	q.queue.Add(item)
+	q.metrics.queueDepth(q.kind, q.queue.Len())
 }
 
 // AddObject takes an Object and adds the types.NamespacedName associated.
 func (q Queue) AddObject(obj Object) {
-	q.queue.Add(config.NamespacedName(obj))
+	q.Add(config.NamespacedName(obj))
+}
+
+// Metrics returns the metrics of the controller of the queue, to record the events it handles.
+func (q Queue) Metrics() Metrics {
+	return q.metrics
+}
+
+// FailedItems returns the items which are no longer retried, as their retry budget was exceeded, sorted by key. An
+// item is removed once it is added again and handled successfully.
+func (q Queue) FailedItems() []FailedItem {
+	q.failed.mu.Lock()
+	res := make([]FailedItem, 0, len(q.failed.items))
+	for _, f := range q.failed.items {
+		res = append(res, f)
+	}
+	q.failed.mu.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
 }
 
 // Run the queue. This is synchronous, so should typically be called in a goroutine.
 func (q Queue) Run(stop <-chan struct{}) {
 	defer q.queue.ShutDown()
 	q.log.Infof("starting")
-	q.queue.Add(defaultSyncSignal)
+	q.Add(defaultSyncSignal)
 	go func() {
 		// Process updates until we return false, which indicates the queue is terminated
 		for q.processNextItem() {
@@ -151,6 +296,11 @@ func (q Queue) processNextItem() bool {
 		// We are done, signal to exit the queue
 		return false
 	}
+	q.metrics.queueDepth(q.kind, q.queue.Len())
+	// The item is handled from now on, adding it again is not deduplicated.
+	q.pending.mu.Lock()
+	delete(q.pending.items, key)
+	q.pending.mu.Unlock()
 
 	// We got the sync signal. This is not a real event, so we exit early after signaling we are synced
 	if key == defaultSyncSignal {
@@ -164,16 +314,30 @@ func (q Queue) processNextItem() bool {
 	// 'Done marks item as done processing' - should be called at the end of all processing
 	defer q.queue.Done(key)
 
+	start := time.Now()
 	err := q.workFn(key)
+	class := ""
+	if q.classOf != nil {
+		class = q.classOf(key)
+	}
+	q.metrics.reconcile(q.kind, class, start, err)
 	if err != nil {
 		retryCount := q.queue.NumRequeues(key) + 1
 		if retryCount < q.maxAttempts {
 			q.log.Errorf("error handling %v, retrying (retry count: %d): %v", formatKey(key), retryCount, err)
+			q.metrics.requeued(q.kind)
 			q.queue.AddRateLimited(key)
 			// Return early, so we do not call Forget(), allowing the rate limiting to backoff
 			return true
 		}
 		q.log.Errorf("error handling %v, and retry budget exceeded: %v", formatKey(key), err)
+		q.failed.mu.Lock()
+		q.failed.items[key] = FailedItem{Key: formatKey(key), Error: err.Error(), Attempts: retryCount, Time: time.Now()}
+		q.failed.mu.Unlock()
+	} else {
+		q.failed.mu.Lock()
+		delete(q.failed.items, key)
+		q.failed.mu.Unlock()
 	}
 	// 'Forget indicates that an item is finished being retried.' - should be called whenever we do not want to backoff on this key.
 	q.queue.Forget(key)
@@ -15,12 +15,14 @@
 package controllers
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
 	"k8s.io/apimachinery/pkg/types"
 
+	"istio.io/istio/pkg/test"
 	"istio.io/istio/pkg/test/util/assert"
 	"istio.io/istio/pkg/test/util/retry"
 )
@@ -42,3 +44,50 @@ func TestQueue(t *testing.T) {
 	// event 2 is guaranteed to happen from WaitForClose
 	assert.Equal(t, handles.Load(), 2)
 }
+
+func TestQueueMaxDelay(t *testing.T) {
+	handles := atomic.NewInt32(0)
+	q := NewQueue("custom", WithMaxAttempts(2),
+		// Without the max delay, the retry would wait for an hour.
+		WithItemExponentialBackoff(time.Hour, time.Hour),
+		WithMaxDelay(time.Millisecond),
+		WithReconciler(func(key types.NamespacedName) error {
+			if handles.Inc() == 1 {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		}))
+	q.Add(types.NamespacedName{Name: "something"})
+	stop := test.NewStop(t)
+	go q.Run(stop)
+	retry.UntilOrFail(t, func() bool { return handles.Load() == 2 }, retry.Timeout(time.Second), retry.Delay(time.Millisecond))
+}
+
+func TestQueueFailedItems(t *testing.T) {
+	fail := atomic.NewBool(true)
+	handles := atomic.NewInt32(0)
+	q := NewQueue("custom", WithMaxAttempts(2),
+		WithItemExponentialBackoff(time.Millisecond, time.Millisecond),
+		WithReconciler(func(key types.NamespacedName) error {
+			handles.Inc()
+			if fail.Load() {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		}))
+	key := types.NamespacedName{Namespace: "ns", Name: "something"}
+	q.Add(key)
+	stop := test.NewStop(t)
+	go q.Run(stop)
+	retry.UntilOrFail(t, func() bool { return len(q.FailedItems()) == 1 }, retry.Timeout(time.Second), retry.Delay(time.Millisecond))
+	failed := q.FailedItems()[0]
+	assert.Equal(t, failed.Key, "ns/something")
+	assert.Equal(t, failed.Error, "failed")
+	assert.Equal(t, failed.Attempts, 2)
+
+	// A successful reconcile removes the item
+	fail.Store(false)
+	q.Add(key)
+	retry.UntilOrFail(t, func() bool { return len(q.FailedItems()) == 0 }, retry.Timeout(time.Second), retry.Delay(time.Millisecond))
+	assert.Equal(t, handles.Load(), int32(3))
+}
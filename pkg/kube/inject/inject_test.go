@@ -321,6 +321,12 @@ func TestInjection(t *testing.T) {
 				m.DefaultConfig.Tracing = &meshapi.Tracing{}
 			},
 		},
+		{
+			// Verifies that the SPIRE workload socket is mounted with the SPIFFE CSI driver
+			in:       "hello.yaml",
+			want:     "hello-spire.yaml.injected",
+			setFlags: []string{`values.global.caName=SPIRE`},
+		},
 	}
 	// Keep track of tests we add options above
 	// We will search for all test files and skip these ones
@@ -25,7 +25,9 @@ import (
 	"istio.io/api/annotation"
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/config/telemetry"
 	"istio.io/istio/pkg/config/validation"
 	"istio.io/istio/pkg/util/protomarshal"
 )
@@ -48,9 +50,15 @@ var (
 		annotation.SidecarTrafficExcludeOutboundPorts.Name:        ValidateExcludeOutboundPorts,
 		annotation.PrometheusMergeMetrics.Name:                    validateBool,
 		annotation.ProxyConfig.Name:                               validateProxyConfig,
+		constants.StatsHistogramBucketsAnnotation:                 validateStatsHistogramBuckets,
 	}
 )
 
+func validateStatsHistogramBuckets(value string) error {
+	_, err := telemetry.ParseStatHistogramBuckets(value)
+	return err
+}
+
 func validateProxyConfig(value string) error {
 	config := mesh.DefaultProxyConfig()
 	if err := protomarshal.ApplyYAML(value, config); err != nil {
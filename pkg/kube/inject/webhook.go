@@ -46,6 +46,7 @@ import (
 	opconfig "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
 	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/util/protomarshal"
@@ -343,6 +344,8 @@ type InjectionParameters struct {
 	revision            string
 	proxyEnvs           map[string]string
 	injectedAnnotations map[string]string
+	// histogramBuckets are the histogram buckets of the Telemetries applying to the pod.
+	histogramBuckets string
 }
 
 func checkPreconditions(params InjectionParameters) {
@@ -638,6 +641,10 @@ func applyMetadata(pod *corev1.Pod, injectedPodData corev1.Pod, req InjectionPar
 	for k, v := range req.injectedAnnotations {
 		pod.Annotations[k] = v
 	}
+	// The histogram buckets set on the pod take precedence over the ones of the Telemetries.
+	if _, f := pod.Annotations[constants.StatsHistogramBucketsAnnotation]; !f && req.histogramBuckets != "" {
+		pod.Annotations[constants.StatsHistogramBucketsAnnotation] = req.histogramBuckets
+	}
 }
 
 // reorderPod ensures containers are properly ordered after merging
@@ -994,6 +1001,10 @@ func (wh *Webhook) inject(ar *kube.AdmissionReview, path string) *kube.Admission
 			proxyConfig = generatedProxyConfig
 		}
 	}
+	var histogramBuckets string
+	if wh.env.PushContext != nil {
+		histogramBuckets = wh.env.PushContext.Telemetry.HistogramBuckets(pod.Namespace, pod.Labels)
+	}
 	deploy, typeMeta := kube.GetDeployMetaFromPod(&pod)
 	params := InjectionParameters{
 		pod:                 &pod,
@@ -1008,6 +1019,7 @@ func (wh *Webhook) inject(ar *kube.AdmissionReview, path string) *kube.Admission
 		revision:            wh.revision,
 		injectedAnnotations: wh.Config.InjectedAnnotations,
 		proxyEnvs:           parseInjectEnvs(path),
+		histogramBuckets:    histogramBuckets,
 	}
 	wh.mu.RUnlock()
 
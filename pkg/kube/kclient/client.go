@@ -19,7 +19,9 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 
 	"istio.io/istio/pilot/pkg/features"
@@ -219,6 +221,58 @@ func NewFiltered[T controllers.ComparableObject](c kube.Client, filter Filter) C
 	}
 }
 
+// NewMetadata returns a read-only client for the metadata of the objects of the resource, for the watches that only
+// need the metadata of their objects: the cache of the client does not hold their spec and status, cutting the memory
+// used for large numbers of objects. Unless the filter sets a transform, the last-applied-configuration annotation
+// is stripped as well, since the objects are never written back.
+// Internally, this uses the shared metadata informer of the resource, so the label and field selectors of the filter
+// are applied to the cache rather than by the server, and only the metadata.name and metadata.namespace fields can
+// be selected.
+func NewMetadata(c kube.Client, gvr schema.GroupVersionResource, filter Filter) Reader[*metav1.PartialObjectMetadata] {
+	if filter.LabelSelector != "" || filter.FieldSelector != "" {
+		selector, err := metadataSelector(filter)
+		if err != nil {
+			// Fail closed: an invalid selector selects nothing, rather than everything.
+			log.Errorf("invalid selectors of the metadata client of %v: %v", gvr, err)
+			selector = func(any) bool { return false }
+		}
+		objectFilter := filter.ObjectFilter
+		filter.ObjectFilter = func(t any) bool {
+			return selector(t) && (objectFilter == nil || objectFilter(t))
+		}
+	}
+	if filter.ObjectTransform == nil {
+		filter.ObjectTransform = kube.StripUnusedFieldsAndLastApplied
+	}
+	inf := c.MetadataInformer().ForResource(gvr).Informer()
+	return ptr.Of(newInformerClient[*metav1.PartialObjectMetadata](c, inf, filter))
+}
+
+// metadataSelector returns a function matching the objects selected by the label and field selectors of the filter.
+func metadataSelector(filter Filter) (func(t any) bool, error) {
+	labelSelector, err := klabels.Parse(filter.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(filter.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range fieldSelector.Requirements() {
+		if r.Field != metav1.ObjectNameField && r.Field != "metadata.namespace" {
+			return nil, fmt.Errorf("unsupported field selector %q", r.Field)
+		}
+	}
+	return func(t any) bool {
+		obj := controllers.ExtractObject(t)
+		if obj == nil {
+			return false
+		}
+		return labelSelector.Matches(klabels.Set(obj.GetLabels())) &&
+			fieldSelector.Matches(fields.Set{metav1.ObjectNameField: obj.GetName(), "metadata.namespace": obj.GetNamespace()})
+	}, nil
+}
+
 // keyFunc is the internal API key function that returns "namespace"/"name" or
 // "name" if "namespace" is empty
 func keyFunc(name, namespace string) string {
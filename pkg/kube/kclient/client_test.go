@@ -25,6 +25,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
+	metadatafake "k8s.io/client-go/metadata/fake"
 
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
@@ -174,3 +175,46 @@ func (t *tracker) Wait(event string) {
 		return nil
 	}, retry.Timeout(time.Second*5))
 }
+
+func TestMetadataClient(t *testing.T) {
+	c := kube.NewFakeClient()
+	gvr := appsv1.SchemeGroupVersion.WithResource("deployments")
+	deployments := kclient.NewMetadata(c, gvr, kclient.Filter{})
+	selected := kclient.NewMetadata(c, gvr, kclient.Filter{LabelSelector: "app=a", FieldSelector: "metadata.namespace=default"})
+	c.RunAndWait(test.NewStop(t))
+
+	obj := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"a": "b",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+	}
+	_, err := c.Metadata().Resource(gvr).Namespace("default").(metadatafake.MetadataClient).CreateFake(obj, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	assert.EventuallyEqual(t, func() int {
+		return len(deployments.List("default", klabels.Everything()))
+	}, 1)
+	got := deployments.Get("1", "default")
+	// The last applied configuration is stripped by default.
+	assert.Equal(t, got.Annotations, map[string]string{"a": "b"})
+
+	// The selectors are applied to the cache.
+	assert.Equal(t, selected.Get("1", "default"), nil)
+	for _, ns := range []string{"default", "other"} {
+		obj := obj.DeepCopy()
+		obj.Name, obj.Namespace, obj.Labels = "2", ns, map[string]string{"app": "a"}
+		_, err := c.Metadata().Resource(gvr).Namespace(ns).(metadatafake.MetadataClient).CreateFake(obj, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	assert.EventuallyEqual(t, func() int {
+		return len(deployments.List(metav1.NamespaceAll, klabels.Everything()))
+	}, 3)
+	assert.Equal(t, len(selected.List(metav1.NamespaceAll, klabels.Everything())), 1)
+	assert.Equal(t, selected.Get("2", "default").Name, "2")
+}
@@ -43,6 +43,12 @@ func newReadClient[T controllers.ComparableObject](c kube.Client, inf cache.Shar
 		}
 		log.Warn(err)
 	}
+	return newInformerClient[T](c, inf, filter)
+}
+
+// newInformerClient returns a client for the informer, without registering its filter for the type: the informers
+// of metadata are shared by resource rather than by type.
+func newInformerClient[T controllers.ComparableObject](c kube.Client, inf cache.SharedIndexInformer, filter Filter) readClient[T] {
 	if filter.ObjectTransform != nil {
 		_ = inf.SetTransform(filter.ObjectTransform)
 	} else {
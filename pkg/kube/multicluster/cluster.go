@@ -23,6 +23,7 @@ import (
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/network"
 	"istio.io/pkg/log"
 )
 
@@ -32,8 +33,14 @@ type Cluster struct {
 	ID cluster.ID
 	// Client for accessing the cluster.
 	Client kube.Client
+	// Network is the network declared for the cluster by its registration, if any.
+	Network network.ID
+	// Locality is the locality declared for the cluster by its registration, if any.
+	Locality string
 
 	kubeConfigSha [sha256.Size]byte
+	// health tracks the health of the remote cluster; it is nil for the config cluster.
+	health *clusterHealth
 
 	stop chan struct{}
 	// initialSync is marked when RunAndWait completes
@@ -0,0 +1,312 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricproducer"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/util/informermetric"
+	"istio.io/istio/pkg/cluster"
+	pkiutil "istio.io/istio/security/pkg/pki/util"
+	"istio.io/istio/security/pkg/util"
+	"istio.io/pkg/log"
+)
+
+// The health of a remote cluster, as reported by ListRemoteClusters.
+const (
+	// HealthUnknown is reported until the first health check of the cluster completes.
+	HealthUnknown = "unknown"
+	// HealthHealthy is reported when the last health check of the cluster succeeded.
+	HealthHealthy = "healthy"
+	// HealthAuthFailure is reported when the API server of the cluster rejects the credentials.
+	HealthAuthFailure = "auth-failure"
+	// HealthUnreachable is reported when the last health check of the cluster failed.
+	HealthUnreachable = "unreachable"
+	// HealthStale is reported when the cluster has not been reached for longer than the stale threshold, so the
+	// data watched from it may be out of date.
+	HealthStale = "stale"
+	// HealthCredentialExpiring is reported when the credentials of the cluster expire within the warning threshold.
+	HealthCredentialExpiring = "credential-expiring"
+	// HealthCredentialExpired is reported when the credentials of the cluster are expired.
+	HealthCredentialExpired = "credential-expired"
+)
+
+// The health metrics of the remote clusters. They are read from the ClusterStore at collection time, rather than
+// recorded, so that the series of a cluster are removed with it.
+var (
+	clusterLabelKey = metricdata.LabelKey{Key: "cluster"}
+
+	clusterHealthy = metricdata.Descriptor{
+		Name:        "istiod_remote_cluster_healthy",
+		Description: "Whether the remote cluster is healthy (1) or not (0), as observed by the last health check.",
+		Unit:        metricdata.UnitDimensionless,
+		Type:        metricdata.TypeGaugeFloat64,
+		LabelKeys:   []metricdata.LabelKey{clusterLabelKey},
+	}
+
+	clusterAuthFailures = metricdata.Descriptor{
+		Name:        "istiod_remote_cluster_auth_failures_total",
+		Description: "Number of health checks of the remote cluster rejected because of its credentials.",
+		Unit:        metricdata.UnitDimensionless,
+		Type:        metricdata.TypeCumulativeInt64,
+		LabelKeys:   []metricdata.LabelKey{clusterLabelKey},
+	}
+
+	clusterCredentialExpiry = metricdata.Descriptor{
+		Name: "istiod_remote_cluster_credential_expiry_seconds",
+		Description: "The number of seconds until the credentials of the remote cluster expire. A negative value indicates " +
+			"the credentials are expired.",
+		Unit:      metricdata.UnitDimensionless,
+		Type:      metricdata.TypeGaugeFloat64,
+		LabelKeys: []metricdata.LabelKey{clusterLabelKey},
+	}
+
+	clusterLastContact = metricdata.Descriptor{
+		Name:        "istiod_remote_cluster_last_contact_seconds",
+		Description: "The number of seconds since the remote cluster was last reached.",
+		Unit:        metricdata.UnitDimensionless,
+		Type:        metricdata.TypeGaugeFloat64,
+		LabelKeys:   []metricdata.LabelKey{clusterLabelKey},
+	}
+)
+
+// clusterHealth tracks the health of a remote cluster, as observed by the periodic health checks and its watches.
+type clusterHealth struct {
+	mu sync.RWMutex
+	// credentialExpiry is the expiry of the credentials in the kubeconfig of the cluster, zero if unknown.
+	credentialExpiry time.Time
+	// created is when the cluster was added, the start of the auth failures count.
+	created time.Time
+	// lastContact is when the last health check succeeded.
+	lastContact time.Time
+	// lastErr is the error of the last health check, if it failed.
+	lastErr error
+	// lastFailure is the error of the last failed health check, and lastFailureTime when it failed.
+	lastFailure     error
+	lastFailureTime time.Time
+	authFailures    int64
+	checked         bool
+	// watchFailingSince returns when the watches of the cluster started failing, if they are failing.
+	watchFailingSince func(now time.Time) (time.Time, bool)
+}
+
+func newClusterHealth(id cluster.ID, kubeConfig []byte, now time.Time) *clusterHealth {
+	return &clusterHealth{
+		credentialExpiry: CredentialExpiry(kubeConfig),
+		created:          now,
+		watchFailingSince: func(now time.Time) (time.Time, bool) {
+			return informermetric.WatchFailingSince(id, now)
+		},
+	}
+}
+
+// CredentialExpiry returns the expiry of the client certificate or the token of the current context of the
+// kubeconfig, or the zero time if it is not known.
+func CredentialExpiry(kubeConfig []byte) time.Time {
+	cfg, err := clientcmd.Load(kubeConfig)
+	if err != nil {
+		return time.Time{}
+	}
+	kubeContext := cfg.Contexts[cfg.CurrentContext]
+	if kubeContext == nil {
+		return time.Time{}
+	}
+	auth := cfg.AuthInfos[kubeContext.AuthInfo]
+	if auth == nil {
+		return time.Time{}
+	}
+	if len(auth.ClientCertificateData) > 0 {
+		if cert, err := pkiutil.ParsePemEncodedCertificate(auth.ClientCertificateData); err == nil {
+			return cert.NotAfter
+		}
+	}
+	if auth.Token != "" {
+		if exp, err := util.GetExp(auth.Token); err == nil {
+			return exp
+		}
+	}
+	return time.Time{}
+}
+
+func (h *clusterHealth) record(now time.Time, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checked = true
+	h.lastErr = err
+	if err == nil {
+		h.lastContact = now
+	} else {
+		h.lastFailure = err
+		h.lastFailureTime = now
+		if isAuthFailure(err) {
+			h.authFailures++
+		}
+	}
+}
+
+// status returns the health of the cluster, and a message detailing it when it is not healthy.
+func (h *clusterHealth) status(now time.Time) (string, string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	expiring := !h.credentialExpiry.IsZero() && h.credentialExpiry.Sub(now) < features.RemoteClusterCredentialExpiryWarning
+	// The data of the cluster is stale if its watches keep failing, whether or not its API server is reachable
+	var watchFailingSince time.Time
+	watchFailing := false
+	if h.watchFailingSince != nil {
+		watchFailingSince, watchFailing = h.watchFailingSince(now)
+	}
+	switch {
+	case !h.credentialExpiry.IsZero() && !now.Before(h.credentialExpiry):
+		return HealthCredentialExpired, fmt.Sprintf("credentials expired at %v", h.credentialExpiry.Format(time.RFC3339))
+	case h.lastErr != nil && isAuthFailure(h.lastErr):
+		return HealthAuthFailure, h.lastErr.Error()
+	case watchFailing && now.Sub(watchFailingSince) > features.RemoteClusterStaleThreshold:
+		return HealthStale, fmt.Sprintf("watches failing since %v", watchFailingSince.Format(time.RFC3339))
+	case h.lastErr != nil:
+		return HealthUnreachable, h.lastErr.Error()
+	case expiring:
+		return HealthCredentialExpiring, fmt.Sprintf("credentials expire at %v", h.credentialExpiry.Format(time.RFC3339))
+	case !h.checked:
+		return HealthUnknown, ""
+	}
+	return HealthHealthy, ""
+}
+
+// since returns when the cluster was last reached, or added if it never was.
+func (h *clusterHealth) since() time.Time {
+	if h.lastContact.IsZero() {
+		return h.created
+	}
+	return h.lastContact
+}
+
+func isAuthFailure(err error) bool {
+	return apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err)
+}
+
+// checkHealth probes the API server of the cluster, recording the outcome in the health of the cluster.
+func (r *Cluster) checkHealth(now time.Time) {
+	_, err := r.Client.Kube().Discovery().ServerVersion()
+	r.health.record(now, err)
+	if status, msg := r.health.status(now); status != HealthHealthy && status != HealthCredentialExpiring {
+		log.Warnf("remote cluster %s is %s: %s", r.ID, status, msg)
+	}
+}
+
+// runHealthChecks periodically checks the health of the remote clusters, until stop is closed.
+func (c *Controller) runHealthChecks(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.checkHealth(time.Now())
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (c *Controller) checkHealth(now time.Time) {
+	for _, clusters := range c.cs.All() {
+		for _, remote := range clusters {
+			if remote.health == nil || remote.Closed() {
+				continue
+			}
+			remote.checkHealth(now)
+		}
+	}
+}
+
+// clusterHealthInfo fills the health of the remote cluster in its debug info.
+func clusterHealthInfo(info *cluster.DebugInfo, remote *Cluster, now time.Time) {
+	if remote.health == nil {
+		return
+	}
+	info.Health, info.HealthMessage = remote.health.status(now)
+	remote.health.mu.RLock()
+	defer remote.health.mu.RUnlock()
+	if !remote.health.credentialExpiry.IsZero() {
+		expiry := remote.health.credentialExpiry
+		info.CredentialExpiry = &expiry
+	}
+	if remote.health.lastFailure != nil {
+		failed := remote.health.lastFailureTime
+		info.LastError = remote.health.lastFailure.Error()
+		info.LastErrorTime = &failed
+	}
+}
+
+// healthMetrics produces the health metrics of the remote clusters of the controller.
+type healthMetrics struct {
+	c *Controller
+}
+
+var _ metricproducer.Producer = healthMetrics{}
+
+// Read implements metricproducer.Producer
+func (m healthMetrics) Read() []*metricdata.Metric {
+	now := time.Now()
+	healthy := &metricdata.Metric{Descriptor: clusterHealthy}
+	authFailures := &metricdata.Metric{Descriptor: clusterAuthFailures}
+	credentialExpiry := &metricdata.Metric{Descriptor: clusterCredentialExpiry}
+	lastContact := &metricdata.Metric{Descriptor: clusterLastContact}
+	for _, clusters := range m.c.cs.All() {
+		for id, remote := range clusters {
+			if remote.health == nil || remote.Closed() {
+				continue
+			}
+			labels := []metricdata.LabelValue{metricdata.NewLabelValue(string(id))}
+			timeSeries := func(p metricdata.Point) *metricdata.TimeSeries {
+				return &metricdata.TimeSeries{LabelValues: labels, Points: []metricdata.Point{p}}
+			}
+			status, _ := remote.health.status(now)
+			value := 0.0
+			if status == HealthHealthy {
+				value = 1
+			}
+			healthy.TimeSeries = append(healthy.TimeSeries, timeSeries(metricdata.NewFloat64Point(now, value)))
+
+			remote.health.mu.RLock()
+			failures := timeSeries(metricdata.NewInt64Point(now, remote.health.authFailures))
+			failures.StartTime = remote.health.created
+			authFailures.TimeSeries = append(authFailures.TimeSeries, failures)
+			if !remote.health.credentialExpiry.IsZero() {
+				credentialExpiry.TimeSeries = append(credentialExpiry.TimeSeries,
+					timeSeries(metricdata.NewFloat64Point(now, remote.health.credentialExpiry.Sub(now).Seconds())))
+			}
+			lastContact.TimeSeries = append(lastContact.TimeSeries,
+				timeSeries(metricdata.NewFloat64Point(now, now.Sub(remote.health.since()).Seconds())))
+			remote.health.mu.RUnlock()
+		}
+	}
+	return []*metricdata.Metric{healthy, authFailures, credentialExpiry, lastContact}
+}
+
+// runHealthMetrics produces the health metrics of the remote clusters until stop is closed.
+func (c *Controller) runHealthMetrics(stop <-chan struct{}) {
+	p := healthMetrics{c: c}
+	metricproducer.GlobalManager().AddProducer(p)
+	<-stop
+	metricproducer.GlobalManager().DeleteProducer(p)
+}
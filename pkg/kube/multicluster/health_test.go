@@ -0,0 +1,229 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/retry"
+	pkiutil "istio.io/istio/security/pkg/pki/util"
+)
+
+// tokenKubeConfig returns a kubeconfig authenticating with a token expiring at exp.
+func tokenKubeConfig(t *testing.T, exp time.Time) []byte {
+	t.Helper()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return kubeConfigWith(t, &api.AuthInfo{Token: "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2ln"})
+}
+
+func kubeConfigWith(t *testing.T, auth *api.AuthInfo) []byte {
+	t.Helper()
+	cfg := api.NewConfig()
+	cfg.Clusters["remote"] = &api.Cluster{Server: "https://remote:6443"}
+	cfg.AuthInfos["remote"] = auth
+	cfg.Contexts["remote"] = &api.Context{Cluster: "remote", AuthInfo: "remote"}
+	cfg.CurrentContext = "remote"
+	out, err := clientcmd.Write(*cfg)
+	assert.NoError(t, err)
+	return out
+}
+
+func TestCredentialExpiry(t *testing.T) {
+	exp := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	assert.Equal(t, CredentialExpiry(tokenKubeConfig(t, exp)), exp)
+
+	cert, key, err := pkiutil.GenCertKeyFromOptions(pkiutil.CertOptions{
+		Host:         "remote",
+		NotBefore:    time.Now(),
+		TTL:          24 * time.Hour,
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	assert.NoError(t, err)
+	parsed, err := pkiutil.ParsePemEncodedCertificate(cert)
+	assert.NoError(t, err)
+	assert.Equal(t, CredentialExpiry(kubeConfigWith(t, &api.AuthInfo{ClientCertificateData: cert, ClientKeyData: key})), parsed.NotAfter)
+
+	assert.Equal(t, CredentialExpiry(kubeConfigWith(t, &api.AuthInfo{Username: "admin", Password: "secret"})), time.Time{})
+	assert.Equal(t, CredentialExpiry([]byte("not a kubeconfig")), time.Time{})
+}
+
+// watchFailingSince returns the watch failures of a cluster whose watches are failing since the time.
+func watchFailingSince(since time.Time) func(time.Time) (time.Time, bool) {
+	return func(time.Time) (time.Time, bool) { return since, true }
+}
+
+func TestClusterHealthStatus(t *testing.T) {
+	now := time.Now()
+	unauthorized := apierrors.NewUnauthorized("token expired")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{}, "", errors.New("denied"))
+	unreachable := errors.New("connection refused")
+	cases := []struct {
+		name   string
+		health *clusterHealth
+		want   string
+	}{
+		{"unknown", &clusterHealth{created: now}, HealthUnknown},
+		{"healthy", &clusterHealth{created: now, checked: true, lastContact: now}, HealthHealthy},
+		{"unauthorized", &clusterHealth{created: now, checked: true, lastErr: unauthorized}, HealthAuthFailure},
+		{"forbidden", &clusterHealth{created: now, checked: true, lastErr: forbidden}, HealthAuthFailure},
+		{"unreachable", &clusterHealth{created: now, checked: true, lastContact: now.Add(-time.Minute), lastErr: unreachable}, HealthUnreachable},
+		{"unreachable for long", &clusterHealth{created: now, checked: true, lastContact: now.Add(-time.Hour), lastErr: unreachable}, HealthUnreachable},
+		{
+			"stale",
+			&clusterHealth{created: now, checked: true, lastContact: now, watchFailingSince: watchFailingSince(now.Add(-time.Hour))},
+			HealthStale,
+		},
+		{
+			"watches failing recently",
+			&clusterHealth{created: now, checked: true, lastContact: now, watchFailingSince: watchFailingSince(now.Add(-time.Second))},
+			HealthHealthy,
+		},
+		{
+			"expiring",
+			&clusterHealth{created: now, checked: true, lastContact: now, credentialExpiry: now.Add(time.Hour)},
+			HealthCredentialExpiring,
+		},
+		{
+			"expired",
+			&clusterHealth{created: now, checked: true, lastErr: unauthorized, credentialExpiry: now.Add(-time.Hour)},
+			HealthCredentialExpired,
+		},
+		{
+			"not expiring soon",
+			&clusterHealth{created: now, checked: true, lastContact: now, credentialExpiry: now.Add(30 * 24 * time.Hour)},
+			HealthHealthy,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := tt.health.status(now)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestRemoteClusterHealth(t *testing.T) {
+	BuildClientsFromConfig = func(kubeConfig []byte, c cluster.ID) (kube.Client, error) {
+		return kube.NewFakeClient(), nil
+	}
+	clientset := kube.NewFakeClient()
+	stop := test.NewStop(t)
+	c := NewController(clientset, secretNamespace, "config", mesh.NewFixedWatcher(nil))
+	_ = c.Run(stop)
+	retry.UntilOrFail(t, c.HasSynced)
+
+	remoteHealth := func() cluster.DebugInfo {
+		t.Helper()
+		var info cluster.DebugInfo
+		retry.UntilSuccessOrFail(t, func() error {
+			clusters := c.ListRemoteClusters()
+			if len(clusters) != 1 {
+				return fmt.Errorf("expected 1 remote cluster, got %d", len(clusters))
+			}
+			info = clusters[0]
+			return nil
+		})
+		return info
+	}
+
+	secrets := clientset.Kube().CoreV1().Secrets(secretNamespace)
+	expiring := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	_, err := secrets.Create(context.TODO(), makeSecret("s0", clusterCredential{"c0", tokenKubeConfig(t, expiring)}), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	info := remoteHealth()
+	assert.Equal(t, info.Health, HealthCredentialExpiring)
+	assert.Equal(t, *info.CredentialExpiry, expiring)
+
+	// Rotating the credentials reloads the cluster with them
+	rotated := time.Unix(time.Now().Add(30*24*time.Hour).Unix(), 0)
+	_, err = secrets.Update(context.TODO(), makeSecret("s0", clusterCredential{"c0", tokenKubeConfig(t, rotated)}), metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	retry.UntilSuccessOrFail(t, func() error {
+		if info := remoteHealth(); info.CredentialExpiry == nil || !info.CredentialExpiry.Equal(rotated) {
+			return fmt.Errorf("expected credentials expiring at %v, got %v", rotated, info.CredentialExpiry)
+		}
+		return nil
+	})
+	assert.Equal(t, remoteHealth().Health, HealthUnknown)
+	c.checkHealth(time.Now())
+	assert.Equal(t, remoteHealth().Health, HealthHealthy)
+
+	series := func() map[string]int {
+		out := map[string]int{}
+		for _, m := range (healthMetrics{c: c}).Read() {
+			out[m.Descriptor.Name] = len(m.TimeSeries)
+		}
+		return out
+	}
+	assert.Equal(t, series(), map[string]int{
+		clusterHealthy.Name:          1,
+		clusterAuthFailures.Name:     1,
+		clusterCredentialExpiry.Name: 1,
+		clusterLastContact.Name:      1,
+	})
+
+	// The metrics of a removed cluster are removed with it
+	assert.NoError(t, secrets.Delete(context.TODO(), "s0", metav1.DeleteOptions{}))
+	retry.UntilSuccessOrFail(t, func() error {
+		if n := len(c.ListRemoteClusters()); n != 0 {
+			return fmt.Errorf("expected no remote cluster, got %d", n)
+		}
+		return nil
+	})
+	assert.Equal(t, series(), map[string]int{
+		clusterHealthy.Name:          0,
+		clusterAuthFailures.Name:     0,
+		clusterCredentialExpiry.Name: 0,
+		clusterLastContact.Name:      0,
+	})
+}
+
+func TestClusterHealthLastError(t *testing.T) {
+	now := time.Now()
+	remote := &Cluster{health: &clusterHealth{created: now}}
+	info := func() cluster.DebugInfo {
+		var info cluster.DebugInfo
+		clusterHealthInfo(&info, remote, now)
+		return info
+	}
+	assert.Equal(t, info().LastError, "")
+
+	remote.health.record(now, errors.New("connection refused"))
+	assert.Equal(t, info().LastError, "connection refused")
+	assert.Equal(t, *info().LastErrorTime, now)
+
+	// The last error is kept once the cluster recovers
+	remote.health.record(now.Add(time.Minute), nil)
+	assert.Equal(t, info().Health, HealthHealthy)
+	assert.Equal(t, info().LastError, "connection refused")
+	assert.Equal(t, *info().LastErrorTime, now)
+}
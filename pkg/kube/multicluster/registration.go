@@ -0,0 +1,416 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/kube/watcher/crdwatcher"
+	"istio.io/istio/pkg/network"
+	"istio.io/pkg/log"
+)
+
+// ClusterRegistrationGVR is the resource of the ClusterRegistrations, registering remote clusters declaratively.
+var ClusterRegistrationGVR = schema.GroupVersionResource{
+	Group:    "multicluster.istio.io",
+	Version:  "v1alpha1",
+	Resource: "clusterregistrations",
+}
+
+// clusterRegistrationCRDName is the name of the CustomResourceDefinition of the ClusterRegistrations.
+var clusterRegistrationCRDName = ClusterRegistrationGVR.Resource + "." + ClusterRegistrationGVR.Group
+
+const (
+	// ClusterCredentialLabel marks the Secrets holding the credentials of the registered clusters. Only these Secrets
+	// are watched.
+	ClusterCredentialLabel = "istio/clusterCredential"
+
+	// The keys of the credential Secrets, the keys of the service account token Secrets.
+	credentialToken = "token"
+	credentialCA    = "ca.crt"
+
+	// ConditionConnected reports whether the remote cluster of a registration is connected.
+	ConditionConnected = "Connected"
+	// ConditionSynced reports whether the resources of the remote cluster of a registration are synced.
+	ConditionSynced = "Synced"
+)
+
+// ClusterRegistration registers a remote cluster in the namespace of istiod, as an alternative to the remote secrets
+// holding raw kubeconfigs. For example:
+//
+//	apiVersion: multicluster.istio.io/v1alpha1
+//	kind: ClusterRegistration
+//	metadata:
+//	  name: cluster-2
+//	  namespace: istio-system
+//	spec:
+//	  server: https://cluster-2.example.com:6443
+//	  network: network-2
+//	  locality: us-east1/us-east1-b
+//	  credentialSecret: cluster-2-credentials
+//
+// The credential Secret, in the same namespace and labeled with ClusterCredentialLabel, holds the token and the
+// ca.crt of the API server, as the service account token Secrets do.
+type ClusterRegistration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterRegistrationSpec   `json:"spec"`
+	Status ClusterRegistrationStatus `json:"status,omitempty"`
+}
+
+// ClusterRegistrationSpec is the specification of a ClusterRegistration.
+type ClusterRegistrationSpec struct {
+	// ClusterID is the ID of the cluster, which defaults to the name of the registration.
+	ClusterID cluster.ID `json:"clusterID,omitempty"`
+	// Server is the address of the API server of the cluster.
+	Server string `json:"server"`
+	// Network is the default network of the endpoints of the cluster.
+	Network network.ID `json:"network,omitempty"`
+	// Locality is the default locality of the endpoints of the cluster, in the region/zone/subzone format.
+	Locality string `json:"locality,omitempty"`
+	// CredentialSecret is the name of the Secret holding the credentials of the cluster.
+	CredentialSecret string `json:"credentialSecret"`
+}
+
+// ClusterRegistrationStatus reports the connectivity and sync state of the cluster of a ClusterRegistration.
+type ClusterRegistrationStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ParseClusterRegistration parses the ClusterRegistration, defaulting its cluster ID.
+func ParseClusterRegistration(u *unstructured.Unstructured) (*ClusterRegistration, error) {
+	r := &ClusterRegistration{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, r); err != nil {
+		return nil, err
+	}
+	if r.Spec.ClusterID == "" {
+		r.Spec.ClusterID = cluster.ID(r.Name)
+	}
+	if r.Spec.Server == "" {
+		return r, fmt.Errorf("server is required")
+	}
+	if r.Spec.CredentialSecret == "" {
+		return r, fmt.Errorf("credentialSecret is required")
+	}
+	return r, nil
+}
+
+// kubeConfig returns the kubeconfig of the cluster with the credentials, so it is handled like the kubeconfigs of
+// the remote secrets.
+func (r ClusterRegistrationSpec) kubeConfig(credentials *corev1.Secret) ([]byte, error) {
+	token := credentials.Data[credentialToken]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("credential secret %s has no %s", credentials.Name, credentialToken)
+	}
+	name := string(r.ClusterID)
+	cfg := api.NewConfig()
+	cfg.Clusters[name] = &api.Cluster{
+		Server:                   r.Server,
+		CertificateAuthorityData: credentials.Data[credentialCA],
+	}
+	cfg.AuthInfos[name] = &api.AuthInfo{Token: string(token)}
+	cfg.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+	cfg.CurrentContext = name
+	return clientcmd.Write(*cfg)
+}
+
+// registrationKey is the key of the clusters of the registration in the ClusterStore, distinct from the keys of
+// the remote secrets.
+func registrationKey(key types.NamespacedName) string {
+	return "clusterregistration/" + key.String()
+}
+
+func (c *Controller) initRegistrations(client kube.Client, namespace string) {
+	c.registrationClient = client
+	c.registrationStatusWriter = atomic.NewBool(false)
+	c.registrationCRD = make(chan struct{})
+	c.crdWatcher = crdwatcher.NewController(client, c.onCRDEvent)
+	c.registrations = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.Dynamic().Resource(ClusterRegistrationGVR).Namespace(namespace).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.Dynamic().Resource(ClusterRegistrationGVR).Namespace(namespace).Watch(context.TODO(), opts)
+			},
+		},
+		&unstructured.Unstructured{}, 0, cache.Indexers{},
+	)
+	// Only the Secrets labeled as credentials are watched, not all the Secrets of the namespace
+	c.credentials = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.LabelSelector = ClusterCredentialLabel + "=true"
+				return client.Kube().CoreV1().Secrets(namespace).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.LabelSelector = ClusterCredentialLabel + "=true"
+				return client.Kube().CoreV1().Secrets(namespace).Watch(context.TODO(), opts)
+			},
+		},
+		&corev1.Secret{}, 0, cache.Indexers{},
+	)
+	_ = c.credentials.SetTransform(kube.StripUnusedFields)
+
+	c.registrationQueue = controllers.NewQueue("multicluster registration",
+		controllers.WithMaxAttempts(maxRetries),
+		controllers.WithReconciler(c.processRegistration))
+	_, _ = c.registrations.AddEventHandler(controllers.ObjectHandler(c.registrationQueue.AddObject))
+	// Rotating the credentials of a cluster reconnects it.
+	_, _ = c.credentials.AddEventHandler(controllers.ObjectHandler(func(o controllers.Object) {
+		for _, obj := range c.registrations.GetStore().List() {
+			u := obj.(*unstructured.Unstructured)
+			if name, _, _ := unstructured.NestedString(u.Object, "spec", "credentialSecret"); name == o.GetName() {
+				c.registrationQueue.AddObject(u)
+			}
+		}
+	}))
+}
+
+// onCRDEvent starts watching the ClusterRegistrations once their CRD is installed: the informer cannot be started
+// before, as it would never sync.
+func (c *Controller) onCRDEvent(name string) {
+	if name != clusterRegistrationCRDName {
+		return
+	}
+	c.registrationCRDOnce.Do(func() {
+		close(c.registrationCRD)
+	})
+}
+
+func (c *Controller) runRegistrations(stop <-chan struct{}) {
+	c.crdWatcher.Run(stop)
+	go c.credentials.Run(stop)
+	select {
+	case <-c.registrationCRD:
+	case <-stop:
+		return
+	}
+	go c.registrations.Run(stop)
+	if !kube.WaitForCacheSync(stop, c.registrations.HasSynced, c.credentials.HasSynced) {
+		log.Error("Failed to sync multicluster registrations controller cache")
+		return
+	}
+	c.registrationQueue.Run(stop)
+}
+
+// registrationsSynced returns whether the ClusterRegistrations presented at startup were processed. There is
+// nothing to wait for if their CRD is not installed.
+func (c *Controller) registrationsSynced() bool {
+	if !c.crdWatcher.HasSynced() {
+		return false
+	}
+	if _, installed, _ := c.crdWatcher.GetByKey(clusterRegistrationCRDName); !installed {
+		return true
+	}
+	return c.registrationQueue.HasSynced()
+}
+
+// RunRegistrationStatus writes the status of the ClusterRegistrations until stop is closed. It is run by the leader
+// istiod only, so that the replicas do not compete to write it; all the replicas connect the registered clusters.
+func (c *Controller) RunRegistrationStatus(stop <-chan struct{}) {
+	if c.registrations == nil {
+		return
+	}
+	c.registrationStatusWriter.Store(true)
+	// Write the status of the registrations processed before the leadership was acquired
+	for _, obj := range c.registrations.GetStore().List() {
+		c.registrationQueue.AddObject(obj.(*unstructured.Unstructured))
+	}
+	<-stop
+	c.registrationStatusWriter.Store(false)
+}
+
+func (c *Controller) processRegistration(key types.NamespacedName) error {
+	log.Infof("processing cluster registration %s", key)
+	obj, exists, err := c.registrations.GetIndexer().GetByKey(key.String())
+	if err != nil {
+		return fmt.Errorf("error fetching object %s: %v", key, err)
+	}
+	if !exists {
+		c.deleteSecret(registrationKey(key))
+		remoteClusters.Record(float64(c.cs.Len()))
+		return nil
+	}
+	u := obj.(*unstructured.Unstructured)
+	r, err := ParseClusterRegistration(u)
+	var conditions []metav1.Condition
+	if err != nil {
+		c.deleteSecret(registrationKey(key))
+		conditions = disconnected("InvalidRegistration", err)
+		err = nil
+	} else {
+		conditions, err = c.addRegistration(key, r.Spec)
+	}
+	remoteClusters.Record(float64(c.cs.Len()))
+	if r != nil && c.registrationStatusWriter.Load() {
+		if serr := c.updateRegistrationStatus(u, r.Status, conditions); serr != nil {
+			log.Warnf("failed to update the status of cluster registration %s: %v", key, serr)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error adding cluster registration %s: %v", key, err)
+	}
+	return nil
+}
+
+// addRegistration adds or updates the cluster of the registration, returning its status conditions.
+func (c *Controller) addRegistration(key types.NamespacedName, r ClusterRegistrationSpec) ([]metav1.Condition, error) {
+	storeKey := registrationKey(key)
+	// Delete the cluster if the registration now declares another one
+	for _, existing := range c.cs.GetExistingClustersFor(storeKey) {
+		if existing.ID != r.ClusterID {
+			c.deleteCluster(storeKey, existing)
+		}
+	}
+	logger := log.WithLabels("cluster", r.ClusterID, "registration", key)
+	if r.ClusterID == c.configClusterID {
+		logger.Infof("ignoring cluster as it would overwrite the config cluster")
+		return disconnected("InvalidRegistration", fmt.Errorf("cluster %s is the config cluster", r.ClusterID)), nil
+	}
+	prev := c.cs.Get(storeKey, r.ClusterID)
+	if prev == nil && c.cs.Contains(r.ClusterID) {
+		logger.Warnf("cluster has already been registered")
+		return disconnected("AlreadyRegistered", fmt.Errorf("cluster %s has already been registered", r.ClusterID)), nil
+	}
+
+	obj, exists, err := c.credentials.GetIndexer().GetByKey(key.Namespace + "/" + r.CredentialSecret)
+	if err != nil || !exists {
+		return disconnected("CredentialNotFound", fmt.Errorf("credential secret %s labeled %s=true not found",
+			r.CredentialSecret, ClusterCredentialLabel)), nil
+	}
+	kubeConfig, err := r.kubeConfig(obj.(*corev1.Secret))
+	if err != nil {
+		return disconnected("InvalidCredential", err), nil
+	}
+
+	action, callback := "Adding", c.handleAdd
+	if prev != nil {
+		kubeConfigSha := sha256.Sum256(kubeConfig)
+		if bytes.Equal(kubeConfigSha[:], prev.kubeConfigSha[:]) && prev.Network == r.Network && prev.Locality == r.Locality {
+			logger.Debugf("skipping update (registration is identical)")
+			return clusterConditions(prev), nil
+		}
+		action, callback = "Updating", c.handleUpdate
+	}
+	logger.Infof("%s cluster", action)
+	// The previous cluster keeps serving until the new one replaces it, so rotating the credentials does not
+	// disconnect the cluster, nor does a rotation to invalid credentials.
+	remoteCluster, err := c.createRemoteCluster(kubeConfig, string(r.ClusterID))
+	if err != nil {
+		logger.Errorf("%s cluster: create remote cluster failed: %v", action, err)
+		return disconnected("ConnectionFailed", err), err
+	}
+	remoteCluster.Network = r.Network
+	remoteCluster.Locality = r.Locality
+	if err := callback(remoteCluster, remoteCluster.stop); err != nil {
+		remoteCluster.Stop()
+		if prev != nil {
+			prev.Stop()
+		}
+		logger.Errorf("%s cluster: initialize cluster failed: %v", action, err)
+		c.cs.Delete(storeKey, remoteCluster.ID)
+		return disconnected("ConnectionFailed", err), err
+	}
+	logger.Infof("finished callback for cluster and starting to sync")
+	c.cs.Store(storeKey, remoteCluster.ID, remoteCluster)
+	if prev != nil {
+		prev.Stop()
+	}
+	go func() {
+		// Report the sync state once the cluster is synced, or the sync timed out.
+		if features.RemoteClusterTimeout > 0 {
+			time.AfterFunc(features.RemoteClusterTimeout, func() { c.registrationQueue.Add(key) })
+		}
+		remoteCluster.Run()
+		c.registrationQueue.Add(key)
+	}()
+	return clusterConditions(remoteCluster), nil
+}
+
+// clusterConditions returns the status conditions of the connected cluster.
+func clusterConditions(c *Cluster) []metav1.Condition {
+	synced := metav1.Condition{Type: ConditionSynced, Status: metav1.ConditionFalse, Reason: "Syncing",
+		Message: "The resources of the cluster are syncing"}
+	if c.SyncDidTimeout() {
+		synced.Reason = "SyncTimeout"
+		synced.Message = fmt.Sprintf("The resources of the cluster did not sync within %v", features.RemoteClusterTimeout)
+	} else if c.HasSynced() {
+		synced.Status = metav1.ConditionTrue
+		synced.Reason = "Synced"
+		synced.Message = "The resources of the cluster are synced"
+	}
+	return []metav1.Condition{
+		{Type: ConditionConnected, Status: metav1.ConditionTrue, Reason: "Connected", Message: "The cluster is connected"},
+		synced,
+	}
+}
+
+// disconnected returns the status conditions of a cluster which could not be connected.
+func disconnected(reason string, err error) []metav1.Condition {
+	return []metav1.Condition{
+		{Type: ConditionConnected, Status: metav1.ConditionFalse, Reason: reason, Message: err.Error()},
+		{Type: ConditionSynced, Status: metav1.ConditionFalse, Reason: "NotConnected", Message: "The cluster is not connected"},
+	}
+}
+
+// updateRegistrationStatus writes the status conditions to the registration, if they changed.
+func (c *Controller) updateRegistrationStatus(u *unstructured.Unstructured, status ClusterRegistrationStatus,
+	conditions []metav1.Condition,
+) error {
+	changed := false
+	for _, cond := range conditions {
+		if existing := meta.FindStatusCondition(status.Conditions, cond.Type); existing != nil && existing.Status == cond.Status &&
+			existing.Reason == cond.Reason && existing.Message == cond.Message {
+			continue
+		}
+		meta.SetStatusCondition(&status.Conditions, cond)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	st, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	u = u.DeepCopy()
+	u.Object["status"] = st
+	_, err = c.registrationClient.Dynamic().Resource(ClusterRegistrationGVR).Namespace(u.GetNamespace()).
+		UpdateStatus(context.TODO(), u, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,229 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/network"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+func TestParseClusterRegistration(t *testing.T) {
+	spec := map[string]any{
+		"server":           "https://cluster-2:6443",
+		"network":          "network-2",
+		"locality":         "region/zone",
+		"credentialSecret": "cluster-2-credentials",
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": ClusterRegistrationGVR.GroupVersion().String(),
+		"kind":       "ClusterRegistration",
+		"metadata":   map[string]any{"name": "cluster-2"},
+		"spec":       spec,
+	}}
+	r, err := ParseClusterRegistration(u)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Spec, ClusterRegistrationSpec{
+		ClusterID:        "cluster-2",
+		Server:           "https://cluster-2:6443",
+		Network:          "network-2",
+		Locality:         "region/zone",
+		CredentialSecret: "cluster-2-credentials",
+	})
+
+	spec["clusterID"] = "remote"
+	r, err = ParseClusterRegistration(u)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Spec.ClusterID, cluster.ID("remote"))
+
+	delete(spec, "credentialSecret")
+	_, err = ParseClusterRegistration(u)
+	assert.Error(t, err)
+}
+
+// registrationHandler records the clusters registered by the handlers.
+type registrationHandler struct {
+	mu       sync.Mutex
+	clusters map[cluster.ID]*Cluster
+}
+
+func (h *registrationHandler) ClusterAdded(cluster *Cluster, stop <-chan struct{}) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clusters[cluster.ID] = cluster
+	return nil
+}
+
+func (h *registrationHandler) ClusterUpdated(cluster *Cluster, stop <-chan struct{}) error {
+	return h.ClusterAdded(cluster, stop)
+}
+
+func (h *registrationHandler) ClusterDeleted(id cluster.ID) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clusters, id)
+	return nil
+}
+
+func (h *registrationHandler) network(id cluster.ID) (network.ID, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c, f := h.clusters[id]
+	if !f {
+		return "", false
+	}
+	return c.Network, true
+}
+
+func TestClusterRegistrations(t *testing.T) {
+	test.SetForTest(t, &features.EnableClusterRegistrations, true)
+	var servers sync.Map
+	BuildClientsFromConfig = func(kubeConfig []byte, c cluster.ID) (kube.Client, error) {
+		cfg, err := clientcmd.Load(kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Clusters[cfg.CurrentContext].Server == "https://unreachable:6443" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		servers.Store(c, cfg.Clusters[cfg.CurrentContext].Server)
+		return kube.NewFakeClient(), nil
+	}
+
+	clientset := kube.NewFakeClient()
+	stop := test.NewStop(t)
+	c := NewController(clientset, secretNamespace, "config", mesh.NewFixedWatcher(nil))
+	h := &registrationHandler{clusters: map[cluster.ID]*Cluster{}}
+	c.AddHandler(h)
+	_ = c.Run(stop)
+	// Without the CRD, there is no registration to wait for
+	retry.UntilOrFail(t, c.HasSynced)
+	go c.RunRegistrationStatus(stop)
+	createRegistrationCRD(t, clientset)
+
+	registrations := clientset.Dynamic().Resource(ClusterRegistrationGVR).Namespace(secretNamespace)
+	secrets := clientset.Kube().CoreV1().Secrets(secretNamespace)
+	registration := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": ClusterRegistrationGVR.GroupVersion().String(),
+		"kind":       "ClusterRegistration",
+		"metadata":   map[string]any{"name": "cluster-2", "namespace": secretNamespace},
+		"spec": map[string]any{
+			"server":           "https://cluster-2:6443",
+			"network":          "network-2",
+			"credentialSecret": "cluster-2-credentials",
+		},
+	}}
+	expectCondition := func(condition string, status metav1.ConditionStatus, reason string) {
+		t.Helper()
+		retry.UntilSuccessOrFail(t, func() error {
+			u, err := registrations.Get(context.TODO(), "cluster-2", metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			r, err := ParseClusterRegistration(u)
+			if err != nil {
+				return err
+			}
+			cond := meta.FindStatusCondition(r.Status.Conditions, condition)
+			if cond == nil || cond.Status != status || cond.Reason != reason {
+				return fmt.Errorf("expected %s condition %s with reason %s, got %+v", condition, status, reason, cond)
+			}
+			return nil
+		})
+	}
+	expectNetwork := func(want network.ID, registered bool) {
+		t.Helper()
+		retry.UntilSuccessOrFail(t, func() error {
+			got, f := h.network("cluster-2")
+			if f != registered || got != want {
+				return fmt.Errorf("expected cluster registered %v with network %q, got %v with %q", registered, want, f, got)
+			}
+			return nil
+		})
+	}
+
+	// The cluster is not connected without its credentials
+	_, err := registrations.Create(context.TODO(), registration, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	expectCondition(ConditionConnected, metav1.ConditionFalse, "CredentialNotFound")
+	expectNetwork("", false)
+
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-2-credentials",
+			Namespace: secretNamespace,
+			Labels:    map[string]string{ClusterCredentialLabel: "true"},
+		},
+		Data: map[string][]byte{"token": []byte("token"), "ca.crt": []byte("ca")},
+	}
+	_, err = secrets.Create(context.TODO(), credentials, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	expectCondition(ConditionConnected, metav1.ConditionTrue, "Connected")
+	expectCondition(ConditionSynced, metav1.ConditionTrue, "Synced")
+	expectNetwork("network-2", true)
+	server, _ := servers.Load(cluster.ID("cluster-2"))
+	assert.Equal(t, server, "https://cluster-2:6443")
+
+	// Changing the network of the cluster updates it
+	registration, err = registrations.Get(context.TODO(), "cluster-2", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, unstructured.SetNestedField(registration.Object, "network-3", "spec", "network"))
+	_, err = registrations.Update(context.TODO(), registration, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	expectNetwork("network-3", true)
+
+	// The previous connection is kept if the cluster cannot be connected with the new settings
+	registration, err = registrations.Get(context.TODO(), "cluster-2", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, unstructured.SetNestedField(registration.Object, "https://unreachable:6443", "spec", "server"))
+	_, err = registrations.Update(context.TODO(), registration, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	expectCondition(ConditionConnected, metav1.ConditionFalse, "ConnectionFailed")
+	expectNetwork("network-3", true)
+
+	assert.NoError(t, registrations.Delete(context.TODO(), "cluster-2", metav1.DeleteOptions{}))
+	expectNetwork("", false)
+}
+
+// createRegistrationCRD installs the CRD of the ClusterRegistrations. The metadata client fake is not kept in sync
+// with the apiextensions one, so the CRD is created in both.
+func createRegistrationCRD(t *testing.T, client kube.Client) {
+	t.Helper()
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: clusterRegistrationCRDName}}
+	_, err := client.Ext().ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), crd, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	fmd := client.Metadata().(*metadatafake.FakeMetadataClient).Resource(gvr.CustomResourceDefinition).(metadatafake.MetadataClient)
+	_, err = fmd.CreateFake(&metav1.PartialObjectMetadata{ObjectMeta: crd.ObjectMeta}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -36,12 +37,14 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/util/informermetric"
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
 	"istio.io/istio/pkg/kube/kclient"
 	filter "istio.io/istio/pkg/kube/namespace"
+	"istio.io/istio/pkg/kube/watcher/crdwatcher"
 	"istio.io/istio/pkg/util/sets"
 	"istio.io/pkg/log"
 	"istio.io/pkg/monitoring"
@@ -98,6 +101,17 @@ type Controller struct {
 	cs                        *ClusterStore
 
 	handlers []ClusterHandler
+
+	// registrations and credentials are set if remote clusters can be registered by ClusterRegistrations.
+	registrations       cache.SharedIndexInformer
+	credentials         cache.SharedIndexInformer
+	registrationQueue   controllers.Queue
+	registrationClient  kube.Client
+	crdWatcher          *crdwatcher.Controller
+	registrationCRD     chan struct{}
+	registrationCRDOnce sync.Once
+	// registrationStatusWriter is set while this istiod is the leader writing the status of the registrations.
+	registrationStatusWriter *atomic.Bool
 }
 
 // NewController returns a new secret controller
@@ -158,6 +172,9 @@ func NewController(kubeclientset kube.Client, namespace string, clusterID cluste
 		controllers.WithReconciler(controller.processItem))
 
 	_, _ = secretsInformer.AddEventHandler(controllers.ObjectHandler(controller.queue.AddObject))
+	if features.EnableClusterRegistrations {
+		controller.initRegistrations(informerClient, namespace)
+	}
 	return controller
 }
 
@@ -178,6 +195,13 @@ func (c *Controller) Run(stopCh <-chan struct{}) error {
 		log.Info("Starting multicluster remote secrets controller")
 
 		go c.informer.Run(stopCh)
+		if c.registrations != nil {
+			go c.runRegistrations(stopCh)
+		}
+		if features.RemoteClusterHealthCheckInterval > 0 {
+			go c.runHealthChecks(features.RemoteClusterHealthCheckInterval, stopCh)
+		}
+		go c.runHealthMetrics(stopCh)
 
 		if !kube.WaitForCacheSync(stopCh, c.informer.HasSynced) {
 			log.Error("Failed to sync multicluster remote secrets controller cache")
@@ -195,6 +219,10 @@ func (c *Controller) HasSynced() bool {
 		// we haven't finished processing the secrets that were present at startup
 		return false
 	}
+	if c.registrations != nil && !c.registrationsSynced() {
+		log.Debug("secret controller did not sync cluster registrations presented at startup")
+		return false
+	}
 	c.cs.RLock()
 	defer c.cs.RUnlock()
 	for _, clusterMap := range c.cs.remoteClusters {
@@ -329,6 +357,7 @@ func (c *Controller) createRemoteCluster(kubeConfig []byte, clusterID string) (*
 		initialSync:        atomic.NewBool(false),
 		initialSyncTimeout: atomic.NewBool(false),
 		kubeConfigSha:      sha256.Sum256(kubeConfig),
+		health:             newClusterHealth(cluster.ID(clusterID), kubeConfig, time.Now()),
 	}, nil
 }
 
@@ -413,6 +442,7 @@ func (c *Controller) deleteCluster(secretKey string, cluster *Cluster) {
 			cluster.ID, secretKey, err)
 	}
 	c.cs.Delete(secretKey, cluster.ID)
+	informermetric.ForgetWatchFailures(cluster.ID)
 
 	log.Infof("Number of remote clusters: %d", c.cs.Len())
 }
@@ -444,6 +474,7 @@ func (c *Controller) handleDelete(key cluster.ID) error {
 // ListRemoteClusters provides debug info about connected remote clusters.
 func (c *Controller) ListRemoteClusters() []cluster.DebugInfo {
 	var out []cluster.DebugInfo
+	now := time.Now()
 	for secretName, clusters := range c.cs.All() {
 		for clusterID, c := range clusters {
 			syncStatus := "syncing"
@@ -454,11 +485,13 @@ func (c *Controller) ListRemoteClusters() []cluster.DebugInfo {
 			} else if c.HasSynced() {
 				syncStatus = "synced"
 			}
-			out = append(out, cluster.DebugInfo{
+			info := cluster.DebugInfo{
 				ID:         clusterID,
 				SecretName: secretName,
 				SyncStatus: syncStatus,
-			})
+			}
+			clusterHealthInfo(&info, c, now)
+			out = append(out, info)
 		}
 	}
 	return out
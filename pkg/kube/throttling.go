@@ -0,0 +1,122 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/rest"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+
+	"istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
+)
+
+const (
+	// clientThrottleLatency is the wait for the client side rate limiter from which a request is considered
+	// throttled, the threshold from which client-go logs the throttling.
+	clientThrottleLatency = 50 * time.Millisecond
+
+	// flowSchemaUIDHeader is set by the API Priority and Fairness of the API server to the FlowSchema classifying
+	// the request.
+	flowSchemaUIDHeader = "X-Kubernetes-PF-FlowSchema-UID"
+)
+
+var (
+	throttleReasonTag = monitoring.MustCreateLabel("reason")
+
+	kubeClientThrottled = monitoring.NewSum(
+		"kube_client_throttled_requests_total",
+		"Requests to the Kubernetes API server throttled, by the client side rate limiter (reason=client) or by "+
+			"the API Priority and Fairness of the API server (reason=server).",
+		monitoring.WithLabels(throttleReasonTag),
+	)
+
+	clientThrottled = kubeClientThrottled.With(throttleReasonTag.Value("client"))
+	serverThrottled = kubeClientThrottled.With(throttleReasonTag.Value("server"))
+)
+
+func init() {
+	monitoring.MustRegister(kubeClientThrottled)
+	clientmetrics.Register(clientmetrics.RegisterOpts{RateLimiterLatency: rateLimiterLatency{}})
+}
+
+// rateLimiterLatency records the requests delayed by the client side rate limiter.
+type rateLimiterLatency struct{}
+
+func (rateLimiterLatency) Observe(_ context.Context, _ string, _ url.URL, latency time.Duration) {
+	if latency >= clientThrottleLatency {
+		clientThrottled.Increment()
+	}
+}
+
+// SetThrottlingRetries sets the number of times the requests throttled by the API Priority and Fairness of the API
+// server are retried, after the delay of the Retry-After header of the responses. Unlike the retries of client-go,
+// which are not configurable, the requests are retried at most maxRetries times, 0 disabling the retries.
+func SetThrottlingRetries(config *rest.Config, maxRetries int) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttlingRoundTripper{rt: rt, maxRetries: maxRetries}
+	})
+}
+
+type throttlingRoundTripper struct {
+	rt         http.RoundTripper
+	maxRetries int
+}
+
+func (t *throttlingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retries := 0; ; retries++ {
+		resp, err := t.rt.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		serverThrottled.Increment()
+		seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil {
+			// Not a throttling of the API Priority and Fairness, left to client-go.
+			return resp, nil
+		}
+		if retries >= t.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			// client-go retries the responses with a Retry-After header, the retries are over.
+			resp.Header.Del("Retry-After")
+			return resp, nil
+		}
+		log.Debugf("request %s %s throttled by the API server (flow schema %s), retrying in %ds",
+			req.Method, req.URL.Path, resp.Header.Get(flowSchemaUIDHeader), seconds)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(time.Duration(seconds) * time.Second)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
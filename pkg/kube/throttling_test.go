@@ -0,0 +1,92 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/atomic"
+
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestThrottlingRoundTripper(t *testing.T) {
+	cases := []struct {
+		name       string
+		throttled  int32
+		maxRetries int
+		retryAfter string
+		wantCode   int
+		wantCalls  int32
+		wantHeader string
+	}{
+		{
+			name:       "retried",
+			throttled:  2,
+			maxRetries: 2,
+			retryAfter: "0",
+			wantCode:   http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "retries exhausted",
+			throttled:  3,
+			maxRetries: 1,
+			retryAfter: "0",
+			wantCode:   http.StatusTooManyRequests,
+			wantCalls:  2,
+			// Removed so client-go does not retry again.
+			wantHeader: "",
+		},
+		{
+			name:       "no retry-after",
+			throttled:  1,
+			maxRetries: 1,
+			wantCode:   http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := atomic.NewInt32(0)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, string(body), "body")
+				if calls.Inc() <= tt.throttled {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			rt := &throttlingRoundTripper{rt: http.DefaultTransport, maxRetries: tt.maxRetries}
+			req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("body"))
+			resp, err := rt.RoundTrip(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tt.wantCode)
+			assert.Equal(t, calls.Load(), tt.wantCalls)
+			assert.Equal(t, resp.Header.Get("Retry-After"), tt.wantHeader)
+		})
+	}
+}
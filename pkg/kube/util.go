@@ -305,6 +305,22 @@ func StripUnusedFields(obj any) (any, error) {
 	return obj, nil
 }
 
+// StripUnusedFieldsAndLastApplied is a transform function for shared informers, like StripUnusedFields, which also
+// removes the last-applied-configuration annotation set by kubectl apply, a copy of the whole object.
+// It must only be used for the objects which are not written back with Update, as the annotation would be deleted.
+func StripUnusedFieldsAndLastApplied(obj any) (any, error) {
+	t, ok := obj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		// shouldn't happen
+		return obj, nil
+	}
+	// ManagedFields is large and we never use it
+	t.GetObjectMeta().SetManagedFields(nil)
+	// The last applied configuration is a copy of the object, and we never use it
+	delete(t.GetObjectMeta().GetAnnotations(), corev1.LastAppliedConfigAnnotation)
+	return obj, nil
+}
+
 // StripNodeUnusedFields is the transform function for shared node informers,
 // it removes unused fields from objects before they are stored in the cache to save memory.
 func StripNodeUnusedFields(obj any) (any, error) {
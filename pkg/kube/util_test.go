@@ -383,3 +383,32 @@ func TestStripUnusedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestStripUnusedFieldsAndLastApplied(t *testing.T) {
+	obj := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "bar",
+			Annotations: map[string]string{
+				"c":                                "d",
+				corev1.LastAppliedConfigAnnotation: "{}",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager: "whatever",
+				},
+			},
+		},
+	}
+	want := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "foo",
+			Name:        "bar",
+			Annotations: map[string]string{"c": "d"},
+		},
+	}
+	got, _ := StripUnusedFieldsAndLastApplied(obj)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StripUnusedFieldsAndLastApplied: got %v, want %v", got, want)
+	}
+}
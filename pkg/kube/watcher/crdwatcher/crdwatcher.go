@@ -18,17 +18,20 @@ import (
 	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 
 	"istio.io/istio/pkg/config/schema/gvr"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
 	"istio.io/pkg/log"
 )
 
 // Controller watches a ConfigMap and calls the given callback when the ConfigMap changes.
 // The ConfigMap is passed to the callback, or nil if it doesn't exist.
 type Controller struct {
-	informer  cache.SharedIndexInformer
+	client    kube.Client
+	crds      kclient.Reader[*metav1.PartialObjectMetadata]
 	mutex     sync.RWMutex
 	callbacks []func(name string)
 }
@@ -36,12 +39,13 @@ type Controller struct {
 // NewController returns a new CRD watcher controller.
 func NewController(client kube.Client, callbacks ...func(name string)) *Controller {
 	c := &Controller{
+		client:    client,
 		callbacks: callbacks,
 	}
 
-	crdMetadataInformer := client.MetadataInformer().ForResource(gvr.CustomResourceDefinition).Informer()
-	_ = crdMetadataInformer.SetTransform(kube.StripUnusedFields)
-	_, _ = crdMetadataInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	// Only the names of the CRDs are needed, their schemas are not held in memory.
+	c.crds = kclient.NewMetadata(client, gvr.CustomResourceDefinition, kclient.Filter{})
+	c.crds.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			crd, ok := obj.(*metav1.PartialObjectMetadata)
 			if !ok {
@@ -58,28 +62,34 @@ func NewController(client kube.Client, callbacks ...func(name string)) *Controll
 		UpdateFunc: nil,
 		DeleteFunc: nil,
 	})
-
-	c.informer = crdMetadataInformer
 	return c
 }
 
 func (c *Controller) Run(stop <-chan struct{}) {
-	go c.informer.Run(stop)
+	c.client.MetadataInformer().Start(stop)
 }
 
 // HasSynced returns whether the underlying cache has synced and the callback has been called at least once.
 func (c *Controller) HasSynced() bool {
-	return c.informer.HasSynced()
+	return c.crds.HasSynced()
 }
 
 // List returns a list of all the currently non-empty accumulators
 func (c *Controller) List() []any {
-	return c.informer.GetIndexer().List()
+	var res []any
+	for _, crd := range c.crds.List(metav1.NamespaceAll, klabels.Everything()) {
+		res = append(res, crd)
+	}
+	return res
 }
 
 // GetByKey returns the accumulator associated with the given key
 func (c *Controller) GetByKey(key string) (item any, exists bool, err error) {
-	return c.informer.GetIndexer().GetByKey(key)
+	crd := c.crds.Get(key, "")
+	if crd == nil {
+		return nil, false, nil
+	}
+	return crd, true, nil
 }
 
 func (c *Controller) AddCallBack(cb func(name string)) {
@@ -45,6 +45,10 @@ const (
 	// DefaultRootCertFilePath is the well-known path for an existing root certificate file
 	DefaultRootCertFilePath = "./etc/certs/root-cert.pem"
 
+	// DefaultCRLFilePath is the well-known path for the certificate revocation list of the CA, mounted from
+	// the istio-ca-root-cert ConfigMap.
+	DefaultCRLFilePath = "./var/run/secrets/istio/ca-crl.pem"
+
 	// WorkloadIdentitySocketPath is the well-known path to the Unix Domain Socket for SDS.
 	WorkloadIdentitySocketPath = "./var/run/secrets/workload-spiffe-uds/socket"
 
@@ -141,6 +145,10 @@ const (
 	// CertSigner info
 	CertSigner = "CertSigner"
 
+	// ExtraSANs declares the comma separated SANs requested on the certificate in addition to the identity of
+	// the workload. They must be allowed by the namespace of the workload.
+	ExtraSANs = "ExtraSANs"
+
 	// ImpersonatedIdentity declares the identity we are requesting a certificate on behalf of.
 	// This is constrained to only allow identities in CATrustedNodeAccounts, and only to impersonate identities
 	// on their node.
@@ -191,6 +199,9 @@ type Options struct {
 	// when generating private keys. Currently only ECDSA is supported.
 	ECCSigAlg string
 
+	// The elliptic curve of the private keys generated with ECCSigAlg, either P256 or P384.
+	ECCCurve string
+
 	// FileMountedCerts indicates whether the proxy is using file
 	// mounted certs created by a foreign CA. Refresh is managed by the external
 	// CA, by updating the Secret or VM file. We will watch the file for changes
@@ -243,6 +254,9 @@ type Options struct {
 	// Cert signer info
 	CertSigner string
 
+	// Additional DNS or URI SANs requested on the workload certificate
+	ExtraSANs []string
+
 	// Delay in reading certificates from file after the change is detected. This is useful in cases
 	// where the write operation of key and cert take longer.
 	FileDebounceDuration time.Duration
@@ -256,6 +270,9 @@ type Options struct {
 	KeyFilePath string
 	// The path for an existing root certificate bundle
 	RootCertFilePath string
+	// The path for the certificate revocation list of the CA. If the file exists, it is sent to the proxy
+	// along with the trust anchor, so that revoked workload certificates are rejected.
+	CRLFilePath string
 }
 
 // TokenManager contains methods for generating token.
@@ -335,6 +352,9 @@ type SecretItem struct {
 
 	RootCert []byte
 
+	// CRL is the certificate revocation list sent along with the root cert, if any.
+	CRL []byte
+
 	// ResourceName passed from envoy SDS discovery request.
 	// "ROOTCA" for root cert request, "default" for key/cert request.
 	ResourceName string
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/sync/singleflight"
 
 	extensions "istio.io/api/extensions/v1alpha1"
 	"istio.io/istio/pkg/util/sets"
@@ -66,6 +67,9 @@ type LocalFileCache struct {
 	// mux is needed because stale Wasm module files will be purged periodically.
 	mux sync.Mutex
 
+	// fetches shares the concurrent fetches of the same module, e.g. by the WasmPlugins using the same module.
+	fetches singleflight.Group
+
 	// option sets for configurating the cache.
 	cacheOptions
 	// stopChan currently is only used by test
@@ -134,6 +138,7 @@ func (o cacheOptions) sanitize() cacheOptions {
 	if o.HTTPRequestMaxRetries != 0 {
 		ret.HTTPRequestMaxRetries = o.HTTPRequestMaxRetries
 	}
+	ret.RequireHTTPChecksum = o.RequireHTTPChecksum
 
 	return ret
 }
@@ -222,6 +227,9 @@ func (c *LocalFileCache) getOrFetch(key cacheKey, opts GetOptions) (*cacheEntry,
 	if err != nil {
 		return nil, fmt.Errorf("fail to parse Wasm module fetch url: %s, error: %v", key.downloadURL, err)
 	}
+	if (u.Scheme == "http" || u.Scheme == "https") && opts.Checksum == "" && c.RequireHTTPChecksum {
+		return nil, fmt.Errorf("wasm module %s has no sha256 checksum, which is required for http and https modules", key.downloadURL)
+	}
 
 	// First check if the cache entry is already downloaded and policy does not require to pull always.
 	ce, checksum := c.getEntry(key, shouldIgnoreResourceVersion(opts.PullPolicy, u))
@@ -229,10 +237,39 @@ func (c *LocalFileCache) getOrFetch(key cacheKey, opts GetOptions) (*cacheEntry,
 		return ce, nil
 	}
 	key.checksum = checksum
+
 	// Fetch the image now as it is not available in cache.
+	v, err, _ := c.fetches.Do(key.downloadURL+"@"+key.checksum, func() (any, error) {
+		return c.fetch(key, opts, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+	f := v.(fetchedModule)
+	key.checksum = f.checksum
+	if f.binary == nil {
+		// The module was already in the cache.
+		if ce, _ := c.getEntry(key, true); ce != nil {
+			return ce, nil
+		}
+		return nil, fmt.Errorf("wasm module %s was purged from the cache while fetched", key.downloadURL)
+	}
+	return c.addEntry(key, f.binary)
+}
+
+// fetchedModule is a module fetched by fetch.
+type fetchedModule struct {
+	// binary is the module, nil if the module of the checksum is already in the cache.
+	binary []byte
+	// checksum is the hex-encoded sha256 checksum of the module.
+	checksum string
+}
+
+func (c *LocalFileCache) fetch(key cacheKey, opts GetOptions, u *url.URL) (fetchedModule, error) {
 	var b []byte         // Byte array of Wasm binary.
 	var dChecksum string // Hex-Encoded sha256 checksum of binary.
 	var binaryFetcher func() ([]byte, error)
+	var err error
 	insecure := c.allowInsecure(u.Host)
 
 	ctx, cancel := context.WithTimeout(context.Background(), opts.RequestTimeout)
@@ -240,10 +277,14 @@ func (c *LocalFileCache) getOrFetch(key cacheKey, opts GetOptions) (*cacheEntry,
 	switch u.Scheme {
 	case "http", "https":
 		// Download the Wasm module with http fetcher.
-		b, err = c.httpFetcher.Fetch(ctx, key.downloadURL, insecure)
+		attempts := c.HTTPRequestMaxRetries
+		if opts.MaxRetries != nil {
+			attempts = *opts.MaxRetries + 1
+		}
+		b, err = c.httpFetcher.FetchWithAttempts(ctx, key.downloadURL, insecure, attempts)
 		if err != nil {
 			wasmRemoteFetchCount.With(resultTag.Value(downloadFailure)).Increment()
-			return nil, err
+			return fetchedModule{}, err
 		}
 
 		// Get sha256 checksum and check if it is the same as provided one.
@@ -261,40 +302,38 @@ func (c *LocalFileCache) getOrFetch(key cacheKey, opts GetOptions) (*cacheEntry,
 		binaryFetcher, dChecksum, err = fetcher.PrepareFetch(u.Host + u.Path)
 		if err != nil {
 			wasmRemoteFetchCount.With(resultTag.Value(manifestFailure)).Increment()
-			return nil, fmt.Errorf("could not fetch Wasm OCI image: %v", err)
+			return fetchedModule{}, fmt.Errorf("could not fetch Wasm OCI image: %v", err)
 		}
 	default:
-		return nil, fmt.Errorf("unsupported Wasm module downloading URL scheme: %v", u.Scheme)
+		return fetchedModule{}, fmt.Errorf("unsupported Wasm module downloading URL scheme: %v", u.Scheme)
 	}
 
 	if key.checksum == "" {
 		key.checksum = dChecksum
 		// check again if the cache is having the checksum.
 		if ce, _ := c.getEntry(key, true); ce != nil {
-			return ce, nil
+			return fetchedModule{checksum: dChecksum}, nil
 		}
 	} else if dChecksum != key.checksum {
 		wasmRemoteFetchCount.With(resultTag.Value(checksumMismatch)).Increment()
-		return nil, fmt.Errorf("module downloaded from %v has checksum %v, which does not match: %v", key.downloadURL, dChecksum, key.checksum)
+		return fetchedModule{}, fmt.Errorf("module downloaded from %v has checksum %v, which does not match: %v", key.downloadURL, dChecksum, key.checksum)
 	}
 
 	if binaryFetcher != nil {
 		b, err = binaryFetcher()
 		if err != nil {
 			wasmRemoteFetchCount.With(resultTag.Value(downloadFailure)).Increment()
-			return nil, fmt.Errorf("could not fetch Wasm binary: %v", err)
+			return fetchedModule{}, fmt.Errorf("could not fetch Wasm binary: %v", err)
 		}
 	}
 
 	if !isValidWasmBinary(b) {
 		wasmRemoteFetchCount.With(resultTag.Value(fetchFailure)).Increment()
-		return nil, fmt.Errorf("fetched Wasm binary from %s is invalid", key.downloadURL)
+		return fetchedModule{}, fmt.Errorf("fetched Wasm binary from %s is invalid", key.downloadURL)
 	}
 
 	wasmRemoteFetchCount.With(resultTag.Value(fetchSuccess)).Increment()
-
-	key.checksum = dChecksum
-	return c.addEntry(key, b)
+	return fetchedModule{binary: b, checksum: dChecksum}, nil
 }
 
 // Cleanup closes background Wasm module purge routine.
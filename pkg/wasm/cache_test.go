@@ -958,3 +958,93 @@ func generateModulePath(t *testing.T, baseDir, resourceName, filename string) st
 	}
 	return filepath.Join(moduleDir, filename)
 }
+
+func TestWasmCacheSharedHTTPFetch(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewLocalFileCache(tmpDir, defaultOptions())
+	defer close(cache.stopChan)
+
+	binary := append(wasmHeader, 1)
+	var requests int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write(binary)
+	}))
+	defer ts.Close()
+
+	// Two WasmPlugins using the same module fetch it once.
+	errs := make(chan error, 2)
+	for _, resource := range []string{"namespace.a", "namespace.b"} {
+		resource := resource
+		go func() {
+			_, err := cache.Get(ts.URL, GetOptions{
+				Checksum:       fmt.Sprintf("%x", sha256.Sum256(binary)),
+				ResourceName:   resource,
+				RequestTimeout: time.Second * 10,
+			})
+			errs <- err
+		}()
+	}
+	// Let the second Get join the fetch of the first one.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("failed to download Wasm module: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("wasm download call got %v want 1", got)
+	}
+}
+
+func TestWasmCacheHTTPPullOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	options := defaultOptions()
+	options.RequireHTTPChecksum = true
+	cache := NewLocalFileCache(tmpDir, options)
+	cache.httpFetcher.initialBackoff = time.Millisecond
+	defer close(cache.stopChan)
+
+	binary := append(wasmHeader, 1)
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first two requests fail.
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(binary)
+	}))
+	defer ts.Close()
+
+	// Modules without checksum are rejected.
+	if _, err := cache.Get(ts.URL, GetOptions{ResourceName: "namespace.resource", RequestTimeout: time.Second * 10}); err == nil {
+		t.Fatalf("expected the module without checksum to be rejected")
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Fatalf("wasm download call got %v want 0", got)
+	}
+
+	// A single retry is not enough for the two failed requests, the third request succeeds.
+	get := func(retries int) error {
+		_, err := cache.Get(ts.URL, GetOptions{
+			Checksum:       fmt.Sprintf("%x", sha256.Sum256(binary)),
+			ResourceName:   "namespace.resource",
+			RequestTimeout: time.Second * 10,
+			MaxRetries:     &retries,
+		})
+		return err
+	}
+	if err := get(1); err == nil {
+		t.Fatalf("expected the download to fail after a single retry")
+	}
+	if err := get(1); err != nil {
+		t.Fatalf("failed to download Wasm module: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Fatalf("wasm download call got %v want 3", got)
+	}
+}
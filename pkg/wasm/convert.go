@@ -33,6 +33,7 @@ import (
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/bootstrap"
 	"istio.io/istio/pkg/config/xds"
+	"istio.io/istio/pkg/ptr"
 )
 
 var allowTypedConfig = protoconv.MessageToAny(&rbac.RBAC{})
@@ -223,6 +224,10 @@ func convertWasmConfigFromRemoteToLocal(ec *core.TypedExtensionConfig, wasmHTTPF
 	if remote.GetHttpUri().Timeout != nil {
 		timeout = remote.GetHttpUri().Timeout.AsDuration()
 	}
+	var maxRetries *int
+	if retries := remote.GetRetryPolicy().GetNumRetries(); retries != nil {
+		maxRetries = ptr.Of(int(retries.GetValue()))
+	}
 	// ec.Name is resourceName.
 	// https://github.com/istio/istio/blob/9ea7ad532a9cc58a3564143d41ac89a61aaa8058/pilot/pkg/networking/core/v1alpha3/extension/wasmplugin.go#L103
 	f, err := cache.Get(httpURI.GetUri(), GetOptions{
@@ -232,6 +237,7 @@ func convertWasmConfigFromRemoteToLocal(ec *core.TypedExtensionConfig, wasmHTTPF
 		RequestTimeout:  timeout,
 		PullSecret:      pullSecret,
 		PullPolicy:      pullPolicy,
+		MaxRetries:      maxRetries,
 	})
 	if err != nil {
 		status = fetchFailure
@@ -69,6 +69,11 @@ func NewHTTPFetcher(requestTimeout time.Duration, requestMaxRetry int) *HTTPFetc
 
 // Fetch downloads a wasm module with HTTP get.
 func (f *HTTPFetcher) Fetch(ctx context.Context, url string, allowInsecure bool) ([]byte, error) {
+	return f.FetchWithAttempts(ctx, url, allowInsecure, f.requestMaxRetry)
+}
+
+// FetchWithAttempts downloads a wasm module with HTTP get, in at most maxAttempts requests.
+func (f *HTTPFetcher) FetchWithAttempts(ctx context.Context, url string, allowInsecure bool, maxAttempts int) ([]byte, error) {
 	c := f.client
 	if allowInsecure {
 		c = f.insecureClient
@@ -78,7 +83,7 @@ func (f *HTTPFetcher) Fetch(ctx context.Context, url string, allowInsecure bool)
 	o.InitialInterval = f.initialBackoff
 	b := backoff.NewExponentialBackOff(o)
 	var lastError error
-	for attempts < f.requestMaxRetry {
+	for attempts < maxAttempts {
 		attempts++
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
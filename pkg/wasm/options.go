@@ -35,6 +35,9 @@ type Options struct {
 	InsecureRegistries    sets.String
 	HTTPRequestTimeout    time.Duration
 	HTTPRequestMaxRetries int
+	// RequireHTTPChecksum rejects the modules fetched over http or https without a sha256 checksum, so their content
+	// is pinned.
+	RequireHTTPChecksum bool
 }
 
 func defaultOptions() Options {
@@ -55,4 +58,6 @@ type GetOptions struct {
 	RequestTimeout  time.Duration
 	PullSecret      []byte
 	PullPolicy      extensions.PullPolicy
+	// MaxRetries overrides the number of retries of the HTTP requests fetching a module over http or https, if set.
+	MaxRetries *int
 }
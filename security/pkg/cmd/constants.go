@@ -28,6 +28,13 @@ const (
 	// rotation grace period, configured as the ratio of the certificate TTL.
 	DefaultRootCertGracePeriodPercentile = 20
 
+	// DefaultRootKeyRolloverPhaseDuration is the default minimum duration of each phase of a
+	// self-signed root key rollover.
+	DefaultRootKeyRolloverPhaseDuration = 48 * time.Hour
+
+	// DefaultIntermediateCertTTL is the default TTL of the intermediate certificates issued by the self-signed root.
+	DefaultIntermediateCertTTL = 365 * 24 * time.Hour
+
 	// ReadSigningCertRetryInterval specifies the time to wait between retries on reading the signing key and cert.
 	ReadSigningCertRetryInterval = time.Second * 5
 
@@ -31,14 +31,18 @@ import (
 // meta: the metadata of configmap.
 // caBundle: ca cert data bytes.
 func InsertDataToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.ObjectMeta, caBundle []byte) error {
+	return InsertCADataToConfigMap(client, meta, caBundle, nil)
+}
+
+// InsertCADataToConfigMap inserts the CA bundle and, if not empty, the certificate revocation list of the CA
+// to a configmap in a namespace. A previously inserted revocation list is removed if crl is empty.
+func InsertCADataToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.ObjectMeta, caBundle, crl []byte) error {
 	configmap := client.Get(meta.Name, meta.Namespace)
 	if configmap == nil {
 		// Create a new ConfigMap.
 		configmap = &v1.ConfigMap{
 			ObjectMeta: meta,
-			Data: map[string]string{
-				constants.CACertNamespaceConfigMapDataName: string(caBundle),
-			},
+			Data:       caData(caBundle, crl),
 		}
 		if _, err := client.Create(configmap); err != nil {
 			// Namespace may be deleted between now... and our previous check. Just skip this, we cannot create into deleted ns
@@ -50,7 +54,7 @@ func InsertDataToConfigMap(client kclient.Client[*v1.ConfigMap], meta metav1.Obj
 		}
 	} else {
 		// Otherwise, update the config map if changes are required
-		err := updateDataInConfigMap(client, configmap, caBundle)
+		err := updateCADataInConfigMap(client, configmap, caBundle, crl)
 		if err != nil {
 			return err
 		}
@@ -74,15 +78,31 @@ func insertData(cm *v1.ConfigMap, data map[string]string) bool {
 	return needsUpdate
 }
 
+func caData(caBundle, crl []byte) map[string]string {
+	data := map[string]string{
+		constants.CACertNamespaceConfigMapDataName: string(caBundle),
+	}
+	if len(crl) > 0 {
+		data[constants.CACRLNamespaceConfigMapDataName] = string(crl)
+	}
+	return data
+}
+
 func updateDataInConfigMap(c kclient.Client[*v1.ConfigMap], cm *v1.ConfigMap, caBundle []byte) error {
+	return updateCADataInConfigMap(c, cm, caBundle, nil)
+}
+
+func updateCADataInConfigMap(c kclient.Client[*v1.ConfigMap], cm *v1.ConfigMap, caBundle, crl []byte) error {
 	if cm == nil {
 		return fmt.Errorf("cannot update nil configmap")
 	}
 	newCm := cm.DeepCopy()
-	data := map[string]string{
-		constants.CACertNamespaceConfigMapDataName: string(caBundle),
+	needsUpdate := insertData(newCm, caData(caBundle, crl))
+	if _, f := newCm.Data[constants.CACRLNamespaceConfigMapDataName]; f && len(crl) == 0 {
+		delete(newCm.Data, constants.CACRLNamespaceConfigMapDataName)
+		needsUpdate = true
 	}
-	if needsUpdate := insertData(newCm, data); !needsUpdate {
+	if !needsUpdate {
 		return nil
 	}
 	if _, err := c.Update(newCm); err != nil {
@@ -15,6 +15,7 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -109,6 +110,53 @@ func TestUpdateDataInConfigMap(t *testing.T) {
 	}
 }
 
+func TestUpdateCADataInConfigMap(t *testing.T) {
+	crlData := map[string]string{
+		constants.CACertNamespaceConfigMapDataName: "test-data",
+		constants.CACRLNamespaceConfigMapDataName:  "test-crl",
+	}
+	testCases := []struct {
+		name              string
+		existingConfigMap *v1.ConfigMap
+		crl               string
+		expectedData      map[string]string
+	}{
+		{
+			name:              "add CRL",
+			existingConfigMap: createConfigMap(namespaceName, configMapName, map[string]string{}),
+			crl:               "test-crl",
+			expectedData:      crlData,
+		},
+		{
+			name:              "remove CRL",
+			existingConfigMap: createConfigMap(namespaceName, configMapName, crlData),
+			expectedData: map[string]string{
+				constants.CACertNamespaceConfigMapDataName: "test-data",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := kube.NewFakeClient()
+			configmaps := kclient.New[*v1.ConfigMap](kc)
+			if _, err := configmaps.Create(tc.existingConfigMap); err != nil {
+				t.Fatalf("failed to create configmap %v", err)
+			}
+			if err := updateCADataInConfigMap(configmaps, tc.existingConfigMap, []byte("test-data"), []byte(tc.crl)); err != nil {
+				t.Fatal(err)
+			}
+			cm, err := kc.Kube().CoreV1().ConfigMaps(namespaceName).Get(context.TODO(), configMapName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedData, cm.Data); diff != "" {
+				t.Fatalf("unexpected configmap data: %v", diff)
+			}
+		})
+	}
+}
+
 func TestInsertDataToConfigMap(t *testing.T) {
 	gvr := schema.GroupVersionResource{
 		Resource: "configmaps",
@@ -104,6 +104,8 @@ type SecretManagerClient struct {
 	// certs being watched with file watcher.
 	fileCerts map[FileCert]struct{}
 	certMutex sync.RWMutex
+	// crlWatch adds the file watcher of the certificate revocation list once it exists.
+	crlWatch sync.Once
 
 	// outputMutex protects writes of certificates to disk
 	outputMutex sync.Mutex
@@ -277,6 +279,7 @@ func (sc *SecretManagerClient) GenerateSecret(resourceName string) (secret *secu
 
 	ns := sc.getCachedSecret(resourceName)
 	if ns != nil {
+		sc.addCRL(ns)
 		return ns, nil
 	}
 
@@ -287,6 +290,7 @@ func (sc *SecretManagerClient) GenerateSecret(resourceName string) (secret *secu
 	// Now that we got the lock, look at cache again before sending request to avoid overwhelming CA
 	ns = sc.getCachedSecret(resourceName)
 	if ns != nil {
+		sc.addCRL(ns)
 		return ns, nil
 	}
 
@@ -305,6 +309,7 @@ func (sc *SecretManagerClient) GenerateSecret(resourceName string) (secret *secu
 
 	if resourceName == security.RootCertReqResourceName {
 		ns.RootCert = sc.mergeTrustAnchorBytes(ns.RootCert)
+		sc.addCRL(ns)
 	} else {
 		// If periodic cert refresh resulted in discovery of a new root, trigger a ROOTCA request to refresh trust anchor
 		oldRoot := sc.cache.GetRoot()
@@ -319,6 +324,27 @@ func (sc *SecretManagerClient) GenerateSecret(resourceName string) (secret *secu
 	return ns, nil
 }
 
+// addCRL attaches the certificate revocation list of the CA to a trust anchor secret, if the CRL file exists,
+// and watches the file so that revocations are pushed to the proxy.
+func (sc *SecretManagerClient) addCRL(secret *security.SecretItem) {
+	crlPath := sc.configOptions.CRLFilePath
+	if secret.ResourceName != security.RootCertReqResourceName || crlPath == "" {
+		return
+	}
+	if _, err := os.Stat(crlPath); err != nil {
+		return
+	}
+	crl, err := sc.readFileWithTimeout(crlPath)
+	if err != nil {
+		cacheLog.Errorf("failed to read certificate revocation list %s: %v", crlPath, err)
+		return
+	}
+	secret.CRL = crl
+	sc.crlWatch.Do(func() {
+		sc.addFileWatcher(crlPath, security.RootCertReqResourceName)
+	})
+}
+
 func (sc *SecretManagerClient) addFileWatcher(file string, resourceName string) {
 	// Try adding file watcher and if it fails start a retryloop.
 	if err := sc.tryAddFileWatcher(file, resourceName); err == nil {
@@ -574,12 +600,16 @@ func (sc *SecretManagerClient) generateNewSecret(resourceName string) (*security
 		ServiceAccount: sc.configOptions.ServiceAccount,
 	}
 
-	cacheLog.Debugf("constructed host name for CSR: %s", csrHostName.String())
+	// The extra SANs are included in the CSR as well, as CAs signing the CSR as is (such as the Kubernetes
+	// CA) require it, while the CA server only accepts CSRs without other SANs than the ones it grants.
+	hosts := strings.Join(append([]string{csrHostName.String()}, sc.configOptions.ExtraSANs...), ",")
+	cacheLog.Debugf("constructed host name for CSR: %s", hosts)
 	options := pkiutil.CertOptions{
-		Host:       csrHostName.String(),
+		Host:       hosts,
 		RSAKeySize: sc.configOptions.WorkloadRSAKeySize,
 		PKCS8Key:   sc.configOptions.Pkcs8Keys,
 		ECSigAlg:   pkiutil.SupportedECSignatureAlgorithms(sc.configOptions.ECCSigAlg),
+		ECCCurve:   pkiutil.SupportedEllipticCurves(sc.configOptions.ECCCurve),
 	}
 
 	// Generate the cert/key, send CSR to CA.
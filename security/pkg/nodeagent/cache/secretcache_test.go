@@ -743,3 +743,64 @@ func TestTryAddFileWatcher(t *testing.T) {
 		})
 	}
 }
+
+func TestRootCertCRL(t *testing.T) {
+	fakeCACli, err := mock.NewMockCAClient(time.Hour, false)
+	if err != nil {
+		t.Fatalf("Error creating Mock CA client: %v", err)
+	}
+	crlPath := filepath.Join(t.TempDir(), "ca-crl.pem")
+	if err := os.WriteFile(crlPath, []byte("crl-1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var notified []string
+	sc := createCache(t, fakeCACli, func(resourceName string) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, resourceName)
+	}, security.Options{WorkloadRSAKeySize: 2048, CRLFilePath: crlPath})
+
+	root, err := sc.GenerateSecret(security.RootCertReqResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(root.CRL) != "crl-1" {
+		t.Fatalf("expected CRL crl-1, got %q", root.CRL)
+	}
+	workload, err := sc.GenerateSecret(security.WorkloadKeyCertResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workload.CRL) != 0 {
+		t.Fatalf("workload certificate should not carry a CRL, got %q", workload.CRL)
+	}
+
+	// Updating the CRL triggers a push of the trust anchor.
+	if err := os.WriteFile(crlPath, []byte("crl-2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	retry.UntilSuccessOrFail(t, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, n := range notified {
+			if n == security.RootCertReqResourceName {
+				return nil
+			}
+		}
+		return fmt.Errorf("trust anchor not pushed, got %v", notified)
+	}, retry.Timeout(5*time.Second))
+	root, err = sc.GenerateSecret(security.RootCertReqResourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(root.CRL) != "crl-2" {
+		t.Fatalf("expected CRL crl-2, got %q", root.CRL)
+	}
+	// The CRL file is only watched once, whatever the number of trust anchor requests.
+	sc.certMutex.RLock()
+	defer sc.certMutex.RUnlock()
+	if len(sc.fileCerts) != 1 {
+		t.Fatalf("expected the CRL file to be watched once, got %v", sc.fileCerts)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"strings"
 
 	"go.uber.org/atomic"
 	"google.golang.org/grpc"
@@ -88,6 +89,11 @@ func (c *CitadelClient) CSRSign(csrPEM []byte, certValidTTLInSec int64) ([]strin
 			},
 		},
 	}
+	if len(c.opts.ExtraSANs) > 0 {
+		crMetaStruct.Fields[security.ExtraSANs] = &structpb.Value{
+			Kind: &structpb.Value_StringValue{StringValue: strings.Join(c.opts.ExtraSANs, ",")},
+		}
+	}
 	req := &pb.IstioCertificateRequest{
 		Csr:              string(csrPEM),
 		ValidityDuration: certValidTTLInSec,
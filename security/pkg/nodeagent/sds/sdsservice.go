@@ -235,6 +235,15 @@ func toEnvoySecret(s *security.SecretItem, caRootPath string, pkpConf *mesh.Priv
 				},
 			},
 		}
+		if len(s.CRL) > 0 {
+			// The CRL is issued by the CA signing workload certificates, so only the leaf is checked against it.
+			secret.GetValidationContext().Crl = &core.DataSource{
+				Specifier: &core.DataSource_InlineBytes{
+					InlineBytes: s.CRL,
+				},
+			}
+			secret.GetValidationContext().OnlyVerifyLeafCertCrl = true
+		}
 	} else {
 		switch pkpConf.GetProvider().(type) {
 		case *mesh.PrivateKeyProvider_Cryptomb:
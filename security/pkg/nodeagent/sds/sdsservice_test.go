@@ -344,3 +344,23 @@ func setupConnection(socket string) (*grpc.ClientConn, error) {
 
 	return conn, nil
 }
+
+func TestToEnvoySecretCRL(t *testing.T) {
+	secret := toEnvoySecret(&ca2.SecretItem{
+		ResourceName: rootResourceName,
+		RootCert:     fakeRootCert,
+		CRL:          []byte("crl"),
+	}, "", nil)
+	vc := secret.GetValidationContext()
+	if got := vc.GetCrl().GetInlineBytes(); string(got) != "crl" {
+		t.Fatalf("expected CRL in validation context, got %q", got)
+	}
+	if !vc.GetOnlyVerifyLeafCertCrl() {
+		t.Fatal("expected only the leaf certificate to be checked against the CRL")
+	}
+
+	secret = toEnvoySecret(&ca2.SecretItem{ResourceName: rootResourceName, RootCert: fakeRootCert}, "", nil)
+	if secret.GetValidationContext().GetCrl() != nil {
+		t.Fatal("expected no CRL in validation context")
+	}
+}
@@ -51,6 +51,8 @@ const (
 	PrivateKeyFile = "key.pem"
 	// RootCertFile is the ID/name for the CA root certificate file.
 	RootCertFile = "root-cert.pem"
+	// CACRLFile is the certificate revocation list of the CA, listing revoked workload certificates.
+	CACRLFile = "ca-crl.pem"
 	// TLSSecretCACertFile is the CA certificate file name as it exists in tls type k8s secret.
 	TLSSecretCACertFile = "tls.crt"
 	// TLSSecretCAPrivateKeyFile is the CA certificate key file name as it exists in tls type k8s secret.
@@ -123,6 +125,7 @@ func NewSelfSignedIstioCAOptions(ctx context.Context,
 		CAType:         selfSignedCA,
 		DefaultCertTTL: defaultCertTTL,
 		MaxCertTTL:     maxCertTTL,
+		CARSAKeySize:   caRSAKeySize,
 		RotatorConfig: &SelfSignedCARootCertRotatorConfig{
 			CheckInterval:      rootCertCheckInverval,
 			caCertTTL:          caCertTTL,
@@ -146,12 +149,12 @@ func NewSelfSignedIstioCAOptions(ctx context.Context,
 		caSecret, err := client.Secrets(namespace).Get(context.TODO(), CASecret, metav1.GetOptions{})
 		if err == nil {
 			pkiCaLog.Infof("Load signing key and cert from existing secret %s/%s", caSecret.Namespace, caSecret.Name)
-			rootCerts, err := util.AppendRootCerts(caSecret.Data[CACertFile], rootCertFile)
+			rootCerts, err := util.AppendRootCerts(caSecretRootCerts(caSecret), rootCertFile)
 			if err != nil {
 				return fmt.Errorf("failed to append root certificates (%v)", err)
 			}
-			if caOpts.KeyCertBundle, err = util.NewVerifiedKeyCertBundleFromPem(caSecret.Data[CACertFile],
-				caSecret.Data[CAPrivateKeyFile], nil, rootCerts); err != nil {
+			signingCert, signingKey, certChain := caSecretSigningCerts(caSecret)
+			if caOpts.KeyCertBundle, err = util.NewVerifiedKeyCertBundleFromPem(signingCert, signingKey, certChain, rootCerts); err != nil {
 				return fmt.Errorf("failed to create CA KeyCertBundle (%v)", err)
 			}
 			pkiCaLog.Infof("Using existing public key: %v", string(rootCerts))
@@ -0,0 +1,416 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/monitoring"
+)
+
+const (
+	// NextCACertFile is the root certificate being distributed during a root key rollover.
+	// It is trusted, but not yet used for signing.
+	NextCACertFile = "next-ca-cert.pem"
+	// NextCAPrivateKeyFile is the private key of NextCACertFile.
+	NextCAPrivateKeyFile = "next-ca-key.pem"
+	// PreviousCACertFile is the root certificate being retired during a root key rollover.
+	// It is no longer used for signing, but stays trusted until the certificates it signed expire.
+	PreviousCACertFile = "previous-ca-cert.pem"
+	// IntermediateCACertFile is the intermediate certificate signing the workload certificates, when the self-signed
+	// root only signs intermediates. It is issued by the signing root, and re-issued along with the root rollover.
+	IntermediateCACertFile = "intermediate-ca-cert.pem"
+	// IntermediateCAPrivateKeyFile is the private key of IntermediateCACertFile.
+	IntermediateCAPrivateKeyFile = "intermediate-ca-key.pem"
+
+	// rolloverTimestampAnnotation records when the current phase of a root key rollover started.
+	rolloverTimestampAnnotation = "ca.istio.io/root-rollover-phase-timestamp"
+)
+
+// RolloverPhase is the phase of a root key rollover.
+type RolloverPhase int
+
+const (
+	// RolloverIdle indicates that no rollover is in progress.
+	RolloverIdle RolloverPhase = iota
+	// RolloverDistributing indicates that the next root is being distributed to the trust bundle,
+	// while the current root keeps signing.
+	RolloverDistributing
+	// RolloverRetiring indicates that the next root has taken over signing, and the previous root
+	// stays in the trust bundle until the certificates it signed expire.
+	RolloverRetiring
+)
+
+func (p RolloverPhase) String() string {
+	switch p {
+	case RolloverDistributing:
+		return "distributing"
+	case RolloverRetiring:
+		return "retiring"
+	default:
+		return "idle"
+	}
+}
+
+// eventReason is the reason of the event recorded when entering the phase.
+func (p RolloverPhase) eventReason() string {
+	switch p {
+	case RolloverDistributing:
+		return "RootKeyRolloverStarted"
+	case RolloverRetiring:
+		return "RootKeyRolloverSwitchedSigner"
+	default:
+		return "RootKeyRolloverCompleted"
+	}
+}
+
+var (
+	phaseTag = monitoring.MustCreateLabel("phase")
+
+	rootCertRolloverPhase = monitoring.NewGauge(
+		"citadel_root_cert_rollover_phase",
+		"The phase of the self-signed root key rollover: 0 when idle, 1 while the next root is distributed, "+
+			"and 2 while the previous root is retired.",
+	)
+
+	rootCertRolloverTransitions = monitoring.NewSum(
+		"citadel_root_cert_rollover_transitions_total",
+		"The number of phase transitions of the self-signed root key rollover, labeled by the phase entered.",
+		monitoring.WithLabels(phaseTag),
+	)
+
+	intermediateCertReissues = monitoring.NewSum(
+		"citadel_intermediate_cert_reissues_total",
+		"The number of intermediate certificates issued by the self-signed root, when it expires or the root rolls over.",
+	)
+)
+
+// intermediateCertReissuedReason is the reason of the event recorded when the intermediate certificate is re-issued
+// outside of a phase transition of the rollover.
+const intermediateCertReissuedReason = "IntermediateCertReissued"
+
+// minIntermediateCertTTL is the shortest TTL an intermediate certificate is issued with. Closer to the expiry of its
+// root, the intermediate would expire before it could be used, so the current one is kept until the root is rotated.
+const minIntermediateCertTTL = time.Minute
+
+func init() {
+	monitoring.MustRegister(
+		rootCertRolloverPhase,
+		rootCertRolloverTransitions,
+		intermediateCertReissues,
+	)
+}
+
+// rolloverPhaseOf returns the rollover phase recorded in the CA secret.
+func rolloverPhaseOf(caSecret *v1.Secret) RolloverPhase {
+	switch {
+	case len(caSecret.Data[NextCACertFile]) > 0:
+		return RolloverDistributing
+	case len(caSecret.Data[PreviousCACertFile]) > 0:
+		return RolloverRetiring
+	default:
+		return RolloverIdle
+	}
+}
+
+// caSecretRootCerts returns all the roots that should be trusted for the CA secret: the signing root,
+// and the next or previous root if a rollover is in progress.
+func caSecretRootCerts(caSecret *v1.Secret) []byte {
+	roots := caSecret.Data[CACertFile]
+	for _, key := range []string{NextCACertFile, PreviousCACertFile} {
+		if extra := caSecret.Data[key]; len(extra) > 0 {
+			roots = util.AppendCertByte(roots, extra)
+		}
+	}
+	return roots
+}
+
+// caSecretSigningCerts returns the signing cert, key and cert chain of the CA secret: the intermediate if one was
+// issued, and the signing root otherwise.
+func caSecretSigningCerts(caSecret *v1.Secret) (cert, key, chain []byte) {
+	if intermediate := caSecret.Data[IntermediateCACertFile]; len(intermediate) > 0 {
+		return intermediate, caSecret.Data[IntermediateCAPrivateKeyFile], util.AppendCertByte(intermediate, caSecret.Data[CACertFile])
+	}
+	return caSecret.Data[CACertFile], caSecret.Data[CAPrivateKeyFile], nil
+}
+
+// checkAndRollOverRootCert drives a root key rollover for the self-signed CA. Rather than re-signing the
+// root with the existing key, a new key and root are generated when the current root is about to expire.
+// The rollover then proceeds in phases, each lasting at least rolloverPhaseDuration, so that every workload
+// trusts the new root before anything is signed with it, and keeps trusting the old root until the
+// certificates it signed have expired:
+//  1. distributing: the trust bundle holds the current and next roots, the current root signs.
+//  2. retiring: the next root becomes the signing root, the trust bundle holds it and the previous root.
+//  3. idle: the previous root is dropped from the trust bundle.
+//
+// With IntermediateCA, the roots only sign an intermediate, which signs the workload certificates. It is re-issued
+// when it is about to expire, and by the next root as it takes over signing, so the new key signs from then on.
+//
+// All state is kept in istio-ca-secret, so that the rollover survives restarts and every istiod converges.
+func (rotator *SelfSignedCARootCertRotator) checkAndRollOverRootCert(caSecret *v1.Secret) {
+	now := time.Now()
+	phase := rolloverPhaseOf(caSecret)
+	rootCertRolloverPhase.Record(float64(phase))
+
+	if phase == RolloverIdle {
+		waitTime, err := rotator.config.certInspector.GetWaitTime(caSecret.Data[CACertFile], now, time.Duration(0))
+		if err == nil && waitTime > 0 {
+			rootCertRotatorLog.Info("Root cert is not about to expire, skipping root key rollover.")
+			rotator.checkIntermediateCert(caSecret, now)
+			return
+		}
+		rootCertRotatorLog.Infof("Start root key rollover, root cert is about to expire: %v", err)
+		rotator.startRollover(caSecret, now)
+		return
+	}
+
+	started, err := time.Parse(time.RFC3339, caSecret.Annotations[rolloverTimestampAnnotation])
+	if err != nil {
+		// Without a valid timestamp the phase may have just started, so restart the clock rather than
+		// risk dropping a root that is still needed.
+		rootCertRotatorLog.Warnf("Invalid %s annotation on CA secret, restarting %s phase: %v",
+			rolloverTimestampAnnotation, phase, err)
+		update := caSecret.DeepCopy()
+		setRolloverTimestamp(update, now)
+		rotator.updateRollover(update, phase, "")
+		return
+	}
+	if now.Sub(started) < rotator.config.RolloverPhaseDuration {
+		rootCertRotatorLog.Infof("Root key rollover is in %s phase since %s.", phase, started.Format(time.RFC3339))
+		rotator.checkIntermediateCert(caSecret, now)
+		return
+	}
+
+	update := caSecret.DeepCopy()
+	switch phase {
+	case RolloverDistributing:
+		update.Data[PreviousCACertFile] = caSecret.Data[CACertFile]
+		update.Data[CACertFile] = caSecret.Data[NextCACertFile]
+		update.Data[CAPrivateKeyFile] = caSecret.Data[NextCAPrivateKeyFile]
+		delete(update.Data, NextCACertFile)
+		delete(update.Data, NextCAPrivateKeyFile)
+		setRolloverTimestamp(update, now)
+		// The intermediate signed by the previous root is replaced, so nothing is signed with the previous key anymore
+		reissued := rotator.issueIntermediateCert(update, now, true)
+		if rotator.updateRollover(update, RolloverRetiring,
+			"The next root certificate is now used for signing; the previous root certificate is still trusted.") && reissued {
+			intermediateCertReissues.Increment()
+		}
+	case RolloverRetiring:
+		delete(update.Data, PreviousCACertFile)
+		delete(update.Annotations, rolloverTimestampAnnotation)
+		rotator.updateRollover(update, RolloverIdle,
+			"The previous root certificate has been removed from the trust bundle; root key rollover is complete.")
+	}
+}
+
+// checkIntermediateCert re-issues the intermediate certificate of the CA secret if it is about to expire, or removes it
+// if IntermediateCA was disabled, and reloads the KeyCertBundle otherwise.
+func (rotator *SelfSignedCARootCertRotator) checkIntermediateCert(caSecret *v1.Secret, now time.Time) {
+	update := caSecret.DeepCopy()
+	if !rotator.issueIntermediateCert(update, now, false) {
+		rotator.reloadKeyCertBundle(caSecret)
+		return
+	}
+	if err := rotator.caSecretController.UpdateCASecretWithRetry(update,
+		rotator.config.retryInterval, rotator.config.retryMax); err != nil {
+		rootCertRotatorLog.Errorf("Failed to update CA secret for intermediate cert (error: %v)", err)
+		return
+	}
+	if err := rotator.reloadKeyCertBundle(update); err != nil {
+		return
+	}
+	if len(update.Data[IntermediateCACertFile]) == 0 {
+		rootCertRotatorLog.Info("Removed the intermediate cert, the root cert signs the workload certificates.")
+		return
+	}
+	intermediateCertReissues.Increment()
+	rotator.recordRolloverEvent(update, intermediateCertReissuedReason,
+		"A new intermediate certificate has been issued by the signing root certificate.")
+}
+
+// issueIntermediateCert sets a new intermediate certificate, signed by the signing root of the CA secret, if forced or
+// if the current one is missing or about to expire. It removes the intermediate if IntermediateCA is disabled. It
+// returns whether the CA secret changed.
+func (rotator *SelfSignedCARootCertRotator) issueIntermediateCert(caSecret *v1.Secret, now time.Time, force bool) bool {
+	current := caSecret.Data[IntermediateCACertFile]
+	if !rotator.config.IntermediateCA {
+		if len(current) == 0 {
+			return false
+		}
+		delete(caSecret.Data, IntermediateCACertFile)
+		delete(caSecret.Data, IntermediateCAPrivateKeyFile)
+		return true
+	}
+	if !force && len(current) > 0 {
+		waitTime, err := rotator.config.certInspector.GetWaitTime(current, now, time.Duration(0))
+		if err == nil && waitTime > 0 {
+			return false
+		}
+	}
+	signerCert, err := util.ParsePemEncodedCertificate(caSecret.Data[CACertFile])
+	if err != nil {
+		rootCertRotatorLog.Errorf("unable to parse the root cert to issue the intermediate cert: %v", err)
+		return false
+	}
+	signerKey, err := util.ParsePemEncodedKey(caSecret.Data[CAPrivateKeyFile])
+	if err != nil {
+		rootCertRotatorLog.Errorf("unable to parse the root key to issue the intermediate cert: %v", err)
+		return false
+	}
+	// The intermediate cannot outlive its root
+	ttl := rotator.config.IntermediateCertTTL
+	if remaining := signerCert.NotAfter.Sub(now); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl < minIntermediateCertTTL {
+		rootCertRotatorLog.Errorf("unable to issue the intermediate cert: the root cert expires at %v, in less than %v",
+			signerCert.NotAfter, minIntermediateCertTTL)
+		return false
+	}
+	pemCert, pemKey, err := util.GenCertKeyFromOptions(util.CertOptions{
+		TTL:        ttl,
+		Org:        rotator.config.org,
+		IsCA:       true,
+		SignerCert: signerCert,
+		SignerPriv: signerKey,
+		RSAKeySize: rotator.ca.caRSAKeySize,
+		IsDualUse:  rotator.config.dualUse,
+	})
+	if err != nil {
+		rootCertRotatorLog.Errorf("unable to generate the intermediate cert and key: %v", err)
+		return false
+	}
+	caSecret.Data[IntermediateCACertFile] = pemCert
+	caSecret.Data[IntermediateCAPrivateKeyFile] = pemKey
+	return true
+}
+
+// startRollover generates the next root with a new key and adds it to the trust bundle.
+func (rotator *SelfSignedCARootCertRotator) startRollover(caSecret *v1.Secret, now time.Time) {
+	oldCertOptions, err := util.GetCertOptionsFromExistingCert(caSecret.Data[CACertFile])
+	if err != nil {
+		rootCertRotatorLog.Warnf("Failed to generate cert options from existing root certificate (%v), "+
+			"new root certificate may not match old root certificate", err)
+	}
+	options := util.CertOptions{
+		TTL:          rotator.config.caCertTTL,
+		Org:          rotator.config.org,
+		IsCA:         true,
+		IsSelfSigned: true,
+		RSAKeySize:   rotator.ca.caRSAKeySize,
+		IsDualUse:    rotator.config.dualUse,
+	}
+	options = util.MergeCertOptions(options, oldCertOptions)
+	pemCert, pemKey, err := util.GenCertKeyFromOptions(options)
+	if err != nil {
+		rootCertRotatorLog.Errorf("unable to generate next CA cert and key for self-signed CA: %v", err)
+		return
+	}
+	update := caSecret.DeepCopy()
+	update.Data[NextCACertFile] = pemCert
+	update.Data[NextCAPrivateKeyFile] = pemKey
+	setRolloverTimestamp(update, now)
+	rotator.updateRollover(update, RolloverDistributing,
+		"A new root certificate has been generated and added to the trust bundle; the current root certificate still signs.")
+}
+
+// updateRollover writes the rollover state into istio-ca-secret and the local KeyCertBundle. When the
+// phase changed, metrics are updated and an event with the given message is recorded on the secret. It returns
+// whether the secret was updated.
+func (rotator *SelfSignedCARootCertRotator) updateRollover(caSecret *v1.Secret, phase RolloverPhase, message string) bool {
+	if err := rotator.caSecretController.UpdateCASecretWithRetry(caSecret,
+		rotator.config.retryInterval, rotator.config.retryMax); err != nil {
+		// Most likely another istiod updated the secret first; its state is picked up on the next check.
+		rootCertRotatorLog.Errorf("Failed to update CA secret for root key rollover (error: %v)", err)
+		return false
+	}
+	if err := rotator.reloadKeyCertBundle(caSecret); err != nil {
+		return true
+	}
+	if message == "" {
+		return true
+	}
+	rootCertRotatorLog.Infof("Root key rollover entered %s phase.", phase)
+	rootCertRolloverPhase.Record(float64(phase))
+	rootCertRolloverTransitions.With(phaseTag.Value(phase.String())).Increment()
+	rotator.recordRolloverEvent(caSecret, phase.eventReason(), message)
+	return true
+}
+
+// reloadKeyCertBundle loads the signing cert, key and trust bundle of the CA secret into the local
+// KeyCertBundle, if they differ from it, which happens when another istiod updated istio-ca-secret.
+func (rotator *SelfSignedCARootCertRotator) reloadKeyCertBundle(caSecret *v1.Secret) error {
+	rootCerts, err := util.AppendRootCerts(caSecretRootCerts(caSecret), rotator.config.rootCertFile)
+	if err != nil {
+		rootCertRotatorLog.Errorf("failed to append root certificates from file: %s", err.Error())
+		return err
+	}
+	signingCert, signingKey, certChain := caSecretSigningCerts(caSecret)
+	caCertInMem, _, _, rootCertsInMem := rotator.ca.GetCAKeyCertBundle().GetAllPem()
+	if bytes.Equal(caCertInMem, signingCert) && bytes.Equal(rootCertsInMem, rootCerts) {
+		return nil
+	}
+	if err := rotator.ca.GetCAKeyCertBundle().VerifyAndSetAll(signingCert, signingKey, certChain, rootCerts); err != nil {
+		rootCertRotatorLog.Errorf("failed to reload root certs into KeyCertBundle (%v)", err)
+		return err
+	}
+	rootCertRotatorLog.Info("Successfully reloaded root certs into KeyCertBundle.")
+	return nil
+}
+
+// recordRolloverEvent records a Kubernetes event on istio-ca-secret for a rollover phase transition or the re-issuance
+// of the intermediate certificate.
+func (rotator *SelfSignedCARootCertRotator) recordRolloverEvent(caSecret *v1.Secret, reason, message string) {
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", caSecret.Name, now.UnixNano()),
+			Namespace: caSecret.Namespace,
+		},
+		InvolvedObject: v1.ObjectReference{
+			APIVersion:      "v1",
+			Kind:            "Secret",
+			Namespace:       caSecret.Namespace,
+			Name:            caSecret.Name,
+			UID:             caSecret.UID,
+			ResourceVersion: caSecret.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           v1.EventTypeNormal,
+		Source:         v1.EventSource{Component: "istiod"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := rotator.config.client.Events(caSecret.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		rootCertRotatorLog.Warnf("Failed to record root key rollover event: %v", err)
+	}
+}
+
+func setRolloverTimestamp(caSecret *v1.Secret, t time.Time) {
+	if caSecret.Annotations == nil {
+		caSecret.Annotations = map[string]string{}
+	}
+	caSecret.Annotations[rolloverTimestampAnnotation] = t.UTC().Format(time.RFC3339)
+}
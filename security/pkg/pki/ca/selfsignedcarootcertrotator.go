@@ -43,6 +43,15 @@ type SelfSignedCARootCertRotatorConfig struct {
 	retryMax           time.Duration
 	dualUse            bool
 	enableJitter       bool
+	// RootKeyRollover enables rolling over the root to a new key, rather than re-signing it with the existing key.
+	RootKeyRollover bool
+	// RolloverPhaseDuration is the minimum duration of each phase of a root key rollover.
+	RolloverPhaseDuration time.Duration
+	// IntermediateCA has the root sign an intermediate, re-issued along with the root key rollover, which signs the
+	// workload certificates. It requires RootKeyRollover.
+	IntermediateCA bool
+	// IntermediateCertTTL is the TTL of the intermediate certificates.
+	IntermediateCertTTL time.Duration
 }
 
 // SelfSignedCARootCertRotator automatically checks self-signed signing root
@@ -135,6 +144,13 @@ func (rotator *SelfSignedCARootCertRotator) checkAndRotateRootCertForSigningCert
 			CASecret)
 		return
 	}
+	// Keep driving a rollover in progress even if key rollover was disabled since, so that the
+	// trust bundle does not lose a root that is still needed, and the intermediate is removed.
+	if rotator.config.RootKeyRollover || rolloverPhaseOf(caSecret) != RolloverIdle ||
+		len(caSecret.Data[IntermediateCACertFile]) > 0 {
+		rotator.checkAndRollOverRootCert(caSecret)
+		return
+	}
 	// Check root certificate expiration time in CA secret
 	waitTime, err := rotator.config.certInspector.GetWaitTime(caSecret.Data[CACertFile], time.Now(), time.Duration(0))
 	if err == nil && waitTime > 0 {
@@ -125,6 +125,203 @@ func TestRootCertRotatorForSigningCitadel(t *testing.T) {
 	verifyRootCertAndPrivateKey(t, false, certItem1, certItem2)
 }
 
+// TestRootKeyRolloverForSigningCitadel verifies that with root key rollover enabled, the
+// rotator distributes a root with a new key before signing with it, and retires the old root.
+func TestRootKeyRolloverForSigningCitadel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rotator := getRootCertRotator(getDefaultSelfSignedIstioCAOptions(client))
+	rotator.config.RootKeyRollover = true
+	rotator.config.RolloverPhaseDuration = time.Hour
+	certItem0 := loadCert(rotator)
+
+	// Root cert is not about to expire, nothing changes.
+	rotator.config.certInspector = certutil.NewCertUtil(0)
+	rotator.checkAndRotateRootCert()
+	certItem1 := loadCert(rotator)
+	verifyRootCertAndPrivateKey(t, true, certItem0, certItem1)
+	if phase := rolloverPhaseOf(certItem1.caSecret); phase != RolloverIdle {
+		t.Fatalf("expected idle phase, got %v", phase)
+	}
+
+	// Root cert is about to expire: the next root is distributed, the current root keeps signing.
+	rotator.config.certInspector = certutil.NewCertUtil(100)
+	rotator.checkAndRotateRootCert()
+	certItem2 := loadCert(rotator)
+	if !bytes.Equal(certItem2.caSecret.Data[CACertFile], certItem1.caSecret.Data[CACertFile]) {
+		t.Fatal("current root should keep signing while the next root is distributed")
+	}
+	if phase := rolloverPhaseOf(certItem2.caSecret); phase != RolloverDistributing {
+		t.Fatalf("expected distributing phase, got %v", phase)
+	}
+	nextCert := certItem2.caSecret.Data[NextCACertFile]
+	if bytes.Equal(certItem2.caSecret.Data[NextCAPrivateKeyFile], certItem1.caSecret.Data[CAPrivateKeyFile]) {
+		t.Fatal("next root should use a new private key")
+	}
+	verifyTrustBundle(t, certItem2.rootCertInKeyCertBundle, certItem1.caSecret.Data[CACertFile], nextCert)
+
+	// A restarted CA loads the roots of the rollover in progress.
+	restarted := getDefaultSelfSignedIstioCAOptions(client)
+	verifyTrustBundle(t, restarted.KeyCertBundle.GetRootCertPem(), certItem1.caSecret.Data[CACertFile], nextCert)
+
+	// The phase has not lasted long enough, nothing changes.
+	rotator.checkAndRotateRootCert()
+	certItem3 := loadCert(rotator)
+	if phase := rolloverPhaseOf(certItem3.caSecret); phase != RolloverDistributing {
+		t.Fatalf("expected distributing phase, got %v", phase)
+	}
+
+	// The next root takes over signing, the previous root stays trusted.
+	rotator.config.RolloverPhaseDuration = 0
+	rotator.checkAndRotateRootCert()
+	certItem4 := loadCert(rotator)
+	if phase := rolloverPhaseOf(certItem4.caSecret); phase != RolloverRetiring {
+		t.Fatalf("expected retiring phase, got %v", phase)
+	}
+	if !bytes.Equal(certItem4.caSecret.Data[CACertFile], nextCert) {
+		t.Fatal("next root should be used for signing")
+	}
+	if signingCert, _, _, _ := rotator.ca.GetCAKeyCertBundle().GetAllPem(); !bytes.Equal(signingCert, nextCert) {
+		t.Fatal("next root should be loaded in the KeyCertBundle for signing")
+	}
+	verifyTrustBundle(t, certItem4.rootCertInKeyCertBundle, nextCert, certItem1.caSecret.Data[CACertFile])
+
+	// The previous root is retired.
+	rotator.checkAndRotateRootCert()
+	certItem5 := loadCert(rotator)
+	if phase := rolloverPhaseOf(certItem5.caSecret); phase != RolloverIdle {
+		t.Fatalf("expected idle phase, got %v", phase)
+	}
+	if _, f := certItem5.caSecret.Annotations[rolloverTimestampAnnotation]; f {
+		t.Fatal("rollover timestamp should be removed once the rollover completes")
+	}
+	if !bytes.Equal(certItem5.rootCertInKeyCertBundle, nextCert) {
+		t.Fatalf("trust bundle should only contain the new root, got %s", certItem5.rootCertInKeyCertBundle)
+	}
+
+	events, err := client.CoreV1().Events(caNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 3 {
+		t.Fatalf("expected an event for each of the 3 phase transitions, got %d", len(events.Items))
+	}
+}
+
+// TestIntermediateCertRolloverForSigningCitadel verifies that with an intermediate CA, the intermediate signs the
+// workload certificates, and is re-issued by the next root as it takes over signing.
+func TestIntermediateCertRolloverForSigningCitadel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rotator := getRootCertRotator(getDefaultSelfSignedIstioCAOptions(client))
+	rotator.config.RootKeyRollover = true
+	rotator.config.IntermediateCA = true
+	rotator.config.IntermediateCertTTL = 24 * time.Hour
+	rotator.config.RolloverPhaseDuration = time.Hour
+
+	// The intermediate is issued by the current root, and signs.
+	rotator.config.certInspector = certutil.NewCertUtil(0)
+	rotator.checkAndRotateRootCert()
+	certItem0 := loadCert(rotator)
+	intermediate0 := certItem0.caSecret.Data[IntermediateCACertFile]
+	verifyIssuedBy(t, intermediate0, certItem0.caSecret.Data[CACertFile])
+	verifySigningCert(t, rotator, intermediate0)
+
+	// The intermediate is kept while it is not about to expire.
+	rotator.checkAndRotateRootCert()
+	if !bytes.Equal(loadCert(rotator).caSecret.Data[IntermediateCACertFile], intermediate0) {
+		t.Fatal("intermediate cert should not be re-issued before it is about to expire")
+	}
+
+	// The next root is distributed, the intermediate of the current root keeps signing.
+	rotator.config.certInspector = certutil.NewCertUtil(100)
+	rotator.checkAndRotateRootCert()
+	certItem1 := loadCert(rotator)
+	if phase := rolloverPhaseOf(certItem1.caSecret); phase != RolloverDistributing {
+		t.Fatalf("expected distributing phase, got %v", phase)
+	}
+	verifySigningCert(t, rotator, intermediate0)
+
+	// The next root takes over signing through a new intermediate.
+	rotator.config.RolloverPhaseDuration = 0
+	rotator.checkAndRotateRootCert()
+	certItem2 := loadCert(rotator)
+	intermediate1 := certItem2.caSecret.Data[IntermediateCACertFile]
+	if bytes.Equal(intermediate1, intermediate0) {
+		t.Fatal("intermediate cert should be re-issued by the next root")
+	}
+	verifyIssuedBy(t, intermediate1, certItem1.caSecret.Data[NextCACertFile])
+	verifySigningCert(t, rotator, intermediate1)
+
+	// Disabling the intermediate has the root sign again.
+	rotator.config.IntermediateCA = false
+	rotator.config.RootKeyRollover = false
+	rotator.config.RolloverPhaseDuration = time.Hour
+	rotator.config.certInspector = certutil.NewCertUtil(0)
+	rotator.checkAndRotateRootCert()
+	certItem3 := loadCert(rotator)
+	if _, f := certItem3.caSecret.Data[IntermediateCACertFile]; f {
+		t.Fatal("intermediate cert should be removed once disabled")
+	}
+	verifySigningCert(t, rotator, certItem3.caSecret.Data[CACertFile])
+}
+
+// TestIntermediateCertNearRootExpiry verifies that no intermediate certificate is issued when the root expires too
+// soon for it to be used.
+func TestIntermediateCertNearRootExpiry(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rotator := getRootCertRotator(getDefaultSelfSignedIstioCAOptions(client))
+	rotator.config.IntermediateCA = true
+	rotator.config.IntermediateCertTTL = 24 * time.Hour
+	caSecret := loadCert(rotator).caSecret.DeepCopy()
+	root, err := util.ParsePemEncodedCertificate(caSecret.Data[CACertFile])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, now := range []time.Time{root.NotAfter.Add(-time.Second), root.NotAfter.Add(time.Minute)} {
+		if rotator.issueIntermediateCert(caSecret, now, true) {
+			t.Fatalf("intermediate cert should not be issued at %v, the root expires at %v", now, root.NotAfter)
+		}
+		if _, f := caSecret.Data[IntermediateCACertFile]; f {
+			t.Fatal("intermediate cert should not be set")
+		}
+	}
+	if !rotator.issueIntermediateCert(caSecret, root.NotAfter.Add(-10*time.Minute), true) {
+		t.Fatal("intermediate cert should be issued when the root does not expire soon")
+	}
+	verifyIssuedBy(t, caSecret.Data[IntermediateCACertFile], caSecret.Data[CACertFile])
+}
+
+func verifyIssuedBy(t *testing.T, certPem, issuerPem []byte) {
+	t.Helper()
+	cert, err := util.ParsePemEncodedCertificate(certPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer, err := util.ParsePemEncodedCertificate(issuerPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert.CheckSignatureFrom(issuer); err != nil {
+		t.Fatalf("cert is not issued by the expected issuer: %v", err)
+	}
+}
+
+func verifySigningCert(t *testing.T, rotator *SelfSignedCARootCertRotator, want []byte) {
+	t.Helper()
+	if signingCert, _, _, _ := rotator.ca.GetCAKeyCertBundle().GetAllPem(); !bytes.Equal(signingCert, want) {
+		t.Fatalf("unexpected signing cert %s", signingCert)
+	}
+}
+
+func verifyTrustBundle(t *testing.T, bundle []byte, roots ...[]byte) {
+	t.Helper()
+	for _, root := range roots {
+		if !bytes.Contains(bundle, bytes.TrimSpace(root)) {
+			t.Fatalf("trust bundle %s does not contain root %s", bundle, root)
+		}
+	}
+}
+
 // TestRootCertRotatorKeepCertFieldsUnchanged verifies that rotator
 // extracts information from existing certificate and passes then into new root
 // certificate.
@@ -15,6 +15,8 @@ package ra
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/exp/slices"
@@ -92,17 +94,58 @@ func ValidateCSR(csrPEM []byte, subjectIDs []string) bool {
 	return true
 }
 
+// BackendFactory creates an RA delegating the signing of the certificates to an external CA backend.
+type BackendFactory func(opts *IstioRAOptions) (RegistrationAuthority, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[CaExternalType]BackendFactory{}
+)
+
+func init() {
+	RegisterBackend(ExtCAK8s, func(opts *IstioRAOptions) (RegistrationAuthority, error) {
+		if opts.K8sClient == nil {
+			return nil, fmt.Errorf("kube client is required")
+		}
+		return NewKubernetesRA(opts)
+	})
+}
+
+// RegisterBackend registers the factory of an external CA backend, selected by the EXTERNAL_CA setting of istiod.
+// Builds of istiod can plug in other backends, such as Vault or a cloud private CA, by registering them from an
+// init function. The Kubernetes CSR API backend is built in.
+func RegisterBackend(name CaExternalType, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = factory
+}
+
+// Backends returns the names of the registered external CA backends.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
 // NewIstioRA is a factory method that returns an RA that implements the RegistrationAuthority functionality.
 // the caOptions defines the external provider
 func NewIstioRA(opts *IstioRAOptions) (RegistrationAuthority, error) {
-	if opts.ExternalCAType == ExtCAK8s {
-		istioRA, err := NewKubernetesRA(opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create an K8s CA: %v", err)
-		}
-		return istioRA, err
+	backendsMu.RLock()
+	factory, f := backends[opts.ExternalCAType]
+	backendsMu.RUnlock()
+	if !f {
+		return nil, fmt.Errorf("invalid CA Name %s, registered backends are %v", opts.ExternalCAType, Backends())
+	}
+	istioRA, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s RA: %v", opts.ExternalCAType, err)
 	}
-	return nil, fmt.Errorf("invalid CA Name %s", opts.ExternalCAType)
+	return istioRA, nil
 }
 
 // preSign : Validation checks to execute before signing certificates
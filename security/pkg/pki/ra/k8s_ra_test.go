@@ -257,3 +257,33 @@ func TestValidateCSR(t *testing.T) {
 		t.Errorf("Test 2: CSR Validation failed")
 	}
 }
+
+func TestNewIstioRA(t *testing.T) {
+	const fakeBackend CaExternalType = "FAKE_BACKEND"
+	fake, err := createFakeK8sRA(kube.NewFakeClient(), TestCACertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterBackend(fakeBackend, func(opts *IstioRAOptions) (RegistrationAuthority, error) {
+		return fake, nil
+	})
+	t.Cleanup(func() {
+		backendsMu.Lock()
+		defer backendsMu.Unlock()
+		delete(backends, fakeBackend)
+	})
+
+	got, err := NewIstioRA(&IstioRAOptions{ExternalCAType: fakeBackend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != fake {
+		t.Fatalf("expected the RA of the registered backend")
+	}
+	if _, err := NewIstioRA(&IstioRAOptions{ExternalCAType: ExtCAK8s, CaCertFile: TestCACertFile}); err == nil {
+		t.Fatalf("expected an error creating the Kubernetes RA without kube client")
+	}
+	if _, err := NewIstioRA(&IstioRAOptions{ExternalCAType: "UNKNOWN"}); err == nil {
+		t.Fatalf("expected an error for an unknown backend")
+	}
+}
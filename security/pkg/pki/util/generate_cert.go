@@ -43,10 +43,31 @@ import (
 type SupportedECSignatureAlgorithms string
 
 const (
-	// only ECDSA using P256 is currently supported
+	// only ECDSA is currently supported
 	EcdsaSigAlg SupportedECSignatureAlgorithms = "ECDSA"
 )
 
+// SupportedEllipticCurves are the types of curves
+// to be used in EC key generation (e.g. P256 or P384)
+type SupportedEllipticCurves string
+
+const (
+	P256Curve SupportedEllipticCurves = "P256"
+	P384Curve SupportedEllipticCurves = "P384"
+)
+
+// GetEllipticCurve returns the elliptic curve of the given type, which defaults to P256.
+func GetEllipticCurve(curve SupportedEllipticCurves) (elliptic.Curve, error) {
+	switch curve {
+	case "", P256Curve:
+		return elliptic.P256(), nil
+	case P384Curve:
+		return elliptic.P384(), nil
+	default:
+		return nil, fmt.Errorf("unsupported elliptic curve %q, supported values: %s, %s", curve, P256Curve, P384Curve)
+	}
+}
+
 // CertOptions contains options for generating a new certificate.
 type CertOptions struct {
 	// Comma-separated hostnames and IPs to generate a certificate for.
@@ -98,6 +119,9 @@ type CertOptions struct {
 	// If empty, RSA is used, otherwise ECC is used.
 	ECSigAlg SupportedECSignatureAlgorithms
 
+	// The curve of the EC private keys generated with ECSigAlg. Defaults to P256.
+	ECCCurve SupportedEllipticCurves
+
 	// Subjective Alternative Name values.
 	DNSNames string
 }
@@ -114,7 +138,11 @@ func GenCertKeyFromOptions(options CertOptions) (pemCert []byte, pemKey []byte,
 
 		switch options.ECSigAlg {
 		case EcdsaSigAlg:
-			ecPriv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			curve, cerr := GetEllipticCurve(options.ECCCurve)
+			if cerr != nil {
+				return nil, nil, fmt.Errorf("cert generation fails at EC key generation (%v)", cerr)
+			}
+			ecPriv, err = ecdsa.GenerateKey(curve, rand.Reader)
 			if err != nil {
 				return nil, nil, fmt.Errorf("cert generation fails at EC key generation (%v)", err)
 			}
@@ -45,6 +45,30 @@ func TestGenCSR(t *testing.T) {
 				ECSigAlg: EcdsaSigAlg,
 			},
 		},
+		"GenCSR with RSA 4096": {
+			csrOptions: CertOptions{
+				Host:       "test_ca.com",
+				Org:        "MyOrg",
+				RSAKeySize: 4096,
+			},
+		},
+		"GenCSR with EC P384": {
+			csrOptions: CertOptions{
+				Host:     "test_ca.com",
+				Org:      "MyOrg",
+				ECSigAlg: EcdsaSigAlg,
+				ECCCurve: P384Curve,
+			},
+		},
+		"GenCSR with EC errors due to invalid curve": {
+			csrOptions: CertOptions{
+				Host:     "test_ca.com",
+				Org:      "MyOrg",
+				ECSigAlg: EcdsaSigAlg,
+				ECCCurve: "P224",
+			},
+			err: errors.New(`EC key generation failed (unsupported elliptic curve "P224", supported values: P256, P384)`),
+		},
 		"GenCSR with EC errors due to invalid signature algorithm": {
 			csrOptions: CertOptions{
 				Host:     "test_ca.com",
@@ -92,8 +116,14 @@ func TestGenCSR(t *testing.T) {
 			if reflect.TypeOf(csr.PublicKey) != reflect.TypeOf(&ecdsa.PublicKey{}) {
 				t.Errorf("%s: decoded PKCS#8 returned unexpected key type: %T", id, csr.PublicKey)
 			}
+			curve, _ := GetEllipticCurve(tc.csrOptions.ECCCurve)
+			if key, ok := csr.PublicKey.(*ecdsa.PublicKey); ok && key.Curve != curve {
+				t.Errorf("%s: unexpected curve %v", id, key.Curve.Params().Name)
+			}
 		} else if reflect.TypeOf(csr.PublicKey) != reflect.TypeOf(&rsa.PublicKey{}) {
 			t.Errorf("%s: decoded PKCS#8 returned unexpected key type: %T", id, csr.PublicKey)
+		} else if size := csr.PublicKey.(*rsa.PublicKey).N.BitLen(); size != tc.csrOptions.RSAKeySize {
+			t.Errorf("%s: unexpected RSA key size %d", id, size)
 		}
 	}
 }
@@ -15,6 +15,10 @@
 package ca
 
 import (
+	"container/heap"
+	"sync"
+	"time"
+
 	"istio.io/pkg/monitoring"
 )
 
@@ -23,7 +27,8 @@ const (
 )
 
 var (
-	errorTag = monitoring.MustCreateLabel(errorlabel)
+	errorTag       = monitoring.MustCreateLabel(errorlabel)
+	trustDomainTag = monitoring.MustCreateLabel("trust_domain")
 
 	csrCounts = monitoring.NewSum(
 		"citadel_server_csr_count",
@@ -56,6 +61,15 @@ var (
 		"The number of certificates issuances that have succeeded.",
 	)
 
+	validCertCounts = monitoring.NewGauge(
+		"citadel_server_valid_cert_count",
+		"The number of workload identities with an unexpired certificate issued by Citadel server, by trust domain. "+
+			"An identity is counted once, however many certificates it was issued. During a trust domain migration, "+
+			"it reports the progress of the workloads to the new trust domain. Each replica only counts the "+
+			"certificates it issued.",
+		monitoring.WithLabels(trustDomainTag),
+	)
+
 	rootCertExpiryTimestamp = monitoring.NewGauge(
 		"citadel_server_root_cert_expiry_timestamp",
 		"The unix timestamp, in seconds, when Citadel root cert will expire. "+
@@ -76,6 +90,7 @@ func init() {
 		idExtractionErrorCounts,
 		certSignErrorCounts,
 		successCounts,
+		validCertCounts,
 		rootCertExpiryTimestamp,
 		certChainExpiryTimestamp,
 	)
@@ -106,3 +121,87 @@ func newMonitoringMetrics() monitoringMetrics {
 func (m *monitoringMetrics) GetCertSignError(err string) monitoring.Metric {
 	return m.certSignErrors.With(errorTag.Value(err))
 }
+
+// issuedCerts tracks the expiry of the latest certificate issued to each identity, by trust domain, to report the
+// number of identities with a valid certificate. Only the certificates issued by this replica are known, so with
+// several istiod replicas an identity is counted by each replica that issued it a certificate.
+type issuedCerts struct {
+	mu sync.Mutex
+	// identities holds the expiry of the latest certificate of each identity, by trust domain.
+	identities map[string]map[string]time.Time
+	// expiries holds the recorded certificates, soonest expiry first, to drop the identities whose latest certificate
+	// expired without going through all of them. The memory is bounded by the number of valid certificates.
+	expiries expiryHeap
+}
+
+func newIssuedCerts() *issuedCerts {
+	return &issuedCerts{identities: map[string]map[string]time.Time{}}
+}
+
+// record adds a certificate issued to the identity of the trust domain, and updates the number of identities with a
+// valid certificate of each trust domain, dropping the ones whose certificates expired.
+func (c *issuedCerts) record(trustDomain, identity string, notAfter, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids, f := c.identities[trustDomain]
+	if !f {
+		ids = map[string]time.Time{}
+		c.identities[trustDomain] = ids
+	}
+	if notAfter.After(ids[identity]) {
+		ids[identity] = notAfter
+	}
+	heap.Push(&c.expiries, issuedCert{trustDomain: trustDomain, identity: identity, notAfter: notAfter})
+	updated := map[string]struct{}{trustDomain: {}}
+	for c.expiries.Len() > 0 && !c.expiries[0].notAfter.After(now) {
+		expired := heap.Pop(&c.expiries).(issuedCert)
+		ids := c.identities[expired.trustDomain]
+		// The identity is only dropped once its latest certificate expired.
+		if latest, f := ids[expired.identity]; f && !latest.After(now) {
+			delete(ids, expired.identity)
+			updated[expired.trustDomain] = struct{}{}
+		}
+	}
+	for td := range updated {
+		validCertCounts.With(trustDomainTag.Value(td)).Record(float64(len(c.identities[td])))
+		if len(c.identities[td]) == 0 {
+			delete(c.identities, td)
+		}
+	}
+}
+
+// count returns the number of identities of the trust domain with a valid certificate.
+func (c *issuedCerts) count(trustDomain string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.identities[trustDomain])
+}
+
+// issuedCert is a certificate issued to an identity.
+type issuedCert struct {
+	trustDomain string
+	identity    string
+	notAfter    time.Time
+}
+
+// expiryHeap is a min-heap of issued certificates by expiry.
+type expiryHeap []issuedCert
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].notAfter.Before(h[j].notAfter) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x any) {
+	*h = append(*h, x.(issuedCert))
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
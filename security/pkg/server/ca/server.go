@@ -15,19 +15,25 @@
 package ca
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	pb "istio.io/api/security/v1alpha1"
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/kclient"
 	"istio.io/istio/pkg/kube/namespace"
 	"istio.io/istio/pkg/security"
+	"istio.io/istio/pkg/spiffe"
 	"istio.io/istio/security/pkg/pki/ca"
 	caerror "istio.io/istio/security/pkg/pki/error"
 	"istio.io/istio/security/pkg/pki/util"
@@ -36,6 +42,24 @@ import (
 
 var serverCaLog = log.RegisterScope("serverca", "Citadel server log", 0)
 
+// WorkloadCertTTLAnnotation can be set on a namespace to bound the TTL of the certificates issued to its
+// workloads, for example "1h". Workloads may still request a shorter TTL, through the SECRET_TTL proxy
+// metadata of their ProxyConfig. The value is itself bounded by the max workload cert TTL of the mesh.
+const WorkloadCertTTLAnnotation = "security.istio.io/workload-cert-ttl"
+
+// CertSignerAnnotation can be set on a namespace to select the signer of the certificates issued to its
+// workloads when istiod acts as a Kubernetes RA, for example "clusterissuer.tenant-a". The signer name
+// is qualified with the CERT_SIGNER_DOMAIN of istiod, and overrides the CERT_SIGNER proxy metadata of
+// the workloads, so that tenants can not chain to the issuing CA of another tenant.
+const CertSignerAnnotation = "security.istio.io/cert-signer"
+
+// AllowedExtraSANsAnnotation can be set on a namespace to allow its workloads to request additional DNS or
+// URI SANs on their certificates, through the ISTIO_META_EXTRA_SANS proxy metadata of their ProxyConfig, for
+// clients that verify host names rather than SPIFFE identities. The value is a comma separated list of SANs,
+// where DNS names may have a wildcard prefix, for example "*.legacy.example.com,urn:example:app". Requests
+// for SANs that are not allowed are rejected.
+const AllowedExtraSANsAnnotation = "security.istio.io/allowed-extra-sans"
+
 // CertificateAuthority contains methods to be supported by a CA.
 type CertificateAuthority interface {
 	// Sign generates a certificate for a workload or CA, from the given CSR and cert opts.
@@ -56,6 +80,8 @@ type Server struct {
 	serverCertTTL  time.Duration
 
 	nodeAuthorizer *NodeAuthorizer
+	namespaces     kclient.Client[*v1.Namespace]
+	issuedCerts    *issuedCerts
 }
 
 type SaNode struct {
@@ -106,12 +132,18 @@ func (s *Server) CreateCertificate(ctx context.Context, request *pb.IstioCertifi
 		// Node is authorized to impersonate; overwrite the SAN to the impersonated identity.
 		sans = []string{impersonatedIdentity}
 	}
-	certSigner := crMetadata[security.CertSigner].GetStringValue()
-	serverCaLog.Debugf("cert signer from workload %s", certSigner)
+	extraSANs, err := s.workloadExtraSANs(sans, crMetadata[security.ExtraSANs].GetStringValue())
+	if err != nil {
+		s.monitoring.CSRError.Increment()
+		serverCaLog.Warnf("extra SANs rejected: %v", err)
+		return nil, status.Errorf(codes.PermissionDenied, "extra SANs rejected (%v)", err)
+	}
+	certSigner := s.workloadCertSigner(sans, crMetadata[security.CertSigner].GetStringValue())
+	serverCaLog.Debugf("cert signer for workload %s", certSigner)
 	_, _, certChainBytes, rootCertBytes := s.ca.GetCAKeyCertBundle().GetAll()
 	certOpts := ca.CertOpts{
-		SubjectIDs: sans,
-		TTL:        time.Duration(request.ValidityDuration) * time.Second,
+		SubjectIDs: append(append([]string{}, sans...), extraSANs...),
+		TTL:        s.workloadCertTTL(sans, time.Duration(request.ValidityDuration)*time.Second),
 		ForCA:      false,
 		CertSigner: certSigner,
 	}
@@ -140,11 +172,124 @@ func (s *Server) CreateCertificate(ctx context.Context, request *pb.IstioCertifi
 	response := &pb.IstioCertificateResponse{
 		CertChain: respCertChain,
 	}
+	s.recordIssuedCert(sans, respCertChain)
 	s.monitoring.Success.Increment()
 	serverCaLog.Debug("CSR successfully signed.")
 	return response, nil
 }
 
+// recordIssuedCert records the certificate issued for the given identities, by the first identity and its trust domain.
+func (s *Server) recordIssuedCert(sans []string, certChain []string) {
+	if s.issuedCerts == nil || len(sans) == 0 || len(certChain) == 0 {
+		return
+	}
+	id, err := spiffe.ParseIdentity(sans[0])
+	if err != nil {
+		return
+	}
+	cert, err := util.ParsePemEncodedCertificate([]byte(certChain[0]))
+	if err != nil {
+		return
+	}
+	s.issuedCerts.record(id.TrustDomain, id.String(), cert.NotAfter, time.Now())
+}
+
+// workloadCertTTL returns the TTL of the certificate issued for the given identities. The requested TTL
+// is bounded by the WorkloadCertTTLAnnotation of the namespace of the identity, if set.
+func (s *Server) workloadCertTTL(sans []string, requested time.Duration) time.Duration {
+	v, ns, f := s.namespaceAnnotation(sans, WorkloadCertTTLAnnotation)
+	if !f {
+		return requested
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl <= 0 {
+		serverCaLog.Warnf("ignoring invalid %s annotation %q on namespace %s", WorkloadCertTTLAnnotation, v, ns)
+		return requested
+	}
+	if s.serverCertTTL > 0 && ttl > s.serverCertTTL {
+		ttl = s.serverCertTTL
+	}
+	if requested <= 0 || requested > ttl {
+		return ttl
+	}
+	return requested
+}
+
+// workloadCertSigner returns the signer of the certificate issued for the given identities. The
+// CertSignerAnnotation of the namespace of the identity, if set, takes precedence over the requested signer.
+func (s *Server) workloadCertSigner(sans []string, requested string) string {
+	signer, ns, f := s.namespaceAnnotation(sans, CertSignerAnnotation)
+	if !f || signer == "" {
+		return requested
+	}
+	if requested != "" && requested != signer {
+		serverCaLog.Warnf("ignoring cert signer %q requested by %v, namespace %s requires signer %q", requested, sans, ns, signer)
+	}
+	return signer
+}
+
+// workloadExtraSANs returns the additional SANs requested for the certificate of the given identities, as a
+// comma separated list. Each of them must be allowed by the AllowedExtraSANsAnnotation of the namespace of
+// the identity.
+func (s *Server) workloadExtraSANs(sans []string, requested string) ([]string, error) {
+	if requested == "" {
+		return nil, nil
+	}
+	allowed, ns, _ := s.namespaceAnnotation(sans, AllowedExtraSANsAnnotation)
+	var extra []string
+	for _, san := range strings.Split(requested, ",") {
+		san = strings.TrimSpace(san)
+		if san == "" {
+			continue
+		}
+		// Extra SANs are only meant for host name verification; a SPIFFE identity would allow the
+		// workload to impersonate another one.
+		if strings.HasPrefix(san, spiffe.URIPrefix) {
+			return nil, fmt.Errorf("SPIFFE identity %q can not be requested as an extra SAN", san)
+		}
+		if !extraSANAllowed(san, allowed) {
+			return nil, fmt.Errorf("SAN %q is not allowed in namespace %s", san, ns)
+		}
+		extra = append(extra, san)
+	}
+	return extra, nil
+}
+
+// extraSANAllowed returns true if the SAN matches one of the comma separated allowed SANs.
+func extraSANAllowed(san, allowed string) bool {
+	for _, a := range strings.Split(allowed, ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if a == san {
+			return true
+		}
+		if strings.HasPrefix(a, "*.") && !strings.Contains(san, ":") && host.Name(san).SubsetOf(host.Name(a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceAnnotation returns the value of the given annotation on the namespace of the first identity,
+// along with the namespace name.
+func (s *Server) namespaceAnnotation(sans []string, annotation string) (string, string, bool) {
+	if s.namespaces == nil || len(sans) == 0 {
+		return "", "", false
+	}
+	id, err := spiffe.ParseIdentity(sans[0])
+	if err != nil {
+		return "", "", false
+	}
+	ns := s.namespaces.Get(id.Namespace, "")
+	if ns == nil {
+		return "", id.Namespace, false
+	}
+	v, f := ns.Annotations[annotation]
+	return v, id.Namespace, f
+}
+
 func recordCertsExpiry(keyCertBundle *util.KeyCertBundle) {
 	rootCertExpiry, err := keyCertBundle.ExtractRootCertExpiryTimestamp()
 	if err != nil {
@@ -186,6 +331,7 @@ func New(
 		serverCertTTL:  ttl,
 		ca:             ca,
 		monitoring:     newMonitoringMetrics(),
+		issuedCerts:    newIssuedCerts(),
 	}
 
 	if len(features.CATrustedNodeAccounts) > 0 && client != nil {
@@ -197,5 +343,8 @@ func New(
 		}
 		server.nodeAuthorizer = na
 	}
+	if client != nil {
+		server.namespaces = kclient.New[*v1.Namespace](client)
+	}
 	return server, nil
 }
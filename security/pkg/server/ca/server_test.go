@@ -20,16 +20,22 @@ import (
 	"crypto/x509/pkix"
 	"fmt"
 	"net"
+	"reflect"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	pb "istio.io/api/security/v1alpha1"
+	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/security"
+	"istio.io/istio/pkg/test"
 	mockca "istio.io/istio/security/pkg/pki/ca/mock"
 	caerror "istio.io/istio/security/pkg/pki/error"
 	"istio.io/istio/security/pkg/pki/util"
@@ -258,3 +264,171 @@ func TestCreateCertificate(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkloadCertTTL(t *testing.T) {
+	namespace := func(name, ttl string) *v1.Namespace {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if ttl != "" {
+			ns.Annotations = map[string]string{WorkloadCertTTLAnnotation: ttl}
+		}
+		return ns
+	}
+	c := kube.NewFakeClient(
+		namespace("default", ""),
+		namespace("secure", "1h"),
+		namespace("long", "720h"),
+		namespace("invalid", "forever"),
+	)
+	server, err := New(&mockca.FakeCA{}, 48*time.Hour, nil, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RunAndWait(test.NewStop(t))
+	kube.WaitForCacheSync(test.NewStop(t), server.namespaces.HasSynced)
+
+	cases := []struct {
+		name      string
+		namespace string
+		requested time.Duration
+		want      time.Duration
+	}{
+		{"no annotation", "default", 24 * time.Hour, 24 * time.Hour},
+		{"unknown namespace", "missing", 24 * time.Hour, 24 * time.Hour},
+		{"bounded by namespace", "secure", 24 * time.Hour, time.Hour},
+		{"shorter workload override", "secure", 30 * time.Minute, 30 * time.Minute},
+		{"default to namespace", "secure", 0, time.Hour},
+		{"namespace bounded by mesh max", "long", 0, 48 * time.Hour},
+		{"invalid annotation", "invalid", 24 * time.Hour, 24 * time.Hour},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			sans := []string{"spiffe://cluster.local/ns/" + tt.namespace + "/sa/default"}
+			if got := server.workloadCertTTL(sans, tt.requested); got != tt.want {
+				t.Fatalf("expected TTL %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWorkloadCertSigner(t *testing.T) {
+	namespace := func(name, signer string) *v1.Namespace {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if signer != "" {
+			ns.Annotations = map[string]string{CertSignerAnnotation: signer}
+		}
+		return ns
+	}
+	c := kube.NewFakeClient(
+		namespace("default", ""),
+		namespace("tenant-a", "tenant-a-issuer"),
+	)
+	server, err := New(&mockca.FakeCA{}, 48*time.Hour, nil, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RunAndWait(test.NewStop(t))
+	kube.WaitForCacheSync(test.NewStop(t), server.namespaces.HasSynced)
+
+	cases := []struct {
+		name      string
+		namespace string
+		requested string
+		want      string
+	}{
+		{"no annotation", "default", "", ""},
+		{"no annotation with workload signer", "default", "workload-issuer", "workload-issuer"},
+		{"unknown namespace", "missing", "workload-issuer", "workload-issuer"},
+		{"namespace signer", "tenant-a", "", "tenant-a-issuer"},
+		{"namespace signer overrides workload", "tenant-a", "tenant-b-issuer", "tenant-a-issuer"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			sans := []string{"spiffe://cluster.local/ns/" + tt.namespace + "/sa/default"}
+			if got := server.workloadCertSigner(sans, tt.requested); got != tt.want {
+				t.Fatalf("expected signer %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWorkloadExtraSANs(t *testing.T) {
+	namespace := func(name, allowed string) *v1.Namespace {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if allowed != "" {
+			ns.Annotations = map[string]string{AllowedExtraSANsAnnotation: allowed}
+		}
+		return ns
+	}
+	c := kube.NewFakeClient(
+		namespace("default", ""),
+		namespace("legacy", "*.legacy.example.com, app.example.com,urn:example:app,spiffe://other/ns/legacy/sa/default"),
+	)
+	server, err := New(&mockca.FakeCA{}, 48*time.Hour, nil, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.RunAndWait(test.NewStop(t))
+	kube.WaitForCacheSync(test.NewStop(t), server.namespaces.HasSynced)
+
+	cases := []struct {
+		name      string
+		namespace string
+		requested string
+		want      []string
+		wantErr   bool
+	}{
+		{"none requested", "default", "", nil, false},
+		{"not allowed", "default", "app.example.com", nil, true},
+		{"unknown namespace", "missing", "app.example.com", nil, true},
+		{"exact DNS name", "legacy", "app.example.com", []string{"app.example.com"}, false},
+		{"wildcard DNS name", "legacy", "a.legacy.example.com", []string{"a.legacy.example.com"}, false},
+		{"wildcard requested", "legacy", "*.legacy.example.com", []string{"*.legacy.example.com"}, false},
+		{"wildcard does not match domain", "legacy", "legacy.example.com", nil, true},
+		{"URI", "legacy", "urn:example:app", []string{"urn:example:app"}, false},
+		{"multiple", "legacy", "app.example.com, b.legacy.example.com,", []string{"app.example.com", "b.legacy.example.com"}, false},
+		{"one not allowed", "legacy", "app.example.com,other.example.com", nil, true},
+		{"SPIFFE identity", "legacy", "spiffe://other/ns/legacy/sa/default", nil, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			sans := []string{"spiffe://cluster.local/ns/" + tt.namespace + "/sa/default"}
+			got, err := server.workloadExtraSANs(sans, tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected SANs %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIssuedCerts(t *testing.T) {
+	const (
+		oldSleep = "spiffe://old.example.com/ns/default/sa/sleep"
+		sleep    = "spiffe://cluster.local/ns/default/sa/sleep"
+		httpbin  = "spiffe://cluster.local/ns/default/sa/httpbin"
+	)
+	now := time.Now()
+	c := newIssuedCerts()
+	c.record("old.example.com", oldSleep, now.Add(time.Hour), now)
+	c.record("cluster.local", sleep, now.Add(time.Hour), now)
+	c.record("cluster.local", httpbin, now.Add(time.Hour), now)
+	// A rotated certificate does not count the identity twice.
+	c.record("cluster.local", sleep, now.Add(2*time.Hour), now)
+	if got := c.count("old.example.com"); got != 1 {
+		t.Fatalf("expected 1 identity of the old trust domain, got %d", got)
+	}
+	if got := c.count("cluster.local"); got != 2 {
+		t.Fatalf("expected 2 identities of the new trust domain, got %d", got)
+	}
+
+	// An hour later, only the rotated certificate of sleep is still valid.
+	c.record("cluster.local", sleep, now.Add(3*time.Hour), now.Add(time.Hour))
+	if got := c.count("old.example.com"); got != 0 {
+		t.Fatalf("expected no identity of the old trust domain, got %d", got)
+	}
+	if got := c.count("cluster.local"); got != 1 {
+		t.Fatalf("expected 1 identity of the new trust domain, got %d", got)
+	}
+}
@@ -68,6 +68,7 @@ var versionMap = map[string]*resourceNames{
 			"debug/resourcesz",
 			"debug/syncz",
 			"debug/telemetryz",
+			"debug/ztunnelz",
 			"metrics",
 		},
 		proxyDebugURLs: []string{